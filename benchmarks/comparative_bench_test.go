@@ -78,9 +78,10 @@ func BenchmarkDataSetCopyVsNew(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				// Deep copy by walking and creating new elements
 				newDS := dicom.NewDataSet()
-				_ = template.Walk(func(elem *element.Element) error {
+				_ = template.Walk(func(_ []tag.Tag, elem *element.Element) error {
 					_ = newDS.Add(elem)
-					return nil
+					// Sequence items are copied with their sequence, not at top level
+					return dicom.SkipSequence
 				})
 			}
 		})
@@ -165,7 +166,7 @@ func BenchmarkLookupStrategies(b *testing.B) {
 			b.ReportAllocs()
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				_ = ds.Walk(func(elem *element.Element) error {
+				_ = ds.Walk(func(_ []tag.Tag, elem *element.Element) error {
 					if elem.Tag() == tag.PatientName {
 						return nil // Found it
 					}
@@ -284,8 +285,12 @@ func BenchmarkMergeStrategies(b *testing.B) {
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
 				dsCopy := ds1.Copy()
-				_ = ds2.Walk(func(elem *element.Element) error {
-					return dsCopy.Add(elem)
+				_ = ds2.Walk(func(_ []tag.Tag, elem *element.Element) error {
+					if err := dsCopy.Add(elem); err != nil {
+						return err
+					}
+					// Sequence items are merged with their sequence, not at top level
+					return dicom.SkipSequence
 				})
 			}
 		})
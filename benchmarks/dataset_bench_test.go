@@ -80,7 +80,7 @@ func BenchmarkDataSetWalk(b *testing.B) {
 			b.ReportAllocs()
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				_ = ds.Walk(func(elem *element.Element) error {
+				_ = ds.Walk(func(_ []tag.Tag, elem *element.Element) error {
 					return nil
 				})
 			}
@@ -153,7 +153,7 @@ func BenchmarkMemoryWalkOperations(b *testing.B) {
 			b.ReportAllocs()
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				_ = ds.Walk(func(elem *element.Element) error {
+				_ = ds.Walk(func(_ []tag.Tag, elem *element.Element) error {
 					// Simulate some work
 					_ = elem.Tag()
 					_ = elem.VR()
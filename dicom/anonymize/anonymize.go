@@ -99,6 +99,16 @@ type Options struct {
 
 	// RemoveCurves removes curve data (50xx groups).
 	RemoveCurves bool
+
+	// KeepTags lists attributes to retain unchanged whatever the profile or action
+	// table says, for the common "basic profile, except keep these" case. A kept
+	// attribute also survives RemovePrivateTags, RemoveOverlays, RemoveCurves and UID
+	// regeneration.
+	KeepTags []tag.Tag
+
+	// RemoveTags lists attributes to remove (X) whatever the profile or action table
+	// says. RemoveTags takes precedence over KeepTags when a tag is in both.
+	RemoveTags []tag.Tag
 }
 
 // Config contains the complete configuration for an Anonymizer.
@@ -118,13 +128,38 @@ type Config struct {
 	// InstitutionName is the replacement value for institution name.
 	InstitutionName string
 
+	// DescriptorIdentifiers lists additional identifiers (former names, external
+	// record numbers, ...) to remove from descriptor text when CleanDescriptors is
+	// set. The patient's names, IDs, accession number and dates are collected from
+	// each dataset automatically (see IdentifiersFromDataSet).
+	DescriptorIdentifiers []string
+
 	// CustomActions allows overriding actions for specific tags.
 	CustomActions map[tag.Tag]Action
 
 	// Callbacks provides custom functions for specific tags when using ActionCallback.
 	Callbacks map[tag.Tag]func(*element.Element) (*element.Element, error)
+
+	// Cleaners provides tag-specific replacements for the built-in cleaning of
+	// ActionClean (C) attributes. Tags without a cleaner use the default cleaning.
+	Cleaners map[tag.Tag]Cleaner
 }
 
+// Cleaner cleans a single attribute for the ActionClean (C) action, returning an
+// element whose value replaces the original. Returning a nil element removes the
+// attribute.
+//
+// Example:
+//
+//	func cleanStationName(elem *element.Element) (*element.Element, error) {
+//	    val, err := value.NewStringValue(vr.ShortString, []string{"STATION"})
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return element.NewElement(elem.Tag(), elem.VR(), val)
+//	}
+type Cleaner func(elem *element.Element) (*element.Element, error)
+
 // Anonymizer performs DICOM dataset de-identification.
 type Anonymizer struct {
 	config  Config
@@ -176,6 +211,59 @@ func NewAnonymizerWithConfig(config Config) *Anonymizer {
 	return a
 }
 
+// NewAnonymizerFromTable creates an anonymizer that applies a caller-supplied
+// tag-to-action table instead of a built-in profile.
+//
+// Every tag in table is handled by its action, including ActionClean, which uses the
+// cleaner registered for the tag with RegisterCleaner (or the default cleaning).
+// Tags not in table are kept, or removed if they are private and
+// opts.RemovePrivateTags is set. The remaining options apply as they do for the
+// built-in profiles: the Retain options (RetainUIDs, RetainPatientCharacteristics,
+// ...) and CleanDescriptors override the table's actions for the attributes they
+// cover, and RemoveOverlays and RemoveCurves remove their groups. The table is
+// copied, so later changes to it do not affect the anonymizer.
+//
+// Start from BasicProfileTable to tweak the standard profile.
+//
+// Example:
+//
+//	table := anonymize.BasicProfileTable()
+//	table[tag.InstitutionName] = anonymize.ActionKeep   // X -> K
+//	table[tag.StationName] = anonymize.ActionClean      // K -> C
+//	anonymizer := anonymize.NewAnonymizerFromTable(table, anonymize.Options{RemovePrivateTags: true})
+//	anonymizer.RegisterCleaner(tag.StationName, cleanStationName)
+func NewAnonymizerFromTable(table map[tag.Tag]Action, opts Options) *Anonymizer {
+	actions := make(map[tag.Tag]Action, len(table))
+	for t, action := range table {
+		actions[t] = action
+	}
+
+	a := &Anonymizer{
+		config: Config{
+			Profile:     ProfileCustom,
+			Options:     opts,
+			PatientName: "ANONYMOUS",
+			PatientID:   fmt.Sprintf("ANON%d", time.Now().Unix()),
+		},
+		actions: actions,
+	}
+	a.applyRetainOptions()
+	if opts.CleanDescriptors {
+		a.initializeCleanDescriptorsProfile()
+	}
+	return a
+}
+
+// RegisterCleaner sets the cleaner used for t when its action is ActionClean,
+// replacing any cleaner registered earlier. Register cleaners before calling
+// Anonymize; RegisterCleaner is not safe for concurrent use with Anonymize.
+func (a *Anonymizer) RegisterCleaner(t tag.Tag, cleaner Cleaner) {
+	if a.config.Cleaners == nil {
+		a.config.Cleaners = make(map[tag.Tag]Cleaner)
+	}
+	a.config.Cleaners[t] = cleaner
+}
+
 // Anonymize performs de-identification on a DICOM dataset.
 //
 // Returns a new anonymized dataset. The original dataset is not modified.
@@ -193,8 +281,19 @@ func (a *Anonymizer) Anonymize(ds *dicom.DataSet) (*dicom.DataSet, error) {
 		return nil, fmt.Errorf("failed to copy dataset: %w", err)
 	}
 
+	// Identifiers must be gathered before the profile replaces them
+	var scrubber *DescriptorScrubber
+	if a.config.Options.CleanDescriptors {
+		identifiers := append(IdentifiersFromDataSet(ds), a.config.DescriptorIdentifiers...)
+		scrubber = NewDescriptorScrubber(identifiers)
+	}
+
 	// Apply profile actions to each element
 	err = newDS.WalkModify(func(elem *element.Element) (bool, error) {
+		if action, ok := a.overrideAction(elem.Tag()); ok {
+			return a.applyAction(elem, action, scrubber)
+		}
+
 		action, ok := a.actions[elem.Tag()]
 		if !ok {
 			// Default action for unspecified tags
@@ -205,7 +304,7 @@ func (a *Anonymizer) Anonymize(ds *dicom.DataSet) (*dicom.DataSet, error) {
 			return false, nil
 		}
 
-		return a.applyAction(elem, action)
+		return a.applyAction(elem, action, scrubber)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to apply anonymization: %w", err)
@@ -232,11 +331,62 @@ func (a *Anonymizer) Anonymize(ds *dicom.DataSet) (*dicom.DataSet, error) {
 		}
 	}
 
+	// KeepTags and RemoveTags override everything above, so they are applied last
+	if err := a.applyOverrides(ds, newDS); err != nil {
+		return nil, fmt.Errorf("failed to apply keep/remove overrides: %w", err)
+	}
+
 	return newDS, nil
 }
 
-// applyAction applies the specified action to an element.
-func (a *Anonymizer) applyAction(elem *element.Element, action Action) (bool, error) {
+// overrideAction returns the action forced on t by Options.RemoveTags or
+// Options.KeepTags. ok is false if neither list contains t.
+func (a *Anonymizer) overrideAction(t tag.Tag) (Action, bool) {
+	for _, r := range a.config.Options.RemoveTags {
+		if r.Equals(t) {
+			return ActionRemove, true
+		}
+	}
+	for _, k := range a.config.Options.KeepTags {
+		if k.Equals(t) {
+			return ActionKeep, true
+		}
+	}
+	return ActionKeep, false
+}
+
+// applyOverrides restores the original top-level elements listed in KeepTags and
+// removes those listed in RemoveTags, undoing any changes made after the element
+// walk (overlay and curve removal, UID regeneration).
+func (a *Anonymizer) applyOverrides(original, newDS *dicom.DataSet) error {
+	for _, t := range a.config.Options.KeepTags {
+		if action, _ := a.overrideAction(t); action != ActionKeep {
+			continue
+		}
+		elem, err := original.Get(t)
+		if err != nil {
+			continue
+		}
+		kept, err := element.NewElement(elem.Tag(), elem.VR(), elem.Value())
+		if err != nil {
+			return err
+		}
+		if err := newDS.Set(kept); err != nil {
+			return err
+		}
+	}
+	for _, t := range a.config.Options.RemoveTags {
+		if newDS.Contains(t) {
+			if err := newDS.Remove(t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyAction applies the specified action to an element. scrubber may be nil.
+func (a *Anonymizer) applyAction(elem *element.Element, action Action, scrubber *DescriptorScrubber) (bool, error) {
 	switch action {
 	case ActionKeep:
 		return false, nil
@@ -251,7 +401,10 @@ func (a *Anonymizer) applyAction(elem *element.Element, action Action) (bool, er
 		return a.replaceWithDummy(elem)
 
 	case ActionClean:
-		return a.cleanElement(elem)
+		if cleaner, ok := a.config.Cleaners[elem.Tag()]; ok {
+			return replaceElement(elem, cleaner)
+		}
+		return a.cleanElement(elem, scrubber)
 
 	case ActionUID:
 		return a.replaceUID(elem)
@@ -264,20 +417,26 @@ func (a *Anonymizer) applyAction(elem *element.Element, action Action) (bool, er
 		if !ok {
 			return false, fmt.Errorf("no callback defined for tag %s", elem.Tag())
 		}
-		newElem, err := callback(elem)
-		if err != nil {
-			return false, err
-		}
-		if newElem == nil {
-			return false, dicom.ErrRemoveElement
-		}
-		return true, elem.SetValue(newElem.Value())
+		return replaceElement(elem, callback)
 
 	default:
 		return false, nil
 	}
 }
 
+// replaceElement replaces the value of elem with the one returned by fn, removing
+// the element if fn returns nil.
+func replaceElement(elem *element.Element, fn func(*element.Element) (*element.Element, error)) (bool, error) {
+	newElem, err := fn(elem)
+	if err != nil {
+		return false, err
+	}
+	if newElem == nil {
+		return false, dicom.ErrRemoveElement
+	}
+	return true, elem.SetValue(newElem.Value())
+}
+
 // replaceWithEmpty replaces the element value with an empty value.
 func (a *Anonymizer) replaceWithEmpty(elem *element.Element) (bool, error) {
 	var val value.Value
@@ -348,16 +507,23 @@ func (a *Anonymizer) replaceWithDummy(elem *element.Element) (bool, error) {
 }
 
 // cleanElement cleans identifying information while preserving clinical meaning.
-func (a *Anonymizer) cleanElement(elem *element.Element) (bool, error) {
-	// Implementation depends on element type
-	// For text fields, remove identifying patterns
-	// For structured fields, preserve structure but remove identifiers
-
+//
+// Free-text values are passed through cleanText and, when the Clean Descriptors
+// Option is active, through scrubber to remove the patient's identifiers. Other
+// VRs are replaced with dummy values.
+func (a *Anonymizer) cleanElement(elem *element.Element, scrubber *DescriptorScrubber) (bool, error) {
 	switch elem.VR() {
-	case vr.LongText, vr.ShortText, vr.UnlimitedText:
-		// Clean text by removing common identifying patterns
-		cleaned := cleanText(elem.Value().String())
-		val, err := value.NewStringValue(elem.VR(), []string{cleaned})
+	case vr.LongString, vr.LongText, vr.ShortText, vr.UnlimitedText, vr.ShortString, vr.PersonName:
+		strVal, ok := elem.Value().(*value.StringValue)
+		if !ok {
+			return a.replaceWithDummy(elem)
+		}
+
+		cleaned := make([]string, len(strVal.Strings()))
+		for i, s := range strVal.Strings() {
+			cleaned[i] = scrubber.Scrub(cleanText(s))
+		}
+		val, err := value.NewStringValue(elem.VR(), cleaned)
 		if err != nil {
 			return false, fmt.Errorf("failed to create cleaned value: %w", err)
 		}
@@ -400,13 +566,17 @@ func (a *Anonymizer) copyDataSet(ds *dicom.DataSet) (*dicom.DataSet, error) {
 	newDS := dicom.NewDataSet()
 
 	// Copy all elements
-	err := ds.Walk(func(elem *element.Element) error {
+	err := ds.Walk(func(_ []tag.Tag, elem *element.Element) error {
 		// Create a copy of the element
 		newElem, err := element.NewElement(elem.Tag(), elem.VR(), elem.Value())
 		if err != nil {
 			return err
 		}
-		return newDS.Add(newElem)
+		if err := newDS.Add(newElem); err != nil {
+			return err
+		}
+		// Sequence items are carried over with their sequence
+		return dicom.SkipSequence
 	})
 
 	return newDS, err
@@ -494,8 +664,14 @@ func (a *Anonymizer) initializeActions() {
 	case ProfileCustom:
 		// For custom profiles, only use explicitly provided CustomActions
 		// No automatic initialization
+		return
 
 	default:
 		a.initializeBasicProfile()
 	}
+
+	// The Clean Descriptors Option can be combined with any standard profile
+	if a.config.Options.CleanDescriptors && a.config.Profile != ProfileClean {
+		a.initializeCleanDescriptorsProfile()
+	}
 }
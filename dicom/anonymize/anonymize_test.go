@@ -447,6 +447,190 @@ func TestHashString(t *testing.T) {
 	assert.NotEqual(t, hash1, hash3)
 }
 
+// TestBasicProfileTable tests that the exported table matches the Basic profile
+func TestBasicProfileTable(t *testing.T) {
+	table := BasicProfileTable()
+	assert.Equal(t, ActionDummy, table[tag.PatientName])
+	assert.Equal(t, ActionUID, table[tag.StudyInstanceUID])
+	assert.Equal(t, ActionRemove, table[tag.InstitutionName])
+	assert.Equal(t, ActionClean, table[tag.StudyDescription])
+
+	// Each call returns an independent copy
+	table[tag.PatientName] = ActionKeep
+	assert.Equal(t, ActionDummy, BasicProfileTable()[tag.PatientName])
+}
+
+// TestNewAnonymizerFromTable tests anonymizing with a tweaked Basic profile table
+func TestNewAnonymizerFromTable(t *testing.T) {
+	ds := setupTestDataSet(t)
+
+	table := BasicProfileTable()
+	table[tag.InstitutionName] = ActionKeep // X -> K
+	table[tag.PatientBirthDate] = ActionRemove
+
+	result, err := NewAnonymizerFromTable(table, Options{}).Anonymize(ds)
+	require.NoError(t, err)
+
+	institution, err := result.Get(tag.InstitutionName)
+	require.NoError(t, err)
+	assert.Equal(t, "General Hospital", institution.Value().String())
+
+	name, err := result.Get(tag.PatientName)
+	require.NoError(t, err)
+	assert.Equal(t, "ANONYMOUS", name.Value().String())
+
+	assert.False(t, result.Contains(tag.PatientBirthDate))
+
+	// Changing the table afterwards does not affect the anonymizer
+	anonymizer := NewAnonymizerFromTable(table, Options{})
+	table[tag.PatientID] = ActionKeep
+	result, err = anonymizer.Anonymize(ds)
+	require.NoError(t, err)
+	id, err := result.Get(tag.PatientID)
+	require.NoError(t, err)
+	assert.NotEqual(t, "PAT123456789", id.Value().String())
+}
+
+// TestNewAnonymizerFromTableRetainUIDs tests that RetainUIDs overrides the table's
+// ActionUID entries
+func TestNewAnonymizerFromTableRetainUIDs(t *testing.T) {
+	ds := setupTestDataSet(t)
+
+	result, err := NewAnonymizerFromTable(BasicProfileTable(), Options{RetainUIDs: true}).Anonymize(ds)
+	require.NoError(t, err)
+
+	for _, uidTag := range []tag.Tag{tag.StudyInstanceUID, tag.SeriesInstanceUID, tag.SOPInstanceUID} {
+		orig, err := ds.Get(uidTag)
+		require.NoError(t, err)
+		anon, err := result.Get(uidTag)
+		require.NoError(t, err)
+		assert.Equal(t, orig.Value().String(), anon.Value().String(), "%s retained", uidTag)
+	}
+
+	// Without the option the table's ActionUID applies
+	result, err = NewAnonymizerFromTable(BasicProfileTable(), Options{}).Anonymize(ds)
+	require.NoError(t, err)
+	studyUID, err := result.Get(tag.StudyInstanceUID)
+	require.NoError(t, err)
+	assert.NotEqual(t, "1.2.840.113619.2.55.3.604688119.123.1234567890.123", studyUID.Value().String())
+}
+
+// TestNewAnonymizerFromTableRetainPatientCharacteristics tests that
+// RetainPatientCharacteristics overrides the table's actions for age and sex
+func TestNewAnonymizerFromTableRetainPatientCharacteristics(t *testing.T) {
+	ds := setupTestDataSet(t)
+
+	result, err := NewAnonymizerFromTable(BasicProfileTable(), Options{RetainPatientCharacteristics: true}).Anonymize(ds)
+	require.NoError(t, err)
+
+	sex, err := result.Get(tag.PatientSex)
+	require.NoError(t, err)
+	assert.Equal(t, "M", sex.Value().String())
+	age, err := result.Get(tag.PatientAge)
+	require.NoError(t, err)
+	assert.Equal(t, "048Y", age.Value().String())
+
+	// Without the option the table's ActionEmpty applies
+	result, err = NewAnonymizerFromTable(BasicProfileTable(), Options{}).Anonymize(ds)
+	require.NoError(t, err)
+	sex, err = result.Get(tag.PatientSex)
+	require.NoError(t, err)
+	assert.Equal(t, "", sex.Value().String())
+}
+
+// TestRegisterCleaner tests that ActionClean uses a registered per-tag cleaner
+func TestRegisterCleaner(t *testing.T) {
+	ds := setupTestDataSet(t)
+	desc, err := value.NewStringValue(vr.LongString, []string{"CT for Smith"})
+	require.NoError(t, err)
+	descElem, err := element.NewElement(tag.StudyDescription, vr.LongString, desc)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(descElem))
+
+	table := map[tag.Tag]Action{
+		tag.InstitutionName:  ActionClean,
+		tag.PatientID:        ActionClean,
+		tag.StudyDescription: ActionClean,
+	}
+	anonymizer := NewAnonymizerFromTable(table, Options{})
+	anonymizer.RegisterCleaner(tag.InstitutionName, func(elem *element.Element) (*element.Element, error) {
+		val, err := value.NewStringValue(vr.LongString, []string{"HOSPITAL"})
+		if err != nil {
+			return nil, err
+		}
+		return element.NewElement(elem.Tag(), elem.VR(), val)
+	})
+	anonymizer.RegisterCleaner(tag.PatientID, func(*element.Element) (*element.Element, error) {
+		return nil, nil
+	})
+
+	result, err := anonymizer.Anonymize(ds)
+	require.NoError(t, err)
+
+	institution, err := result.Get(tag.InstitutionName)
+	require.NoError(t, err)
+	assert.Equal(t, "HOSPITAL", institution.Value().String())
+
+	// A nil element from the cleaner removes the attribute
+	assert.False(t, result.Contains(tag.PatientID))
+
+	// Tags without a cleaner use the default cleaning
+	description, err := result.Get(tag.StudyDescription)
+	require.NoError(t, err)
+	assert.Equal(t, "CT for Smith", description.Value().String())
+}
+
+// TestKeepAndRemoveTags tests that Options.KeepTags and Options.RemoveTags override
+// the profile
+func TestKeepAndRemoveTags(t *testing.T) {
+	ds := setupTestDataSet(t)
+	desc, err := value.NewStringValue(vr.LongString, []string{"CT for Smith"})
+	require.NoError(t, err)
+	descElem, err := element.NewElement(tag.StudyDescription, vr.LongString, desc)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(descElem))
+	modality, err := value.NewStringValue(vr.CodeString, []string{"CT"})
+	require.NoError(t, err)
+	modalityElem, err := element.NewElement(tag.Modality, vr.CodeString, modality)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(modalityElem))
+	originalStudyUID, err := ds.Get(tag.StudyInstanceUID)
+	require.NoError(t, err)
+
+	anonymizer := NewAnonymizerWithConfig(Config{
+		Profile:     ProfileBasic,
+		PatientName: "ANONYMOUS",
+		Options: Options{
+			CleanDescriptors: true,
+			KeepTags:         []tag.Tag{tag.StudyDescription, tag.StudyInstanceUID, tag.InstitutionName, tag.Modality},
+			RemoveTags:       []tag.Tag{tag.Modality},
+		},
+	})
+	result, err := anonymizer.Anonymize(ds)
+	require.NoError(t, err)
+
+	// Kept attributes survive cleaning, removal and UID regeneration
+	kept, err := result.Get(tag.StudyDescription)
+	require.NoError(t, err)
+	assert.Equal(t, "CT for Smith", kept.Value().String())
+
+	institution, err := result.Get(tag.InstitutionName)
+	require.NoError(t, err)
+	assert.Equal(t, "General Hospital", institution.Value().String())
+
+	studyUID, err := result.Get(tag.StudyInstanceUID)
+	require.NoError(t, err)
+	assert.Equal(t, originalStudyUID.Value().String(), studyUID.Value().String())
+
+	// RemoveTags wins over KeepTags and the profile's K
+	assert.False(t, result.Contains(tag.Modality))
+
+	// Attributes not listed follow the profile
+	name, err := result.Get(tag.PatientName)
+	require.NoError(t, err)
+	assert.Equal(t, "ANONYMOUS", name.Value().String())
+}
+
 // Helper functions
 
 func setupTestDataSet(t *testing.T) *dicom.DataSet {
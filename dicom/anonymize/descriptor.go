@@ -0,0 +1,231 @@
+package anonymize
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// DescriptorScrubber removes known patient identifiers from free-text descriptors.
+//
+// It implements the text scrubbing behind the Clean Descriptors Option: occurrences of
+// the patient's name parts, IDs, accession number and dates are removed from
+// attributes such as Study Description or Image Comments while the rest of the text
+// (and its clinical meaning) is kept.
+//
+// Matching is case-insensitive and only whole words match: an identifier must not be
+// preceded or followed by a letter or digit, so the surname "Ann" is removed from
+// "CT for ann smith" but not from "annual follow-up". Longer identifiers are tried
+// first, so "Smith-Jones" wins over "Smith".
+//
+// A DescriptorScrubber is safe for concurrent use.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part15.html#sect_E.3.5
+type DescriptorScrubber struct {
+	identifiers []string
+}
+
+// NewDescriptorScrubber creates a scrubber for the given identifiers.
+//
+// Empty and whitespace-only identifiers are ignored. Identifiers that look like DICOM
+// dates (YYYYMMDD) also match the common written forms YYYY-MM-DD, YYYY/MM/DD,
+// DD/MM/YYYY, MM/DD/YYYY and DD.MM.YYYY.
+//
+// Example:
+//
+//	scrubber := anonymize.NewDescriptorScrubber([]string{"Smith", "John", "MRN12345", "19800115"})
+//	scrubber.Scrub("CT head for John Smith (MRN12345), DOB 1980-01-15")
+//	// "CT head for (), DOB"
+func NewDescriptorScrubber(identifiers []string) *DescriptorScrubber {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		id = strings.TrimSpace(id)
+		key := strings.ToLower(id)
+		if id == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		ids = append(ids, id)
+	}
+
+	for _, id := range identifiers {
+		add(id)
+		for _, variant := range dateVariants(strings.TrimSpace(id)) {
+			add(variant)
+		}
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool { return len(ids[i]) > len(ids[j]) })
+
+	return &DescriptorScrubber{identifiers: ids}
+}
+
+// Scrub returns text with every whole-word occurrence of an identifier removed.
+//
+// Whitespace left behind by a removal is collapsed so that "for John Smith today"
+// becomes "for today", and the result is trimmed.
+func (s *DescriptorScrubber) Scrub(text string) string {
+	if s == nil || len(s.identifiers) == 0 || text == "" {
+		return text
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	removed := false
+
+	for i := 0; i < len(text); {
+		if n := s.matchAt(text, i); n > 0 {
+			i += n
+			removed = true
+			// Drop the space that separated the identifier from the next word
+			// when the output already ends in whitespace
+			if i < len(text) && text[i] == ' ' && (b.Len() == 0 || strings.HasSuffix(b.String(), " ")) {
+				i++
+			}
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(text[i:])
+		b.WriteRune(r)
+		i += size
+	}
+
+	if !removed {
+		return text
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// matchAt returns the byte length of the longest identifier matching text at offset i
+// on word boundaries, or 0 if none matches.
+func (s *DescriptorScrubber) matchAt(text string, i int) int {
+	if i > 0 {
+		prev, _ := utf8.DecodeLastRuneInString(text[:i])
+		if isWordRune(prev) {
+			return 0
+		}
+	}
+
+	for _, id := range s.identifiers {
+		end := i + len(id)
+		if end > len(text) || !strings.EqualFold(text[i:end], id) {
+			continue
+		}
+		if end < len(text) {
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			if isWordRune(next) {
+				continue
+			}
+		}
+		return len(id)
+	}
+
+	return 0
+}
+
+// isWordRune reports whether r is part of a word for boundary matching.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// dateVariants returns common written forms of a DICOM date (YYYYMMDD), or nil if id
+// is not eight digits.
+func dateVariants(id string) []string {
+	if len(id) != 8 {
+		return nil
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return nil
+		}
+	}
+
+	yyyy, mm, dd := id[0:4], id[4:6], id[6:8]
+	return []string{
+		yyyy + "-" + mm + "-" + dd,
+		yyyy + "/" + mm + "/" + dd,
+		dd + "/" + mm + "/" + yyyy,
+		mm + "/" + dd + "/" + yyyy,
+		dd + "." + mm + "." + yyyy,
+	}
+}
+
+// descriptorIdentifierTags are the attributes whose values are collected as
+// identifiers by IdentifiersFromDataSet.
+var descriptorIdentifierTags = []tag.Tag{
+	tag.PatientName,
+	tag.PatientID,
+	tag.OtherPatientIDs,
+	tag.OtherPatientNames,
+	tag.PatientBirthName,
+	tag.PatientMotherBirthName,
+	tag.AccessionNumber,
+	tag.PatientBirthDate,
+	tag.StudyDate,
+	tag.StudyID,
+}
+
+// IdentifiersFromDataSet collects the patient identifiers in ds for a DescriptorScrubber:
+// the patient's names (split into components), IDs, accession number, birth and
+// study dates, and Study ID.
+//
+// Name components shorter than two characters (initials) are skipped, since removing
+// every standalone "A" or "J" from descriptors would damage clinical text.
+//
+// Example:
+//
+//	scrubber := anonymize.NewDescriptorScrubber(anonymize.IdentifiersFromDataSet(ds))
+func IdentifiersFromDataSet(ds *dicom.DataSet) []string {
+	if ds == nil {
+		return nil
+	}
+
+	var ids []string
+	for _, t := range descriptorIdentifierTags {
+		elem, err := ds.Get(t)
+		if err != nil {
+			continue
+		}
+		strVal, ok := elem.Value().(*value.StringValue)
+		if !ok {
+			continue
+		}
+
+		for _, v := range strVal.Strings() {
+			if t.Equals(tag.PatientName) || t.Equals(tag.OtherPatientNames) ||
+				t.Equals(tag.PatientBirthName) || t.Equals(tag.PatientMotherBirthName) {
+				ids = append(ids, nameParts(v)...)
+				continue
+			}
+			if v = strings.TrimSpace(v); v != "" {
+				ids = append(ids, v)
+			}
+		}
+	}
+
+	return ids
+}
+
+// nameParts splits a Person Name (PN) value into its name components, skipping
+// initials and empty components.
+func nameParts(pn string) []string {
+	fields := strings.FieldsFunc(pn, func(r rune) bool {
+		return r == '^' || r == '=' || r == ' ' || r == ','
+	})
+
+	var parts []string
+	for _, f := range fields {
+		f = strings.TrimRight(f, ".")
+		if utf8.RuneCountInString(f) >= 2 {
+			parts = append(parts, f)
+		}
+	}
+	return parts
+}
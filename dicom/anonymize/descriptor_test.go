@@ -0,0 +1,98 @@
+package anonymize
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDescriptorScrubber tests identifier removal from free text
+func TestDescriptorScrubber(t *testing.T) {
+	scrubber := NewDescriptorScrubber([]string{"Smith", "Ann", "Smith-Jones", "MRN12345", "19800115", " "})
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"name removed", "CT head for Ann Smith today", "CT head for today"},
+		{"case insensitive", "ANN SMITH follow-up", "follow-up"},
+		{"word boundaries", "annual review, Smithfield clinic", "annual review, Smithfield clinic"},
+		{"longest match first", "Patient Smith-Jones", "Patient"},
+		{"punctuation kept", "Prior (MRN12345) imaging", "Prior () imaging"},
+		{"DICOM date", "DOB 19800115", "DOB"},
+		{"ISO date", "DOB 1980-01-15.", "DOB ."},
+		{"day first date", "born 15/01/1980 in", "born in"},
+		{"no identifiers", "MR BRAIN W/O CONTRAST", "MR BRAIN W/O CONTRAST"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, scrubber.Scrub(tt.input))
+		})
+	}
+}
+
+// TestDescriptorScrubber_Nil tests that a nil or empty scrubber leaves text unchanged
+func TestDescriptorScrubber_Nil(t *testing.T) {
+	var scrubber *DescriptorScrubber
+	assert.Equal(t, "Smith", scrubber.Scrub("Smith"))
+	assert.Equal(t, "Smith", NewDescriptorScrubber(nil).Scrub("Smith"))
+}
+
+// TestIdentifiersFromDataSet tests identifier collection from patient attributes
+func TestIdentifiersFromDataSet(t *testing.T) {
+	ds := setupTestDataSet(t)
+
+	ids := IdentifiersFromDataSet(ds)
+	assert.Contains(t, ids, "Smith")
+	assert.Contains(t, ids, "John")
+	assert.Contains(t, ids, "Robert")
+	assert.Contains(t, ids, "Dr")
+	assert.Contains(t, ids, "PAT123456789")
+	assert.Contains(t, ids, "19750315")
+
+	assert.Nil(t, IdentifiersFromDataSet(nil))
+}
+
+// TestAnonymizeCleanDescriptors tests that descriptors are scrubbed rather than removed
+func TestAnonymizeCleanDescriptors(t *testing.T) {
+	ds := setupTestDataSet(t)
+
+	val, err := value.NewStringValue(vr.LongString, []string{"CT chest John Smith"})
+	require.NoError(t, err)
+	elem, err := element.NewElement(tag.StudyDescription, vr.LongString, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(elem))
+
+	val, err = value.NewStringValue(vr.LongText, []string{"Compared with prior for PAT123456789 (aka Jack Doe)"})
+	require.NoError(t, err)
+	elem, err = element.NewElement(tag.ImageComments, vr.LongText, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(elem))
+
+	anonymizer := NewAnonymizerWithConfig(Config{
+		Profile:               ProfileBasic,
+		Options:               Options{CleanDescriptors: true},
+		PatientName:           "ANONYMOUS",
+		PatientID:             "ANON001",
+		DescriptorIdentifiers: []string{"Jack Doe"},
+	})
+
+	result, err := anonymizer.Anonymize(ds)
+	require.NoError(t, err)
+
+	desc, err := result.Get(tag.StudyDescription)
+	require.NoError(t, err)
+	assert.Equal(t, "CT chest", desc.Value().String())
+
+	comments, err := result.Get(tag.ImageComments)
+	require.NoError(t, err)
+	assert.Equal(t, "Compared with prior for (aka )", comments.Value().String())
+}
@@ -43,6 +43,51 @@
 //	}
 //	anonymizer := anonymize.NewAnonymizerWithConfig(config)
 //
+// # Keeping and Removing Specific Tags
+//
+// For the common "standard profile with a few exceptions" case, list the exceptions
+// in Options.KeepTags and Options.RemoveTags instead of building a table:
+//
+//	opts := anonymize.Options{
+//	    KeepTags:   []tag.Tag{tag.StudyDescription, tag.StudyInstanceUID},
+//	    RemoveTags: []tag.Tag{tag.StationName},
+//	}
+//	anonymizer := anonymize.NewAnonymizerWithConfig(anonymize.Config{
+//	    Profile: anonymize.ProfileBasic,
+//	    Options: opts,
+//	})
+//
+// The overrides are applied last, so precedence from highest to lowest is:
+//
+//  1. RemoveTags: the attribute is removed (X).
+//  2. KeepTags: the attribute is kept unchanged, even if the profile would dummy,
+//     empty, remove, clean or regenerate it, or it is a private, overlay or curve
+//     attribute otherwise removed by the options.
+//  3. Config.CustomActions, then the profile's or table's action.
+//
+// # Custom Action Tables
+//
+// For full control, supply a tag-to-action table. BasicProfileTable returns a copy
+// of the standard table to start from, and RegisterCleaner installs a tag-specific
+// cleaner for ActionClean (C) attributes:
+//
+//	table := anonymize.BasicProfileTable()
+//	table[tag.InstitutionName] = anonymize.ActionKeep
+//	table[tag.StationName] = anonymize.ActionClean
+//	anonymizer := anonymize.NewAnonymizerFromTable(table, anonymize.Options{RemovePrivateTags: true})
+//	anonymizer.RegisterCleaner(tag.StationName, cleanStationName)
+//
+// # Clean Descriptors
+//
+// With the Clean Descriptors Option, descriptor attributes (Study Description, Series
+// Description, Image Comments, ...) are kept but scrubbed of the patient's name parts,
+// IDs, accession number and dates, matched case-insensitively on word boundaries.
+// Extra identifiers can be supplied with Config.DescriptorIdentifiers, and the
+// scrubber can be used on its own:
+//
+//	scrubber := anonymize.NewDescriptorScrubber(anonymize.IdentifiersFromDataSet(ds))
+//	clean := scrubber.Scrub("Follow-up for John Smith, MRN12345")
+//
 // # Action Types
 //
 // The package uses standard DICOM PS3.15 action types:
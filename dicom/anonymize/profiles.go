@@ -5,6 +5,26 @@ import (
 	"github.com/codeninja55/go-radx/dicom/tag"
 )
 
+// BasicProfileTable returns the tag-to-action table of the Basic Application Level
+// Confidentiality Profile, as applied by NewAnonymizer(ProfileBasic) without any
+// Retain options.
+//
+// Each call returns a new map, so callers can modify it freely and pass it to
+// NewAnonymizerFromTable.
+//
+// Example:
+//
+//	table := anonymize.BasicProfileTable()
+//	table[tag.PatientSex] = anonymize.ActionKeep // Z -> K
+//	anonymizer := anonymize.NewAnonymizerFromTable(table, anonymize.Options{})
+//
+// Reference: https://dicom.nema.org/medical/dicom/current/output/html/part15.html#table_E.1-1
+func BasicProfileTable() map[tag.Tag]Action {
+	a := &Anonymizer{actions: make(map[tag.Tag]Action)}
+	a.initializeBasicProfile()
+	return a.actions
+}
+
 // initializeBasicProfile sets up actions for the Basic Application Level Confidentiality Profile.
 //
 // This implements DICOM PS3.15 Annex E Table E.1-1:
@@ -135,7 +155,20 @@ func (a *Anonymizer) initializeBasicProfile() {
 	// File metadata that may contain identifying information
 	a.actions[tag.MediaStorageSOPInstanceUID] = ActionUID // U - should match SOPInstanceUID
 
-	// Apply options-based modifications
+	// Patient characteristics are removed unless RetainPatientCharacteristics is set
+	a.actions[tag.PatientAge] = ActionEmpty     // Z
+	a.actions[tag.PatientSex] = ActionEmpty     // Z
+	a.actions[tag.PatientSize] = ActionRemove   // X
+	a.actions[tag.PatientWeight] = ActionRemove // X
+
+	a.applyRetainOptions()
+}
+
+// applyRetainOptions overrides the actions of the attributes covered by the Retain
+// options set in the configuration, so that they are kept (or, for
+// RetainLongitudinalTemporalInfo, passed to their callbacks) whichever profile or
+// action table the anonymizer started from.
+func (a *Anonymizer) applyRetainOptions() {
 	if a.config.Options.RetainDeviceIdentity {
 		a.actions[tag.InstitutionName] = ActionKeep
 		a.actions[tag.StationName] = ActionKeep
@@ -148,11 +181,6 @@ func (a *Anonymizer) initializeBasicProfile() {
 		a.actions[tag.PatientSex] = ActionKeep
 		a.actions[tag.PatientSize] = ActionKeep
 		a.actions[tag.PatientWeight] = ActionKeep
-	} else {
-		a.actions[tag.PatientAge] = ActionEmpty
-		a.actions[tag.PatientSex] = ActionEmpty
-		a.actions[tag.PatientSize] = ActionRemove
-		a.actions[tag.PatientWeight] = ActionRemove
 	}
 
 	if a.config.Options.RetainUIDs {
@@ -0,0 +1,366 @@
+package dicom
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// Builder assembles a new DataSet for a given SOP class through chainable setters.
+//
+// Each setter records the attribute and returns the builder, so calls can be chained.
+// The first invalid value is remembered and returned by Build; later setters are
+// ignored once an error has occurred. Build fills in missing UIDs and the Modality,
+// then validates the result against the SOP class's IOD with ValidateIOD.
+//
+// Example:
+//
+//	ds, err := dicom.NewBuilder(uid.SecondaryCaptureImageStorage).
+//	    PatientName("Doe^John").
+//	    PatientID("12345").
+//	    ConversionType("WSD").
+//	    Rows(512).Columns(512).
+//	    SamplesPerPixel(1).PhotometricInterpretation("MONOCHROME2").
+//	    BitsAllocated(8).BitsStored(8).HighBit(7).PixelRepresentation(0).
+//	    PixelData(pixels).
+//	    Build()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+type Builder struct {
+	ds        *DataSet
+	pixelData []byte
+	err       error
+}
+
+// NewBuilder starts a dataset for an instance of sopClassUID.
+func NewBuilder(sopClassUID uid.UID) *Builder {
+	b := &Builder{ds: NewDataSet()}
+	return b.setString(tag.SOPClassUID, vr.UniqueIdentifier, sopClassUID.String())
+}
+
+// PatientName sets Patient's Name (0010,0010), e.g. "Doe^John".
+func (b *Builder) PatientName(name string) *Builder {
+	return b.apply(func() error { return b.ds.SetPatientName(name) })
+}
+
+// PatientID sets Patient ID (0010,0020).
+func (b *Builder) PatientID(id string) *Builder {
+	return b.apply(func() error { return b.ds.SetPatientID(id) })
+}
+
+// PatientBirthDate sets Patient's Birth Date (0010,0030) in YYYYMMDD format.
+func (b *Builder) PatientBirthDate(date string) *Builder {
+	return b.apply(func() error { return b.ds.SetPatientBirthDate(date) })
+}
+
+// PatientSex sets Patient's Sex (0010,0040): "M", "F" or "O".
+func (b *Builder) PatientSex(sex string) *Builder {
+	return b.apply(func() error { return b.ds.SetPatientSex(sex) })
+}
+
+// StudyUID sets Study Instance UID (0020,000D). If not called, Build generates one.
+func (b *Builder) StudyUID(studyUID string) *Builder {
+	return b.apply(func() error { return b.ds.SetStudyInstanceUID(studyUID) })
+}
+
+// SeriesUID sets Series Instance UID (0020,000E). If not called, Build generates one.
+func (b *Builder) SeriesUID(seriesUID string) *Builder {
+	return b.apply(func() error { return b.ds.SetSeriesInstanceUID(seriesUID) })
+}
+
+// SOPInstanceUID sets SOP Instance UID (0008,0018). If not called, Build generates one.
+func (b *Builder) SOPInstanceUID(instanceUID string) *Builder {
+	return b.apply(func() error { return b.ds.SetSOPInstanceUID(instanceUID) })
+}
+
+// StudyDate sets Study Date (0008,0020) in YYYYMMDD format.
+func (b *Builder) StudyDate(date string) *Builder {
+	return b.apply(func() error { return b.ds.SetStudyDate(date) })
+}
+
+// StudyTime sets Study Time (0008,0030) in HHMMSS format.
+func (b *Builder) StudyTime(t string) *Builder {
+	return b.apply(func() error { return b.ds.SetStudyTime(t) })
+}
+
+// AccessionNumber sets Accession Number (0008,0050).
+func (b *Builder) AccessionNumber(number string) *Builder {
+	return b.apply(func() error { return b.ds.SetAccessionNumber(number) })
+}
+
+// SeriesNumber sets Series Number (0020,0011).
+func (b *Builder) SeriesNumber(n int) *Builder {
+	return b.apply(func() error { return b.ds.SetSeriesNumber(n) })
+}
+
+// InstanceNumber sets Instance Number (0020,0013).
+func (b *Builder) InstanceNumber(n int) *Builder {
+	return b.apply(func() error { return b.ds.SetInstanceNumber(n) })
+}
+
+// Modality sets Modality (0008,0060). If not called, Build uses the SOP class default.
+func (b *Builder) Modality(modality string) *Builder {
+	return b.setString(tag.Modality, vr.CodeString, modality)
+}
+
+// ImageType sets Image Type (0008,0008), e.g. "ORIGINAL", "PRIMARY", "AXIAL".
+func (b *Builder) ImageType(values ...string) *Builder {
+	return b.setString(tag.ImageType, vr.CodeString, values...)
+}
+
+// ConversionType sets Conversion Type (0008,0064), required for Secondary Capture.
+func (b *Builder) ConversionType(conversionType string) *Builder {
+	return b.setString(tag.ConversionType, vr.CodeString, conversionType)
+}
+
+// Rescale sets Rescale Slope (0028,1053) and Rescale Intercept (0028,1052).
+func (b *Builder) Rescale(slope, intercept float64) *Builder {
+	b.setString(tag.RescaleSlope, vr.DecimalString, strconv.FormatFloat(slope, 'g', -1, 64))
+	return b.setString(tag.RescaleIntercept, vr.DecimalString, strconv.FormatFloat(intercept, 'g', -1, 64))
+}
+
+// Rows sets Rows (0028,0010).
+func (b *Builder) Rows(n int) *Builder {
+	return b.setUint16(tag.Rows, n)
+}
+
+// Columns sets Columns (0028,0011).
+func (b *Builder) Columns(n int) *Builder {
+	return b.setUint16(tag.Columns, n)
+}
+
+// SamplesPerPixel sets Samples per Pixel (0028,0002).
+func (b *Builder) SamplesPerPixel(n int) *Builder {
+	return b.setUint16(tag.SamplesPerPixel, n)
+}
+
+// PhotometricInterpretation sets Photometric Interpretation (0028,0004).
+func (b *Builder) PhotometricInterpretation(pi string) *Builder {
+	return b.setString(tag.PhotometricInterpretation, vr.CodeString, pi)
+}
+
+// BitsAllocated sets Bits Allocated (0028,0100).
+func (b *Builder) BitsAllocated(n int) *Builder {
+	return b.setUint16(tag.BitsAllocated, n)
+}
+
+// BitsStored sets Bits Stored (0028,0101).
+func (b *Builder) BitsStored(n int) *Builder {
+	return b.setUint16(tag.BitsStored, n)
+}
+
+// HighBit sets High Bit (0028,0102).
+func (b *Builder) HighBit(n int) *Builder {
+	return b.setUint16(tag.HighBit, n)
+}
+
+// PixelRepresentation sets Pixel Representation (0028,0103): 0 unsigned, 1 signed.
+func (b *Builder) PixelRepresentation(n int) *Builder {
+	return b.setUint16(tag.PixelRepresentation, n)
+}
+
+// PixelData sets native Pixel Data (7FE0,0010). The VR is chosen at Build time from
+// Bits Allocated: OB for 8 bits or fewer, OW otherwise.
+func (b *Builder) PixelData(data []byte) *Builder {
+	b.pixelData = data
+	return b
+}
+
+// Element adds an arbitrary element, replacing any element with the same tag. Use it
+// for attributes without a dedicated setter.
+func (b *Builder) Element(elem *element.Element) *Builder {
+	return b.apply(func() error { return b.ds.Set(elem) })
+}
+
+// Build completes and validates the dataset.
+//
+// Missing Study, Series and SOP Instance UIDs are generated and a missing Modality
+// is set from the SOP class where known. Type 2 attributes that were not set are
+// added with zero length. The result is checked with ValidateIOD.
+// Returns the first setter error, or a validation error wrapping
+// ErrMissingRequiredAttribute. Each call returns an independent deep copy, with its
+// own generated UIDs, so a builder can serve as a template for several instances.
+func (b *Builder) Build() (*DataSet, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	ds, err := deepCopyDataSet(b.ds)
+	if err != nil {
+		return nil, err
+	}
+	build := &Builder{ds: ds, pixelData: bytes.Clone(b.pixelData)}
+
+	if !build.ds.Contains(tag.StudyInstanceUID) {
+		build.StudyUID("")
+	}
+	if !build.ds.Contains(tag.SeriesInstanceUID) {
+		build.SeriesUID("")
+	}
+	if !build.ds.Contains(tag.SOPInstanceUID) {
+		build.SOPInstanceUID("")
+	}
+	if !build.ds.Contains(tag.Modality) {
+		if elem, err := build.ds.Get(tag.SOPClassUID); err == nil {
+			if def, ok := iodDefinitions[elem.Value().String()]; ok {
+				build.Modality(def.modality)
+			}
+		}
+	}
+	if build.pixelData != nil {
+		build.setPixelData()
+	}
+	build.addEmptyType2()
+	if build.err != nil {
+		return nil, build.err
+	}
+
+	if err := ValidateIOD(build.ds); err != nil {
+		return nil, err
+	}
+	return build.ds, nil
+}
+
+// deepCopyDataSet returns a copy of ds sharing no elements, values or sequence
+// items with it.
+func deepCopyDataSet(ds *DataSet) (*DataSet, error) {
+	copied := NewDataSet()
+	for _, elem := range ds.Elements() {
+		val, err := deepCopyValue(elem.Value())
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", elem.Tag(), err)
+		}
+		elemCopy, err := element.NewElement(elem.Tag(), elem.VR(), val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", elem.Tag(), err)
+		}
+		if err := copied.Add(elemCopy); err != nil {
+			return nil, err
+		}
+	}
+	return copied, nil
+}
+
+// deepCopyValue returns a copy of v sharing no slices or sequence items with it.
+func deepCopyValue(v value.Value) (value.Value, error) {
+	switch v := v.(type) {
+	case *value.StringValue:
+		return value.NewStringValue(v.VR(), slices.Clone(v.Strings()))
+	case *value.IntValue:
+		return value.NewIntValue(v.VR(), slices.Clone(v.Ints()))
+	case *value.FloatValue:
+		return value.NewFloatValue(v.VR(), slices.Clone(v.Floats()))
+	case *value.BytesValue:
+		return value.NewBytesValue(v.VR(), bytes.Clone(v.Bytes()))
+	case *value.SequenceValue:
+		items := make([]value.Item, 0, v.Len())
+		for i, item := range v.Items() {
+			itemDS, ok := item.(*DataSet)
+			if !ok {
+				return nil, fmt.Errorf("sequence item %d has unsupported type %T", i, item)
+			}
+			itemCopy, err := deepCopyDataSet(itemDS)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, itemCopy)
+		}
+		return value.NewSequenceValue(items)
+	default:
+		return v, nil
+	}
+}
+
+// addEmptyType2 adds every Type 2 attribute of the IOD that was not set as a
+// zero-length element, which is how DICOM records that its value is unknown.
+func (b *Builder) addEmptyType2() {
+	_, type2 := requiredTags(b.ds)
+	for _, t := range type2 {
+		if b.ds.Contains(t) {
+			continue
+		}
+		b.apply(func() error {
+			info, err := tag.Find(t)
+			if err != nil || len(info.VRs) == 0 {
+				return fmt.Errorf("no dictionary VR for Type 2 attribute %s", t)
+			}
+			val, err := emptyValue(info.VRs[0])
+			if err != nil {
+				return err
+			}
+			elem, err := element.NewElement(t, info.VRs[0], val)
+			if err != nil {
+				return err
+			}
+			return b.ds.Set(elem)
+		})
+	}
+}
+
+// apply runs fn unless an earlier setter failed, recording its error.
+func (b *Builder) apply(fn func() error) *Builder {
+	if b.err == nil {
+		b.err = fn()
+	}
+	return b
+}
+
+// setString sets a string-valued element.
+func (b *Builder) setString(t tag.Tag, v vr.VR, values ...string) *Builder {
+	return b.apply(func() error {
+		val, err := value.NewStringValue(v, values)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", tagKeyword(t), err)
+		}
+		elem, err := element.NewElement(t, v, val)
+		if err != nil {
+			return fmt.Errorf("failed to create %s element: %w", tagKeyword(t), err)
+		}
+		return b.ds.Set(elem)
+	})
+}
+
+// setUint16 sets a US element, rejecting values outside 0-65535.
+func (b *Builder) setUint16(t tag.Tag, n int) *Builder {
+	return b.apply(func() error {
+		if n < 0 || n > 0xFFFF {
+			return fmt.Errorf("invalid %s: %d is out of range for US", tagKeyword(t), n)
+		}
+		val, err := value.NewIntValue(vr.UnsignedShort, []int64{int64(n)})
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", tagKeyword(t), err)
+		}
+		elem, err := element.NewElement(t, vr.UnsignedShort, val)
+		if err != nil {
+			return fmt.Errorf("failed to create %s element: %w", tagKeyword(t), err)
+		}
+		return b.ds.Set(elem)
+	})
+}
+
+// setPixelData stores the pending pixel data with a VR matching Bits Allocated.
+func (b *Builder) setPixelData() {
+	v := vr.OtherWord
+	if bits, err := b.ds.GetInts(tag.BitsAllocated); err == nil && len(bits) > 0 && bits[0] <= 8 {
+		v = vr.OtherByte
+	}
+	b.apply(func() error {
+		val, err := value.NewBytesValue(v, b.pixelData)
+		if err != nil {
+			return fmt.Errorf("invalid PixelData: %w", err)
+		}
+		elem, err := element.NewElement(tag.PixelData, v, val)
+		if err != nil {
+			return fmt.Errorf("failed to create PixelData element: %w", err)
+		}
+		return b.ds.Set(elem)
+	})
+}
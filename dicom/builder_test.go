@@ -0,0 +1,182 @@
+package dicom_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSecondaryCaptureBuilder returns a builder with everything a Secondary Capture
+// image requires.
+func newSecondaryCaptureBuilder() *dicom.Builder {
+	return dicom.NewBuilder(uid.SecondaryCaptureImageStorage).
+		PatientName("Doe^John").
+		PatientID("12345").
+		ConversionType("WSD").
+		Rows(2).Columns(2).
+		SamplesPerPixel(1).PhotometricInterpretation("MONOCHROME2").
+		BitsAllocated(8).BitsStored(8).HighBit(7).PixelRepresentation(0).
+		PixelData([]byte{0, 64, 128, 255})
+}
+
+func TestBuilder_Build(t *testing.T) {
+	ds, err := newSecondaryCaptureBuilder().Build()
+	require.NoError(t, err)
+
+	name, err := ds.Get(tag.PatientName)
+	require.NoError(t, err)
+	assert.Equal(t, "Doe^John", name.Value().String())
+
+	// Modality defaults from the SOP class
+	modality, err := ds.Get(tag.Modality)
+	require.NoError(t, err)
+	assert.Equal(t, "OT", modality.Value().String())
+
+	// Missing UIDs are generated
+	for _, tg := range []tag.Tag{tag.StudyInstanceUID, tag.SeriesInstanceUID, tag.SOPInstanceUID} {
+		elem, err := ds.Get(tg)
+		require.NoError(t, err)
+		assert.True(t, uid.IsValid(elem.Value().String()), "generated UID for %s", tg)
+	}
+
+	// 8-bit pixel data is stored as OB
+	pixels, err := ds.Get(tag.PixelData)
+	require.NoError(t, err)
+	assert.Equal(t, vr.OtherByte, pixels.VR())
+
+	// The built dataset is writable
+	require.NoError(t, dicom.WriteFile(filepath.Join(t.TempDir(), "built.dcm"), ds))
+}
+
+func TestBuilder_KeepsExplicitValues(t *testing.T) {
+	ds, err := newSecondaryCaptureBuilder().
+		StudyUID("1.2.3.4").
+		Modality("SC").
+		Build()
+	require.NoError(t, err)
+
+	study, err := ds.Get(tag.StudyInstanceUID)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", study.Value().String())
+
+	modality, err := ds.Get(tag.Modality)
+	require.NoError(t, err)
+	assert.Equal(t, "SC", modality.Value().String())
+}
+
+func TestBuilder_BuildReturnsIndependentCopies(t *testing.T) {
+	b := newSecondaryCaptureBuilder()
+	first, err := b.Build()
+	require.NoError(t, err)
+	require.NoError(t, first.SetPatientName("Changed^Name"))
+
+	second, err := b.Build()
+	require.NoError(t, err)
+	name, err := second.Get(tag.PatientName)
+	require.NoError(t, err)
+	assert.Equal(t, "Doe^John", name.Value().String())
+
+	// Elements are not shared either
+	firstPixels, err := first.Get(tag.PixelData)
+	require.NoError(t, err)
+	firstPixels.Value().Bytes()[0] = 99
+	secondPixels, err := second.Get(tag.PixelData)
+	require.NoError(t, err)
+	assert.Equal(t, byte(0), secondPixels.Value().Bytes()[0])
+}
+
+func TestBuilder_BuildGeneratesUIDsPerCall(t *testing.T) {
+	b := newSecondaryCaptureBuilder()
+	first, err := b.Build()
+	require.NoError(t, err)
+	second, err := b.Build()
+	require.NoError(t, err)
+
+	for _, tg := range []tag.Tag{tag.StudyInstanceUID, tag.SeriesInstanceUID, tag.SOPInstanceUID} {
+		firstUID, err := first.Get(tg)
+		require.NoError(t, err)
+		secondUID, err := second.Get(tg)
+		require.NoError(t, err)
+		assert.NotEqual(t, firstUID.Value().String(), secondUID.Value().String(), "each Build generates its own %s", tg)
+	}
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	t.Run("missing IOD attributes", func(t *testing.T) {
+		_, err := dicom.NewBuilder(uid.CTImageStorage).PatientName("Doe^John").Build()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, dicom.ErrMissingRequiredAttribute)
+		assert.Contains(t, err.Error(), "RescaleSlope")
+		assert.Contains(t, err.Error(), "Rows")
+	})
+
+	t.Run("first setter error is returned", func(t *testing.T) {
+		_, err := newSecondaryCaptureBuilder().Rows(70000).PatientSex("X").Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Rows")
+	})
+
+	t.Run("invalid UID", func(t *testing.T) {
+		_, err := newSecondaryCaptureBuilder().StudyUID("not-a-uid").Build()
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateIOD(t *testing.T) {
+	t.Run("complete dataset", func(t *testing.T) {
+		ds, err := newSecondaryCaptureBuilder().Build()
+		require.NoError(t, err)
+		assert.NoError(t, dicom.ValidateIOD(ds))
+	})
+
+	t.Run("empty Type 1 value", func(t *testing.T) {
+		ds, err := newSecondaryCaptureBuilder().Build()
+		require.NoError(t, err)
+		require.NoError(t, ds.Set(mustNewElement(tag.ConversionType, vr.CodeString,
+			mustNewStringValue(vr.CodeString, []string{""}))))
+
+		err = dicom.ValidateIOD(ds)
+		assert.ErrorIs(t, err, dicom.ErrMissingRequiredAttribute)
+		assert.Contains(t, err.Error(), "ConversionType")
+	})
+
+	t.Run("Type 2 attributes may be empty but not absent", func(t *testing.T) {
+		ds, err := newSecondaryCaptureBuilder().Build()
+		require.NoError(t, err)
+
+		present, empty := ds.IsEmpty(tag.PatientBirthDate)
+		assert.True(t, present, "Build adds unset Type 2 attributes")
+		assert.True(t, empty)
+		require.NoError(t, ds.Set(mustNewElement(tag.PatientName, vr.PersonName,
+			mustNewStringValue(vr.PersonName, []string{}))))
+		assert.NoError(t, dicom.ValidateIOD(ds))
+
+		require.NoError(t, ds.Remove(tag.PatientName))
+		require.NoError(t, ds.Remove(tag.InstanceNumber))
+		err = dicom.ValidateIOD(ds)
+		assert.ErrorIs(t, err, dicom.ErrMissingRequiredAttribute)
+		assert.Contains(t, err.Error(), "PatientName (Type 2)")
+		assert.Contains(t, err.Error(), "InstanceNumber (Type 2)")
+	})
+
+	t.Run("unknown SOP class checks common attributes only", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		require.NoError(t, ds.Add(mustNewElement(tag.SOPClassUID, vr.UniqueIdentifier,
+			mustNewStringValue(vr.UniqueIdentifier, []string{"1.2.3.999"}))))
+
+		err := dicom.ValidateIOD(ds)
+		assert.ErrorIs(t, err, dicom.ErrMissingRequiredAttribute)
+		assert.Contains(t, err.Error(), "SOPInstanceUID")
+		assert.NotContains(t, err.Error(), "Rows")
+	})
+
+	t.Run("nil dataset", func(t *testing.T) {
+		assert.Error(t, dicom.ValidateIOD(nil))
+	})
+}
@@ -0,0 +1,440 @@
+package charset
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// esc is the ISO 2022 escape character that starts an escape sequence.
+const esc = 0x1B
+
+// codeElement is a character repertoire that ISO 2022 code extensions designate to
+// the G0 (bytes 0x21-0x7E) or G1 (bytes 0xA1-0xFE) code element.
+type codeElement struct {
+	escape string // Escape sequence designating the element
+	g1     bool   // Designated to G1 rather than G0
+	width  int    // Bytes per character
+
+	// decode converts the bytes of one character, as stored, to a rune.
+	decode func(b []byte) (rune, bool)
+	// encode converts a rune to the bytes of one character, as stored.
+	encode func(r rune) ([]byte, bool)
+}
+
+var (
+	ascii = &codeElement{
+		escape: "\x1b(B",
+		width:  1,
+		decode: func(b []byte) (rune, bool) { return rune(b[0]), b[0] < 0x80 },
+		encode: func(r rune) ([]byte, bool) { return []byte{byte(r)}, r < 0x80 },
+	}
+
+	// jisRoman is JIS X 0201 Romaji, which differs from ASCII only in 0x5C (yen
+	// sign) and 0x7E (overline); like most implementations they are read as ASCII.
+	jisRoman = &codeElement{
+		escape: "\x1b(J",
+		width:  1,
+		decode: ascii.decode,
+		encode: ascii.encode,
+	}
+
+	jisKatakana = &codeElement{
+		escape: "\x1b)I",
+		g1:     true,
+		width:  1,
+		decode: func(b []byte) (rune, bool) {
+			return 0xFF61 + rune(b[0]) - 0xA1, b[0] >= 0xA1 && b[0] <= 0xDF
+		},
+		encode: func(r rune) ([]byte, bool) {
+			return []byte{byte(r - 0xFF61 + 0xA1)}, r >= 0xFF61 && r <= 0xFF9F
+		},
+	}
+
+	// jisX0208 is read and written through EUC-JP, which stores it with the high
+	// bit of both bytes set.
+	jisX0208 = &codeElement{
+		escape: "\x1b$B",
+		width:  2,
+		decode: func(b []byte) (rune, bool) {
+			return decodeRune(japanese.EUCJP, []byte{b[0] | 0x80, b[1] | 0x80})
+		},
+		encode: func(r rune) ([]byte, bool) {
+			e, ok := encodeRune(japanese.EUCJP, r)
+			if !ok || len(e) != 2 || e[0] < 0xA1 || e[1] < 0xA1 {
+				return nil, false
+			}
+			return []byte{e[0] & 0x7F, e[1] & 0x7F}, true
+		},
+	}
+
+	// jisX0212 is stored by EUC-JP behind the single shift 0x8F.
+	jisX0212 = &codeElement{
+		escape: "\x1b$(D",
+		width:  2,
+		decode: func(b []byte) (rune, bool) {
+			return decodeRune(japanese.EUCJP, []byte{0x8F, b[0] | 0x80, b[1] | 0x80})
+		},
+		encode: func(r rune) ([]byte, bool) {
+			e, ok := encodeRune(japanese.EUCJP, r)
+			if !ok || len(e) != 3 || e[0] != 0x8F {
+				return nil, false
+			}
+			return []byte{e[1] & 0x7F, e[2] & 0x7F}, true
+		},
+	}
+
+	ksX1001 = doubleByteG1("\x1b$)C", korean.EUCKR)
+	gb2312  = doubleByteG1("\x1b$)A", simplifiedchinese.GBK)
+)
+
+// doubleByteG1 returns a G1 code element stored as in the EUC form of enc.
+func doubleByteG1(escape string, enc encoding.Encoding) *codeElement {
+	return &codeElement{
+		escape: escape,
+		g1:     true,
+		width:  2,
+		decode: func(b []byte) (rune, bool) { return decodeRune(enc, b) },
+		encode: func(r rune) ([]byte, bool) {
+			e, ok := encodeRune(enc, r)
+			return e, ok && len(e) == 2 && e[0] >= 0xA1 && e[1] >= 0xA1
+		},
+	}
+}
+
+// singleByteG1 returns the G1 code element of the upper half of an ISO 8859 style
+// character set.
+func singleByteG1(escape string, cm *charmap.Charmap) *codeElement {
+	return &codeElement{
+		escape: escape,
+		g1:     true,
+		width:  1,
+		decode: func(b []byte) (rune, bool) {
+			r := cm.DecodeByte(b[0])
+			return r, r != utf8.RuneError
+		},
+		encode: func(r rune) ([]byte, bool) {
+			b, ok := cm.EncodeRune(r)
+			return []byte{b}, ok && b >= 0xA0
+		},
+	}
+}
+
+// repertoire describes one Defined Term of Specific Character Set.
+type repertoire struct {
+	g0, g1 *codeElement // Code elements; g1 is nil for ASCII only
+
+	// whole decodes and encodes values without code extensions, for character sets
+	// that are not built from code elements (UTF-8, GB18030, GBK).
+	whole encoding.Encoding
+}
+
+var (
+	latin1   = singleByteG1("\x1b-A", charmap.ISO8859_1)
+	latin2   = singleByteG1("\x1b-B", charmap.ISO8859_2)
+	latin3   = singleByteG1("\x1b-C", charmap.ISO8859_3)
+	latin4   = singleByteG1("\x1b-D", charmap.ISO8859_4)
+	cyrillic = singleByteG1("\x1b-L", charmap.ISO8859_5)
+	arabic   = singleByteG1("\x1b-G", charmap.ISO8859_6)
+	greek    = singleByteG1("\x1b-F", charmap.ISO8859_7)
+	hebrew   = singleByteG1("\x1b-H", charmap.ISO8859_8)
+	latin5   = singleByteG1("\x1b-M", charmap.ISO8859_9)
+	latin9   = singleByteG1("\x1b-b", charmap.ISO8859_15)
+	thai     = singleByteG1("\x1b-T", charmap.Windows874)
+)
+
+// repertoires maps the Defined Terms of Specific Character Set, without the
+// "ISO_IR " or "ISO 2022 IR " prefix, to their code elements.
+var repertoires = map[string]repertoire{
+	"6":   {g0: ascii},
+	"100": {g0: ascii, g1: latin1},
+	"101": {g0: ascii, g1: latin2},
+	"109": {g0: ascii, g1: latin3},
+	"110": {g0: ascii, g1: latin4},
+	"144": {g0: ascii, g1: cyrillic},
+	"127": {g0: ascii, g1: arabic},
+	"126": {g0: ascii, g1: greek},
+	"138": {g0: ascii, g1: hebrew},
+	"148": {g0: ascii, g1: latin5},
+	"203": {g0: ascii, g1: latin9},
+	"166": {g0: ascii, g1: thai},
+	"13":  {g0: jisRoman, g1: jisKatakana},
+	"87":  {g0: jisX0208},
+	"159": {g0: jisX0212},
+	"149": {g1: ksX1001},
+	"58":  {g1: gb2312},
+}
+
+// wholeEncodings are the Defined Terms whose values are decoded as a whole, without
+// code extensions.
+var wholeEncodings = map[string]encoding.Encoding{
+	"ISO_IR 192": encoding.Nop, // UTF-8
+	"GB18030":    simplifiedchinese.GB18030,
+	"GBK":        simplifiedchinese.GBK,
+}
+
+// characterSet is a parsed Specific Character Set.
+type characterSet struct {
+	initial  repertoire     // The first value, active at the start of every value
+	elements []*codeElement // Code elements the value may switch to, in declared order
+}
+
+// parse parses a Specific Character Set value, the Defined Terms separated by
+// backslashes. An empty first value means the default repertoire (ASCII).
+func parse(specificCharacterSet string) (*characterSet, error) {
+	terms := strings.Split(specificCharacterSet, `\`)
+	cs := &characterSet{}
+	for i, term := range terms {
+		term = strings.TrimSpace(term)
+		if enc, ok := wholeEncodings[term]; ok {
+			if len(terms) > 1 {
+				return nil, fmt.Errorf("character set %q does not support code extensions", term)
+			}
+			return &characterSet{initial: repertoire{whole: enc}}, nil
+		}
+
+		var number string
+		switch {
+		case term == "" && i == 0:
+			number = "6"
+		case strings.HasPrefix(term, "ISO_IR ") && len(terms) == 1:
+			number = strings.TrimPrefix(term, "ISO_IR ")
+		case strings.HasPrefix(term, "ISO 2022 IR "):
+			number = strings.TrimPrefix(term, "ISO 2022 IR ")
+		}
+		rep, ok := repertoires[number]
+		if !ok {
+			return nil, fmt.Errorf("unsupported specific character set %q", term)
+		}
+		if i == 0 {
+			if rep.g0 == nil {
+				return nil, fmt.Errorf("character set %q cannot be the first value of Specific Character Set", term)
+			}
+			cs.initial = rep
+		}
+		for _, e := range []*codeElement{rep.g0, rep.g1} {
+			if e != nil {
+				cs.elements = append(cs.elements, e)
+			}
+		}
+	}
+	if len(terms) == 1 && cs.initial.g0 != ascii && cs.initial.g0 != jisRoman {
+		return nil, fmt.Errorf("character set %q requires code extensions", specificCharacterSet)
+	}
+	return cs, nil
+}
+
+// Decode converts a string value encoded in specificCharacterSet to UTF-8.
+//
+// Backslashes separating multiple values and line breaks of text values reset the
+// code extensions to those of the first value. Use DecodePersonName for PN values,
+// whose components reset them too.
+//
+// Returns an error if the character set is not supported or data is not valid in it.
+//
+// Example:
+//
+//	text, err := charset.Decode([]byte("M\xfcller"), "ISO_IR 100") // "Müller"
+func Decode(data []byte, specificCharacterSet string) (string, error) {
+	return decode(data, specificCharacterSet, "\\\r\n\f\t")
+}
+
+// DecodePersonName converts a PN value encoded in specificCharacterSet to UTF-8,
+// resetting code extensions at every component (^) and component group (=)
+// delimiter as well as between values.
+//
+// Example:
+//
+//	name, err := charset.DecodePersonName(raw, `ISO 2022 IR 6\ISO 2022 IR 87`)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.1.2.5.3
+func DecodePersonName(data []byte, specificCharacterSet string) (string, error) {
+	return decode(data, specificCharacterSet, "\\\r\n\f\t^=")
+}
+
+// Encode converts a UTF-8 string value to specificCharacterSet, for writing.
+//
+// With code extensions, each character is written in the first listed repertoire
+// that contains it, with the escape sequences needed to switch to it, and the value
+// returns to ASCII before every delimiter and at its end.
+//
+// Returns an error if the character set is not supported or s contains a character
+// none of its repertoires can represent.
+//
+// Example:
+//
+//	raw, err := charset.Encode("Müller", "ISO_IR 100") // "M\xfcller"
+func Encode(s string, specificCharacterSet string) ([]byte, error) {
+	return encode(s, specificCharacterSet, "\\\r\n\f\t")
+}
+
+// EncodePersonName converts a UTF-8 PN value to specificCharacterSet, treating the
+// component (^) and component group (=) delimiters as points where code extensions
+// return to their initial state.
+//
+// Example:
+//
+//	raw, err := charset.EncodePersonName("Yamada^Tarou=山田^太郎=やまだ^たろう",
+//	    `ISO 2022 IR 6\ISO 2022 IR 87`)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_H.3.1
+func EncodePersonName(s string, specificCharacterSet string) ([]byte, error) {
+	return encode(s, specificCharacterSet, "\\\r\n\f\t^=")
+}
+
+// decode implements Decode, resetting code extensions after any of delimiters.
+func decode(data []byte, specificCharacterSet, delimiters string) (string, error) {
+	cs, err := parse(specificCharacterSet)
+	if err != nil {
+		return "", err
+	}
+	if cs.initial.whole != nil {
+		out, err := cs.initial.whole.NewDecoder().Bytes(data)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s value: %w", specificCharacterSet, err)
+		}
+		return string(out), nil
+	}
+
+	var sb strings.Builder
+	g0, g1 := cs.initial.g0, cs.initial.g1
+	for i := 0; i < len(data); {
+		b := data[i]
+		if b == esc {
+			e := cs.designation(data[i:])
+			if e == nil {
+				return "", fmt.Errorf("unsupported escape sequence at byte %d of %s value", i, specificCharacterSet)
+			}
+			if e.g1 {
+				g1 = e
+			} else {
+				g0 = e
+			}
+			i += len(e.escape)
+			continue
+		}
+
+		e := g0
+		if b >= 0x80 {
+			e = g1
+		} else if b < 0x21 || g0.width == 1 {
+			// Controls, space and single-byte G0 characters
+			sb.WriteByte(b)
+			if strings.IndexByte(delimiters, b) >= 0 {
+				g0, g1 = cs.initial.g0, cs.initial.g1
+			}
+			i++
+			continue
+		}
+		if e == nil {
+			return "", fmt.Errorf("byte 0x%02X at %d of %s value has no character set", b, i, specificCharacterSet)
+		}
+		if i+e.width > len(data) {
+			return "", fmt.Errorf("truncated character at byte %d of %s value", i, specificCharacterSet)
+		}
+		r, ok := e.decode(data[i : i+e.width])
+		if !ok {
+			return "", fmt.Errorf("invalid character at byte %d of %s value", i, specificCharacterSet)
+		}
+		sb.WriteRune(r)
+		i += e.width
+	}
+	return sb.String(), nil
+}
+
+// encode implements Encode, resetting code extensions after any of delimiters.
+func encode(s string, specificCharacterSet, delimiters string) ([]byte, error) {
+	cs, err := parse(specificCharacterSet)
+	if err != nil {
+		return nil, err
+	}
+	if cs.initial.whole != nil {
+		out, err := cs.initial.whole.NewEncoder().Bytes([]byte(s))
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode %q in %s: %w", s, specificCharacterSet, err)
+		}
+		return out, nil
+	}
+
+	var out []byte
+	g0, g1 := cs.initial.g0, cs.initial.g1
+	for _, r := range s {
+		if r < 0x80 {
+			if g0.width != 1 {
+				out = append(out, ascii.escape...)
+				g0 = ascii
+			}
+			out = append(out, byte(r))
+			if strings.ContainsRune(delimiters, r) {
+				g0, g1 = cs.initial.g0, cs.initial.g1
+			}
+			continue
+		}
+
+		e, encoded := cs.encodeRune(r, g0, g1)
+		if e == nil {
+			return nil, fmt.Errorf("character %q cannot be encoded in %s", r, specificCharacterSet)
+		}
+		if e.g1 && e != g1 {
+			out = append(out, e.escape...)
+			g1 = e
+		} else if !e.g1 && e != g0 {
+			out = append(out, e.escape...)
+			g0 = e
+		}
+		out = append(out, encoded...)
+	}
+	if g0.width != 1 {
+		out = append(out, ascii.escape...)
+	}
+	return out, nil
+}
+
+// designation returns the code element whose escape sequence starts data, if it is
+// one of the character set's repertoires or ASCII.
+func (cs *characterSet) designation(data []byte) *codeElement {
+	for _, e := range append([]*codeElement{ascii}, cs.elements...) {
+		if strings.HasPrefix(string(data), e.escape) {
+			return e
+		}
+	}
+	return nil
+}
+
+// encodeRune returns the code element to write r in, preferring the designated
+// elements g0 and g1 over switching, and r's bytes in it.
+func (cs *characterSet) encodeRune(r rune, g0, g1 *codeElement) (*codeElement, []byte) {
+	for _, e := range append([]*codeElement{g0, g1}, cs.elements...) {
+		if e == nil {
+			continue
+		}
+		if b, ok := e.encode(r); ok {
+			return e, b
+		}
+	}
+	return nil, nil
+}
+
+// decodeRune decodes the bytes of a single character in enc.
+func decodeRune(enc encoding.Encoding, b []byte) (rune, bool) {
+	out, err := enc.NewDecoder().Bytes(b)
+	if err != nil {
+		return utf8.RuneError, false
+	}
+	r, size := utf8.DecodeRune(out)
+	return r, r != utf8.RuneError && size == len(out)
+}
+
+// encodeRune encodes a single character in enc.
+func encodeRune(enc encoding.Encoding, r rune) ([]byte, bool) {
+	out, err := enc.NewEncoder().Bytes([]byte(string(r)))
+	return out, err == nil
+}
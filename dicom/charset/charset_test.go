@@ -0,0 +1,114 @@
+package charset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// japaneseName is the PN example of PS3.5 Section H.3.1, with its encoding in
+// ISO 2022 IR 6\ISO 2022 IR 87.
+const (
+	japaneseName    = "Yamada^Tarou=山田^太郎=やまだ^たろう"
+	japaneseNameRaw = "Yamada^Tarou=\x1b$B;3ED\x1b(B^\x1b$BB@O:\x1b(B=\x1b$B$d$^$@\x1b(B^\x1b$B$?$m$&\x1b(B"
+	japaneseCharset = `ISO 2022 IR 6\ISO 2022 IR 87`
+)
+
+// TestEncodePersonName_Japanese tests that escape sequences are emitted and reset
+// at every PN delimiter, matching the standard's example byte for byte.
+func TestEncodePersonName_Japanese(t *testing.T) {
+	raw, err := EncodePersonName(japaneseName, japaneseCharset)
+	require.NoError(t, err)
+	assert.Equal(t, japaneseNameRaw, string(raw))
+
+	decoded, err := DecodePersonName(raw, japaneseCharset)
+	require.NoError(t, err)
+	assert.Equal(t, japaneseName, decoded)
+}
+
+// TestDecodePersonName_ResetsAtDelimiters tests that a G1 designation does not
+// carry over a component group delimiter.
+func TestDecodePersonName_ResetsAtDelimiters(t *testing.T) {
+	_, err := DecodePersonName([]byte("=\x1b$)C\xfb\xf3=\xfb\xf3"), `\ISO 2022 IR 149`)
+	assert.ErrorContains(t, err, "has no character set")
+
+	// Without the PN delimiters, the designation lasts to the end of the value
+	decoded, err := Decode([]byte("=\x1b$)C\xfb\xf3=\xfb\xf3"), `\ISO 2022 IR 149`)
+	require.NoError(t, err)
+	assert.Equal(t, "=洪=洪", decoded)
+}
+
+// TestDecode_SingleCharacterSets tests values without code extensions.
+func TestDecode_SingleCharacterSets(t *testing.T) {
+	tests := []struct {
+		name    string
+		charset string
+		raw     string
+		want    string
+	}{
+		{name: "default repertoire", charset: "", raw: "Smith^John", want: "Smith^John"},
+		{name: "ASCII", charset: "ISO_IR 6", raw: "Smith", want: "Smith"},
+		{name: "Latin-1", charset: "ISO_IR 100", raw: "M\xfcller", want: "Müller"},
+		{name: "Cyrillic", charset: "ISO_IR 144", raw: "\xbb\xee\xda\xe1\xd5\xdc\xd1\xe3\xe0\xd3", want: "Люксембург"},
+		{name: "Greek", charset: "ISO_IR 126", raw: "\xc4\xe9\xef\xed\xf5\xf3\xe9\xef\xf2", want: "Διονυσιος"},
+		{name: "UTF-8", charset: "ISO_IR 192", raw: "Wang^XiaoDong=王^小東", want: "Wang^XiaoDong=王^小東"},
+		{name: "GB18030", charset: "GB18030", raw: "Wang^XiaoDong=\xcd\xf5^\xd0\xa1\xb6\xab", want: "Wang^XiaoDong=王^小东"},
+		{name: "JIS X 0201 katakana", charset: "ISO_IR 13", raw: "\xd4\xcf\xc0\xde", want: "ﾔﾏﾀﾞ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode([]byte(tt.raw), tt.charset)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			raw, err := Encode(got, tt.charset)
+			require.NoError(t, err)
+			assert.Equal(t, tt.raw, string(raw))
+		})
+	}
+}
+
+// TestEncodePersonName_Korean tests a G1 code element, which is designated once
+// per component group and needs no return to ASCII.
+func TestEncodePersonName_Korean(t *testing.T) {
+	const cs = `\ISO 2022 IR 149`
+	const raw = "Hong^Gildong=\x1b$)C\xfb\xf3^\x1b$)C\xd1\xce\xd4\xd7=\x1b$)C\xc8\xab^\x1b$)C\xb1\xe6\xb5\xbf"
+
+	decoded, err := DecodePersonName([]byte(raw), cs)
+	require.NoError(t, err)
+	assert.Equal(t, "Hong^Gildong=洪^吉洞=홍^길동", decoded)
+
+	encoded, err := EncodePersonName(decoded, cs)
+	require.NoError(t, err)
+	assert.Equal(t, raw, string(encoded))
+}
+
+// TestDecode_MultiValue tests that value separators reset code extensions.
+func TestDecode_MultiValue(t *testing.T) {
+	decoded, err := Decode([]byte("\x1b$)C\xfb\xf3\\\x1b$)C\xfb\xf3"), `\ISO 2022 IR 149`)
+	require.NoError(t, err)
+	assert.Equal(t, `洪\洪`, decoded)
+
+	_, err = Decode([]byte("\x1b$)C\xfb\xf3\\\xfb\xf3"), `\ISO 2022 IR 149`)
+	assert.ErrorContains(t, err, "has no character set")
+}
+
+// TestCharset_Errors tests unsupported character sets and invalid values.
+func TestCharset_Errors(t *testing.T) {
+	_, err := Decode([]byte("x"), "ISO_IR 999")
+	assert.ErrorContains(t, err, "unsupported specific character set")
+
+	_, err = Decode([]byte("x"), `ISO_IR 192\ISO 2022 IR 87`)
+	assert.ErrorContains(t, err, "does not support code extensions")
+
+	_, err = Decode([]byte("\x1b$B;"), japaneseCharset)
+	assert.ErrorContains(t, err, "truncated character")
+
+	_, err = Decode([]byte("\x1b$)C\xb1"), japaneseCharset)
+	assert.ErrorContains(t, err, "unsupported escape sequence")
+
+	_, err = Encode("山田", "ISO_IR 100")
+	assert.ErrorContains(t, err, "cannot be encoded")
+}
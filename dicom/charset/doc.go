@@ -0,0 +1,36 @@
+// Package charset decodes and encodes DICOM string values in the character
+// repertoires named by Specific Character Set (0008,0005).
+//
+// String values are stored in a dataset as the bytes read from the file. Values of
+// the VRs affected by Specific Character Set (SH, LO, ST, LT, UC, UT and PN) must be
+// decoded before display, and encoded before they are written, with the dataset's
+// character set.
+//
+// # Single Character Sets
+//
+// A single-valued Specific Character Set such as "ISO_IR 100" (Latin-1) or
+// "ISO_IR 192" (UTF-8) applies to every byte of the value:
+//
+//	text, err := charset.Decode(raw, "ISO_IR 100")
+//
+// # Code Extensions
+//
+// A multi-valued Specific Character Set such as "ISO 2022 IR 6\ISO 2022 IR 87"
+// enables ISO/IEC 2022 code extension techniques: escape sequences within the value
+// switch between the listed repertoires, for example to write the ideographic and
+// phonetic groups of a Japanese person name in JIS X 0208. The active repertoires
+// return to those of the first value at the start of each line and each value, and
+// for person names at each component and component group delimiter; Encode and
+// EncodePersonName emit the escape sequences this requires:
+//
+//	raw, err := charset.EncodePersonName("Yamada^Tarou=山田^太郎=やまだ^たろう",
+//	    `ISO 2022 IR 6\ISO 2022 IR 87`)
+//
+// Supported repertoires are ASCII (ISO_IR 6), ISO 8859 parts 1-9 and 15, Thai
+// (ISO_IR 166), JIS X 0201 (ISO_IR 13), JIS X 0208 (ISO 2022 IR 87), JIS X 0212
+// (ISO 2022 IR 159), KS X 1001 (ISO 2022 IR 149), GB 2312 (ISO 2022 IR 58), UTF-8
+// (ISO_IR 192), GB18030 and GBK.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#chapter_6
+package charset
@@ -0,0 +1,150 @@
+package dicom
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// csvColumnPresets are the built-in column sets of CSVColumnPreset.
+var csvColumnPresets = map[string][]tag.Tag{
+	"study-summary": {
+		tag.PatientID, tag.PatientName, tag.StudyInstanceUID, tag.AccessionNumber,
+		tag.StudyDate, tag.StudyDescription, tag.Modality, tag.SeriesDescription,
+	},
+	"series-summary": {
+		tag.PatientID, tag.StudyInstanceUID, tag.SeriesInstanceUID, tag.SeriesNumber,
+		tag.Modality, tag.SeriesDescription, tag.BodyPartExamined, tag.SeriesDate,
+	},
+	"instance-qa": {
+		tag.SOPInstanceUID, tag.SOPClassUID, tag.SeriesInstanceUID, tag.InstanceNumber,
+		tag.Rows, tag.Columns, tag.BitsStored, tag.PhotometricInterpretation,
+		tag.NumberOfFrames, tag.TransferSyntaxUID,
+	},
+}
+
+// CSVColumnPreset returns a copy of a built-in column set for ExportCSV:
+//   - "study-summary": PatientID, PatientName, StudyInstanceUID, AccessionNumber,
+//     StudyDate, StudyDescription, Modality, SeriesDescription
+//   - "series-summary": PatientID, StudyInstanceUID, SeriesInstanceUID, SeriesNumber,
+//     Modality, SeriesDescription, BodyPartExamined, SeriesDate
+//   - "instance-qa": SOPInstanceUID, SOPClassUID, SeriesInstanceUID, InstanceNumber,
+//     Rows, Columns, BitsStored, PhotometricInterpretation, NumberOfFrames,
+//     TransferSyntaxUID
+//
+// Returns false if name is not a preset.
+//
+// Example:
+//
+//	columns, _ := dicom.CSVColumnPreset("study-summary")
+//	err := dicom.ExportCSV(os.Stdout, coll, columns)
+func CSVColumnPreset(name string) ([]tag.Tag, bool) {
+	columns, ok := csvColumnPresets[name]
+	if !ok {
+		return nil, false
+	}
+	return append([]tag.Tag(nil), columns...), true
+}
+
+// ExportCSV writes one CSV row per instance of coll with the given tags as columns,
+// for spreadsheet-based QA and audit reports.
+//
+// The header row holds the keyword of each tag (its (gggg,eeee) form for private or
+// unknown tags). Values are rendered as strings with padding trimmed and multiple
+// values joined with a backslash, as in DICOM. Absent attributes and binary values
+// (OB, OW, UN, ...) are written as empty cells; sequences as their item count.
+//
+// Rows are ordered by Study Instance UID, then Series Number and Instance Number
+// (falling back to UID order), so the output is deterministic.
+//
+// Returns an error if coll is nil, no columns are given, or writing fails.
+//
+// Example:
+//
+//	result, err := dicom.ParseDirectory("/data/incoming")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	f, _ := os.Create("audit.csv")
+//	defer f.Close()
+//	err = dicom.ExportCSV(f, result.Collection, []tag.Tag{
+//	    tag.PatientID, tag.StudyDate, tag.Modality, tag.SeriesDescription,
+//	})
+func ExportCSV(w io.Writer, coll *DataSetCollection, columns []tag.Tag) error {
+	if coll == nil {
+		return fmt.Errorf("cannot export CSV from nil collection")
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("no CSV columns given")
+	}
+
+	datasets := coll.DataSets()
+	sort.SliceStable(datasets, func(i, j int) bool {
+		a, b := datasets[i], datasets[j]
+		if studyA, studyB := stringValue(a, tag.StudyInstanceUID), stringValue(b, tag.StudyInstanceUID); studyA != studyB {
+			return studyA < studyB
+		}
+		seriesA, seriesB := stringValue(a, tag.SeriesInstanceUID), stringValue(b, tag.SeriesInstanceUID)
+		if seriesA != seriesB {
+			return lessByNumberThenUID(intValue(a, tag.SeriesNumber), intValue(b, tag.SeriesNumber), seriesA, seriesB)
+		}
+		return lessByNumberThenUID(intValue(a, tag.InstanceNumber), intValue(b, tag.InstanceNumber),
+			stringValue(a, tag.SOPInstanceUID), stringValue(b, tag.SOPInstanceUID))
+	})
+
+	cw := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, t := range columns {
+		header[i] = t.String()
+		if info, err := tag.Find(t); err == nil {
+			header[i] = info.Keyword
+		}
+	}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	row := make([]string, len(columns))
+	for _, ds := range datasets {
+		for i, t := range columns {
+			row[i] = csvCell(ds, t)
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// csvCell renders the value of t in ds for a CSV cell.
+func csvCell(ds *DataSet, t tag.Tag) string {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return ""
+	}
+
+	switch v := elem.Value().(type) {
+	case *value.StringValue:
+		values := v.Strings()
+		trimmed := make([]string, len(values))
+		for i, s := range values {
+			trimmed[i] = strings.TrimRight(strings.TrimSpace(s), "\x00")
+		}
+		return strings.Join(trimmed, "\\")
+	case *value.BytesValue:
+		return ""
+	default:
+		return elem.Value().String()
+	}
+}
@@ -0,0 +1,106 @@
+package dicom_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportCSV(t *testing.T) {
+	const ctImage = "1.2.840.10008.5.1.4.1.1.2"
+
+	newInstance := func(sopUID, seriesUID, studyUID string, seriesNumber int, instanceNumber string) *dicom.DataSet {
+		ds := createTestDataSetForCollection(sopUID, seriesUID, studyUID, "PAT001", "ACC42", ctImage, seriesNumber)
+		require.NoError(t, ds.Add(mustNewElement(tag.InstanceNumber, vr.IntegerString,
+			mustNewStringValue(vr.IntegerString, []string{instanceNumber}))))
+		require.NoError(t, ds.Add(mustNewElement(tag.StudyDate, vr.Date,
+			mustNewStringValue(vr.Date, []string{"20240115"}))))
+		require.NoError(t, ds.Add(mustNewElement(tag.SeriesDescription, vr.LongString,
+			mustNewStringValue(vr.LongString, []string{"AX, \"thin\" "}))))
+		require.NoError(t, ds.Add(mustNewElement(tag.ImageType, vr.CodeString,
+			mustNewStringValue(vr.CodeString, []string{"ORIGINAL", "PRIMARY", "AXIAL"}))))
+		rows, err := value.NewIntValue(vr.UnsignedShort, []int64{512})
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(mustNewElement(tag.Rows, vr.UnsignedShort, rows)))
+		pixels, err := value.NewBytesValue(vr.OtherWord, make([]byte, 32))
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(mustNewElement(tag.PixelData, vr.OtherWord, pixels)))
+		return ds
+	}
+
+	coll := dicom.NewDataSetCollection()
+	require.NoError(t, coll.Add(newInstance("1.2.3.2.2", "1.2.3.2", "1.2.3", 2, "2")))
+	require.NoError(t, coll.Add(newInstance("1.2.3.1.1", "1.2.3.1", "1.2.3", 1, "1")))
+	require.NoError(t, coll.Add(newInstance("1.2.3.2.10", "1.2.3.2", "1.2.3", 2, "1")))
+	require.NoError(t, coll.Add(newInstance("1.2.4.1.1", "1.2.4.1", "1.2.4", 1, "1")))
+
+	columns := []tag.Tag{
+		tag.SOPInstanceUID, tag.StudyDate, tag.SeriesDescription, tag.ImageType,
+		tag.Rows, tag.PixelData, tag.Modality, tag.New(0x0009, 0x0010),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, dicom.ExportCSV(&buf, coll, columns))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+
+	assert.Equal(t, []string{
+		"SOPInstanceUID", "StudyDate", "SeriesDescription", "ImageType",
+		"Rows", "PixelData", "Modality", "(0009,0010)",
+	}, records[0])
+	assert.Equal(t, []string{
+		"1.2.3.1.1", "20240115", "AX, \"thin\"", `ORIGINAL\PRIMARY\AXIAL`, "512", "", "", "",
+	}, records[1])
+
+	var order []string
+	for _, record := range records[1:] {
+		order = append(order, record[0])
+	}
+	assert.Equal(t, []string{"1.2.3.1.1", "1.2.3.2.10", "1.2.3.2.2", "1.2.4.1.1"}, order,
+		"rows are ordered by study, series number and instance number")
+}
+
+func TestExportCSV_Preset(t *testing.T) {
+	columns, ok := dicom.CSVColumnPreset("study-summary")
+	require.True(t, ok)
+	assert.Contains(t, columns, tag.PatientID)
+	assert.Contains(t, columns, tag.StudyDate)
+	assert.Contains(t, columns, tag.Modality)
+	assert.Contains(t, columns, tag.SeriesDescription)
+
+	// The preset is a copy
+	columns[0] = tag.PixelData
+	again, _ := dicom.CSVColumnPreset("study-summary")
+	assert.NotEqual(t, tag.PixelData, again[0])
+
+	for _, name := range []string{"series-summary", "instance-qa"} {
+		_, ok := dicom.CSVColumnPreset(name)
+		assert.True(t, ok, name)
+	}
+	_, ok = dicom.CSVColumnPreset("unknown")
+	assert.False(t, ok)
+
+	coll := dicom.NewDataSetCollection()
+	require.NoError(t, coll.Add(createTestDataSetForCollection(
+		"1.2.3.1.1", "1.2.3.1", "1.2.3", "PAT001", "ACC42", "1.2.840.10008.5.1.4.1.1.2", 1)))
+	var buf bytes.Buffer
+	require.NoError(t, dicom.ExportCSV(&buf, coll, again))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "PAT001", records[1][0])
+}
+
+func TestExportCSV_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, dicom.ExportCSV(&buf, nil, []tag.Tag{tag.PatientID}))
+	assert.Error(t, dicom.ExportCSV(&buf, dicom.NewDataSetCollection(), nil))
+}
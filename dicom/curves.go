@@ -0,0 +1,231 @@
+package dicom
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// Curve holds one retired Curve (groups 50xx) of a dataset, as found in legacy ECG,
+// hemodynamic and nuclear medicine files.
+//
+// Curve Data is decoded into Data as float64 values whatever its stored
+// representation. Points are interleaved: the coordinates of point i are
+// Data[i*Dimensions : (i+1)*Dimensions]. Use Axis to get the values of one dimension.
+//
+// DICOM Standard Reference (retired, see the 2004 edition):
+// https://dicom.nema.org/medical/dicom/2004/04_03PU.PDF (Section C.10.2)
+type Curve struct {
+	// Group is the repeating group holding the curve, 0x5000 to 0x501E.
+	Group uint16
+
+	Dimensions     int    // Curve Dimensions (50xx,0005)
+	NumberOfPoints int    // Number of Points (50xx,0010)
+	TypeOfData     string // Type of Data (50xx,0020), e.g. "ECG" or "PRESSURE"
+	Description    string // Curve Description (50xx,0022)
+	Label          string // Curve Label (50xx,2500)
+
+	AxisUnits  []string // Axis Units (50xx,0030), one per dimension
+	AxisLabels []string // Axis Labels (50xx,0040), one per dimension
+
+	// DataValueRepresentation is Data Value Representation (50xx,0103):
+	// 0 = US, 1 = SS, 2 = FL, 3 = FD, 4 = SL.
+	DataValueRepresentation int
+
+	Data []float64 // Curve Data (50xx,3000), Dimensions values per point
+}
+
+// Axis returns the coordinates of dimension dim (0-based) for every point, or nil if
+// dim is out of range.
+func (c *Curve) Axis(dim int) []float64 {
+	if dim < 0 || dim >= c.Dimensions {
+		return nil
+	}
+	axis := make([]float64, 0, c.NumberOfPoints)
+	for i := dim; i < len(c.Data); i += c.Dimensions {
+		axis = append(axis, c.Data[i])
+	}
+	return axis
+}
+
+// HasCurveData reports whether ds contains retired Curve Data (50xx,3000) in any of
+// the curve repeating groups.
+func HasCurveData(ds *DataSet) bool {
+	return len(curveGroups(ds)) > 0
+}
+
+// ExtractCurves returns the retired curves of ds, one per 50xx group holding Curve
+// Data (50xx,3000), ordered by group.
+//
+// Curve Dimensions, Number of Points and Data Value Representation are required for
+// each curve. Attributes of groups other than 5000 are usually read as UN from
+// implicit VR files; their values are decoded as little endian.
+//
+// Returns an error wrapping ErrMissingRequiredAttribute if a required attribute is
+// missing, or an error if Curve Data is shorter than declared or uses an unknown
+// representation.
+//
+// Example:
+//
+//	curves, err := dicom.ExtractCurves(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, c := range curves {
+//	    fmt.Printf("%s: %d points\n", c.TypeOfData, c.NumberOfPoints)
+//	    times, values := c.Axis(0), c.Axis(1)
+//	    ...
+//	}
+//
+// DICOM Standard Reference (retired, see the 2004 edition):
+// https://dicom.nema.org/medical/dicom/2004/04_03PU.PDF (Section C.10.2)
+func ExtractCurves(ds *DataSet) ([]Curve, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	groups := curveGroups(ds)
+	curves := make([]Curve, 0, len(groups))
+	for _, group := range groups {
+		curve, err := extractCurve(ds, group)
+		if err != nil {
+			return nil, fmt.Errorf("curve group %04X: %w", group, err)
+		}
+		curves = append(curves, curve)
+	}
+	return curves, nil
+}
+
+// curveGroups returns the curve groups of ds holding Curve Data, in order.
+func curveGroups(ds *DataSet) []uint16 {
+	if ds == nil {
+		return nil
+	}
+	var groups []uint16
+	for _, t := range ds.Tags() {
+		if t.Element == tag.CurveData.Element && t.Group >= 0x5000 && t.Group <= 0x501E && t.Group%2 == 0 {
+			groups = append(groups, t.Group)
+		}
+	}
+	return groups
+}
+
+// extractCurve reads the curve in group.
+func extractCurve(ds *DataSet, group uint16) (Curve, error) {
+	curve := Curve{Group: group}
+	at := func(t tag.Tag) tag.Tag { return tag.New(group, t.Element) }
+
+	for _, attr := range []struct {
+		tag tag.Tag
+		dst *int
+	}{
+		{tag.CurveDimensions, &curve.Dimensions},
+		{tag.NumberOfPoints, &curve.NumberOfPoints},
+		{tag.DataValueRepresentation, &curve.DataValueRepresentation},
+	} {
+		n, err := curveUint16(ds, at(attr.tag))
+		if err != nil {
+			return curve, err
+		}
+		*attr.dst = n
+	}
+	if curve.Dimensions < 1 {
+		return curve, fmt.Errorf("invalid Curve Dimensions %d", curve.Dimensions)
+	}
+
+	curve.TypeOfData = curveString(ds, at(tag.TypeOfData))
+	curve.Description = curveString(ds, at(tag.CurveDescription))
+	curve.Label = curveString(ds, at(tag.CurveLabel))
+	if s := curveString(ds, at(tag.AxisUnits)); s != "" {
+		curve.AxisUnits = strings.Split(s, "\\")
+	}
+	if s := curveString(ds, at(tag.AxisLabels)); s != "" {
+		curve.AxisLabels = strings.Split(s, "\\")
+	}
+
+	elem, err := ds.Get(at(tag.CurveData))
+	if err != nil {
+		return curve, err
+	}
+	data, err := decodeCurveData(elem.Value().Bytes(), curve.DataValueRepresentation, curve.Dimensions*curve.NumberOfPoints)
+	if err != nil {
+		return curve, err
+	}
+	curve.Data = data
+	return curve, nil
+}
+
+// curveUint16 reads a single US value, decoding it from the raw bytes of UN values.
+func curveUint16(ds *DataSet, t tag.Tag) (int, error) {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s %s", ErrMissingRequiredAttribute, tagKeyword(tag.New(0x5000, t.Element)), t)
+	}
+	switch v := elem.Value().(type) {
+	case *value.IntValue:
+		if ints := v.Ints(); len(ints) > 0 {
+			return int(ints[0]), nil
+		}
+	case *value.BytesValue:
+		if b := v.Bytes(); len(b) >= 2 {
+			return int(binary.LittleEndian.Uint16(b)), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s %s is empty", ErrMissingRequiredAttribute, tagKeyword(tag.New(0x5000, t.Element)), t)
+}
+
+// curveString returns the trimmed string value of t, decoding UN values as text, or
+// "" if it is absent.
+func curveString(ds *DataSet, t tag.Tag) string {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return ""
+	}
+	s := elem.Value().String()
+	if b, ok := elem.Value().(*value.BytesValue); ok {
+		s = string(b.Bytes())
+	}
+	return strings.TrimRight(strings.TrimSpace(s), "\x00")
+}
+
+// decodeCurveData decodes n little endian values of the given Data Value
+// Representation from data.
+func decodeCurveData(data []byte, representation, n int) ([]float64, error) {
+	var size int
+	switch representation {
+	case 0, 1: // US, SS
+		size = 2
+	case 2, 4: // FL, SL
+		size = 4
+	case 3: // FD
+		size = 8
+	default:
+		return nil, fmt.Errorf("unknown Data Value Representation %d", representation)
+	}
+	if len(data) < n*size {
+		return nil, fmt.Errorf("curve data has %d bytes, %d values of %d bytes need %d",
+			len(data), n, size, n*size)
+	}
+
+	values := make([]float64, n)
+	for i := range values {
+		b := data[i*size:]
+		switch representation {
+		case 0:
+			values[i] = float64(binary.LittleEndian.Uint16(b))
+		case 1:
+			values[i] = float64(int16(binary.LittleEndian.Uint16(b)))
+		case 2:
+			values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+		case 3:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(b))
+		case 4:
+			values[i] = float64(int32(binary.LittleEndian.Uint32(b)))
+		}
+	}
+	return values, nil
+}
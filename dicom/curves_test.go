@@ -0,0 +1,133 @@
+package dicom_test
+
+import (
+	"encoding/binary"
+	"math"
+	"path/filepath"
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addCurveUint16 adds a US attribute of a curve group.
+func addCurveUint16(t *testing.T, ds *dicom.DataSet, tg tag.Tag, n int64) {
+	t.Helper()
+	val, err := value.NewIntValue(vr.UnsignedShort, []int64{n})
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(mustNewElement(tg, vr.UnsignedShort, val)))
+}
+
+// addCurveBytes adds a binary attribute of a curve group.
+func addCurveBytes(t *testing.T, ds *dicom.DataSet, tg tag.Tag, v vr.VR, data []byte) {
+	t.Helper()
+	val, err := value.NewBytesValue(v, data)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(mustNewElement(tg, v, val)))
+}
+
+// newCurveDataSet returns a dataset with a 2D SS pressure curve in group 5000 and a
+// 1D FD curve in group 5002 whose attributes are UN, as read from implicit VR files.
+func newCurveDataSet(t *testing.T) *dicom.DataSet {
+	ds := dicom.NewDataSet()
+
+	addCurveUint16(t, ds, tag.CurveDimensions, 2)
+	addCurveUint16(t, ds, tag.NumberOfPoints, 3)
+	addCurveUint16(t, ds, tag.DataValueRepresentation, 1) // SS
+	require.NoError(t, ds.Set(mustNewElement(tag.TypeOfData, vr.CodeString,
+		mustNewStringValue(vr.CodeString, []string{"PRESSURE"}))))
+	require.NoError(t, ds.Set(mustNewElement(tag.AxisUnits, vr.ShortString,
+		mustNewStringValue(vr.ShortString, []string{"MSEC", "MMHG"}))))
+	ss := make([]byte, 12)
+	for i, v := range []int16{0, 80, 10, 120, 20, -5} {
+		binary.LittleEndian.PutUint16(ss[i*2:], uint16(v))
+	}
+	addCurveBytes(t, ds, tag.CurveData, vr.OtherWord, ss)
+
+	un := func(n uint16) []byte { return binary.LittleEndian.AppendUint16(nil, n) }
+	addCurveBytes(t, ds, tag.New(0x5002, 0x0005), vr.Unknown, un(1))
+	addCurveBytes(t, ds, tag.New(0x5002, 0x0010), vr.Unknown, un(2))
+	addCurveBytes(t, ds, tag.New(0x5002, 0x0103), vr.Unknown, un(3)) // FD
+	addCurveBytes(t, ds, tag.New(0x5002, 0x0020), vr.Unknown, []byte("ECG "))
+	fd := binary.LittleEndian.AppendUint64(nil, math.Float64bits(1.5))
+	fd = binary.LittleEndian.AppendUint64(fd, math.Float64bits(-0.25))
+	addCurveBytes(t, ds, tag.New(0x5002, 0x3000), vr.Unknown, fd)
+
+	return ds
+}
+
+func TestExtractCurves(t *testing.T) {
+	ds := newCurveDataSet(t)
+	assert.True(t, dicom.HasCurveData(ds))
+
+	curves, err := dicom.ExtractCurves(ds)
+	require.NoError(t, err)
+	require.Len(t, curves, 2)
+
+	pressure := curves[0]
+	assert.Equal(t, uint16(0x5000), pressure.Group)
+	assert.Equal(t, 2, pressure.Dimensions)
+	assert.Equal(t, 3, pressure.NumberOfPoints)
+	assert.Equal(t, "PRESSURE", pressure.TypeOfData)
+	assert.Equal(t, []string{"MSEC", "MMHG"}, pressure.AxisUnits)
+	assert.Equal(t, []float64{0, 80, 10, 120, 20, -5}, pressure.Data)
+	assert.Equal(t, []float64{0, 10, 20}, pressure.Axis(0))
+	assert.Equal(t, []float64{80, 120, -5}, pressure.Axis(1))
+	assert.Nil(t, pressure.Axis(2))
+
+	ecg := curves[1]
+	assert.Equal(t, uint16(0x5002), ecg.Group)
+	assert.Equal(t, "ECG", ecg.TypeOfData)
+	assert.Equal(t, []float64{1.5, -0.25}, ecg.Data)
+
+	// Curves survive a write and read
+	path := filepath.Join(t.TempDir(), "curves.dcm")
+	written, err := newSecondaryCaptureBuilder().Build()
+	require.NoError(t, err)
+	for _, elem := range ds.Elements() {
+		require.NoError(t, written.Set(elem))
+	}
+	require.NoError(t, dicom.WriteFile(path, written))
+	parsed, err := dicom.ParseFile(path)
+	require.NoError(t, err)
+	reread, err := dicom.ExtractCurves(parsed)
+	require.NoError(t, err)
+	require.Len(t, reread, 2)
+	assert.Equal(t, pressure.Data, reread[0].Data)
+	assert.Equal(t, ecg.Data, reread[1].Data)
+}
+
+func TestExtractCurves_Errors(t *testing.T) {
+	assert.False(t, dicom.HasCurveData(dicom.NewDataSet()))
+	curves, err := dicom.ExtractCurves(dicom.NewDataSet())
+	require.NoError(t, err)
+	assert.Empty(t, curves)
+
+	t.Run("missing attribute", func(t *testing.T) {
+		ds := newCurveDataSet(t)
+		require.NoError(t, ds.Remove(tag.NumberOfPoints))
+		_, err := dicom.ExtractCurves(ds)
+		assert.ErrorIs(t, err, dicom.ErrMissingRequiredAttribute)
+	})
+
+	t.Run("truncated data", func(t *testing.T) {
+		ds := newCurveDataSet(t)
+		addCurveUint16(t, ds, tag.NumberOfPoints, 4)
+		_, err := dicom.ExtractCurves(ds)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown representation", func(t *testing.T) {
+		ds := newCurveDataSet(t)
+		addCurveUint16(t, ds, tag.DataValueRepresentation, 9)
+		_, err := dicom.ExtractCurves(ds)
+		assert.Error(t, err)
+	})
+
+	_, err = dicom.ExtractCurves(nil)
+	assert.Error(t, err)
+}
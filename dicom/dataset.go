@@ -14,6 +14,7 @@ import (
 
 	"github.com/codeninja55/go-radx/dicom/element"
 	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
 )
 
 // DataSet represents a collection of DICOM data elements.
@@ -90,9 +91,10 @@ func NewDataSetWithElements(elements []*element.Element) (*DataSet, error) {
 	return ds, nil
 }
 
-// Add inserts or replaces an element in the dataset.
+// Add inserts an element into the dataset.
 //
-// If an element with the same tag already exists, it will be replaced.
+// Returns an error wrapping ErrDuplicateTag if an element with the same tag is already
+// present; use Set to replace an existing element, or Update for read-modify-write.
 // Returns an error if the element is nil.
 //
 // Example:
@@ -105,11 +107,70 @@ func (ds *DataSet) Add(elem *element.Element) error {
 	if elem == nil {
 		return fmt.Errorf("cannot add nil element")
 	}
+	if _, exists := ds.elements[elem.Tag()]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateTag, elem.Tag())
+	}
 
 	ds.elements[elem.Tag()] = elem
 	return nil
 }
 
+// Set inserts an element into the dataset, replacing any element with the same tag.
+//
+// The replacement is taken as given, including its VR. Returns an error if the element
+// is nil.
+//
+// Example:
+//
+//	elem, _ := element.NewElement(tag.PatientName, vr.PersonName, value)
+//	if err := ds.Set(elem); err != nil {
+//	    log.Fatal(err)
+//	}
+func (ds *DataSet) Set(elem *element.Element) error {
+	if elem == nil {
+		return fmt.Errorf("cannot set nil element")
+	}
+
+	ds.elements[elem.Tag()] = elem
+	return nil
+}
+
+// Update replaces the value of an existing element with the result of fn.
+//
+// fn receives the current value and returns the new one. The element keeps its tag
+// and VR, and the new value must be valid for that VR. If fn returns an error the
+// dataset is left unchanged and the error is returned.
+//
+// Returns an error if the tag is not present in the dataset.
+//
+// Example:
+//
+//	err := ds.Update(tag.PatientName, func(old value.Value) (value.Value, error) {
+//	    name := strings.ToUpper(old.String())
+//	    return value.NewStringValue(vr.PersonName, []string{name})
+//	})
+func (ds *DataSet) Update(t tag.Tag, fn func(old value.Value) (value.Value, error)) error {
+	if fn == nil {
+		return fmt.Errorf("update function is nil")
+	}
+	elem, exists := ds.elements[t]
+	if !exists {
+		return fmt.Errorf("element with tag %s not found", t)
+	}
+
+	newVal, err := fn(elem.Value())
+	if err != nil {
+		return fmt.Errorf("update %s: %w", t, err)
+	}
+
+	updated, err := element.NewElement(t, elem.VR(), newVal)
+	if err != nil {
+		return fmt.Errorf("update %s: %w", t, err)
+	}
+	ds.elements[t] = updated
+	return nil
+}
+
 // Get retrieves an element by its DICOM tag.
 //
 // Returns an error if the tag is not found in the dataset.
@@ -165,6 +226,48 @@ func (ds *DataSet) Contains(t tag.Tag) bool {
 	return exists
 }
 
+// IsEmpty reports whether the element with the given tag is present in the dataset
+// and, if so, whether it has no value.
+//
+// DICOM distinguishes an absent attribute from one that is present with zero
+// length: a Type 2 attribute must be present but may be empty when its value is
+// unknown. An element is empty if its value is zero length, has only empty string
+// values (ignoring space and NUL padding), or is a sequence with no items.
+//
+// Example:
+//
+//	present, empty := ds.IsEmpty(tag.PatientName)
+//	switch {
+//	case !present:
+//	    fmt.Println("PatientName is absent")
+//	case empty:
+//	    fmt.Println("PatientName is present but unknown")
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.4
+func (ds *DataSet) IsEmpty(t tag.Tag) (present bool, empty bool) {
+	elem, exists := ds.elements[t]
+	if !exists {
+		return false, false
+	}
+	return true, isEmptyValue(elem.Value())
+}
+
+// isEmptyValue reports whether v encodes no value.
+func isEmptyValue(v value.Value) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case *value.SequenceValue:
+		return v.Len() == 0
+	case *value.StringValue:
+		return strings.Trim(v.String(), " \x00\\") == ""
+	default:
+		return len(v.Bytes()) == 0
+	}
+}
+
 // Remove removes an element from the dataset by its tag.
 //
 // Returns an error if the tag is not found.
@@ -192,7 +295,7 @@ func (ds *DataSet) Len() int {
 	return len(ds.elements)
 }
 
-// Elements returns all elements in the dataset sorted by tag.
+// Elements returns all elements in the dataset sorted by (group, element).
 //
 // The returned slice is a copy and can be safely modified without affecting
 // the dataset.
@@ -218,7 +321,7 @@ func (ds *DataSet) Elements() []*element.Element {
 	return elements
 }
 
-// Tags returns all tags in the dataset sorted in ascending order.
+// Tags returns all tags in the dataset sorted by (group, element).
 //
 // The returned slice is a copy and can be safely modified without affecting
 // the dataset.
@@ -287,6 +390,64 @@ func (ds *DataSet) String() string {
 	return sb.String()
 }
 
+// Equals returns true if this dataset equals another dataset.
+//
+// Datasets are equal if they contain the same tags and each pair of elements is
+// equal (see element.Equals). Nested sequence items are compared recursively.
+// This makes *DataSet usable as a value.Item in sequences.
+func (ds *DataSet) Equals(other value.Item) bool {
+	otherDS, ok := other.(*DataSet)
+	if !ok || otherDS == nil {
+		return false
+	}
+
+	if len(ds.elements) != len(otherDS.elements) {
+		return false
+	}
+
+	for t, elem := range ds.elements {
+		otherElem, exists := otherDS.elements[t]
+		if !exists || !elem.Equals(otherElem) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EqualsIgnoringPadding is like Equals but ignores differences in value padding, such
+// as a trailing space on a text value or a trailing NUL on a UI, at every nesting
+// level (see value.EqualsIgnoringPadding). Use it to diff or de-duplicate files that
+// were written by different implementations.
+//
+// Example:
+//
+//	if a.EqualsIgnoringPadding(b) {
+//	    fmt.Println("duplicate")
+//	}
+func (ds *DataSet) EqualsIgnoringPadding(other value.Item) bool {
+	otherDS, ok := other.(*DataSet)
+	if !ok || otherDS == nil {
+		return false
+	}
+
+	if len(ds.elements) != len(otherDS.elements) {
+		return false
+	}
+
+	for t, elem := range ds.elements {
+		otherElem, exists := otherDS.elements[t]
+		if !exists || !elem.EqualsIgnoringPadding(otherElem) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Verify DataSet implements value.Item at compile time
+var _ value.Item = (*DataSet)(nil)
+
 // Copy creates a deep copy of the dataset.
 //
 // The returned dataset is independent and modifications will not affect
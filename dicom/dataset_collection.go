@@ -2,6 +2,7 @@ package dicom
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 
@@ -11,7 +12,7 @@ import (
 // DataSetCollection represents a read-optimized collection of DICOM datasets with comprehensive indexing.
 //
 // This collection type is optimized for fast read operations with all indexes pre-built.
-// It maintains 7 indexes for O(1) lookups by:
+// It maintains 8 indexes for O(1) lookups by:
 //   - SOPInstanceUID (0008,0018) - Primary key, unique per instance
 //   - SeriesInstanceUID (0020,000E) - Groups instances into series
 //   - StudyInstanceUID (0020,000D) - Groups series into studies
@@ -19,6 +20,7 @@ import (
 //   - AccessionNumber (0008,0050) - Study identifier
 //   - SOPClassUID (0008,0016) - Type of DICOM object
 //   - SeriesNumber (0020,0011) - Ordered access within series
+//   - FrameOfReferenceUID (0020,0052) - Groups instances sharing a coordinate system
 //
 // Thread-safe for concurrent access.
 //
@@ -55,12 +57,25 @@ type DataSetCollection struct {
 	datasets map[string]*DataSet
 
 	// Secondary indexes for O(1) lookups
-	seriesInstanceIndex  map[string][]*DataSet // SeriesInstanceUID -> datasets
-	studyInstanceIndex   map[string][]*DataSet // StudyInstanceUID -> datasets
-	patientIDIndex       map[string][]*DataSet // PatientID -> datasets
-	accessionNumberIndex map[string][]*DataSet // AccessionNumber -> datasets
-	sopClassIndex        map[string][]*DataSet // SOPClassUID -> datasets
-	seriesNumberIndex    map[int][]*DataSet    // SeriesNumber -> datasets (ordered)
+	seriesInstanceIndex   map[string][]*DataSet // SeriesInstanceUID -> datasets
+	studyInstanceIndex    map[string][]*DataSet // StudyInstanceUID -> datasets
+	patientIDIndex        map[string][]*DataSet // PatientID -> datasets
+	accessionNumberIndex  map[string][]*DataSet // AccessionNumber -> datasets
+	sopClassIndex         map[string][]*DataSet // SOPClassUID -> datasets
+	seriesNumberIndex     map[int][]*DataSet    // SeriesNumber -> datasets (ordered)
+	frameOfReferenceIndex map[string][]*DataSet // FrameOfReferenceUID -> datasets
+
+	// Source file of each dataset, when known
+	filePaths map[string]string // SOPInstanceUID -> file path
+
+	// Description token index for SearchText, built on first use and discarded
+	// whenever datasets are added or removed
+	textIndexMu sync.Mutex
+	textIndex   *textIndex
+
+	// Datasets rejected by AddOrRecord as a different instance with an existing
+	// SOPInstanceUID, reported by ValidateUIDConsistency
+	instanceCollisions []instanceCollision
 }
 
 // NewDataSetCollection creates a new empty dataset collection.
@@ -71,13 +86,15 @@ type DataSetCollection struct {
 //	fmt.Println(coll.Len())  // Output: 0
 func NewDataSetCollection() *DataSetCollection {
 	return &DataSetCollection{
-		datasets:             make(map[string]*DataSet),
-		seriesInstanceIndex:  make(map[string][]*DataSet),
-		studyInstanceIndex:   make(map[string][]*DataSet),
-		patientIDIndex:       make(map[string][]*DataSet),
-		accessionNumberIndex: make(map[string][]*DataSet),
-		sopClassIndex:        make(map[string][]*DataSet),
-		seriesNumberIndex:    make(map[int][]*DataSet),
+		datasets:              make(map[string]*DataSet),
+		seriesInstanceIndex:   make(map[string][]*DataSet),
+		studyInstanceIndex:    make(map[string][]*DataSet),
+		patientIDIndex:        make(map[string][]*DataSet),
+		accessionNumberIndex:  make(map[string][]*DataSet),
+		sopClassIndex:         make(map[string][]*DataSet),
+		frameOfReferenceIndex: make(map[string][]*DataSet),
+		seriesNumberIndex:     make(map[int][]*DataSet),
+		filePaths:             make(map[string]string),
 	}
 }
 
@@ -109,7 +126,8 @@ func NewDataSetCollectionWithDataSets(datasets []*DataSet) (*DataSetCollection,
 // Returns an error if:
 //   - The dataset is nil
 //   - Required UIDs are missing (SOPInstanceUID, SeriesInstanceUID, StudyInstanceUID, PatientID, SOPClassUID)
-//   - A dataset with the same SOPInstanceUID already exists
+//   - A dataset with the same SOPInstanceUID already exists; nothing is recorded, use
+//     AddOrRecord to have a differing duplicate reported by ValidateUIDConsistency
 //
 // Example:
 //
@@ -117,6 +135,25 @@ func NewDataSetCollectionWithDataSets(datasets []*DataSet) (*DataSetCollection,
 //	    log.Printf("Failed to add dataset: %v", err)
 //	}
 func (c *DataSetCollection) Add(ds *DataSet) error {
+	return c.AddWithFilePath(ds, "")
+}
+
+// AddWithFilePath inserts a dataset read from path into the collection.
+//
+// It behaves like Add and additionally records the file the dataset came from, which
+// is available through FilePath and included by ExportManifest. An empty path records
+// nothing.
+//
+// Example:
+//
+//	ds, err := dicom.ParseFile(path)
+//	if err != nil {
+//	    return err
+//	}
+//	if err := coll.AddWithFilePath(ds, path); err != nil {
+//	    log.Printf("Failed to add dataset: %v", err)
+//	}
+func (c *DataSetCollection) AddWithFilePath(ds *DataSet, path string) error {
 	if ds == nil {
 		return fmt.Errorf("cannot add nil dataset")
 	}
@@ -169,10 +206,59 @@ func (c *DataSetCollection) Add(ds *DataSet) error {
 	c.accessionNumberIndex[accessionNumber] = append(c.accessionNumberIndex[accessionNumber], ds)
 	c.sopClassIndex[sopClassUID] = append(c.sopClassIndex[sopClassUID], ds)
 	c.seriesNumberIndex[seriesNumber] = append(c.seriesNumberIndex[seriesNumber], ds)
+	for _, forUID := range frameOfReferenceUIDs(ds) {
+		c.frameOfReferenceIndex[forUID] = append(c.frameOfReferenceIndex[forUID], ds)
+	}
+
+	if path != "" {
+		c.filePaths[sopInstanceUID] = path
+	}
+	c.textIndex = nil
 
 	return nil
 }
 
+// AddOrRecord inserts a dataset read from path into the collection like
+// AddWithFilePath, and remembers a rejected dataset whose SOPInstanceUID is already
+// held by a different instance, so that ValidateUIDConsistency reports the collision.
+//
+// The collection keeps the dataset added first. An identical copy is not a collision.
+// ParseDirectory and Apply add datasets this way. path may be empty.
+//
+// Returns the error of AddWithFilePath.
+//
+// Example:
+//
+//	for path, ds := range imported {
+//	    if err := coll.AddOrRecord(ds, path); err != nil {
+//	        log.Printf("Skipped %s: %v", path, err)
+//	    }
+//	}
+//	conflicts := coll.ValidateUIDConsistency()
+func (c *DataSetCollection) AddOrRecord(ds *DataSet, path string) error {
+	err := c.AddWithFilePath(ds, path)
+	if err != nil && ds != nil {
+		c.recordInstanceCollision(ds, path)
+	}
+	return err
+}
+
+// FilePath returns the file a dataset was read from, or "" if it is not known.
+//
+// Paths are recorded by AddWithFilePath and by ParseDirectory.
+//
+// Example:
+//
+//	if path := coll.FilePath(sopInstanceUID); path != "" {
+//	    fmt.Printf("Instance stored at %s\n", path)
+//	}
+func (c *DataSetCollection) FilePath(sopInstanceUID string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.filePaths[sopInstanceUID]
+}
+
 // GetBySOPInstanceUID retrieves a dataset by its SOPInstanceUID.
 //
 // Returns an error if the dataset is not found.
@@ -311,6 +397,43 @@ func (c *DataSetCollection) GetBySOPClassUID(uid string) []*DataSet {
 	return result
 }
 
+// GetByFrameOfReferenceUID retrieves all datasets in the coordinate system identified
+// by a Frame of Reference UID, for spatial registration and fusion across series.
+//
+// A dataset belongs to a frame of reference through its own Frame of Reference UID
+// (0020,0052) or, for objects defined on other images such as an RT Structure Set,
+// through the Frame of Reference UID of each Referenced Frame of Reference Sequence
+// (3006,0010) item. A CT series and the RT Structure Set drawn on it are therefore
+// returned together.
+//
+// Returns an empty slice if no datasets are found.
+//
+// Example:
+//
+//	elem, err := ct.Get(tag.FrameOfReferenceUID)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	related := coll.GetByFrameOfReferenceUID(elem.Value().String())
+//	fmt.Printf("%d instances share the CT coordinate system\n", len(related))
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.4.1
+func (c *DataSetCollection) GetByFrameOfReferenceUID(uid string) []*DataSet {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	datasets := c.frameOfReferenceIndex[uid]
+	if datasets == nil {
+		return []*DataSet{}
+	}
+
+	// Return a copy to prevent external modification
+	result := make([]*DataSet, len(datasets))
+	copy(result, datasets)
+	return result
+}
+
 // GetBySeriesNumber retrieves all datasets with the given series number.
 //
 // Returns an empty slice if no datasets are found.
@@ -393,6 +516,11 @@ func (c *DataSetCollection) Remove(sopInstanceUID string) error {
 
 	// Remove from primary storage
 	delete(c.datasets, sopInstanceUID)
+	delete(c.filePaths, sopInstanceUID)
+	c.textIndex = nil
+	c.instanceCollisions = slices.DeleteFunc(c.instanceCollisions, func(collision instanceCollision) bool {
+		return collision.sopInstanceUID == sopInstanceUID
+	})
 
 	// Remove from all indexes
 	c.seriesInstanceIndex[seriesInstanceUID] = c.removeFromSlice(c.seriesInstanceIndex[seriesInstanceUID], ds)
@@ -401,6 +529,9 @@ func (c *DataSetCollection) Remove(sopInstanceUID string) error {
 	c.accessionNumberIndex[accessionNumber] = c.removeFromSlice(c.accessionNumberIndex[accessionNumber], ds)
 	c.sopClassIndex[sopClassUID] = c.removeFromSlice(c.sopClassIndex[sopClassUID], ds)
 	c.seriesNumberIndex[seriesNumber] = c.removeFromSlice(c.seriesNumberIndex[seriesNumber], ds)
+	for _, forUID := range frameOfReferenceUIDs(ds) {
+		c.frameOfReferenceIndex[forUID] = c.removeFromSlice(c.frameOfReferenceIndex[forUID], ds)
+	}
 
 	return nil
 }
@@ -507,6 +638,26 @@ func (c *DataSetCollection) extractOptionalIntValue(ds *DataSet, t tag.Tag) (int
 	return intValue, nil
 }
 
+// frameOfReferenceUIDs returns the distinct, non-empty frames of reference of ds:
+// its Frame of Reference UID and those of its Referenced Frame of Reference Sequence.
+func frameOfReferenceUIDs(ds *DataSet) []string {
+	var uids []string
+	add := func(item *DataSet) {
+		uid := stringValue(item, tag.FrameOfReferenceUID)
+		if uid != "" && !slices.Contains(uids, uid) {
+			uids = append(uids, uid)
+		}
+	}
+
+	add(ds)
+	if items, err := ds.GetSequenceItems(tag.ReferencedFrameOfReferenceSequence); err == nil {
+		for _, item := range items {
+			add(item)
+		}
+	}
+	return uids
+}
+
 // removeFromSlice removes a dataset from a slice and returns the modified slice.
 func (c *DataSetCollection) removeFromSlice(slice []*DataSet, ds *DataSet) []*DataSet {
 	if slice == nil {
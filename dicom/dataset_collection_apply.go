@@ -0,0 +1,149 @@
+package dicom
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ApplyOptions configures DataSetCollection.ApplyWithOptions.
+type ApplyOptions struct {
+	// Workers specifies the number of datasets transformed concurrently.
+	// The transform must be safe for concurrent use when Workers > 1.
+	// Default: 1 (sequential)
+	Workers int
+}
+
+// ApplyError reports the datasets that could not be transformed or collected by
+// DataSetCollection.Apply.
+type ApplyError struct {
+	// Errors maps the SOPInstanceUID of each source dataset to its error.
+	Errors map[string]error
+}
+
+// Error lists the failed datasets in SOPInstanceUID order.
+func (e *ApplyError) Error() string {
+	uids := e.sortedUIDs()
+	parts := make([]string, len(uids))
+	for i, uid := range uids {
+		parts[i] = fmt.Sprintf("%s: %v", uid, e.Errors[uid])
+	}
+	return fmt.Sprintf("%d datasets failed: %s", len(uids), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the per-dataset errors, for use with errors.Is and errors.As.
+func (e *ApplyError) Unwrap() []error {
+	uids := e.sortedUIDs()
+	errs := make([]error, len(uids))
+	for i, uid := range uids {
+		errs[i] = e.Errors[uid]
+	}
+	return errs
+}
+
+// sortedUIDs returns the SOPInstanceUIDs of the failed datasets in order.
+func (e *ApplyError) sortedUIDs() []string {
+	uids := make([]string, 0, len(e.Errors))
+	for uid := range e.Errors {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+// Apply runs fn over every dataset of the collection, sequentially in SOPInstanceUID
+// order, and collects the datasets it returns into a new collection. It is
+// equivalent to ApplyWithOptions with default options.
+//
+// Example:
+//
+//	anonymized, err := coll.Apply(func(ds *dicom.DataSet) (*dicom.DataSet, error) {
+//	    return anonymizer.Anonymize(ds)
+//	})
+func (c *DataSetCollection) Apply(fn func(ds *DataSet) (*DataSet, error)) (*DataSetCollection, error) {
+	return c.ApplyWithOptions(fn, ApplyOptions{})
+}
+
+// ApplyWithOptions runs fn over every dataset of the collection, optionally on
+// several workers, and collects the datasets it returns into a new collection.
+//
+// fn may modify and return its argument or return a new dataset; returning a nil
+// dataset without an error drops the dataset from the result. The source collection
+// is not changed, but datasets modified in place by fn are shared with it. Source
+// file paths are not carried over, since the transformed datasets no longer match
+// their files.
+//
+// A dataset fails if fn returns an error or the result cannot be added to the new
+// collection, for example because it lacks a required UID or duplicates another
+// result's SOPInstanceUID. The other datasets are still processed: the returned
+// collection holds every successful result and the error is an *ApplyError keyed by
+// the SOPInstanceUID of each failed source dataset. Results that collide with an
+// earlier result's SOPInstanceUID but differ from it are also reported by the new
+// collection's ValidateUIDConsistency.
+//
+// Example:
+//
+//	transcoded, err := coll.ApplyWithOptions(func(ds *dicom.DataSet) (*dicom.DataSet, error) {
+//	    return ds, transcode(ds)
+//	}, dicom.ApplyOptions{Workers: 8})
+//	var applyErr *dicom.ApplyError
+//	if errors.As(err, &applyErr) {
+//	    for uid, err := range applyErr.Errors {
+//	        log.Printf("%s: %v", uid, err)
+//	    }
+//	}
+func (c *DataSetCollection) ApplyWithOptions(fn func(ds *DataSet) (*DataSet, error), opts ApplyOptions) (*DataSetCollection, error) {
+	if fn == nil {
+		return nil, fmt.Errorf("apply function is nil")
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+
+	// Source UIDs are read before fn runs, since fn may change them
+	datasets := c.DataSets()
+	sourceUIDs := make([]string, len(datasets))
+	for i, ds := range datasets {
+		sourceUIDs[i] = extractSOPInstanceUID(ds)
+	}
+
+	results := make([]*DataSet, len(datasets))
+	errs := make([]error, len(datasets))
+
+	jobs := make(chan int, len(datasets))
+	for i := range datasets {
+		jobs <- i
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(opts.Workers, len(datasets)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = fn(datasets[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Collect in source order so that duplicate results fail deterministically
+	applied := NewDataSetCollection()
+	failed := make(map[string]error)
+	for i, result := range results {
+		err := errs[i]
+		if err == nil && result != nil {
+			err = applied.AddOrRecord(result, "")
+		}
+		if err != nil {
+			failed[sourceUIDs[i]] = err
+		}
+	}
+
+	if len(failed) > 0 {
+		return applied, &ApplyError{Errors: failed}
+	}
+	return applied, nil
+}
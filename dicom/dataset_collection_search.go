@@ -0,0 +1,149 @@
+package dicom
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// searchTextTags are the free-text attributes matched by SearchText.
+var searchTextTags = []tag.Tag{
+	tag.StudyDescription,
+	tag.SeriesDescription,
+	tag.ProtocolName,
+}
+
+// textIndex is an inverted index from the lower-cased words of the searchable
+// descriptions to the datasets containing them.
+type textIndex struct {
+	texts  map[string][]string // SOPInstanceUID -> lower-cased descriptions
+	tokens map[string][]string // word -> SOPInstanceUIDs
+}
+
+// SearchText returns the datasets whose Study Description (0008,1030), Series
+// Description (0008,103E) or Protocol Name (0018,1030) contains query, ignoring case.
+//
+// Each instance is returned once, in SOPInstanceUID order. A query that is empty or
+// only whitespace matches nothing.
+//
+// The descriptions are indexed by word the first time the collection is searched,
+// and again after datasets are added or removed, so that later searches only compare
+// the query against the descriptions sharing its words. Changes made to a dataset's
+// descriptions after it was indexed are not seen until the index is rebuilt.
+//
+// Example:
+//
+//	for _, ds := range coll.SearchText("head ct") {
+//	    fmt.Println(ds.String())
+//	}
+func (c *DataSetCollection) SearchText(query string) []*DataSet {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return []*DataSet{}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	index := c.searchIndex()
+	candidates := index.candidates(query)
+
+	result := []*DataSet{}
+	for _, sopInstanceUID := range candidates {
+		for _, text := range index.texts[sopInstanceUID] {
+			if strings.Contains(text, query) {
+				result = append(result, c.datasets[sopInstanceUID])
+				break
+			}
+		}
+	}
+	return result
+}
+
+// searchIndex returns the text index, building it if needed. The caller must hold
+// at least the read lock.
+func (c *DataSetCollection) searchIndex() *textIndex {
+	c.textIndexMu.Lock()
+	defer c.textIndexMu.Unlock()
+
+	if c.textIndex != nil {
+		return c.textIndex
+	}
+
+	index := &textIndex{
+		texts:  make(map[string][]string, len(c.datasets)),
+		tokens: make(map[string][]string),
+	}
+	for sopInstanceUID, ds := range c.datasets {
+		var texts []string
+		seen := make(map[string]bool)
+		for _, t := range searchTextTags {
+			text := strings.ToLower(stringValue(ds, t))
+			if text == "" {
+				continue
+			}
+			texts = append(texts, text)
+			for _, token := range textTokens(text) {
+				if !seen[token] {
+					seen[token] = true
+					index.tokens[token] = append(index.tokens[token], sopInstanceUID)
+				}
+			}
+		}
+		if len(texts) > 0 {
+			index.texts[sopInstanceUID] = texts
+		}
+	}
+
+	c.textIndex = index
+	return index
+}
+
+// candidates returns, in sorted order, the instances that may contain query: for
+// every word of query, they have an indexed word containing it. A query matching
+// across the end of a word, such as "d c" in "head ct", still has each of its
+// words inside a description word.
+func (x *textIndex) candidates(query string) []string {
+	var result map[string]bool
+	for _, queryToken := range textTokens(query) {
+		matches := make(map[string]bool)
+		for token, sopInstanceUIDs := range x.tokens {
+			if !strings.Contains(token, queryToken) {
+				continue
+			}
+			for _, sopInstanceUID := range sopInstanceUIDs {
+				if result == nil || result[sopInstanceUID] {
+					matches[sopInstanceUID] = true
+				}
+			}
+		}
+		result = matches
+		if len(result) == 0 {
+			return nil
+		}
+	}
+
+	// A query of only punctuation has no words to narrow the search by
+	if result == nil {
+		result = make(map[string]bool, len(x.texts))
+		for sopInstanceUID := range x.texts {
+			result[sopInstanceUID] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(result))
+	for sopInstanceUID := range result {
+		sorted = append(sorted, sopInstanceUID)
+	}
+	slices.Sort(sorted)
+	return sorted
+}
+
+// textTokens splits lower-cased text into its words of letters and digits.
+func textTokens(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -1,7 +1,9 @@
 package dicom_test
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
 	"sync"
 	"testing"
 
@@ -333,6 +335,62 @@ func TestDataSetCollection_GetBySOPClassUID(t *testing.T) {
 	})
 }
 
+// TestDataSetCollection_GetByFrameOfReferenceUID tests retrieving by FrameOfReferenceUID
+func TestDataSetCollection_GetByFrameOfReferenceUID(t *testing.T) {
+	const (
+		ctImage     = "1.2.840.10008.5.1.4.1.1.2"
+		rtStruct    = "1.2.840.10008.5.1.4.1.1.481.3"
+		planningFoR = "1.2.826.0.1.3680043.10.1451.9"
+	)
+	withFoR := func(ds *dicom.DataSet, uid string) *dicom.DataSet {
+		require.NoError(t, ds.Add(mustNewElement(tag.FrameOfReferenceUID, vr.UniqueIdentifier,
+			mustNewStringValue(vr.UniqueIdentifier, []string{uid}))))
+		return ds
+	}
+
+	t.Run("images and RT structure set sharing a frame of reference", func(t *testing.T) {
+		coll := dicom.NewDataSetCollection()
+
+		ct1 := withFoR(createTestDataSetForCollection("1.2.3.1", "1.2.3.100", "1.2.3.1000", "P001", "A001", ctImage, 1), planningFoR)
+		ct2 := withFoR(createTestDataSetForCollection("1.2.3.2", "1.2.3.100", "1.2.3.1000", "P001", "A001", ctImage, 1), planningFoR)
+		other := withFoR(createTestDataSetForCollection("1.2.3.3", "1.2.3.200", "1.2.3.1000", "P001", "A001", ctImage, 2), "1.2.3.999")
+		noFoR := createTestDataSetForCollection("1.2.3.4", "1.2.3.300", "1.2.3.1000", "P001", "A001", ctImage, 3)
+
+		// RT Structure Set referencing the planning CT's frame of reference
+		structSet := createTestDataSetForCollection("1.2.3.5", "1.2.3.400", "1.2.3.1000", "P001", "A001", rtStruct, 4)
+		refItem := withFoR(dicom.NewDataSet(), planningFoR)
+		seq, err := dicom.NewSequenceElement(tag.ReferencedFrameOfReferenceSequence, []*dicom.DataSet{refItem})
+		require.NoError(t, err)
+		require.NoError(t, structSet.Add(seq))
+
+		for _, ds := range []*dicom.DataSet{ct1, ct2, other, noFoR, structSet} {
+			require.NoError(t, coll.Add(ds))
+		}
+
+		datasets := coll.GetByFrameOfReferenceUID(planningFoR)
+		assert.ElementsMatch(t, []*dicom.DataSet{ct1, ct2, structSet}, datasets)
+		assert.Len(t, coll.GetByFrameOfReferenceUID("1.2.3.999"), 1)
+		assert.Empty(t, coll.GetByFrameOfReferenceUID(""), "datasets without a frame of reference are not indexed")
+		assert.Empty(t, coll.GetByFrameOfReferenceUID("1.2.3.404"))
+
+		// Removal updates the index
+		require.NoError(t, coll.Remove("1.2.3.5"))
+		assert.ElementsMatch(t, []*dicom.DataSet{ct1, ct2}, coll.GetByFrameOfReferenceUID(planningFoR))
+	})
+
+	t.Run("frame of reference listed twice is indexed once", func(t *testing.T) {
+		coll := dicom.NewDataSetCollection()
+		ds := withFoR(createTestDataSetForCollection("1.2.3.1", "1.2.3.100", "1.2.3.1000", "P001", "A001", rtStruct, 1), planningFoR)
+		seq, err := dicom.NewSequenceElement(tag.ReferencedFrameOfReferenceSequence,
+			[]*dicom.DataSet{withFoR(dicom.NewDataSet(), planningFoR)})
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(seq))
+		require.NoError(t, coll.Add(ds))
+
+		assert.Len(t, coll.GetByFrameOfReferenceUID(planningFoR), 1)
+	})
+}
+
 // TestDataSetCollection_GetBySeriesNumber tests retrieving by SeriesNumber
 func TestDataSetCollection_GetBySeriesNumber(t *testing.T) {
 	t.Run("get datasets by series number", func(t *testing.T) {
@@ -612,3 +670,260 @@ func TestDataSetCollection_ThreadSafety(t *testing.T) {
 		assert.GreaterOrEqual(t, coll.Len(), 50) // At least initial datasets
 	})
 }
+
+// TestDataSetCollection_FilePath tests recording and removing source file paths
+func TestDataSetCollection_FilePath(t *testing.T) {
+	coll := dicom.NewDataSetCollection()
+	ds := createTestDataSetForCollection("1.2.3.1", "1.2.3", "1.2", "PAT001", "", "1.2.840.10008.5.1.4.1.1.2", 1)
+
+	require.NoError(t, coll.AddWithFilePath(ds, "/data/1.dcm"))
+	assert.Equal(t, "/data/1.dcm", coll.FilePath("1.2.3.1"))
+	assert.Empty(t, coll.FilePath("9.9.9"))
+
+	// Duplicates are rejected without changing the recorded path
+	assert.Error(t, coll.AddWithFilePath(ds, "/data/other.dcm"))
+	assert.Equal(t, "/data/1.dcm", coll.FilePath("1.2.3.1"))
+
+	require.NoError(t, coll.Remove("1.2.3.1"))
+	assert.Empty(t, coll.FilePath("1.2.3.1"))
+}
+
+func TestDataSetCollection_SearchText(t *testing.T) {
+	addText := func(ds *dicom.DataSet, tg tag.Tag, text string) *dicom.DataSet {
+		require.NoError(t, ds.Add(mustNewElement(tg, vr.LongString, mustNewStringValue(vr.LongString, []string{text}))))
+		return ds
+	}
+
+	headCT := createTestDataSetForCollection("1.1", "1.2", "1.3", "P1", "", "1.2.840.10008.5.1.4.1.1.2", 1)
+	addText(headCT, tag.StudyDescription, "CT HEAD W/O CONTRAST")
+	addText(headCT, tag.SeriesDescription, "Head 5mm")
+	headMR := createTestDataSetForCollection("2.1", "2.2", "2.3", "P2", "", "1.2.840.10008.5.1.4.1.1.4", 1)
+	addText(headMR, tag.ProtocolName, "t1_mprage_head")
+	chest := createTestDataSetForCollection("3.1", "3.2", "3.3", "P3", "", "1.2.840.10008.5.1.4.1.1.2", 1)
+	addText(chest, tag.SeriesDescription, "Chest PA")
+	plain := createTestDataSetForCollection("4.1", "4.2", "4.3", "P4", "", "1.2.840.10008.5.1.4.1.1.2", 1)
+
+	coll, err := dicom.NewDataSetCollectionWithDataSets([]*dicom.DataSet{chest, headMR, headCT, plain})
+	require.NoError(t, err)
+
+	tests := []struct {
+		query string
+		want  []*dicom.DataSet
+	}{
+		{query: "head", want: []*dicom.DataSet{headCT, headMR}},
+		{query: "  HEAD  ", want: []*dicom.DataSet{headCT, headMR}},
+		{query: "ct head", want: []*dicom.DataSet{headCT}},
+		{query: "t h", want: []*dicom.DataSet{headCT}},
+		{query: "mprage", want: []*dicom.DataSet{headMR}},
+		{query: "w/o", want: []*dicom.DataSet{headCT}},
+		{query: "/", want: []*dicom.DataSet{headCT}},
+		{query: "est p", want: []*dicom.DataSet{chest}},
+		{query: "head chest", want: []*dicom.DataSet{}},
+		{query: "abdomen", want: []*dicom.DataSet{}},
+		{query: "", want: []*dicom.DataSet{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.want, coll.SearchText(tt.query))
+		})
+	}
+
+	t.Run("index follows additions and removals", func(t *testing.T) {
+		headXR := createTestDataSetForCollection("5.1", "5.2", "5.3", "P5", "", "1.2.840.10008.5.1.4.1.1.1", 1)
+		addText(headXR, tag.StudyDescription, "XR Head")
+		require.NoError(t, coll.Add(headXR))
+		assert.Equal(t, []*dicom.DataSet{headCT, headMR, headXR}, coll.SearchText("head"))
+
+		require.NoError(t, coll.Remove("2.1"))
+		assert.Equal(t, []*dicom.DataSet{headCT, headXR}, coll.SearchText("head"))
+	})
+}
+
+func TestDataSetCollection_Apply(t *testing.T) {
+	newCollection := func(t *testing.T) *dicom.DataSetCollection {
+		var datasets []*dicom.DataSet
+		for i := 1; i <= 6; i++ {
+			datasets = append(datasets, createTestDataSetForCollection(
+				fmt.Sprintf("1.2.%d", i), "1.2.100", "1.2.200", "P1", "", "1.2.840.10008.5.1.4.1.1.2", i))
+		}
+		coll, err := dicom.NewDataSetCollectionWithDataSets(datasets)
+		require.NoError(t, err)
+		return coll
+	}
+
+	// renumber copies ds with a new SOPInstanceUID.
+	renumber := func(ds *dicom.DataSet) (*dicom.DataSet, error) {
+		out := ds.Copy()
+		uid := stringOf(t, ds, tag.SOPInstanceUID) + ".9"
+		return out, out.Set(mustNewElement(tag.SOPInstanceUID, vr.UniqueIdentifier,
+			mustNewStringValue(vr.UniqueIdentifier, []string{uid})))
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		coll := newCollection(t)
+		applied, err := coll.Apply(renumber)
+		require.NoError(t, err)
+		assert.Equal(t, 6, applied.Len())
+		assert.True(t, applied.Contains("1.2.3.9"))
+		assert.True(t, coll.Contains("1.2.3"), "the source collection is unchanged")
+		assert.False(t, coll.Contains("1.2.3.9"))
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		applied, err := newCollection(t).ApplyWithOptions(renumber, dicom.ApplyOptions{Workers: 4})
+		require.NoError(t, err)
+		assert.Equal(t, 6, applied.Len())
+		for i := 1; i <= 6; i++ {
+			assert.True(t, applied.Contains(fmt.Sprintf("1.2.%d.9", i)))
+		}
+	})
+
+	t.Run("errors are aggregated", func(t *testing.T) {
+		errOdd := errors.New("odd series")
+		applied, err := newCollection(t).ApplyWithOptions(func(ds *dicom.DataSet) (*dicom.DataSet, error) {
+			switch stringOf(t, ds, tag.SOPInstanceUID) {
+			case "1.2.1", "1.2.3":
+				return nil, errOdd
+			case "1.2.5":
+				return nil, nil // dropped
+			case "1.2.6":
+				return dicom.NewDataSet(), nil // cannot be collected
+			}
+			return ds, nil
+		}, dicom.ApplyOptions{Workers: 3})
+
+		var applyErr *dicom.ApplyError
+		require.ErrorAs(t, err, &applyErr)
+		assert.ErrorIs(t, err, errOdd)
+		assert.Len(t, applyErr.Errors, 3)
+		assert.Contains(t, applyErr.Errors, "1.2.6")
+		assert.Contains(t, err.Error(), "3 datasets failed")
+
+		assert.Equal(t, 2, applied.Len())
+		assert.True(t, applied.Contains("1.2.2"))
+		assert.True(t, applied.Contains("1.2.4"))
+	})
+
+	t.Run("colliding results are reported", func(t *testing.T) {
+		applied, err := newCollection(t).Apply(func(ds *dicom.DataSet) (*dicom.DataSet, error) {
+			out := ds.Copy()
+			return out, out.Set(mustNewElement(tag.SOPInstanceUID, vr.UniqueIdentifier,
+				mustNewStringValue(vr.UniqueIdentifier, []string{"1.2.9"})))
+		})
+
+		var applyErr *dicom.ApplyError
+		require.ErrorAs(t, err, &applyErr)
+		assert.Len(t, applyErr.Errors, 5)
+		assert.Equal(t, 1, applied.Len())
+
+		conflicts := applied.ValidateUIDConsistency()
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, dicom.UIDConflictInstanceContent, conflicts[0].Kind)
+		assert.Equal(t, "1.2.9", conflicts[0].UID)
+	})
+
+	t.Run("nil function", func(t *testing.T) {
+		_, err := newCollection(t).Apply(nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestDataSetCollection_ValidateUIDConsistency(t *testing.T) {
+	t.Run("consistent collection", func(t *testing.T) {
+		coll := dicom.NewDataSetCollection()
+		require.NoError(t, coll.Add(createTestDataSetForCollection("1.1.1", "1.1", "1", "P1", "", "1.2.840.10008.5.1.4.1.1.2", 1)))
+		require.NoError(t, coll.Add(createTestDataSetForCollection("1.1.2", "1.1", "1", "P1", "", "1.2.840.10008.5.1.4.1.1.2", 1)))
+
+		// An identical copy is rejected but is not a conflict
+		err := coll.Add(createTestDataSetForCollection("1.1.1", "1.1", "1", "P1", "", "1.2.840.10008.5.1.4.1.1.2", 1))
+		require.Error(t, err)
+
+		assert.Empty(t, coll.ValidateUIDConsistency())
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		coll := dicom.NewDataSetCollection()
+		require.NoError(t, coll.Add(createTestDataSetForCollection("1.1.1", "1.1", "1", "P1", "", "1.2.840.10008.5.1.4.1.1.2", 1)))
+		// Series 1.1 reused in study 2, which also belongs to another patient
+		require.NoError(t, coll.Add(createTestDataSetForCollection("2.1.1", "1.1", "2", "P2", "", "1.2.840.10008.5.1.4.1.1.2", 1)))
+		require.NoError(t, coll.Add(createTestDataSetForCollection("2.1.2", "2.2", "2", "P3", "", "1.2.840.10008.5.1.4.1.1.2", 2)))
+		// Study UID 3 is also used as a SOPInstanceUID
+		require.NoError(t, coll.Add(createTestDataSetForCollection("3", "3.1", "3", "P3", "", "1.2.840.10008.5.1.4.1.1.2", 1)))
+
+		// Add rejects a different instance with the same SOPInstanceUID without
+		// recording anything
+		err := coll.AddWithFilePath(createTestDataSetForCollection("1.1.1", "1.1", "1", "P1", "ACC9", "1.2.840.10008.5.1.4.1.1.2", 1), "/import/b/1.dcm")
+		require.Error(t, err)
+
+		conflicts := coll.ValidateUIDConsistency()
+		assert.Equal(t, []dicom.UIDConflict{
+			{
+				Kind:            dicom.UIDConflictSeriesStudies,
+				UID:             "1.1",
+				Values:          []string{"1", "2"},
+				SOPInstanceUIDs: []string{"1.1.1", "2.1.1"},
+			},
+			{
+				Kind:            dicom.UIDConflictStudyPatients,
+				UID:             "2",
+				Values:          []string{"P2", "P3"},
+				SOPInstanceUIDs: []string{"2.1.1", "2.1.2"},
+			},
+			{
+				Kind:            dicom.UIDConflictLevels,
+				UID:             "3",
+				Values:          []string{"SOPInstanceUID", "StudyInstanceUID"},
+				SOPInstanceUIDs: []string{"3"},
+			},
+		}, conflicts)
+		assert.Equal(t, "series in multiple studies: 1.1 (1, 2)", conflicts[0].String())
+	})
+
+	t.Run("instance collision recorded by AddOrRecord", func(t *testing.T) {
+		coll := dicom.NewDataSetCollection()
+		require.NoError(t, coll.AddOrRecord(createTestDataSetForCollection("1.1.1", "1.1", "1", "P1", "", "1.2.840.10008.5.1.4.1.1.2", 1), "/import/a/1.dcm"))
+
+		// An identical copy is rejected without being recorded
+		err := coll.AddOrRecord(createTestDataSetForCollection("1.1.1", "1.1", "1", "P1", "", "1.2.840.10008.5.1.4.1.1.2", 1), "/import/c/1.dcm")
+		require.Error(t, err)
+
+		err = coll.AddOrRecord(createTestDataSetForCollection("1.1.1", "1.1", "1", "P1", "ACC9", "1.2.840.10008.5.1.4.1.1.2", 1), "/import/b/1.dcm")
+		require.Error(t, err)
+		assert.Equal(t, "/import/a/1.dcm", coll.FilePath("1.1.1"), "the first dataset is kept")
+
+		assert.Equal(t, []dicom.UIDConflict{{
+			Kind:            dicom.UIDConflictInstanceContent,
+			UID:             "1.1.1",
+			Values:          []string{"/import/b/1.dcm"},
+			SOPInstanceUIDs: []string{"1.1.1"},
+		}}, coll.ValidateUIDConsistency())
+	})
+
+	t.Run("instance collision while parsing a directory", func(t *testing.T) {
+		dir := t.TempDir()
+		paths := []string{filepath.Join(dir, "a.dcm"), filepath.Join(dir, "b.dcm")}
+		require.NoError(t, dicom.WriteFile(paths[0], createTestDataSetForCollection("1.1.1", "1.1", "1", "P1", "", "1.2.840.10008.5.1.4.1.1.2", 1)))
+		require.NoError(t, dicom.WriteFile(paths[1], createTestDataSetForCollection("1.1.1", "1.1", "1", "P1", "ACC9", "1.2.840.10008.5.1.4.1.1.2", 1)))
+
+		result, err := dicom.ParseDirectory(dir)
+		require.NoError(t, err)
+		coll := result.Collection
+		require.Equal(t, 1, coll.Len())
+
+		// The file read second is the rejected copy
+		rejected := paths[0]
+		if coll.FilePath("1.1.1") == paths[0] {
+			rejected = paths[1]
+		}
+		assert.Equal(t, []dicom.UIDConflict{{
+			Kind:            dicom.UIDConflictInstanceContent,
+			UID:             "1.1.1",
+			Values:          []string{rejected},
+			SOPInstanceUIDs: []string{"1.1.1"},
+		}}, coll.ValidateUIDConsistency())
+
+		// Removing the instance forgets its collisions
+		require.NoError(t, coll.Remove("1.1.1"))
+		assert.Empty(t, coll.ValidateUIDConsistency())
+	})
+}
@@ -0,0 +1,276 @@
+package dicom
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// UIDConflictKind identifies the kind of UID inconsistency reported by
+// ValidateUIDConsistency.
+type UIDConflictKind int
+
+const (
+	// UIDConflictInstanceContent means a dataset with the same SOPInstanceUID but
+	// different content was added after the instance already in the collection, and
+	// rejected. Re-adding an identical copy of an instance is not a conflict.
+	UIDConflictInstanceContent UIDConflictKind = iota
+
+	// UIDConflictSeriesStudies means instances of one series name different studies.
+	UIDConflictSeriesStudies
+
+	// UIDConflictStudyPatients means instances of one study name different patients.
+	UIDConflictStudyPatients
+
+	// UIDConflictLevels means one UID is used at more than one level of the
+	// patient/study/series/instance hierarchy, such as a SeriesInstanceUID that is
+	// also a StudyInstanceUID.
+	UIDConflictLevels
+)
+
+// String returns a short description of the conflict kind.
+func (k UIDConflictKind) String() string {
+	switch k {
+	case UIDConflictInstanceContent:
+		return "instance content differs"
+	case UIDConflictSeriesStudies:
+		return "series in multiple studies"
+	case UIDConflictStudyPatients:
+		return "study in multiple patients"
+	case UIDConflictLevels:
+		return "UID used at multiple levels"
+	default:
+		return fmt.Sprintf("UIDConflictKind(%d)", int(k))
+	}
+}
+
+// UIDConflict describes a UID whose use across a collection indicates corrupt or
+// mislabeled data.
+type UIDConflict struct {
+	Kind UIDConflictKind
+
+	// UID is the reused identifier: the SOPInstanceUID, SeriesInstanceUID or
+	// StudyInstanceUID named by Kind, or for UIDConflictLevels the shared UID.
+	UID string
+
+	// Values are the conflicting values, sorted: the StudyInstanceUIDs of the
+	// series, the PatientIDs of the study, or the attribute keywords the UID appears
+	// in. For UIDConflictInstanceContent they are the file paths of the rejected
+	// copies, where known.
+	Values []string
+
+	// SOPInstanceUIDs lists the instances in the collection involved in the
+	// conflict, sorted.
+	SOPInstanceUIDs []string
+}
+
+// String formats the conflict for logs and reports.
+func (c UIDConflict) String() string {
+	return fmt.Sprintf("%s: %s (%s)", c.Kind, c.UID, strings.Join(c.Values, ", "))
+}
+
+// instanceCollision records a dataset rejected by AddOrRecord because its
+// SOPInstanceUID was already in the collection with different content.
+type instanceCollision struct {
+	sopInstanceUID string
+	path           string
+}
+
+// recordInstanceCollision remembers ds, read from path, if the collection already
+// holds a different instance with its SOPInstanceUID. It is called once Add has
+// rejected ds, so that Add itself leaves the collection unchanged on failure.
+func (c *DataSetCollection) recordInstanceCollision(ds *DataSet, path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sopInstanceUID, err := c.extractStringValue(ds, tag.SOPInstanceUID, "SOPInstanceUID")
+	if err != nil {
+		return
+	}
+	existing, exists := c.datasets[sopInstanceUID]
+	if !exists || existing == ds || sameInstanceContent(existing, ds) {
+		return
+	}
+	c.instanceCollisions = append(c.instanceCollisions, instanceCollision{sopInstanceUID: sopInstanceUID, path: path})
+}
+
+// ValidateUIDConsistency reports UIDs whose use indicates corrupt or mislabeled
+// data, a typical problem when a collection is assembled from several sources:
+//   - A SOPInstanceUID added again with different content by AddOrRecord, which
+//     keeps the first dataset and remembers the others for this report. ParseDirectory
+//     and Apply add datasets this way; Add, AddWithFilePath and
+//     NewDataSetCollectionWithDataSets simply reject a duplicate SOPInstanceUID
+//     and record nothing
+//   - A SeriesInstanceUID whose instances name more than one StudyInstanceUID
+//   - A StudyInstanceUID whose instances name more than one PatientID
+//   - A UID used as more than one of SOPInstanceUID, SeriesInstanceUID,
+//     StudyInstanceUID and FrameOfReferenceUID
+//
+// Content is compared ignoring the File Meta Information and value padding (see
+// DataSet.EqualsIgnoringPadding), so the same instance stored by different
+// applications or in different transfer syntaxes is not a conflict.
+//
+// Conflicts are returned grouped by kind in the order above, each kind sorted by
+// UID. An empty slice means no conflicts were found.
+//
+// Example:
+//
+//	result, _ := dicom.ParseDirectory(dir)
+//	for _, conflict := range result.Collection.ValidateUIDConsistency() {
+//	    log.Printf("UID conflict: %s", conflict)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#chapter_9
+func (c *DataSetCollection) ValidateUIDConsistency() []UIDConflict {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	conflicts := []UIDConflict{}
+	conflicts = append(conflicts, c.instanceConflicts()...)
+	conflicts = append(conflicts, indexConflicts(UIDConflictSeriesStudies, c.seriesInstanceIndex, tag.StudyInstanceUID)...)
+	conflicts = append(conflicts, indexConflicts(UIDConflictStudyPatients, c.studyInstanceIndex, tag.PatientID)...)
+	conflicts = append(conflicts, c.levelConflicts()...)
+	return conflicts
+}
+
+// instanceConflicts reports the recorded instance collisions, one conflict per
+// SOPInstanceUID.
+func (c *DataSetCollection) instanceConflicts() []UIDConflict {
+	paths := make(map[string][]string)
+	for _, collision := range c.instanceCollisions {
+		paths[collision.sopInstanceUID] = append(paths[collision.sopInstanceUID], collision.path)
+	}
+
+	conflicts := []UIDConflict{}
+	for _, sopInstanceUID := range sortedKeys(paths) {
+		values := []string{}
+		for _, path := range paths[sopInstanceUID] {
+			if path != "" {
+				values = append(values, path)
+			}
+		}
+		slices.Sort(values)
+		conflicts = append(conflicts, UIDConflict{
+			Kind:            UIDConflictInstanceContent,
+			UID:             sopInstanceUID,
+			Values:          slices.Compact(values),
+			SOPInstanceUIDs: []string{sopInstanceUID},
+		})
+	}
+	return conflicts
+}
+
+// indexConflicts reports the keys of index whose datasets do not all share the same
+// value of attribute.
+func indexConflicts(kind UIDConflictKind, index map[string][]*DataSet, attribute tag.Tag) []UIDConflict {
+	conflicts := []UIDConflict{}
+	for _, uid := range sortedKeys(index) {
+		datasets := index[uid]
+		values := make([]string, 0, len(datasets))
+		sopInstanceUIDs := make([]string, 0, len(datasets))
+		for _, ds := range datasets {
+			values = append(values, stringValue(ds, attribute))
+			sopInstanceUIDs = append(sopInstanceUIDs, stringValue(ds, tag.SOPInstanceUID))
+		}
+		slices.Sort(values)
+		values = slices.Compact(values)
+		if len(values) < 2 {
+			continue
+		}
+		slices.Sort(sopInstanceUIDs)
+		conflicts = append(conflicts, UIDConflict{
+			Kind:            kind,
+			UID:             uid,
+			Values:          values,
+			SOPInstanceUIDs: sopInstanceUIDs,
+		})
+	}
+	return conflicts
+}
+
+// levelConflicts reports UIDs used at more than one level of the hierarchy.
+func (c *DataSetCollection) levelConflicts() []UIDConflict {
+	levels := []struct {
+		keyword string
+		index   map[string][]*DataSet
+	}{
+		{"SOPInstanceUID", nil},
+		{"SeriesInstanceUID", c.seriesInstanceIndex},
+		{"StudyInstanceUID", c.studyInstanceIndex},
+		{"FrameOfReferenceUID", c.frameOfReferenceIndex},
+	}
+
+	keywords := make(map[string][]string)
+	instances := make(map[string][]string)
+	for _, level := range levels {
+		if level.index == nil {
+			for sopInstanceUID := range c.datasets {
+				keywords[sopInstanceUID] = append(keywords[sopInstanceUID], level.keyword)
+				instances[sopInstanceUID] = append(instances[sopInstanceUID], sopInstanceUID)
+			}
+			continue
+		}
+		for uid, datasets := range level.index {
+			if len(datasets) == 0 {
+				continue
+			}
+			keywords[uid] = append(keywords[uid], level.keyword)
+			for _, ds := range datasets {
+				instances[uid] = append(instances[uid], stringValue(ds, tag.SOPInstanceUID))
+			}
+		}
+	}
+
+	conflicts := []UIDConflict{}
+	for _, uid := range sortedKeys(keywords) {
+		if len(keywords[uid]) < 2 {
+			continue
+		}
+		values := keywords[uid]
+		slices.Sort(values)
+		sopInstanceUIDs := instances[uid]
+		slices.Sort(sopInstanceUIDs)
+		conflicts = append(conflicts, UIDConflict{
+			Kind:            UIDConflictLevels,
+			UID:             uid,
+			Values:          values,
+			SOPInstanceUIDs: slices.Compact(sopInstanceUIDs),
+		})
+	}
+	return conflicts
+}
+
+// sameInstanceContent reports whether two datasets hold the same instance, ignoring
+// File Meta Information and value padding.
+func sameInstanceContent(a, b *DataSet) bool {
+	count := 0
+	for t, elem := range a.elements {
+		if t.Group == 0x0002 {
+			continue
+		}
+		count++
+		other, exists := b.elements[t]
+		if !exists || !elem.EqualsIgnoringPadding(other) {
+			return false
+		}
+	}
+	for t := range b.elements {
+		if t.Group != 0x0002 {
+			count--
+		}
+	}
+	return count == 0
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package dicom
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/datetime"
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// timezoneOffsetRegex matches Timezone Offset From UTC (0008,0201): &ZZXX.
+var timezoneOffsetRegex = regexp.MustCompile(`^[+-]\d{4}$`)
+
+// ContentDateTime returns Content Date (0008,0023) and Content Time (0008,0033)
+// combined into a single DateTime, the time the image pixel data was created.
+//
+// If only Content Time is present, the date is taken from Series Date (0008,0021) or
+// failing that Study Date (0008,0020). If only Content Date is present, the result
+// has day precision. The offset from UTC is taken from Timezone Offset From UTC
+// (0008,0201) when present; otherwise NoOffset is set and the time is local to the
+// modality, held in UTC as datetime.ParseDateTime does.
+//
+// Returns an error if neither Content Date nor Content Time is present, if only the
+// time is present and no date can be found for it, or if a value cannot be parsed.
+//
+// Example:
+//
+//	dt, err := ds.ContentDateTime()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(dt.Time.Format(time.RFC3339))
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.1
+func (ds *DataSet) ContentDateTime() (datetime.DateTime, error) {
+	return ds.combinedDateTime(tag.Tag{}, tag.ContentDate, tag.ContentTime)
+}
+
+// AcquisitionDateTime returns the time the acquisition of the data started: the
+// Acquisition DateTime (0008,002A) element if present, otherwise Acquisition Date
+// (0008,0022) and Acquisition Time (0008,0032) combined.
+//
+// Dates and offsets are completed as for ContentDateTime: a lone Acquisition Time
+// takes its date from Series Date or Study Date, and Timezone Offset From UTC
+// (0008,0201) applies to values that carry no offset of their own.
+//
+// Returns an error if none of the three elements is present, if only the time is
+// present and no date can be found for it, or if a value cannot be parsed.
+//
+// Example:
+//
+//	sort.Slice(instances, func(i, j int) bool {
+//	    a, _ := instances[i].AcquisitionDateTime()
+//	    b, _ := instances[j].AcquisitionDateTime()
+//	    return a.Time.Before(b.Time)
+//	})
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.1
+func (ds *DataSet) AcquisitionDateTime() (datetime.DateTime, error) {
+	return ds.combinedDateTime(tag.AcquisitionDateTime, tag.AcquisitionDate, tag.AcquisitionTime)
+}
+
+// combinedDateTime reads the DT element dtTag, if it is not the zero tag and is
+// present, or else the DA and TM elements dateTag and timeTag, into one DateTime.
+func (ds *DataSet) combinedDateTime(dtTag, dateTag, timeTag tag.Tag) (datetime.DateTime, error) {
+	dtStr := ""
+	if dtTag != (tag.Tag{}) {
+		dtStr = stringValue(ds, dtTag)
+	}
+	source := dtTag.String()
+
+	if dtStr == "" {
+		source = dateTag.String() + " and " + timeTag.String()
+		dateStr, timeStr := stringValue(ds, dateTag), stringValue(ds, timeTag)
+		if dateStr == "" && timeStr == "" {
+			if dtTag != (tag.Tag{}) {
+				return datetime.DateTime{}, fmt.Errorf("none of %s, %s or %s is present", dtTag, dateTag, timeTag)
+			}
+			return datetime.DateTime{}, fmt.Errorf("neither %s nor %s is present", dateTag, timeTag)
+		}
+		dateSource := dateTag
+		if dateStr == "" {
+			// A time alone is on the day of the series or study
+			for _, t := range []tag.Tag{tag.SeriesDate, tag.StudyDate} {
+				if dateStr = stringValue(ds, t); dateStr != "" {
+					dateSource = t
+					break
+				}
+			}
+			if dateStr == "" {
+				return datetime.DateTime{}, fmt.Errorf("%s is present but there is no %s, Series Date or Study Date", timeTag, dateTag)
+			}
+		}
+
+		date, err := datetime.ParseDate(dateStr)
+		if err != nil {
+			return datetime.DateTime{}, fmt.Errorf("failed to parse %s: %w", dateSource, err)
+		}
+		dtStr = date.DCM()
+		if timeStr != "" {
+			tm, err := datetime.ParseTime(timeStr)
+			if err != nil {
+				return datetime.DateTime{}, fmt.Errorf("failed to parse %s: %w", timeTag, err)
+			}
+			if date.Precision != datetime.PrecisionDay {
+				return datetime.DateTime{}, fmt.Errorf("%s %q is not a full date to combine with %s", dateSource, dateStr, timeTag)
+			}
+			dtStr += tm.DCM()
+		}
+	}
+
+	// A value without an offset is in the timezone of the dataset, if stated
+	if !strings.ContainsAny(dtStr, "+-") {
+		if offset := stringValue(ds, tag.TimezoneOffsetFromUTC); offset != "" {
+			if !timezoneOffsetRegex.MatchString(offset) {
+				return datetime.DateTime{}, fmt.Errorf("invalid Timezone Offset From UTC %q", offset)
+			}
+			dtStr += offset
+		}
+	}
+
+	dt, err := datetime.ParseDateTime(dtStr)
+	if err != nil {
+		return datetime.DateTime{}, fmt.Errorf("failed to parse %s: %w", source, err)
+	}
+	return dt, nil
+}
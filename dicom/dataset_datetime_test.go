@@ -0,0 +1,146 @@
+package dicom_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/datetime"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTemporalDataSet returns a dataset holding the given string elements.
+func newTemporalDataSet(t *testing.T, values map[tag.Tag]string) *dicom.DataSet {
+	t.Helper()
+	ds := dicom.NewDataSet()
+	for tg, v := range values {
+		var r vr.VR
+		switch tg {
+		case tag.AcquisitionDateTime:
+			r = vr.DateTime
+		case tag.ContentTime, tag.AcquisitionTime:
+			r = vr.Time
+		case tag.TimezoneOffsetFromUTC:
+			r = vr.ShortString
+		default:
+			r = vr.Date
+		}
+		require.NoError(t, ds.Set(mustNewElement(tg, r, mustNewStringValue(r, []string{v}))))
+	}
+	return ds
+}
+
+func TestDataSet_ContentDateTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    map[tag.Tag]string
+		want      time.Time
+		precision datetime.PrecisionLevel
+		noOffset  bool
+	}{
+		{
+			name:      "date and time",
+			values:    map[tag.Tag]string{tag.ContentDate: "20231015", tag.ContentTime: "143025.123"},
+			want:      time.Date(2023, 10, 15, 14, 30, 25, 123000000, time.UTC),
+			precision: datetime.PrecisionMS3,
+			noOffset:  true,
+		},
+		{
+			name:      "date only",
+			values:    map[tag.Tag]string{tag.ContentDate: "20231015"},
+			want:      time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC),
+			precision: datetime.PrecisionDay,
+			noOffset:  true,
+		},
+		{
+			name:      "time only takes the series date",
+			values:    map[tag.Tag]string{tag.ContentTime: "0930", tag.SeriesDate: "20231016", tag.StudyDate: "20231015"},
+			want:      time.Date(2023, 10, 16, 9, 30, 0, 0, time.UTC),
+			precision: datetime.PrecisionMinutes,
+			noOffset:  true,
+		},
+		{
+			name:      "time only takes the study date",
+			values:    map[tag.Tag]string{tag.ContentTime: "093000", tag.StudyDate: "20231015"},
+			want:      time.Date(2023, 10, 15, 9, 30, 0, 0, time.UTC),
+			precision: datetime.PrecisionSeconds,
+			noOffset:  true,
+		},
+		{
+			name: "timezone offset",
+			values: map[tag.Tag]string{tag.ContentDate: "20231015", tag.ContentTime: "143025",
+				tag.TimezoneOffsetFromUTC: "+1000"},
+			want:      time.Date(2023, 10, 15, 4, 30, 25, 0, time.UTC),
+			precision: datetime.PrecisionSeconds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt, err := newTemporalDataSet(t, tt.values).ContentDateTime()
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(dt.Time), "got %s, want %s", dt.Time, tt.want)
+			assert.Equal(t, tt.precision, dt.Precision)
+			assert.Equal(t, tt.noOffset, dt.NoOffset)
+		})
+	}
+}
+
+func TestDataSet_AcquisitionDateTime(t *testing.T) {
+	t.Run("DT element wins", func(t *testing.T) {
+		ds := newTemporalDataSet(t, map[tag.Tag]string{
+			tag.AcquisitionDateTime: "20231015143025-0500",
+			tag.AcquisitionDate:     "20200101",
+			tag.AcquisitionTime:     "000000",
+		})
+		dt, err := ds.AcquisitionDateTime()
+		require.NoError(t, err)
+		assert.True(t, time.Date(2023, 10, 15, 19, 30, 25, 0, time.UTC).Equal(dt.Time))
+		assert.False(t, dt.NoOffset)
+	})
+
+	t.Run("DT without offset takes the dataset offset", func(t *testing.T) {
+		ds := newTemporalDataSet(t, map[tag.Tag]string{
+			tag.AcquisitionDateTime:   "202310151430",
+			tag.TimezoneOffsetFromUTC: "-0130",
+		})
+		dt, err := ds.AcquisitionDateTime()
+		require.NoError(t, err)
+		assert.True(t, time.Date(2023, 10, 15, 16, 0, 0, 0, time.UTC).Equal(dt.Time))
+		assert.Equal(t, datetime.PrecisionMinutes, dt.Precision)
+	})
+
+	t.Run("date and time", func(t *testing.T) {
+		ds := newTemporalDataSet(t, map[tag.Tag]string{
+			tag.AcquisitionDate: "20231015",
+			tag.AcquisitionTime: "143025.5",
+		})
+		dt, err := ds.AcquisitionDateTime()
+		require.NoError(t, err)
+		assert.True(t, time.Date(2023, 10, 15, 14, 30, 25, 500000000, time.UTC).Equal(dt.Time))
+		assert.Equal(t, "20231015143025.5", dt.DCM())
+	})
+}
+
+func TestDataSet_DateTimeErrors(t *testing.T) {
+	_, err := dicom.NewDataSet().ContentDateTime()
+	assert.ErrorContains(t, err, "neither")
+
+	_, err = dicom.NewDataSet().AcquisitionDateTime()
+	assert.ErrorContains(t, err, "none of")
+
+	_, err = newTemporalDataSet(t, map[tag.Tag]string{tag.ContentTime: "143025"}).ContentDateTime()
+	assert.ErrorContains(t, err, "no (0008,0023), Series Date or Study Date")
+
+	_, err = newTemporalDataSet(t, map[tag.Tag]string{tag.ContentDate: "202310", tag.ContentTime: "1430"}).ContentDateTime()
+	assert.ErrorContains(t, err, "not a full date")
+
+	_, err = newTemporalDataSet(t, map[tag.Tag]string{tag.ContentDate: "20231015", tag.ContentTime: "2561"}).ContentDateTime()
+	assert.Error(t, err)
+
+	_, err = newTemporalDataSet(t, map[tag.Tag]string{tag.ContentDate: "20231015", tag.TimezoneOffsetFromUTC: "10:00"}).ContentDateTime()
+	assert.ErrorContains(t, err, "Timezone Offset From UTC")
+}
@@ -2,10 +2,14 @@
 package dicom
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/codeninja55/go-radx/dicom/datetime"
 	"github.com/codeninja55/go-radx/dicom/element"
 	"github.com/codeninja55/go-radx/dicom/tag"
 	"github.com/codeninja55/go-radx/dicom/uid"
@@ -27,7 +31,7 @@ func (ds *DataSet) SetPatientName(name string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create PatientName element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 // SetPatientID sets the Patient ID (0010,0020) in the dataset.
@@ -44,7 +48,7 @@ func (ds *DataSet) SetPatientID(id string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create PatientID element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 // SetPatientBirthDate sets the Patient's Birth Date (0010,0030) in the dataset.
@@ -68,7 +72,7 @@ func (ds *DataSet) SetPatientBirthDate(date string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create PatientBirthDate element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 // SetPatientAge sets the Patient's Age (0010,1010) in the dataset.
@@ -92,7 +96,88 @@ func (ds *DataSet) SetPatientAge(age string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create PatientAge element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
+}
+
+// FillPatientAge sets Patient's Age (0010,1010) from Patient's Birth Date (0010,0030)
+// and Study Date (0008,0020) when the age is absent or empty.
+//
+// The age is computed with datetime.AgeBetween, which picks the conventional unit
+// (days, weeks, months or years) for the age. Nothing is changed, and no error is
+// returned, if either date is missing, empty or less precise than a full day, or if
+// the dataset already has a Patient's Age. Use RefreshPatientAge to overwrite an
+// existing age.
+//
+// Returns an error if a date cannot be parsed or the study date precedes the birth
+// date.
+//
+// Example:
+//
+//	// PatientBirthDate=19800115, StudyDate=20231014, no PatientAge
+//	if err := ds.FillPatientAge(); err != nil {
+//	    log.Printf("Could not derive age: %v", err)
+//	}
+//	// PatientAge is now "043Y"
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.2.2
+func (ds *DataSet) FillPatientAge() error {
+	return ds.fillPatientAge(false)
+}
+
+// RefreshPatientAge recomputes Patient's Age (0010,1010) from Patient's Birth Date and
+// Study Date like FillPatientAge, replacing any existing age.
+//
+// An existing age is left untouched when the dates are missing or not precise enough
+// to compute a new one.
+//
+// Example:
+//
+//	if err := ds.RefreshPatientAge(); err != nil {
+//	    log.Printf("Could not derive age: %v", err)
+//	}
+func (ds *DataSet) RefreshPatientAge() error {
+	return ds.fillPatientAge(true)
+}
+
+// fillPatientAge implements FillPatientAge and RefreshPatientAge.
+func (ds *DataSet) fillPatientAge(force bool) error {
+	if !force {
+		if elem, err := ds.Get(tag.PatientAge); err == nil && strings.TrimSpace(elem.Value().String()) != "" {
+			return nil
+		}
+	}
+
+	dateValue := func(t tag.Tag) string {
+		elem, err := ds.Get(t)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(elem.Value().String())
+	}
+	birthStr, studyStr := dateValue(tag.PatientBirthDate), dateValue(tag.StudyDate)
+	if birthStr == "" || studyStr == "" {
+		return nil
+	}
+
+	birth, err := datetime.ParseDate(birthStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse PatientBirthDate: %w", err)
+	}
+	study, err := datetime.ParseDate(studyStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse StudyDate: %w", err)
+	}
+	if birth.Precision != datetime.PrecisionDay || study.Precision != datetime.PrecisionDay {
+		return nil
+	}
+
+	age, err := datetime.AgeBetween(birth, study)
+	if err != nil {
+		return fmt.Errorf("failed to compute PatientAge: %w", err)
+	}
+
+	return ds.SetPatientAge(age.DCM())
 }
 
 // SetPatientSex sets the Patient's Sex (0010,0040) in the dataset.
@@ -117,7 +202,7 @@ func (ds *DataSet) SetPatientSex(sex string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create PatientSex element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 // SetAccessionNumber sets the Accession Number (0008,0050) in the dataset.
@@ -134,7 +219,7 @@ func (ds *DataSet) SetAccessionNumber(number string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create AccessionNumber element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 // SetStudyInstanceUID sets the Study Instance UID (0020,000D) in the dataset.
@@ -163,7 +248,7 @@ func (ds *DataSet) SetStudyInstanceUID(uidStr string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create StudyInstanceUID element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 // SetSeriesInstanceUID sets the Series Instance UID (0020,000E) in the dataset.
@@ -192,7 +277,7 @@ func (ds *DataSet) SetSeriesInstanceUID(uidStr string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create SeriesInstanceUID element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 // SetSOPInstanceUID sets the SOP Instance UID (0008,0018) in the dataset.
@@ -221,7 +306,7 @@ func (ds *DataSet) SetSOPInstanceUID(uidStr string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create SOPInstanceUID element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 // GenerateNewUIDs generates new UIDs for Study, Series, and SOP Instance UIDs.
@@ -265,7 +350,7 @@ func (ds *DataSet) GenerateNewUIDs() error {
 		if err != nil {
 			return fmt.Errorf("failed to update Media Storage SOP Instance UID: %w", err)
 		}
-		if err := ds.Add(elem); err != nil {
+		if err := ds.Set(elem); err != nil {
 			return fmt.Errorf("failed to add Media Storage SOP Instance UID: %w", err)
 		}
 	}
@@ -294,7 +379,7 @@ func (ds *DataSet) SetStudyDate(date string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create StudyDate element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 // SetStudyTime sets the Study Time (0008,0030) in the dataset.
@@ -314,7 +399,7 @@ func (ds *DataSet) SetStudyTime(timeStr string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create StudyTime element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 // SetSeriesNumber sets the Series Number (0020,0011) in the dataset.
@@ -331,7 +416,7 @@ func (ds *DataSet) SetSeriesNumber(number int) error {
 	if err != nil {
 		return fmt.Errorf("failed to create SeriesNumber element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 // SetInstanceNumber sets the Instance Number (0020,0013) in the dataset.
@@ -348,25 +433,64 @@ func (ds *DataSet) SetInstanceNumber(number int) error {
 	if err != nil {
 		return fmt.Errorf("failed to create InstanceNumber element: %w", err)
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
-// Walk iterates through all elements in the dataset, calling fn for each element.
+// SkipSequence is returned by the function passed to Walk to skip the items of the
+// sequence (SQ) element just visited. Returned for any other element it has no
+// effect.
+var SkipSequence = errors.New("skip sequence")
+
+// Walk visits every element in the dataset in tag order, descending depth-first
+// into the items of sequence (SQ) elements.
 //
-// The function fn should return an error to stop iteration.
-// If fn returns nil, iteration continues.
+// fn receives the tags of the sequences enclosing the element, outermost first; the
+// path is empty for top-level elements. A sequence element is visited before its
+// items. Returning SkipSequence, or an error wrapping it, from fn for a sequence
+// element skips its items; returning any other error stops the walk and Walk
+// returns that error. The path slice is owned by fn and may be retained.
 //
 // Example:
 //
-//	ds.Walk(func(elem *element.Element) error {
-//	    fmt.Printf("%s = %s\n", elem.Tag(), elem.Value())
+//	err := ds.Walk(func(path []tag.Tag, elem *element.Element) error {
+//	    if elem.Tag() == tag.PixelData {
+//	        return nil
+//	    }
+//	    fmt.Printf("%v %s = %s\n", path, elem.Tag(), elem.Value())
 //	    return nil
 //	})
-func (ds *DataSet) Walk(fn func(*element.Element) error) error {
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.5
+func (ds *DataSet) Walk(fn func(path []tag.Tag, elem *element.Element) error) error {
+	return ds.walk(nil, fn)
+}
+
+// walk visits the elements of ds, which is nested at path.
+func (ds *DataSet) walk(path []tag.Tag, fn func(path []tag.Tag, elem *element.Element) error) error {
 	for _, elem := range ds.Elements() {
-		if err := fn(elem); err != nil {
+		if err := fn(append([]tag.Tag(nil), path...), elem); err != nil {
+			if errors.Is(err, SkipSequence) {
+				continue
+			}
 			return err
 		}
+
+		seq, ok := elem.Value().(*value.SequenceValue)
+		if !ok {
+			continue
+		}
+
+		items, err := SequenceItems(seq)
+		if err != nil {
+			return fmt.Errorf("sequence %s: %w", elem.Tag(), err)
+		}
+		itemPath := append(append([]tag.Tag(nil), path...), elem.Tag())
+		for _, item := range items {
+			if err := item.walk(itemPath, fn); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -479,6 +603,49 @@ func (ds *DataSet) RemoveGroupTags(group uint16) error {
 	return nil
 }
 
+// RemoveGroupLengths removes all Group Length (gggg,0000) elements except the File
+// Meta Information Group Length (0002,0000), including those nested in sequence items.
+//
+// Group Length elements are retired outside File Meta Information. Old files still
+// carry them, and their values go stale as soon as the group is modified, so they
+// should be dropped before re-encoding. The writer never emits them for the dataset.
+//
+// Example:
+//
+//	if err := ds.RemoveGroupLengths(); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.2
+func (ds *DataSet) RemoveGroupLengths() error {
+	for t, elem := range ds.elements {
+		if isGroupLengthTag(t) && t.Group != 0x0002 {
+			delete(ds.elements, t)
+			continue
+		}
+
+		seq, ok := elem.Value().(*value.SequenceValue)
+		if !ok {
+			continue
+		}
+		for _, item := range seq.Items() {
+			if itemDS, ok := item.(*DataSet); ok {
+				if err := itemDS.RemoveGroupLengths(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isGroupLengthTag reports whether t is a Group Length (gggg,0000) element.
+func isGroupLengthTag(t tag.Tag) bool {
+	return t.Element == 0x0000
+}
+
 // isInGroup checks if a tag group belongs to a logical group.
 //
 // This handles repeating groups like curves (0x5000-0x50FF) and overlays (0x6000-0x60FF).
@@ -530,7 +697,7 @@ func (ds *DataSet) SetCurrentDateTime() error {
 	if err != nil {
 		return fmt.Errorf("failed to create InstanceCreationDate: %w", err)
 	}
-	if err := ds.Add(dateElem); err != nil {
+	if err := ds.Set(dateElem); err != nil {
 		return err
 	}
 
@@ -543,7 +710,7 @@ func (ds *DataSet) SetCurrentDateTime() error {
 	if err != nil {
 		return fmt.Errorf("failed to create InstanceCreationTime: %w", err)
 	}
-	if err := ds.Add(timeElem); err != nil {
+	if err := ds.Set(timeElem); err != nil {
 		return err
 	}
 
@@ -553,7 +720,7 @@ func (ds *DataSet) SetCurrentDateTime() error {
 		if err != nil {
 			return fmt.Errorf("failed to create ContentDate: %w", err)
 		}
-		if err := ds.Add(contentDateElem); err != nil {
+		if err := ds.Set(contentDateElem); err != nil {
 			return err
 		}
 	}
@@ -564,7 +731,7 @@ func (ds *DataSet) SetCurrentDateTime() error {
 		if err != nil {
 			return fmt.Errorf("failed to create ContentTime: %w", err)
 		}
-		if err := ds.Add(contentTimeElem); err != nil {
+		if err := ds.Set(contentTimeElem); err != nil {
 			return err
 		}
 	}
@@ -637,3 +804,245 @@ func (ds *DataSet) AnonymizeBasic() error {
 
 	return nil
 }
+
+// GetSequenceItems returns the items of the sequence element with the given tag as datasets.
+//
+// Returns an error if the tag is not present or is not a sequence (SQ) element.
+// An empty sequence returns an empty slice.
+//
+// Example:
+//
+//	items, err := ds.GetSequenceItems(tag.ReferencedImageSequence)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, item := range items {
+//	    elem, _ := item.Get(tag.ReferencedSOPInstanceUID)
+//	    fmt.Println(elem.Value())
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.5
+func (ds *DataSet) GetSequenceItems(t tag.Tag) ([]*DataSet, error) {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return nil, err
+	}
+
+	seq, ok := elem.Value().(*value.SequenceValue)
+	if !ok {
+		return nil, fmt.Errorf("element %s is not a sequence (VR %s)", t, elem.VR())
+	}
+
+	items, err := SequenceItems(seq)
+	if err != nil {
+		return nil, fmt.Errorf("sequence %s: %w", t, err)
+	}
+
+	return items, nil
+}
+
+// GetFloats returns all values of the element with the given tag as float64.
+//
+// Binary numeric VRs (FL, FD, SS, US, SL, UL, SV, UV) and the numeric string VRs
+// Decimal String (DS) and Integer String (IS) are handled uniformly, so geometry and
+// display attributes can be read without branching on VR. An empty element returns
+// an empty slice.
+//
+// Returns an error if the tag is not present, has a non-numeric VR, or holds a string
+// component that is not a valid number.
+//
+// Example:
+//
+//	position, err := ds.GetFloats(tag.ImagePositionPatient)  // DS, 3 values
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	centers, _ := ds.GetFloats(tag.WindowCenter)  // DS, 1-n values
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2
+func (ds *DataSet) GetFloats(t tag.Tag) ([]float64, error) {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := elem.Value().(type) {
+	case *value.FloatValue:
+		return v.Floats(), nil
+	case *value.IntValue:
+		result := make([]float64, len(v.Ints()))
+		for i, n := range v.Ints() {
+			result[i] = float64(n)
+		}
+		return result, nil
+	case *value.StringValue:
+		switch v.VR() {
+		case vr.DecimalString:
+			floats, err := v.AsFloats()
+			if err != nil {
+				return nil, fmt.Errorf("element %s: %w", t, err)
+			}
+			return floats, nil
+		case vr.IntegerString:
+			ints, err := v.AsInts()
+			if err != nil {
+				return nil, fmt.Errorf("element %s: %w", t, err)
+			}
+			result := make([]float64, len(ints))
+			for i, n := range ints {
+				result[i] = float64(n)
+			}
+			return result, nil
+		}
+	}
+
+	return nil, fmt.Errorf("element %s is not numeric (VR %s)", t, elem.VR())
+}
+
+// GetInts returns all values of the element with the given tag as int64.
+//
+// Binary integer VRs (SS, US, SL, UL, SV, UV, AT) and Integer String (IS) are read
+// directly. Decimal String (DS) and float VRs (FL, FD) are accepted when every
+// component is a whole number, since writers commonly encode integral quantities
+// such as Rescale Intercept as DS. An empty element returns an empty slice.
+//
+// Returns an error if the tag is not present, has a non-numeric VR, or holds a
+// component that is not a whole number.
+//
+// Example:
+//
+//	dims, err := ds.GetInts(tag.AcquisitionMatrix)  // US, 4 values
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2
+func (ds *DataSet) GetInts(t tag.Tag) ([]int64, error) {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if intVal, ok := elem.Value().(*value.IntValue); ok {
+		return intVal.Ints(), nil
+	}
+	if strVal, ok := elem.Value().(*value.StringValue); ok && strVal.VR() == vr.IntegerString {
+		ints, err := strVal.AsInts()
+		if err != nil {
+			return nil, fmt.Errorf("element %s: %w", t, err)
+		}
+		return ints, nil
+	}
+
+	floats, err := ds.GetFloats(t)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int64, len(floats))
+	for i, f := range floats {
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return nil, fmt.Errorf("element %s value %v at index %d is not an integer", t, f, i)
+		}
+		result[i] = int64(f)
+	}
+	return result, nil
+}
+
+// SequenceItems returns the items of a sequence value as datasets.
+//
+// Combined with Elements, which returns elements in ascending tag order, this gives a
+// deterministic depth-first traversal of a dataset including nested sequences.
+//
+// Example:
+//
+//	for _, elem := range ds.Elements() {
+//	    seq, ok := elem.Value().(*value.SequenceValue)
+//	    if !ok {
+//	        continue
+//	    }
+//	    items, err := dicom.SequenceItems(seq)
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    for _, item := range items {
+//	        fmt.Println(item.Tags())
+//	    }
+//	}
+func SequenceItems(seq *value.SequenceValue) ([]*DataSet, error) {
+	if seq == nil {
+		return nil, fmt.Errorf("sequence value is nil")
+	}
+
+	items := make([]*DataSet, 0, seq.Len())
+	for i, item := range seq.Items() {
+		itemDS, ok := item.(*DataSet)
+		if !ok {
+			return nil, fmt.Errorf("item %d has unsupported type %T", i, item)
+		}
+		items = append(items, itemDS)
+	}
+
+	return items, nil
+}
+
+// NewSequenceElement creates a sequence (SQ) element from nested datasets.
+//
+// Example:
+//
+//	item := dicom.NewDataSet()
+//	// ... populate item ...
+//	elem, err := dicom.NewSequenceElement(tag.ReferencedImageSequence, []*dicom.DataSet{item})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	ds.Add(elem)
+func NewSequenceElement(t tag.Tag, items []*DataSet) (*element.Element, error) {
+	valueItems := make([]value.Item, len(items))
+	for i, item := range items {
+		if item == nil {
+			return nil, fmt.Errorf("sequence item %d is nil", i)
+		}
+		valueItems[i] = item
+	}
+
+	seq, err := value.NewSequenceValue(valueItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sequence value: %w", err)
+	}
+
+	return element.NewElement(t, vr.SequenceOfItems, seq)
+}
+
+// stringValue returns the trimmed string value of t, or "" if it is absent.
+func stringValue(ds *DataSet, t tag.Tag) string {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(strings.TrimSpace(elem.Value().String()), "\x00")
+}
+
+// intValue returns the integer value of t, or nil if it is absent or not an integer.
+func intValue(ds *DataSet, t tag.Tag) *int {
+	n, err := strconv.Atoi(stringValue(ds, t))
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// lessByNumberThenUID orders by number, with missing numbers last, then by UID.
+func lessByNumberThenUID(a, b *int, uidA, uidB string) bool {
+	switch {
+	case a != nil && b != nil && *a != *b:
+		return *a < *b
+	case a != nil && b == nil:
+		return true
+	case a == nil && b != nil:
+		return false
+	}
+	return uidA < uidB
+}
@@ -1,6 +1,7 @@
 package dicom
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/codeninja55/go-radx/dicom/element"
@@ -264,7 +265,7 @@ func TestWalk(t *testing.T) {
 
 	// Walk and count elements
 	count := 0
-	err := ds.Walk(func(elem *element.Element) error {
+	err := ds.Walk(func(_ []tag.Tag, elem *element.Element) error {
 		count++
 		assert.NotNil(t, elem)
 		assert.NotNil(t, elem.Value())
@@ -283,7 +284,7 @@ func TestWalkWithError(t *testing.T) {
 
 	// Walk with error on second element
 	count := 0
-	err := ds.Walk(func(elem *element.Element) error {
+	err := ds.Walk(func(_ []tag.Tag, elem *element.Element) error {
 		count++
 		if count == 2 {
 			return assert.AnError
@@ -295,6 +296,81 @@ func TestWalkWithError(t *testing.T) {
 	assert.Equal(t, 2, count)
 }
 
+// TestWalkNested tests that Walk descends into sequence items and reports paths
+func TestWalkNested(t *testing.T) {
+	inner := NewDataSet()
+	_ = inner.SetPatientID("INNER")
+	innerSeq, err := NewSequenceElement(tag.ReferencedSOPSequence, []*DataSet{inner})
+	require.NoError(t, err)
+
+	item := NewDataSet()
+	_ = item.SetPatientName("Item^One")
+	require.NoError(t, item.Add(innerSeq))
+	seq, err := NewSequenceElement(tag.ReferencedImageSequence, []*DataSet{item, NewDataSet()})
+	require.NoError(t, err)
+
+	ds := NewDataSet()
+	_ = ds.SetPatientName("Doe^John")
+	require.NoError(t, ds.Add(seq))
+
+	type visit struct {
+		path []tag.Tag
+		tag  tag.Tag
+	}
+	var visits []visit
+	err = ds.Walk(func(path []tag.Tag, elem *element.Element) error {
+		visits = append(visits, visit{path, elem.Tag()})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []visit{
+		{nil, tag.ReferencedImageSequence},
+		{[]tag.Tag{tag.ReferencedImageSequence}, tag.ReferencedSOPSequence},
+		{[]tag.Tag{tag.ReferencedImageSequence, tag.ReferencedSOPSequence}, tag.PatientID},
+		{[]tag.Tag{tag.ReferencedImageSequence}, tag.PatientName},
+		{nil, tag.PatientName},
+	}, visits)
+
+	t.Run("SkipSequence", func(t *testing.T) {
+		var tags []tag.Tag
+		err := ds.Walk(func(path []tag.Tag, elem *element.Element) error {
+			tags = append(tags, elem.Tag())
+			if elem.Tag() == tag.ReferencedSOPSequence || elem.Tag() == tag.PatientName {
+				return SkipSequence
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []tag.Tag{tag.ReferencedImageSequence, tag.ReferencedSOPSequence, tag.PatientName, tag.PatientName}, tags)
+	})
+
+	t.Run("wrapped SkipSequence", func(t *testing.T) {
+		var tags []tag.Tag
+		err := ds.Walk(func(path []tag.Tag, elem *element.Element) error {
+			tags = append(tags, elem.Tag())
+			if elem.Tag() == tag.ReferencedImageSequence {
+				return fmt.Errorf("not interested in %s: %w", elem.Tag(), SkipSequence)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []tag.Tag{tag.ReferencedImageSequence, tag.PatientName}, tags)
+	})
+
+	t.Run("error aborts", func(t *testing.T) {
+		count := 0
+		err := ds.Walk(func(path []tag.Tag, elem *element.Element) error {
+			count++
+			if len(path) == 2 {
+				return assert.AnError
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 3, count)
+	})
+}
+
 // TestWalkModify tests modifying elements during iteration
 func TestWalkModify(t *testing.T) {
 	ds := NewDataSet()
@@ -416,6 +492,42 @@ func TestRemoveGroupTags(t *testing.T) {
 	assert.Equal(t, 1, len(ds.Elements()))
 }
 
+// TestRemoveGroupLengths tests removing dataset and nested Group Length elements
+func TestRemoveGroupLengths(t *testing.T) {
+	newGroupLength := func(group uint16) *element.Element {
+		val, err := value.NewIntValue(vr.UnsignedLong, []int64{42})
+		require.NoError(t, err)
+		elem, err := element.NewElement(tag.New(group, 0x0000), vr.UnsignedLong, val)
+		require.NoError(t, err)
+		return elem
+	}
+
+	ds := NewDataSet()
+	_ = ds.SetPatientName("Doe^John")
+	require.NoError(t, ds.Add(newGroupLength(0x0002)))
+	require.NoError(t, ds.Add(newGroupLength(0x0010)))
+	require.NoError(t, ds.Add(newGroupLength(0x0029)))
+
+	item := NewDataSet()
+	_ = item.SetSOPInstanceUID("1.2.3.4")
+	require.NoError(t, item.Add(newGroupLength(0x0008)))
+	seqElem, err := NewSequenceElement(tag.ReferencedImageSequence, []*DataSet{item})
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(seqElem))
+
+	require.NoError(t, ds.RemoveGroupLengths())
+
+	assert.True(t, ds.Contains(tag.New(0x0002, 0x0000)))
+	assert.False(t, ds.Contains(tag.New(0x0010, 0x0000)))
+	assert.False(t, ds.Contains(tag.New(0x0029, 0x0000)))
+	assert.True(t, ds.Contains(tag.PatientName))
+
+	items, err := ds.GetSequenceItems(tag.ReferencedImageSequence)
+	require.NoError(t, err)
+	assert.False(t, items[0].Contains(tag.New(0x0008, 0x0000)))
+	assert.True(t, items[0].Contains(tag.SOPInstanceUID))
+}
+
 // TestAnonymizeBasic tests basic anonymization
 func TestAnonymizeBasic(t *testing.T) {
 	ds := NewDataSet()
@@ -551,3 +663,148 @@ func TestSetInstanceNumber(t *testing.T) {
 	require.Len(t, strs, 1)
 	assert.Equal(t, "42", strs[0])
 }
+
+// addNumericTestElement adds an element built from a string, int or float value.
+func addNumericTestElement(t *testing.T, ds *DataSet, tg tag.Tag, v vr.VR, val value.Value) {
+	elem, err := element.NewElement(tg, v, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(elem))
+}
+
+// TestGetFloatsAndInts tests uniform numeric access across binary and string VRs
+func TestGetFloatsAndInts(t *testing.T) {
+	ds := NewDataSet()
+
+	position, err := value.NewStringValue(vr.DecimalString, []string{"-125.5", " 0 ", "42"})
+	require.NoError(t, err)
+	addNumericTestElement(t, ds, tag.ImagePositionPatient, vr.DecimalString, position)
+
+	instance, err := value.NewStringValue(vr.IntegerString, []string{"7"})
+	require.NoError(t, err)
+	addNumericTestElement(t, ds, tag.InstanceNumber, vr.IntegerString, instance)
+
+	matrix, err := value.NewIntValue(vr.UnsignedShort, []int64{0, 256, 256, 0})
+	require.NoError(t, err)
+	addNumericTestElement(t, ds, tag.AcquisitionMatrix, vr.UnsignedShort, matrix)
+
+	fov, err := value.NewFloatValue(vr.FloatingPointDouble, []float64{0.5, 2})
+	require.NoError(t, err)
+	addNumericTestElement(t, ds, tag.ReconstructionFieldOfView, vr.FloatingPointDouble, fov)
+
+	name, err := value.NewStringValue(vr.PersonName, []string{"Doe^John"})
+	require.NoError(t, err)
+	addNumericTestElement(t, ds, tag.PatientName, vr.PersonName, name)
+
+	t.Run("GetFloats", func(t *testing.T) {
+		tests := []struct {
+			tag  tag.Tag
+			want []float64
+		}{
+			{tag.ImagePositionPatient, []float64{-125.5, 0, 42}},
+			{tag.InstanceNumber, []float64{7}},
+			{tag.AcquisitionMatrix, []float64{0, 256, 256, 0}},
+			{tag.ReconstructionFieldOfView, []float64{0.5, 2}},
+		}
+		for _, tt := range tests {
+			got, err := ds.GetFloats(tt.tag)
+			require.NoError(t, err, "tag %s", tt.tag)
+			assert.Equal(t, tt.want, got, "tag %s", tt.tag)
+		}
+	})
+
+	t.Run("GetInts", func(t *testing.T) {
+		got, err := ds.GetInts(tag.InstanceNumber)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{7}, got)
+
+		got, err = ds.GetInts(tag.AcquisitionMatrix)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{0, 256, 256, 0}, got)
+
+		// Fractional DS and FD values cannot be returned as integers
+		_, err = ds.GetInts(tag.ImagePositionPatient)
+		assert.Error(t, err)
+		_, err = ds.GetInts(tag.ReconstructionFieldOfView)
+		assert.Error(t, err)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := ds.GetFloats(tag.PatientName)
+		assert.Error(t, err)
+		_, err = ds.GetInts(tag.PatientName)
+		assert.Error(t, err)
+		_, err = ds.GetFloats(tag.WindowCenter)
+		assert.Error(t, err)
+	})
+}
+
+func TestFillPatientAge(t *testing.T) {
+	newDS := func(t *testing.T, birth, study, age string) *DataSet {
+		ds := NewDataSet()
+		if birth != "" {
+			require.NoError(t, ds.SetPatientBirthDate(birth))
+		}
+		if study != "" {
+			require.NoError(t, ds.SetStudyDate(study))
+		}
+		if age != "" {
+			require.NoError(t, ds.SetPatientAge(age))
+		}
+		return ds
+	}
+	patientAge := func(ds *DataSet) string {
+		elem, err := ds.Get(tag.PatientAge)
+		if err != nil {
+			return ""
+		}
+		return elem.Value().String()
+	}
+
+	t.Run("computes missing age", func(t *testing.T) {
+		ds := newDS(t, "19800115", "20231014", "")
+		require.NoError(t, ds.FillPatientAge())
+		assert.Equal(t, "043Y", patientAge(ds))
+	})
+
+	t.Run("infant age in days", func(t *testing.T) {
+		ds := newDS(t, "20231001", "20231014", "")
+		require.NoError(t, ds.FillPatientAge())
+		assert.Equal(t, "013D", patientAge(ds))
+	})
+
+	t.Run("keeps existing age", func(t *testing.T) {
+		ds := newDS(t, "19800115", "20231014", "040Y")
+		require.NoError(t, ds.FillPatientAge())
+		assert.Equal(t, "040Y", patientAge(ds))
+	})
+
+	t.Run("refresh overwrites existing age", func(t *testing.T) {
+		ds := newDS(t, "19800115", "20231014", "040Y")
+		require.NoError(t, ds.RefreshPatientAge())
+		assert.Equal(t, "043Y", patientAge(ds))
+	})
+
+	t.Run("skips missing dates", func(t *testing.T) {
+		ds := newDS(t, "19800115", "", "")
+		require.NoError(t, ds.FillPatientAge())
+		assert.False(t, ds.Contains(tag.PatientAge))
+	})
+
+	t.Run("skips partial dates", func(t *testing.T) {
+		ds := newDS(t, "", "20231014", "")
+		val, err := value.NewStringValue(vr.Date, []string{"1980"})
+		require.NoError(t, err)
+		elem, err := element.NewElement(tag.PatientBirthDate, vr.Date, val)
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(elem))
+
+		require.NoError(t, ds.FillPatientAge())
+		assert.False(t, ds.Contains(tag.PatientAge))
+	})
+
+	t.Run("study before birth", func(t *testing.T) {
+		ds := newDS(t, "20231014", "20231001", "")
+		assert.Error(t, ds.FillPatientAge())
+		assert.False(t, ds.Contains(tag.PatientAge))
+	})
+}
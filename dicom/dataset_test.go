@@ -1,6 +1,8 @@
 package dicom_test
 
 import (
+	"errors"
+	"path/filepath"
 	"testing"
 
 	dicom "github.com/codeninja55/go-radx/dicom"
@@ -108,7 +110,7 @@ func TestDataSet_Add(t *testing.T) {
 		assert.Contains(t, err.Error(), "nil")
 	})
 
-	t.Run("add duplicate tag replaces", func(t *testing.T) {
+	t.Run("add duplicate tag errors", func(t *testing.T) {
 		ds := dicom.NewDataSet()
 
 		elem1 := mustNewElement(tag.New(0x0010, 0x0010), vr.PersonName,
@@ -117,13 +119,87 @@ func TestDataSet_Add(t *testing.T) {
 			mustNewStringValue(vr.PersonName, []string{"Smith^Jane"}))
 
 		require.NoError(t, ds.Add(elem1))
-		require.NoError(t, ds.Add(elem2))
+		err := ds.Add(elem2)
+		assert.ErrorIs(t, err, dicom.ErrDuplicateTag)
 
-		assert.Equal(t, 1, ds.Len()) // Should still be 1
+		assert.Equal(t, 1, ds.Len())
 
 		retrieved, err := ds.Get(tag.New(0x0010, 0x0010))
 		require.NoError(t, err)
-		assert.Equal(t, "Smith^Jane", retrieved.Value().String())
+		assert.Equal(t, "Doe^John", retrieved.Value().String())
+	})
+}
+
+// TestDataSet_Set tests unconditional insertion and replacement
+func TestDataSet_Set(t *testing.T) {
+	ds := dicom.NewDataSet()
+
+	elem1 := mustNewElement(tag.New(0x0010, 0x0010), vr.PersonName,
+		mustNewStringValue(vr.PersonName, []string{"Doe^John"}))
+	elem2 := mustNewElement(tag.New(0x0010, 0x0010), vr.PersonName,
+		mustNewStringValue(vr.PersonName, []string{"Smith^Jane"}))
+
+	require.NoError(t, ds.Set(elem1))
+	require.NoError(t, ds.Set(elem2))
+	assert.Equal(t, 1, ds.Len())
+
+	retrieved, err := ds.Get(tag.New(0x0010, 0x0010))
+	require.NoError(t, err)
+	assert.Equal(t, "Smith^Jane", retrieved.Value().String())
+
+	assert.Error(t, ds.Set(nil))
+}
+
+// TestDataSet_Update tests read-modify-write of element values
+func TestDataSet_Update(t *testing.T) {
+	newDS := func() *dicom.DataSet {
+		ds := dicom.NewDataSet()
+		require.NoError(t, ds.Add(mustNewElement(tag.New(0x0020, 0x0013), vr.IntegerString,
+			mustNewStringValue(vr.IntegerString, []string{"7"}))))
+		return ds
+	}
+
+	t.Run("updates value and keeps VR", func(t *testing.T) {
+		ds := newDS()
+		err := ds.Update(tag.New(0x0020, 0x0013), func(old value.Value) (value.Value, error) {
+			assert.Equal(t, "7", old.String())
+			return value.NewStringValue(vr.IntegerString, []string{"8"})
+		})
+		require.NoError(t, err)
+
+		elem, err := ds.Get(tag.New(0x0020, 0x0013))
+		require.NoError(t, err)
+		assert.Equal(t, vr.IntegerString, elem.VR())
+		assert.Equal(t, "8", elem.Value().String())
+	})
+
+	t.Run("VR mismatch leaves element unchanged", func(t *testing.T) {
+		ds := newDS()
+		err := ds.Update(tag.New(0x0020, 0x0013), func(old value.Value) (value.Value, error) {
+			return value.NewStringValue(vr.LongString, []string{"8"})
+		})
+		assert.Error(t, err)
+
+		elem, err := ds.Get(tag.New(0x0020, 0x0013))
+		require.NoError(t, err)
+		assert.Equal(t, "7", elem.Value().String())
+	})
+
+	t.Run("function error is returned", func(t *testing.T) {
+		ds := newDS()
+		errBoom := errors.New("boom")
+		err := ds.Update(tag.New(0x0020, 0x0013), func(old value.Value) (value.Value, error) {
+			return nil, errBoom
+		})
+		assert.ErrorIs(t, err, errBoom)
+	})
+
+	t.Run("missing tag", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		err := ds.Update(tag.New(0x0010, 0x0010), func(old value.Value) (value.Value, error) {
+			return old, nil
+		})
+		assert.Error(t, err)
 	})
 }
 
@@ -209,6 +285,88 @@ func TestDataSet_Contains(t *testing.T) {
 	})
 }
 
+// TestDataSet_IsEmpty tests telling absent elements from present but empty ones
+func TestDataSet_IsEmpty(t *testing.T) {
+	ds := dicom.NewDataSet()
+	require.NoError(t, ds.Add(mustNewElement(tag.PatientName, vr.PersonName,
+		mustNewStringValue(vr.PersonName, []string{}))))
+	require.NoError(t, ds.Add(mustNewElement(tag.PatientID, vr.LongString,
+		mustNewStringValue(vr.LongString, []string{"12345"}))))
+	require.NoError(t, ds.Add(mustNewElement(tag.StudyID, vr.ShortString,
+		mustNewStringValue(vr.ShortString, []string{"  "}))))
+	emptySeq, err := value.NewSequenceValue(nil)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(mustNewElement(tag.ReferencedImageSequence, vr.SequenceOfItems, emptySeq)))
+
+	tests := []struct {
+		name    string
+		tag     tag.Tag
+		present bool
+		empty   bool
+	}{
+		{name: "zero length", tag: tag.PatientName, present: true, empty: true},
+		{name: "with value", tag: tag.PatientID, present: true, empty: false},
+		{name: "only padding", tag: tag.StudyID, present: true, empty: true},
+		{name: "sequence without items", tag: tag.ReferencedImageSequence, present: true, empty: true},
+		{name: "absent", tag: tag.PatientSex, present: false, empty: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			present, empty := ds.IsEmpty(tt.tag)
+			assert.Equal(t, tt.present, present)
+			assert.Equal(t, tt.empty, empty)
+		})
+	}
+
+	t.Run("zero-length PatientName round-trips", func(t *testing.T) {
+		built, err := newSecondaryCaptureBuilder().Build()
+		require.NoError(t, err)
+		require.NoError(t, built.Set(mustNewElement(tag.PatientName, vr.PersonName,
+			mustNewStringValue(vr.PersonName, []string{}))))
+
+		path := filepath.Join(t.TempDir(), "empty-name.dcm")
+		require.NoError(t, dicom.WriteFile(path, built))
+		parsed, err := dicom.ParseFile(path)
+		require.NoError(t, err)
+
+		present, empty := parsed.IsEmpty(tag.PatientName)
+		assert.True(t, present, "zero-length PatientName must not be dropped")
+		assert.True(t, empty)
+		assert.NoError(t, dicom.ValidateIOD(parsed))
+	})
+}
+
+// TestDataSet_PersonNameCodeExtensions tests a Japanese person name using ISO 2022
+// code extensions surviving a write and parse.
+func TestDataSet_PersonNameCodeExtensions(t *testing.T) {
+	const charset = `ISO 2022 IR 6\ISO 2022 IR 87`
+	want := value.ParsePersonName("Yamada^Tarou=山田^太郎=やまだ^たろう")
+
+	raw, err := want.Encode(charset)
+	require.NoError(t, err)
+
+	built, err := newSecondaryCaptureBuilder().Build()
+	require.NoError(t, err)
+	require.NoError(t, built.Set(mustNewElement(tag.SpecificCharacterSet, vr.CodeString,
+		mustNewStringValue(vr.CodeString, []string{"ISO 2022 IR 6", "ISO 2022 IR 87"}))))
+	require.NoError(t, built.Set(mustNewElement(tag.PatientName, vr.PersonName,
+		mustNewStringValue(vr.PersonName, []string{string(raw)}))))
+
+	path := filepath.Join(t.TempDir(), "japanese-name.dcm")
+	require.NoError(t, dicom.WriteFile(path, built))
+	parsed, err := dicom.ParseFile(path)
+	require.NoError(t, err)
+
+	elem, err := parsed.Get(tag.PatientName)
+	require.NoError(t, err)
+	name, ok := elem.Value().(*value.StringValue)
+	require.True(t, ok)
+
+	names, err := name.AsPersonName(stringOf(t, parsed, tag.SpecificCharacterSet))
+	require.NoError(t, err)
+	assert.Equal(t, []value.PersonName{want}, names)
+}
+
 // TestDataSet_Remove tests removing elements from a dataset
 func TestDataSet_Remove(t *testing.T) {
 	t.Run("remove existing element", func(t *testing.T) {
@@ -338,6 +496,91 @@ func TestDataSet_Tags(t *testing.T) {
 	})
 }
 
+// TestDataSet_CanonicalOrder tests that Tags and Elements order by group, then element
+func TestDataSet_CanonicalOrder(t *testing.T) {
+	ds := dicom.NewDataSet()
+
+	tags := []tag.Tag{
+		tag.New(0x7FE0, 0x0010),
+		tag.New(0x0011, 0x0001),
+		tag.New(0x0010, 0x1000),
+		tag.New(0x0008, 0x0018),
+		tag.New(0x0010, 0x0010),
+		tag.New(0x0009, 0xFFFF),
+	}
+	for _, tg := range tags {
+		require.NoError(t, ds.Add(mustNewElement(tg, vr.LongString,
+			mustNewStringValue(vr.LongString, []string{"x"}))))
+	}
+
+	expected := []tag.Tag{
+		tag.New(0x0008, 0x0018),
+		tag.New(0x0009, 0xFFFF),
+		tag.New(0x0010, 0x0010),
+		tag.New(0x0010, 0x1000),
+		tag.New(0x0011, 0x0001),
+		tag.New(0x7FE0, 0x0010),
+	}
+
+	// Repeat to catch any dependence on map iteration order
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, expected, ds.Tags())
+
+		elements := ds.Elements()
+		require.Len(t, elements, len(expected))
+		for j, elem := range elements {
+			assert.Equal(t, expected[j], elem.Tag())
+		}
+	}
+}
+
+// TestSequenceItems tests walking nested sequences in canonical order
+func TestSequenceItems(t *testing.T) {
+	item := dicom.NewDataSet()
+	require.NoError(t, item.Add(mustNewElement(tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier,
+		mustNewStringValue(vr.UniqueIdentifier, []string{"1.2.3.4"}))))
+	require.NoError(t, item.Add(mustNewElement(tag.ReferencedSOPClassUID, vr.UniqueIdentifier,
+		mustNewStringValue(vr.UniqueIdentifier, []string{"1.2.840.10008.5.1.4.1.1.2"}))))
+
+	seqElem, err := dicom.NewSequenceElement(tag.ReferencedImageSequence, []*dicom.DataSet{item})
+	require.NoError(t, err)
+
+	ds := dicom.NewDataSet()
+	require.NoError(t, ds.Add(seqElem))
+	require.NoError(t, ds.Add(mustNewElement(tag.PatientID, vr.LongString,
+		mustNewStringValue(vr.LongString, []string{"12345"}))))
+
+	var visited []tag.Tag
+	var walk func(d *dicom.DataSet)
+	walk = func(d *dicom.DataSet) {
+		for _, elem := range d.Elements() {
+			visited = append(visited, elem.Tag())
+			seq, ok := elem.Value().(*value.SequenceValue)
+			if !ok {
+				continue
+			}
+			items, err := dicom.SequenceItems(seq)
+			require.NoError(t, err)
+			for _, it := range items {
+				walk(it)
+			}
+		}
+	}
+	walk(ds)
+
+	assert.Equal(t, []tag.Tag{
+		tag.ReferencedImageSequence,
+		tag.ReferencedSOPClassUID,
+		tag.ReferencedSOPInstanceUID,
+		tag.PatientID,
+	}, visited)
+
+	t.Run("nil sequence", func(t *testing.T) {
+		_, err := dicom.SequenceItems(nil)
+		assert.Error(t, err)
+	})
+}
+
 // TestDataSet_String tests string representation
 func TestDataSet_String(t *testing.T) {
 	t.Run("empty dataset", func(t *testing.T) {
@@ -376,6 +619,34 @@ func TestDataSet_String(t *testing.T) {
 	})
 }
 
+// TestDataSet_EqualsIgnoringPadding tests that padding differences are ignored at every nesting level
+func TestDataSet_EqualsIgnoringPadding(t *testing.T) {
+	build := func(name, uid string) *dicom.DataSet {
+		item := dicom.NewDataSet()
+		require.NoError(t, item.Add(mustNewElement(tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier,
+			mustNewStringValue(vr.UniqueIdentifier, []string{uid}))))
+		seq, err := dicom.NewSequenceElement(tag.ReferencedImageSequence, []*dicom.DataSet{item})
+		require.NoError(t, err)
+
+		ds := dicom.NewDataSet()
+		require.NoError(t, ds.Add(mustNewElement(tag.PatientName, vr.PersonName,
+			mustNewStringValue(vr.PersonName, []string{name}))))
+		require.NoError(t, ds.Add(seq))
+		return ds
+	}
+
+	a := build("Doe^John", "1.2.3")
+	b := build("Doe^John ", "1.2.3\x00")
+
+	assert.False(t, a.Equals(b))
+	assert.True(t, a.EqualsIgnoringPadding(b))
+	assert.True(t, b.EqualsIgnoringPadding(a))
+
+	assert.False(t, a.EqualsIgnoringPadding(build("Doe^Jane", "1.2.3")))
+	assert.False(t, a.EqualsIgnoringPadding(build("Doe^John", "1.2.4")))
+	assert.False(t, a.EqualsIgnoringPadding(dicom.NewDataSet()))
+}
+
 // TestDataSet_Copy tests copying a dataset
 func TestDataSet_Copy(t *testing.T) {
 	t.Run("copy empty dataset", func(t *testing.T) {
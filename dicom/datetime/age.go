@@ -27,11 +27,19 @@ type Age struct {
 
 	// Unit is the time unit (Days, Weeks, Months, or Years).
 	Unit AgeUnit
+
+	// Original is the input string when ParseAgeLax accepted a non-canonical form
+	// such as "42Y" or "5y", and empty otherwise. DCM always emits the canonical
+	// form; write Original back instead to preserve the source value unchanged.
+	Original string
 }
 
 var (
 	// ageRegex matches DICOM AS format: nnnU where nnn is 000-999, U is D/W/M/Y
 	ageRegex = regexp.MustCompile(`^(\d{3})([DWMY])$`)
+
+	// laxAgeRegex matches 1-3 digits, optional spaces and a single letter unit
+	laxAgeRegex = regexp.MustCompile(`^(\d{1,3}) *([A-Za-z])$`)
 )
 
 // ParseAge parses a DICOM Age String (AS) into an Age struct.
@@ -64,6 +72,51 @@ func ParseAge(s string) (Age, error) {
 	return parseAgeComponents(s, matches)
 }
 
+// ParseAgeLax parses an Age String (AS) like ParseAge, but also accepts the
+// non-conformant forms found in real data:
+//   - 1-3 digit counts without zero padding ("42Y", "5D")
+//   - lowercase units ("042y", "5w")
+//   - spaces between the count and the unit ("042 Y")
+//
+// Canonical input parses exactly as with ParseAge. For non-canonical input the
+// trimmed original string is kept in Age.Original and IsLax reports true. Units other
+// than D, W, M and Y are still rejected.
+//
+// Examples:
+//
+//	age, err := ParseAgeLax("42Y")    // 42 years, age.DCM() == "042Y"
+//	age, err := ParseAgeLax("5y")     // 5 years
+//	age, err := ParseAgeLax("042 Y")  // 42 years
+//	age, err := ParseAgeLax("042H")   // error: invalid unit 'H'
+func ParseAgeLax(s string) (Age, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Age{}, newParseError("AS", s, "empty input")
+	}
+
+	if matches := ageRegex.FindStringSubmatch(s); matches != nil {
+		return parseAgeComponents(s, matches)
+	}
+
+	matches := laxAgeRegex.FindStringSubmatch(s)
+	if matches == nil {
+		return Age{}, newParseError("AS", s, "invalid format (expected 1-3 digits followed by D, W, M, or Y)")
+	}
+	matches[2] = strings.ToUpper(matches[2])
+
+	age, err := parseAgeComponents(s, matches)
+	if err != nil {
+		return Age{}, err
+	}
+	age.Original = s
+	return age, nil
+}
+
+// IsLax reports whether the age was parsed from non-canonical input by ParseAgeLax.
+func (a Age) IsLax() bool {
+	return a.Original != ""
+}
+
 // parseAgeComponents parses individual age components from regex matches.
 func parseAgeComponents(input string, matches []string) (Age, error) {
 	// matches[0] = full match
@@ -100,6 +153,62 @@ func parseAgeComponents(input string, matches []string) (Age, error) {
 	}, nil
 }
 
+// AgeBetween returns the age on date at of someone born on date birth, expressed in
+// the conventional unit for that age:
+//   - under 1 month: completed days ("012D")
+//   - under 3 months: completed weeks ("009W")
+//   - under 2 years: completed months ("018M")
+//   - otherwise: completed years ("042Y")
+//
+// Months and years are counted on the calendar, so an age of one year is reached on
+// the first anniversary of the birth date. Values above 999 are capped at 999.
+//
+// Both dates must have day precision; an error is returned if either is less
+// precise or if at is before birth.
+//
+// Examples:
+//
+//	birth, _ := ParseDate("19800115")
+//	study, _ := ParseDate("20231014")
+//	age, err := AgeBetween(birth, study)  // 043Y
+func AgeBetween(birth, at Date) (Age, error) {
+	if birth.Precision != PrecisionDay || at.Precision != PrecisionDay {
+		return Age{}, newFormatError("AS", fmt.Sprintf("dates must have day precision, got %s and %s",
+			birth.Precision, at.Precision))
+	}
+
+	by, bm, bd := birth.Time.Date()
+	ay, am, ad := at.Time.Date()
+	start := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	end := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	if end.Before(start) {
+		return Age{}, newFormatError("AS", fmt.Sprintf("date %s is before birth date %s",
+			end.Format("20060102"), start.Format("20060102")))
+	}
+
+	// Completed calendar months
+	months := (ay-by)*12 + int(am-bm)
+	if ad < bd {
+		months--
+	}
+	days := int(end.Sub(start).Hours() / 24)
+
+	var age Age
+	switch {
+	case months < 1:
+		age = Age{Value: days, Unit: Days}
+	case months < 3:
+		age = Age{Value: days / 7, Unit: Weeks}
+	case months < 24:
+		age = Age{Value: months, Unit: Months}
+	default:
+		age = Age{Value: months / 12, Unit: Years}
+	}
+	age.Value = min(age.Value, 999)
+
+	return age, nil
+}
+
 // Duration converts the age to a time.Duration using standard medical factors.
 //
 // Conversion factors:
@@ -143,7 +252,8 @@ func (a Age) Duration() time.Duration {
 
 // DCM returns the age in DICOM AS format.
 //
-// Output format: nnnU where nnn is zero-padded to 3 digits.
+// Output format: nnnU where nnn is zero-padded to 3 digits. The canonical form is
+// emitted even for ages parsed leniently by ParseAgeLax.
 //
 // Examples:
 //
@@ -340,3 +340,109 @@ func TestAge_RoundTrip(t *testing.T) {
 		})
 	}
 }
+
+// TestAgeBetween tests computing ages in the conventional unit.
+func TestAgeBetween(t *testing.T) {
+	tests := []struct {
+		name  string
+		birth string
+		at    string
+		want  string
+	}{
+		{"same day", "20230101", "20230101", "000D"},
+		{"days", "20230101", "20230120", "019D"},
+		{"one month is weeks", "20230101", "20230201", "004W"},
+		{"weeks", "20230101", "20230315", "010W"},
+		{"three months", "20230101", "20230401", "003M"},
+		{"months", "20220115", "20231014", "020M"},
+		{"two years", "20211014", "20231014", "002Y"},
+		{"day before birthday", "19800115", "20230114", "042Y"},
+		{"on birthday", "19800115", "20230115", "043Y"},
+		{"leap day birthday", "20000229", "20230228", "022Y"},
+		{"capped at 999", "00010101", "20230101", "999Y"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			birth, err := ParseDate(tt.birth)
+			require.NoError(t, err)
+			at, err := ParseDate(tt.at)
+			require.NoError(t, err)
+
+			age, err := AgeBetween(birth, at)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, age.DCM())
+		})
+	}
+}
+
+// TestAgeBetween_Errors tests rejected date combinations.
+func TestAgeBetween_Errors(t *testing.T) {
+	full, err := ParseDate("20230101")
+	require.NoError(t, err)
+	monthOnly, err := ParseDate("202301")
+	require.NoError(t, err)
+	earlier, err := ParseDate("20221231")
+	require.NoError(t, err)
+
+	_, err = AgeBetween(monthOnly, full)
+	assert.Error(t, err)
+
+	_, err = AgeBetween(full, monthOnly)
+	assert.Error(t, err)
+
+	_, err = AgeBetween(full, earlier)
+	assert.Error(t, err)
+}
+
+// TestParseAgeLax tests lenient parsing of non-conformant age strings.
+func TestParseAgeLax(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Age
+		wantDCM string
+		lax     bool
+	}{
+		{"canonical", "042Y", Age{Value: 42, Unit: Years}, "042Y", false},
+		{"not zero padded", "42Y", Age{Value: 42, Unit: Years, Original: "42Y"}, "042Y", true},
+		{"single digit lowercase", "5y", Age{Value: 5, Unit: Years, Original: "5y"}, "005Y", true},
+		{"internal space", "042 Y", Age{Value: 42, Unit: Years, Original: "042 Y"}, "042Y", true},
+		{"lowercase weeks", "3w", Age{Value: 3, Unit: Weeks, Original: "3w"}, "003W", true},
+		{"surrounding whitespace", " 7 d ", Age{Value: 7, Unit: Days, Original: "7 d"}, "007D", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			age, err := ParseAgeLax(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, age)
+			assert.Equal(t, tt.wantDCM, age.DCM())
+			assert.Equal(t, tt.lax, age.IsLax())
+		})
+	}
+}
+
+// TestParseAgeLax_Invalid tests that lenient parsing still rejects malformed ages.
+func TestParseAgeLax_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"empty", "", "empty"},
+		{"invalid unit", "042H", "invalid unit 'H'"},
+		{"invalid lowercase unit", "5h", "invalid unit 'H'"},
+		{"too many digits", "1000Y", "invalid format"},
+		{"no unit", "42", "invalid format"},
+		{"negative", "-5Y", "invalid format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAgeLax(tt.input)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -315,6 +315,64 @@ func (dt DateTime) DCM() string {
 	return base
 }
 
+// ToUTC returns the datetime shifted to UTC, with a "+0000" offset.
+//
+// The instant in time is unchanged; only the wall clock and offset move. Precision is
+// kept, so values coarser than hours are shifted at their implicit start (midnight for
+// day precision) and may land on the previous or next calendar day.
+//
+// Returns an error if NoOffset is true: without an offset the zone is unknown and the
+// value cannot be placed on the UTC timeline. Use WithOffset to assign one first.
+//
+// Example:
+//
+//	dt, _ := ParseDateTime("20231015143025+1000")
+//	utc, err := dt.ToUTC()
+//	fmt.Println(utc.DCM()) // "20231015043025+0000"
+func (dt DateTime) ToUTC() (DateTime, error) {
+	if dt.NoOffset {
+		return DateTime{}, newFormatError("DT", "cannot convert to UTC without a timezone offset")
+	}
+	dt.Time = dt.Time.UTC()
+	return dt, nil
+}
+
+// WithOffset returns the datetime expressed in a fixed UTC offset of seconds.
+//
+// If the datetime has an offset, it is converted: the instant stays the same and the
+// wall clock moves to the new zone. If NoOffset is true, the value is reinterpreted:
+// the wall clock is kept and the offset is attached, which is how local timestamps
+// from a site with a known zone are labelled. The result always has NoOffset false.
+//
+// Example:
+//
+//	dt, _ := ParseDateTime("20231015143025+1000")
+//	fmt.Println(dt.WithOffset(-5 * 3600).DCM()) // "20231014233025-0500"
+//
+//	local, _ := ParseDateTime("20231015143025")
+//	fmt.Println(local.WithOffset(3600).DCM()) // "20231015143025+0100"
+func (dt DateTime) WithOffset(seconds int) DateTime {
+	loc := time.FixedZone(formatOffset(seconds), seconds)
+	if dt.NoOffset {
+		t := dt.Time
+		dt.Time = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	} else {
+		dt.Time = dt.Time.In(loc)
+	}
+	dt.NoOffset = false
+	return dt
+}
+
+// formatOffset formats a UTC offset in seconds as &ZZXX.
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, (seconds%3600)/60)
+}
+
 // String returns a human-readable representation of the datetime.
 //
 // Format: YYYY-MM-DD HH:MM:SS[.FFFFFF] ZONE
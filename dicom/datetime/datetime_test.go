@@ -535,3 +535,76 @@ func TestDateTime_RoundTrip(t *testing.T) {
 		})
 	}
 }
+
+// TestDateTime_ToUTC tests shifting datetimes with an offset to UTC.
+func TestDateTime_ToUTC(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"positive offset", "20231015143025+1000", "20231015043025+0000"},
+		{"negative offset crosses midnight", "20231015203025.123-0500", "20231016013025.123+0000"},
+		{"already UTC", "20231015143025+0000", "20231015143025+0000"},
+		{"half hour offset", "202310151430+0530", "202310150900+0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt, err := ParseDateTime(tt.input)
+			require.NoError(t, err)
+
+			utc, err := dt.ToUTC()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, utc.DCM())
+			assert.False(t, utc.NoOffset)
+			assert.Equal(t, dt.Precision, utc.Precision)
+			assert.True(t, dt.Time.Equal(utc.Time), "instant must not change")
+		})
+	}
+}
+
+// TestDateTime_ToUTC_NoOffset tests that datetimes without an offset cannot be converted.
+func TestDateTime_ToUTC_NoOffset(t *testing.T) {
+	dt, err := ParseDateTime("20231015143025")
+	require.NoError(t, err)
+
+	_, err = dt.ToUTC()
+	require.Error(t, err)
+	var formatErr *FormatError
+	assert.ErrorAs(t, err, &formatErr)
+}
+
+// TestDateTime_WithOffset tests converting and reinterpreting datetimes in a fixed offset.
+func TestDateTime_WithOffset(t *testing.T) {
+	t.Run("converts datetime with offset", func(t *testing.T) {
+		dt, err := ParseDateTime("20231015143025+1000")
+		require.NoError(t, err)
+
+		shifted := dt.WithOffset(-5 * 3600)
+		assert.Equal(t, "20231014233025-0500", shifted.DCM())
+		assert.True(t, dt.Time.Equal(shifted.Time))
+	})
+
+	t.Run("reinterprets datetime without offset", func(t *testing.T) {
+		dt, err := ParseDateTime("20231015143025")
+		require.NoError(t, err)
+
+		local := dt.WithOffset(3600)
+		assert.False(t, local.NoOffset)
+		assert.Equal(t, "20231015143025+0100", local.DCM())
+
+		utc, err := local.ToUTC()
+		require.NoError(t, err)
+		assert.Equal(t, "20231015133025+0000", utc.DCM())
+	})
+
+	t.Run("round trips through DCM", func(t *testing.T) {
+		dt, err := ParseDateTime("20231015143025.5+0930")
+		require.NoError(t, err)
+
+		parsed, err := ParseDateTime(dt.WithOffset(-(3*3600 + 30*60)).DCM())
+		require.NoError(t, err)
+		assert.True(t, dt.Time.Equal(parsed.Time))
+	})
+}
@@ -42,6 +42,19 @@ type ParseDirectoryOptions struct {
 	// Context allows cancellation of the parsing operation.
 	// If nil, a background context will be used.
 	Context context.Context
+
+	// ValueCacheBytes bounds the memory held by large binary values, such as Pixel
+	// Data, across all parsed datasets. If positive, top-level OB, OD, OF, OL, OV,
+	// OW and UN values of 1 KiB or more are not kept after parsing but read back
+	// from their file on first access (see element.NewLazyElement). Once the loaded
+	// values exceed ValueCacheBytes, the least recently loaded are dropped again
+	// and reread on their next access. This lets a browser keep the metadata of a
+	// large collection in memory while only recently viewed pixel data is resident.
+	//
+	// Values of deflated datasets are always kept in memory. A single value larger
+	// than the budget is kept until another value is loaded.
+	// Default: 0 (all values stay in memory)
+	ValueCacheBytes int
 }
 
 // ParseResult contains the results of a directory parsing operation.
@@ -249,12 +262,17 @@ func parseFileConcurrently(files []string, opts ParseDirectoryOptions) *ParseRes
 	results := make(chan parseFileResult, len(files))
 
 	// Start worker pool
+	var cache *valueCache
+	if opts.ValueCacheBytes > 0 {
+		cache = newValueCache(int64(opts.ValueCacheBytes))
+	}
+
 	var wg sync.WaitGroup
 	for w := 0; w < opts.Workers; w++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			parseWorker(jobs, results, opts.Context)
+			parseWorker(jobs, results, opts.Context, cache)
 		}()
 	}
 
@@ -294,7 +312,7 @@ func parseFileConcurrently(files []string, opts ParseDirectoryOptions) *ParseRes
 			}
 		} else {
 			// Add dataset to collection
-			if err := collection.Add(result.dataset); err != nil {
+			if err := collection.AddOrRecord(result.dataset, result.path); err != nil {
 				failed++
 				errorsMu.Lock()
 				errors[result.path] = fmt.Errorf("failed to add to collection: %w", err)
@@ -328,8 +346,9 @@ type parseFileResult struct {
 }
 
 // parseWorker is a worker goroutine that parses files from the jobs channel
-// and sends results to the results channel.
-func parseWorker(jobs <-chan string, results chan<- parseFileResult, ctx context.Context) {
+// and sends results to the results channel. If cache is not nil, large binary values
+// are made lazy and their loaded size is bounded by the cache.
+func parseWorker(jobs <-chan string, results chan<- parseFileResult, ctx context.Context, cache *valueCache) {
 	for filePath := range jobs {
 		// Check for cancellation
 		select {
@@ -342,8 +361,11 @@ func parseWorker(jobs <-chan string, results chan<- parseFileResult, ctx context
 		default:
 		}
 
-		// Parse the file
-		dataset, err := ParseFile(filePath)
+		// Parse the file (aborts mid-file if the context is cancelled)
+		dataset, err := ParseFileWithOptions(filePath, ParseOptions{Context: ctx, TrackOffsets: cache != nil})
+		if err == nil && cache != nil {
+			err = cache.makeLazy(dataset, filePath)
+		}
 		results <- parseFileResult{
 			path:    filePath,
 			dataset: dataset,
@@ -1,6 +1,7 @@
 package dicom
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -11,7 +12,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/codeninja55/go-radx/dicom/element"
 	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -426,6 +429,10 @@ func TestParseDirectory_DataSetCollection(t *testing.T) {
 		if err == nil {
 			sopInstanceUID := elem.Value().String()
 			assert.NotEmpty(t, sopInstanceUID, "SOPInstanceUID should not be empty")
+
+			// Source file is recorded for each dataset
+			path := result.Collection.FilePath(sopInstanceUID)
+			assert.True(t, strings.HasSuffix(path, ".dcm"), "FilePath should be the parsed file, got %q", path)
 		}
 	}
 }
@@ -472,6 +479,105 @@ func TestParseDirectory_MixedContent(t *testing.T) {
 	}
 }
 
+// writeLazyTestFiles writes n secondary capture files of 64x64 8-bit pixels to a
+// temporary directory, the pixels of file i all having the value i+1.
+func writeLazyTestFiles(t *testing.T, n int) string {
+	t.Helper()
+	dir := t.TempDir()
+	for i := 0; i < n; i++ {
+		ds, err := NewBuilder(uid.SecondaryCaptureImageStorage).
+			PatientName("Doe^John").PatientID("12345").ConversionType("WSD").
+			Rows(64).Columns(64).
+			SamplesPerPixel(1).PhotometricInterpretation("MONOCHROME2").
+			BitsAllocated(8).BitsStored(8).HighBit(7).PixelRepresentation(0).
+			PixelData(bytes.Repeat([]byte{byte(i + 1)}, 64*64)).
+			Build()
+		require.NoError(t, err)
+		require.NoError(t, WriteFile(filepath.Join(dir, fmt.Sprintf("image%d.dcm", i)), ds))
+	}
+	return dir
+}
+
+// TestParseDirectoryWithOptions_ValueCacheBytes tests that loaded pixel data beyond
+// the budget is evicted least recently loaded first and reread on access.
+func TestParseDirectoryWithOptions_ValueCacheBytes(t *testing.T) {
+	dir := writeLazyTestFiles(t, 4)
+
+	// Room for two 4 KiB images
+	result, err := ParseDirectoryWithOptions(dir, ParseDirectoryOptions{ValueCacheBytes: 10000})
+	require.NoError(t, err)
+	require.Equal(t, 4, result.Parsed)
+
+	pixels := make([]*element.Element, 4)
+	for _, ds := range result.Collection.DataSets() {
+		path := result.Collection.FilePath(stringValue(ds, tag.SOPInstanceUID))
+		var i int
+		_, err := fmt.Sscanf(filepath.Base(path), "image%d.dcm", &i)
+		require.NoError(t, err)
+		pixels[i], err = ds.Get(tag.PixelData)
+		require.NoError(t, err)
+
+		assert.True(t, pixels[i].IsLazy())
+		assert.False(t, pixels[i].IsLoaded(), "pixel data is not resident after parsing")
+		rows, err := ds.Get(tag.Rows)
+		require.NoError(t, err)
+		assert.False(t, rows.IsLazy(), "small values stay in memory")
+	}
+
+	loaded := func() []bool {
+		states := make([]bool, len(pixels))
+		for i, p := range pixels {
+			states[i] = p.IsLoaded()
+		}
+		return states
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, bytes.Repeat([]byte{byte(i + 1)}, 64*64), pixels[i].Value().Bytes())
+	}
+	assert.Equal(t, []bool{false, true, true, false}, loaded(), "the first image loaded is evicted")
+
+	// An evicted value is reread from disk, evicting the next oldest
+	assert.Equal(t, bytes.Repeat([]byte{1}, 64*64), pixels[0].Value().Bytes())
+	assert.Equal(t, []bool{true, false, true, false}, loaded())
+
+	// Accessing a resident value does not reload it
+	pixels[2].Value()
+	assert.Equal(t, []bool{true, false, true, false}, loaded())
+}
+
+// TestParseDirectoryWithOptions_ValueCacheMissingFile tests that writing or signing
+// a dataset fails, rather than losing its pixel data, when an evicted value cannot
+// be reread because its file was removed.
+func TestParseDirectoryWithOptions_ValueCacheMissingFile(t *testing.T) {
+	dir := writeLazyTestFiles(t, 1)
+	result, err := ParseDirectoryWithOptions(dir, ParseDirectoryOptions{ValueCacheBytes: 10000})
+	require.NoError(t, err)
+	ds := result.Collection.DataSets()[0]
+
+	pixels, err := ds.Get(tag.PixelData)
+	require.NoError(t, err)
+	require.NoError(t, pixels.Load())
+	pixels.Unload()
+	require.NoError(t, os.Remove(result.Collection.FilePath(stringValue(ds, tag.SOPInstanceUID))))
+
+	err = WriteFile(filepath.Join(t.TempDir(), "copy.dcm"), ds)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.ErrorIs(t, SignDataset(ds, SignOptions{Key: []byte("key")}), os.ErrNotExist)
+}
+
+// TestParseDirectoryWithOptions_ValueCacheDisabled tests that values stay in
+// memory without a cache budget.
+func TestParseDirectoryWithOptions_ValueCacheDisabled(t *testing.T) {
+	result, err := ParseDirectory(writeLazyTestFiles(t, 2))
+	require.NoError(t, err)
+	for _, ds := range result.Collection.DataSets() {
+		elem, err := ds.Get(tag.PixelData)
+		require.NoError(t, err)
+		assert.False(t, elem.IsLazy())
+	}
+}
+
 // TestApplyDefaultOptions tests that default options are correctly applied.
 func TestApplyDefaultOptions(t *testing.T) {
 	testCases := []struct {
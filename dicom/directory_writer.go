@@ -158,8 +158,11 @@ func applyDefaultDirectoryWriteOptions(opts DirectoryWriteOptions) DirectoryWrit
 		opts.PatientFolderNaming = defaultPatientFolderNaming
 	}
 
-	// Apply defaults for nested WriteOptions
+	// Apply defaults for nested WriteOptions. An unset TransferSyntax stays nil so
+	// each file defaults to the transfer syntax its own Pixel Data requires.
+	transferSyntax := opts.WriteOptions.TransferSyntax
 	opts.WriteOptions = applyDefaultWriteOptions(opts.WriteOptions)
+	opts.WriteOptions.TransferSyntax = transferSyntax
 
 	// For directory operations, we need CreateDirs to be true to create
 	// the hierarchical directory structure. Override if not explicitly set.
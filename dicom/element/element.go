@@ -26,9 +26,12 @@ import (
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1
 type Element struct {
-	tag   tag.Tag
-	vr    vr.VR
-	value value.Value
+	tag      tag.Tag
+	vr       vr.VR
+	value    value.Value
+	location *Location  // Source position, set only when parsed with offset tracking
+	raw      []byte     // Encoded value field, set only when parsed with raw value retention
+	lazy     *lazyValue // Loader of the value, set only for elements created with NewLazyElement
 }
 
 // NewElement creates a new DICOM data element.
@@ -71,8 +74,17 @@ func (e *Element) VR() vr.VR {
 
 // Value returns the value of this element.
 // Similar to pydicom's DataElement.value property.
+//
+// The value of a lazy element (see NewLazyElement) is loaded on first access. If it
+// cannot be loaded, an empty value of the element's VR is returned; use Load to get
+// the error.
 func (e *Element) Value() value.Value {
-	return e.value
+	v, err := e.loadValue()
+	if err != nil {
+		empty, _ := value.NewBytesValue(e.vr, nil) //nolint:errcheck // Lazy elements have binary VRs
+		return empty
+	}
+	return v
 }
 
 // Name returns the human-readable name of this element from the DICOM dictionary.
@@ -111,7 +123,7 @@ func (e *Element) Keyword() string {
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.4
 func (e *Element) ValueMultiplicity() string {
 	// Count values based on type
-	switch v := e.value.(type) {
+	switch v := e.Value().(type) {
 	case *value.StringValue:
 		return fmt.Sprintf("%d", len(v.Strings()))
 	case *value.IntValue:
@@ -157,7 +169,7 @@ func (e *Element) String() string {
 
 	// Value
 	sb.WriteString("= ")
-	valueStr := e.value.String()
+	valueStr := e.Value().String()
 
 	// Truncate very long values for display
 	const maxValueLen = 80
@@ -192,7 +204,14 @@ func (e *Element) SetValue(val value.Value) error {
 		return fmt.Errorf("value VR %s does not match element VR %s", val.VR().String(), e.vr.String())
 	}
 
+	if e.lazy != nil {
+		// The element now holds its value in memory for good
+		e.lazy.mu.Lock()
+		defer e.lazy.mu.Unlock()
+		e.lazy.load = nil
+	}
 	e.value = val
+	e.raw = nil // The encoded form no longer matches the value
 	return nil
 }
 
@@ -215,5 +234,18 @@ func (e *Element) Equals(other *Element) bool {
 	}
 
 	// Compare values using Value.Equals()
-	return e.value.Equals(other.value)
+	return e.Value().Equals(other.Value())
+}
+
+// EqualsIgnoringPadding is like Equals but compares values with
+// value.EqualsIgnoringPadding, so elements differing only in trailing value padding
+// are equal.
+func (e *Element) EqualsIgnoringPadding(other *Element) bool {
+	if other == nil {
+		return false
+	}
+	if !e.tag.Equals(other.tag) || e.vr != other.vr {
+		return false
+	}
+	return value.EqualsIgnoringPadding(e.Value(), other.Value())
 }
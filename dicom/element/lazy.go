@@ -0,0 +1,137 @@
+package element
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// lazyValue loads the value of a lazy element on demand. mu guards the element's
+// value, which is nil while the value is not loaded.
+type lazyValue struct {
+	mu     sync.Mutex
+	load   func() (value.Value, error) // nil once the element holds a value set by SetValue
+	loaded func(*Element, value.Value) // optional, called after each load
+}
+
+// NewLazyElement creates an element whose value is read by load the first time it
+// is needed, instead of being held in memory from the start.
+//
+// The value is loaded by Value or Load, and can be dropped again with Unload, after
+// which the next access calls load again. load must return a value of VR v each time
+// it is called. If loaded is not nil it is called after every successful load, with
+// no lock held, which lets a cache track resident values and Unload others.
+//
+// Lazy elements are used for large binary values such as Pixel Data, so v must be
+// a binary VR (OB, OD, OF, OL, OV, OW or UN).
+//
+// Returns an error if:
+//   - v is not a binary VR
+//   - load is nil
+//
+// Example:
+//
+//	elem, err := element.NewLazyElement(tag.PixelData, vr.OtherWord, func() (value.Value, error) {
+//	    data, err := readPixelData(path)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return value.NewBytesValue(vr.OtherWord, data)
+//	}, nil)
+func NewLazyElement(t tag.Tag, v vr.VR, load func() (value.Value, error), loaded func(*Element, value.Value)) (*Element, error) {
+	if _, err := value.NewBytesValue(v, nil); err != nil {
+		return nil, fmt.Errorf("lazy element %s: %w", t, err)
+	}
+	if load == nil {
+		return nil, fmt.Errorf("lazy element %s: load function cannot be nil", t)
+	}
+
+	return &Element{
+		tag:  t,
+		vr:   v,
+		lazy: &lazyValue{load: load, loaded: loaded},
+	}, nil
+}
+
+// IsLazy reports whether the element loads its value on demand. It is false for
+// elements created with NewElement and for lazy elements given a value by SetValue.
+func (e *Element) IsLazy() bool {
+	if e.lazy == nil {
+		return false
+	}
+	e.lazy.mu.Lock()
+	defer e.lazy.mu.Unlock()
+	return e.lazy.load != nil
+}
+
+// IsLoaded reports whether the element's value is held in memory. It is always true
+// for elements that are not lazy.
+func (e *Element) IsLoaded() bool {
+	if e.lazy == nil {
+		return true
+	}
+	e.lazy.mu.Lock()
+	defer e.lazy.mu.Unlock()
+	return e.value != nil
+}
+
+// Load reads the value of a lazy element if it is not already in memory. It does
+// nothing for elements that are not lazy.
+//
+// Value calls Load itself; call Load directly to find out why a value could not be
+// read, since Value then returns an empty value instead.
+func (e *Element) Load() error {
+	_, err := e.loadValue()
+	return err
+}
+
+// Unload drops the value of a lazy element from memory. The next access reads it
+// again. It does nothing for elements that are not lazy, so values that exist only
+// in memory are never lost.
+func (e *Element) Unload() {
+	if e.lazy == nil {
+		return
+	}
+	e.lazy.mu.Lock()
+	defer e.lazy.mu.Unlock()
+	if e.lazy.load != nil {
+		e.value = nil
+	}
+}
+
+// loadValue returns the element's value, loading it first if it is lazy and not in
+// memory.
+func (e *Element) loadValue() (value.Value, error) {
+	l := e.lazy
+	if l == nil {
+		return e.value, nil
+	}
+
+	l.mu.Lock()
+	if e.value != nil {
+		v := e.value
+		l.mu.Unlock()
+		return v, nil
+	}
+	v, err := l.load()
+	if err == nil && v == nil {
+		err = fmt.Errorf("load returned no value")
+	}
+	if err == nil && v.VR() != e.vr {
+		err = fmt.Errorf("loaded value VR %s does not match element VR %s", v.VR().String(), e.vr.String())
+	}
+	if err != nil {
+		l.mu.Unlock()
+		return nil, fmt.Errorf("failed to load value of %s: %w", e.tag, err)
+	}
+	e.value = v
+	l.mu.Unlock()
+
+	if l.loaded != nil {
+		l.loaded(e, v)
+	}
+	return v, nil
+}
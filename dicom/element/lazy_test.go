@@ -0,0 +1,91 @@
+package element
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLazyElement(t *testing.T) {
+	loads := 0
+	var hooked []value.Value
+	elem, err := NewLazyElement(tag.PixelData, vr.OtherByte, func() (value.Value, error) {
+		loads++
+		return value.NewBytesValue(vr.OtherByte, []byte{1, 2, 3, 4})
+	}, func(e *Element, v value.Value) {
+		assert.Equal(t, tag.PixelData, e.Tag())
+		hooked = append(hooked, v)
+	})
+	require.NoError(t, err)
+
+	assert.True(t, elem.IsLazy())
+	assert.False(t, elem.IsLoaded())
+	assert.Equal(t, 0, loads, "nothing is read until the value is needed")
+
+	assert.Equal(t, []byte{1, 2, 3, 4}, elem.Value().Bytes())
+	assert.Equal(t, []byte{1, 2, 3, 4}, elem.Value().Bytes())
+	assert.True(t, elem.IsLoaded())
+	assert.Equal(t, 1, loads)
+	assert.Len(t, hooked, 1)
+
+	elem.Unload()
+	assert.False(t, elem.IsLoaded())
+	require.NoError(t, elem.Load())
+	assert.True(t, elem.IsLoaded())
+	assert.Equal(t, 2, loads, "an unloaded value is read again")
+	assert.Len(t, hooked, 2)
+
+	// A value set in memory is never dropped
+	replacement, err := value.NewBytesValue(vr.OtherByte, []byte{9, 8})
+	require.NoError(t, err)
+	require.NoError(t, elem.SetValue(replacement))
+	assert.False(t, elem.IsLazy())
+	elem.Unload()
+	assert.True(t, elem.IsLoaded())
+	assert.Equal(t, []byte{9, 8}, elem.Value().Bytes())
+	assert.Equal(t, 2, loads)
+}
+
+func TestNewLazyElement_Errors(t *testing.T) {
+	load := func() (value.Value, error) { return value.NewBytesValue(vr.OtherByte, nil) }
+
+	_, err := NewLazyElement(tag.PatientName, vr.PersonName, load, nil)
+	assert.Error(t, err, "only binary VRs can be lazy")
+
+	_, err = NewLazyElement(tag.PixelData, vr.OtherByte, nil, nil)
+	assert.Error(t, err)
+
+	// A failed load leaves the element unloaded and Value empty
+	errDisk := errors.New("disk gone")
+	elem, err := NewLazyElement(tag.PixelData, vr.OtherByte, func() (value.Value, error) {
+		return nil, errDisk
+	}, nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, elem.Load(), errDisk)
+	assert.Empty(t, elem.Value().Bytes())
+	assert.False(t, elem.IsLoaded())
+
+	// So does a value of the wrong VR
+	elem, err = NewLazyElement(tag.PixelData, vr.OtherWord, load, nil)
+	require.NoError(t, err)
+	assert.Error(t, elem.Load())
+	assert.Equal(t, vr.OtherWord, elem.Value().VR())
+}
+
+func TestElement_NotLazy(t *testing.T) {
+	val, err := value.NewStringValue(vr.LongString, []string{"PAT001"})
+	require.NoError(t, err)
+	elem, err := NewElement(tag.PatientID, vr.LongString, val)
+	require.NoError(t, err)
+
+	assert.False(t, elem.IsLazy())
+	assert.True(t, elem.IsLoaded())
+	require.NoError(t, elem.Load())
+	elem.Unload()
+	assert.Equal(t, val, elem.Value())
+}
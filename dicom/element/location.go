@@ -0,0 +1,66 @@
+package element
+
+// UndefinedLength is the value length field used for sequences, items and
+// encapsulated pixel data whose length is given by delimiters instead.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1.1
+const UndefinedLength = uint32(0xFFFFFFFF)
+
+// Location records where an element was found in the stream it was parsed from.
+//
+// All offsets are absolute byte positions from the start of the stream, i.e. the
+// first byte of the 128-byte preamble of a Part 10 file is offset 0.
+//
+//   - Offset is the position of the first byte of the tag (start of the element).
+//   - ValueOffset is the position of the first byte of the value field, after the
+//     tag, VR (explicit VR only), reserved bytes and length field.
+//   - Length is the total number of bytes the element occupies on disk, from Offset
+//     to the end of the value, including any item and delimitation items of
+//     undefined-length sequences or encapsulated pixel data.
+//   - ValueLength is the value length field exactly as encoded, which is
+//     UndefinedLength (0xFFFFFFFF) for delimited values.
+//
+// For a defined-length element the value occupies the byte range
+// [ValueOffset, ValueOffset+ValueLength), which can be served with a single
+// byte-range read.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1
+type Location struct {
+	Offset      int64
+	ValueOffset int64
+	Length      int64
+	ValueLength uint32
+}
+
+// HeaderLength returns the number of bytes between the start of the tag and the
+// start of the value.
+func (l Location) HeaderLength() int64 {
+	return l.ValueOffset - l.Offset
+}
+
+// Location returns the element's position in its source stream.
+//
+// The second return value is false if the element was not parsed with offset
+// tracking enabled (see dicom.ParseOptions.TrackOffsets) or was created in memory.
+//
+// Example:
+//
+//	ds, _ := dicom.ParseFileWithOptions("image.dcm", dicom.ParseOptions{TrackOffsets: true})
+//	elem, _ := ds.Get(tag.PixelData)
+//	if loc, ok := elem.Location(); ok {
+//	    fmt.Printf("Pixel Data value at byte %d (%d bytes)\n", loc.ValueOffset, loc.ValueLength)
+//	}
+func (e *Element) Location() (Location, bool) {
+	if e.location == nil {
+		return Location{}, false
+	}
+	return *e.location, true
+}
+
+// SetLocation records the element's position in its source stream.
+// It is called by the parser and is not considered when comparing elements.
+func (e *Element) SetLocation(loc Location) {
+	e.location = &loc
+}
@@ -0,0 +1,42 @@
+package element
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElement_Location(t *testing.T) {
+	val, err := value.NewStringValue(vr.LongString, []string{"PAT001"})
+	require.NoError(t, err)
+	elem, err := NewElement(tag.PatientID, vr.LongString, val)
+	require.NoError(t, err)
+
+	// In-memory elements have no location
+	_, ok := elem.Location()
+	assert.False(t, ok)
+
+	loc := Location{Offset: 300, ValueOffset: 308, Length: 14, ValueLength: 6}
+	elem.SetLocation(loc)
+
+	got, ok := elem.Location()
+	require.True(t, ok)
+	assert.Equal(t, loc, got)
+	assert.Equal(t, int64(8), got.HeaderLength())
+}
+
+func TestElement_LocationIgnoredByEquals(t *testing.T) {
+	val, err := value.NewStringValue(vr.LongString, []string{"PAT001"})
+	require.NoError(t, err)
+	a, err := NewElement(tag.PatientID, vr.LongString, val)
+	require.NoError(t, err)
+	b, err := NewElement(tag.PatientID, vr.LongString, val)
+	require.NoError(t, err)
+
+	a.SetLocation(Location{Offset: 10, ValueOffset: 18, Length: 14, ValueLength: 6})
+	assert.True(t, a.Equals(b))
+}
@@ -0,0 +1,32 @@
+package element
+
+// RawBytes returns the element's value field exactly as it was encoded in the
+// stream it was parsed from, before any decoding: in the dataset's byte order, with
+// its padding, and for sequences and encapsulated pixel data including the item and
+// delimitation items.
+//
+// It returns nil if the element was not parsed with raw value retention enabled
+// (see dicom.ParseOptions.RetainRawValues), was created in memory, or has had its
+// value replaced with SetValue since. The returned slice must not be modified.
+//
+// The encoded form is what Digital Signatures hash, and it allows values whose
+// interpretation is unknown (such as UN or private elements) to be re-emitted
+// faithfully.
+//
+// Example:
+//
+//	ds, _ := dicom.ParseFileWithOptions("signed.dcm", dicom.ParseOptions{RetainRawValues: true})
+//	elem, _ := ds.Get(tag.PatientName)
+//	h.Write(elem.RawBytes())
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part15.html#sect_C.1
+func (e *Element) RawBytes() []byte {
+	return e.raw
+}
+
+// SetRawBytes records the element's encoded value field. It is called by the parser
+// and is not considered when comparing elements.
+func (e *Element) SetRawBytes(raw []byte) {
+	e.raw = raw
+}
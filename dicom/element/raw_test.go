@@ -0,0 +1,35 @@
+package element
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElement_RawBytes(t *testing.T) {
+	val, err := value.NewStringValue(vr.LongString, []string{"PAT001"})
+	require.NoError(t, err)
+	elem, err := NewElement(tag.PatientID, vr.LongString, val)
+	require.NoError(t, err)
+
+	// In-memory elements have no raw bytes
+	assert.Nil(t, elem.RawBytes())
+
+	elem.SetRawBytes([]byte("PAT001"))
+	assert.Equal(t, []byte("PAT001"), elem.RawBytes())
+
+	// Raw bytes do not take part in comparisons
+	other, err := NewElement(tag.PatientID, vr.LongString, val)
+	require.NoError(t, err)
+	assert.True(t, elem.Equals(other))
+
+	// Replacing the value discards the stale encoding
+	newVal, err := value.NewStringValue(vr.LongString, []string{"PAT002"})
+	require.NoError(t, err)
+	require.NoError(t, elem.SetValue(newVal))
+	assert.Nil(t, elem.RawBytes())
+}
@@ -2,6 +2,7 @@
 package dicom
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -24,8 +25,41 @@ import (
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1.2
 type ElementParser struct {
-	reader *Reader
-	ts     *TransferSyntax
+	reader       *Reader
+	ts           *TransferSyntax
+	trackOffsets bool // Record element.Location on each parsed element
+	retainRaw    bool // Record element.RawBytes on each parsed element
+
+	// maxElementLength rejects declared value lengths above this size (0 = no limit).
+	maxElementLength uint32
+
+	// onDuplicateTag and warn control how repeated tags within a dataset or item are
+	// resolved and reported (see ParseOptions.OnDuplicateTag).
+	onDuplicateTag DuplicateTagPolicy
+	warn           func(err error)
+
+	// preserveUN keeps Implicit VR elements whose dictionary entry lists several VRs
+	// as UN (see ParseOptions.PreserveUN).
+	preserveUN bool
+
+	// vrOverrides maps tags to the VR their values are decoded as, in place of the
+	// file's or dictionary's VR (see ParseOptions.VROverrides).
+	vrOverrides map[tag.Tag]vr.VR
+
+	// bitsAllocated is the Bits Allocated (0028,0100) of the dataset or item being
+	// parsed, or 0 if not yet seen. Used to resolve the Pixel Data VR in Implicit VR.
+	bitsAllocated uint16
+
+	// pixelRepresentation is the Pixel Representation (0028,0103) in effect for the
+	// dataset or item being parsed. Used to resolve "US or SS" pixel value
+	// attributes in Implicit VR.
+	pixelRepresentation uint16
+
+	// headerTag is the tag of the element ReadElement last started, and
+	// hasHeaderTag reports whether that tag was read in full. They let the caller
+	// place an element whose header or value turns out to be invalid.
+	headerTag    tag.Tag
+	hasHeaderTag bool
 }
 
 // NewElementParser creates a new element parser with the specified reader and transfer syntax.
@@ -39,19 +73,33 @@ func NewElementParser(reader *Reader, ts *TransferSyntax) *ElementParser {
 // ReadElement reads the next data element from the stream.
 //
 // Returns an error if the element cannot be parsed or if the stream ends unexpectedly.
+// When encapsulated pixel data ends without its Sequence Delimitation Item, the error
+// wraps ErrTruncatedPixelData and the returned element holds the fragments read
+// before the end of the stream.
 //
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1
 func (p *ElementParser) ReadElement() (*element.Element, error) {
+	start := p.reader.Position()
+
 	// Read tag (4 bytes: group + element)
+	p.hasHeaderTag = false
 	t, err := p.readTag()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read tag: %w", err)
 	}
+	p.headerTag, p.hasHeaderTag = t, true
+
+	return p.readElementBody(t, start)
+}
 
+// readElementBody reads the VR, length and value of an element whose tag has
+// already been read. start is the stream position of the tag.
+func (p *ElementParser) readElementBody(t tag.Tag, start int64) (*element.Element, error) {
 	// Read VR based on transfer syntax
 	var v vr.VR
 	var length uint32
+	var err error
 
 	if p.ts.ExplicitVR {
 		// Explicit VR: VR is in the file
@@ -75,14 +123,39 @@ func (p *ElementParser) ReadElement() (*element.Element, error) {
 		// For Implicit VR, length is always 4 bytes
 		length, err = p.reader.ReadUint32()
 		if err != nil {
-			return nil, fmt.Errorf("failed to read length for tag %s: %w", t, err)
+			return nil, fmt.Errorf("failed to read length for tag %s: %w", t, unexpectedEOF(err))
 		}
 	}
 
-	// Read value based on VR type
+	// Overrides change how the value is decoded, never how the header was read
+	if override, ok := p.vrOverrides[t]; ok {
+		v = override
+	}
+
+	if err := p.checkLength(t, length); err != nil {
+		return nil, err
+	}
+
+	valueOffset := p.reader.Position()
+
+	// Read value based on VR type, capturing its encoded bytes if requested
+	var rawMark int
+	if p.retainRaw {
+		rawMark = p.reader.beginCapture()
+	}
 	val, err := p.readValue(t, v, length)
+	var raw []byte
+	if p.retainRaw {
+		raw = p.reader.endCapture(rawMark)
+	}
+	var truncated error
 	if err != nil {
-		return nil, fmt.Errorf("failed to read value for tag %s: %w", t, err)
+		err = fmt.Errorf("failed to read value for tag %s: %w", t, err)
+		if val == nil || !errors.Is(err, ErrTruncatedPixelData) {
+			return nil, err
+		}
+		// Keep the fragments read so far; the caller decides whether to use them
+		truncated = err
 	}
 
 	// Create and return element
@@ -91,11 +164,61 @@ func (p *ElementParser) ReadElement() (*element.Element, error) {
 		return nil, fmt.Errorf("failed to create element for tag %s: %w", t, err)
 	}
 
-	return elem, nil
+	// Remember Bits Allocated for a later Implicit VR Pixel Data element
+	if t.Equals(tag.BitsAllocated) {
+		if intVal, ok := val.(*value.IntValue); ok && len(intVal.Ints()) > 0 {
+			p.bitsAllocated = uint16(intVal.Ints()[0])
+		}
+	}
+
+	// Remember Pixel Representation for later Implicit VR pixel value attributes
+	if t.Equals(tag.PixelRepresentation) {
+		if intVal, ok := val.(*value.IntValue); ok && len(intVal.Ints()) > 0 {
+			p.pixelRepresentation = uint16(intVal.Ints()[0])
+		}
+	}
+
+	if p.retainRaw {
+		elem.SetRawBytes(raw)
+	}
+
+	if p.trackOffsets {
+		elem.SetLocation(element.Location{
+			Offset:      start,
+			ValueOffset: valueOffset,
+			Length:      p.reader.Position() - start,
+			ValueLength: length,
+		})
+	}
+
+	return elem, truncated
+}
+
+// checkLength rejects a defined value length that exceeds the configured maximum
+// or the bytes remaining in the stream, before any buffer is allocated for it.
+// Undefined lengths are not checked; their contents are checked as they are read.
+func (p *ElementParser) checkLength(t tag.Tag, length uint32) error {
+	if length == undefinedLength {
+		return nil
+	}
+	if p.maxElementLength > 0 && length > p.maxElementLength {
+		return fmt.Errorf("%w: tag %s declares %d bytes (maximum %d)", ErrElementTooLarge, t, length, p.maxElementLength)
+	}
+	if remaining, ok := p.reader.Remaining(); ok && int64(length) > remaining {
+		return fmt.Errorf("%w: tag %s declares %d bytes but only %d remain", ErrElementTooLarge, t, length, remaining)
+	}
+	return nil
 }
 
 // readTag reads a DICOM tag (group and element).
+//
+// A stream that ends exactly before the tag returns an error wrapping io.EOF, the
+// normal end of a dataset; one that ends inside the tag wraps io.ErrUnexpectedEOF.
 func (p *ElementParser) readTag() (tag.Tag, error) {
+	if err := p.requireBytes(4, "tag"); err != nil {
+		return tag.Tag{}, err
+	}
+
 	// Read group (2 bytes)
 	group, err := p.reader.ReadUint16()
 	if err != nil {
@@ -105,18 +228,43 @@ func (p *ElementParser) readTag() (tag.Tag, error) {
 	// Read element (2 bytes)
 	elem, err := p.reader.ReadUint16()
 	if err != nil {
-		return tag.Tag{}, fmt.Errorf("failed to read tag element: %w", err)
+		return tag.Tag{}, fmt.Errorf("failed to read tag element: %w", unexpectedEOF(err))
 	}
 
 	return tag.New(group, elem), nil
 }
 
+// requireBytes checks that at least n bytes remain before reading a fixed-size
+// field, when the stream size is known. A stream with no bytes left is reported as
+// io.EOF so that callers can still detect the end of a dataset; a stream with fewer
+// than n bytes left is reported as io.ErrUnexpectedEOF.
+func (p *ElementParser) requireBytes(n int64, field string) error {
+	remaining, ok := p.reader.Remaining()
+	if !ok || remaining >= n {
+		return nil
+	}
+	if remaining == 0 {
+		return fmt.Errorf("failed to read %s: %w", field, io.EOF)
+	}
+	return fmt.Errorf("failed to read %s: need %d bytes but only %d remain: %w",
+		field, n, remaining, io.ErrUnexpectedEOF)
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF, for reads that continue a
+// field or element whose first bytes have already been consumed.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
 // readVRExplicit reads a 2-byte VR in Explicit VR encoding.
 func (p *ElementParser) readVRExplicit() (vr.VR, error) {
 	// Read 2-byte VR string
 	vrStr, err := p.reader.ReadString(2)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read VR: %w", err)
+		return 0, fmt.Errorf("failed to read VR: %w", unexpectedEOF(err))
 	}
 
 	// Parse VR string
@@ -131,12 +279,22 @@ func (p *ElementParser) readVRExplicit() (vr.VR, error) {
 // readVRImplicit looks up the VR for a tag from the DICOM data dictionary.
 // This is used for Implicit VR transfer syntaxes where VR is not encoded in the file.
 //
-// For tags with multiple possible VRs (e.g., PixelData can be "OB or OW"),
-// this returns the first VR in the list as the default.
+// For tags with multiple possible VRs this returns the first VR in the list as the
+// default, except for Pixel Data which is resolved by ImplicitPixelDataVR and the
+// "US or SS" pixel value attributes which are resolved by PixelValueVR. With
+// ParseOptions.PreserveUN such tags are read as UN instead, keeping their bytes
+// uninterpreted.
 //
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1.2
 func (p *ElementParser) readVRImplicit(t tag.Tag) (vr.VR, error) {
+	if t.Equals(tag.PixelData) {
+		return ImplicitPixelDataVR(p.bitsAllocated), nil
+	}
+	if IsPixelValueTag(t) {
+		return PixelValueVR(p.pixelRepresentation), nil
+	}
+
 	// Look up tag in dictionary
 	info, err := tag.Find(t)
 	if err != nil {
@@ -145,13 +303,36 @@ func (p *ElementParser) readVRImplicit(t tag.Tag) (vr.VR, error) {
 	}
 
 	// Return first VR (for tags with multiple VRs like "OB or OW", use the first one)
-	if len(info.VRs) == 0 {
+	if len(info.VRs) == 0 || (p.preserveUN && len(info.VRs) > 1) {
 		return vr.Unknown, nil
 	}
 
 	return info.VRs[0], nil
 }
 
+// ImplicitPixelDataVR returns the VR assigned to Pixel Data (7FE0,0010) when it is
+// read with an Implicit VR transfer syntax.
+//
+// The dictionary lists Pixel Data as "OB or OW" and Implicit VR does not encode which
+// one applies, so the parser decides from the Bits Allocated (0028,0100) already read
+// from the same dataset (or sequence item, for icon images):
+//   - BitsAllocated 1-8: OB (byte stream)
+//   - BitsAllocated > 8: OW (16-bit words)
+//   - Bits Allocated not yet seen (0): OW, the VR PS3.5 assigns to Implicit VR pixel data
+//
+// Choosing the right VR matters when the data is later re-encoded: OW values are
+// byte-swapped per 16-bit word for big-endian transfer syntaxes while OB is not, so
+// reading 16-bit pixels as OB corrupts the byte order of a big-endian write.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_A.1
+func ImplicitPixelDataVR(bitsAllocated uint16) vr.VR {
+	if bitsAllocated > 0 && bitsAllocated <= 8 {
+		return vr.OtherByte
+	}
+	return vr.OtherWord
+}
+
 // readLength reads the value length field.
 //
 // Length encoding depends on VR:
@@ -166,7 +347,7 @@ func (p *ElementParser) readLength(v vr.VR) (uint32, error) {
 		// Read 2-byte reserved field (must be 0x0000)
 		reserved, err := p.reader.ReadUint16()
 		if err != nil {
-			return 0, fmt.Errorf("failed to read reserved field: %w", err)
+			return 0, fmt.Errorf("failed to read reserved field: %w", unexpectedEOF(err))
 		}
 		if reserved != 0x0000 {
 			// Not strictly an error per standard, but log for debugging
@@ -176,7 +357,7 @@ func (p *ElementParser) readLength(v vr.VR) (uint32, error) {
 		// Read 4-byte length
 		length, err := p.reader.ReadUint32()
 		if err != nil {
-			return 0, fmt.Errorf("failed to read 32-bit length: %w", err)
+			return 0, fmt.Errorf("failed to read 32-bit length: %w", unexpectedEOF(err))
 		}
 
 		return length, nil
@@ -185,7 +366,7 @@ func (p *ElementParser) readLength(v vr.VR) (uint32, error) {
 	// Read 2-byte length for standard VRs
 	length16, err := p.reader.ReadUint16()
 	if err != nil {
-		return 0, fmt.Errorf("failed to read 16-bit length: %w", err)
+		return 0, fmt.Errorf("failed to read 16-bit length: %w", unexpectedEOF(err))
 	}
 
 	return uint32(length16), nil
@@ -199,11 +380,10 @@ func (p *ElementParser) readValue(t tag.Tag, v vr.VR, length uint32) (value.Valu
 	}
 
 	// Handle undefined length (0xFFFFFFFF)
-	if length == 0xFFFFFFFF {
-		// For sequences with undefined length, skip the sequence content
-		// Sequences are delimited by Sequence Delimitation Item (FFFE,E0DD)
+	if length == undefinedLength {
+		// Sequences with undefined length are delimited by Sequence Delimitation Item (FFFE,E0DD)
 		if v == vr.SequenceOfItems {
-			return p.skipUndefinedLengthSequence(t)
+			return p.readSequence(t, length)
 		}
 
 		// Handle encapsulated pixel data (OB/OW with undefined length)
@@ -224,13 +404,12 @@ func (p *ElementParser) readValue(t tag.Tag, v vr.VR, length uint32) (value.Valu
 	// Check sequences first, then float types before numeric types (floats are also numeric)
 	switch {
 	case v == vr.SequenceOfItems:
-		// Sequence with defined length - skip it
-		return p.skipDefinedLengthSequence(t, length)
+		return p.readSequence(t, length)
 	case v.IsStringType():
 		return p.readStringValue(v, length)
 	case v == vr.FloatingPointSingle || v == vr.FloatingPointDouble:
 		return p.readFloatValue(v, length)
-	case v.IsNumericType():
+	case v.IsNumericType() || v == vr.AttributeTag:
 		return p.readIntValue(v, length)
 	case v.IsBinaryType():
 		return p.readBytesValue(v, length)
@@ -240,17 +419,24 @@ func (p *ElementParser) readValue(t tag.Tag, v vr.VR, length uint32) (value.Valu
 	}
 }
 
-// createEmptyValue creates an empty value for the given VR.
+// createEmptyValue creates the value of a zero-length element, so that an attribute
+// present with no value (as Type 2 attributes may be) stays present rather than being
+// dropped.
 func (p *ElementParser) createEmptyValue(v vr.VR) (value.Value, error) {
+	return emptyValue(v)
+}
+
+// emptyValue returns a value of the given VR with no values.
+func emptyValue(v vr.VR) (value.Value, error) {
 	switch {
 	case v == vr.SequenceOfItems:
-		return value.NewBytesValue(vr.SequenceOfItems, []byte{})
+		return value.NewSequenceValue(nil)
 	case v.IsStringType():
 		return value.NewStringValue(v, []string{})
-	case v.IsNumericType():
-		return value.NewIntValue(v, []int64{})
 	case v == vr.FloatingPointSingle || v == vr.FloatingPointDouble:
 		return value.NewFloatValue(v, []float64{})
+	case v.IsNumericType() || v == vr.AttributeTag:
+		return value.NewIntValue(v, []int64{})
 	case v.IsBinaryType():
 		return value.NewBytesValue(v, []byte{})
 	default:
@@ -357,11 +543,17 @@ func (p *ElementParser) readIntValue(v vr.VR, length uint32) (*value.IntValue, e
 			val = int64(u32)
 
 		case vr.AttributeTag:
-			u32, err := p.reader.ReadUint32()
+			// A tag is stored as two 16-bit values, group then element, each in the
+			// transfer syntax byte order
+			group, err := p.reader.ReadUint16()
 			if err != nil {
 				return nil, err
 			}
-			val = int64(u32)
+			elem, err := p.reader.ReadUint16()
+			if err != nil {
+				return nil, err
+			}
+			val = int64(group)<<16 | int64(elem)
 
 		case vr.SignedVeryLong:
 			data, err := p.reader.ReadBytes(8)
@@ -470,237 +662,135 @@ func (p *ElementParser) readBytesValue(v vr.VR, length uint32) (*value.BytesValu
 	return bytesVal, nil
 }
 
-// skipDefinedLengthSequence skips over a sequence with defined length.
-//
-// For sequences with known length, we simply skip the specified number of bytes.
-// The sequence content is not parsed.
+// Delimitation tags used by sequences and encapsulated pixel data.
 //
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.5
-func (p *ElementParser) skipDefinedLengthSequence(sequenceTag tag.Tag, length uint32) (value.Value, error) {
-	// Skip the sequence content
-	if length > 0 {
-		_, err := p.reader.ReadBytes(int(length))
-		if err != nil {
-			return nil, fmt.Errorf("failed to skip sequence %s content (%d bytes): %w", sequenceTag, length, err)
-		}
-	}
-
-	// Return empty bytes value as placeholder for skipped sequence
-	return value.NewBytesValue(vr.SequenceOfItems, []byte{})
-}
+const (
+	itemTagValue                 = uint32(0xFFFEE000) // Item
+	itemDelimitationTagValue     = uint32(0xFFFEE00D) // Item Delimitation Item
+	sequenceDelimitationTagValue = uint32(0xFFFEE0DD) // Sequence Delimitation Item
+	undefinedLength              = element.UndefinedLength
+)
 
-// skipUndefinedLengthSequence skips over a sequence with undefined length.
+// readSequence reads a Sequence of Items (SQ) value into a *value.SequenceValue.
 //
-// Sequences with undefined length are terminated by a Sequence Delimitation Item (FFFE,E0DD).
-// Items within the sequence start with Item tag (FFFE,E000) and may have undefined length too,
-// in which case they're terminated by Item Delimitation Item (FFFE,E00D).
-//
-// For now, we skip the entire sequence content and return a placeholder value.
+// Both defined-length and undefined-length sequences are supported. A defined-length
+// sequence ends after length bytes; an undefined-length sequence ends at a Sequence
+// Delimitation Item (FFFE,E0DD). Each item is parsed into a nested *DataSet using
+// the same transfer syntax as the enclosing dataset.
 //
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.5
-func (p *ElementParser) skipUndefinedLengthSequence(sequenceTag tag.Tag) (value.Value, error) {
-	// Delimiter tags
-	const (
-		itemTag                 = uint32(0xFFFEE000) // Item
-		itemDelimitationTag     = uint32(0xFFFEE00D) // Item Delimitation Item
-		sequenceDelimitationTag = uint32(0xFFFEE0DD) // Sequence Delimitation Item
-	)
+func (p *ElementParser) readSequence(sequenceTag tag.Tag, length uint32) (value.Value, error) {
+	undefined := length == undefinedLength
+	start := p.reader.Position()
+	var items []value.Item
 
-	for {
-		// Read next tag
+	for undefined || p.reader.Position()-start < int64(length) {
 		t, err := p.readTag()
 		if err != nil {
-			return nil, fmt.Errorf("unexpected EOF while skipping sequence %s: %w", sequenceTag, err)
+			return nil, fmt.Errorf("unexpected EOF while reading sequence %s: %w", sequenceTag, err)
 		}
 
-		tagValue := t.Uint32()
-
-		// Check for sequence delimitation first
-		if tagValue == sequenceDelimitationTag {
-			// Read and discard length (should be 0)
-			_, err = p.reader.ReadUint32()
-			if err != nil {
-				return nil, fmt.Errorf("failed to read sequence delimitation length: %w", err)
-			}
-			// Return empty bytes value as placeholder for skipped sequence
-			return value.NewBytesValue(vr.SequenceOfItems, []byte{})
+		itemLength, err := p.reader.ReadUint32()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read item length in sequence %s: %w", sequenceTag, err)
 		}
 
-		// Read length based on tag type
-		var elemLength uint32
-		if tagValue == itemTag || tagValue == itemDelimitationTag {
-			// Delimiter items: read 4-byte length directly (no VR)
-			elemLength, err = p.reader.ReadUint32()
-			if err != nil {
-				return nil, fmt.Errorf("failed to read delimiter item length: %w", err)
-			}
-		} else {
-			// Regular data element within item
-			// Read VR and length according to transfer syntax
-			var v vr.VR
-			if p.ts.ExplicitVR {
-				v, err = p.readVRExplicit()
-				if err != nil {
-					return nil, fmt.Errorf("failed to read VR while skipping sequence: %w", err)
-				}
+		switch t.Uint32() {
+		case sequenceDelimitationTagValue:
+			// End of an undefined-length sequence (tolerated in defined-length sequences)
+			return value.NewSequenceValue(items)
 
-				elemLength, err = p.readLength(v)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read length while skipping sequence: %w", err)
-				}
-			} else {
-				// Implicit VR: look up VR and read 4-byte length
-				v, err = p.readVRImplicit(t)
-				if err != nil {
-					return nil, fmt.Errorf("failed to look up VR while skipping sequence: %w", err)
-				}
-
-				elemLength, err = p.reader.ReadUint32()
-				if err != nil {
-					return nil, fmt.Errorf("failed to read length while skipping sequence: %w", err)
-				}
-			}
-
-			// If this is a nested sequence with undefined length, recurse
-			if v == vr.SequenceOfItems && elemLength == 0xFFFFFFFF {
-				_, err = p.skipUndefinedLengthSequence(t)
-				if err != nil {
-					return nil, fmt.Errorf("failed to skip nested sequence %s: %w", t, err)
-				}
-				continue
-			}
-		}
-
-		// Handle different tag types
-		switch tagValue {
-		case itemTag:
-			// Start of an item
-			if elemLength == 0xFFFFFFFF {
-				// Item with undefined length - skip until item delimitation
-				err = p.skipUndefinedLengthItem()
-				if err != nil {
-					return nil, fmt.Errorf("failed to skip undefined length item: %w", err)
-				}
-			} else if elemLength > 0 {
-				// Item with defined length - skip the content
-				_, err := p.reader.ReadBytes(int(elemLength))
-				if err != nil {
-					return nil, fmt.Errorf("failed to skip item content: %w", err)
-				}
+		case itemTagValue:
+			item, err := p.readItem(itemLength)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read item %d of sequence %s: %w", len(items), sequenceTag, err)
 			}
-
-		case itemDelimitationTag:
-			// This should not occur at sequence level
-			return nil, fmt.Errorf("unexpected item delimitation tag while skipping sequence %s", sequenceTag)
+			items = append(items, item)
 
 		default:
-			// Regular data element - skip it
-			if elemLength > 0 && elemLength != 0xFFFFFFFF {
-				_, err := p.reader.ReadBytes(int(elemLength))
-				if err != nil {
-					return nil, fmt.Errorf("failed to skip element value: %w", err)
-				}
-			}
+			return nil, fmt.Errorf("unexpected tag %s in sequence %s (expected Item or Sequence Delimitation)", t, sequenceTag)
 		}
 	}
+
+	return value.NewSequenceValue(items)
 }
 
-// skipUndefinedLengthItem skips over an item with undefined length.
+// readItem reads a single sequence item into a nested DataSet.
 //
-// Items with undefined length are terminated by an Item Delimitation Item (FFFE,E00D).
+// A defined-length item ends after length bytes; an undefined-length item ends at an
+// Item Delimitation Item (FFFE,E00D).
 //
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.5
-func (p *ElementParser) skipUndefinedLengthItem() error {
-	// Delimiter tags
-	const (
-		itemDelimitationTag     = uint32(0xFFFEE00D) // Item Delimitation Item
-		sequenceDelimitationTag = uint32(0xFFFEE0DD) // Sequence Delimitation Item (not expected here, but check anyway)
-	)
-
-	for {
-		// Read next tag
+func (p *ElementParser) readItem(length uint32) (*DataSet, error) {
+	// Items are nested datasets with their own Bits Allocated (e.g. Icon Image Sequence)
+	outerBitsAllocated := p.bitsAllocated
+	p.bitsAllocated = 0
+	defer func() { p.bitsAllocated = outerBitsAllocated }()
+
+	// Pixel Representation is inherited from the enclosing dataset unless the item
+	// has its own (e.g. Real World Value Mapping items refer to the image's pixels)
+	outerPixelRepresentation := p.pixelRepresentation
+	defer func() { p.pixelRepresentation = outerPixelRepresentation }()
+
+	undefined := length == undefinedLength
+	start := p.reader.Position()
+	ds := NewDataSet()
+
+	for undefined || p.reader.Position()-start < int64(length) {
+		elemStart := p.reader.Position()
 		t, err := p.readTag()
 		if err != nil {
-			if err == io.EOF {
-				// EOF while reading item - this might mean the sequence/item delimiter is missing
-				// Return EOF so caller can handle it
-				return io.EOF
-			}
-			return fmt.Errorf("failed to read tag while skipping item: %w", err)
+			return nil, fmt.Errorf("failed to read tag in item: %w", err)
 		}
 
-		tagValue := t.Uint32()
-
-		// Check for item delimitation
-		if tagValue == itemDelimitationTag {
+		if t.Uint32() == itemDelimitationTagValue {
 			// Read and discard length (should be 0)
-			_, err = p.reader.ReadUint32()
-			if err != nil {
-				return fmt.Errorf("failed to read item delimitation length: %w", err)
+			if _, err := p.reader.ReadUint32(); err != nil {
+				return nil, fmt.Errorf("failed to read item delimitation length: %w", err)
 			}
-			return nil
+			return ds, nil
 		}
 
-		// Check if we accidentally hit sequence delimitation (shouldn't happen, but be defensive)
-		if tagValue == sequenceDelimitationTag {
-			// This is actually the end of the parent sequence, not the item
-			// We need to "un-read" this tag by putting it back somehow
-			// For now, just return an error - the sequence parser will handle this
-			return fmt.Errorf("found sequence delimitation while expecting item delimitation")
+		elem, err := p.readElementBody(t, elemStart)
+		if err != nil {
+			return nil, err
 		}
 
-		// Regular data element - read VR and length according to transfer syntax
-		var v vr.VR
-		var elemLength uint32
-		if p.ts.ExplicitVR {
-			v, err = p.readVRExplicit()
-			if err != nil {
-				return fmt.Errorf("failed to read VR while skipping item: %w", err)
-			}
+		if err := p.addElement(ds, elem); err != nil {
+			return nil, err
+		}
+	}
 
-			elemLength, err = p.readLength(v)
-			if err != nil {
-				return fmt.Errorf("failed to read length while skipping item: %w", err)
-			}
-		} else {
-			// Implicit VR: look up VR and read 4-byte length
-			v, err = p.readVRImplicit(t)
-			if err != nil {
-				return fmt.Errorf("failed to look up VR while skipping item: %w", err)
-			}
+	return ds, nil
+}
 
-			elemLength, err = p.reader.ReadUint32()
-			if err != nil {
-				return fmt.Errorf("failed to read length while skipping item: %w", err)
-			}
-		}
+// addElement stores a parsed element in ds, resolving a repeated tag according to
+// the parser's duplicate tag policy. Elements may arrive in any tag order.
+func (p *ElementParser) addElement(ds *DataSet, elem *element.Element) error {
+	if _, exists := ds.elements[elem.Tag()]; !exists {
+		ds.elements[elem.Tag()] = elem
+		return nil
+	}
 
-		// If this is a nested sequence with undefined length, recurse
-		if v == vr.SequenceOfItems && elemLength == 0xFFFFFFFF {
-			_, err = p.skipUndefinedLengthSequence(t)
-			if err != nil {
-				if err == io.EOF {
-					return io.EOF
-				}
-				return fmt.Errorf("failed to skip nested sequence %s: %w", t, err)
-			}
-			continue
-		}
+	dup := fmt.Errorf("%w: %s appears more than once", ErrDuplicateTag, elem.Tag())
+	switch p.onDuplicateTag {
+	case DuplicateTagError:
+		return dup
+	case DuplicateTagKeepFirst:
+		dup = fmt.Errorf("%w; keeping first occurrence", dup)
+	default:
+		ds.elements[elem.Tag()] = elem
+		dup = fmt.Errorf("%w; keeping last occurrence", dup)
+	}
 
-		// Skip defined-length values
-		if elemLength > 0 && elemLength != 0xFFFFFFFF {
-			_, err := p.reader.ReadBytes(int(elemLength))
-			if err != nil {
-				if err == io.EOF {
-					return io.EOF
-				}
-				return fmt.Errorf("failed to skip element value: %w", err)
-			}
-		}
+	if p.warn != nil {
+		p.warn(dup)
 	}
+	return nil
 }
 
 // skipEncapsulatedPixelData reads encapsulated pixel data with undefined length.
@@ -714,32 +804,50 @@ func (p *ElementParser) skipUndefinedLengthItem() error {
 // This function reads all fragments and stores them as raw bytes in encapsulated format.
 // The pixel module will later parse and decompress this data.
 //
+// The total size is bounded by maxElementLength. If the stream ends before the
+// Sequence Delimitation Item, or a fragment declares more bytes than remain, the
+// complete items read so far are returned, closed with a Sequence Delimitation Item,
+// together with an error wrapping ErrTruncatedPixelData.
+//
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_A.4
 func (p *ElementParser) skipEncapsulatedPixelData(pixelDataTag tag.Tag, pixelVR vr.VR) (value.Value, error) {
-	// Delimiter tags
-	const (
-		itemTag                 = uint32(0xFFFEE000) // Item
-		sequenceDelimitationTag = uint32(0xFFFEE0DD) // Sequence Delimitation Item
-	)
-
 	// Buffer to collect all encapsulated data (including item tags and lengths)
 	var encapsulatedData []byte
+	items := 0
+
+	// truncated returns the items collected so far with the end-of-data cause.
+	truncated := func(cause error) (value.Value, error) {
+		val, err := value.NewBytesValue(pixelVR, append(encapsulatedData, 0xFE, 0xFF, 0xDD, 0xE0, 0x00, 0x00, 0x00, 0x00))
+		if err != nil {
+			return nil, err
+		}
+		// The cause is formatted rather than wrapped so that the error does not match
+		// io.EOF, which callers treat as a normal end of the dataset.
+		return val, fmt.Errorf("%w: %s ended after %d items (%d bytes) without a Sequence Delimitation Item: %v",
+			ErrTruncatedPixelData, pixelDataTag, items, len(encapsulatedData), cause)
+	}
 
 	for {
 		// Read next tag
 		t, err := p.readTag()
 		if err != nil {
-			return nil, fmt.Errorf("unexpected EOF while reading encapsulated pixel data %s: %w", pixelDataTag, err)
+			if isEOF(err) {
+				return truncated(err)
+			}
+			return nil, fmt.Errorf("failed to read encapsulated pixel data %s: %w", pixelDataTag, err)
 		}
 
 		tagValue := t.Uint32()
 
 		// Check for sequence delimitation (end of encapsulated data)
-		if tagValue == sequenceDelimitationTag {
+		if tagValue == sequenceDelimitationTagValue {
 			// Read and discard length (should be 0)
 			_, err = p.reader.ReadUint32()
 			if err != nil {
+				if isEOF(err) {
+					return truncated(err)
+				}
 				return nil, fmt.Errorf("failed to read sequence delimitation length: %w", err)
 			}
 
@@ -751,31 +859,55 @@ func (p *ElementParser) skipEncapsulatedPixelData(pixelDataTag tag.Tag, pixelVR
 		}
 
 		// Should only encounter Item tags in encapsulated pixel data
-		if tagValue != itemTag {
+		if tagValue != itemTagValue {
 			return nil, fmt.Errorf("unexpected tag %s while reading encapsulated pixel data (expected Item or Sequence Delimitation)", t)
 		}
 
 		// Read item length (4 bytes)
 		itemLength, err := p.reader.ReadUint32()
 		if err != nil {
+			if isEOF(err) {
+				return truncated(err)
+			}
 			return nil, fmt.Errorf("failed to read item length: %w", err)
 		}
 
-		// Add item tag and length (little-endian uint32) to encapsulated data
-		encapsulatedData = append(encapsulatedData,
-			0xFE, 0xFF, 0x00, 0xE0,
-			byte(itemLength&0xFF),
-			byte((itemLength>>8)&0xFF),
-			byte((itemLength>>16)&0xFF),
-			byte((itemLength>>24)&0xFF))
+		if itemLength == undefinedLength {
+			return nil, fmt.Errorf("%w: pixel data fragment with undefined length", ErrInvalidLength)
+		}
+		if total := uint64(len(encapsulatedData)) + 8 + uint64(itemLength); p.maxElementLength > 0 && total > uint64(p.maxElementLength) {
+			return nil, fmt.Errorf("%w: encapsulated pixel data %s exceeds %d bytes", ErrElementTooLarge, pixelDataTag, p.maxElementLength)
+		}
+		if remaining, ok := p.reader.Remaining(); ok && int64(itemLength) > remaining {
+			return truncated(fmt.Errorf("item %d declares %d bytes but only %d remain", items, itemLength, remaining))
+		}
 
-		// Read and append the item data
+		// Read the item data before recording the item, so that a partial fragment
+		// is dropped on truncation
+		var itemData []byte
 		if itemLength > 0 {
-			itemData, err := p.reader.ReadBytes(int(itemLength))
+			itemData, err = p.reader.ReadBytes(int(itemLength))
 			if err != nil {
+				if isEOF(err) {
+					return truncated(err)
+				}
 				return nil, fmt.Errorf("failed to read item data (%d bytes): %w", itemLength, err)
 			}
-			encapsulatedData = append(encapsulatedData, itemData...)
 		}
+
+		// Add item tag and length (little-endian uint32) and data to encapsulated data
+		encapsulatedData = append(encapsulatedData,
+			0xFE, 0xFF, 0x00, 0xE0,
+			byte(itemLength&0xFF),
+			byte((itemLength>>8)&0xFF),
+			byte((itemLength>>16)&0xFF),
+			byte((itemLength>>24)&0xFF))
+		encapsulatedData = append(encapsulatedData, itemData...)
+		items++
 	}
 }
+
+// isEOF reports whether err means the stream ended.
+func isEOF(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -4,9 +4,12 @@ package dicom
 import (
 	"bytes"
 	"encoding/binary"
+	"io"
 	"testing"
 
+	"github.com/codeninja55/go-radx/dicom/element"
 	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
 	"github.com/codeninja55/go-radx/dicom/vr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -228,6 +231,73 @@ func TestElementParser_ReadElement_ExplicitVR_OB(t *testing.T) {
 	assert.Contains(t, elem.Value().String(), "00 01 02 03")
 }
 
+// encapsulatedPixelDataHeader returns an explicit VR OB Pixel Data header with
+// undefined length.
+func encapsulatedPixelDataHeader() []byte {
+	return []byte{0xE0, 0x7F, 0x10, 0x00, 'O', 'B', 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF}
+}
+
+// pixelItem encodes an Item (FFFE,E000) with the given declared length and data.
+func pixelItem(length uint32, data []byte) []byte {
+	item := []byte{0xFE, 0xFF, 0x00, 0xE0, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(item[4:], length)
+	return append(item, data...)
+}
+
+// TestElementParser_ReadElement_TruncatedPixelData tests encapsulated pixel data
+// that ends before its Sequence Delimitation Item.
+func TestElementParser_ReadElement_TruncatedPixelData(t *testing.T) {
+	ts := &TransferSyntax{ExplicitVR: true, ByteOrder: binary.LittleEndian}
+	bot := pixelItem(0, nil)
+	fragment := pixelItem(4, []byte{1, 2, 3, 4})
+	delimiter := []byte{0xFE, 0xFF, 0xDD, 0xE0, 0, 0, 0, 0}
+	complete := append(append(append([]byte{}, bot...), fragment...), delimiter...)
+
+	testCases := []struct {
+		name   string
+		stream []byte
+	}{
+		{name: "missing delimiter", stream: append(append([]byte{}, bot...), fragment...)},
+		{name: "partial fragment", stream: append(append(append([]byte{}, bot...), fragment...), pixelItem(100, []byte{9, 9})...)},
+		{name: "partial item header", stream: append(append(append([]byte{}, bot...), fragment...), 0xFE, 0xFF)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, sized := range []bool{true, false} {
+				data := append(encapsulatedPixelDataHeader(), tc.stream...)
+				var r io.Reader = bytes.NewReader(data)
+				if !sized {
+					// MultiReader hides the stream size
+					r = io.MultiReader(r)
+				}
+				parser := NewElementParser(NewReader(r, binary.LittleEndian), ts)
+
+				elem, err := parser.ReadElement()
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrTruncatedPixelData)
+				assert.NotErrorIs(t, err, io.EOF, "truncation must not look like a clean end of stream")
+				require.NotNil(t, elem, "the fragments read so far are returned")
+				assert.Equal(t, tag.PixelData, elem.Tag())
+				assert.Equal(t, complete, elem.Value().Bytes(), "complete items are kept and closed with a delimiter")
+			}
+		})
+	}
+
+	t.Run("bounded by max element length", func(t *testing.T) {
+		stream := append(encapsulatedPixelDataHeader(), bot...)
+		for i := 0; i < 4; i++ {
+			stream = append(stream, fragment...)
+		}
+		stream = append(stream, delimiter...)
+		parser := NewElementParser(NewReader(bytes.NewReader(stream), binary.LittleEndian), ts)
+		parser.maxElementLength = 32
+
+		_, err := parser.ReadElement()
+		assert.ErrorIs(t, err, ErrElementTooLarge)
+	})
+}
+
 // TestElementParser_ReadElement_ExplicitVR_FL tests parsing a FL element.
 func TestElementParser_ReadElement_ExplicitVR_FL(t *testing.T) {
 	// Setup: Create a buffer with a FL element
@@ -381,3 +451,400 @@ func TestElementParser_ReadElement_InvalidVR(t *testing.T) {
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, ErrInvalidVR)
 }
+
+// writeTestItemElement writes an explicit VR LE short-length element to buf.
+func writeTestItemElement(buf *bytes.Buffer, group, elem uint16, vrStr, val string) {
+	binary.Write(buf, binary.LittleEndian, group)
+	binary.Write(buf, binary.LittleEndian, elem)
+	buf.WriteString(vrStr)
+	binary.Write(buf, binary.LittleEndian, uint16(len(val)))
+	buf.WriteString(val)
+}
+
+// TestElementParser_ReadElement_Sequence tests parsing defined and undefined length sequences.
+func TestElementParser_ReadElement_Sequence(t *testing.T) {
+	// Item content: (0008,1150) UI "1.2.3.4" padded to even length, (0008,1155) UI "1.2.3.4.5"
+	item := new(bytes.Buffer)
+	writeTestItemElement(item, 0x0008, 0x1150, "UI", "1.2.3.4\x00")
+	writeTestItemElement(item, 0x0008, 0x1155, "UI", "1.2.3.4.5\x00")
+
+	testCases := []struct {
+		name            string
+		undefinedSeq    bool
+		undefinedItem   bool
+		expectedNumItem int
+	}{
+		{name: "defined sequence, defined items", expectedNumItem: 2},
+		{name: "undefined sequence, defined items", undefinedSeq: true, expectedNumItem: 2},
+		{name: "defined sequence, undefined items", undefinedItem: true, expectedNumItem: 2},
+		{name: "undefined sequence, undefined items", undefinedSeq: true, undefinedItem: true, expectedNumItem: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Build sequence content: two identical items
+			content := new(bytes.Buffer)
+			for i := 0; i < tc.expectedNumItem; i++ {
+				binary.Write(content, binary.LittleEndian, uint16(0xFFFE))
+				binary.Write(content, binary.LittleEndian, uint16(0xE000))
+				if tc.undefinedItem {
+					binary.Write(content, binary.LittleEndian, uint32(0xFFFFFFFF))
+				} else {
+					binary.Write(content, binary.LittleEndian, uint32(item.Len()))
+				}
+				content.Write(item.Bytes())
+				if tc.undefinedItem {
+					binary.Write(content, binary.LittleEndian, uint16(0xFFFE))
+					binary.Write(content, binary.LittleEndian, uint16(0xE00D))
+					binary.Write(content, binary.LittleEndian, uint32(0))
+				}
+			}
+
+			buf := new(bytes.Buffer)
+			binary.Write(buf, binary.LittleEndian, uint16(0x0008)) // Referenced Image Sequence
+			binary.Write(buf, binary.LittleEndian, uint16(0x1140))
+			buf.WriteString("SQ")
+			binary.Write(buf, binary.LittleEndian, uint16(0))
+			if tc.undefinedSeq {
+				binary.Write(buf, binary.LittleEndian, uint32(0xFFFFFFFF))
+				buf.Write(content.Bytes())
+				binary.Write(buf, binary.LittleEndian, uint16(0xFFFE))
+				binary.Write(buf, binary.LittleEndian, uint16(0xE0DD))
+				binary.Write(buf, binary.LittleEndian, uint32(0))
+			} else {
+				binary.Write(buf, binary.LittleEndian, uint32(content.Len()))
+				buf.Write(content.Bytes())
+			}
+			// Trailing element proves the parser stopped at the right place
+			writeTestItemElement(buf, 0x0010, 0x0020, "LO", "PAT001")
+
+			parser := NewElementParser(NewReader(buf, binary.LittleEndian), &TransferSyntax{
+				ExplicitVR: true,
+				ByteOrder:  binary.LittleEndian,
+			})
+
+			elem, err := parser.ReadElement()
+			require.NoError(t, err)
+			assert.Equal(t, vr.SequenceOfItems, elem.VR())
+
+			ds := NewDataSet()
+			require.NoError(t, ds.Add(elem))
+			items, err := ds.GetSequenceItems(tag.ReferencedImageSequence)
+			require.NoError(t, err)
+			require.Len(t, items, tc.expectedNumItem)
+
+			for _, it := range items {
+				uidElem, err := it.Get(tag.ReferencedSOPInstanceUID)
+				require.NoError(t, err)
+				assert.Equal(t, "1.2.3.4.5", uidElem.Value().String())
+			}
+
+			next, err := parser.ReadElement()
+			require.NoError(t, err)
+			assert.Equal(t, "PAT001", next.Value().String())
+		})
+	}
+}
+
+// TestElementParser_ReadElement_EmptySequence tests a zero-length sequence.
+func TestElementParser_ReadElement_EmptySequence(t *testing.T) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, uint16(0x0008))
+	binary.Write(buf, binary.LittleEndian, uint16(0x1140))
+	buf.WriteString("SQ")
+	binary.Write(buf, binary.LittleEndian, uint16(0))
+	binary.Write(buf, binary.LittleEndian, uint32(0))
+
+	parser := NewElementParser(NewReader(buf, binary.LittleEndian), &TransferSyntax{
+		ExplicitVR: true,
+		ByteOrder:  binary.LittleEndian,
+	})
+
+	elem, err := parser.ReadElement()
+	require.NoError(t, err)
+	assert.Equal(t, "[0 items]", elem.Value().String())
+}
+
+// writeImplicitElement writes an Implicit VR Little Endian element.
+func writeImplicitElement(buf *bytes.Buffer, group, elem uint16, val []byte) {
+	binary.Write(buf, binary.LittleEndian, group)
+	binary.Write(buf, binary.LittleEndian, elem)
+	binary.Write(buf, binary.LittleEndian, uint32(len(val)))
+	buf.Write(val)
+}
+
+// TestElementParser_ReadElement_ImplicitPixelDataVR tests that Implicit VR Pixel Data
+// is read as OB or OW depending on the preceding Bits Allocated.
+func TestElementParser_ReadElement_ImplicitPixelDataVR(t *testing.T) {
+	testCases := []struct {
+		name          string
+		bitsAllocated []byte // nil to omit (0028,0100)
+		expected      vr.VR
+	}{
+		{name: "8-bit", bitsAllocated: []byte{8, 0}, expected: vr.OtherByte},
+		{name: "16-bit", bitsAllocated: []byte{16, 0}, expected: vr.OtherWord},
+		{name: "1-bit", bitsAllocated: []byte{1, 0}, expected: vr.OtherByte},
+		{name: "no bits allocated", expected: vr.OtherWord},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if tc.bitsAllocated != nil {
+				writeImplicitElement(buf, 0x0028, 0x0100, tc.bitsAllocated)
+			}
+			writeImplicitElement(buf, 0x7FE0, 0x0010, []byte{1, 2, 3, 4})
+
+			parser := NewElementParser(NewReader(buf, binary.LittleEndian), &TransferSyntax{
+				ExplicitVR: false,
+				ByteOrder:  binary.LittleEndian,
+			})
+
+			var pixelData *element.Element
+			for buf.Len() > 0 {
+				elem, err := parser.ReadElement()
+				require.NoError(t, err)
+				if elem.Tag().Equals(tag.PixelData) {
+					pixelData = elem
+				}
+			}
+			require.NotNil(t, pixelData)
+			assert.Equal(t, tc.expected, pixelData.VR())
+			assert.Equal(t, []byte{1, 2, 3, 4}, pixelData.Value().Bytes())
+		})
+	}
+}
+
+// TestElementParser_ReadElement_ImplicitPixelDataVR_Item tests that Bits Allocated in
+// a sequence item applies only to Pixel Data within that item.
+func TestElementParser_ReadElement_ImplicitPixelDataVR_Item(t *testing.T) {
+	// Icon Image Sequence item: 8-bit icon
+	item := new(bytes.Buffer)
+	writeImplicitElement(item, 0x0028, 0x0100, []byte{8, 0})
+	writeImplicitElement(item, 0x7FE0, 0x0010, []byte{1, 2})
+
+	buf := new(bytes.Buffer)
+	writeImplicitElement(buf, 0x0028, 0x0100, []byte{16, 0})
+	binary.Write(buf, binary.LittleEndian, uint16(0x0088)) // Icon Image Sequence
+	binary.Write(buf, binary.LittleEndian, uint16(0x0200))
+	binary.Write(buf, binary.LittleEndian, uint32(8+item.Len()))
+	binary.Write(buf, binary.LittleEndian, uint16(0xFFFE))
+	binary.Write(buf, binary.LittleEndian, uint16(0xE000))
+	binary.Write(buf, binary.LittleEndian, uint32(item.Len()))
+	buf.Write(item.Bytes())
+	writeImplicitElement(buf, 0x7FE0, 0x0010, []byte{1, 2, 3, 4})
+
+	parser := NewElementParser(NewReader(buf, binary.LittleEndian), &TransferSyntax{
+		ExplicitVR: false,
+		ByteOrder:  binary.LittleEndian,
+	})
+
+	ds := NewDataSet()
+	for buf.Len() > 0 {
+		elem, err := parser.ReadElement()
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(elem))
+	}
+
+	items, err := ds.GetSequenceItems(tag.IconImageSequence)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	icon, err := items[0].Get(tag.PixelData)
+	require.NoError(t, err)
+	assert.Equal(t, vr.OtherByte, icon.VR())
+
+	pixelData, err := ds.Get(tag.PixelData)
+	require.NoError(t, err)
+	assert.Equal(t, vr.OtherWord, pixelData.VR())
+}
+
+func TestImplicitPixelDataVR(t *testing.T) {
+	assert.Equal(t, vr.OtherByte, ImplicitPixelDataVR(8))
+	assert.Equal(t, vr.OtherWord, ImplicitPixelDataVR(16))
+	assert.Equal(t, vr.OtherWord, ImplicitPixelDataVR(32))
+	assert.Equal(t, vr.OtherWord, ImplicitPixelDataVR(0))
+}
+
+// TestElementParser_ReadElement_AT tests that an AT value is decoded as a group and
+// an element, each in the transfer syntax byte order, so AsTags returns the tag.
+func TestElementParser_ReadElement_AT(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		t.Run(order.String(), func(t *testing.T) {
+			// (0028,0009) AT Frame Increment Pointer = (0018,1063) Frame Time
+			buf := new(bytes.Buffer)
+			binary.Write(buf, order, uint16(0x0028))
+			binary.Write(buf, order, uint16(0x0009))
+			buf.WriteString("AT")
+			binary.Write(buf, order, uint16(4))
+			binary.Write(buf, order, uint16(0x0018))
+			binary.Write(buf, order, uint16(0x1063))
+
+			ts := &TransferSyntax{ExplicitVR: true, ByteOrder: order}
+			parser := NewElementParser(NewReader(buf, order), ts)
+			elem, err := parser.ReadElement()
+			require.NoError(t, err)
+			assert.True(t, elem.Tag().Equals(tag.FrameIncrementPointer))
+			assert.Equal(t, vr.AttributeTag, elem.VR())
+
+			intVal, ok := elem.Value().(*value.IntValue)
+			require.True(t, ok)
+			tags, err := intVal.AsTags()
+			require.NoError(t, err)
+			assert.Equal(t, []tag.Tag{tag.FrameTime}, tags)
+		})
+	}
+}
+
+// TestElementParser_ReadElement_ImplicitPixelValueVR tests that Implicit VR "US or SS"
+// pixel value attributes follow the preceding Pixel Representation, including in
+// sequence items that do not set their own.
+func TestElementParser_ReadElement_ImplicitPixelValueVR(t *testing.T) {
+	testCases := []struct {
+		name                string
+		pixelRepresentation []byte // nil to omit (0028,0103)
+		expectedVR          vr.VR
+		expectedValue       int64
+	}{
+		{name: "signed", pixelRepresentation: []byte{1, 0}, expectedVR: vr.SignedShort, expectedValue: -1000},
+		{name: "unsigned", pixelRepresentation: []byte{0, 0}, expectedVR: vr.UnsignedShort, expectedValue: 64536},
+		{name: "no pixel representation", expectedVR: vr.UnsignedShort, expectedValue: 64536},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Real World Value Mapping item without its own Pixel Representation
+			item := new(bytes.Buffer)
+			writeImplicitElement(item, 0x0040, 0x9216, []byte{0x18, 0xFC})
+
+			buf := new(bytes.Buffer)
+			if tc.pixelRepresentation != nil {
+				writeImplicitElement(buf, 0x0028, 0x0103, tc.pixelRepresentation)
+			}
+			writeImplicitElement(buf, 0x0028, 0x0106, []byte{0x18, 0xFC}) // -1000 as SS
+			binary.Write(buf, binary.LittleEndian, uint16(0x0040))        // Real World Value Mapping Sequence
+			binary.Write(buf, binary.LittleEndian, uint16(0x9096))
+			binary.Write(buf, binary.LittleEndian, uint32(8+item.Len()))
+			binary.Write(buf, binary.LittleEndian, uint16(0xFFFE))
+			binary.Write(buf, binary.LittleEndian, uint16(0xE000))
+			binary.Write(buf, binary.LittleEndian, uint32(item.Len()))
+			buf.Write(item.Bytes())
+
+			parser := NewElementParser(NewReader(buf, binary.LittleEndian), &TransferSyntax{
+				ExplicitVR: false,
+				ByteOrder:  binary.LittleEndian,
+			})
+			ds := NewDataSet()
+			for buf.Len() > 0 {
+				elem, err := parser.ReadElement()
+				require.NoError(t, err)
+				require.NoError(t, ds.Add(elem))
+			}
+
+			smallest, err := ds.Get(tag.SmallestImagePixelValue)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedVR, smallest.VR())
+			values, err := ds.GetInts(tag.SmallestImagePixelValue)
+			require.NoError(t, err)
+			assert.Equal(t, []int64{tc.expectedValue}, values)
+
+			items, err := ds.GetSequenceItems(tag.RealWorldValueMappingSequence)
+			require.NoError(t, err)
+			require.Len(t, items, 1)
+			firstMapped, err := items[0].Get(tag.RealWorldValueFirstValueMapped)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedVR, firstMapped.VR())
+		})
+	}
+}
+
+func TestPixelValueVR(t *testing.T) {
+	assert.Equal(t, vr.UnsignedShort, PixelValueVR(0))
+	assert.Equal(t, vr.SignedShort, PixelValueVR(1))
+	assert.True(t, IsPixelValueTag(tag.PixelPaddingValue))
+	assert.False(t, IsPixelValueTag(tag.RedPaletteColorLookupTableDescriptor))
+	assert.False(t, IsPixelValueTag(tag.Rows))
+}
+
+// TestElementParser_ReadElement_TruncatedHeader tests that a stream ending inside the
+// tag, VR or length of an element is reported as io.ErrUnexpectedEOF, while a stream
+// ending before the tag is a clean io.EOF.
+func TestElementParser_ReadElement_TruncatedHeader(t *testing.T) {
+	explicit := []byte{0x10, 0x00, 0x10, 0x00, 'P', 'N', 0x04, 0x00, 'D', 'o', 'e', ' '}
+	explicitLong := []byte{0xE0, 0x7F, 0x10, 0x00, 'O', 'B', 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 1, 2}
+	implicit := []byte{0x10, 0x00, 0x10, 0x00, 0x04, 0x00, 0x00, 0x00, 'D', 'o', 'e', ' '}
+
+	testCases := []struct {
+		name       string
+		data       []byte
+		explicitVR bool
+	}{
+		{name: "inside tag group", data: explicit[:1], explicitVR: true},
+		{name: "inside tag element", data: explicit[:3], explicitVR: true},
+		{name: "after tag", data: explicit[:4], explicitVR: true},
+		{name: "inside VR", data: explicit[:5], explicitVR: true},
+		{name: "after VR", data: explicit[:6], explicitVR: true},
+		{name: "inside 16-bit length", data: explicit[:7], explicitVR: true},
+		{name: "inside reserved field", data: explicitLong[:7], explicitVR: true},
+		{name: "inside 32-bit length", data: explicitLong[:10], explicitVR: true},
+		{name: "implicit after tag", data: implicit[:4]},
+		{name: "implicit inside length", data: implicit[:6]},
+	}
+
+	for _, tc := range testCases {
+		ts := &TransferSyntax{ExplicitVR: tc.explicitVR, ByteOrder: binary.LittleEndian}
+		t.Run(tc.name+"/known size", func(t *testing.T) {
+			parser := NewElementParser(NewReader(bytes.NewReader(tc.data), binary.LittleEndian), ts)
+			_, err := parser.ReadElement()
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		})
+		t.Run(tc.name+"/unknown size", func(t *testing.T) {
+			parser := NewElementParser(NewReader(io.MultiReader(bytes.NewReader(tc.data)), binary.LittleEndian), ts)
+			_, err := parser.ReadElement()
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		})
+	}
+
+	t.Run("end of stream", func(t *testing.T) {
+		parser := NewElementParser(NewReader(bytes.NewReader(explicit), binary.LittleEndian),
+			&TransferSyntax{ExplicitVR: true, ByteOrder: binary.LittleEndian})
+		_, err := parser.ReadElement()
+		require.NoError(t, err)
+		_, err = parser.ReadElement()
+		assert.ErrorIs(t, err, io.EOF)
+		assert.NotErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+}
+
+// FuzzReadElement feeds arbitrary bytes to the element parser with both Explicit and
+// Implicit VR Little Endian and checks that malformed input fails with an error
+// rather than a panic.
+func FuzzReadElement(f *testing.F) {
+	var seed bytes.Buffer
+	writeTestItemElement(&seed, 0x0010, 0x0010, "PN", "Doe^John")
+	writeTestItemElement(&seed, 0x0028, 0x0010, "US", "\x00\x02")
+	f.Add(seed.Bytes())
+	f.Add(seed.Bytes()[:7])
+
+	var implicit bytes.Buffer
+	writeImplicitElement(&implicit, 0x0028, 0x0100, []byte{8, 0})
+	writeImplicitElement(&implicit, 0x7FE0, 0x0010, []byte{1, 2, 3, 4})
+	f.Add(implicit.Bytes())
+
+	f.Add(encapsulatedPixelDataHeader())
+	f.Add(append(encapsulatedPixelDataHeader(), pixelItem(8, []byte{1, 2})...))
+	f.Add([]byte{0xFE, 0xFF, 0x00, 0xE0, 0xFF, 0xFF, 0xFF, 0xFF})
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, explicitVR := range []bool{true, false} {
+			parser := NewElementParser(NewReader(bytes.NewReader(data), binary.LittleEndian),
+				&TransferSyntax{ExplicitVR: explicitVR, ByteOrder: binary.LittleEndian})
+			// Every successful read consumes at least a tag, so this terminates.
+			for {
+				if _, err := parser.ReadElement(); err != nil {
+					break
+				}
+			}
+		}
+	})
+}
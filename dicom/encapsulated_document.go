@@ -0,0 +1,63 @@
+package dicom
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// ExtractEncapsulatedDocument returns the document wrapped by an Encapsulated PDF,
+// CDA, STL, OBJ or MTL Storage object, together with its MIME type.
+//
+// The document bytes come from Encapsulated Document (0042,0011) and the MIME type
+// from MIME Type of Encapsulated Document (0042,0012). The stored value is padded to
+// even length, so the bytes are trimmed to Encapsulated Document Length (0042,0015)
+// when that attribute is present. Without it a single trailing NULL pad byte is
+// removed, except for binary STL models (model/stl) whose final bytes may
+// legitimately be zero. The returned slice is a copy and may be modified freely.
+//
+// Returns an error if the Encapsulated Document is missing or empty.
+//
+// Example:
+//
+//	mimeType, data, err := dicom.ExtractEncapsulatedDocument(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if mimeType == "application/pdf" {
+//	    os.WriteFile("report.pdf", data, 0o644)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.24.2
+func ExtractEncapsulatedDocument(ds *DataSet) (mimeType string, data []byte, err error) {
+	if ds == nil {
+		return "", nil, fmt.Errorf("dataset is nil")
+	}
+
+	elem, err := ds.Get(tag.EncapsulatedDocument)
+	if err != nil {
+		return "", nil, fmt.Errorf("encapsulated document not found: %w", err)
+	}
+	raw := elem.Value().Bytes()
+	if len(raw) == 0 {
+		return "", nil, fmt.Errorf("encapsulated document %s is empty", tag.EncapsulatedDocument)
+	}
+
+	if mimeElem, err := ds.Get(tag.MIMETypeOfEncapsulatedDocument); err == nil {
+		mimeType = strings.TrimSpace(mimeElem.Value().String())
+	}
+
+	size := len(raw)
+	if lengths, err := ds.GetInts(tag.EncapsulatedDocumentLength); err == nil && len(lengths) > 0 &&
+		lengths[0] >= 0 && lengths[0] <= int64(size) {
+		size = int(lengths[0])
+	} else if raw[size-1] == 0x00 && !strings.EqualFold(mimeType, "model/stl") {
+		size--
+	}
+
+	data = make([]byte, size)
+	copy(data, raw)
+	return mimeType, data, nil
+}
@@ -0,0 +1,115 @@
+package dicom_test
+
+import (
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEncapsulatedDocumentDataSet(t *testing.T, mimeType string, doc []byte, length int64) *dicom.DataSet {
+	ds := dicom.NewDataSet()
+
+	docValue, err := value.NewBytesValue(vr.OtherByte, doc)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(mustNewElement(tag.EncapsulatedDocument, vr.OtherByte, docValue)))
+
+	if mimeType != "" {
+		require.NoError(t, ds.Add(mustNewElement(tag.MIMETypeOfEncapsulatedDocument, vr.LongString,
+			mustNewStringValue(vr.LongString, []string{mimeType}))))
+	}
+
+	if length >= 0 {
+		lengthValue, err := value.NewIntValue(vr.UnsignedLong, []int64{length})
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(mustNewElement(tag.EncapsulatedDocumentLength, vr.UnsignedLong, lengthValue)))
+	}
+
+	return ds
+}
+
+func TestExtractEncapsulatedDocument(t *testing.T) {
+	pdf := []byte("%PDF-1.4\n%%EOF")
+
+	tests := []struct {
+		name     string
+		mimeType string
+		stored   []byte
+		length   int64
+		want     []byte
+	}{
+		{
+			name:     "PDF trimmed to document length",
+			mimeType: "application/pdf",
+			stored:   append(append([]byte{}, pdf...), 0x00, 0x00),
+			length:   int64(len(pdf)),
+			want:     pdf,
+		},
+		{
+			name:     "PDF pad byte removed without document length",
+			mimeType: "application/pdf",
+			stored:   append([]byte("%PDF-1.4\n%%EOF\n"), 0x00),
+			length:   -1,
+			want:     []byte("%PDF-1.4\n%%EOF\n"),
+		},
+		{
+			name:     "binary STL keeps trailing zero bytes",
+			mimeType: "model/stl",
+			stored:   []byte{0x01, 0x02, 0x00, 0x00},
+			length:   -1,
+			want:     []byte{0x01, 0x02, 0x00, 0x00},
+		},
+		{
+			name:     "out of range document length is ignored",
+			mimeType: "text/xml",
+			stored:   []byte("<a/>"),
+			length:   100,
+			want:     []byte("<a/>"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newEncapsulatedDocumentDataSet(t, tt.mimeType, tt.stored, tt.length)
+
+			mimeType, data, err := dicom.ExtractEncapsulatedDocument(ds)
+			require.NoError(t, err)
+			assert.Equal(t, tt.mimeType, mimeType)
+			assert.Equal(t, tt.want, data)
+		})
+	}
+}
+
+func TestExtractEncapsulatedDocument_ReturnsCopy(t *testing.T) {
+	ds := newEncapsulatedDocumentDataSet(t, "application/pdf", []byte("%PDF"), -1)
+
+	_, data, err := dicom.ExtractEncapsulatedDocument(ds)
+	require.NoError(t, err)
+	data[0] = 'X'
+
+	_, again, err := dicom.ExtractEncapsulatedDocument(ds)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("%PDF"), again)
+}
+
+func TestExtractEncapsulatedDocument_Errors(t *testing.T) {
+	t.Run("nil dataset", func(t *testing.T) {
+		_, _, err := dicom.ExtractEncapsulatedDocument(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing document", func(t *testing.T) {
+		_, _, err := dicom.ExtractEncapsulatedDocument(dicom.NewDataSet())
+		assert.Error(t, err)
+	})
+
+	t.Run("empty document", func(t *testing.T) {
+		ds := newEncapsulatedDocumentDataSet(t, "application/pdf", []byte{}, -1)
+		_, _, err := dicom.ExtractEncapsulatedDocument(ds)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,104 @@
+package dicom
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEncodedLength verifies EncodedLength agrees with the bytes the writer emits.
+func TestEncodedLength(t *testing.T) {
+	mustElem := func(tg tag.Tag, v vr.VR, val value.Value, err error) *element.Element {
+		require.NoError(t, err)
+		elem, err := element.NewElement(tg, v, val)
+		require.NoError(t, err)
+		return elem
+	}
+	str := func(tg tag.Tag, v vr.VR, values ...string) *element.Element {
+		val, err := value.NewStringValue(v, values)
+		return mustElem(tg, v, val, err)
+	}
+	ints := func(tg tag.Tag, v vr.VR, values ...int64) *element.Element {
+		val, err := value.NewIntValue(v, values)
+		return mustElem(tg, v, val, err)
+	}
+	floats := func(tg tag.Tag, v vr.VR, values ...float64) *element.Element {
+		val, err := value.NewFloatValue(v, values)
+		return mustElem(tg, v, val, err)
+	}
+	raw := func(tg tag.Tag, v vr.VR, data []byte) *element.Element {
+		val, err := value.NewBytesValue(v, data)
+		return mustElem(tg, v, val, err)
+	}
+
+	item := NewDataSet()
+	require.NoError(t, item.Add(str(tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier, "1.2.3")))
+	require.NoError(t, item.Add(str(tag.CodeMeaning, vr.LongString, "Odd")))
+	nested := NewDataSet()
+	require.NoError(t, nested.Add(ints(tag.Rows, vr.UnsignedShort, 512)))
+	inner, err := NewSequenceElement(tag.ReferencedImageSequence, []*DataSet{nested})
+	require.NoError(t, err)
+	require.NoError(t, item.Add(inner))
+	seq, err := NewSequenceElement(tag.ReferencedSeriesSequence, []*DataSet{item, NewDataSet()})
+	require.NoError(t, err)
+	emptySeq, err := NewSequenceElement(tag.ReferencedStudySequence, nil)
+	require.NoError(t, err)
+
+	encapsulated := []byte{
+		0xFE, 0xFF, 0x00, 0xE0, 0x00, 0x00, 0x00, 0x00, // Basic Offset Table (empty)
+		0xFE, 0xFF, 0x00, 0xE0, 0x02, 0x00, 0x00, 0x00, 0xFF, 0xD8, // Fragment
+		0xFE, 0xFF, 0xDD, 0xE0, 0x00, 0x00, 0x00, 0x00, // Sequence Delimitation Item
+	}
+
+	tests := []struct {
+		name string
+		elem *element.Element
+	}{
+		{"PN odd", str(tag.PatientName, vr.PersonName, "Doe^Jo")},
+		{"PN even", str(tag.PatientName, vr.PersonName, "Doe^Joe")},
+		{"CS multi-valued", str(tag.ImageType, vr.CodeString, "ORIGINAL", "PRIMARY", "AXIAL")},
+		{"UI odd", str(tag.SOPInstanceUID, vr.UniqueIdentifier, "1.2.840.10008.1")},
+		{"DS", str(tag.SliceThickness, vr.DecimalString, "2.5")},
+		{"UT long length", str(tag.TextValue, vr.UnlimitedText, "Free text")},
+		{"empty LO", str(tag.StudyDescription, vr.LongString)},
+		{"US", ints(tag.Rows, vr.UnsignedShort, 512)},
+		{"UL multi-valued", ints(tag.SimpleFrameList, vr.UnsignedLong, 1, 2, 3)},
+		{"FD", floats(tag.ReconstructionFieldOfView, vr.FloatingPointDouble, 250, 250)},
+		{"OB odd", raw(tag.New(0x0009, 0x1001), vr.OtherByte, []byte{1, 2, 3})},
+		{"OW", raw(tag.PixelData, vr.OtherWord, make([]byte, 32))},
+		{"encapsulated pixel data", raw(tag.PixelData, vr.OtherByte, encapsulated)},
+		{"sequence", seq},
+		{"empty sequence", emptySeq},
+	}
+
+	syntaxes := []struct {
+		name       string
+		ts         *TransferSyntax
+		explicitVR bool
+	}{
+		{"default", nil, true},
+		{"explicit VR", &TransferSyntax{UID: "1.2.840.10008.1.2.1", ExplicitVR: true}, true},
+		{"implicit VR", &TransferSyntax{UID: "1.2.840.10008.1.2", ExplicitVR: false}, false},
+	}
+
+	for _, ts := range syntaxes {
+		for _, tt := range tests {
+			t.Run(ts.name+"/"+tt.name, func(t *testing.T) {
+				var buf bytes.Buffer
+				require.NoError(t, writeElement(&buf, tt.elem, ts.explicitVR))
+
+				n := EncodedLength(tt.elem, ts.ts)
+				assert.Equal(t, buf.Len(), n)
+				assert.Zero(t, n%2, "encoded length must be even")
+			})
+		}
+	}
+
+	assert.Equal(t, 0, EncodedLength(nil, nil))
+}
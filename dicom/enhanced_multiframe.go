@@ -0,0 +1,397 @@
+package dicom
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// Tolerances used when validating the geometry of a legacy series.
+const (
+	// orientationTolerance is the largest difference allowed between the direction
+	// cosines of two slices.
+	orientationTolerance = 1e-4
+
+	// spacingTolerance is the largest difference, in mm, allowed between pixel
+	// spacings, and between slice gaps for them to count as uniform.
+	spacingTolerance = 1e-3
+)
+
+// legacyConvertedClasses maps single-frame Storage SOP classes to the Legacy
+// Converted Enhanced class used for their multi-frame form.
+var legacyConvertedClasses = map[string]uid.UID{
+	uid.CTImageStorage.String():                         uid.LegacyConvertedEnhancedCTImageStorage,
+	uid.MRImageStorage.String():                         uid.LegacyConvertedEnhancedMRImageStorage,
+	uid.PositronEmissionTomographyImageStorage.String(): uid.LegacyConvertedEnhancedPETImageStorage,
+}
+
+// functionalGroupMacro is a functional group macro sequence and the attributes it
+// takes over from the top level of a single-frame image.
+type functionalGroupMacro struct {
+	sequence tag.Tag
+	attrs    []tag.Tag
+}
+
+// convertedMacros are the macros filled from per-slice attributes. Each is placed in
+// the Shared Functional Groups Sequence when it is identical for every slice, and in
+// the Per-Frame Functional Groups Sequence otherwise.
+var convertedMacros = []functionalGroupMacro{
+	{tag.PixelMeasuresSequence, []tag.Tag{tag.PixelSpacing, tag.SliceThickness}},
+	{tag.PixelValueTransformationSequence, []tag.Tag{tag.RescaleIntercept, tag.RescaleSlope, tag.RescaleType}},
+	{tag.FrameVOILUTSequence, []tag.Tag{tag.WindowCenter, tag.WindowWidth, tag.WindowCenterWidthExplanation}},
+}
+
+// perSliceTags are top-level attributes of the single-frame images that do not carry
+// over to the multi-frame image, because they describe one slice or are replaced by
+// functional groups.
+var perSliceTags = []tag.Tag{
+	tag.ImagePositionPatient,
+	tag.ImageOrientationPatient,
+	tag.SpacingBetweenSlices,
+	tag.SliceLocation,
+	tag.InstanceNumber,
+	tag.PixelData,
+}
+
+// legacySlice is a single-frame image of the series being converted.
+type legacySlice struct {
+	ds       *DataSet
+	position []float64
+	distance float64 // position along the slice normal
+	pixels   []byte
+}
+
+// ToEnhancedMultiFrame converts a legacy single-frame CT, MR or PET series into one
+// Legacy Converted Enhanced multi-frame image.
+//
+// The slices are sorted along the slice normal and their native pixel data is stacked
+// into a single Pixel Data element, one frame per slice. Image Orientation (Patient)
+// goes into the Shared Functional Groups Sequence (5200,9229) and each slice's Image
+// Position (Patient) into its Per-Frame Functional Groups Sequence (5200,9230) item.
+// Pixel measures, rescale and window attributes are shared when every slice has the
+// same values and per-frame otherwise. Spacing Between Slices is recorded when the
+// slices are evenly spaced. All other attributes are taken from the first slice, and
+// a new SOP Instance UID is generated.
+//
+// Returns an error if the series is empty, mixes SOP classes, series or frames of
+// reference, has slices with differing orientation, pixel spacing or pixel format,
+// has two slices at the same position, or holds compressed pixel data.
+//
+// Example:
+//
+//	volume, err := dicom.ToEnhancedMultiFrame(coll.GetBySeriesInstanceUID(seriesUID))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = dicom.WriteFile("volume.dcm", volume)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_A.70
+func ToEnhancedMultiFrame(series []*DataSet) (*DataSet, error) {
+	if len(series) == 0 {
+		return nil, fmt.Errorf("series is empty")
+	}
+	for i, ds := range series {
+		if ds == nil {
+			return nil, fmt.Errorf("slice %d is nil", i)
+		}
+	}
+
+	ref := series[0]
+	sopClass := stringValue(ref, tag.SOPClassUID)
+	targetClass, ok := legacyConvertedClasses[sopClass]
+	if !ok {
+		return nil, fmt.Errorf("SOP class %q has no legacy converted enhanced multi-frame equivalent", sopClass)
+	}
+
+	orientation, err := ref.GetFloats(tag.ImageOrientationPatient)
+	if err != nil || len(orientation) != 6 {
+		return nil, fmt.Errorf("%w: slice 0 has no valid ImageOrientationPatient", ErrMissingRequiredAttribute)
+	}
+	spacing, err := ref.GetFloats(tag.PixelSpacing)
+	if err != nil || len(spacing) != 2 {
+		return nil, fmt.Errorf("%w: slice 0 has no valid PixelSpacing", ErrMissingRequiredAttribute)
+	}
+	frameSize, err := nativeFrameSize(ref)
+	if err != nil {
+		return nil, err
+	}
+	normal := [3]float64{
+		orientation[1]*orientation[5] - orientation[2]*orientation[4],
+		orientation[2]*orientation[3] - orientation[0]*orientation[5],
+		orientation[0]*orientation[4] - orientation[1]*orientation[3],
+	}
+
+	slices := make([]legacySlice, len(series))
+	for i, ds := range series {
+		if err := checkSliceConsistency(ref, ds, orientation, spacing); err != nil {
+			return nil, fmt.Errorf("slice %d: %w", i, err)
+		}
+
+		position, err := ds.GetFloats(tag.ImagePositionPatient)
+		if err != nil || len(position) != 3 {
+			return nil, fmt.Errorf("%w: slice %d has no valid ImagePositionPatient", ErrMissingRequiredAttribute, i)
+		}
+		pixels, err := nativePixels(ds, frameSize)
+		if err != nil {
+			return nil, fmt.Errorf("slice %d: %w", i, err)
+		}
+
+		slices[i] = legacySlice{
+			ds:       ds,
+			position: position,
+			distance: position[0]*normal[0] + position[1]*normal[1] + position[2]*normal[2],
+			pixels:   pixels,
+		}
+	}
+
+	sort.SliceStable(slices, func(i, j int) bool { return slices[i].distance < slices[j].distance })
+
+	uniform := true
+	var gap float64
+	for i := 1; i < len(slices); i++ {
+		g := slices[i].distance - slices[i-1].distance
+		if g < spacingTolerance {
+			return nil, fmt.Errorf("slices at %v and %v share the same position",
+				slices[i-1].position, slices[i].position)
+		}
+		if i == 1 {
+			gap = g
+		} else if math.Abs(g-gap) > spacingTolerance {
+			uniform = false
+		}
+	}
+
+	result := slices[0].ds.Copy()
+	for _, t := range result.Tags() {
+		if t.Group == 0x0002 {
+			_ = result.Remove(t)
+		}
+	}
+	for _, t := range perSliceTags {
+		_ = result.Remove(t)
+	}
+	for _, macro := range convertedMacros {
+		for _, t := range macro.attrs {
+			_ = result.Remove(t)
+		}
+	}
+
+	shared := NewDataSet()
+	perFrame := make([]*DataSet, len(slices))
+	for i := range perFrame {
+		perFrame[i] = NewDataSet()
+	}
+
+	if err := addMacro(shared, tag.PlaneOrientationSequence, ref, tag.ImageOrientationPatient); err != nil {
+		return nil, err
+	}
+	for i, s := range slices {
+		if err := addMacro(perFrame[i], tag.PlanePositionSequence, s.ds, tag.ImagePositionPatient); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, macro := range convertedMacros {
+		items := make([]*DataSet, len(slices))
+		for i, s := range slices {
+			items[i] = subset(s.ds, macro.attrs...)
+			if macro.sequence.Equals(tag.PixelMeasuresSequence) && uniform && len(slices) > 1 {
+				if err := setDecimal(items[i], tag.SpacingBetweenSlices, gap); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if sameItems(items) {
+			if err := addMacroItem(shared, macro.sequence, items[0]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for i := range items {
+			if err := addMacroItem(perFrame[i], macro.sequence, items[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sharedSeq, err := NewSequenceElement(tag.SharedFunctionalGroupsSequence, []*DataSet{shared})
+	if err != nil {
+		return nil, err
+	}
+	perFrameSeq, err := NewSequenceElement(tag.PerFrameFunctionalGroupsSequence, perFrame)
+	if err != nil {
+		return nil, err
+	}
+
+	pixelElem, err := ref.Get(tag.PixelData)
+	if err != nil {
+		return nil, err
+	}
+	pixels := make([]byte, 0, frameSize*len(slices))
+	for _, s := range slices {
+		pixels = append(pixels, s.pixels...)
+	}
+	pixelVal, err := value.NewBytesValue(pixelElem.VR(), pixels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pixel data: %w", err)
+	}
+	pixelData, err := element.NewElement(tag.PixelData, pixelElem.VR(), pixelVal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pixel data: %w", err)
+	}
+
+	for _, elem := range []*element.Element{sharedSeq, perFrameSeq, pixelData} {
+		if err := result.Set(elem); err != nil {
+			return nil, err
+		}
+	}
+	if err := setString(result, tag.NumberOfFrames, vr.IntegerString, strconv.Itoa(len(slices))); err != nil {
+		return nil, err
+	}
+	if err := setString(result, tag.SOPClassUID, vr.UniqueIdentifier, targetClass.String()); err != nil {
+		return nil, err
+	}
+	if err := result.SetSOPInstanceUID(""); err != nil {
+		return nil, fmt.Errorf("failed to generate SOP Instance UID: %w", err)
+	}
+
+	return result, nil
+}
+
+// checkSliceConsistency verifies that ds can be stacked with ref.
+func checkSliceConsistency(ref, ds *DataSet, orientation, spacing []float64) error {
+	for _, t := range []tag.Tag{tag.SOPClassUID, tag.SeriesInstanceUID, tag.FrameOfReferenceUID} {
+		if got, want := stringValue(ds, t), stringValue(ref, t); got != want {
+			return fmt.Errorf("%s %q differs from %q", tagKeyword(t), got, want)
+		}
+	}
+	for _, t := range imagePixelRequiredTags {
+		if got, want := stringValue(ds, t), stringValue(ref, t); got != want {
+			return fmt.Errorf("%s %q differs from %q", tagKeyword(t), got, want)
+		}
+	}
+
+	o, err := ds.GetFloats(tag.ImageOrientationPatient)
+	if err != nil || len(o) != 6 {
+		return fmt.Errorf("%w: no valid ImageOrientationPatient", ErrMissingRequiredAttribute)
+	}
+	for i := range o {
+		if math.Abs(o[i]-orientation[i]) > orientationTolerance {
+			return fmt.Errorf("ImageOrientationPatient %v differs from %v", o, orientation)
+		}
+	}
+
+	s, err := ds.GetFloats(tag.PixelSpacing)
+	if err != nil || len(s) != 2 {
+		return fmt.Errorf("%w: no valid PixelSpacing", ErrMissingRequiredAttribute)
+	}
+	if math.Abs(s[0]-spacing[0]) > spacingTolerance || math.Abs(s[1]-spacing[1]) > spacingTolerance {
+		return fmt.Errorf("PixelSpacing %v differs from %v", s, spacing)
+	}
+	return nil
+}
+
+// nativeFrameSize returns the size in bytes of one frame of native pixel data.
+func nativeFrameSize(ds *DataSet) (int, error) {
+	size := 1
+	for _, t := range []tag.Tag{tag.Rows, tag.Columns, tag.SamplesPerPixel} {
+		n, err := ds.GetInts(t)
+		if err != nil || len(n) == 0 || n[0] <= 0 {
+			return 0, fmt.Errorf("%w: %s", ErrMissingRequiredAttribute, tagKeyword(t))
+		}
+		size *= int(n[0])
+	}
+	bits, err := ds.GetInts(tag.BitsAllocated)
+	if err != nil || len(bits) == 0 || bits[0] <= 0 || bits[0]%8 != 0 {
+		return 0, fmt.Errorf("%w: BitsAllocated must be a multiple of 8", ErrMissingRequiredAttribute)
+	}
+	return size * int(bits[0]/8), nil
+}
+
+// nativePixels returns the frameSize bytes of native pixel data of a single-frame image.
+func nativePixels(ds *DataSet, frameSize int) ([]byte, error) {
+	elem, err := ds.Get(tag.PixelData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: PixelData", ErrMissingRequiredAttribute)
+	}
+	data := elem.Value().Bytes()
+	if isEncapsulatedPixelValue(data) {
+		return nil, fmt.Errorf("compressed pixel data is not supported, decompress the series first")
+	}
+	if len(data) < frameSize {
+		return nil, fmt.Errorf("pixel data has %d bytes, expected %d", len(data), frameSize)
+	}
+	return data[:frameSize], nil
+}
+
+// subset returns a new dataset holding the tags present in ds.
+func subset(ds *DataSet, tags ...tag.Tag) *DataSet {
+	item := NewDataSet()
+	for _, t := range tags {
+		if elem, err := ds.Get(t); err == nil {
+			_ = item.Set(elem)
+		}
+	}
+	return item
+}
+
+// sameItems reports whether every slice has the same macro item.
+func sameItems(items []*DataSet) bool {
+	for _, item := range items[1:] {
+		if !item.Equals(items[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// addMacro adds a functional group macro sequence holding the tags present in src
+// to group.
+func addMacro(group *DataSet, sequenceTag tag.Tag, src *DataSet, tags ...tag.Tag) error {
+	return addMacroItem(group, sequenceTag, subset(src, tags...))
+}
+
+// addMacroItem adds a functional group macro sequence with the single item to group.
+// Nothing is added if the item is empty.
+func addMacroItem(group *DataSet, sequenceTag tag.Tag, item *DataSet) error {
+	if item.Len() == 0 {
+		return nil
+	}
+	seq, err := NewSequenceElement(sequenceTag, []*DataSet{item})
+	if err != nil {
+		return err
+	}
+	return group.Set(seq)
+}
+
+// setString sets a single-valued string element.
+func setString(ds *DataSet, t tag.Tag, v vr.VR, s string) error {
+	return setStrings(ds, t, v, []string{s})
+}
+
+// setStrings sets a multi-valued string element.
+func setStrings(ds *DataSet, t tag.Tag, v vr.VR, values []string) error {
+	val, err := value.NewStringValue(v, values)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", tagKeyword(t), err)
+	}
+	elem, err := element.NewElement(t, v, val)
+	if err != nil {
+		return fmt.Errorf("failed to create %s element: %w", tagKeyword(t), err)
+	}
+	return ds.Set(elem)
+}
+
+// setDecimal sets a single-valued DS element, formatted to fit the 16-character limit.
+func setDecimal(ds *DataSet, t tag.Tag, f float64) error {
+	return setString(ds, t, vr.DecimalString, strconv.FormatFloat(f, 'g', 10, 64))
+}
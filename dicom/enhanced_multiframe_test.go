@@ -0,0 +1,170 @@
+package dicom_test
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLegacyCTSlice returns a 2x2 8-bit CT slice at z with every pixel set to fill.
+func newLegacyCTSlice(t *testing.T, z float64, fill byte) *dicom.DataSet {
+	t.Helper()
+	pixels := []byte{fill, fill, fill, fill}
+	ds, err := dicom.NewBuilder(uid.CTImageStorage).
+		PatientName("Doe^John").
+		PatientID("12345").
+		StudyUID("1.2.3.4").
+		SeriesUID("1.2.3.4.5").
+		ImageType("ORIGINAL", "PRIMARY", "AXIAL").
+		Rescale(1, -1024).
+		Rows(2).Columns(2).
+		SamplesPerPixel(1).PhotometricInterpretation("MONOCHROME2").
+		BitsAllocated(8).BitsStored(8).HighBit(7).PixelRepresentation(0).
+		PixelData(pixels).
+		Build()
+	require.NoError(t, err)
+
+	setDS := func(tg tag.Tag, values ...string) {
+		require.NoError(t, ds.Set(mustNewElement(tg, vr.DecimalString, mustNewStringValue(vr.DecimalString, values))))
+	}
+	setDS(tag.ImagePositionPatient, "-10", "-10", strconv.FormatFloat(z, 'f', -1, 64))
+	setDS(tag.ImageOrientationPatient, "1", "0", "0", "0", "1", "0")
+	setDS(tag.PixelSpacing, "0.5", "0.5")
+	setDS(tag.SliceThickness, "2.5")
+	return ds
+}
+
+func TestToEnhancedMultiFrame(t *testing.T) {
+	series := []*dicom.DataSet{
+		newLegacyCTSlice(t, 5, 30),
+		newLegacyCTSlice(t, 0, 10),
+		newLegacyCTSlice(t, 2.5, 20),
+	}
+
+	ds, err := dicom.ToEnhancedMultiFrame(series)
+	require.NoError(t, err)
+
+	sopClass, err := ds.Get(tag.SOPClassUID)
+	require.NoError(t, err)
+	assert.Equal(t, uid.LegacyConvertedEnhancedCTImageStorage.String(), sopClass.Value().String())
+
+	frames, err := ds.GetInts(tag.NumberOfFrames)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3}, frames)
+
+	// Frames are stacked in spatial order
+	pixels, err := ds.Get(tag.PixelData)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{10, 10, 10, 10, 20, 20, 20, 20, 30, 30, 30, 30}, pixels.Value().Bytes())
+
+	// Per-slice attributes moved into functional groups
+	assert.False(t, ds.Contains(tag.ImagePositionPatient))
+	assert.False(t, ds.Contains(tag.PixelSpacing))
+	assert.False(t, ds.Contains(tag.RescaleIntercept))
+
+	for i, want := range []string{`-10\-10\0`, `-10\-10\2.5`, `-10\-10\5`} {
+		v, err := dicom.FunctionalGroupValue(ds, i, tag.PlanePositionSequence, tag.ImagePositionPatient)
+		require.NoError(t, err)
+		assert.Equal(t, want, v.String(), "frame %d position", i)
+	}
+
+	shared, err := ds.GetSequenceItems(tag.SharedFunctionalGroupsSequence)
+	require.NoError(t, err)
+	require.Len(t, shared, 1)
+	for _, seq := range []tag.Tag{tag.PlaneOrientationSequence, tag.PixelMeasuresSequence, tag.PixelValueTransformationSequence} {
+		assert.True(t, shared[0].Contains(seq), "shared %s", seq)
+	}
+
+	spacing, err := dicom.FunctionalGroupValue(ds, 0, tag.PixelMeasuresSequence, tag.SpacingBetweenSlices)
+	require.NoError(t, err)
+	assert.Equal(t, "2.5", spacing.String())
+
+	// The result has a fresh SOP Instance UID and can be written and read back
+	for _, slice := range series {
+		assert.NotEqual(t, mustString(t, slice, tag.SOPInstanceUID), mustString(t, ds, tag.SOPInstanceUID))
+	}
+	path := filepath.Join(t.TempDir(), "enhanced.dcm")
+	require.NoError(t, dicom.WriteFile(path, ds))
+	parsed, err := dicom.ParseFile(path)
+	require.NoError(t, err)
+	v, err := dicom.FunctionalGroupValue(parsed, 2, tag.PlanePositionSequence, tag.ImagePositionPatient)
+	require.NoError(t, err)
+	assert.Equal(t, `-10\-10\5`, v.String())
+}
+
+func TestToEnhancedMultiFrame_PerFrameMacros(t *testing.T) {
+	first := newLegacyCTSlice(t, 0, 10)
+	second := newLegacyCTSlice(t, 1, 20)
+	third := newLegacyCTSlice(t, 3, 30)
+	require.NoError(t, third.Set(mustNewElement(tag.RescaleIntercept, vr.DecimalString,
+		mustNewStringValue(vr.DecimalString, []string{"-1000"}))))
+
+	ds, err := dicom.ToEnhancedMultiFrame([]*dicom.DataSet{first, second, third})
+	require.NoError(t, err)
+
+	// Differing rescale values go per-frame
+	intercept, err := dicom.FunctionalGroupValue(ds, 2, tag.PixelValueTransformationSequence, tag.RescaleIntercept)
+	require.NoError(t, err)
+	assert.Equal(t, "-1000", intercept.String())
+	shared, err := ds.GetSequenceItems(tag.SharedFunctionalGroupsSequence)
+	require.NoError(t, err)
+	assert.False(t, shared[0].Contains(tag.PixelValueTransformationSequence))
+
+	// Uneven slice gaps leave out Spacing Between Slices
+	_, err = dicom.FunctionalGroupValue(ds, 0, tag.PixelMeasuresSequence, tag.SpacingBetweenSlices)
+	assert.Error(t, err)
+}
+
+func TestToEnhancedMultiFrame_Errors(t *testing.T) {
+	t.Run("empty series", func(t *testing.T) {
+		_, err := dicom.ToEnhancedMultiFrame(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("differing orientation", func(t *testing.T) {
+		tilted := newLegacyCTSlice(t, 5, 30)
+		require.NoError(t, tilted.Set(mustNewElement(tag.ImageOrientationPatient, vr.DecimalString,
+			mustNewStringValue(vr.DecimalString, []string{"1", "0", "0", "0", "0.98", "0.2"}))))
+		_, err := dicom.ToEnhancedMultiFrame([]*dicom.DataSet{newLegacyCTSlice(t, 0, 10), tilted})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ImageOrientationPatient")
+	})
+
+	t.Run("duplicate position", func(t *testing.T) {
+		_, err := dicom.ToEnhancedMultiFrame([]*dicom.DataSet{newLegacyCTSlice(t, 0, 10), newLegacyCTSlice(t, 0, 20)})
+		assert.Error(t, err)
+	})
+
+	t.Run("differing pixel format", func(t *testing.T) {
+		other := newLegacyCTSlice(t, 5, 30)
+		rows, err := value.NewIntValue(vr.UnsignedShort, []int64{4})
+		require.NoError(t, err)
+		require.NoError(t, other.Set(mustNewElement(tag.Rows, vr.UnsignedShort, rows)))
+		_, err = dicom.ToEnhancedMultiFrame([]*dicom.DataSet{newLegacyCTSlice(t, 0, 10), other})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Rows")
+	})
+
+	t.Run("unsupported SOP class", func(t *testing.T) {
+		ds, err := newSecondaryCaptureBuilder().Build()
+		require.NoError(t, err)
+		_, err = dicom.ToEnhancedMultiFrame([]*dicom.DataSet{ds})
+		assert.Error(t, err)
+	})
+}
+
+// mustString returns the string value of t in ds.
+func mustString(t *testing.T, ds *dicom.DataSet, tg tag.Tag) string {
+	t.Helper()
+	elem, err := ds.Get(tg)
+	require.NoError(t, err)
+	return elem.Value().String()
+}
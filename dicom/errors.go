@@ -34,12 +34,59 @@ var ErrInvalidTransferSyntax = errors.New("invalid or unsupported transfer synta
 // https://dicom.nema.org/medical/dicom/current/output/html/part10.html#sect_7.1
 var ErrMissingTransferSyntax = errors.New("missing Transfer Syntax UID in File Meta Information")
 
+// ErrDuplicateTag indicates an element was added to a DataSet that already contains
+// an element with the same tag. Use DataSet.Set to replace elements.
+var ErrDuplicateTag = errors.New("duplicate tag")
+
 // ErrInvalidLength indicates an invalid value length was encountered.
 var ErrInvalidLength = errors.New("invalid value length")
 
+// ErrElementTooLarge indicates an element declared a value length larger than
+// ParseOptions.MaxElementLength or than the bytes remaining in the input. This
+// usually means the length field is corrupt; the parser refuses to allocate it.
+var ErrElementTooLarge = errors.New("element length exceeds limit")
+
+// ErrTruncatedPixelData indicates encapsulated pixel data of undefined length ended
+// before its Sequence Delimitation Item, as in a truncated or partially transferred
+// file. ElementParser.ReadElement returns it together with a Pixel Data element
+// holding the complete fragments read so far; in tolerant parsing that element is
+// kept and the problem reported as a warning.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_A.4
+var ErrTruncatedPixelData = errors.New("truncated encapsulated pixel data")
+
+// ErrTrailingData indicates bytes after the last complete element of a dataset that
+// do not form a valid element, such as padding or junk appended to a file.
+// With ParseOptions.AllowTrailingData (the default) they are ignored and reported as
+// a warning wrapping this error.
+var ErrTrailingData = errors.New("trailing data after dataset")
+
 // ErrUndefinedLength indicates an undefined length (0xFFFFFFFF) was encountered.
 // This is valid for sequences but requires special handling.
 //
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.5
 var ErrUndefinedLength = errors.New("undefined length encountered")
+
+// ErrGroupLengthMismatch indicates a Group Length element (gggg,0000) does not match
+// the number of bytes actually occupied by the remaining elements of the group.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part10.html#sect_7.1
+var ErrGroupLengthMismatch = errors.New("group length does not match encoded group")
+
+// ErrMissingRequiredAttribute indicates a dataset lacks an attribute required by
+// its Information Object Definition (IOD).
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#chapter_A
+var ErrMissingRequiredAttribute = errors.New("missing required attribute")
+
+// ErrInvalidSignature indicates a digital signature does not match the dataset: a
+// signed data element was changed or removed after signing, or the wrong key was
+// used to verify it.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part15.html#sect_C.1
+var ErrInvalidSignature = errors.New("digital signature does not match")
@@ -0,0 +1,99 @@
+package dicom
+
+import (
+	"fmt"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// FileMeta is a typed view of the File Meta Information group (0002,xxxx).
+//
+// Optional elements that are absent from the dataset are left as zero values.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part10.html#sect_7.1
+type FileMeta struct {
+	GroupLength                     uint32 // (0002,0000) File Meta Information Group Length
+	HasGroupLength                  bool   // true if (0002,0000) was present
+	Version                         []byte // (0002,0001) File Meta Information Version
+	MediaStorageSOPClassUID         string // (0002,0002)
+	MediaStorageSOPInstanceUID      string // (0002,0003)
+	TransferSyntaxUID               string // (0002,0010)
+	ImplementationClassUID          string // (0002,0012)
+	ImplementationVersionName       string // (0002,0013)
+	SourceApplicationEntityTitle    string // (0002,0016)
+	SendingApplicationEntityTitle   string // (0002,0017)
+	ReceivingApplicationEntityTitle string // (0002,0018)
+	PrivateInformationCreatorUID    string // (0002,0100)
+	PrivateInformation              []byte // (0002,0102)
+}
+
+// FileMetaInfo extracts the File Meta Information group from a dataset into a FileMeta.
+//
+// The Transfer Syntax UID (0002,0010) is required; ErrMissingTransferSyntax is
+// returned if it is absent or empty. All other elements are optional.
+//
+// Example:
+//
+//	ds, _ := dicom.ParseFile("image.dcm")
+//	meta, err := dicom.FileMetaInfo(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(meta.TransferSyntaxUID, meta.ImplementationClassUID)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part10.html#sect_7.1
+func FileMetaInfo(ds *DataSet) (*FileMeta, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("cannot read file meta information from nil dataset")
+	}
+
+	meta := &FileMeta{}
+
+	if elem, err := ds.Get(tag.New(0x0002, 0x0000)); err == nil {
+		if intVal, ok := elem.Value().(*value.IntValue); ok && len(intVal.Ints()) > 0 {
+			meta.GroupLength = uint32(intVal.Ints()[0])
+			meta.HasGroupLength = true
+		}
+	}
+
+	if elem, err := ds.Get(tag.New(0x0002, 0x0001)); err == nil {
+		if bytesVal, ok := elem.Value().(*value.BytesValue); ok {
+			meta.Version = bytesVal.Bytes()
+		}
+	}
+
+	if elem, err := ds.Get(tag.New(0x0002, 0x0102)); err == nil {
+		if bytesVal, ok := elem.Value().(*value.BytesValue); ok {
+			meta.PrivateInformation = bytesVal.Bytes()
+		}
+	}
+
+	stringFields := []struct {
+		tag tag.Tag
+		dst *string
+	}{
+		{tag.New(0x0002, 0x0002), &meta.MediaStorageSOPClassUID},
+		{tag.New(0x0002, 0x0003), &meta.MediaStorageSOPInstanceUID},
+		{tag.New(0x0002, 0x0010), &meta.TransferSyntaxUID},
+		{tag.New(0x0002, 0x0012), &meta.ImplementationClassUID},
+		{tag.New(0x0002, 0x0013), &meta.ImplementationVersionName},
+		{tag.New(0x0002, 0x0016), &meta.SourceApplicationEntityTitle},
+		{tag.New(0x0002, 0x0017), &meta.SendingApplicationEntityTitle},
+		{tag.New(0x0002, 0x0018), &meta.ReceivingApplicationEntityTitle},
+		{tag.New(0x0002, 0x0100), &meta.PrivateInformationCreatorUID},
+	}
+	for _, f := range stringFields {
+		if elem, err := ds.Get(f.tag); err == nil {
+			*f.dst = extractUIDString(elem)
+		}
+	}
+
+	if meta.TransferSyntaxUID == "" {
+		return nil, fmt.Errorf("%w: Transfer Syntax UID not found in File Meta Information", ErrMissingTransferSyntax)
+	}
+
+	return meta, nil
+}
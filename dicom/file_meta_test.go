@@ -0,0 +1,65 @@
+package dicom
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFileMetaInfo_Generated tests extracting File Meta Information generated by the writer.
+func TestFileMetaInfo_Generated(t *testing.T) {
+	ds := createTestDatasetForWriter(t)
+	ts := applyDefaultWriteOptions(WriteOptions{}).TransferSyntax
+
+	metaDS, err := generateFileMetaInformation(ds, ts)
+	require.NoError(t, err)
+
+	meta, err := FileMetaInfo(metaDS)
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte{0x00, 0x01}, meta.Version)
+	assert.Equal(t, "1.2.840.10008.5.1.4.1.1.1", meta.MediaStorageSOPClassUID)
+	assert.Equal(t, "1.2.840.10008.5.1.4.1.1.1.999", meta.MediaStorageSOPInstanceUID)
+	assert.Equal(t, "1.2.840.10008.1.2.1", meta.TransferSyntaxUID)
+	assert.Equal(t, "1.2.826.0.1.3680043.10.1451", meta.ImplementationClassUID)
+	assert.Equal(t, "GO-RADX_1_0", meta.ImplementationVersionName)
+	assert.False(t, meta.HasGroupLength, "generated meta has no group length until written")
+	assert.Empty(t, meta.SourceApplicationEntityTitle)
+}
+
+// TestFileMetaInfo_OptionalElements tests that optional AE titles are populated when present.
+func TestFileMetaInfo_OptionalElements(t *testing.T) {
+	ds := NewDataSet()
+
+	tsValue, err := value.NewStringValue(vr.UniqueIdentifier, []string{"1.2.840.10008.1.2"})
+	require.NoError(t, err)
+	tsElem, err := element.NewElement(tag.New(0x0002, 0x0010), vr.UniqueIdentifier, tsValue)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(tsElem))
+
+	aeValue, err := value.NewStringValue(vr.ApplicationEntity, []string{"STORESCU"})
+	require.NoError(t, err)
+	aeElem, err := element.NewElement(tag.New(0x0002, 0x0016), vr.ApplicationEntity, aeValue)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(aeElem))
+
+	meta, err := FileMetaInfo(ds)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.840.10008.1.2", meta.TransferSyntaxUID)
+	assert.Equal(t, "STORESCU", meta.SourceApplicationEntityTitle)
+}
+
+// TestFileMetaInfo_MissingTransferSyntax tests that a missing Transfer Syntax UID is an error.
+func TestFileMetaInfo_MissingTransferSyntax(t *testing.T) {
+	_, err := FileMetaInfo(NewDataSet())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrMissingTransferSyntax)
+
+	_, err = FileMetaInfo(nil)
+	assert.Error(t, err)
+}
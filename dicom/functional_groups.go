@@ -0,0 +1,72 @@
+package dicom
+
+import (
+	"fmt"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// FunctionalGroupValue returns an attribute of a functional group macro for one frame
+// of an enhanced multi-frame image.
+//
+// sequenceTag is the functional group macro sequence (for example
+// PixelMeasuresSequence or PlanePositionSequence) and attrTag the attribute inside its
+// first item. The Per-Frame Functional Groups Sequence (5200,9230) item for frameIndex
+// (0-based) is searched first, then the Shared Functional Groups Sequence (5200,9229),
+// so attributes that are constant across frames are found wherever the modality put
+// them.
+//
+// Returns an error if frameIndex is out of range or the attribute is in neither group.
+//
+// Example:
+//
+//	// Slice thickness of frame 3, shared or per-frame
+//	v, err := dicom.FunctionalGroupValue(ds, 2, tag.PixelMeasuresSequence, tag.SliceThickness)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	thickness, _ := v.(*value.StringValue).AsFloats()
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.16
+func FunctionalGroupValue(ds *DataSet, frameIndex int, sequenceTag, attrTag tag.Tag) (value.Value, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	if frames, err := ds.GetSequenceItems(tag.PerFrameFunctionalGroupsSequence); err == nil {
+		if frameIndex < 0 || frameIndex >= len(frames) {
+			return nil, fmt.Errorf("frame index %d out of range, Per-Frame Functional Groups Sequence has %d items",
+				frameIndex, len(frames))
+		}
+		if v, ok := functionalGroupAttribute(frames[frameIndex], sequenceTag, attrTag); ok {
+			return v, nil
+		}
+	} else if frameIndex < 0 {
+		return nil, fmt.Errorf("frame index %d out of range", frameIndex)
+	}
+
+	if shared, err := ds.GetSequenceItems(tag.SharedFunctionalGroupsSequence); err == nil && len(shared) > 0 {
+		if v, ok := functionalGroupAttribute(shared[0], sequenceTag, attrTag); ok {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("attribute %s in %s not found in per-frame (frame %d) or shared functional groups",
+		attrTag, sequenceTag, frameIndex)
+}
+
+// functionalGroupAttribute returns attrTag from the first item of sequenceTag in a
+// functional groups item.
+func functionalGroupAttribute(group *DataSet, sequenceTag, attrTag tag.Tag) (value.Value, bool) {
+	items, err := group.GetSequenceItems(sequenceTag)
+	if err != nil || len(items) == 0 {
+		return nil, false
+	}
+	elem, err := items[0].Get(attrTag)
+	if err != nil {
+		return nil, false
+	}
+	return elem.Value(), true
+}
@@ -0,0 +1,81 @@
+package dicom_test
+
+import (
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunctionalGroupValue(t *testing.T) {
+	// groupItem builds a functional groups item holding one macro sequence with one attribute
+	groupItem := func(seqTag, attrTag tag.Tag, v vr.VR, s string) *dicom.DataSet {
+		macro := dicom.NewDataSet()
+		require.NoError(t, macro.Add(mustNewElement(attrTag, v, mustNewStringValue(v, []string{s}))))
+		seq, err := dicom.NewSequenceElement(seqTag, []*dicom.DataSet{macro})
+		require.NoError(t, err)
+		item := dicom.NewDataSet()
+		require.NoError(t, item.Add(seq))
+		return item
+	}
+
+	shared := groupItem(tag.PixelMeasuresSequence, tag.SliceThickness, vr.DecimalString, "2.5")
+	frame0 := groupItem(tag.PlanePositionSequence, tag.ImagePositionPatient, vr.DecimalString, "0")
+	frame1 := groupItem(tag.PixelMeasuresSequence, tag.SliceThickness, vr.DecimalString, "1.0")
+
+	ds := dicom.NewDataSet()
+	sharedSeq, err := dicom.NewSequenceElement(tag.SharedFunctionalGroupsSequence, []*dicom.DataSet{shared})
+	require.NoError(t, err)
+	perFrameSeq, err := dicom.NewSequenceElement(tag.PerFrameFunctionalGroupsSequence, []*dicom.DataSet{frame0, frame1})
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(sharedSeq))
+	require.NoError(t, ds.Add(perFrameSeq))
+
+	tests := []struct {
+		name    string
+		frame   int
+		seqTag  tag.Tag
+		attrTag tag.Tag
+		want    string
+	}{
+		{"shared fallback", 0, tag.PixelMeasuresSequence, tag.SliceThickness, "2.5"},
+		{"per-frame overrides shared", 1, tag.PixelMeasuresSequence, tag.SliceThickness, "1.0"},
+		{"per-frame only", 0, tag.PlanePositionSequence, tag.ImagePositionPatient, "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := dicom.FunctionalGroupValue(ds, tt.frame, tt.seqTag, tt.attrTag)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v.String())
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := dicom.FunctionalGroupValue(ds, 1, tag.PlanePositionSequence, tag.ImagePositionPatient)
+		assert.Error(t, err)
+	})
+
+	t.Run("frame out of range", func(t *testing.T) {
+		_, err := dicom.FunctionalGroupValue(ds, 2, tag.PixelMeasuresSequence, tag.SliceThickness)
+		assert.Error(t, err)
+		_, err = dicom.FunctionalGroupValue(ds, -1, tag.PixelMeasuresSequence, tag.SliceThickness)
+		assert.Error(t, err)
+	})
+
+	t.Run("shared groups only", func(t *testing.T) {
+		sharedOnly := dicom.NewDataSet()
+		require.NoError(t, sharedOnly.Add(sharedSeq))
+		v, err := dicom.FunctionalGroupValue(sharedOnly, 5, tag.PixelMeasuresSequence, tag.SliceThickness)
+		require.NoError(t, err)
+		assert.Equal(t, "2.5", v.String())
+	})
+
+	t.Run("nil dataset", func(t *testing.T) {
+		_, err := dicom.FunctionalGroupValue(nil, 0, tag.PixelMeasuresSequence, tag.SliceThickness)
+		assert.Error(t, err)
+	})
+}
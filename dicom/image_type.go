@@ -0,0 +1,106 @@
+package dicom
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// ImageTypeInfo is the parsed form of Image Type (0008,0008), which characterizes an
+// image by its pixel data, its role in the examination and modality-specific
+// properties.
+type ImageTypeInfo struct {
+	// PixelDataCharacteristics is Value 1: ORIGINAL if the pixel values are based on
+	// the original patient examination, DERIVED if they have been derived from other
+	// images, or MIXED for an enhanced image whose frames differ.
+	PixelDataCharacteristics string
+
+	// PatientExaminationCharacteristics is Value 2: PRIMARY for images created as a
+	// direct result of the examination, SECONDARY for images created after it, or
+	// MIXED.
+	PatientExaminationCharacteristics string
+
+	// ModalitySpecific holds Value 3 and any further values, such as AXIAL or
+	// LOCALIZER for CT, in order. Empty when Image Type has two values or fewer.
+	ModalitySpecific []string
+}
+
+// IsOriginal reports whether the pixel values are original (Value 1 ORIGINAL).
+func (it *ImageTypeInfo) IsOriginal() bool {
+	return it.PixelDataCharacteristics == "ORIGINAL"
+}
+
+// IsDerived reports whether the pixel values were derived from other images, as for
+// reformats, projections and subtractions (Value 1 DERIVED).
+func (it *ImageTypeInfo) IsDerived() bool {
+	return it.PixelDataCharacteristics == "DERIVED"
+}
+
+// IsPrimary reports whether the image was created as a direct result of the patient
+// examination (Value 2 PRIMARY).
+func (it *ImageTypeInfo) IsPrimary() bool {
+	return it.PatientExaminationCharacteristics == "PRIMARY"
+}
+
+// IsLocalizer reports whether the image is a localizer (scout or topogram) used to
+// plan the acquisition, marked by a LOCALIZER value from Value 3 on.
+func (it *ImageTypeInfo) IsLocalizer() bool {
+	return slices.Contains(it.ModalitySpecific, "LOCALIZER")
+}
+
+// ImageType parses Image Type (0008,0008) of ds. Values are trimmed of padding and
+// converted to upper case; empty values are kept so that each value keeps its
+// position.
+//
+// Returns an error if:
+//   - ds is nil
+//   - Image Type is absent, empty or not a string value
+//
+// Example:
+//
+//	info, err := dicom.ImageType(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if info.IsLocalizer() {
+//	    continue // skip scouts when stacking slices
+//	}
+//	if info.IsDerived() {
+//	    showDerivedWarning()
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.1.1.2
+func ImageType(ds *DataSet) (*ImageTypeInfo, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+	elem, err := ds.Get(tag.ImageType)
+	if err != nil {
+		return nil, fmt.Errorf("image type: %w", err)
+	}
+	strVal, ok := elem.Value().(*value.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("image type: unexpected VR %s", elem.VR())
+	}
+
+	values := make([]string, len(strVal.Strings()))
+	for i, s := range strVal.Strings() {
+		values[i] = strings.ToUpper(strings.TrimSpace(s))
+	}
+	if strings.Join(values, "") == "" {
+		return nil, fmt.Errorf("image type is empty")
+	}
+
+	info := &ImageTypeInfo{PixelDataCharacteristics: values[0]}
+	if len(values) > 1 {
+		info.PatientExaminationCharacteristics = values[1]
+	}
+	if len(values) > 2 {
+		info.ModalitySpecific = values[2:]
+	}
+	return info, nil
+}
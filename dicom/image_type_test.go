@@ -0,0 +1,94 @@
+package dicom_test
+
+import (
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newImageTypeDataSet(t *testing.T, values ...string) *dicom.DataSet {
+	t.Helper()
+	ds := dicom.NewDataSet()
+	require.NoError(t, ds.Set(mustNewElement(tag.ImageType, vr.CodeString, mustNewStringValue(vr.CodeString, values))))
+	return ds
+}
+
+func TestImageType(t *testing.T) {
+	tests := []struct {
+		name                                  string
+		values                                []string
+		want                                  dicom.ImageTypeInfo
+		original, derived, primary, localizer bool
+	}{
+		{
+			name:     "CT axial",
+			values:   []string{"ORIGINAL", "PRIMARY", "AXIAL"},
+			want:     dicom.ImageTypeInfo{PixelDataCharacteristics: "ORIGINAL", PatientExaminationCharacteristics: "PRIMARY", ModalitySpecific: []string{"AXIAL"}},
+			original: true, primary: true,
+		},
+		{
+			name:     "CT localizer",
+			values:   []string{"ORIGINAL", "PRIMARY", "LOCALIZER"},
+			want:     dicom.ImageTypeInfo{PixelDataCharacteristics: "ORIGINAL", PatientExaminationCharacteristics: "PRIMARY", ModalitySpecific: []string{"LOCALIZER"}},
+			original: true, primary: true, localizer: true,
+		},
+		{
+			name:    "secondary reformat",
+			values:  []string{"DERIVED", "SECONDARY", "REFORMATTED", "AVERAGE"},
+			want:    dicom.ImageTypeInfo{PixelDataCharacteristics: "DERIVED", PatientExaminationCharacteristics: "SECONDARY", ModalitySpecific: []string{"REFORMATTED", "AVERAGE"}},
+			derived: true,
+		},
+		{
+			name:     "padding and case",
+			values:   []string{" original", "primary ", "", "localizer"},
+			want:     dicom.ImageTypeInfo{PixelDataCharacteristics: "ORIGINAL", PatientExaminationCharacteristics: "PRIMARY", ModalitySpecific: []string{"", "LOCALIZER"}},
+			original: true, primary: true, localizer: true,
+		},
+		{
+			name:   "enhanced mixed",
+			values: []string{"MIXED", "MIXED"},
+			want:   dicom.ImageTypeInfo{PixelDataCharacteristics: "MIXED", PatientExaminationCharacteristics: "MIXED"},
+		},
+		{
+			name:     "value 1 only",
+			values:   []string{"ORIGINAL"},
+			want:     dicom.ImageTypeInfo{PixelDataCharacteristics: "ORIGINAL"},
+			original: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := dicom.ImageType(newImageTypeDataSet(t, tt.values...))
+			require.NoError(t, err)
+			assert.Equal(t, &tt.want, info)
+			assert.Equal(t, tt.original, info.IsOriginal())
+			assert.Equal(t, tt.derived, info.IsDerived())
+			assert.Equal(t, tt.primary, info.IsPrimary())
+			assert.Equal(t, tt.localizer, info.IsLocalizer())
+		})
+	}
+}
+
+func TestImageType_Errors(t *testing.T) {
+	_, err := dicom.ImageType(nil)
+	assert.Error(t, err)
+
+	_, err = dicom.ImageType(dicom.NewDataSet())
+	assert.Error(t, err, "Image Type absent")
+
+	_, err = dicom.ImageType(newImageTypeDataSet(t))
+	assert.Error(t, err, "Image Type empty")
+
+	ds := dicom.NewDataSet()
+	bytesVal, err := value.NewBytesValue(vr.Unknown, []byte("ORIGINAL"))
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(mustNewElement(tag.ImageType, vr.Unknown, bytesVal)))
+	_, err = dicom.ImageType(ds)
+	assert.Error(t, err, "Image Type read as UN")
+}
@@ -0,0 +1,161 @@
+package dicom
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+)
+
+// iodDefinition lists what a SOP class's IOD requires beyond the common baseline.
+type iodDefinition struct {
+	modality string    // Default Modality (0008,0060) for instances of this class
+	required []tag.Tag // Type 1 attributes of the modality-specific modules
+	type2    []tag.Tag // Type 2 attributes of the modality-specific modules
+}
+
+// commonRequiredTags are Type 1 attributes of the SOP Common, General Study and
+// General Series modules, shared by every composite IOD.
+var commonRequiredTags = []tag.Tag{
+	tag.SOPClassUID,
+	tag.SOPInstanceUID,
+	tag.StudyInstanceUID,
+	tag.SeriesInstanceUID,
+	tag.Modality,
+}
+
+// commonType2Tags are Type 2 attributes of the Patient, General Study, General Series
+// and General Equipment modules, shared by every composite IOD. They must be present
+// but may be empty.
+var commonType2Tags = []tag.Tag{
+	tag.PatientName,
+	tag.PatientID,
+	tag.PatientBirthDate,
+	tag.PatientSex,
+	tag.StudyDate,
+	tag.StudyTime,
+	tag.ReferringPhysicianName,
+	tag.StudyID,
+	tag.AccessionNumber,
+	tag.SeriesNumber,
+	tag.Manufacturer,
+}
+
+// generalImageType2Tags are Type 2 attributes of the General Image module.
+var generalImageType2Tags = []tag.Tag{
+	tag.InstanceNumber,
+}
+
+// imagePixelRequiredTags are Type 1 attributes of the Image Pixel module.
+var imagePixelRequiredTags = []tag.Tag{
+	tag.SamplesPerPixel,
+	tag.PhotometricInterpretation,
+	tag.Rows,
+	tag.Columns,
+	tag.BitsAllocated,
+	tag.BitsStored,
+	tag.HighBit,
+	tag.PixelRepresentation,
+}
+
+// iodDefinitions maps SOP Class UIDs to their IOD requirements.
+var iodDefinitions = map[string]iodDefinition{
+	uid.CTImageStorage.String(): {
+		modality: "CT",
+		required: append([]tag.Tag{tag.ImageType, tag.RescaleIntercept, tag.RescaleSlope}, imagePixelRequiredTags...),
+		type2:    generalImageType2Tags,
+	},
+	uid.MRImageStorage.String(): {
+		modality: "MR",
+		required: append([]tag.Tag{tag.ImageType, tag.ScanningSequence, tag.SequenceVariant}, imagePixelRequiredTags...),
+		type2:    generalImageType2Tags,
+	},
+	uid.ComputedRadiographyImageStorage.String(): {
+		modality: "CR",
+		required: imagePixelRequiredTags,
+		type2:    generalImageType2Tags,
+	},
+	uid.DigitalXRayImageStorageForPresentation.String(): {
+		modality: "DX",
+		required: append([]tag.Tag{tag.ImageType}, imagePixelRequiredTags...),
+		type2:    generalImageType2Tags,
+	},
+	uid.UltrasoundImageStorage.String(): {
+		modality: "US",
+		required: imagePixelRequiredTags,
+		type2:    generalImageType2Tags,
+	},
+	uid.SecondaryCaptureImageStorage.String(): {
+		modality: "OT",
+		required: append([]tag.Tag{tag.ConversionType}, imagePixelRequiredTags...),
+		type2:    generalImageType2Tags,
+	},
+}
+
+// ValidateIOD checks that ds contains the Type 1 and Type 2 attributes required by
+// the IOD of its SOP Class UID (0008,0016).
+//
+// Every dataset must carry the SOP Common, Patient, General Study, General Series
+// and General Equipment attributes. CT, MR, CR, DX, US and Secondary Capture image
+// storage classes are additionally checked for their image modules; other SOP
+// classes are checked against the common attributes only. Type 1 attributes must be
+// present with a non-empty value. Type 2 attributes must be present but may be empty
+// (zero length), see DataSet.IsEmpty.
+//
+// Returns an error wrapping ErrMissingRequiredAttribute that names every missing
+// attribute, or nil if the dataset is complete.
+//
+// Example:
+//
+//	if err := dicom.ValidateIOD(ds); err != nil {
+//	    log.Printf("incomplete instance: %v", err)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#chapter_A
+func ValidateIOD(ds *DataSet) error {
+	if ds == nil {
+		return fmt.Errorf("dataset is nil")
+	}
+
+	required, type2 := requiredTags(ds)
+
+	var missing []string
+	for _, t := range required {
+		elem, err := ds.Get(t)
+		if err != nil || elem.Value() == nil || strings.TrimSpace(elem.Value().String()) == "" {
+			missing = append(missing, tagKeyword(t))
+		}
+	}
+	for _, t := range type2 {
+		if !ds.Contains(t) {
+			missing = append(missing, tagKeyword(t)+" (Type 2)")
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingRequiredAttribute, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// requiredTags returns the Type 1 and Type 2 attributes of the IOD of ds's SOP class.
+func requiredTags(ds *DataSet) (required, type2 []tag.Tag) {
+	required, type2 = commonRequiredTags, commonType2Tags
+	if elem, err := ds.Get(tag.SOPClassUID); err == nil {
+		if def, ok := iodDefinitions[strings.TrimSpace(elem.Value().String())]; ok {
+			required = append(append([]tag.Tag{}, required...), def.required...)
+			type2 = append(append([]tag.Tag{}, type2...), def.type2...)
+		}
+	}
+	return required, type2
+}
+
+// tagKeyword returns the dictionary keyword of t, falling back to its numeric form.
+func tagKeyword(t tag.Tag) string {
+	if info, err := tag.Find(t); err == nil && info.Keyword != "" {
+		return info.Keyword
+	}
+	return t.String()
+}
@@ -0,0 +1,408 @@
+package dicom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// MarshalJSONOptions configures DICOM JSON encoding.
+type MarshalJSONOptions struct {
+	// NumericStringsAsNumbers encodes DS and IS values as JSON numbers (42), the form
+	// PS3.18 prefers. Set to false to encode them as JSON strings ("42") for
+	// consumers that reject numbers for these VRs. Values that are not valid numbers
+	// are encoded as strings either way.
+	// If nil, defaults to true.
+	NumericStringsAsNumbers *bool
+}
+
+// MarshalJSON encodes the dataset in the DICOM JSON Model: an object keyed by the
+// eight-digit uppercase hexadecimal tag, in ascending tag order, where each
+// attribute is encoded by its value's MarshalJSON method.
+//
+// Sequence items are encoded recursively, so json.Marshal(ds) produces a complete
+// DICOM JSON instance. It is equivalent to MarshalJSONWithOptions with the default
+// options.
+//
+// Example:
+//
+//	data, err := json.Marshal(ds)
+//	// {"00100010":{"vr":"PN","Value":[{"Alphabetic":"Doe^John"}]}, ...}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part18.html#chapter_F
+func (ds *DataSet) MarshalJSON() ([]byte, error) {
+	return ds.MarshalJSONWithOptions(MarshalJSONOptions{})
+}
+
+// MarshalJSONWithOptions encodes the dataset in the DICOM JSON Model as MarshalJSON
+// does, with the encoding choices PS3.18 leaves open taken from opts. The options
+// apply to sequence items as well.
+//
+// Example:
+//
+//	// For a server that only accepts DS and IS values as strings
+//	asNumbers := false
+//	data, err := ds.MarshalJSONWithOptions(dicom.MarshalJSONOptions{
+//	    NumericStringsAsNumbers: &asNumbers,
+//	})
+//	// {"00180050":{"vr":"DS","Value":["2.5"]}, ...}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part18.html#sect_F.2.3.1
+func (ds *DataSet) MarshalJSONWithOptions(opts MarshalJSONOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, elem := range ds.Elements() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `"%08X":`, elem.Tag().Uint32())
+
+		data, err := marshalJSONValue(elem.Value(), opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", elem.Tag(), err)
+		}
+		if data == nil {
+			// Elements without a value carry only their VR
+			fmt.Fprintf(&buf, `{"vr":"%s"}`, elem.VR().String())
+			continue
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalJSONValue encodes val as a DICOM JSON attribute object, or returns nil if
+// val cannot be encoded.
+func marshalJSONValue(val value.Value, opts MarshalJSONOptions) ([]byte, error) {
+	if opts.NumericStringsAsNumbers != nil && !*opts.NumericStringsAsNumbers {
+		switch v := val.(type) {
+		case *value.StringValue:
+			return v.MarshalJSONStrings()
+		case *value.SequenceValue:
+			return marshalJSONSequence(v, opts)
+		}
+	}
+
+	m, ok := val.(json.Marshaler)
+	if !ok {
+		return nil, nil
+	}
+	return m.MarshalJSON()
+}
+
+// marshalJSONSequence encodes seq as SequenceValue.MarshalJSON does, passing opts
+// on to its items.
+func marshalJSONSequence(seq *value.SequenceValue, opts MarshalJSONOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"vr":"SQ"`)
+	if seq.Len() > 0 {
+		buf.WriteString(`,"Value":[`)
+		for i, item := range seq.Items() {
+			itemDS, ok := item.(*DataSet)
+			if !ok {
+				return nil, fmt.Errorf("sequence item %d is %T, not *dicom.DataSet", i, item)
+			}
+			data, err := itemDS.MarshalJSONWithOptions(opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal sequence item %d: %w", i, err)
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(data)
+		}
+		buf.WriteByte(']')
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonAttribute is a DICOM JSON attribute object as decoded by UnmarshalJSON.
+type jsonAttribute struct {
+	VR           string            `json:"vr"`
+	Value        []json.RawMessage `json:"Value"`
+	InlineBinary []byte            `json:"InlineBinary"`
+	BulkDataURI  string            `json:"BulkDataURI"`
+}
+
+// UnmarshalJSON decodes a DICOM JSON Model object into ds, adding its attributes
+// and replacing any elements with the same tags. Sequence items are decoded
+// recursively.
+//
+// Both encodings PS3.18 allows for numbers are accepted transparently: DS, IS and
+// the binary numeric VRs may be JSON numbers (42) or strings holding a number
+// ("42"), so JSON from servers that disagree on the encoding decodes to the same
+// dataset. PN values may be objects of component groups or plain strings.
+//
+// Returns an error if:
+//   - data is not a JSON object of attribute objects
+//   - a key is not an eight-digit hexadecimal tag
+//   - an attribute has a missing or unknown VR (ErrInvalidVR)
+//   - a value is not valid for its VR
+//   - an attribute references its value by BulkDataURI, which is not retrieved
+//
+// Example:
+//
+//	ds := dicom.NewDataSet()
+//	if err := json.Unmarshal(data, ds); err != nil {
+//	    log.Fatal(err)
+//	}
+//	thickness, _ := ds.GetFloats(tag.SliceThickness) // from 2.5 or "2.5"
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part18.html#sect_F.2
+func (ds *DataSet) UnmarshalJSON(data []byte) error {
+	var attrs map[string]jsonAttribute
+	if err := json.Unmarshal(data, &attrs); err != nil {
+		return fmt.Errorf("failed to decode DICOM JSON: %w", err)
+	}
+	if ds.elements == nil {
+		ds.elements = make(map[tag.Tag]*element.Element, len(attrs))
+	}
+
+	for key, attr := range attrs {
+		t, err := parseJSONTag(key)
+		if err != nil {
+			return err
+		}
+		elem, err := attr.element(t)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal %s: %w", t, err)
+		}
+		ds.elements[t] = elem
+	}
+	return nil
+}
+
+// parseJSONTag parses a DICOM JSON attribute key such as "00100010".
+func parseJSONTag(s string) (tag.Tag, error) {
+	n, err := strconv.ParseUint(s, 16, 32)
+	if len(s) != 8 || err != nil {
+		return tag.Tag{}, fmt.Errorf("%w: %q is not an eight-digit hexadecimal tag", ErrInvalidTag, s)
+	}
+	return tag.New(uint16(n>>16), uint16(n)), nil
+}
+
+// element converts the attribute to an element with tag t.
+func (a jsonAttribute) element(t tag.Tag) (*element.Element, error) {
+	if a.VR == "" {
+		return nil, fmt.Errorf("%w: missing vr", ErrInvalidVR)
+	}
+	v, err := vr.Parse(a.VR)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidVR, a.VR)
+	}
+	if a.BulkDataURI != "" {
+		return nil, fmt.Errorf("value is referenced by BulkDataURI %q, which is not retrieved", a.BulkDataURI)
+	}
+
+	if v == vr.SequenceOfItems {
+		items := make([]*DataSet, len(a.Value))
+		for i, raw := range a.Value {
+			items[i] = NewDataSet()
+			if err := items[i].UnmarshalJSON(raw); err != nil {
+				return nil, fmt.Errorf("sequence item %d: %w", i, err)
+			}
+		}
+		return NewSequenceElement(t, items)
+	}
+
+	val, err := a.value(v)
+	if err != nil {
+		return nil, err
+	}
+	return element.NewElement(t, v, val)
+}
+
+// value decodes the attribute's value as VR v, which is not SQ.
+func (a jsonAttribute) value(v vr.VR) (value.Value, error) {
+	if v.IsBinaryType() {
+		return value.NewBytesValue(v, a.InlineBinary)
+	}
+	if len(a.Value) == 0 {
+		return emptyValue(v)
+	}
+
+	switch {
+	case v.IsStringType():
+		values := make([]string, len(a.Value))
+		for i, raw := range a.Value {
+			s, err := decodeJSONString(v, raw)
+			if err != nil {
+				return nil, fmt.Errorf("value %d: %w", i, err)
+			}
+			values[i] = s
+		}
+		return value.NewStringValue(v, values)
+	case v == vr.FloatingPointSingle || v == vr.FloatingPointDouble:
+		values := make([]float64, len(a.Value))
+		for i, raw := range a.Value {
+			s, err := decodeJSONNumber(raw)
+			if err != nil {
+				return nil, fmt.Errorf("value %d: %w", i, err)
+			}
+			if values[i], err = strconv.ParseFloat(s, 64); err != nil {
+				return nil, fmt.Errorf("value %d: %w", i, err)
+			}
+		}
+		return value.NewFloatValue(v, values)
+	case v == vr.AttributeTag:
+		tags := make([]tag.Tag, len(a.Value))
+		for i, raw := range a.Value {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, fmt.Errorf("value %d: %w", i, err)
+			}
+			t, err := parseJSONTag(s)
+			if err != nil {
+				return nil, fmt.Errorf("value %d: %w", i, err)
+			}
+			tags[i] = t
+		}
+		return value.NewTagValue(tags), nil
+	case v.IsNumericType():
+		values := make([]int64, len(a.Value))
+		for i, raw := range a.Value {
+			s, err := decodeJSONNumber(raw)
+			if err != nil {
+				return nil, fmt.Errorf("value %d: %w", i, err)
+			}
+			if values[i], err = parseJSONInt(v, s); err != nil {
+				return nil, fmt.Errorf("value %d: %w", i, err)
+			}
+		}
+		return value.NewIntValue(v, values)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidVR, v.String())
+	}
+}
+
+// decodeJSONString decodes a single value of a string VR. null is an empty value.
+func decodeJSONString(v vr.VR, raw json.RawMessage) (string, error) {
+	if string(raw) == "null" {
+		return "", nil
+	}
+
+	switch v {
+	case vr.PersonName:
+		return decodeJSONPersonName(raw)
+	case vr.DecimalString:
+		s, err := decodeJSONNumber(raw)
+		if err != nil || raw[0] == '"' {
+			return s, err
+		}
+		return formatJSONDecimal(s)
+	case vr.IntegerString:
+		s, err := decodeJSONNumber(raw)
+		if err != nil || raw[0] == '"' {
+			return s, err
+		}
+		n, err := parseJSONInt(vr.SignedVeryLong, s)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(n, 10), nil
+	default:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+}
+
+// decodeJSONNumber returns the text of a value encoded either as a JSON number or
+// as a string. Strings are returned as given.
+func decodeJSONNumber(raw json.RawMessage) (string, error) {
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil || n == "" {
+		return "", fmt.Errorf("%s is neither a number nor a string", raw)
+	}
+	return string(n), nil
+}
+
+// parseJSONInt parses an integer of VR v. Numbers with an exponent or a zero
+// fraction, such as 1e3 or 512.0, are accepted as JSON allows them.
+func parseJSONInt(v vr.VR, s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if v == vr.UnsignedVeryLong {
+		if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+			if n > math.MaxInt64 {
+				return 0, fmt.Errorf("UV value %d is out of range", n)
+			}
+			return int64(n), nil
+		}
+	} else if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > 1<<53 {
+		return 0, fmt.Errorf("%q is not an integer", s)
+	}
+	return int64(f), nil
+}
+
+// formatJSONDecimal returns a DS value for the JSON number s, reformatting numbers
+// too long for the 16 bytes DS allows.
+func formatJSONDecimal(s string) (string, error) {
+	if len(s) <= 16 {
+		return s, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return "", err
+	}
+	for prec := 16; prec > 0; prec-- {
+		if formatted := strconv.FormatFloat(f, 'g', prec, 64); len(formatted) <= 16 {
+			return formatted, nil
+		}
+	}
+	return "", fmt.Errorf("DS value %s cannot be represented in 16 bytes", s)
+}
+
+// decodeJSONPersonName decodes a PN value from an object of component groups. A
+// plain string is accepted as the value itself.
+func decodeJSONPersonName(raw json.RawMessage) (string, error) {
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+
+	var groups map[string]string
+	if err := json.Unmarshal(raw, &groups); err != nil {
+		return "", fmt.Errorf("invalid PN value: %w", err)
+	}
+	parts := []string{groups["Alphabetic"], groups["Ideographic"], groups["Phonetic"]}
+	for len(parts) > 1 && parts[len(parts)-1] == "" {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, "="), nil
+}
+
+// Verify DataSet implements json.Marshaler and json.Unmarshaler at compile time
+var (
+	_ json.Marshaler   = (*DataSet)(nil)
+	_ json.Unmarshaler = (*DataSet)(nil)
+)
@@ -0,0 +1,201 @@
+package dicom_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSet_MarshalJSON(t *testing.T) {
+	item := dicom.NewDataSet()
+	require.NoError(t, item.Add(mustNewElement(tag.CodeValue, vr.ShortString,
+		mustNewStringValue(vr.ShortString, []string{"T-D1100"}))))
+
+	rows, err := value.NewIntValue(vr.UnsignedShort, []int64{512})
+	require.NoError(t, err)
+	pixels, err := value.NewBytesValue(vr.OtherByte, []byte{0xFF, 0x00})
+	require.NoError(t, err)
+	seq, err := dicom.NewSequenceElement(tag.AnatomicRegionSequence, []*dicom.DataSet{item})
+	require.NoError(t, err)
+
+	ds := dicom.NewDataSet()
+	require.NoError(t, ds.Add(mustNewElement(tag.PatientName, vr.PersonName,
+		mustNewStringValue(vr.PersonName, []string{"Doe^John"}))))
+	require.NoError(t, ds.Add(mustNewElement(tag.Rows, vr.UnsignedShort, rows)))
+	require.NoError(t, ds.Add(mustNewElement(tag.PixelData, vr.OtherByte, pixels)))
+	require.NoError(t, ds.Add(seq))
+
+	data, err := json.Marshal(ds)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"00082218": {"vr":"SQ","Value":[{"00080100":{"vr":"SH","Value":["T-D1100"]}}]},
+		"00100010": {"vr":"PN","Value":[{"Alphabetic":"Doe^John"}]},
+		"00280010": {"vr":"US","Value":[512]},
+		"7FE00010": {"vr":"OB","InlineBinary":"/wA="}
+	}`, string(data))
+
+	empty, err := json.Marshal(dicom.NewDataSet())
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(empty))
+}
+
+func newJSONNumericDataSet(t *testing.T) *dicom.DataSet {
+	t.Helper()
+	item := dicom.NewDataSet()
+	require.NoError(t, item.Add(mustNewElement(tag.ReferencedFrameNumber, vr.IntegerString,
+		mustNewStringValue(vr.IntegerString, []string{"3"}))))
+	seq, err := dicom.NewSequenceElement(tag.ReferencedImageSequence, []*dicom.DataSet{item})
+	require.NoError(t, err)
+
+	ds := dicom.NewDataSet()
+	require.NoError(t, ds.Add(mustNewElement(tag.SliceThickness, vr.DecimalString,
+		mustNewStringValue(vr.DecimalString, []string{"2.5"}))))
+	require.NoError(t, ds.Add(mustNewElement(tag.InstanceNumber, vr.IntegerString,
+		mustNewStringValue(vr.IntegerString, []string{"42"}))))
+	require.NoError(t, ds.Add(seq))
+	return ds
+}
+
+func TestDataSet_MarshalJSONWithOptions(t *testing.T) {
+	ds := newJSONNumericDataSet(t)
+
+	asNumbers, asStrings := true, false
+	tests := []struct {
+		name string
+		opts dicom.MarshalJSONOptions
+		want string
+	}{
+		{
+			name: "default",
+			want: `{
+				"00081140": {"vr":"SQ","Value":[{"00081160":{"vr":"IS","Value":[3]}}]},
+				"00180050": {"vr":"DS","Value":[2.5]},
+				"00200013": {"vr":"IS","Value":[42]}
+			}`,
+		},
+		{
+			name: "numbers",
+			opts: dicom.MarshalJSONOptions{NumericStringsAsNumbers: &asNumbers},
+			want: `{
+				"00081140": {"vr":"SQ","Value":[{"00081160":{"vr":"IS","Value":[3]}}]},
+				"00180050": {"vr":"DS","Value":[2.5]},
+				"00200013": {"vr":"IS","Value":[42]}
+			}`,
+		},
+		{
+			name: "strings",
+			opts: dicom.MarshalJSONOptions{NumericStringsAsNumbers: &asStrings},
+			want: `{
+				"00081140": {"vr":"SQ","Value":[{"00081160":{"vr":"IS","Value":["3"]}}]},
+				"00180050": {"vr":"DS","Value":["2.5"]},
+				"00200013": {"vr":"IS","Value":["42"]}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ds.MarshalJSONWithOptions(tt.opts)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestDataSet_UnmarshalJSON_RoundTrip(t *testing.T) {
+	ds := newJSONNumericDataSet(t)
+	require.NoError(t, ds.Add(mustNewElement(tag.PatientName, vr.PersonName,
+		mustNewStringValue(vr.PersonName, []string{"Yamada^Tarou=山田^太郎"}))))
+	require.NoError(t, ds.Add(mustNewElement(tag.ImageType, vr.CodeString,
+		mustNewStringValue(vr.CodeString, []string{"ORIGINAL", "PRIMARY"}))))
+	require.NoError(t, ds.Add(mustNewElement(tag.StudyDescription, vr.LongString,
+		mustNewStringValue(vr.LongString, []string{}))))
+	rows, err := value.NewIntValue(vr.UnsignedShort, []int64{512})
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(mustNewElement(tag.Rows, vr.UnsignedShort, rows)))
+	mapped, err := value.NewFloatValue(vr.FloatingPointDouble, []float64{0.5, 1.25})
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(mustNewElement(tag.DoubleFloatRealWorldValueLastValueMapped, vr.FloatingPointDouble, mapped)))
+	require.NoError(t, ds.Add(mustNewElement(tag.FrameIncrementPointer, vr.AttributeTag,
+		value.NewTagValue([]tag.Tag{tag.FrameTime}))))
+	pixels, err := value.NewBytesValue(vr.OtherByte, []byte{0xFF, 0x00})
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(mustNewElement(tag.PixelData, vr.OtherByte, pixels)))
+
+	asStrings := false
+	for _, opts := range []dicom.MarshalJSONOptions{{}, {NumericStringsAsNumbers: &asStrings}} {
+		data, err := ds.MarshalJSONWithOptions(opts)
+		require.NoError(t, err)
+
+		decoded := dicom.NewDataSet()
+		require.NoError(t, json.Unmarshal(data, decoded))
+		assert.True(t, ds.Equals(decoded), "decoded from %s", data)
+
+		again, err := decoded.MarshalJSONWithOptions(opts)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(data), string(again))
+	}
+}
+
+func TestDataSet_UnmarshalJSON_NumericForms(t *testing.T) {
+	forms := []string{
+		`{"00180050":{"vr":"DS","Value":[2.5]},"00200013":{"vr":"IS","Value":[42]},"00280010":{"vr":"US","Value":[512]}}`,
+		`{"00180050":{"vr":"DS","Value":["2.5"]},"00200013":{"vr":"IS","Value":["42"]},"00280010":{"vr":"US","Value":["512"]}}`,
+	}
+	for _, form := range forms {
+		var ds dicom.DataSet
+		require.NoError(t, json.Unmarshal([]byte(form), &ds), form)
+		assert.Equal(t, "2.5", stringOf(t, &ds, tag.SliceThickness))
+		assert.Equal(t, "42", stringOf(t, &ds, tag.InstanceNumber))
+		rows, err := ds.GetInts(tag.Rows)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{512}, rows)
+	}
+
+	// Numbers are converted to valid DS and IS values
+	var ds dicom.DataSet
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"00180050":{"vr":"DS","Value":[0.1234567890123456789]},
+		"00200013":{"vr":"IS","Value":[1e3]},
+		"00280030":{"vr":"DS","Value":[0.5,null,"+1"]}
+	}`), &ds))
+	assert.Equal(t, "0.12345678901235", stringOf(t, &ds, tag.SliceThickness))
+	assert.Equal(t, "1000", stringOf(t, &ds, tag.InstanceNumber))
+	spacing, err := ds.Get(tag.PixelSpacing)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0.5", "", "+1"}, spacing.Value().(*value.StringValue).Strings())
+}
+
+func TestDataSet_UnmarshalJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not an object", `[]`},
+		{"invalid tag key", `{"0010":{"vr":"PN"}}`},
+		{"missing vr", `{"00100010":{"Value":["Doe"]}}`},
+		{"unknown vr", `{"00100010":{"vr":"XX"}}`},
+		{"IS not an integer", `{"00200013":{"vr":"IS","Value":[4.5]}}`},
+		{"US as object", `{"00280010":{"vr":"US","Value":[{}]}}`},
+		{"US null", `{"00280010":{"vr":"US","Value":[null]}}`},
+		{"US out of range", `{"00280010":{"vr":"US","Value":[70000]}}`},
+		{"invalid AT", `{"00209165":{"vr":"AT","Value":["0018"]}}`},
+		{"bulk data", `{"7FE00010":{"vr":"OB","BulkDataURI":"https://example.com/frames/1"}}`},
+		{"invalid sequence item", `{"00081140":{"vr":"SQ","Value":[{"00081160":{"vr":"IS","Value":[true]}}]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal([]byte(tt.data), dicom.NewDataSet()))
+		})
+	}
+
+	err := json.Unmarshal([]byte(`{"00100010":{"vr":"XX"}}`), dicom.NewDataSet())
+	assert.ErrorIs(t, err, dicom.ErrInvalidVR)
+}
@@ -0,0 +1,167 @@
+package dicom
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// Manifest is the JSON index of a study produced by ExportManifest.
+//
+// It describes the patient, the study and every series and instance with the
+// identifiers a downstream system needs to locate and upload the files. Pixel data
+// and other bulk data are never included.
+type Manifest struct {
+	Patient ManifestPatient  `json:"patient"`
+	Study   ManifestStudy    `json:"study"`
+	Series  []ManifestSeries `json:"series"`
+}
+
+// ManifestPatient holds the patient demographics of a Manifest.
+type ManifestPatient struct {
+	PatientName      string `json:"patientName,omitempty"`      // (0010,0010)
+	PatientID        string `json:"patientID,omitempty"`        // (0010,0020)
+	PatientBirthDate string `json:"patientBirthDate,omitempty"` // (0010,0030)
+	PatientSex       string `json:"patientSex,omitempty"`       // (0010,0040)
+}
+
+// ManifestStudy holds the study-level attributes of a Manifest.
+type ManifestStudy struct {
+	StudyInstanceUID       string `json:"studyInstanceUID"`                 // (0020,000D)
+	StudyID                string `json:"studyID,omitempty"`                // (0020,0010)
+	AccessionNumber        string `json:"accessionNumber,omitempty"`        // (0008,0050)
+	StudyDate              string `json:"studyDate,omitempty"`              // (0008,0020)
+	StudyTime              string `json:"studyTime,omitempty"`              // (0008,0030)
+	StudyDescription       string `json:"studyDescription,omitempty"`       // (0008,1030)
+	ReferringPhysicianName string `json:"referringPhysicianName,omitempty"` // (0008,0090)
+	NumberOfSeries         int    `json:"numberOfSeries"`
+	NumberOfInstances      int    `json:"numberOfInstances"`
+}
+
+// ManifestSeries describes one series of a Manifest.
+type ManifestSeries struct {
+	SeriesInstanceUID string             `json:"seriesInstanceUID"`           // (0020,000E)
+	SeriesNumber      *int               `json:"seriesNumber,omitempty"`      // (0020,0011)
+	Modality          string             `json:"modality,omitempty"`          // (0008,0060)
+	SeriesDescription string             `json:"seriesDescription,omitempty"` // (0008,103E)
+	Instances         []ManifestInstance `json:"instances"`
+}
+
+// ManifestInstance describes one instance of a Manifest.
+type ManifestInstance struct {
+	SOPInstanceUID    string `json:"sopInstanceUID"`              // (0008,0018)
+	SOPClassUID       string `json:"sopClassUID"`                 // (0008,0016)
+	InstanceNumber    *int   `json:"instanceNumber,omitempty"`    // (0020,0013)
+	TransferSyntaxUID string `json:"transferSyntaxUID,omitempty"` // (0002,0010)
+	FilePath          string `json:"filePath,omitempty"`
+}
+
+// ExportManifest produces a JSON manifest of a study for handing it to another system.
+//
+// The manifest lists the patient demographics, the study-level attributes and, for
+// each series, the instances with their SOP Instance UID, SOP Class UID, transfer
+// syntax and source file path when known (see DataSetCollection.AddWithFilePath).
+// Series are ordered by Series Number and instances by Instance Number, falling back
+// to UID order, so the output is deterministic. Pixel data is never included.
+//
+// Patient and study attributes are taken from the instance with the lowest SOP
+// Instance UID, so the result does not depend on the order instances were added.
+//
+// Returns an error if coll is nil or contains no instances of studyUID.
+//
+// Example:
+//
+//	result, err := dicom.ParseDirectory("/data/incoming")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	manifest, err := dicom.ExportManifest(result.Collection, studyUID)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("manifest.json", manifest, 0o644)
+func ExportManifest(coll *DataSetCollection, studyUID string) ([]byte, error) {
+	if coll == nil {
+		return nil, fmt.Errorf("cannot export manifest from nil collection")
+	}
+
+	datasets := coll.GetByStudyInstanceUID(studyUID)
+	if len(datasets) == 0 {
+		return nil, fmt.Errorf("study %s not found in collection", studyUID)
+	}
+
+	// Group instances by series
+	seriesByUID := make(map[string]*ManifestSeries)
+	var seriesList []*ManifestSeries
+	for _, ds := range datasets {
+		seriesUID := stringValue(ds, tag.SeriesInstanceUID)
+		series, ok := seriesByUID[seriesUID]
+		if !ok {
+			series = &ManifestSeries{
+				SeriesInstanceUID: seriesUID,
+				SeriesNumber:      intValue(ds, tag.SeriesNumber),
+				Modality:          stringValue(ds, tag.Modality),
+				SeriesDescription: stringValue(ds, tag.SeriesDescription),
+			}
+			seriesByUID[seriesUID] = series
+			seriesList = append(seriesList, series)
+		}
+
+		sopInstanceUID := stringValue(ds, tag.SOPInstanceUID)
+		series.Instances = append(series.Instances, ManifestInstance{
+			SOPInstanceUID:    sopInstanceUID,
+			SOPClassUID:       stringValue(ds, tag.SOPClassUID),
+			InstanceNumber:    intValue(ds, tag.InstanceNumber),
+			TransferSyntaxUID: stringValue(ds, tag.TransferSyntaxUID),
+			FilePath:          coll.FilePath(sopInstanceUID),
+		})
+	}
+
+	sort.Slice(seriesList, func(i, j int) bool {
+		return lessByNumberThenUID(seriesList[i].SeriesNumber, seriesList[j].SeriesNumber,
+			seriesList[i].SeriesInstanceUID, seriesList[j].SeriesInstanceUID)
+	})
+
+	m := Manifest{Series: make([]ManifestSeries, 0, len(seriesList))}
+	for _, series := range seriesList {
+		instances := series.Instances
+		sort.Slice(instances, func(i, j int) bool {
+			return lessByNumberThenUID(instances[i].InstanceNumber, instances[j].InstanceNumber,
+				instances[i].SOPInstanceUID, instances[j].SOPInstanceUID)
+		})
+		m.Series = append(m.Series, *series)
+	}
+
+	// Demographics and study attributes from the instance with the lowest UID
+	first := datasets[0]
+	for _, ds := range datasets[1:] {
+		if stringValue(ds, tag.SOPInstanceUID) < stringValue(first, tag.SOPInstanceUID) {
+			first = ds
+		}
+	}
+	m.Patient = ManifestPatient{
+		PatientName:      stringValue(first, tag.PatientName),
+		PatientID:        stringValue(first, tag.PatientID),
+		PatientBirthDate: stringValue(first, tag.PatientBirthDate),
+		PatientSex:       stringValue(first, tag.PatientSex),
+	}
+	m.Study = ManifestStudy{
+		StudyInstanceUID:       studyUID,
+		StudyID:                stringValue(first, tag.StudyID),
+		AccessionNumber:        stringValue(first, tag.AccessionNumber),
+		StudyDate:              stringValue(first, tag.StudyDate),
+		StudyTime:              stringValue(first, tag.StudyTime),
+		StudyDescription:       stringValue(first, tag.StudyDescription),
+		ReferringPhysicianName: stringValue(first, tag.ReferringPhysicianName),
+		NumberOfSeries:         len(m.Series),
+		NumberOfInstances:      len(datasets),
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	return data, nil
+}
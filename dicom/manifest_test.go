@@ -0,0 +1,89 @@
+package dicom_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportManifest(t *testing.T) {
+	const (
+		studyUID    = "1.2.826.0.1.3680043.10.1451.4"
+		ctSeries    = "1.2.826.0.1.3680043.10.1451.4.1"
+		scoutSeries = "1.2.826.0.1.3680043.10.1451.4.2"
+		ctImage     = "1.2.840.10008.5.1.4.1.1.2"
+	)
+
+	newInstance := func(sopUID, seriesUID string, seriesNumber int, instanceNumber string) *dicom.DataSet {
+		ds := createTestDataSetForCollection(sopUID, seriesUID, studyUID, "PAT001", "ACC42", ctImage, seriesNumber)
+		require.NoError(t, ds.Add(mustNewElement(tag.InstanceNumber, vr.IntegerString,
+			mustNewStringValue(vr.IntegerString, []string{instanceNumber}))))
+		require.NoError(t, ds.Add(mustNewElement(tag.Modality, vr.CodeString,
+			mustNewStringValue(vr.CodeString, []string{"CT"}))))
+		require.NoError(t, ds.Add(mustNewElement(tag.StudyDescription, vr.LongString,
+			mustNewStringValue(vr.LongString, []string{"CT CHEST"}))))
+		require.NoError(t, ds.Add(mustNewElement(tag.TransferSyntaxUID, vr.UniqueIdentifier,
+			mustNewStringValue(vr.UniqueIdentifier, []string{"1.2.840.10008.1.2.1"}))))
+		pixels, err := value.NewBytesValue(vr.OtherWord, make([]byte, 32))
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(mustNewElement(tag.PixelData, vr.OtherWord, pixels)))
+		return ds
+	}
+
+	coll := dicom.NewDataSetCollection()
+	require.NoError(t, coll.AddWithFilePath(newInstance(ctSeries+".2", ctSeries, 2, "2"), "/data/ct/2.dcm"))
+	require.NoError(t, coll.AddWithFilePath(newInstance(ctSeries+".10", ctSeries, 2, "1"), "/data/ct/1.dcm"))
+	require.NoError(t, coll.Add(newInstance(scoutSeries+".1", scoutSeries, 1, "1")))
+	// Another study is left out
+	require.NoError(t, coll.Add(createTestDataSetForCollection(
+		"1.2.3.9.1", "1.2.3.9", "1.2.3", "PAT001", "ACC43", ctImage, 1)))
+
+	data, err := dicom.ExportManifest(coll, studyUID)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "pixel", "pixel data must not be exported")
+
+	var m dicom.Manifest
+	require.NoError(t, json.Unmarshal(data, &m))
+
+	assert.Equal(t, "Test^Patient", m.Patient.PatientName)
+	assert.Equal(t, "PAT001", m.Patient.PatientID)
+
+	assert.Equal(t, studyUID, m.Study.StudyInstanceUID)
+	assert.Equal(t, "ACC42", m.Study.AccessionNumber)
+	assert.Equal(t, "CT CHEST", m.Study.StudyDescription)
+	assert.Equal(t, 2, m.Study.NumberOfSeries)
+	assert.Equal(t, 3, m.Study.NumberOfInstances)
+
+	// Series in Series Number order, instances in Instance Number order
+	require.Len(t, m.Series, 2)
+	assert.Equal(t, scoutSeries, m.Series[0].SeriesInstanceUID)
+	require.NotNil(t, m.Series[0].SeriesNumber)
+	assert.Equal(t, 1, *m.Series[0].SeriesNumber)
+	assert.Equal(t, "CT", m.Series[0].Modality)
+
+	ct := m.Series[1]
+	require.Len(t, ct.Instances, 2)
+	assert.Equal(t, ctSeries+".10", ct.Instances[0].SOPInstanceUID)
+	assert.Equal(t, "/data/ct/1.dcm", ct.Instances[0].FilePath)
+	assert.Equal(t, ctSeries+".2", ct.Instances[1].SOPInstanceUID)
+	assert.Equal(t, "/data/ct/2.dcm", ct.Instances[1].FilePath)
+	assert.Equal(t, ctImage, ct.Instances[0].SOPClassUID)
+	assert.Equal(t, "1.2.840.10008.1.2.1", ct.Instances[0].TransferSyntaxUID)
+
+	// Unknown file paths are omitted
+	assert.Empty(t, m.Series[0].Instances[0].FilePath)
+}
+
+func TestExportManifest_Errors(t *testing.T) {
+	_, err := dicom.ExportManifest(nil, "1.2.3")
+	assert.Error(t, err)
+
+	_, err = dicom.ExportManifest(dicom.NewDataSetCollection(), "1.2.3")
+	assert.Error(t, err)
+}
@@ -3,15 +3,18 @@ package dicom
 
 import (
 	"compress/flate"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/codeninja55/go-radx/dicom/element"
 	"github.com/codeninja55/go-radx/dicom/tag"
 	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
 )
 
 // Parser handles parsing of DICOM files.
@@ -29,6 +32,146 @@ type Parser struct {
 	rawReader    io.Reader // Original io.Reader for decompression wrapping
 	ts           *TransferSyntax
 	bufferedElem *element.Element // Element read ahead during File Meta parsing
+	opts         ParseOptions
+}
+
+// ParseOptions configures DICOM parsing behavior.
+type ParseOptions struct {
+	// Tolerant downgrades recoverable structural problems (such as a File Meta
+	// Information Group Length that does not match the encoded group) from errors
+	// to warnings reported through WarningCallback. Encapsulated pixel data cut off
+	// before its Sequence Delimitation Item (ErrTruncatedPixelData) is kept with the
+	// fragments read so far, so partial pixel data can still be recovered.
+	// Default: false (strict)
+	Tolerant bool
+
+	// WarningCallback is called for each recoverable problem found in tolerant mode.
+	// Optional: if nil, warnings are silently ignored.
+	WarningCallback func(err error)
+
+	// TrackOffsets records each parsed element's byte offset and on-disk length,
+	// available afterwards via element.Location. Offsets are absolute positions in
+	// the input stream (see element.Location for the exact semantics). Offsets are
+	// not recorded for the dataset of deflated transfer syntaxes, since positions in
+	// the inflated stream do not correspond to file positions.
+	// Default: false
+	TrackOffsets bool
+
+	// RetainRawValues keeps the encoded bytes of each parsed element's value field,
+	// available afterwards via element.RawBytes, for Digital Signature verification
+	// or faithful re-emission of values whose interpretation is unknown. For deflated
+	// transfer syntaxes the bytes are those of the inflated dataset. This roughly
+	// doubles the memory held by the parsed dataset.
+	// Default: false
+	RetainRawValues bool
+
+	// MaxElementLength is the largest value length, in bytes, the parser will accept
+	// for a single element or encapsulated pixel data fragment. A larger declared
+	// length, or one exceeding the bytes remaining in a stream of known size, fails
+	// with ErrElementTooLarge instead of attempting the allocation. This protects
+	// services parsing untrusted uploads from corrupt or malicious length fields.
+	// Default: DefaultMaxElementLength (set math.MaxUint32 to disable the limit)
+	MaxElementLength uint32
+
+	// OnDuplicateTag decides what happens when a tag occurs more than once in the
+	// same dataset or sequence item, as some legacy modalities emit for private tags.
+	// Every duplicate is reported through WarningCallback unless the policy is
+	// DuplicateTagError, which fails the parse with ErrDuplicateTag instead.
+	// Default: DuplicateTagKeepLast
+	OnDuplicateTag DuplicateTagPolicy
+
+	// PreserveUN keeps Implicit VR elements whose VR cannot be determined from the
+	// data dictionary as UN (Unknown) rather than guessing, so their bytes are
+	// carried through re-encoding untouched. This applies to attributes the
+	// dictionary lists with several VRs (such as "US or SS" or "OB or OW") that the
+	// parser cannot resolve from the dataset itself; private and unrecognized tags
+	// are always read as UN. Pixel Data and the pixel value attributes resolved from
+	// Bits Allocated and Pixel Representation are unaffected.
+	// Default: false (use the first VR listed in the dictionary)
+	PreserveUN bool
+
+	// AllowTrailingData ends the dataset, rather than failing the parse, when the
+	// bytes after the last complete top-level element do not form a valid element
+	// header or end before the element they start, as with padding or junk appended
+	// to a file. The ignored bytes are reported through WarningCallback as
+	// ErrTrailingData. A stream that ends exactly at an element boundary is always a
+	// clean end of the dataset, and invalid values or lengths in a well-formed
+	// element header still fail the parse.
+	//
+	// Bytes only count as trailing data when they follow the dataset: they come
+	// after Pixel Data (7FE0,0010), or, after at least one element of a dataset
+	// without Pixel Data, they do not hold a complete tag, or their tag is not
+	// above the tag of the element before them, as the ascending order of a
+	// dataset requires, or is not in the data dictionary. A bad header or
+	// truncated element of a dictionary attribute in the middle of a dataset,
+	// including a truncated Pixel Data element, always fails the parse.
+	// If nil, defaults to true. Set to false to fail with the read error instead.
+	AllowTrailingData *bool
+
+	// VROverrides forces the VR of the listed tags in the main dataset and its
+	// sequence items, so their values are decoded as the given VR rather than the
+	// VR encoded in the file (Explicit VR) or listed in the data dictionary
+	// (Implicit VR). The parsed element carries the override VR, and is written
+	// with it if the dataset is saved again.
+	//
+	// This is a compatibility workaround for devices known to encode specific
+	// attributes with the wrong VR, such as a DS value marked as UN; conformant
+	// files never need it. In Explicit VR the header is still read as the file's
+	// VR describes it, so only the value is reinterpreted. The File Meta
+	// Information is not affected, and SQ cannot be used as an override.
+	// Default: nil (no overrides)
+	VROverrides map[tag.Tag]vr.VR
+
+	// Context allows cancellation of the parsing operation.
+	// The context is checked before each top-level element is read.
+	// If nil, a background context will be used.
+	Context context.Context
+}
+
+// DuplicateTagPolicy selects how the parser handles a tag that appears more than
+// once within a single dataset or sequence item.
+type DuplicateTagPolicy int
+
+const (
+	// DuplicateTagKeepLast replaces the earlier element with the later one.
+	DuplicateTagKeepLast DuplicateTagPolicy = iota
+	// DuplicateTagKeepFirst keeps the earlier element and discards the later one.
+	DuplicateTagKeepFirst
+	// DuplicateTagError fails the parse with ErrDuplicateTag.
+	DuplicateTagError
+)
+
+// DefaultMaxElementLength is the default ParseOptions.MaxElementLength (1 GiB),
+// comfortably above the pixel data of any realistic single-file image.
+const DefaultMaxElementLength uint32 = 1 << 30
+
+// applyDefaultParseOptions fills in missing options with sensible defaults.
+func applyDefaultParseOptions(opts ParseOptions) ParseOptions {
+	if opts.Context == nil {
+		opts.Context = context.Background()
+	}
+	if opts.MaxElementLength == 0 {
+		opts.MaxElementLength = DefaultMaxElementLength
+	}
+	if opts.AllowTrailingData == nil {
+		allow := true
+		opts.AllowTrailingData = &allow
+	}
+	return opts
+}
+
+// validateVROverrides rejects ParseOptions.VROverrides entries that name an unknown
+// VR or SQ, whose items cannot be recovered from a value of another VR.
+func validateVROverrides(overrides map[tag.Tag]vr.VR) error {
+	for t, v := range overrides {
+		if parsed, err := vr.Parse(v.String()); err != nil || parsed != v {
+			return fmt.Errorf("%w: VR override for tag %s", ErrInvalidVR, t)
+		}
+		if v == vr.SequenceOfItems {
+			return fmt.Errorf("%w: VR override for tag %s cannot be SQ", ErrInvalidVR, t)
+		}
+	}
+	return nil
 }
 
 // ParseFile reads and parses a DICOM file from the filesystem.
@@ -63,6 +206,119 @@ func ParseFile(path string) (*DataSet, error) {
 	return ParseReader(file)
 }
 
+// ParseFileContext reads and parses a DICOM file, aborting if ctx is cancelled.
+//
+// The context is checked between elements, so a cancelled or expired context stops
+// a long-running parse promptly. On cancellation ctx.Err() is returned.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+//	defer cancel()
+//	ds, err := dicom.ParseFileContext(ctx, "large_multiframe.dcm")
+//	if errors.Is(err, context.DeadlineExceeded) {
+//	    log.Println("parse timed out")
+//	}
+func ParseFileContext(ctx context.Context, path string) (*DataSet, error) {
+	return ParseFileWithOptions(path, ParseOptions{Context: ctx})
+}
+
+// ParseReaderContext reads and parses a DICOM file from an io.Reader, aborting if
+// ctx is cancelled. See ParseFileContext.
+func ParseReaderContext(ctx context.Context, r io.Reader) (*DataSet, error) {
+	return ParseReaderWithOptions(r, ParseOptions{Context: ctx})
+}
+
+// ParseFileWithOptions reads and parses a DICOM file with configurable options.
+//
+// Example:
+//
+//	ds, err := dicom.ParseFileWithOptions("image.dcm", dicom.ParseOptions{
+//	    Tolerant: true,
+//	    WarningCallback: func(err error) {
+//	        log.Printf("warning: %v", err)
+//	    },
+//	})
+func ParseFileWithOptions(path string, opts ParseOptions) (*DataSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	//nolint:errcheck // File close in defer for read-only operation
+	defer func() { _ = file.Close() }()
+
+	return ParseReaderWithOptions(file, opts)
+}
+
+// progressInterval is the minimum time between two ParseFileWithProgress callbacks.
+const progressInterval = 100 * time.Millisecond
+
+// ParseFileWithProgress reads and parses a DICOM file, reporting how many of the
+// file's bytes have been consumed as elements are read.
+//
+// progress receives the bytes read so far and the file size. It is called at most
+// once per 100ms while parsing, to keep the overhead negligible on files with many
+// small elements, and a final time once the whole file has been parsed. This lets
+// applications show a progress bar while loading a single large instance, such as
+// a multi-gigabyte whole-slide image. A nil progress behaves like ParseFile.
+//
+// Example:
+//
+//	ds, err := dicom.ParseFileWithProgress("slide.dcm", func(read, total int64) {
+//	    fmt.Printf("\rloading %3d%%", read*100/total)
+//	})
+func ParseFileWithProgress(path string, progress func(bytesRead, totalBytes int64)) (*DataSet, error) {
+	if progress == nil {
+		return ParseFile(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	//nolint:errcheck // File close in defer for read-only operation
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	pr := &progressReader{r: file, total: info.Size(), progress: progress, last: time.Now()}
+	ds, err := ParseReader(pr)
+	if err != nil {
+		return nil, err
+	}
+	progress(pr.read, pr.total)
+	return ds, nil
+}
+
+// progressReader counts the bytes read from r and reports them to progress at most
+// once per progressInterval.
+type progressReader struct {
+	r        io.Reader
+	read     int64
+	total    int64
+	progress func(bytesRead, totalBytes int64)
+	last     time.Time
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	if now := time.Now(); now.Sub(p.last) >= progressInterval {
+		p.last = now
+		p.progress(p.read, p.total)
+	}
+	return n, err
+}
+
+// Len returns the number of unread bytes, which lets the parser bound declared
+// element lengths as it does for the file itself (see streamSize).
+func (p *progressReader) Len() int {
+	return int(p.total - p.read)
+}
+
 // ParseReader reads and parses a DICOM file from an io.Reader.
 //
 // This allows parsing DICOM data from any source (files, network, memory, etc.).
@@ -79,13 +335,33 @@ func ParseFile(path string) (*DataSet, error) {
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part10.html#sect_7
 func ParseReader(r io.Reader) (*DataSet, error) {
+	return ParseReaderWithOptions(r, ParseOptions{})
+}
+
+// ParseReaderWithOptions reads and parses a DICOM file from an io.Reader with
+// configurable options.
+//
+// Example:
+//
+//	ds, err := dicom.ParseReaderWithOptions(file, dicom.ParseOptions{Tolerant: true})
+func ParseReaderWithOptions(r io.Reader, opts ParseOptions) (*DataSet, error) {
+	opts = applyDefaultParseOptions(opts)
+	if err := opts.Context.Err(); err != nil {
+		return nil, err
+	}
+	if err := validateVROverrides(opts.VROverrides); err != nil {
+		return nil, err
+	}
+
 	// Create binary reader (File Meta is always Little Endian)
 	reader := NewReader(r, binary.LittleEndian)
+	reader.size = streamSize(r)
 
 	// Create parser, keeping reference to original reader for potential decompression
 	parser := &Parser{
 		reader:    reader,
 		rawReader: r,
+		opts:      opts,
 	}
 
 	// Step 1: Read and validate preamble + "DICM" prefix
@@ -132,13 +408,16 @@ func ParseReader(r io.Reader) (*DataSet, error) {
 	// Step 4: Read main dataset
 	mainDS, err := parser.readDataset()
 	if err != nil {
+		if ctxErr := opts.Context.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		return nil, fmt.Errorf("failed to read dataset: %w", err)
 	}
 
 	// Merge File Meta and main dataset
 	// Add all File Meta elements first
 	for _, elem := range metaInfo.Elements() {
-		_ = mainDS.Add(elem) //nolint:errcheck // Element from parsed dataset, guaranteed non-nil
+		_ = mainDS.Set(elem) //nolint:errcheck // Element from parsed dataset, guaranteed non-nil
 	}
 
 	return mainDS, nil
@@ -207,6 +486,11 @@ func (p *Parser) readFileMetaInformation() (*DataSet, error) {
 
 	// Create element parser for File Meta
 	elemParser := NewElementParser(p.reader, fileMetaTS)
+	elemParser.trackOffsets = p.opts.TrackOffsets
+	elemParser.retainRaw = p.opts.RetainRawValues
+	elemParser.maxElementLength = p.opts.MaxElementLength
+	elemParser.onDuplicateTag = p.opts.OnDuplicateTag
+	elemParser.warn = p.opts.WarningCallback
 
 	// Create dataset to store File Meta elements
 	ds := NewDataSet()
@@ -220,7 +504,7 @@ func (p *Parser) readFileMetaInformation() (*DataSet, error) {
 		return nil, fmt.Errorf("failed to read first File Meta element: %w", err)
 	}
 
-	_ = ds.Add(firstElem) //nolint:errcheck // Element just parsed, guaranteed non-nil
+	_ = ds.Set(firstElem) //nolint:errcheck // Element just parsed, guaranteed non-nil
 
 	// Check if this is the Group Length element
 	groupLengthTag := tag.New(0x0002, 0x0000)
@@ -247,6 +531,7 @@ func (p *Parser) readFileMetaInformation() (*DataSet, error) {
 		startPos := p.reader.Position()
 
 		for bytesRead < fileMetaLength {
+			elemStart := p.reader.Position()
 			elem, err := elemParser.ReadElement()
 			if err != nil {
 				if err == io.EOF {
@@ -256,12 +541,29 @@ func (p *Parser) readFileMetaInformation() (*DataSet, error) {
 				return nil, fmt.Errorf("failed to read File Meta element: %w", err)
 			}
 
-			_ = ds.Add(elem) //nolint:errcheck // Element just parsed, guaranteed non-nil
+			// Group length overstated: we've run into the main dataset
+			if elem.Tag().Group != 0x0002 {
+				p.bufferedElem = elem
+				bytesRead = uint32(elemStart - startPos)
+				break
+			}
+
+			if err := elemParser.addElement(ds, elem); err != nil {
+				return nil, err
+			}
 
 			// Update bytes read
 			currentPos := p.reader.Position()
 			bytesRead = uint32(currentPos - startPos)
 		}
+
+		if bytesRead != fileMetaLength {
+			mismatch := fmt.Errorf("%w: (0002,0000) is %d but group elements occupy %d bytes",
+				ErrGroupLengthMismatch, fileMetaLength, bytesRead)
+			if err := p.warnOrFail(mismatch); err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		// Fallback: read until we hit a tag outside Group 0x0002
 		for {
@@ -283,13 +585,65 @@ func (p *Parser) readFileMetaInformation() (*DataSet, error) {
 			}
 
 			// Add element to dataset
-			_ = ds.Add(elem) //nolint:errcheck // Element just parsed, guaranteed non-nil
+			if err := elemParser.addElement(ds, elem); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return ds, nil
 }
 
+// ignoreTrailingData reports whether err, from reading the top-level element at
+// offset start, should end the dataset as trailing data, warning about it if so.
+// Only bytes that do not form an element header (an invalid tag or VR) or that end
+// before the element does count as trailing data. A well-formed element with an
+// invalid value, a duplicate tag, or a declared length beyond the limit or the
+// stream (ErrElementTooLarge) is a problem with the dataset itself and still fails.
+//
+// afterDataset reports whether the bytes follow the dataset rather than sit inside
+// it (see ParseOptions.AllowTrailingData); bytes inside the dataset always fail.
+func (p *Parser) ignoreTrailingData(err error, start int64, afterDataset bool) bool {
+	if p.opts.AllowTrailingData != nil && !*p.opts.AllowTrailingData {
+		return false
+	}
+	if !afterDataset {
+		return false
+	}
+	if !errors.Is(err, ErrInvalidVR) && !errors.Is(err, ErrInvalidTag) &&
+		!errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return false
+	}
+	if p.opts.WarningCallback != nil {
+		if remaining, ok := p.reader.Remaining(); ok {
+			size := remaining + p.reader.Position()
+			err = fmt.Errorf("%w: %d bytes at offset %d ignored: %w", ErrTrailingData, size-start, start, err)
+		} else {
+			err = fmt.Errorf("%w: bytes from offset %d ignored: %w", ErrTrailingData, start, err)
+		}
+		p.opts.WarningCallback(err)
+	}
+	return true
+}
+
+// isDictionaryTag reports whether t is a public attribute of the data dictionary.
+func isDictionaryTag(t tag.Tag) bool {
+	_, err := tag.Find(t)
+	return err == nil
+}
+
+// warnOrFail reports a recoverable problem. In tolerant mode the problem is
+// passed to the WarningCallback and parsing continues; otherwise it is returned.
+func (p *Parser) warnOrFail(err error) error {
+	if !p.opts.Tolerant {
+		return err
+	}
+	if p.opts.WarningCallback != nil {
+		p.opts.WarningCallback(err)
+	}
+	return nil
+}
+
 // detectTransferSyntax extracts the Transfer Syntax UID from File Meta Information
 // and returns the corresponding TransferSyntax configuration.
 //
@@ -312,146 +666,7 @@ func (p *Parser) detectTransferSyntax(metaInfo *DataSet) (*TransferSyntax, error
 		return nil, fmt.Errorf("%w: Transfer Syntax UID is empty", ErrMissingTransferSyntax)
 	}
 
-	// Map UID to TransferSyntax properties
-	// For now, support the most common transfer syntaxes
-	switch tsUID {
-	case "1.2.840.10008.1.2":
-		// Implicit VR Little Endian
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: false,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: false,
-			Deflated:   false,
-		}, nil
-
-	case "1.2.840.10008.1.2.1":
-		// Explicit VR Little Endian (default)
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: false,
-			Deflated:   false,
-		}, nil
-
-	case "1.2.840.10008.1.2.2":
-		// Explicit VR Big Endian (RETIRED)
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.BigEndian,
-			Compressed: false,
-			Deflated:   false,
-		}, nil
-
-	case "1.2.840.10008.1.2.1.99":
-		// Deflated Explicit VR Little Endian
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: false,
-			Deflated:   true,
-		}, nil
-
-	// Compressed transfer syntaxes
-	// Pixel data remains as raw bytes until explicitly decompressed via pixel.Extract()
-
-	case "1.2.840.10008.1.2.5":
-		// RLE Lossless
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: true,
-			Deflated:   false,
-		}, nil
-
-	case "1.2.840.10008.1.2.4.50":
-		// JPEG Baseline (Process 1)
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: true,
-			Deflated:   false,
-		}, nil
-
-	case "1.2.840.10008.1.2.4.51":
-		// JPEG Baseline (Processes 2 & 4)
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: true,
-			Deflated:   false,
-		}, nil
-
-	case "1.2.840.10008.1.2.4.57":
-		// JPEG Lossless, Non-Hierarchical, First-Order Prediction
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: true,
-			Deflated:   false,
-		}, nil
-
-	case "1.2.840.10008.1.2.4.70":
-		// JPEG Lossless, Non-Hierarchical (Process 14)
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: true,
-			Deflated:   false,
-		}, nil
-
-	case "1.2.840.10008.1.2.4.90":
-		// JPEG 2000 Image Compression (Lossless Only)
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: true,
-			Deflated:   false,
-		}, nil
-
-	case "1.2.840.10008.1.2.4.91":
-		// JPEG 2000 Image Compression
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: true,
-			Deflated:   false,
-		}, nil
-
-	case "1.2.840.10008.1.2.4.201":
-		// High-Throughput JPEG 2000 (HTJ2K) Lossless Only
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: true,
-			Deflated:   false,
-		}, nil
-
-	case "1.2.840.10008.1.2.4.203":
-		// High-Throughput JPEG 2000 (HTJ2K) Lossless or Lossy
-		return &TransferSyntax{
-			UID:        tsUID,
-			ExplicitVR: true,
-			ByteOrder:  binary.LittleEndian,
-			Compressed: true,
-			Deflated:   false,
-		}, nil
-
-	default:
-		// Unknown transfer syntax - return error
-		return nil, fmt.Errorf("%w: Transfer Syntax UID %q not supported", ErrInvalidTransferSyntax, tsUID)
-	}
+	return lookupTransferSyntax(tsUID)
 }
 
 // readDataset reads the main dataset elements using the detected transfer syntax.
@@ -464,47 +679,73 @@ func (p *Parser) detectTransferSyntax(metaInfo *DataSet) (*TransferSyntax, error
 func (p *Parser) readDataset() (*DataSet, error) {
 	// Create element parser with detected transfer syntax
 	elemParser := NewElementParser(p.reader, p.ts)
+	elemParser.trackOffsets = p.opts.TrackOffsets && !p.ts.Deflated
+	elemParser.retainRaw = p.opts.RetainRawValues
+	elemParser.maxElementLength = p.opts.MaxElementLength
+	elemParser.onDuplicateTag = p.opts.OnDuplicateTag
+	elemParser.warn = p.opts.WarningCallback
+	elemParser.preserveUN = p.opts.PreserveUN
+	elemParser.vrOverrides = p.opts.VROverrides
 
 	// Create dataset to store elements
 	ds := NewDataSet()
 
 	// If we have a buffered element from File Meta parsing, add it first
+	var prevTag tag.Tag
 	if p.bufferedElem != nil {
-		_ = ds.Add(p.bufferedElem) //nolint:errcheck // Element from File Meta parsing, guaranteed non-nil
+		_ = ds.Set(p.bufferedElem) //nolint:errcheck // Element from File Meta parsing, guaranteed non-nil
+		prevTag = p.bufferedElem.Tag()
 		p.bufferedElem = nil
 	}
 
 	// Read elements until EOF
 	for {
+		// Check for cancellation between elements
+		if err := p.opts.Context.Err(); err != nil {
+			return nil, err
+		}
+
+		elemStart := p.reader.Position()
 		elem, err := elemParser.ReadElement()
 		if err != nil {
-			if err == io.EOF {
-				// Normal end of file
+			if errors.Is(err, ErrTruncatedPixelData) {
+				// The stream ended inside encapsulated pixel data. In tolerant mode keep
+				// the complete fragments as the last element of the dataset.
+				if err := p.warnOrFail(err); err != nil {
+					return nil, fmt.Errorf("failed to read dataset element: %w", err)
+				}
+				if elem != nil {
+					if err := elemParser.addElement(ds, elem); err != nil {
+						return nil, err
+					}
+				}
+				break
+			}
+			if err == io.EOF || errors.Is(err, io.EOF) && p.reader.Position() == elemStart {
+				// Normal end of file, at an element boundary
 				break
 			}
-			// Check if this is an EOF wrapped in other errors (e.g., from sequence parsing)
-			// In that case, treat it as end of dataset rather than failure
-			if errors.Is(err, io.EOF) {
-				// EOF encountered during parsing (e.g., in sequence skipping)
-				// This might indicate a truncated file, but we can return what we've parsed so far
+			// Junk follows the dataset once Pixel Data has been read. Without Pixel
+			// Data, as in SR or presentation states, it follows a dataset of at least
+			// one element when it has no complete tag, or a tag that breaks the
+			// ascending order or is not in the data dictionary.
+			afterDataset := ds.Contains(tag.PixelData) ||
+				ds.Len() > 0 && (!elemParser.hasHeaderTag ||
+					elemParser.headerTag.Compare(prevTag) <= 0 ||
+					!isDictionaryTag(elemParser.headerTag))
+			if p.ignoreTrailingData(err, elemStart, afterDataset) {
+				// Junk after the dataset, or a truncated element appended to it
 				break
 			}
 			return nil, fmt.Errorf("failed to read dataset element: %w", err)
 		}
 
 		// Add element to dataset
-		_ = ds.Add(elem) //nolint:errcheck // Element just parsed, guaranteed non-nil
+		if err := elemParser.addElement(ds, elem); err != nil {
+			return nil, err
+		}
+		prevTag = elem.Tag()
 	}
 
 	return ds, nil
 }
-
-// TransferSyntax describes the encoding of a DICOM dataset.
-// TODO: Move to transfer_syntax.go once implemented
-type TransferSyntax struct {
-	UID        string           // Transfer Syntax UID
-	ExplicitVR bool             // true = Explicit VR, false = Implicit VR
-	ByteOrder  binary.ByteOrder // Little or Big Endian
-	Compressed bool             // true if pixel data is compressed
-	Deflated   bool             // true for deflated transfer syntax
-}
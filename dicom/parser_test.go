@@ -3,11 +3,19 @@ package dicom
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -230,3 +238,576 @@ func TestParser_Integration(t *testing.T) {
 
 	// Further integration testing will be added as we implement more functionality
 }
+
+// encodeTestFileWithGroupLength writes a minimal Part 10 stream to memory and
+// overwrites its (0002,0000) value with groupLength.
+func encodeTestFileWithGroupLength(t *testing.T, groupLength uint32) []byte {
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeDICOMFile(buf, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+
+	data := buf.Bytes()
+	// Preamble (128) + "DICM" (4) + tag (4) + VR (2) + length (2)
+	binary.LittleEndian.PutUint32(data[140:144], groupLength)
+	return data
+}
+
+// TestParseReader_GroupLengthValid tests that a correct group length parses in strict mode.
+func TestParseReader_GroupLengthValid(t *testing.T) {
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeDICOMFile(buf, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+
+	ds, err := ParseReader(buf)
+	require.NoError(t, err)
+
+	meta, err := FileMetaInfo(ds)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.840.10008.1.2.1", meta.TransferSyntaxUID)
+}
+
+// TestParseReader_GroupLengthMismatch tests strict and tolerant handling of a bad group length.
+func TestParseReader_GroupLengthMismatch(t *testing.T) {
+	testCases := []struct {
+		name        string
+		groupLength func(actual uint32) uint32
+	}{
+		{name: "understated", groupLength: func(actual uint32) uint32 { return actual - 3 }},
+		{name: "overstated", groupLength: func(actual uint32) uint32 { return actual + 10 }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Recover the real group length from a clean encoding
+			clean := new(bytes.Buffer)
+			require.NoError(t, writeDICOMFile(clean, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+			actual := binary.LittleEndian.Uint32(clean.Bytes()[140:144])
+
+			data := encodeTestFileWithGroupLength(t, tc.groupLength(actual))
+
+			// Strict mode fails
+			_, err := ParseReader(bytes.NewReader(data))
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrGroupLengthMismatch)
+
+			// Tolerant mode warns and parses the dataset
+			var warnings []error
+			ds, err := ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{
+				Tolerant:        true,
+				WarningCallback: func(err error) { warnings = append(warnings, err) },
+			})
+			require.NoError(t, err)
+			require.Len(t, warnings, 1)
+			assert.ErrorIs(t, warnings[0], ErrGroupLengthMismatch)
+
+			elem, err := ds.GetByKeyword("PatientID")
+			require.NoError(t, err)
+			assert.Equal(t, "PAT001", elem.Value().String())
+		})
+	}
+}
+
+// cancelAfterReader cancels a context once more than limit bytes have been read.
+type cancelAfterReader struct {
+	r      io.Reader
+	read   int
+	limit  int
+	cancel context.CancelFunc
+}
+
+func (c *cancelAfterReader) Read(p []byte) (int, error) {
+	// Small reads so cancellation lands between elements
+	if len(p) > 16 {
+		p = p[:16]
+	}
+	n, err := c.r.Read(p)
+	c.read += n
+	if c.read > c.limit {
+		c.cancel()
+	}
+	return n, err
+}
+
+// TestParseReaderContext tests cancellation before and during parsing.
+func TestParseReaderContext(t *testing.T) {
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeDICOMFile(buf, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+	data := buf.Bytes()
+
+	t.Run("background context parses", func(t *testing.T) {
+		ds, err := ParseReaderContext(context.Background(), bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Greater(t, ds.Len(), 0)
+	})
+
+	t.Run("already cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := ParseReaderContext(ctx, bytes.NewReader(data))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("cancelled mid-parse", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Cancel while the last main dataset elements are still unread
+		reader := &cancelAfterReader{r: bytes.NewReader(data), limit: len(data) - 40, cancel: cancel}
+		_, err := ParseReaderContext(ctx, reader)
+		assert.Equal(t, context.Canceled, err)
+	})
+}
+
+// TestParseFileContext_Deadline tests that an expired deadline is reported.
+func TestParseFileContext_Deadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	_, err := ParseFileContext(ctx, filepath.Join("..", "testdata", "dicom", "MR2_UNCR.dcm"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// TestParseFileWithProgress tests that progress ends at the file size and never goes back.
+func TestParseFileWithProgress(t *testing.T) {
+	path := filepath.Join("..", "testdata", "dicom", "MR2_UNCR.dcm")
+	stat, err := os.Stat(path)
+	require.NoError(t, err)
+
+	var calls [][2]int64
+	ds, err := ParseFileWithProgress(path, func(read, total int64) {
+		calls = append(calls, [2]int64{read, total})
+	})
+	require.NoError(t, err)
+
+	expected, err := ParseFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected.Len(), ds.Len())
+
+	require.NotEmpty(t, calls)
+	assert.Equal(t, [2]int64{stat.Size(), stat.Size()}, calls[len(calls)-1])
+	for i := 1; i < len(calls); i++ {
+		assert.GreaterOrEqual(t, calls[i][0], calls[i-1][0])
+	}
+
+	ds, err = ParseFileWithProgress(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, expected.Len(), ds.Len())
+
+	_, err = ParseFileWithProgress("/nonexistent/file.dcm", func(int64, int64) {})
+	assert.Error(t, err)
+}
+
+// TestParseFileWithOptions_TrackOffsets tests that element locations point back into the file.
+func TestParseFileWithOptions_TrackOffsets(t *testing.T) {
+	path := filepath.Join("..", "testdata", "dicom", "MR2_UNCR.dcm")
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	ds, err := ParseFileWithOptions(path, ParseOptions{TrackOffsets: true})
+	require.NoError(t, err)
+
+	for _, elem := range ds.Elements() {
+		loc, ok := elem.Location()
+		require.True(t, ok, "element %s should have a location", elem.Tag())
+
+		// Offset points at the encoded tag
+		group := binary.LittleEndian.Uint16(raw[loc.Offset:])
+		elemNum := binary.LittleEndian.Uint16(raw[loc.Offset+2:])
+		assert.Equal(t, elem.Tag().Group, group, "tag group at offset for %s", elem.Tag())
+		assert.Equal(t, elem.Tag().Element, elemNum, "tag element at offset for %s", elem.Tag())
+		assert.LessOrEqual(t, loc.Offset+loc.Length, int64(len(raw)))
+	}
+
+	// Pixel Data value bytes can be served from a byte-range read
+	pixelElem, err := ds.Get(tag.PixelData)
+	require.NoError(t, err)
+	loc, ok := pixelElem.Location()
+	require.True(t, ok)
+	assert.Equal(t, int64(12), loc.HeaderLength(), "OB/OW explicit VR header is 12 bytes")
+	assert.Equal(t, pixelElem.Value().Bytes(), raw[loc.ValueOffset:loc.ValueOffset+int64(loc.ValueLength)])
+
+	// Without the option no locations are recorded
+	plain, err := ParseFile(path)
+	require.NoError(t, err)
+	plainPixel, err := plain.Get(tag.PixelData)
+	require.NoError(t, err)
+	_, ok = plainPixel.Location()
+	assert.False(t, ok)
+}
+
+// TestParseFileWithOptions_RetainRawValues tests that retained raw bytes match the file.
+func TestParseFileWithOptions_RetainRawValues(t *testing.T) {
+	for _, name := range []string{"MR2_UNCR.dcm", "MR2_UNCI.dcm", "693_J2KR.dcm", "MR-SIEMENS-DICOM-WithOverlays.dcm"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join("..", "testdata", "dicom", name)
+			raw, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			ds, err := ParseFileWithOptions(path, ParseOptions{TrackOffsets: true, RetainRawValues: true})
+			require.NoError(t, err)
+
+			checked := 0
+			require.NoError(t, ds.Walk(func(_ []tag.Tag, elem *element.Element) error {
+				loc, ok := elem.Location()
+				require.True(t, ok, "element %s should have a location", elem.Tag())
+				end := loc.Offset + loc.Length
+				assert.Equal(t, raw[loc.ValueOffset:end], elem.RawBytes(), "raw bytes of %s", elem.Tag())
+				checked++
+				return nil
+			}))
+			assert.Greater(t, checked, 0)
+		})
+	}
+
+	// Without the option no raw bytes are retained
+	plain, err := ParseFile(filepath.Join("..", "testdata", "dicom", "MR2_UNCR.dcm"))
+	require.NoError(t, err)
+	plainPixel, err := plain.Get(tag.PixelData)
+	require.NoError(t, err)
+	assert.Nil(t, plainPixel.RawBytes())
+}
+
+// appendOversizedElement appends an explicit VR OB element header declaring length
+// bytes, with no value following it.
+func appendOversizedElement(data []byte, length uint32) []byte {
+	header := make([]byte, 12)
+	binary.LittleEndian.PutUint16(header[0:2], 0x0011)
+	binary.LittleEndian.PutUint16(header[2:4], 0x1010)
+	copy(header[4:6], "OB")
+	binary.LittleEndian.PutUint32(header[8:12], length)
+	return append(append([]byte{}, data...), header...)
+}
+
+// TestParseReaderWithOptions_MaxElementLength tests that corrupt lengths are rejected
+// before allocation.
+func TestParseReaderWithOptions_MaxElementLength(t *testing.T) {
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeDICOMFile(buf, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+	clean := buf.Bytes()
+
+	t.Run("length beyond remaining stream", func(t *testing.T) {
+		data := appendOversizedElement(clean, 1<<20)
+		_, err := ParseReader(bytes.NewReader(data))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrElementTooLarge)
+	})
+
+	t.Run("length beyond default limit on unsized stream", func(t *testing.T) {
+		data := appendOversizedElement(clean, 0xFFFFFFF0)
+		// MultiReader hides the stream size, leaving only the configured limit
+		_, err := ParseReader(io.MultiReader(bytes.NewReader(data)))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrElementTooLarge)
+	})
+
+	t.Run("custom limit", func(t *testing.T) {
+		_, err := ParseReaderWithOptions(bytes.NewReader(clean), ParseOptions{MaxElementLength: 4})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrElementTooLarge)
+
+		_, err = ParseReaderWithOptions(bytes.NewReader(clean), ParseOptions{MaxElementLength: 1 << 20})
+		assert.NoError(t, err)
+	})
+}
+
+// TestParseReaderWithOptions_TruncatedPixelData tests strict and tolerant handling of
+// a file cut off inside its encapsulated pixel data.
+func TestParseReaderWithOptions_TruncatedPixelData(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("..", "testdata", "dicom", "693_J2KR.dcm"))
+	require.NoError(t, err)
+	// Drop the Sequence Delimitation Item and the tail of the last fragment
+	data := raw[:len(raw)-100]
+
+	// Strict mode fails rather than silently dropping the pixel data
+	_, err = ParseReader(bytes.NewReader(data))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrTruncatedPixelData)
+
+	// Tolerant mode keeps the complete fragments and warns
+	var warnings []error
+	ds, err := ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{
+		Tolerant:        true,
+		WarningCallback: func(err error) { warnings = append(warnings, err) },
+	})
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.ErrorIs(t, warnings[0], ErrTruncatedPixelData)
+
+	elem, err := ds.Get(tag.PixelData)
+	require.NoError(t, err)
+	pixels := elem.Value().Bytes()
+	require.Greater(t, len(pixels), 16)
+	assert.Equal(t, []byte{0xFE, 0xFF, 0x00, 0xE0}, pixels[:4], "starts with the Basic Offset Table item")
+	assert.Equal(t, []byte{0xFE, 0xFF, 0xDD, 0xE0, 0, 0, 0, 0}, pixels[len(pixels)-8:])
+
+	full, err := ParseReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	fullElem, err := full.Get(tag.PixelData)
+	require.NoError(t, err)
+	assert.Less(t, len(pixels), len(fullElem.Value().Bytes()))
+}
+
+// TestParseReaderWithOptions_AllowTrailingData tests that junk after a complete
+// dataset is ignored with a warning by default and fails when disallowed.
+func TestParseReaderWithOptions_AllowTrailingData(t *testing.T) {
+	ds := createTestDatasetForWriter(t)
+	pixels, err := value.NewBytesValue(vr.OtherWord, make([]byte, 64))
+	require.NoError(t, err)
+	pixelElem, err := element.NewElement(tag.PixelData, vr.OtherWord, pixels)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(pixelElem))
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeDICOMFile(buf, ds, applyDefaultWriteOptions(WriteOptions{})))
+	clean := buf.Bytes()
+	want, err := ParseReader(bytes.NewReader(clean))
+	require.NoError(t, err)
+
+	junk := bytes.Repeat([]byte("junk"), 25)
+	data := append(append([]byte{}, clean...), junk...)
+
+	var warnings []error
+	parsed, err := ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{
+		WarningCallback: func(err error) { warnings = append(warnings, err) },
+	})
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.ErrorIs(t, warnings[0], ErrTrailingData)
+	assert.ErrorIs(t, warnings[0], ErrInvalidVR)
+	assert.ErrorContains(t, warnings[0], fmt.Sprintf("100 bytes at offset %d ignored", len(clean)))
+	assert.Equal(t, want.Len(), parsed.Len())
+
+	allow := false
+	_, err = ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{AllowTrailingData: &allow})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidVR)
+
+	t.Run("clean end", func(t *testing.T) {
+		var warnings []error
+		_, err := ParseReaderWithOptions(bytes.NewReader(clean), ParseOptions{
+			AllowTrailingData: &allow,
+			WarningCallback:   func(err error) { warnings = append(warnings, err) },
+		})
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("truncated last element", func(t *testing.T) {
+		data := appendShortElement(clean, tag.New(0x0011, 0x1010), "LO", "PRIVATE VALUE")
+		data = data[:len(data)-4]
+
+		// On a stream of known size the short value is refused as ErrElementTooLarge
+		// before it is read; MultiReader hides the size so the stream runs out instead
+		var warnings []error
+		ds, err := ParseReaderWithOptions(io.MultiReader(bytes.NewReader(data)), ParseOptions{
+			WarningCallback: func(err error) { warnings = append(warnings, err) },
+		})
+		require.NoError(t, err)
+		require.Len(t, warnings, 1)
+		assert.ErrorIs(t, warnings[0], ErrTrailingData)
+		assert.ErrorContains(t, warnings[0], fmt.Sprintf("bytes from offset %d ignored", len(clean)))
+		assert.Equal(t, want.Len(), ds.Len())
+
+		_, err = ParseReaderWithOptions(io.MultiReader(bytes.NewReader(data)), ParseOptions{AllowTrailingData: &allow})
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("invalid value still fails", func(t *testing.T) {
+		data := appendShortElement(clean, tag.New(0x0011, 0x0010), "DA", "1994.11.05")
+		_, err := ParseReader(bytes.NewReader(data))
+		assert.Error(t, err)
+	})
+
+	t.Run("mid-file corruption still fails", func(t *testing.T) {
+		// Corrupt the VR of Patient ID (0010,0020), between elements that follow it
+		idTag := []byte{0x10, 0x00, 0x20, 0x00, 'L', 'O'}
+		offset := bytes.Index(clean, idTag)
+		require.Positive(t, offset)
+		data := append([]byte{}, clean...)
+		copy(data[offset+4:], "??")
+
+		_, err := ParseReader(bytes.NewReader(data))
+		assert.ErrorIs(t, err, ErrInvalidVR)
+	})
+
+	t.Run("truncated pixel data still fails", func(t *testing.T) {
+		data := clean[:len(clean)-10]
+		_, err := ParseReader(io.MultiReader(bytes.NewReader(data)))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("junk after a dataset without pixel data", func(t *testing.T) {
+		// Objects such as SR have no Pixel Data to mark the end of the dataset
+		buf := new(bytes.Buffer)
+		require.NoError(t, writeDICOMFile(buf, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+		noPixels := buf.Bytes()
+		want, err := ParseReader(bytes.NewReader(noPixels))
+		require.NoError(t, err)
+		data := append(append([]byte{}, noPixels...), junk...)
+
+		var warnings []error
+		parsed, err := ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{
+			WarningCallback: func(err error) { warnings = append(warnings, err) },
+		})
+		require.NoError(t, err)
+		require.Len(t, warnings, 1)
+		assert.ErrorIs(t, warnings[0], ErrTrailingData)
+		assert.ErrorContains(t, warnings[0], fmt.Sprintf("100 bytes at offset %d ignored", len(noPixels)))
+		assert.Equal(t, want.Len(), parsed.Len())
+	})
+}
+
+// appendShortElement appends an explicit VR element with a 2-byte length field.
+func appendShortElement(data []byte, t tag.Tag, vrCode string, val string) []byte {
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint16(header[0:2], t.Group)
+	binary.LittleEndian.PutUint16(header[2:4], t.Element)
+	copy(header[4:6], vrCode)
+	binary.LittleEndian.PutUint16(header[6:8], uint16(len(val)))
+	return append(append(append([]byte{}, data...), header...), val...)
+}
+
+// TestParseReaderWithOptions_OnDuplicateTag tests each duplicate tag policy on a
+// stream that repeats a private tag and is out of ascending order.
+func TestParseReaderWithOptions_OnDuplicateTag(t *testing.T) {
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeDICOMFile(buf, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+
+	privateTag := tag.New(0x0011, 0x1010)
+	data := appendShortElement(buf.Bytes(), privateTag, "LO", "FIRST ")
+	data = appendShortElement(data, privateTag, "LO", "SECOND")
+	data = appendShortElement(data, tag.AccessionNumber, "SH", "ACC1")
+
+	testCases := []struct {
+		name   string
+		policy DuplicateTagPolicy
+		want   string
+	}{
+		{name: "keep last (default)", policy: DuplicateTagKeepLast, want: "SECOND"},
+		{name: "keep first", policy: DuplicateTagKeepFirst, want: "FIRST"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var warnings []error
+			ds, err := ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{
+				OnDuplicateTag:  tc.policy,
+				WarningCallback: func(err error) { warnings = append(warnings, err) },
+			})
+			require.NoError(t, err)
+			require.Len(t, warnings, 1)
+			assert.ErrorIs(t, warnings[0], ErrDuplicateTag)
+
+			elem, err := ds.Get(privateTag)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, elem.Value().String())
+
+			// The out-of-order element is still parsed
+			elem, err = ds.Get(tag.AccessionNumber)
+			require.NoError(t, err)
+			assert.Equal(t, "ACC1", elem.Value().String())
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		_, err := ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{OnDuplicateTag: DuplicateTagError})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrDuplicateTag)
+	})
+}
+
+// appendLongElement appends an explicit VR element with a 4-byte length field,
+// such as UN, holding val.
+func appendLongElement(data []byte, t tag.Tag, vrCode string, val string) []byte {
+	header := make([]byte, 12)
+	binary.LittleEndian.PutUint16(header[0:2], t.Group)
+	binary.LittleEndian.PutUint16(header[2:4], t.Element)
+	copy(header[4:6], vrCode)
+	binary.LittleEndian.PutUint32(header[8:12], uint32(len(val)))
+	return append(append(append([]byte{}, data...), header...), val...)
+}
+
+// TestParseReaderWithOptions_VROverrides tests that overridden tags are decoded as
+// the override VR in both Explicit and Implicit VR, including in sequence items.
+func TestParseReaderWithOptions_VROverrides(t *testing.T) {
+	privateTag := tag.New(0x0011, 0x1010)
+	overrides := map[tag.Tag]vr.VR{
+		tag.SliceThickness: vr.DecimalString,
+		privateTag:         vr.DecimalString,
+	}
+
+	requireDS := func(t *testing.T, ds *DataSet, tg tag.Tag, want float64) {
+		t.Helper()
+		elem, err := ds.Get(tg)
+		require.NoError(t, err)
+		assert.Equal(t, vr.DecimalString, elem.VR())
+		floats, err := ds.GetFloats(tg)
+		require.NoError(t, err)
+		assert.Equal(t, []float64{want}, floats)
+	}
+
+	t.Run("explicit VR", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, writeDICOMFile(buf, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+		// A DS value marked as UN, followed by a correctly encoded element
+		data := appendLongElement(buf.Bytes(), tag.SliceThickness, "UN", "2.5 ")
+		data = appendShortElement(data, tag.AccessionNumber, "SH", "ACC1")
+
+		plain, err := ParseReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		elem, err := plain.Get(tag.SliceThickness)
+		require.NoError(t, err)
+		assert.Equal(t, vr.Unknown, elem.VR())
+
+		ds, err := ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{VROverrides: overrides})
+		require.NoError(t, err)
+		requireDS(t, ds, tag.SliceThickness, 2.5)
+		accession, err := ds.Get(tag.AccessionNumber)
+		require.NoError(t, err, "the header is read with the file's VR")
+		assert.Equal(t, "ACC1", accession.Value().String())
+	})
+
+	t.Run("implicit VR", func(t *testing.T) {
+		source := createTestDatasetForWriter(t)
+		unknown, err := value.NewBytesValue(vr.Unknown, []byte("7.25"))
+		require.NoError(t, err)
+		privateElem, err := element.NewElement(privateTag, vr.Unknown, unknown)
+		require.NoError(t, err)
+		require.NoError(t, source.Add(privateElem))
+
+		item := NewDataSet()
+		require.NoError(t, item.Add(privateElem))
+		seq, err := NewSequenceElement(tag.ReferencedImageSequence, []*DataSet{item})
+		require.NoError(t, err)
+		require.NoError(t, source.Add(seq))
+
+		implicitUID := uid.ImplicitVRLittleEndian
+		buf := new(bytes.Buffer)
+		require.NoError(t, writeDICOMFile(buf, source, applyDefaultWriteOptions(WriteOptions{TransferSyntax: &implicitUID})))
+
+		plain, err := ParseReader(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		elem, err := plain.Get(privateTag)
+		require.NoError(t, err)
+		assert.Equal(t, vr.Unknown, elem.VR(), "private tags are UN in Implicit VR")
+
+		ds, err := ParseReaderWithOptions(bytes.NewReader(buf.Bytes()), ParseOptions{VROverrides: overrides})
+		require.NoError(t, err)
+		requireDS(t, ds, privateTag, 7.25)
+		items, err := ds.GetSequenceItems(tag.ReferencedImageSequence)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		requireDS(t, items[0], privateTag, 7.25)
+	})
+
+	t.Run("invalid overrides", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, writeDICOMFile(buf, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+
+		for _, v := range []vr.VR{vr.SequenceOfItems, vr.VR(0)} {
+			_, err := ParseReaderWithOptions(bytes.NewReader(buf.Bytes()), ParseOptions{
+				VROverrides: map[tag.Tag]vr.VR{privateTag: v},
+			})
+			assert.ErrorIs(t, err, ErrInvalidVR, "override %d", v)
+		}
+	})
+}
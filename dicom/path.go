@@ -0,0 +1,98 @@
+package dicom
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// GetPath returns the value of a nested element addressed by a path.
+//
+// A path is a list of segments separated by "/". Each element segment is either a
+// keyword ("ReferencedSOPInstanceUID") or a tag in "(GGGG,EEEE)" or "GGGG,EEEE"
+// notation, and each sequence must be followed by the 0-based index of the item to
+// descend into. The last segment must name an element, whose value is returned.
+//
+// Errors identify the segment where the path could not be followed, for example a
+// missing element, an index out of range or an element that is not a sequence.
+//
+// Example:
+//
+//	v, err := ds.GetPath("ReferencedImageSequence/0/ReferencedSOPInstanceUID")
+//	v, err := ds.GetPath("(0008,1140)/0/(0008,1155)")
+//
+//	// Slice thickness of the second frame of an enhanced multi-frame image
+//	v, err := ds.GetPath("PerFrameFunctionalGroupsSequence/1/PixelMeasuresSequence/0/SliceThickness")
+func (ds *DataSet) GetPath(path string) (value.Value, error) {
+	segments := strings.Split(path, "/")
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	current := ds
+	for i := 0; i < len(segments); i++ {
+		segment := strings.TrimSpace(segments[i])
+		t, err := parsePathTag(segment)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: segment %d %q: %w", path, i+1, segment, err)
+		}
+
+		elem, err := current.Get(t)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: segment %d %q: element %s not found", path, i+1, segment, t)
+		}
+
+		if i == len(segments)-1 {
+			return elem.Value(), nil
+		}
+
+		// Any further segment must select an item of this sequence
+		seq, ok := elem.Value().(*value.SequenceValue)
+		if !ok {
+			return nil, fmt.Errorf("path %q: segment %d %q: element %s is not a sequence (VR %s)",
+				path, i+1, segment, t, elem.VR())
+		}
+		items, err := SequenceItems(seq)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: segment %d %q: %w", path, i+1, segment, err)
+		}
+
+		i++
+		indexSegment := strings.TrimSpace(segments[i])
+		index, err := strconv.Atoi(indexSegment)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: segment %d %q: expected item index after sequence %s",
+				path, i+1, indexSegment, t)
+		}
+		if index < 0 || index >= len(items) {
+			return nil, fmt.Errorf("path %q: segment %d: item index %d out of range, sequence %s has %d items",
+				path, i+1, index, t, len(items))
+		}
+		if i == len(segments)-1 {
+			return nil, fmt.Errorf("path %q: ends at sequence item %d, expected an element", path, index)
+		}
+
+		current = items[index]
+	}
+
+	return nil, fmt.Errorf("path %q: no element", path)
+}
+
+// parsePathTag resolves a path segment given as a tag or a keyword.
+func parsePathTag(segment string) (tag.Tag, error) {
+	if segment == "" {
+		return tag.Tag{}, fmt.Errorf("empty segment")
+	}
+	if strings.Contains(segment, ",") {
+		return tag.Parse(segment)
+	}
+
+	info, err := tag.FindByKeyword(segment)
+	if err != nil {
+		return tag.Tag{}, fmt.Errorf("unknown keyword %q", segment)
+	}
+	return info.Tag, nil
+}
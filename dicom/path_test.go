@@ -0,0 +1,86 @@
+package dicom_test
+
+import (
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSet_GetPath(t *testing.T) {
+	newItem := func(sopUID string) *dicom.DataSet {
+		item := dicom.NewDataSet()
+		require.NoError(t, item.Add(mustNewElement(tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier,
+			mustNewStringValue(vr.UniqueIdentifier, []string{sopUID}))))
+		return item
+	}
+
+	// Two-level nesting: PerFrameFunctionalGroupsSequence/n/PixelMeasuresSequence/0
+	measures := dicom.NewDataSet()
+	require.NoError(t, measures.Add(mustNewElement(tag.SliceThickness, vr.DecimalString,
+		mustNewStringValue(vr.DecimalString, []string{"1.25"}))))
+	pixelMeasures, err := dicom.NewSequenceElement(tag.PixelMeasuresSequence, []*dicom.DataSet{measures})
+	require.NoError(t, err)
+	frame := dicom.NewDataSet()
+	require.NoError(t, frame.Add(pixelMeasures))
+	perFrame, err := dicom.NewSequenceElement(tag.PerFrameFunctionalGroupsSequence,
+		[]*dicom.DataSet{dicom.NewDataSet(), frame})
+	require.NoError(t, err)
+
+	refImages, err := dicom.NewSequenceElement(tag.ReferencedImageSequence,
+		[]*dicom.DataSet{newItem("1.2.3.1"), newItem("1.2.3.2")})
+	require.NoError(t, err)
+
+	ds := dicom.NewDataSet()
+	require.NoError(t, ds.Add(refImages))
+	require.NoError(t, ds.Add(perFrame))
+	require.NoError(t, ds.Add(mustNewElement(tag.Modality, vr.CodeString,
+		mustNewStringValue(vr.CodeString, []string{"CT"}))))
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"top-level keyword", "Modality", "CT"},
+		{"keywords", "ReferencedImageSequence/1/ReferencedSOPInstanceUID", "1.2.3.2"},
+		{"tags", "(0008,1140)/0/(0008,1155)", "1.2.3.1"},
+		{"tags without parentheses", "0008,1140/1/0008,1155", "1.2.3.2"},
+		{"mixed", "(0008,1140)/0/ReferencedSOPInstanceUID", "1.2.3.1"},
+		{"nested", "PerFrameFunctionalGroupsSequence/1/PixelMeasuresSequence/0/SliceThickness", "1.25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ds.GetPath(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v.String())
+		})
+	}
+
+	errorTests := []struct {
+		name    string
+		path    string
+		wantMsg string
+	}{
+		{"empty path", "", "empty path"},
+		{"unknown keyword", "NotAKeyword", "unknown keyword"},
+		{"missing element", "ReferencedImageSequence/0/SOPClassUID", "segment 3"},
+		{"index out of range", "ReferencedImageSequence/2/ReferencedSOPInstanceUID", "out of range"},
+		{"missing index", "ReferencedImageSequence/ReferencedSOPInstanceUID", "expected item index"},
+		{"not a sequence", "Modality/0/ReferencedSOPInstanceUID", "not a sequence"},
+		{"ends at item", "ReferencedImageSequence/0", "expected an element"},
+		{"missing in nested item", "PerFrameFunctionalGroupsSequence/0/PixelMeasuresSequence/0/SliceThickness", "segment 3"},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ds.GetPath(tt.path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package pixel
+
+import "sync"
+
+// BufferPool recycles pixel data buffers between Extract calls.
+//
+// Decoding a large series allocates a new buffer for every image, which puts heavy
+// pressure on the garbage collector in high-throughput pipelines such as AI
+// preprocessing. A BufferPool keeps buffers of a fixed size in a sync.Pool so that
+// they can be reused once the caller is done with the pixel data.
+//
+// Create one pool per image size, pass it to Extract with WithBufferPool and return
+// each buffer with Put when the PixelData is no longer used. Pixel data that does not
+// fit in a pool buffer is allocated normally.
+//
+// A BufferPool is safe for concurrent use.
+//
+// Example:
+//
+//	pool := pixel.NewBufferPool(512 * 512 * 2) // 512×512 16-bit slices
+//	for _, ds := range series {
+//	    pd, err := pixel.Extract(ds, pixel.WithBufferPool(pool))
+//	    if err != nil {
+//	        return err
+//	    }
+//	    process(pd)
+//	    pool.Put(pd.RawBytes()) // pd must not be used after this
+//	}
+type BufferPool struct {
+	size int
+	pool sync.Pool
+}
+
+// NewBufferPool creates a pool of buffers of frameSizeBytes bytes.
+//
+// For single-frame images this is Rows × Columns × SamplesPerPixel × BitsAllocated/8.
+// For multi-frame images, size the pool for all frames of an image, since Extract
+// returns them in one contiguous buffer.
+func NewBufferPool(frameSizeBytes int) *BufferPool {
+	p := &BufferPool{size: max(frameSizeBytes, 0)}
+	p.pool.New = func() any {
+		buf := make([]byte, p.size)
+		return &buf
+	}
+	return p
+}
+
+// Size returns the length of the buffers in the pool.
+func (p *BufferPool) Size() int {
+	return p.size
+}
+
+// Get returns a buffer of Size bytes. Its contents are undefined.
+func (p *BufferPool) Get() []byte {
+	buf := p.pool.Get().(*[]byte)
+	return (*buf)[:p.size]
+}
+
+// Put returns a buffer to the pool for reuse. The caller must not use buf afterwards.
+//
+// Buffers smaller than Size, such as pixel data that was allocated because it did not
+// fit in the pool, are dropped.
+func (p *BufferPool) Put(buf []byte) {
+	if p == nil || cap(buf) < p.size {
+		return
+	}
+	buf = buf[:p.size]
+	p.pool.Put(&buf)
+}
+
+// ExtractOption configures Extract.
+type ExtractOption func(*extractOptions)
+
+// extractOptions holds the settings applied by ExtractOptions.
+type extractOptions struct {
+	pool  *BufferPool
+	cache *DecodeCache
+}
+
+// WithBufferPool makes Extract place decoded pixel data in buffers drawn from pool.
+//
+// Pixel data of up to pool.Size() bytes is decoded into a pool buffer; larger data is
+// allocated as usual. With a pool, native (uncompressed) pixel data is copied out of
+// the dataset rather than shared with it, so every buffer returned by Extract can be
+// handed back with pool.Put.
+func WithBufferPool(pool *BufferPool) ExtractOption {
+	return func(o *extractOptions) {
+		o.pool = pool
+	}
+}
+
+// applyExtractOptions builds extractOptions from the given options.
+func applyExtractOptions(opts []ExtractOption) extractOptions {
+	var o extractOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// buffer returns an n-byte buffer, drawn from the pool when one is configured and n fits.
+func (o *extractOptions) buffer(n int) []byte {
+	if o.pool != nil && n <= o.pool.Size() {
+		return o.pool.Get()[:n]
+	}
+	return make([]byte, n)
+}
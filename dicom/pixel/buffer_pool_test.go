@@ -0,0 +1,167 @@
+package pixel
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExtractDataSet builds a dataset holding pd encoded with the given transfer syntax.
+func newExtractDataSet(tb testing.TB, pd *PixelData, tsUID string) *dicom.DataSet {
+	tb.Helper()
+
+	ds := dicom.NewDataSet()
+	add := func(elem *element.Element, err error) {
+		require.NoError(tb, err)
+		require.NoError(tb, ds.Add(elem))
+	}
+	addUint16 := func(tg tag.Tag, n uint16) {
+		val, err := value.NewIntValue(vr.UnsignedShort, []int64{int64(n)})
+		require.NoError(tb, err)
+		add(element.NewElement(tg, vr.UnsignedShort, val))
+	}
+	addString := func(tg tag.Tag, v vr.VR, s string) {
+		val, err := value.NewStringValue(v, []string{s})
+		require.NoError(tb, err)
+		add(element.NewElement(tg, v, val))
+	}
+
+	addUint16(tag.Rows, pd.Rows)
+	addUint16(tag.Columns, pd.Columns)
+	addUint16(tag.BitsAllocated, pd.BitsAllocated)
+	addUint16(tag.BitsStored, pd.BitsStored)
+	addUint16(tag.HighBit, pd.HighBit)
+	addUint16(tag.PixelRepresentation, pd.PixelRepresentation)
+	addUint16(tag.SamplesPerPixel, pd.SamplesPerPixel)
+	addString(tag.PhotometricInterpretation, vr.CodeString, pd.PhotometricInterpretation)
+	addString(tag.NumberOfFrames, vr.IntegerString, strconv.Itoa(max(pd.NumberOfFrames, 1)))
+	addString(tag.TransferSyntaxUID, vr.UniqueIdentifier, tsUID)
+
+	data := pd.RawBytes()
+	if isEncapsulated(tsUID) {
+		var err error
+		data, err = EncodeForTransferSyntax(pd, tsUID)
+		require.NoError(tb, err)
+	}
+	val, err := value.NewBytesValue(vr.OtherByte, data)
+	require.NoError(tb, err)
+	add(element.NewElement(tag.PixelData, vr.OtherByte, val))
+
+	return ds
+}
+
+func TestBufferPool(t *testing.T) {
+	pool := NewBufferPool(64)
+	assert.Equal(t, 64, pool.Size())
+
+	buf := pool.Get()
+	assert.Len(t, buf, 64)
+	pool.Put(buf[:10]) // Resliced buffers keep their capacity
+	assert.Len(t, pool.Get(), 64)
+
+	// Undersized buffers are dropped rather than handed out
+	pool.Put(make([]byte, 8))
+	assert.Len(t, pool.Get(), 64)
+
+	var nilPool *BufferPool
+	assert.NotPanics(t, func() { nilPool.Put(buf) })
+}
+
+func TestExtract_WithBufferPool(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:        PatternGradient,
+		Rows:           8,
+		Columns:        8,
+		NumberOfFrames: 2,
+		BitsAllocated:  8,
+	})
+	size := len(pd.RawBytes())
+
+	tests := []struct {
+		name  string
+		tsUID string
+	}{
+		{"native", "1.2.840.10008.1.2.1"},
+		{"RLE Lossless", "1.2.840.10008.1.2.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newExtractDataSet(t, pd, tt.tsUID)
+			pool := NewBufferPool(size)
+
+			got, err := Extract(ds, WithBufferPool(pool))
+			require.NoError(t, err)
+			assert.Equal(t, pd.RawBytes(), got.RawBytes())
+
+			// The buffer belongs to the caller: recycling it must not touch the dataset
+			buf := got.RawBytes()
+			for i := range buf {
+				buf[i] = 0xFF
+			}
+			pool.Put(buf)
+
+			again, err := Extract(ds, WithBufferPool(pool))
+			require.NoError(t, err)
+			assert.Equal(t, pd.RawBytes(), again.RawBytes())
+		})
+	}
+
+	t.Run("pixel data larger than pool", func(t *testing.T) {
+		ds := newExtractDataSet(t, pd, "1.2.840.10008.1.2.5")
+		got, err := Extract(ds, WithBufferPool(NewBufferPool(size/2)))
+		require.NoError(t, err)
+		assert.Equal(t, pd.RawBytes(), got.RawBytes())
+	})
+}
+
+// BenchmarkExtract_Series decodes an RLE-compressed series of single-frame images
+// with and without a buffer pool.
+func BenchmarkExtract_Series(b *testing.B) {
+	const seriesLength = 32
+
+	series := make([]*dicom.DataSet, seriesLength)
+	var frameSize int
+	for i := range series {
+		pd := NewSyntheticPixelData(SyntheticOptions{
+			Pattern:       PatternGradient,
+			Rows:          512,
+			Columns:       512,
+			BitsAllocated: 8,
+		})
+		frameSize = len(pd.RawBytes())
+		series[i] = newExtractDataSet(b, pd, "1.2.840.10008.1.2.5")
+	}
+
+	b.Run("NoPool", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, ds := range series {
+				if _, err := Extract(ds); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("BufferPool", func(b *testing.B) {
+		pool := NewBufferPool(frameSize)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, ds := range series {
+				pd, err := Extract(ds, WithBufferPool(pool))
+				if err != nil {
+					b.Fatal(err)
+				}
+				pool.Put(pd.RawBytes())
+			}
+		}
+	})
+}
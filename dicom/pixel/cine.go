@@ -0,0 +1,126 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// FrameTimings returns the time at which each frame of a cine loop is displayed,
+// measured from the start of the loop.
+//
+// Timing is read from Frame Time Vector (0018,1065) when present, whose values are
+// the increments from the previous frame (the first is normally 0), and otherwise from
+// the constant Frame Time (0018,1063). Both are in milliseconds. The result has one
+// entry per frame (Number of Frames, default 1) and starts at 0 for a constant frame
+// time.
+//
+// Returns an error wrapping ErrMissingRequiredAttribute if neither attribute is
+// present, and an error if the Frame Time Vector length differs from Number of Frames
+// or a time is negative.
+//
+// Example:
+//
+//	timings, err := pixel.FrameTimings(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for i, at := range timings {
+//	    time.AfterFunc(at, func() { show(frames[i]) })
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.5
+func FrameTimings(ds *dicom.DataSet) ([]time.Duration, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	numberOfFrames := getIntWithDefault(ds, tag.NumberOfFrames, 1)
+	if numberOfFrames < 1 {
+		return nil, fmt.Errorf("invalid number of frames: %d", numberOfFrames)
+	}
+
+	if vector, err := ds.GetFloats(tag.FrameTimeVector); err == nil && len(vector) > 0 {
+		if len(vector) != numberOfFrames {
+			return nil, fmt.Errorf("frame time vector has %d values but number of frames is %d",
+				len(vector), numberOfFrames)
+		}
+
+		timings := make([]time.Duration, numberOfFrames)
+		var elapsed float64
+		for i, increment := range vector {
+			if increment < 0 || math.IsNaN(increment) {
+				return nil, fmt.Errorf("invalid frame time increment for frame %d: %v ms", i+1, increment)
+			}
+			elapsed += increment
+			timings[i] = millisecondsToDuration(elapsed)
+		}
+		return timings, nil
+	}
+
+	frameTime, err := ds.GetFloats(tag.FrameTime)
+	if err != nil || len(frameTime) == 0 {
+		return nil, &MissingAttributeError{
+			AttributeName: "FrameTime or FrameTimeVector",
+			Tag:           tag.FrameTime.String(),
+		}
+	}
+	if frameTime[0] < 0 || math.IsNaN(frameTime[0]) {
+		return nil, fmt.Errorf("invalid frame time: %v ms", frameTime[0])
+	}
+
+	timings := make([]time.Duration, numberOfFrames)
+	for i := range timings {
+		timings[i] = millisecondsToDuration(float64(i) * frameTime[0])
+	}
+	return timings, nil
+}
+
+// RecommendedFrameRate returns the frame rate, in frames per second, at which a cine
+// loop should be played.
+//
+// The rate is read from Cine Rate (0018,0040), then Recommended Display Frame Rate
+// (0008,2144). If neither is present it is derived from Frame Time (0018,1063) as
+// 1000 / FrameTime.
+//
+// Returns an error wrapping ErrMissingRequiredAttribute if no rate can be determined.
+//
+// Example:
+//
+//	fps, err := pixel.RecommendedFrameRate(ds)
+//	if err == nil {
+//	    ticker := time.NewTicker(time.Duration(float64(time.Second) / fps))
+//	    defer ticker.Stop()
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.5
+func RecommendedFrameRate(ds *dicom.DataSet) (float64, error) {
+	if ds == nil {
+		return 0, fmt.Errorf("dataset is nil")
+	}
+
+	for _, t := range []tag.Tag{tag.CineRate, tag.RecommendedDisplayFrameRate} {
+		if rates, err := ds.GetFloats(t); err == nil && len(rates) > 0 && rates[0] > 0 {
+			return rates[0], nil
+		}
+	}
+
+	if frameTime, err := ds.GetFloats(tag.FrameTime); err == nil && len(frameTime) > 0 && frameTime[0] > 0 {
+		return 1000 / frameTime[0], nil
+	}
+
+	return 0, &MissingAttributeError{
+		AttributeName: "CineRate or RecommendedDisplayFrameRate",
+		Tag:           tag.CineRate.String(),
+	}
+}
+
+// millisecondsToDuration converts a time in milliseconds to a time.Duration.
+func millisecondsToDuration(ms float64) time.Duration {
+	return time.Duration(math.Round(ms * float64(time.Millisecond)))
+}
@@ -0,0 +1,117 @@
+package pixel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func addCineString(t *testing.T, ds *dicom.DataSet, tg tag.Tag, v vr.VR, values ...string) {
+	val, err := value.NewStringValue(v, values)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, v, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+func TestFrameTimings(t *testing.T) {
+	t.Run("constant frame time", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addCineString(t, ds, tag.NumberOfFrames, vr.IntegerString, "4")
+		addCineString(t, ds, tag.FrameTime, vr.DecimalString, "33.3")
+
+		timings, err := FrameTimings(ds)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{
+			0,
+			33300 * time.Microsecond,
+			66600 * time.Microsecond,
+			99900 * time.Microsecond,
+		}, timings)
+	})
+
+	t.Run("frame time vector is cumulative", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addCineString(t, ds, tag.NumberOfFrames, vr.IntegerString, "4")
+		addCineString(t, ds, tag.FrameTime, vr.DecimalString, "100")
+		addCineString(t, ds, tag.FrameTimeVector, vr.DecimalString, "0", "40", "40", "120")
+
+		timings, err := FrameTimings(ds)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{
+			0,
+			40 * time.Millisecond,
+			80 * time.Millisecond,
+			200 * time.Millisecond,
+		}, timings)
+	})
+
+	t.Run("single frame default", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addCineString(t, ds, tag.FrameTime, vr.DecimalString, "50")
+
+		timings, err := FrameTimings(ds)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{0}, timings)
+	})
+}
+
+func TestFrameTimings_Errors(t *testing.T) {
+	_, err := FrameTimings(nil)
+	assert.Error(t, err)
+
+	_, err = FrameTimings(dicom.NewDataSet())
+	assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+
+	ds := dicom.NewDataSet()
+	addCineString(t, ds, tag.NumberOfFrames, vr.IntegerString, "3")
+	addCineString(t, ds, tag.FrameTimeVector, vr.DecimalString, "0", "40")
+	_, err = FrameTimings(ds)
+	assert.Error(t, err)
+
+	addCineString(t, ds, tag.FrameTimeVector, vr.DecimalString, "0", "-40", "40")
+	_, err = FrameTimings(ds)
+	assert.Error(t, err)
+}
+
+func TestRecommendedFrameRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements map[tag.Tag]string
+		want     float64
+	}{
+		{"cine rate", map[tag.Tag]string{tag.CineRate: "30", tag.RecommendedDisplayFrameRate: "25"}, 30},
+		{"recommended display frame rate", map[tag.Tag]string{tag.RecommendedDisplayFrameRate: "25"}, 25},
+		{"derived from frame time", map[tag.Tag]string{tag.FrameTime: "40"}, 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := dicom.NewDataSet()
+			for tg, s := range tt.elements {
+				v := vr.IntegerString
+				if tg.Equals(tag.FrameTime) {
+					v = vr.DecimalString
+				}
+				addCineString(t, ds, tg, v, s)
+			}
+
+			rate, err := RecommendedFrameRate(ds)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, rate, 1e-9)
+		})
+	}
+
+	_, err := RecommendedFrameRate(dicom.NewDataSet())
+	assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+
+	_, err = RecommendedFrameRate(nil)
+	assert.Error(t, err)
+}
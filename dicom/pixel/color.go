@@ -0,0 +1,213 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+)
+
+// colorTransform is an affine colour space transform of 8-bit samples:
+// out = matrix * (in - inOffset) + outOffset.
+type colorTransform struct {
+	matrix    [3][3]float64
+	inOffset  [3]float64
+	outOffset [3]float64
+}
+
+// apply transforms the three samples of one pixel, rounding the results to the
+// nearest integer and clamping them to 0-255.
+func (t colorTransform) apply(samples [3]float64) [3]uint8 {
+	var out [3]uint8
+	for c := 0; c < 3; c++ {
+		m := t.matrix[c]
+		v := m[0]*(samples[0]-t.inOffset[0]) + m[1]*(samples[1]-t.inOffset[1]) + m[2]*(samples[2]-t.inOffset[2])
+		out[c] = clampUint8(int32(math.Round(v + t.outOffset[c])))
+	}
+	return out
+}
+
+var (
+	// rgbToYBRFull is the ITU-R BT.601 full range transform of DICOM YBR_FULL.
+	rgbToYBRFull = colorTransform{
+		matrix: [3][3]float64{
+			{0.2990, 0.5870, 0.1140},
+			{-0.1687, -0.3313, 0.5000},
+			{0.5000, -0.4187, -0.0813},
+		},
+		outOffset: [3]float64{0, 128, 128},
+	}
+
+	// ybrFullToRGB is the inverse of rgbToYBRFull.
+	ybrFullToRGB = colorTransform{
+		matrix: [3][3]float64{
+			{1, 0, 1.402},
+			{1, -0.344136, -0.714136},
+			{1, 1.772, 0},
+		},
+		inOffset: [3]float64{0, 128, 128},
+	}
+
+	// rgbToYBRPartial is the ITU-R BT.601 partial (studio) range transform of DICOM
+	// YBR_PARTIAL_422 and YBR_PARTIAL_420, with Y in 16-235 and Cb, Cr in 16-240.
+	rgbToYBRPartial = colorTransform{
+		matrix: [3][3]float64{
+			{0.2568, 0.5041, 0.0979},
+			{-0.1482, -0.2910, 0.4392},
+			{0.4392, -0.3678, -0.0714},
+		},
+		outOffset: [3]float64{16, 128, 128},
+	}
+
+	// ybrPartialToRGB is the inverse of rgbToYBRPartial.
+	ybrPartialToRGB = colorTransform{
+		matrix: [3][3]float64{
+			{1.1644, 0, 1.5960},
+			{1.1644, -0.3918, -0.8130},
+			{1.1644, 2.0172, 0},
+		},
+		inOffset: [3]float64{16, 128, 128},
+	}
+)
+
+// RGBToYBRFull converts 8-bit RGB pixel data to YBR_FULL, the ITU-R BT.601 full range
+// luminance and chrominance encoding used by DICOM and expected by JPEG encoders:
+//
+//	Y  =  0.2990*R + 0.5870*G + 0.1140*B
+//	Cb = -0.1687*R - 0.3313*G + 0.5000*B + 128
+//	Cr =  0.5000*R - 0.4187*G - 0.0813*B + 128
+//
+// Values are rounded to the nearest integer, so an RGB → YBR_FULL → RGB round trip
+// reproduces each channel within ±1. Planar Configuration is preserved.
+//
+// Returns an error if p is not 8-bit RGB.
+//
+// Example:
+//
+//	ybr, err := pixel.RGBToYBRFull(rgb)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.3.1.2
+func RGBToYBRFull(p *PixelData) (*PixelData, error) {
+	return convertColor(p, []string{"RGB"}, rgbToYBRFull, "YBR_FULL")
+}
+
+// YBRFullToRGB converts 8-bit YBR_FULL pixel data to RGB, the inverse of
+// RGBToYBRFull:
+//
+//	R = Y                        + 1.402    * (Cr - 128)
+//	G = Y - 0.344136 * (Cb - 128) - 0.714136 * (Cr - 128)
+//	B = Y + 1.772    * (Cb - 128)
+//
+// Values are rounded to the nearest integer and clamped to 0-255. Planar
+// Configuration is preserved.
+//
+// Returns an error if p is not 8-bit YBR_FULL.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.3.1.2
+func YBRFullToRGB(p *PixelData) (*PixelData, error) {
+	return convertColor(p, []string{"YBR_FULL"}, ybrFullToRGB, "RGB")
+}
+
+// RGBToYBRPartial converts 8-bit RGB pixel data to the ITU-R BT.601 partial range
+// encoding of YBR_PARTIAL_422 and YBR_PARTIAL_420, with Y in 16-235 and Cb, Cr in
+// 16-240:
+//
+//	Y  =  0.2568*R + 0.5041*G + 0.0979*B + 16
+//	Cb = -0.1482*R - 0.2910*G + 0.4392*B + 128
+//	Cr =  0.4392*R - 0.3678*G - 0.0714*B + 128
+//
+// The chrominance is not subsampled: every pixel keeps its own Cb and Cr, as in the
+// output of a decoder. The result is labelled YBR_PARTIAL_422 because DICOM defines
+// no partial range interpretation without subsampling. The narrower range loses
+// precision, so a round trip reproduces each channel within ±2.
+//
+// Returns an error if p is not 8-bit RGB.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.3.1.2
+func RGBToYBRPartial(p *PixelData) (*PixelData, error) {
+	return convertColor(p, []string{"RGB"}, rgbToYBRPartial, "YBR_PARTIAL_422")
+}
+
+// YBRPartialToRGB converts 8-bit partial range YBR pixel data (YBR_PARTIAL_422 or
+// YBR_PARTIAL_420 with one Cb and Cr per pixel, as produced by RGBToYBRPartial or a
+// decoder) to RGB:
+//
+//	R = 1.1644 * (Y - 16)                        + 1.5960 * (Cr - 128)
+//	G = 1.1644 * (Y - 16) - 0.3918 * (Cb - 128) - 0.8130 * (Cr - 128)
+//	B = 1.1644 * (Y - 16) + 2.0172 * (Cb - 128)
+//
+// Returns an error if p is not 8-bit partial range YBR.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.3.1.2
+func YBRPartialToRGB(p *PixelData) (*PixelData, error) {
+	return convertColor(p, []string{"YBR_PARTIAL_422", "YBR_PARTIAL_420"}, ybrPartialToRGB, "RGB")
+}
+
+// convertColor applies t to every pixel of p, which must be 8-bit with three samples
+// per pixel and one of the given photometric interpretations.
+func convertColor(p *PixelData, sourcePIs []string, t colorTransform, targetPI string) (*PixelData, error) {
+	if p == nil {
+		return nil, fmt.Errorf("pixel data is nil")
+	}
+	supported := false
+	for _, pi := range sourcePIs {
+		supported = supported || p.PhotometricInterpretation == pi
+	}
+	if !supported {
+		return nil, fmt.Errorf("%s conversion requires photometric interpretation %v, got %s",
+			targetPI, sourcePIs, p.PhotometricInterpretation)
+	}
+	if p.SamplesPerPixel != 3 {
+		return nil, fmt.Errorf("%s conversion requires SamplesPerPixel=3, got %d", targetPI, p.SamplesPerPixel)
+	}
+	if p.BitsAllocated != 8 {
+		return nil, fmt.Errorf("%s conversion currently only supports 8-bit data, got %d", targetPI, p.BitsAllocated)
+	}
+
+	// Sample c of pixel i is at i*3+c when interleaved. When planar, each frame holds
+	// its own three planes, so sample c of pixel j of a frame starting at base is at
+	// base+c*planeSize+j.
+	pixels := len(p.data) / 3
+	planeSize := int(p.Rows) * int(p.Columns)
+	if p.PlanarConfiguration != 1 || planeSize == 0 || pixels%planeSize != 0 {
+		planeSize = pixels
+	}
+	offset := func(i, c int) int {
+		if p.PlanarConfiguration != 1 {
+			return i*3 + c
+		}
+		return (i/planeSize)*planeSize*3 + c*planeSize + i%planeSize
+	}
+
+	data := make([]byte, len(p.data))
+	var in [3]float64
+	for i := 0; i < pixels; i++ {
+		for c := 0; c < 3; c++ {
+			in[c] = float64(p.data[offset(i, c)])
+		}
+		out := t.apply(in)
+		for c := 0; c < 3; c++ {
+			data[offset(i, c)] = out[c]
+		}
+	}
+
+	return &PixelData{
+		Rows:                      p.Rows,
+		Columns:                   p.Columns,
+		BitsAllocated:             p.BitsAllocated,
+		BitsStored:                p.BitsStored,
+		HighBit:                   p.HighBit,
+		PixelRepresentation:       p.PixelRepresentation,
+		SamplesPerPixel:           p.SamplesPerPixel,
+		PhotometricInterpretation: targetPI,
+		PlanarConfiguration:       p.PlanarConfiguration,
+		NumberOfFrames:            p.NumberOfFrames,
+		data:                      data,
+		TransferSyntaxUID:         p.TransferSyntaxUID,
+	}, nil
+}
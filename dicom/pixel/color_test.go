@@ -0,0 +1,231 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newColorCube returns an RGB image sampling the 8-bit colour cube in steps of 5,
+// including the extremes 0 and 255, with one row per red level.
+func newColorCube(t *testing.T) *PixelData {
+	var data []byte
+	for r := 0; r <= 255; r += 5 {
+		for g := 0; g <= 255; g += 5 {
+			for b := 0; b <= 255; b += 5 {
+				data = append(data, byte(r), byte(g), byte(b))
+			}
+		}
+	}
+	pd, err := NewPixelDataFromRGB(data, 52*52, 52)
+	require.NoError(t, err)
+	return pd
+}
+
+// maxChannelError returns the largest absolute difference between samples of a and b.
+func maxChannelError(a, b []byte) int {
+	worst := 0
+	for i := range a {
+		d := int(a[i]) - int(b[i])
+		if d < 0 {
+			d = -d
+		}
+		worst = max(worst, d)
+	}
+	return worst
+}
+
+func TestRGBToYBRFull(t *testing.T) {
+	pd, err := NewPixelDataFromRGB([]byte{255, 0, 0, 0, 255, 0, 0, 0, 255, 128, 128, 128}, 2, 2)
+	require.NoError(t, err)
+
+	ybr, err := RGBToYBRFull(pd)
+	require.NoError(t, err)
+	assert.Equal(t, "YBR_FULL", ybr.PhotometricInterpretation)
+	assert.Equal(t, []byte{
+		76, 85, 255, // red
+		150, 44, 21, // green
+		29, 255, 107, // blue
+		128, 128, 128, // grey has no chrominance
+	}, ybr.data)
+}
+
+func TestYBRFull_RoundTrip(t *testing.T) {
+	rgb := newColorCube(t)
+
+	ybr, err := RGBToYBRFull(rgb)
+	require.NoError(t, err)
+	back, err := YBRFullToRGB(ybr)
+	require.NoError(t, err)
+
+	assert.Equal(t, "RGB", back.PhotometricInterpretation)
+	assert.LessOrEqual(t, maxChannelError(rgb.data, back.data), 1)
+}
+
+func TestYBRPartial_RoundTrip(t *testing.T) {
+	rgb := newColorCube(t)
+
+	ybr, err := RGBToYBRPartial(rgb)
+	require.NoError(t, err)
+	assert.Equal(t, "YBR_PARTIAL_422", ybr.PhotometricInterpretation)
+	for i := 0; i < len(ybr.data); i += 3 {
+		y, cb, cr := ybr.data[i], ybr.data[i+1], ybr.data[i+2]
+		if y < 16 || y > 235 || cb < 16 || cb > 240 || cr < 16 || cr > 240 {
+			t.Fatalf("pixel %d: YBR (%d, %d, %d) outside the partial range", i/3, y, cb, cr)
+		}
+	}
+
+	back, err := YBRPartialToRGB(ybr)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, maxChannelError(rgb.data, back.data), 2)
+}
+
+func TestYBRFull_Planar(t *testing.T) {
+	interleaved := []byte{255, 0, 0, 10, 200, 30}
+	planar := []byte{255, 10, 0, 200, 0, 30}
+	pd, err := NewPixelDataBuilder().
+		WithDimensions(2, 1).
+		WithBitsAllocated(8).
+		WithSamplesPerPixel(3).
+		WithPhotometricInterpretation("RGB").
+		WithPlanarConfiguration(1).
+		WithPixelData(planar).
+		Build()
+	require.NoError(t, err)
+	reference, err := NewPixelDataFromRGB(interleaved, 2, 1)
+	require.NoError(t, err)
+
+	ybr, err := RGBToYBRFull(pd)
+	require.NoError(t, err)
+	want, err := RGBToYBRFull(reference)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint16(1), ybr.PlanarConfiguration)
+	assert.Equal(t, []byte{want.data[0], want.data[3], want.data[1], want.data[4], want.data[2], want.data[5]}, ybr.data)
+}
+
+func TestYBRFull_PlanarMultiFrame(t *testing.T) {
+	// Two frames of two pixels, each frame with its own R, G and B planes
+	frames := [][]byte{{255, 0, 0, 10, 200, 30}, {0, 0, 255, 90, 90, 90}}
+	var planar []byte
+	for _, f := range frames {
+		planar = append(planar, f[0], f[3], f[1], f[4], f[2], f[5])
+	}
+	pd, err := NewPixelDataBuilder().
+		WithDimensions(2, 1).
+		WithBitsAllocated(8).
+		WithSamplesPerPixel(3).
+		WithPhotometricInterpretation("RGB").
+		WithPlanarConfiguration(1).
+		WithNumberOfFrames(2).
+		WithPixelData(planar).
+		Build()
+	require.NoError(t, err)
+
+	ybr, err := RGBToYBRFull(pd)
+	require.NoError(t, err)
+
+	var want []byte
+	for _, f := range frames {
+		reference, err := NewPixelDataFromRGB(f, 2, 1)
+		require.NoError(t, err)
+		w, err := RGBToYBRFull(reference)
+		require.NoError(t, err)
+		want = append(want, w.data[0], w.data[3], w.data[1], w.data[4], w.data[2], w.data[5])
+	}
+	assert.Equal(t, want, ybr.data)
+}
+
+func TestColorConversion_Errors(t *testing.T) {
+	rgb, err := NewPixelDataFromRGB([]byte{1, 2, 3}, 1, 1)
+	require.NoError(t, err)
+	_, err = YBRFullToRGB(rgb)
+	assert.Error(t, err, "source must be YBR_FULL")
+	_, err = YBRPartialToRGB(rgb)
+	assert.Error(t, err)
+
+	gray, err := NewPixelDataFromUint8([]uint8{1, 2, 3, 4}, 2, 2)
+	require.NoError(t, err)
+	gray.PhotometricInterpretation = "RGB"
+	_, err = RGBToYBRFull(gray)
+	assert.Error(t, err, "requires three samples per pixel")
+
+	_, err = RGBToYBRFull(nil)
+	assert.Error(t, err)
+}
+
+// newDoubledColorCube returns the colour cube of newColorCube with every pixel
+// repeated horizontally, so that each pair subsampled by 4:2:2 shares one colour.
+func newDoubledColorCube(t *testing.T) *PixelData {
+	cube := newColorCube(t)
+	data := make([]byte, 0, 2*len(cube.data))
+	for i := 0; i < len(cube.data); i += 3 {
+		data = append(data, cube.data[i:i+3]...)
+		data = append(data, cube.data[i:i+3]...)
+	}
+	pd, err := NewPixelDataFromRGB(data, 2*52*52, 52)
+	require.NoError(t, err)
+	return pd
+}
+
+func TestConvertPhotometricInterpretation_422(t *testing.T) {
+	rgb := newDoubledColorCube(t)
+
+	for _, tc := range []struct {
+		pi     string
+		direct func(*PixelData) (*PixelData, error)
+	}{
+		{"YBR_FULL_422", RGBToYBRFull},
+		{"YBR_PARTIAL_422", RGBToYBRPartial},
+	} {
+		t.Run(tc.pi, func(t *testing.T) {
+			ybr, err := ConvertPhotometricInterpretation(rgb, tc.pi)
+			require.NoError(t, err)
+			assert.Equal(t, tc.pi, ybr.PhotometricInterpretation)
+
+			// Each pair is stored as Y0 Cb Y1 Cr with the rounded values of the
+			// unsubsampled transform.
+			direct, err := tc.direct(rgb)
+			require.NoError(t, err)
+			for i := 0; i < len(ybr.data); i += 6 {
+				want := []byte{direct.data[i], direct.data[i+1], direct.data[i+3], direct.data[i+5]}
+				got := []byte{ybr.data[i], ybr.data[i+1], ybr.data[i+3], ybr.data[i+4]}
+				require.Equal(t, want, got, "pair %d", i/6)
+			}
+
+			back, err := ConvertPhotometricInterpretation(ybr, "RGB")
+			require.NoError(t, err)
+			assert.LessOrEqual(t, maxChannelError(rgb.data, back.data), 2)
+		})
+	}
+}
+
+func TestConvertPhotometricInterpretation_422OddColumns(t *testing.T) {
+	pd, err := NewPixelDataFromRGB([]byte{
+		255, 0, 0, 255, 0, 0, 0, 0, 255,
+	}, 3, 1)
+	require.NoError(t, err)
+
+	ybr, err := ConvertPhotometricInterpretation(pd, "YBR_PARTIAL_422")
+	require.NoError(t, err)
+	direct, err := RGBToYBRPartial(pd)
+	require.NoError(t, err)
+	// The unpaired last pixel keeps its own Y, Cb and Cr.
+	assert.Equal(t, direct.data[6:], ybr.data[6:])
+
+	back, err := ConvertPhotometricInterpretation(ybr, "RGB")
+	require.NoError(t, err)
+	assert.LessOrEqual(t, maxChannelError(pd.data, back.data), 2)
+}
+
+func TestConvertPhotometricInterpretation_Partial420(t *testing.T) {
+	rgb := newColorCube(t)
+
+	ybr, err := RGBToYBRPartial(rgb)
+	require.NoError(t, err)
+	ybr.PhotometricInterpretation = "YBR_PARTIAL_420"
+	back, err := ConvertPhotometricInterpretation(ybr, "RGB")
+	require.NoError(t, err)
+	assert.LessOrEqual(t, maxChannelError(rgb.data, back.data), 2)
+}
@@ -0,0 +1,155 @@
+package pixel
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+)
+
+// DecodeCache is a size-bounded LRU cache of decompressed frames.
+//
+// Interactive viewers decode the same frames over and over as the user scrubs
+// through a series. For expensive codecs such as JPEG 2000 and HTJ2K, passing a
+// DecodeCache to Extract with WithCache turns repeated decodes into a hash and a copy.
+//
+// Entries are keyed by a SHA-256 hash of the compressed frame bytes together with the
+// transfer syntax and decode parameters (dimensions, bit depth, samples, photometric
+// interpretation), so identical frames are shared across datasets and a frame is
+// never returned for a different pixel description. Only encapsulated (compressed)
+// frames are cached; native pixel data needs no decoding.
+//
+// When adding a frame would exceed the byte budget, the least recently used frames
+// are evicted. Frames larger than the whole budget are not cached.
+//
+// A DecodeCache is safe for concurrent use.
+//
+// Example:
+//
+//	cache := pixel.NewDecodeCache(256 << 20) // 256 MiB of decoded frames
+//	for {
+//	    pd, err := pixel.Extract(currentDataSet(), pixel.WithCache(cache))
+//	    if err != nil {
+//	        return err
+//	    }
+//	    render(pd)
+//	}
+type DecodeCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	ll       *list.List
+	entries  map[decodeCacheKey]*list.Element
+	hits     uint64
+	misses   uint64
+}
+
+// decodeCacheKey identifies a compressed frame and the parameters it was decoded with.
+type decodeCacheKey [sha256.Size]byte
+
+// decodeCacheEntry is a cached decoded frame.
+type decodeCacheEntry struct {
+	key  decodeCacheKey
+	data []byte
+}
+
+// NewDecodeCache creates a cache holding up to maxBytes of decoded frame data.
+func NewDecodeCache(maxBytes int) *DecodeCache {
+	return &DecodeCache{
+		maxBytes: max(maxBytes, 0),
+		ll:       list.New(),
+		entries:  make(map[decodeCacheKey]*list.Element),
+	}
+}
+
+// WithCache makes Extract look up decoded frames in cache before decompressing them,
+// and store newly decoded frames in it.
+func WithCache(cache *DecodeCache) ExtractOption {
+	return func(o *extractOptions) {
+		o.cache = cache
+	}
+}
+
+// Len returns the number of cached frames.
+func (c *DecodeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Size returns the number of bytes of decoded data held by the cache.
+func (c *DecodeCache) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Stats returns the number of cache hits and misses since the cache was created.
+func (c *DecodeCache) Stats() (hits, misses uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Purge removes all cached frames.
+func (c *DecodeCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	clear(c.entries)
+	c.size = 0
+}
+
+// get returns the cached frame for key. The returned slice must not be modified.
+func (c *DecodeCache) get(key decodeCacheKey) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.ll.MoveToFront(e)
+		c.hits++
+		return e.Value.(*decodeCacheEntry).data, true
+	}
+	c.misses++
+	return nil, false
+}
+
+// add stores a decoded frame, evicting least recently used frames to stay in budget.
+// The cache keeps data, so the caller must not modify it afterwards.
+func (c *DecodeCache) add(key decodeCacheKey, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(data) > c.maxBytes {
+		return
+	}
+	if e, ok := c.entries[key]; ok {
+		c.ll.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.ll.PushFront(&decodeCacheEntry{key: key, data: data})
+	c.size += len(data)
+
+	for c.size > c.maxBytes {
+		oldest := c.ll.Back()
+		entry := oldest.Value.(*decodeCacheEntry)
+		c.ll.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.size -= len(entry.data)
+	}
+}
+
+// newDecodeCacheKey hashes a compressed frame with the parameters used to decode it.
+func newDecodeCacheKey(compressed []byte, info *PixelInfo) decodeCacheKey {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s|%d|%d|%d|%d|%d|%d|%d|%s|%d\x00",
+		info.TransferSyntaxUID, info.Rows, info.Columns, info.BitsAllocated, info.BitsStored,
+		info.HighBit, info.PixelRepresentation, info.SamplesPerPixel,
+		info.PhotometricInterpretation, info.PlanarConfiguration)
+	h.Write(compressed)
+
+	var key decodeCacheKey
+	h.Sum(key[:0])
+	return key
+}
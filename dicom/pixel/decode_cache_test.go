@@ -0,0 +1,128 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCache_LRU(t *testing.T) {
+	cache := NewDecodeCache(10)
+	key := func(b byte) decodeCacheKey { return decodeCacheKey{b} }
+
+	cache.add(key(1), make([]byte, 4))
+	cache.add(key(2), make([]byte, 4))
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, 8, cache.Size())
+
+	// Touch 1 so that 2 becomes the least recently used entry
+	_, ok := cache.get(key(1))
+	require.True(t, ok)
+
+	cache.add(key(3), make([]byte, 4))
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, 8, cache.Size())
+
+	_, ok = cache.get(key(2))
+	assert.False(t, ok, "least recently used frame should be evicted")
+	_, ok = cache.get(key(1))
+	assert.True(t, ok)
+	_, ok = cache.get(key(3))
+	assert.True(t, ok)
+
+	// Frames larger than the whole budget are not cached
+	cache.add(key(4), make([]byte, 11))
+	_, ok = cache.get(key(4))
+	assert.False(t, ok)
+
+	hits, misses := cache.Stats()
+	assert.Equal(t, uint64(3), hits)
+	assert.Equal(t, uint64(2), misses)
+
+	cache.Purge()
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, 0, cache.Size())
+}
+
+func TestExtract_WithCache(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:        PatternCheckerboard,
+		Rows:           16,
+		Columns:        16,
+		NumberOfFrames: 3,
+		BitsAllocated:  8,
+	})
+	ds := newExtractDataSet(t, pd, "1.2.840.10008.1.2.5")
+	cache := NewDecodeCache(1 << 20)
+
+	first, err := Extract(ds, WithCache(cache))
+	require.NoError(t, err)
+	assert.Equal(t, pd.RawBytes(), first.RawBytes())
+
+	// The checkerboard frames are identical, so they share one cache entry
+	hits, misses := cache.Stats()
+	assert.Equal(t, uint64(2), hits)
+	assert.Equal(t, uint64(1), misses)
+	assert.Equal(t, 1, cache.Len())
+
+	// Modifying the returned pixel data must not corrupt the cache
+	buf := first.RawBytes()
+	for i := range buf {
+		buf[i] = 0xAA
+	}
+
+	second, err := Extract(ds, WithCache(cache))
+	require.NoError(t, err)
+	assert.Equal(t, pd.RawBytes(), second.RawBytes())
+	hits, misses = cache.Stats()
+	assert.Equal(t, uint64(5), hits)
+	assert.Equal(t, uint64(1), misses)
+}
+
+func TestNewDecodeCacheKey(t *testing.T) {
+	info := &PixelInfo{Rows: 4, Columns: 4, BitsAllocated: 8, BitsStored: 8, HighBit: 7,
+		SamplesPerPixel: 1, PhotometricInterpretation: "MONOCHROME2", TransferSyntaxUID: "1.2.840.10008.1.2.5"}
+	data := []byte{1, 2, 3, 4}
+
+	assert.Equal(t, newDecodeCacheKey(data, info), newDecodeCacheKey(append([]byte{}, data...), info))
+	assert.NotEqual(t, newDecodeCacheKey(data, info), newDecodeCacheKey([]byte{1, 2, 3, 5}, info))
+
+	other := *info
+	other.PhotometricInterpretation = "MONOCHROME1"
+	assert.NotEqual(t, newDecodeCacheKey(data, info), newDecodeCacheKey(data, &other))
+}
+
+// BenchmarkExtract_Cache decodes the same compressed multi-frame image repeatedly,
+// as a viewer does while scrubbing, with and without a decode cache.
+func BenchmarkExtract_Cache(b *testing.B) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:        PatternGradient,
+		Rows:           512,
+		Columns:        512,
+		NumberOfFrames: 8,
+		BitsAllocated:  8,
+	})
+	ds := newExtractDataSet(b, pd, "1.2.840.10008.1.2.5")
+
+	b.Run("NoCache", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := Extract(ds); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("CacheHit", func(b *testing.B) {
+		cache := NewDecodeCache(64 << 20)
+		if _, err := Extract(ds, WithCache(cache)); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := Extract(ds, WithCache(cache)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package pixel
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/codeninja55/go-radx/dicom"
+)
+
+// FramesDigest returns the SHA-256 digest of each frame's decoded pixel bytes, for
+// de-duplicating stored instances by pixel content.
+//
+// The digests cover decoded pixels in the little-endian layout returned by Extract,
+// so they are independent of the transfer syntax: the same image stored uncompressed
+// and losslessly compressed has the same frame digests, as do two instances that
+// differ only in patient or study metadata. This is unlike a digest of the encoded
+// dataset, which changes with any attribute or with the encoding.
+//
+// Frames are decoded and hashed one at a time, so memory use is bounded by the size
+// of a single frame rather than the whole multi-frame image.
+//
+// Returns an error if the pixel attributes are missing or a frame cannot be decoded.
+//
+// Example:
+//
+//	a, err := pixel.FramesDigest(original)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	b, _ := pixel.FramesDigest(candidate)
+//	duplicate := len(a) == len(b)
+//	for i := range a {
+//	    duplicate = duplicate && bytes.Equal(a[i], b[i])
+//	}
+func FramesDigest(ds *dicom.DataSet) ([][]byte, error) {
+	var digests [][]byte
+	err := forEachFrame(ds, func(_ int, frame []byte) error {
+		sum := sha256.Sum256(frame)
+		digests = append(digests, sum[:])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return digests, nil
+}
+
+// forEachFrame decodes the frames of ds in order and calls fn with each, stopping at
+// the first error. The frame slice is only valid during the call.
+func forEachFrame(ds *dicom.DataSet, fn func(index int, frame []byte) error) error {
+	info, data, err := readPixelInfo(ds)
+	if err != nil {
+		return err
+	}
+	if info.NumberOfFrames < 1 {
+		return &PixelDataError{
+			Field:    "NumberOfFrames",
+			Expected: "at least 1",
+			Actual:   info.NumberOfFrames,
+		}
+	}
+
+	decoder, err := GetDecoder(info.TransferSyntaxUID)
+	if err != nil {
+		return err
+	}
+
+	frameSize := CalculateExpectedSize(info) / info.NumberOfFrames
+	swap := info.TransferSyntaxUID == explicitVRBigEndianUID && info.BitsAllocated == 16
+
+	frameInfo := *info
+	frameInfo.NumberOfFrames = 1
+
+	if !isEncapsulated(info.TransferSyntaxUID) {
+		if err := ValidatePixelData(data, info); err != nil {
+			return err
+		}
+		var swapped []byte
+		if swap {
+			swapped = make([]byte, frameSize)
+		}
+		for i := 0; i < info.NumberOfFrames; i++ {
+			frame := data[i*frameSize : (i+1)*frameSize]
+			if swap {
+				// The dataset's bytes are left untouched
+				copy(swapped, frame)
+				SwapBytes16(swapped)
+				frame = swapped
+			}
+			if err := fn(i, frame); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	encapsulated, err := ParseEncapsulatedPixelData(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse encapsulated pixel data: %w", err)
+	}
+	for i := 0; i < info.NumberOfFrames; i++ {
+		fragments, err := encapsulated.GetFrameFragments(i)
+		if err != nil {
+			return fmt.Errorf("failed to get fragments of frame %d: %w", i, err)
+		}
+		frame, err := decoder.Decode(ConcatenateFragments(fragments), &frameInfo)
+		if err != nil {
+			return fmt.Errorf("failed to decode frame %d: %w", i, err)
+		}
+		if len(frame) != frameSize {
+			return &PixelDataError{
+				Field:    fmt.Sprintf("frame %d size", i),
+				Expected: fmt.Sprintf("%d bytes", frameSize),
+				Actual:   fmt.Sprintf("%d bytes", len(frame)),
+			}
+		}
+		if err := fn(i, frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package pixel
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDigestPixelData returns three 4x2 16-bit frames, the last equal to the first.
+func newDigestPixelData(t *testing.T) *PixelData {
+	samples := []uint16{
+		0, 1, 2, 3, 4, 5, 6, 7,
+		100, 200, 300, 400, 500, 600, 700, 800,
+		0, 1, 2, 3, 4, 5, 6, 7,
+	}
+	data := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		data[i*2] = byte(s)
+		data[i*2+1] = byte(s >> 8)
+	}
+	pd, err := NewPixelDataBuilder().
+		WithDimensions(4, 2).
+		WithBitsAllocated(16).
+		WithPhotometricInterpretation("MONOCHROME2").
+		WithNumberOfFrames(3).
+		WithPixelData(data).
+		Build()
+	require.NoError(t, err)
+	return pd
+}
+
+func TestFramesDigest(t *testing.T) {
+	pd := newDigestPixelData(t)
+	ds := newExtractDataSet(t, pd, "1.2.840.10008.1.2.1")
+
+	digests, err := FramesDigest(ds)
+	require.NoError(t, err)
+	require.Len(t, digests, 3)
+
+	raw := pd.RawBytes()
+	want := sha256.Sum256(raw[:16])
+	assert.Equal(t, want[:], digests[0])
+	assert.NotEqual(t, digests[0], digests[1])
+	assert.Equal(t, digests[0], digests[2], "identical frames have identical digests")
+
+	t.Run("metadata does not affect digests", func(t *testing.T) {
+		other := newExtractDataSet(t, pd, "1.2.840.10008.1.2.1")
+		addGSPSString(t, other, tag.PatientName, vr.PersonName, "Other^Patient")
+		addGSPSString(t, other, tag.PatientID, vr.LongString, "OTHER")
+		got, err := FramesDigest(other)
+		require.NoError(t, err)
+		assert.Equal(t, digests, got)
+	})
+
+	t.Run("independent of transfer syntax", func(t *testing.T) {
+		for _, tsUID := range []string{"1.2.840.10008.1.2", explicitVRBigEndianUID} {
+			encoded := newExtractDataSet(t, pd, tsUID)
+			if tsUID == explicitVRBigEndianUID {
+				bigEndian := append([]byte(nil), raw...)
+				SwapBytes16(bigEndian)
+				setPixelDataBytes(t, encoded, bigEndian)
+			}
+			got, err := FramesDigest(encoded)
+			require.NoError(t, err, tsUID)
+			assert.Equal(t, digests, got, tsUID)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := FramesDigest(dicom.NewDataSet())
+		assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+
+		short := newExtractDataSet(t, pd, "1.2.840.10008.1.2.1")
+		setPixelDataBytes(t, short, raw[:20])
+		_, err = FramesDigest(short)
+		assert.Error(t, err)
+	})
+}
+
+// setPixelDataBytes replaces the PixelData of ds with data.
+func setPixelDataBytes(t *testing.T, ds *dicom.DataSet, data []byte) {
+	val, err := value.NewBytesValue(vr.OtherWord, data)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tag.PixelData, vr.OtherWord, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
@@ -0,0 +1,313 @@
+package pixel
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// DimensionIndices returns the Dimension Index Values (0020,9157) of every frame of
+// an enhanced multi-frame image.
+//
+// Values are read from the Frame Content Sequence (0020,9111) of each Per-Frame
+// Functional Groups Sequence (5200,9230) item. The result has one entry per frame,
+// each holding one value per dimension in the order of the Dimension Index Sequence
+// (0020,9222). Index values are 1-based as stored in the dataset.
+//
+// Returns an error if the Per-Frame Functional Groups Sequence is missing, if its item
+// count disagrees with Number of Frames (0028,0008), or if frames have differing
+// numbers of dimension values.
+//
+// Example:
+//
+//	indices, err := pixel.DimensionIndices(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("frame 1 is at %v\n", indices[0])  // e.g. [1 3] (stack 1, position 3)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.17
+func DimensionIndices(ds *dicom.DataSet) ([][]uint32, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	frames, err := ds.GetSequenceItems(tag.PerFrameFunctionalGroupsSequence)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Per-Frame Functional Groups Sequence: %v", ErrMissingRequiredAttribute, err)
+	}
+
+	if numberOfFrames := getIntWithDefault(ds, tag.NumberOfFrames, len(frames)); numberOfFrames != len(frames) {
+		return nil, fmt.Errorf("number of frames is %d but Per-Frame Functional Groups Sequence has %d items",
+			numberOfFrames, len(frames))
+	}
+
+	numDimensions := -1
+	if dims, err := ds.GetSequenceItems(tag.DimensionIndexSequence); err == nil {
+		numDimensions = len(dims)
+	}
+
+	indices := make([][]uint32, len(frames))
+	for i, frame := range frames {
+		content, err := frame.GetSequenceItems(tag.FrameContentSequence)
+		if err != nil || len(content) == 0 {
+			return nil, fmt.Errorf("frame %d: missing Frame Content Sequence", i+1)
+		}
+
+		elem, err := content[0].Get(tag.DimensionIndexValues)
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: missing Dimension Index Values", i+1)
+		}
+		intVal, ok := elem.Value().(*value.IntValue)
+		if !ok {
+			return nil, fmt.Errorf("frame %d: Dimension Index Values has unexpected value type %T", i+1, elem.Value())
+		}
+
+		values := make([]uint32, len(intVal.Ints()))
+		for j, v := range intVal.Ints() {
+			values[j] = uint32(v)
+		}
+
+		if numDimensions < 0 {
+			numDimensions = len(values)
+		}
+		if len(values) != numDimensions {
+			return nil, fmt.Errorf("frame %d has %d dimension index values, expected %d", i+1, len(values), numDimensions)
+		}
+
+		indices[i] = values
+	}
+
+	return indices, nil
+}
+
+// SortFramesByDimension returns the 0-based frame numbers of an enhanced multi-frame
+// image ordered by the chosen dimensions.
+//
+// dimOrder lists positions in the Dimension Index Values (0-based, following the
+// Dimension Index Sequence order) from most to least significant. For example, with
+// dimensions [Stack ID, In-Stack Position, Temporal Position], dimOrder []int{0, 2, 1}
+// groups frames by stack, then by time point, then by slice: the layout needed to
+// assemble a 4D volume. Frames that compare equal keep their stored order.
+//
+// Example:
+//
+//	order, err := pixel.SortFramesByDimension(ds, []int{0, 2, 1})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	frames := pd.Frames()
+//	for _, idx := range order {
+//	    process(frames[idx])
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.17
+func SortFramesByDimension(ds *dicom.DataSet, dimOrder []int) ([]int, error) {
+	indices, err := DimensionIndices(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dimOrder) == 0 {
+		return nil, fmt.Errorf("at least one dimension is required")
+	}
+	if len(indices) > 0 {
+		for _, d := range dimOrder {
+			if d < 0 || d >= len(indices[0]) {
+				return nil, fmt.Errorf("dimension %d out of range (dataset has %d dimensions)", d, len(indices[0]))
+			}
+		}
+	}
+
+	order := make([]int, len(indices))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		ia, ib := indices[order[a]], indices[order[b]]
+		for _, d := range dimOrder {
+			if ia[d] != ib[d] {
+				return ia[d] < ib[d]
+			}
+		}
+		return false
+	})
+
+	return order, nil
+}
+
+// DimensionSpec describes one dimension of an enhanced multi-frame image, in the
+// order its index appears in the Dimension Index Values (0020,9157) of each frame.
+type DimensionSpec struct {
+	// IndexPointer is the attribute whose values the dimension indexes, e.g.
+	// tag.InStackPositionNumber or tag.ImagePositionPatient.
+	IndexPointer tag.Tag
+
+	// FunctionalGroupPointer is the functional group sequence holding the
+	// attribute, e.g. tag.FrameContentSequence or tag.PlanePositionSequence. It is
+	// the zero tag for attributes at the top level of the dataset.
+	FunctionalGroupPointer tag.Tag
+
+	// IndexPrivateCreator and FunctionalGroupPrivateCreator identify the private
+	// blocks of IndexPointer and FunctionalGroupPointer. Each is required if its
+	// tag is private.
+	IndexPrivateCreator           string
+	FunctionalGroupPrivateCreator string
+
+	// Label is the optional Dimension Description Label (0020,9421).
+	Label string
+}
+
+// BuildDimensionOrganization returns the Dimension Organization Sequence (0020,9221)
+// and Dimension Index Sequence (0020,9222) of an enhanced multi-frame image with the
+// dimensions dims.
+//
+// Both sequences carry the same newly generated Dimension Organization UID. The
+// Dimension Index Sequence has one item per dimension, in the order of dims, so the
+// Dimension Index Values of each frame's Frame Content Sequence must hold one index
+// per dimension in that order, as DimensionIndices reads them.
+//
+// Returns an error if:
+//   - dims is empty or lists the same attribute of the same functional group twice
+//   - an IndexPointer is the zero tag, a sequence, or a standard tag unknown to the
+//     data dictionary
+//   - a FunctionalGroupPointer is a standard tag that is not a sequence
+//   - a private tag has no private creator, or a Label is not a valid LO value
+//
+// Example:
+//
+//	organization, index, err := pixel.BuildDimensionOrganization([]pixel.DimensionSpec{
+//	    {IndexPointer: tag.StackID, FunctionalGroupPointer: tag.FrameContentSequence},
+//	    {IndexPointer: tag.InStackPositionNumber, FunctionalGroupPointer: tag.FrameContentSequence},
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	orgElem, _ := element.NewElement(tag.DimensionOrganizationSequence, vr.SequenceOfItems, &organization)
+//	indexElem, _ := element.NewElement(tag.DimensionIndexSequence, vr.SequenceOfItems, &index)
+//	ds.Set(orgElem)
+//	ds.Set(indexElem)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.17
+func BuildDimensionOrganization(dims []DimensionSpec) (value.SequenceValue, value.SequenceValue, error) {
+	if len(dims) == 0 {
+		return value.SequenceValue{}, value.SequenceValue{}, fmt.Errorf("at least one dimension is required")
+	}
+
+	type dimensionKey struct{ index, group tag.Tag }
+	seen := make(map[dimensionKey]bool, len(dims))
+	for i, dim := range dims {
+		if err := validateDimensionSpec(dim); err != nil {
+			return value.SequenceValue{}, value.SequenceValue{}, fmt.Errorf("dimension %d: %w", i+1, err)
+		}
+		key := dimensionKey{dim.IndexPointer, dim.FunctionalGroupPointer}
+		if seen[key] {
+			return value.SequenceValue{}, value.SequenceValue{}, fmt.Errorf("dimension %d: %s in %s is already a dimension",
+				i+1, dim.IndexPointer, dim.FunctionalGroupPointer)
+		}
+		seen[key] = true
+	}
+
+	organizationUID := uid.Generate()
+
+	organization := dicom.NewDataSet()
+	if err := setStringElement(organization, tag.DimensionOrganizationUID, vr.UniqueIdentifier, organizationUID); err != nil {
+		return value.SequenceValue{}, value.SequenceValue{}, err
+	}
+
+	items := make([]value.Item, len(dims))
+	for i, dim := range dims {
+		item := dicom.NewDataSet()
+		if err := setStringElement(item, tag.DimensionOrganizationUID, vr.UniqueIdentifier, organizationUID); err != nil {
+			return value.SequenceValue{}, value.SequenceValue{}, err
+		}
+		if err := setDimensionPointer(item, tag.DimensionIndexPointer, dim.IndexPointer); err != nil {
+			return value.SequenceValue{}, value.SequenceValue{}, err
+		}
+		if dim.FunctionalGroupPointer != (tag.Tag{}) {
+			if err := setDimensionPointer(item, tag.FunctionalGroupPointer, dim.FunctionalGroupPointer); err != nil {
+				return value.SequenceValue{}, value.SequenceValue{}, err
+			}
+		}
+		for _, field := range []struct {
+			t tag.Tag
+			s string
+		}{
+			{tag.DimensionIndexPrivateCreator, dim.IndexPrivateCreator},
+			{tag.FunctionalGroupPrivateCreator, dim.FunctionalGroupPrivateCreator},
+			{tag.DimensionDescriptionLabel, dim.Label},
+		} {
+			if field.s == "" {
+				continue
+			}
+			if err := setStringElement(item, field.t, vr.LongString, field.s); err != nil {
+				return value.SequenceValue{}, value.SequenceValue{}, fmt.Errorf("dimension %d: %w", i+1, err)
+			}
+		}
+		items[i] = item
+	}
+
+	organizationSeq, err := value.NewSequenceValue([]value.Item{organization})
+	if err != nil {
+		return value.SequenceValue{}, value.SequenceValue{}, err
+	}
+	indexSeq, err := value.NewSequenceValue(items)
+	if err != nil {
+		return value.SequenceValue{}, value.SequenceValue{}, err
+	}
+	return *organizationSeq, *indexSeq, nil
+}
+
+// validateDimensionSpec checks that dim points at an attribute that can index frames.
+func validateDimensionSpec(dim DimensionSpec) error {
+	if dim.IndexPointer == (tag.Tag{}) {
+		return fmt.Errorf("Dimension Index Pointer is required")
+	}
+	if dim.IndexPointer.IsPrivate() {
+		if dim.IndexPrivateCreator == "" {
+			return fmt.Errorf("private Dimension Index Pointer %s requires a private creator", dim.IndexPointer)
+		}
+	} else {
+		info, err := tag.Find(dim.IndexPointer)
+		if err != nil {
+			return fmt.Errorf("Dimension Index Pointer %s is not a known attribute", dim.IndexPointer)
+		}
+		if len(info.VRs) == 1 && info.VRs[0] == vr.SequenceOfItems {
+			return fmt.Errorf("Dimension Index Pointer %s (%s) is a sequence", dim.IndexPointer, info.Keyword)
+		}
+	}
+
+	if dim.FunctionalGroupPointer == (tag.Tag{}) {
+		return nil
+	}
+	if dim.FunctionalGroupPointer.IsPrivate() {
+		if dim.FunctionalGroupPrivateCreator == "" {
+			return fmt.Errorf("private Functional Group Pointer %s requires a private creator", dim.FunctionalGroupPointer)
+		}
+		return nil
+	}
+	info, err := tag.Find(dim.FunctionalGroupPointer)
+	if err != nil || len(info.VRs) != 1 || info.VRs[0] != vr.SequenceOfItems {
+		return fmt.Errorf("Functional Group Pointer %s is not a functional group sequence", dim.FunctionalGroupPointer)
+	}
+	return nil
+}
+
+// setDimensionPointer adds or replaces an AT element holding pointer.
+func setDimensionPointer(ds *dicom.DataSet, t tag.Tag, pointer tag.Tag) error {
+	elem, err := element.NewElement(t, vr.AttributeTag, value.NewTagValue([]tag.Tag{pointer}))
+	if err != nil {
+		return fmt.Errorf("failed to create element %s: %w", t, err)
+	}
+	return ds.Set(elem)
+}
@@ -0,0 +1,233 @@
+package pixel
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDimensionDataSet builds an enhanced multi-frame dataset whose frames carry the
+// given Dimension Index Values.
+func newDimensionDataSet(t *testing.T, numDimensions int, frames ...[]int64) *dicom.DataSet {
+	ds := dicom.NewDataSet()
+
+	nf, err := value.NewStringValue(vr.IntegerString, []string{strconv.Itoa(len(frames))})
+	require.NoError(t, err)
+	nfElem, err := element.NewElement(tag.NumberOfFrames, vr.IntegerString, nf)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(nfElem))
+
+	dims := make([]*dicom.DataSet, numDimensions)
+	for i := range dims {
+		dims[i] = dicom.NewDataSet()
+	}
+	addGSPSSequence(t, ds, tag.DimensionIndexSequence, dims...)
+
+	items := make([]*dicom.DataSet, len(frames))
+	for i, values := range frames {
+		val, err := value.NewIntValue(vr.UnsignedLong, values)
+		require.NoError(t, err)
+		elem, err := element.NewElement(tag.DimensionIndexValues, vr.UnsignedLong, val)
+		require.NoError(t, err)
+
+		content := dicom.NewDataSet()
+		require.NoError(t, content.Add(elem))
+
+		items[i] = dicom.NewDataSet()
+		addGSPSSequence(t, items[i], tag.FrameContentSequence, content)
+	}
+	addGSPSSequence(t, ds, tag.PerFrameFunctionalGroupsSequence, items...)
+
+	return ds
+}
+
+func TestDimensionIndices(t *testing.T) {
+	ds := newDimensionDataSet(t, 2, []int64{1, 2}, []int64{1, 1}, []int64{2, 1})
+
+	indices, err := DimensionIndices(ds)
+	require.NoError(t, err)
+	assert.Equal(t, [][]uint32{{1, 2}, {1, 1}, {2, 1}}, indices)
+}
+
+func TestDimensionIndices_Errors(t *testing.T) {
+	t.Run("nil dataset", func(t *testing.T) {
+		_, err := DimensionIndices(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing per-frame functional groups", func(t *testing.T) {
+		_, err := DimensionIndices(dicom.NewDataSet())
+		assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+	})
+
+	t.Run("frame count mismatch", func(t *testing.T) {
+		ds := newDimensionDataSet(t, 1, []int64{1}, []int64{2})
+		nf, err := value.NewStringValue(vr.IntegerString, []string{"3"})
+		require.NoError(t, err)
+		elem, err := element.NewElement(tag.NumberOfFrames, vr.IntegerString, nf)
+		require.NoError(t, err)
+		require.NoError(t, ds.Set(elem))
+
+		_, err = DimensionIndices(ds)
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong number of values", func(t *testing.T) {
+		ds := newDimensionDataSet(t, 2, []int64{1, 1}, []int64{2})
+		_, err := DimensionIndices(ds)
+		assert.Error(t, err)
+	})
+}
+
+func TestSortFramesByDimension(t *testing.T) {
+	// Dimensions: [stack position, temporal position]
+	ds := newDimensionDataSet(t, 2,
+		[]int64{2, 1},
+		[]int64{1, 2},
+		[]int64{1, 1},
+		[]int64{2, 2},
+	)
+
+	tests := []struct {
+		name     string
+		dimOrder []int
+		want     []int
+	}{
+		{"stack then temporal", []int{0, 1}, []int{2, 1, 0, 3}},
+		{"temporal then stack", []int{1, 0}, []int{2, 0, 1, 3}},
+		{"stack only keeps stored order", []int{0}, []int{1, 2, 0, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := SortFramesByDimension(ds, tt.dimOrder)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, order)
+		})
+	}
+}
+
+func TestSortFramesByDimension_Errors(t *testing.T) {
+	ds := newDimensionDataSet(t, 2, []int64{1, 1})
+
+	_, err := SortFramesByDimension(ds, nil)
+	assert.Error(t, err)
+
+	_, err = SortFramesByDimension(ds, []int{2})
+	assert.Error(t, err)
+
+	_, err = SortFramesByDimension(ds, []int{-1})
+	assert.Error(t, err)
+}
+
+func TestBuildDimensionOrganization(t *testing.T) {
+	organization, index, err := BuildDimensionOrganization([]DimensionSpec{
+		{IndexPointer: tag.StackID, FunctionalGroupPointer: tag.FrameContentSequence, Label: "Stack"},
+		{IndexPointer: tag.InStackPositionNumber, FunctionalGroupPointer: tag.FrameContentSequence},
+		{IndexPointer: tag.ImagePositionPatient, FunctionalGroupPointer: tag.PlanePositionSequence},
+	})
+	require.NoError(t, err)
+
+	orgItems, err := dicom.SequenceItems(&organization)
+	require.NoError(t, err)
+	require.Len(t, orgItems, 1)
+	orgUID, err := orgItems[0].Get(tag.DimensionOrganizationUID)
+	require.NoError(t, err)
+	require.NotEmpty(t, orgUID.Value().String())
+
+	dims, err := dicom.SequenceItems(&index)
+	require.NoError(t, err)
+	require.Len(t, dims, 3)
+	pointers := func(item *dicom.DataSet, tg tag.Tag) []tag.Tag {
+		elem, err := item.Get(tg)
+		require.NoError(t, err)
+		tags, err := elem.Value().(*value.IntValue).AsTags()
+		require.NoError(t, err)
+		return tags
+	}
+	for i, want := range []struct{ index, group tag.Tag }{
+		{tag.StackID, tag.FrameContentSequence},
+		{tag.InStackPositionNumber, tag.FrameContentSequence},
+		{tag.ImagePositionPatient, tag.PlanePositionSequence},
+	} {
+		uidElem, err := dims[i].Get(tag.DimensionOrganizationUID)
+		require.NoError(t, err)
+		assert.Equal(t, orgUID.Value().String(), uidElem.Value().String(), "dimension %d shares the organization UID", i+1)
+		assert.Equal(t, []tag.Tag{want.index}, pointers(dims[i], tag.DimensionIndexPointer))
+		assert.Equal(t, []tag.Tag{want.group}, pointers(dims[i], tag.FunctionalGroupPointer))
+	}
+	label, err := dims[0].Get(tag.DimensionDescriptionLabel)
+	require.NoError(t, err)
+	assert.Equal(t, "Stack", label.Value().String())
+	assert.False(t, dims[1].Contains(tag.DimensionDescriptionLabel))
+
+	// The index sequence describes frames carrying one index per dimension
+	ds := newDimensionDataSet(t, 0, []int64{1, 1, 1}, []int64{1, 2, 2})
+	indexElem, err := element.NewElement(tag.DimensionIndexSequence, vr.SequenceOfItems, &index)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(indexElem))
+	indices, err := DimensionIndices(ds)
+	require.NoError(t, err)
+	assert.Equal(t, [][]uint32{{1, 1, 1}, {1, 2, 2}}, indices)
+
+	// Each call starts a new organization
+	again, _, err := BuildDimensionOrganization([]DimensionSpec{{IndexPointer: tag.StackID, FunctionalGroupPointer: tag.FrameContentSequence}})
+	require.NoError(t, err)
+	againItems, err := dicom.SequenceItems(&again)
+	require.NoError(t, err)
+	againUID, err := againItems[0].Get(tag.DimensionOrganizationUID)
+	require.NoError(t, err)
+	assert.NotEqual(t, orgUID.Value().String(), againUID.Value().String())
+}
+
+func TestBuildDimensionOrganization_Private(t *testing.T) {
+	private := tag.New(0x0029, 0x1010)
+	_, index, err := BuildDimensionOrganization([]DimensionSpec{
+		{IndexPointer: private, IndexPrivateCreator: "ACME 1.0"},
+	})
+	require.NoError(t, err)
+	dims, err := dicom.SequenceItems(&index)
+	require.NoError(t, err)
+	creator, err := dims[0].Get(tag.DimensionIndexPrivateCreator)
+	require.NoError(t, err)
+	assert.Equal(t, "ACME 1.0", creator.Value().String())
+	assert.False(t, dims[0].Contains(tag.FunctionalGroupPointer), "top-level attributes have no functional group")
+}
+
+func TestBuildDimensionOrganization_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		dims []DimensionSpec
+	}{
+		{"no dimensions", nil},
+		{"zero index pointer", []DimensionSpec{{FunctionalGroupPointer: tag.FrameContentSequence}}},
+		{"duplicate dimension", []DimensionSpec{
+			{IndexPointer: tag.StackID, FunctionalGroupPointer: tag.FrameContentSequence},
+			{IndexPointer: tag.StackID, FunctionalGroupPointer: tag.FrameContentSequence},
+		}},
+		{"unknown standard tag", []DimensionSpec{{IndexPointer: tag.New(0x0020, 0x9FFE)}}},
+		{"sequence as index", []DimensionSpec{{IndexPointer: tag.FrameContentSequence}}},
+		{"functional group not a sequence", []DimensionSpec{
+			{IndexPointer: tag.StackID, FunctionalGroupPointer: tag.InStackPositionNumber},
+		}},
+		{"private index without creator", []DimensionSpec{{IndexPointer: tag.New(0x0029, 0x1010)}}},
+		{"private group without creator", []DimensionSpec{
+			{IndexPointer: tag.StackID, FunctionalGroupPointer: tag.New(0x0029, 0x1011)},
+		}},
+		{"label too long", []DimensionSpec{{IndexPointer: tag.StackID, Label: strings.Repeat("x", 65)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := BuildDimensionOrganization(tt.dims)
+			assert.Error(t, err)
+		})
+	}
+}
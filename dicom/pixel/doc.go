@@ -41,6 +41,9 @@
 //	rgbData := make([]byte, 512*512*3)
 //	pixelData, err := pixel.NewPixelDataFromRGB(rgbData, 512, 512)
 //
+//	// Deterministic synthetic test patterns (gradients, checkerboards, HU ramps)
+//	pixelData := pixel.NewSyntheticPixelData(pixel.SyntheticOptions{Pattern: pixel.PatternHounsfieldRamp})
+//
 // # Multi-Frame Support
 //
 // For multi-frame datasets, access individual frames:
@@ -52,6 +55,11 @@
 //	    // Process frame pixels...
 //	}
 //
+// FlattenFrame turns one frame of an enhanced multi-frame image into a classic
+// single-frame dataset, promoting the frame's functional group attributes (per-frame
+// over shared) to the top level alongside that frame's pixel data, so code written
+// for single-frame images can process each frame unchanged.
+//
 // # Image Transformations
 //
 // Convert between color spaces (photometric interpretations):
@@ -62,6 +70,9 @@
 //	// MONOCHROME1 → MONOCHROME2 (inversion)
 //	mono2, err := pixel.ConvertPhotometricInterpretation(mono1Data, "MONOCHROME2")
 //
+// The BT.601 colour transforms are also available directly as RGBToYBRFull,
+// YBRFullToRGB and their partial range variants RGBToYBRPartial and YBRPartialToRGB.
+//
 // Convert between planar configurations:
 //
 //	// Planar → Interleaved (RRR...GGG...BBB... → RGBRGBRGB...)
@@ -87,6 +98,36 @@
 //	// Convert to standard image format
 //	img := displayData.Image() // Returns image.Image
 //
+// For a display that has not been calibrated to the DICOM Grayscale Standard Display
+// Function (PS3.14), ApplyGSDF perceptually linearizes the display-ready values for
+// the display's measured luminance range:
+//
+//	calibrated, err := pixel.ApplyGSDF(displayData, 350, 0.5) // cd/m²
+//
+// Pixel Padding Value (0028,0120) marks pixels outside the field of view. PaddingMask
+// identifies them, Statistics and AutoWindow ignore them, and the pipeline can paint
+// them a fixed value:
+//
+//	wl, err := pixel.AutoWindow(ds, pixelData)
+//	displayData, err := pixel.ApplyFullImagePipeline(ds, pixelData, 8, pixel.WithPaddingBackground(0))
+//
+// # Volumes
+//
+// BuildVolume stacks the slices of a CT, MR or PET series into one 3D array of
+// rescaled voxels, sorted along the slice normal, for volumetric processing:
+//
+//	vol, err := pixel.BuildVolume(series)
+//	hu := vol.At(x, y, z)
+//
+// Gantry-tilted or unevenly spaced series are rejected unless WithResampling is
+// given, which interpolates them onto a regular grid.
+//
+// ToNIfTI adds the RAS+ affine used by neuroimaging tools and encodes the volume as
+// a NIfTI-1 file; NIfTIAffine computes the affine from the series geometry alone:
+//
+//	nv, err := pixel.ToNIfTI(series)
+//	data, err := nv.MarshalBinary()
+//
 // # Decoder Registry
 //
 // The package uses a pluggable decoder registry. Custom decoders can be registered for
@@ -94,6 +135,27 @@
 //
 //	pixel.RegisterDecoder("1.2.3.4.5.6.7", myCustomDecoder)
 //
+// Encoders are registered the same way and used by EncodeForTransferSyntax. RLE
+// Lossless and JPEG Baseline encoders are built in:
+//
+//	pixel.RegisterEncoder("1.2.840.10008.1.2.4.201", myHTJ2KEncoder)
+//	encapsulated, err := pixel.EncodeForTransferSyntax(pd, "1.2.840.10008.1.2.4.201")
+//
+// Lossily compressed images must say so. EncodeJPEGBaselineDataSet flags the
+// dataset itself; after storing the output of any other lossy encoder, call
+// RecordLossyCompression with the ratio achieved. LossyCompressionInfo reads the
+// flag and the history of ratios and methods back.
+//
+// # Buffer Pools
+//
+// Batch pipelines that decode many same-sized images can recycle the decoded buffers
+// with a BufferPool to reduce garbage collection:
+//
+//	pool := pixel.NewBufferPool(512 * 512 * 2)
+//	pd, err := pixel.Extract(ds, pixel.WithBufferPool(pool))
+//	// ... use pd ...
+//	pool.Put(pd.RawBytes())
+//
 // # CGo Dependencies
 //
 // Some decoders require external C libraries:
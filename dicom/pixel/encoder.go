@@ -0,0 +1,109 @@
+package pixel
+
+import (
+	"sync"
+)
+
+// Encoder defines the interface for compressing pixel data into a specific transfer syntax.
+//
+// It is the counterpart of Decoder. Implementations must be safe for concurrent use.
+type Encoder interface {
+	// Encode compresses native pixel data.
+	//
+	// Returns one compressed fragment per frame, ready to be encapsulated with
+	// EncapsulateFrames.
+	Encode(pd *PixelData) ([][]byte, error)
+
+	// TransferSyntaxUID returns the transfer syntax UID this encoder produces.
+	TransferSyntaxUID() string
+}
+
+// encoderRegistry manages registered pixel data encoders.
+var (
+	encoderRegistry   = make(map[string]Encoder)
+	encoderRegistryMu sync.RWMutex
+)
+
+// RegisterEncoder registers an encoder for a specific transfer syntax UID.
+//
+// If an encoder is already registered for the UID, it will be replaced.
+// This function is safe for concurrent use.
+//
+// Example:
+//
+//	pixel.RegisterEncoder("1.2.840.10008.1.2.4.201", htj2kEncoder)
+func RegisterEncoder(transferSyntaxUID string, encoder Encoder) {
+	encoderRegistryMu.Lock()
+	defer encoderRegistryMu.Unlock()
+	encoderRegistry[transferSyntaxUID] = encoder
+}
+
+// GetEncoder retrieves the encoder for a specific transfer syntax UID.
+//
+// Returns an error if no encoder is registered for the UID.
+// This function is safe for concurrent use.
+func GetEncoder(transferSyntaxUID string) (Encoder, error) {
+	encoderRegistryMu.RLock()
+	defer encoderRegistryMu.RUnlock()
+
+	encoder, ok := encoderRegistry[transferSyntaxUID]
+	if !ok {
+		return nil, &TransferSyntaxError{UID: transferSyntaxUID}
+	}
+	return encoder, nil
+}
+
+// UnregisterEncoder removes an encoder for a specific transfer syntax UID.
+//
+// This is primarily useful for testing. Most applications should not need to unregister encoders.
+// This function is safe for concurrent use.
+func UnregisterEncoder(transferSyntaxUID string) {
+	encoderRegistryMu.Lock()
+	defer encoderRegistryMu.Unlock()
+	delete(encoderRegistry, transferSyntaxUID)
+}
+
+// ListEncoders returns a list of all registered transfer syntax UIDs.
+//
+// This function is safe for concurrent use.
+func ListEncoders() []string {
+	encoderRegistryMu.RLock()
+	defer encoderRegistryMu.RUnlock()
+
+	uids := make([]string, 0, len(encoderRegistry))
+	for uid := range encoderRegistry {
+		uids = append(uids, uid)
+	}
+	return uids
+}
+
+// EncodeForTransferSyntax compresses pd with the encoder registered for tsUID and
+// returns the encapsulated Pixel Data (7FE0,0010) value: a Basic Offset Table,
+// one fragment per frame and a Sequence Delimitation Item.
+//
+// Built-in encoders are registered for RLE Lossless (1.2.840.10008.1.2.5) and JPEG
+// Baseline (1.2.840.10008.1.2.4.50, quality DefaultJPEGQuality). Returns an error
+// wrapping ErrUnsupportedTransferSyntax if no encoder is registered.
+//
+// Example:
+//
+//	encapsulated, err := pixel.EncodeForTransferSyntax(pd, uid.RLELossless.String())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_A.4
+func EncodeForTransferSyntax(pd *PixelData, tsUID string) ([]byte, error) {
+	encoder, err := GetEncoder(tsUID)
+	if err != nil {
+		return nil, err
+	}
+
+	fragments, err := encoder.Encode(pd)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncapsulateFrames(fragments), nil
+}
@@ -0,0 +1,97 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubEncoder returns a fixed fragment per frame.
+type stubEncoder struct{}
+
+func (e *stubEncoder) Encode(pd *PixelData) ([][]byte, error) {
+	return [][]byte{{0x01, 0x02}}, nil
+}
+
+func (e *stubEncoder) TransferSyntaxUID() string { return "1.2.3.4.5" }
+
+func TestEncoderRegistry(t *testing.T) {
+	const tsUID = "1.2.3.4.5"
+
+	_, err := GetEncoder(tsUID)
+	assert.ErrorIs(t, err, ErrUnsupportedTransferSyntax)
+
+	RegisterEncoder(tsUID, &stubEncoder{})
+	defer UnregisterEncoder(tsUID)
+
+	enc, err := GetEncoder(tsUID)
+	require.NoError(t, err)
+	assert.Equal(t, tsUID, enc.TransferSyntaxUID())
+	assert.Contains(t, ListEncoders(), tsUID)
+
+	pd := NewSyntheticPixelData(SyntheticOptions{Rows: 2, Columns: 2, BitsAllocated: 8})
+	encapsulated, err := EncodeForTransferSyntax(pd, tsUID)
+	require.NoError(t, err)
+	assert.Equal(t, EncapsulateFrames([][]byte{{0x01, 0x02}}), encapsulated)
+}
+
+func TestEncoderRegistry_BuiltIn(t *testing.T) {
+	encoders := ListEncoders()
+	assert.Contains(t, encoders, uid.RLELossless.String())
+	assert.Contains(t, encoders, uid.JPEGBaselineProcess1.String())
+}
+
+func TestEncodeForTransferSyntax_Unsupported(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{Rows: 2, Columns: 2})
+	_, err := EncodeForTransferSyntax(pd, "1.2.3.999")
+	assert.ErrorIs(t, err, ErrUnsupportedTransferSyntax)
+}
+
+func TestEncodeForTransferSyntax_RoundTrip(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:        PatternGradient,
+		Rows:           16,
+		Columns:        16,
+		NumberOfFrames: 2,
+		BitsAllocated:  8,
+	})
+
+	tests := []struct {
+		name    string
+		tsUID   string
+		maxDiff float64
+	}{
+		{"RLE Lossless", uid.RLELossless.String(), 0},
+		{"JPEG Baseline", uid.JPEGBaselineProcess1.String(), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encapsulated, err := EncodeForTransferSyntax(pd, tt.tsUID)
+			require.NoError(t, err)
+
+			parsed, err := ParseEncapsulatedPixelData(encapsulated)
+			require.NoError(t, err)
+			require.Len(t, parsed.Fragments, 2)
+
+			decoder, err := GetDecoder(tt.tsUID)
+			require.NoError(t, err)
+
+			frameSize := 16 * 16
+			for i, fragment := range parsed.Fragments {
+				decoded, err := decoder.Decode(fragment.Data, &PixelInfo{
+					Rows:            16,
+					Columns:         16,
+					BitsAllocated:   8,
+					SamplesPerPixel: 1,
+					NumberOfFrames:  1,
+				})
+				require.NoError(t, err)
+				want := pd.RawBytes()[i*frameSize : (i+1)*frameSize]
+				assert.LessOrEqual(t, meanAbsDiff(want, decoded), tt.maxDiff, "frame %d", i)
+			}
+		})
+	}
+}
@@ -20,6 +20,14 @@ var (
 
 	// ErrDecompressionFailed indicates that pixel data decompression failed.
 	ErrDecompressionFailed = errors.New("decompression failed")
+
+	// ErrGantryTilt indicates that the slices of a series are not stacked along their
+	// normal, as in a CT acquired with a tilted gantry.
+	ErrGantryTilt = errors.New("slices are offset in plane (gantry tilt)")
+
+	// ErrIrregularSliceSpacing indicates that the gaps between the slices of a series
+	// are not uniform.
+	ErrIrregularSliceSpacing = errors.New("irregular slice spacing")
 )
 
 // TransferSyntaxError wraps ErrUnsupportedTransferSyntax with the specific UID.
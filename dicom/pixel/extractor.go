@@ -35,78 +35,22 @@ import (
 // Optional DICOM attributes:
 //   - (0028,0006) PlanarConfiguration (defaults to 0)
 //   - (0028,0008) NumberOfFrames (defaults to 1)
-func Extract(ds *dicom.DataSet) (*PixelData, error) {
-	// Extract required metadata
-	rows, err := getUint16(ds, tag.Rows, "Rows")
-	if err != nil {
-		return nil, err
-	}
-
-	columns, err := getUint16(ds, tag.Columns, "Columns")
-	if err != nil {
-		return nil, err
-	}
-
-	bitsAllocated, err := getUint16(ds, tag.BitsAllocated, "BitsAllocated")
-	if err != nil {
-		return nil, err
-	}
-
-	bitsStored, err := getUint16(ds, tag.BitsStored, "BitsStored")
-	if err != nil {
-		return nil, err
-	}
-
-	highBit, err := getUint16(ds, tag.HighBit, "HighBit")
-	if err != nil {
-		return nil, err
-	}
-
-	pixelRepresentation, err := getUint16(ds, tag.PixelRepresentation, "PixelRepresentation")
-	if err != nil {
-		return nil, err
-	}
-
-	samplesPerPixel, err := getUint16(ds, tag.SamplesPerPixel, "SamplesPerPixel")
-	if err != nil {
-		return nil, err
-	}
-
-	photometricInterpretation, err := getString(ds, tag.PhotometricInterpretation, "PhotometricInterpretation")
-	if err != nil {
-		return nil, err
-	}
-
-	// Extract optional metadata with defaults
-	planarConfiguration := getUint16WithDefault(ds, tag.PlanarConfiguration, 0)
-	numberOfFrames := getIntWithDefault(ds, tag.NumberOfFrames, 1)
+//
+// 16-bit pixel data in Explicit VR Big Endian datasets is byte-swapped so that the
+// result always has the little-endian layout expected by Array and the other helpers.
+//
+// Options such as WithBufferPool control how the decoded data is allocated, and
+// WithCache reuses previously decoded frames.
+func Extract(ds *dicom.DataSet, opts ...ExtractOption) (*PixelData, error) {
+	options := applyExtractOptions(opts)
 
-	// Get transfer syntax UID
-	transferSyntaxUID, err := getString(ds, tag.TransferSyntaxUID, "TransferSyntaxUID")
+	info, encapsulatedData, err := readPixelInfo(ds)
 	if err != nil {
 		return nil, err
 	}
-
-	// Get raw pixel data
-	pixelDataElem, err := ds.Get(tag.PixelData)
-	if err != nil {
-		return nil, &MissingAttributeError{
-			AttributeName: "PixelData",
-			Tag:           tag.PixelData.String(),
-		}
-	}
-
-	pixelDataValue := pixelDataElem.Value()
-	bytesVal, ok := pixelDataValue.(*value.BytesValue)
-	if !ok {
-		return nil, &PixelDataError{
-			Field:    "PixelData value type",
-			Expected: "*value.BytesValue",
-			Actual:   fmt.Sprintf("%T", pixelDataValue),
-		}
-	}
-
-	encapsulatedData := bytesVal.Bytes()
+	numberOfFrames := info.NumberOfFrames
+	transferSyntaxUID := info.TransferSyntaxUID
+	bitsAllocated := info.BitsAllocated
 
 	// Get decoder for transfer syntax
 	decoder, err := GetDecoder(transferSyntaxUID)
@@ -114,21 +58,6 @@ func Extract(ds *dicom.DataSet) (*PixelData, error) {
 		return nil, err
 	}
 
-	// Create PixelInfo for decoder
-	info := &PixelInfo{
-		Rows:                      rows,
-		Columns:                   columns,
-		BitsAllocated:             bitsAllocated,
-		BitsStored:                bitsStored,
-		HighBit:                   highBit,
-		PixelRepresentation:       pixelRepresentation,
-		SamplesPerPixel:           samplesPerPixel,
-		PhotometricInterpretation: photometricInterpretation,
-		PlanarConfiguration:       planarConfiguration,
-		NumberOfFrames:            numberOfFrames,
-		TransferSyntaxUID:         transferSyntaxUID,
-	}
-
 	var decompressedData []byte
 
 	// Check if transfer syntax uses encapsulation
@@ -157,7 +86,7 @@ func Extract(ds *dicom.DataSet) (*PixelData, error) {
 
 		// Decompress each frame
 		frameSize := CalculateExpectedSize(info) / numberOfFrames
-		decompressedData = make([]byte, 0, CalculateExpectedSize(info))
+		decompressedData = options.buffer(CalculateExpectedSize(info))[:0]
 
 		for frameIndex := 0; frameIndex < numberOfFrames; frameIndex++ {
 			// Get fragments for this frame
@@ -177,7 +106,7 @@ func Extract(ds *dicom.DataSet) (*PixelData, error) {
 			frameInfo := *info // Copy info
 			frameInfo.NumberOfFrames = 1
 
-			decompressedFrame, err := decoder.Decode(compressedFrame, &frameInfo)
+			decompressedFrame, err := decodeFrame(decoder, compressedFrame, &frameInfo, options.cache)
 			if err != nil {
 				return nil, &PixelDataError{
 					Field:    fmt.Sprintf("frame %d decompression", frameIndex),
@@ -210,8 +139,114 @@ func Extract(ds *dicom.DataSet) (*PixelData, error) {
 		return nil, err
 	}
 
+	// Big endian 16-bit samples are swapped to the little-endian layout used by
+	// PixelData. Native data aliases the dataset, so swap a copy; pooled callers
+	// also need a buffer they own.
+	swap := transferSyntaxUID == explicitVRBigEndianUID && bitsAllocated == 16
+	if swap || (options.pool != nil && !isEncapsulated(transferSyntaxUID)) {
+		buf := options.buffer(len(decompressedData))
+		copy(buf, decompressedData)
+		decompressedData = buf
+	}
+	if swap {
+		SwapBytes16(decompressedData)
+	}
+
 	// Return PixelData struct
 	return &PixelData{
+		Rows:                      info.Rows,
+		Columns:                   info.Columns,
+		BitsAllocated:             info.BitsAllocated,
+		BitsStored:                info.BitsStored,
+		HighBit:                   info.HighBit,
+		PixelRepresentation:       info.PixelRepresentation,
+		SamplesPerPixel:           info.SamplesPerPixel,
+		PhotometricInterpretation: info.PhotometricInterpretation,
+		PlanarConfiguration:       info.PlanarConfiguration,
+		NumberOfFrames:            numberOfFrames,
+		data:                      decompressedData,
+		TransferSyntaxUID:         transferSyntaxUID,
+	}, nil
+}
+
+// readPixelInfo reads the Image Pixel attributes and transfer syntax of ds, returning
+// them with the raw (native or encapsulated) PixelData bytes.
+func readPixelInfo(ds *dicom.DataSet) (*PixelInfo, []byte, error) {
+	// Extract required metadata
+	rows, err := getUint16(ds, tag.Rows, "Rows")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns, err := getUint16(ds, tag.Columns, "Columns")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bitsAllocated, err := getUint16(ds, tag.BitsAllocated, "BitsAllocated")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bitsStored, err := getUint16(ds, tag.BitsStored, "BitsStored")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	highBit, err := getUint16(ds, tag.HighBit, "HighBit")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pixelRepresentation, err := getUint16(ds, tag.PixelRepresentation, "PixelRepresentation")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	samplesPerPixel, err := getUint16(ds, tag.SamplesPerPixel, "SamplesPerPixel")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	photometricInterpretation, err := getString(ds, tag.PhotometricInterpretation, "PhotometricInterpretation")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Extract optional metadata with defaults
+	planarConfiguration := getUint16WithDefault(ds, tag.PlanarConfiguration, 0)
+	numberOfFrames := getIntWithDefault(ds, tag.NumberOfFrames, 1)
+
+	// Get transfer syntax UID
+	transferSyntaxUID, err := getString(ds, tag.TransferSyntaxUID, "TransferSyntaxUID")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get raw pixel data
+	pixelDataElem, err := ds.Get(tag.PixelData)
+	if err != nil {
+		return nil, nil, &MissingAttributeError{
+			AttributeName: "PixelData",
+			Tag:           tag.PixelData.String(),
+		}
+	}
+
+	// Pixel Data read on demand is reloaded from its file, which may have changed
+	if err := pixelDataElem.Load(); err != nil {
+		return nil, nil, fmt.Errorf("failed to load PixelData: %w", err)
+	}
+	pixelDataValue := pixelDataElem.Value()
+	bytesVal, ok := pixelDataValue.(*value.BytesValue)
+	if !ok {
+		return nil, nil, &PixelDataError{
+			Field:    "PixelData value type",
+			Expected: "*value.BytesValue",
+			Actual:   fmt.Sprintf("%T", pixelDataValue),
+		}
+	}
+
+	return &PixelInfo{
 		Rows:                      rows,
 		Columns:                   columns,
 		BitsAllocated:             bitsAllocated,
@@ -222,9 +257,28 @@ func Extract(ds *dicom.DataSet) (*PixelData, error) {
 		PhotometricInterpretation: photometricInterpretation,
 		PlanarConfiguration:       planarConfiguration,
 		NumberOfFrames:            numberOfFrames,
-		data:                      decompressedData,
 		TransferSyntaxUID:         transferSyntaxUID,
-	}, nil
+	}, bytesVal.Bytes(), nil
+}
+
+// decodeFrame decompresses one frame, consulting cache first when one is configured.
+// Cached frames are shared, so the result must be copied rather than modified.
+func decodeFrame(decoder Decoder, compressed []byte, info *PixelInfo, cache *DecodeCache) ([]byte, error) {
+	if cache == nil {
+		return decoder.Decode(compressed, info)
+	}
+
+	key := newDecodeCacheKey(compressed, info)
+	if frame, ok := cache.get(key); ok {
+		return frame, nil
+	}
+
+	frame, err := decoder.Decode(compressed, info)
+	if err != nil {
+		return nil, err
+	}
+	cache.add(key, frame)
+	return frame, nil
 }
 
 // getUint16 extracts a uint16 value from a DICOM element.
@@ -356,6 +410,32 @@ func getString(ds *dicom.DataSet, t tag.Tag, name string) (string, error) {
 	return strs[0], nil
 }
 
+// explicitVRBigEndianUID is the (retired) Explicit VR Big Endian transfer syntax, the
+// only native transfer syntax that stores multi-byte pixel samples big endian.
+const explicitVRBigEndianUID = "1.2.840.10008.1.2.2"
+
+// explicitVRLittleEndianUID is the Explicit VR Little Endian transfer syntax, assumed
+// for native pixel data found in an otherwise encapsulated dataset, such as an icon.
+const explicitVRLittleEndianUID = "1.2.840.10008.1.2.1"
+
+// SwapBytes16 reverses the byte order of every 16-bit word in data, in place.
+//
+// It converts OW pixel data between big endian and little endian layouts. A trailing
+// odd byte is left unchanged.
+//
+// Example:
+//
+//	raw := []byte{0x01, 0x02, 0x03, 0x04}
+//	pixel.SwapBytes16(raw) // raw is now {0x02, 0x01, 0x04, 0x03}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.3
+func SwapBytes16(data []byte) {
+	for i := 0; i+1 < len(data); i += 2 {
+		data[i], data[i+1] = data[i+1], data[i]
+	}
+}
+
 // isEncapsulated returns true if the transfer syntax uses encapsulated pixel data format.
 //
 // Encapsulated format is used by all compressed transfer syntaxes:
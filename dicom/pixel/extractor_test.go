@@ -0,0 +1,101 @@
+package pixel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwapBytes16(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+		want []byte
+	}{
+		{"empty", []byte{}, []byte{}},
+		{"words", []byte{0x01, 0x02, 0x03, 0x04}, []byte{0x02, 0x01, 0x04, 0x03}},
+		{"odd trailing byte", []byte{0x01, 0x02, 0x03}, []byte{0x02, 0x01, 0x03}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SwapBytes16(tt.in)
+			assert.Equal(t, tt.want, tt.in)
+		})
+	}
+}
+
+func TestExtract_BigEndian(t *testing.T) {
+	reference, err := NewPixelDataFromUint16([]uint16{0, 1, 0x0102, 0xABCD, 4095, 0xFF00}, 3, 2)
+	require.NoError(t, err)
+
+	bigEndian := make([]byte, len(reference.RawBytes()))
+	copy(bigEndian, reference.RawBytes())
+	SwapBytes16(bigEndian)
+
+	ds := newExtractDataSet(t, reference, explicitVRBigEndianUID)
+	val, err := value.NewBytesValue(vr.OtherWord, bigEndian)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tag.PixelData, vr.OtherWord, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+
+	for _, opts := range [][]ExtractOption{nil, {WithBufferPool(NewBufferPool(len(bigEndian)))}} {
+		pd, err := Extract(ds, opts...)
+		require.NoError(t, err)
+		assert.Equal(t, reference.Array(), pd.Array())
+	}
+
+	// The dataset keeps its big endian bytes
+	elem, err = ds.Get(tag.PixelData)
+	require.NoError(t, err)
+	assert.Equal(t, bigEndian, elem.Value().Bytes())
+
+	t.Run("8-bit data is not swapped", func(t *testing.T) {
+		pd8, err := NewPixelDataFromUint8([]uint8{1, 2, 3, 4}, 2, 2)
+		require.NoError(t, err)
+		got, err := Extract(newExtractDataSet(t, pd8, explicitVRBigEndianUID))
+		require.NoError(t, err)
+		assert.Equal(t, pd8.Array(), got.Array())
+	})
+}
+
+// TestExtract_LazyPixelDataMissingFile tests that Extract fails, rather than
+// decoding empty pixel data, when evicted Pixel Data cannot be reread from its file.
+func TestExtract_LazyPixelDataMissingFile(t *testing.T) {
+	ds, err := dicom.NewBuilder(uid.SecondaryCaptureImageStorage).
+		PatientID("12345").ConversionType("WSD").
+		Rows(64).Columns(64).
+		SamplesPerPixel(1).PhotometricInterpretation("MONOCHROME2").
+		BitsAllocated(8).BitsStored(8).HighBit(7).PixelRepresentation(0).
+		PixelData(make([]byte, 64*64)).
+		Build()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "image.dcm")
+	require.NoError(t, dicom.WriteFile(path, ds))
+
+	result, err := dicom.ParseDirectoryWithOptions(dir, dicom.ParseDirectoryOptions{ValueCacheBytes: 10000})
+	require.NoError(t, err)
+	parsed := result.Collection.DataSets()[0]
+	_, err = Extract(parsed)
+	require.NoError(t, err)
+
+	elem, err := parsed.Get(tag.PixelData)
+	require.NoError(t, err)
+	elem.Unload()
+	require.NoError(t, os.Remove(path))
+
+	_, err = Extract(parsed)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
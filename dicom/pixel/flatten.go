@@ -0,0 +1,195 @@
+package pixel
+
+import (
+	"fmt"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// wholeFunctionalGroups are the functional group macros whose sequence is itself
+// the attribute, with one item per referenced image or mapping, as in a single-frame
+// image. FlattenFrame promotes them as they are instead of unwrapping their first
+// item.
+var wholeFunctionalGroups = map[tag.Tag]bool{
+	tag.ReferencedImageSequence:       true,
+	tag.DerivationImageSequence:       true,
+	tag.RealWorldValueMappingSequence: true,
+}
+
+// FlattenFrame returns a single-frame view of frame frameIndex (0-based) of an
+// enhanced multi-frame image, in the classic layout that single-frame code expects.
+//
+// The result holds the top-level attributes of ds with the functional group
+// attributes of the frame promoted to the top level, and only that frame's pixel
+// data:
+//   - For each macro in the Shared Functional Groups Sequence (5200,9229) item and
+//     then the frame's Per-Frame Functional Groups Sequence (5200,9230) item, the
+//     attributes of the macro's first item are set at the top level. So Image
+//     Position (Patient) comes from the Plane Position Sequence, Pixel Spacing and
+//     Slice Thickness from the Pixel Measures Sequence, Rescale Slope and Intercept
+//     from the Pixel Value Transformation Sequence and Window Center and Width from
+//     the Frame VOI LUT Sequence, for example.
+//   - Referenced Image, Derivation Image and Real World Value Mapping Sequences are
+//     macros that are attributes in their own right, and are promoted whole.
+//   - Precedence is per-frame over shared over top level: a per-frame value replaces
+//     a shared value of the same attribute, which replaces any top-level value.
+//   - The two functional groups sequences and Number of Frames (0028,0008) are
+//     removed, and Pixel Data (7FE0,0010) holds the frame alone: its native bytes,
+//     or for encapsulated transfer syntaxes its fragments re-encapsulated as a
+//     single frame.
+//
+// Elements are shared with ds, as with DataSet.Copy, so ds must not be modified
+// while the result is in use.
+//
+// Returns an error if:
+//   - ds is nil or has no Per-Frame Functional Groups Sequence
+//   - frameIndex is out of range
+//   - the frame's pixel data cannot be located, or native frames do not fill
+//     whole bytes (as with some 1-bit images)
+//
+// Example:
+//
+//	for i := 0; i < numberOfFrames; i++ {
+//	    frame, err := pixel.FlattenFrame(ds, i)
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    processSingleFrame(frame)  // sees ImagePositionPatient, RescaleSlope, ...
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.16
+func FlattenFrame(ds *dicom.DataSet, frameIndex int) (*dicom.DataSet, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+	perFrame, err := ds.GetSequenceItems(tag.PerFrameFunctionalGroupsSequence)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Per-Frame Functional Groups Sequence: %v", ErrMissingRequiredAttribute, err)
+	}
+	numberOfFrames := getIntWithDefault(ds, tag.NumberOfFrames, len(perFrame))
+	if frameIndex < 0 || frameIndex >= len(perFrame) || frameIndex >= numberOfFrames {
+		return nil, fmt.Errorf("frame index %d out of range, image has %d frames", frameIndex, min(numberOfFrames, len(perFrame)))
+	}
+
+	result := ds.Copy()
+	for _, t := range []tag.Tag{tag.SharedFunctionalGroupsSequence, tag.PerFrameFunctionalGroupsSequence, tag.NumberOfFrames} {
+		_ = result.Remove(t)
+	}
+
+	groups := []*dicom.DataSet{perFrame[frameIndex]}
+	if shared, err := ds.GetSequenceItems(tag.SharedFunctionalGroupsSequence); err == nil && len(shared) > 0 {
+		groups = []*dicom.DataSet{shared[0], perFrame[frameIndex]}
+	}
+	for _, group := range groups {
+		if err := promoteFunctionalGroups(result, group); err != nil {
+			return nil, fmt.Errorf("frame %d: %w", frameIndex, err)
+		}
+	}
+
+	if ds.Contains(tag.PixelData) {
+		pixelElem, err := flattenFramePixelData(ds, frameIndex, numberOfFrames)
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %w", frameIndex, err)
+		}
+		if err := result.Set(pixelElem); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// promoteFunctionalGroups sets the attributes of the macros in one functional
+// groups item at the top level of result.
+func promoteFunctionalGroups(result, group *dicom.DataSet) error {
+	for _, macro := range group.Elements() {
+		if macro.VR() != vr.SequenceOfItems {
+			continue
+		}
+		if wholeFunctionalGroups[macro.Tag()] {
+			if err := result.Set(macro); err != nil {
+				return err
+			}
+			continue
+		}
+
+		seq, ok := macro.Value().(*value.SequenceValue)
+		if !ok {
+			continue
+		}
+		items, err := dicom.SequenceItems(seq)
+		if err != nil {
+			return fmt.Errorf("%s: %w", attributeKeyword(macro.Tag()), err)
+		}
+		if len(items) == 0 {
+			continue
+		}
+		for _, elem := range items[0].Elements() {
+			if err := result.Set(elem); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// flattenFramePixelData returns a Pixel Data element holding frame frameIndex of ds.
+func flattenFramePixelData(ds *dicom.DataSet, frameIndex, numberOfFrames int) (*element.Element, error) {
+	pixelElem, err := ds.Get(tag.PixelData)
+	if err != nil {
+		return nil, err
+	}
+	data := pixelElem.Value().Bytes()
+
+	var frame []byte
+	if tsUID, err := getString(ds, tag.TransferSyntaxUID, "TransferSyntaxUID"); err == nil && isEncapsulated(tsUID) {
+		encapsulated, err := ParseEncapsulatedPixelData(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPixelData, err)
+		}
+		fragments, err := encapsulated.GetFrameFragments(frameIndex)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPixelData, err)
+		}
+		frame = EncapsulateFrames([][]byte{ConcatenateFragments(fragments)})
+	} else {
+		var dims [4]uint16
+		for i, attr := range []struct {
+			t    tag.Tag
+			name string
+		}{
+			{tag.Rows, "Rows"},
+			{tag.Columns, "Columns"},
+			{tag.BitsAllocated, "BitsAllocated"},
+			{tag.SamplesPerPixel, "SamplesPerPixel"},
+		} {
+			if dims[i], err = getUint16(ds, attr.t, attr.name); err != nil {
+				return nil, err
+			}
+		}
+		frameBits := int(dims[0]) * int(dims[1]) * int(dims[2]) * int(dims[3])
+		if frameBits%8 != 0 {
+			return nil, fmt.Errorf("%w: native frames of %d bits do not start on byte boundaries", ErrInvalidPixelData, frameBits)
+		}
+		frameSize := frameBits / 8
+		if len(data) < frameSize*numberOfFrames {
+			return nil, &PixelDataError{
+				Field:    "pixel data length",
+				Expected: frameSize * numberOfFrames,
+				Actual:   len(data),
+			}
+		}
+		frame = data[frameIndex*frameSize : (frameIndex+1)*frameSize]
+	}
+
+	val, err := value.NewBytesValue(pixelElem.VR(), frame)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pixel data value: %w", err)
+	}
+	return element.NewElement(tag.PixelData, pixelElem.VR(), val)
+}
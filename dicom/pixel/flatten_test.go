@@ -0,0 +1,126 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlattenDataSet returns a two-frame 2x2 8-bit enhanced image whose frames are
+// filled with 10 and 20. Rescale is shared but overridden for the second frame.
+func newFlattenDataSet(t *testing.T, tsUID string) *dicom.DataSet {
+	t.Helper()
+
+	pd, err := NewPixelDataFromUint8([]uint8{10, 10, 10, 10, 20, 20, 20, 20}, 2, 4)
+	require.NoError(t, err)
+	pd.Rows, pd.NumberOfFrames = 2, 2
+	ds := newExtractDataSet(t, pd, tsUID)
+	addGSPSString(t, ds, tag.RescaleSlope, vr.DecimalString, "9")
+
+	macro := func(seq tag.Tag, attrs map[tag.Tag][]string) *dicom.DataSet {
+		item := dicom.NewDataSet()
+		for attr, values := range attrs {
+			addGSPSString(t, item, attr, vr.DecimalString, values...)
+		}
+		group := dicom.NewDataSet()
+		addGSPSSequence(t, group, seq, item)
+		return group
+	}
+
+	shared := macro(tag.PixelValueTransformationSequence, map[tag.Tag][]string{
+		tag.RescaleSlope:     {"1"},
+		tag.RescaleIntercept: {"-1024"},
+	})
+	planeOrientation := macro(tag.PlaneOrientationSequence, map[tag.Tag][]string{
+		tag.ImageOrientationPatient: {"1", "0", "0", "0", "1", "0"},
+	})
+	require.NoError(t, shared.Merge(planeOrientation))
+
+	first := macro(tag.PlanePositionSequence, map[tag.Tag][]string{tag.ImagePositionPatient: {"0", "0", "0"}})
+	ref := dicom.NewDataSet()
+	addGSPSString(t, ref, tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier, "1.2.3.4")
+	addGSPSSequence(t, first, tag.ReferencedImageSequence, ref)
+
+	second := macro(tag.PlanePositionSequence, map[tag.Tag][]string{tag.ImagePositionPatient: {"0", "0", "2.5"}})
+	require.NoError(t, second.Merge(macro(tag.PixelValueTransformationSequence, map[tag.Tag][]string{
+		tag.RescaleSlope:     {"2"},
+		tag.RescaleIntercept: {"0"},
+	})))
+
+	addGSPSSequence(t, ds, tag.SharedFunctionalGroupsSequence, shared)
+	addGSPSSequence(t, ds, tag.PerFrameFunctionalGroupsSequence, first, second)
+	return ds
+}
+
+func TestFlattenFrame(t *testing.T) {
+	ds := newFlattenDataSet(t, "1.2.840.10008.1.2.1")
+
+	frame, err := FlattenFrame(ds, 0)
+	require.NoError(t, err)
+	floats := func(ds *dicom.DataSet, tg tag.Tag) []float64 {
+		values, err := ds.GetFloats(tg)
+		require.NoError(t, err, "%s", tg)
+		return values
+	}
+	assert.Equal(t, []float64{0, 0, 0}, floats(frame, tag.ImagePositionPatient))
+	assert.Equal(t, []float64{1, 0, 0, 0, 1, 0}, floats(frame, tag.ImageOrientationPatient))
+	assert.Equal(t, []float64{1}, floats(frame, tag.RescaleSlope), "shared overrides top level")
+	assert.Equal(t, []float64{-1024}, floats(frame, tag.RescaleIntercept))
+	for _, tg := range []tag.Tag{tag.SharedFunctionalGroupsSequence, tag.PerFrameFunctionalGroupsSequence, tag.NumberOfFrames} {
+		assert.False(t, frame.Contains(tg), "%s", tg)
+	}
+
+	refs, err := frame.GetSequenceItems(tag.ReferencedImageSequence)
+	require.NoError(t, err, "Referenced Image Sequence is promoted whole")
+	require.Len(t, refs, 1)
+	assert.True(t, refs[0].Contains(tag.ReferencedSOPInstanceUID))
+
+	pd, err := Extract(frame)
+	require.NoError(t, err)
+	assert.Equal(t, 1, pd.NumberOfFrames)
+	assert.Equal(t, []byte{10, 10, 10, 10}, pd.RawBytes())
+
+	frame, err = FlattenFrame(ds, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 0, 2.5}, floats(frame, tag.ImagePositionPatient))
+	assert.Equal(t, []float64{2}, floats(frame, tag.RescaleSlope), "per-frame overrides shared")
+	assert.Equal(t, []float64{0}, floats(frame, tag.RescaleIntercept))
+	assert.False(t, frame.Contains(tag.ReferencedImageSequence))
+	pd, err = Extract(frame)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{20, 20, 20, 20}, pd.RawBytes())
+
+	// The source is unchanged
+	assert.Equal(t, []float64{9}, floats(ds, tag.RescaleSlope))
+	assert.True(t, ds.Contains(tag.PerFrameFunctionalGroupsSequence))
+}
+
+func TestFlattenFrame_Encapsulated(t *testing.T) {
+	ds := newFlattenDataSet(t, "1.2.840.10008.1.2.5") // RLE Lossless
+
+	for i, want := range []byte{10, 20} {
+		frame, err := FlattenFrame(ds, i)
+		require.NoError(t, err)
+		pd, err := Extract(frame)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{want, want, want, want}, pd.RawBytes())
+	}
+}
+
+func TestFlattenFrame_Errors(t *testing.T) {
+	_, err := FlattenFrame(nil, 0)
+	assert.Error(t, err)
+
+	_, err = FlattenFrame(dicom.NewDataSet(), 0)
+	assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+
+	ds := newFlattenDataSet(t, "1.2.840.10008.1.2.1")
+	for _, index := range []int{-1, 2} {
+		_, err = FlattenFrame(ds, index)
+		assert.Error(t, err, "frame %d", index)
+	}
+}
@@ -0,0 +1,195 @@
+package pixel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// PixelMeasuresMacro holds the Pixel Measures Macro (0028,9110) of one frame of an
+// enhanced multi-frame image.
+//
+// Optional attributes that are absent are nil.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.16.2.1
+type PixelMeasuresMacro struct {
+	// PixelSpacing (0028,0030) is the physical distance between pixel centres in mm,
+	// as [row spacing, column spacing].
+	PixelSpacing []float64
+
+	// SliceThickness (0018,0050) is the nominal slice thickness in mm.
+	SliceThickness *float64
+
+	// SpacingBetweenSlices (0018,0088) is the distance between adjacent slices in mm.
+	SpacingBetweenSlices *float64
+}
+
+// PixelMeasures returns the Pixel Measures Macro of frame frameIndex (0-based),
+// taking each attribute from the Per-Frame Functional Groups Sequence (5200,9230)
+// item for the frame or, failing that, the Shared Functional Groups Sequence
+// (5200,9229).
+//
+// Returns an error if none of the macro's attributes is present or a value is
+// malformed.
+//
+// Example:
+//
+//	pm, err := pixel.PixelMeasures(ds, 0)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("spacing %v mm\n", pm.PixelSpacing)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.16.2.1
+func PixelMeasures(ds *dicom.DataSet, frameIndex int) (*PixelMeasuresMacro, error) {
+	var pm PixelMeasuresMacro
+	found := false
+
+	spacing, ok, err := functionalGroupFloats(ds, frameIndex, tag.PixelMeasuresSequence, tag.PixelSpacing, 2)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		pm.PixelSpacing = spacing
+		found = true
+	}
+
+	for _, attr := range []struct {
+		tag tag.Tag
+		dst **float64
+	}{
+		{tag.SliceThickness, &pm.SliceThickness},
+		{tag.SpacingBetweenSlices, &pm.SpacingBetweenSlices},
+	} {
+		values, ok, err := functionalGroupFloats(ds, frameIndex, tag.PixelMeasuresSequence, attr.tag, 1)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			*attr.dst = &values[0]
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: Pixel Measures Sequence for frame %d", ErrMissingRequiredAttribute, frameIndex)
+	}
+	return &pm, nil
+}
+
+// PlanePosition returns Image Position (Patient) (0020,0032) of frame frameIndex
+// (0-based) from the Plane Position Macro (0020,9113): the x, y and z coordinates in
+// mm of the centre of the frame's first voxel.
+//
+// Per-frame functional groups are consulted first, then shared functional groups.
+//
+// Example:
+//
+//	pos, err := pixel.PlanePosition(ds, 10)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("slice at z=%.2f\n", pos[2])
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.16.2.3
+func PlanePosition(ds *dicom.DataSet, frameIndex int) ([3]float64, error) {
+	var pos [3]float64
+	values, ok, err := functionalGroupFloats(ds, frameIndex, tag.PlanePositionSequence, tag.ImagePositionPatient, 3)
+	if err != nil {
+		return pos, err
+	}
+	if !ok {
+		return pos, fmt.Errorf("%w: Image Position (Patient) for frame %d", ErrMissingRequiredAttribute, frameIndex)
+	}
+	copy(pos[:], values)
+	return pos, nil
+}
+
+// PlaneOrientation returns Image Orientation (Patient) (0020,0037) of frame
+// frameIndex (0-based) from the Plane Orientation Macro (0020,9116): the direction
+// cosines of the first row followed by those of the first column.
+//
+// Per-frame functional groups are consulted first, then shared functional groups.
+//
+// Example:
+//
+//	orient, err := pixel.PlaneOrientation(ds, 0)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	row, col := orient[:3], orient[3:]
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.16.2.4
+func PlaneOrientation(ds *dicom.DataSet, frameIndex int) ([6]float64, error) {
+	var orient [6]float64
+	values, ok, err := functionalGroupFloats(ds, frameIndex, tag.PlaneOrientationSequence, tag.ImageOrientationPatient, 6)
+	if err != nil {
+		return orient, err
+	}
+	if !ok {
+		return orient, fmt.Errorf("%w: Image Orientation (Patient) for frame %d", ErrMissingRequiredAttribute, frameIndex)
+	}
+	copy(orient[:], values)
+	return orient, nil
+}
+
+// functionalGroupFloats reads a numeric functional group attribute with exactly n
+// values. ok is false if the attribute is absent or empty in both functional groups.
+func functionalGroupFloats(ds *dicom.DataSet, frameIndex int, sequenceTag, attrTag tag.Tag, n int) ([]float64, bool, error) {
+	if ds == nil {
+		return nil, false, fmt.Errorf("dataset is nil")
+	}
+	if frames, err := ds.GetSequenceItems(tag.PerFrameFunctionalGroupsSequence); err == nil &&
+		(frameIndex < 0 || frameIndex >= len(frames)) {
+		return nil, false, fmt.Errorf("frame index %d out of range, Per-Frame Functional Groups Sequence has %d items",
+			frameIndex, len(frames))
+	}
+
+	v, err := dicom.FunctionalGroupValue(ds, frameIndex, sequenceTag, attrTag)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	var values []float64
+	switch val := v.(type) {
+	case *value.StringValue:
+		if strings.TrimSpace(val.String()) == "" {
+			return nil, false, nil
+		}
+		if values, err = val.AsFloats(); err != nil {
+			return nil, false, fmt.Errorf("invalid %s: %w", attributeKeyword(attrTag), err)
+		}
+	case *value.FloatValue:
+		values = val.Floats()
+	default:
+		return nil, false, fmt.Errorf("invalid %s: unexpected VR %s", attributeKeyword(attrTag), v.VR())
+	}
+
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+	if len(values) != n {
+		return nil, false, &PixelDataError{
+			Field:    attributeKeyword(attrTag) + " value count",
+			Expected: n,
+			Actual:   len(values),
+		}
+	}
+	return values, true, nil
+}
+
+// attributeKeyword returns the dictionary keyword of t, or its (gggg,eeee) form for
+// tags not in the dictionary.
+func attributeKeyword(t tag.Tag) string {
+	if info, err := tag.Find(t); err == nil {
+		return info.Keyword
+	}
+	return t.String()
+}
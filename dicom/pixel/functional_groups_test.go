@@ -0,0 +1,117 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMacroItem builds a functional groups macro item with DS attributes.
+func newMacroItem(t *testing.T, attrs map[tag.Tag][]string) *dicom.DataSet {
+	item := dicom.NewDataSet()
+	for tg, values := range attrs {
+		addGSPSString(t, item, tg, vr.DecimalString, values...)
+	}
+	return item
+}
+
+// newFunctionalGroupsDataSet returns a two-frame enhanced image with shared pixel
+// measures and orientation, per-frame positions, and a per-frame slice thickness
+// override on the second frame.
+func newFunctionalGroupsDataSet(t *testing.T) *dicom.DataSet {
+	shared := dicom.NewDataSet()
+	addGSPSSequence(t, shared, tag.PixelMeasuresSequence, newMacroItem(t, map[tag.Tag][]string{
+		tag.PixelSpacing:   {"0.5", "0.75"},
+		tag.SliceThickness: {"2.0"},
+	}))
+	addGSPSSequence(t, shared, tag.PlaneOrientationSequence, newMacroItem(t, map[tag.Tag][]string{
+		tag.ImageOrientationPatient: {"1", "0", "0", "0", "1", "0"},
+	}))
+
+	frame0 := dicom.NewDataSet()
+	addGSPSSequence(t, frame0, tag.PlanePositionSequence, newMacroItem(t, map[tag.Tag][]string{
+		tag.ImagePositionPatient: {"-100", "-100", "0"},
+	}))
+	frame1 := dicom.NewDataSet()
+	addGSPSSequence(t, frame1, tag.PlanePositionSequence, newMacroItem(t, map[tag.Tag][]string{
+		tag.ImagePositionPatient: {"-100", "-100", "1.5"},
+	}))
+	addGSPSSequence(t, frame1, tag.PixelMeasuresSequence, newMacroItem(t, map[tag.Tag][]string{
+		tag.SliceThickness:       {"1.5"},
+		tag.SpacingBetweenSlices: {"1.5"},
+	}))
+
+	ds := dicom.NewDataSet()
+	addGSPSSequence(t, ds, tag.SharedFunctionalGroupsSequence, shared)
+	addGSPSSequence(t, ds, tag.PerFrameFunctionalGroupsSequence, frame0, frame1)
+	return ds
+}
+
+func TestPixelMeasures(t *testing.T) {
+	ds := newFunctionalGroupsDataSet(t)
+
+	pm, err := PixelMeasures(ds, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.5, 0.75}, pm.PixelSpacing)
+	require.NotNil(t, pm.SliceThickness)
+	assert.Equal(t, 2.0, *pm.SliceThickness)
+	assert.Nil(t, pm.SpacingBetweenSlices)
+
+	// Per-frame values override shared ones, attribute by attribute
+	pm, err = PixelMeasures(ds, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.5, 0.75}, pm.PixelSpacing)
+	require.NotNil(t, pm.SliceThickness)
+	assert.Equal(t, 1.5, *pm.SliceThickness)
+	require.NotNil(t, pm.SpacingBetweenSlices)
+	assert.Equal(t, 1.5, *pm.SpacingBetweenSlices)
+
+	_, err = PixelMeasures(ds, 2)
+	assert.Error(t, err)
+
+	_, err = PixelMeasures(dicom.NewDataSet(), 0)
+	assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+}
+
+func TestPlanePosition(t *testing.T) {
+	ds := newFunctionalGroupsDataSet(t)
+
+	pos, err := PlanePosition(ds, 1)
+	require.NoError(t, err)
+	assert.Equal(t, [3]float64{-100, -100, 1.5}, pos)
+
+	_, err = PlanePosition(ds, -1)
+	assert.Error(t, err)
+
+	_, err = PlanePosition(nil, 0)
+	assert.Error(t, err)
+}
+
+func TestPlaneOrientation(t *testing.T) {
+	ds := newFunctionalGroupsDataSet(t)
+
+	orient, err := PlaneOrientation(ds, 1)
+	require.NoError(t, err)
+	assert.Equal(t, [6]float64{1, 0, 0, 0, 1, 0}, orient)
+
+	t.Run("wrong value count", func(t *testing.T) {
+		shared := dicom.NewDataSet()
+		addGSPSSequence(t, shared, tag.PlaneOrientationSequence, newMacroItem(t, map[tag.Tag][]string{
+			tag.ImageOrientationPatient: {"1", "0", "0"},
+		}))
+		bad := dicom.NewDataSet()
+		addGSPSSequence(t, bad, tag.SharedFunctionalGroupsSequence, shared)
+
+		_, err := PlaneOrientation(bad, 0)
+		assert.ErrorIs(t, err, ErrInvalidPixelData)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := PlaneOrientation(dicom.NewDataSet(), 0)
+		assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+	})
+}
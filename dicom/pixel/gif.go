@@ -0,0 +1,250 @@
+package pixel
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/codeninja55/go-radx/dicom"
+)
+
+// defaultGIFFrameDelay is the frame delay used when neither GIFOptions nor the
+// dataset specify the timing of a loop (10 frames per second).
+const defaultGIFFrameDelay = 100 * time.Millisecond
+
+// GIFOptions configures ToAnimatedGIF.
+type GIFOptions struct {
+	// FrameDelay, if positive, displays every frame for this long instead of using
+	// the timing recorded in the dataset.
+	FrameDelay time.Duration
+
+	// LoopCount controls looping as in image/gif: 0 loops forever, -1 plays the
+	// frames once, and n > 0 repeats them n times after the first play.
+	LoopCount int
+
+	// WindowCenter and WindowWidth, if WindowWidth is positive, replace the window of
+	// grayscale images. Otherwise the dataset's Window Center/Width is used, falling
+	// back to the full range of the loop.
+	WindowCenter, WindowWidth float64
+
+	// Dither enables Floyd-Steinberg error diffusion when colour frames are quantized
+	// to the GIF palette, which trades banding for noise.
+	Dither bool
+}
+
+// ToAnimatedGIF renders every frame of a multi-frame image for display and encodes
+// them as an animated GIF, for sharing previews of ultrasound, XA or other cine loops
+// in a browser or chat.
+//
+// Grayscale frames go through the display pipeline: Rescale Slope/Intercept, the
+// window (see GIFOptions), and inversion of MONOCHROME1, and are encoded with a
+// 256-level gray palette. One window applies to the whole loop so that brightness does
+// not flicker between frames. Colour frames (RGB, YBR_FULL, YBR_FULL_422 and PALETTE
+// COLOR) are converted to 8-bit RGB and quantized to the Plan 9 palette.
+//
+// Frames are shown at the times given by FrameTimings (Frame Time Vector or Frame
+// Time), falling back to RecommendedFrameRate and then to 10 frames per second. GIF
+// delays have a resolution of 10ms and browsers slow down shorter delays, so delays
+// are rounded and at least 20ms.
+//
+// Example:
+//
+//	data, err := pixel.ToAnimatedGIF(ds, pixel.GIFOptions{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("loop.gif", data, 0o644)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.5
+func ToAnimatedGIF(ds *dicom.DataSet, opts GIFOptions) ([]byte, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+	pd, err := Extract(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	var frames []*image.Paletted
+	if pd.SamplesPerPixel == 1 && pd.PhotometricInterpretation != "PALETTE COLOR" {
+		frames, err = grayscaleGIFFrames(ds, pd, opts)
+	} else {
+		frames, err = colorGIFFrames(ds, pd, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	anim := &gif.GIF{
+		Image:     frames,
+		Delay:     gifDelays(ds, len(frames), opts),
+		LoopCount: opts.LoopCount,
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, fmt.Errorf("failed to encode GIF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// grayscaleGIFFrames applies the grayscale display pipeline to every frame of pd.
+func grayscaleGIFFrames(ds *dicom.DataSet, pd *PixelData, opts GIFOptions) ([]*image.Paletted, error) {
+	if pd.BitsAllocated != 8 && pd.BitsAllocated != 16 {
+		return nil, fmt.Errorf("GIF export supports BitsAllocated 8 or 16, got %d", pd.BitsAllocated)
+	}
+
+	modality, err := ExtractModalityLUTFromDataSet(ds)
+	if err != nil {
+		return nil, err
+	}
+	stored := storedValues(pd)
+	values := make([]float64, len(stored))
+	for i, v := range stored {
+		values[i] = modality.RescaleSlope*float64(v) + modality.RescaleIntercept
+	}
+
+	center, width := opts.WindowCenter, opts.WindowWidth
+	if width <= 0 {
+		if wl, err := ExtractWindowLevelFromDataSet(ds); err == nil && wl.WindowWidth > 0 {
+			center, width = wl.WindowCenter, wl.WindowWidth
+		} else {
+			center, width = fullRangeWindow(values)
+		}
+	}
+	lower, upper := center-width/2, center+width/2
+	invert := pd.PhotometricInterpretation == "MONOCHROME1"
+
+	grays := make(color.Palette, 256)
+	for i := range grays {
+		grays[i] = color.Gray{Y: uint8(i)}
+	}
+
+	rect := image.Rect(0, 0, int(pd.Columns), int(pd.Rows))
+	frameLen := int(pd.Rows) * int(pd.Columns)
+	numberOfFrames := max(pd.NumberOfFrames, 1)
+	if len(values) < frameLen*numberOfFrames {
+		return nil, &PixelDataError{
+			Field:    "pixel data samples",
+			Expected: frameLen * numberOfFrames,
+			Actual:   len(values),
+		}
+	}
+
+	frames := make([]*image.Paletted, numberOfFrames)
+	for f := range frames {
+		img := image.NewPaletted(rect, grays)
+		for i, v := range values[f*frameLen : (f+1)*frameLen] {
+			level := uint8(math.Round(applyWindowLevelValue(v, lower, upper, 255)))
+			if invert {
+				level = 255 - level
+			}
+			img.Pix[i] = level
+		}
+		frames[f] = img
+	}
+	return frames, nil
+}
+
+// colorGIFFrames converts every frame of pd to RGB and quantizes it.
+func colorGIFFrames(ds *dicom.DataSet, pd *PixelData, opts GIFOptions) ([]*image.Paletted, error) {
+	var err error
+	switch {
+	case pd.PhotometricInterpretation == "PALETTE COLOR":
+		lut, err := ExtractPaletteColorLUTFromDataSet(ds)
+		if err != nil {
+			return nil, err
+		}
+		if pd, err = ApplyPaletteColorLUT(pd, lut); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(pd.PhotometricInterpretation, "YBR_FULL"):
+		if pd, err = ConvertPhotometricInterpretation(pd, "RGB"); err != nil {
+			return nil, err
+		}
+	case pd.PhotometricInterpretation != "RGB":
+		return nil, fmt.Errorf("GIF export does not support photometric interpretation %s",
+			pd.PhotometricInterpretation)
+	}
+	if pd.SamplesPerPixel != 3 || pd.BitsAllocated != 8 {
+		return nil, fmt.Errorf("GIF export supports 8-bit RGB colour images, got %d samples of %d bits",
+			pd.SamplesPerPixel, pd.BitsAllocated)
+	}
+
+	drawer := draw.Drawer(draw.Src)
+	if opts.Dither {
+		drawer = draw.FloydSteinberg
+	}
+
+	rect := image.Rect(0, 0, int(pd.Columns), int(pd.Rows))
+	planeLen := int(pd.Rows) * int(pd.Columns)
+	frameLen := planeLen * 3
+	numberOfFrames := max(pd.NumberOfFrames, 1)
+	if len(pd.data) < frameLen*numberOfFrames {
+		return nil, &PixelDataError{
+			Field:    "pixel data length (bytes)",
+			Expected: frameLen * numberOfFrames,
+			Actual:   len(pd.data),
+		}
+	}
+
+	frames := make([]*image.Paletted, numberOfFrames)
+	for f := range frames {
+		data := pd.data[f*frameLen : (f+1)*frameLen]
+		rgba := image.NewRGBA(rect)
+		for i := 0; i < planeLen; i++ {
+			if pd.PlanarConfiguration == 0 {
+				copy(rgba.Pix[i*4:i*4+3], data[i*3:i*3+3])
+			} else {
+				rgba.Pix[i*4] = data[i]
+				rgba.Pix[i*4+1] = data[planeLen+i]
+				rgba.Pix[i*4+2] = data[2*planeLen+i]
+			}
+			rgba.Pix[i*4+3] = 255
+		}
+
+		img := image.NewPaletted(rect, palette.Plan9)
+		drawer.Draw(img, rect, rgba, image.Point{})
+		frames[f] = img
+	}
+	return frames, nil
+}
+
+// gifDelays returns the display time of each of n frames in hundredths of a second.
+func gifDelays(ds *dicom.DataSet, n int, opts GIFOptions) []int {
+	durations := make([]time.Duration, n)
+	switch timings, err := FrameTimings(ds); {
+	case opts.FrameDelay > 0:
+		for i := range durations {
+			durations[i] = opts.FrameDelay
+		}
+	case err == nil && len(timings) == n && n > 1:
+		// Each frame is shown until the next one starts; the last frame keeps the
+		// delay of the one before it.
+		for i := 0; i < n-1; i++ {
+			durations[i] = timings[i+1] - timings[i]
+		}
+		durations[n-1] = durations[n-2]
+	default:
+		delay := defaultGIFFrameDelay
+		if fps, err := RecommendedFrameRate(ds); err == nil {
+			delay = time.Duration(float64(time.Second) / fps)
+		}
+		for i := range durations {
+			durations[i] = delay
+		}
+	}
+
+	delays := make([]int, n)
+	for i, d := range durations {
+		delays[i] = max(int(math.Round(float64(d)/float64(10*time.Millisecond))), 2)
+	}
+	return delays
+}
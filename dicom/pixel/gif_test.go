@@ -0,0 +1,97 @@
+package pixel
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeTestGIF(t *testing.T, data []byte) *gif.GIF {
+	t.Helper()
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	require.NoError(t, err)
+	return anim
+}
+
+func TestToAnimatedGIF(t *testing.T) {
+	// Three 2x1 frames of 16-bit grayscale
+	newGrayLoop := func(t *testing.T, photometric string) *PixelData {
+		pd, err := NewPixelDataFromUint16([]uint16{0, 100, 200, 300, 400, 500}, 2, 3)
+		require.NoError(t, err)
+		pd.Rows, pd.NumberOfFrames = 1, 3
+		pd.PhotometricInterpretation = photometric
+		return pd
+	}
+
+	t.Run("grayscale with frame time vector", func(t *testing.T) {
+		ds := newExtractDataSet(t, newGrayLoop(t, "MONOCHROME2"), explicitVRLittleEndianUID)
+		addCineString(t, ds, tag.FrameTimeVector, vr.DecimalString, "0", "50", "100")
+
+		data, err := ToAnimatedGIF(ds, GIFOptions{})
+		require.NoError(t, err)
+
+		anim := decodeTestGIF(t, data)
+		require.Len(t, anim.Image, 3)
+		assert.Equal(t, []int{5, 10, 10}, anim.Delay)
+		assert.Equal(t, 0, anim.LoopCount)
+
+		// Full range window over the whole loop: 0 -> black, 500 -> white
+		assert.Equal(t, color.Gray{Y: 0}, color.GrayModel.Convert(anim.Image[0].At(0, 0)))
+		assert.Equal(t, color.Gray{Y: 255}, color.GrayModel.Convert(anim.Image[2].At(1, 0)))
+		assert.Equal(t, color.Gray{Y: 102}, color.GrayModel.Convert(anim.Image[1].At(0, 0)))
+	})
+
+	t.Run("MONOCHROME1 is inverted", func(t *testing.T) {
+		ds := newExtractDataSet(t, newGrayLoop(t, "MONOCHROME1"), explicitVRLittleEndianUID)
+
+		data, err := ToAnimatedGIF(ds, GIFOptions{FrameDelay: 40 * time.Millisecond, LoopCount: -1})
+		require.NoError(t, err)
+
+		anim := decodeTestGIF(t, data)
+		assert.Equal(t, []int{4, 4, 4}, anim.Delay)
+		assert.Equal(t, color.Gray{Y: 255}, color.GrayModel.Convert(anim.Image[0].At(0, 0)))
+		assert.Equal(t, color.Gray{Y: 0}, color.GrayModel.Convert(anim.Image[2].At(1, 0)))
+	})
+
+	t.Run("explicit window and default timing", func(t *testing.T) {
+		ds := newExtractDataSet(t, newGrayLoop(t, "MONOCHROME2"), explicitVRLittleEndianUID)
+
+		data, err := ToAnimatedGIF(ds, GIFOptions{WindowCenter: 100, WindowWidth: 200})
+		require.NoError(t, err)
+
+		anim := decodeTestGIF(t, data)
+		assert.Equal(t, []int{10, 10, 10}, anim.Delay)
+		assert.Equal(t, color.Gray{Y: 255}, color.GrayModel.Convert(anim.Image[1].At(1, 0)))
+	})
+
+	t.Run("RGB", func(t *testing.T) {
+		pd, err := NewPixelDataFromRGB([]byte{255, 0, 0, 0, 0, 255, 0, 255, 0, 255, 255, 255}, 2, 2)
+		require.NoError(t, err)
+		pd.Rows, pd.NumberOfFrames = 1, 2
+		ds := newExtractDataSet(t, pd, explicitVRLittleEndianUID)
+		addCineString(t, ds, tag.FrameTime, vr.DecimalString, "33.3")
+
+		data, err := ToAnimatedGIF(ds, GIFOptions{Dither: true})
+		require.NoError(t, err)
+
+		anim := decodeTestGIF(t, data)
+		require.Len(t, anim.Image, 2)
+		assert.Equal(t, []int{3, 3}, anim.Delay)
+		r, g, b, _ := anim.Image[0].At(0, 0).RGBA()
+		assert.Equal(t, [3]uint32{0xFFFF, 0, 0}, [3]uint32{r, g, b})
+		r, g, b, _ = anim.Image[1].At(1, 0).RGBA()
+		assert.Equal(t, [3]uint32{0xFFFF, 0xFFFF, 0xFFFF}, [3]uint32{r, g, b})
+	})
+
+	t.Run("nil dataset", func(t *testing.T) {
+		_, err := ToAnimatedGIF(nil, GIFOptions{})
+		assert.Error(t, err)
+	})
+}
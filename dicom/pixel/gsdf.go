@@ -0,0 +1,168 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// GSDFMinLuminance and GSDFMaxLuminance bound the luminance range, in cd/m², over
+	// which the Grayscale Standard Display Function is defined (JND indices 1 to 1023).
+	GSDFMinLuminance = 0.05
+	GSDFMaxLuminance = 4000.0
+
+	// gsdfMaxJND is the highest JND index of the Grayscale Standard Display Function.
+	gsdfMaxJND = 1023
+)
+
+// gsdfLuminanceCoefficients are a to m of the rational polynomial in ln(j) giving
+// log10 of the luminance of JND index j (PS3.14 Section 7).
+var gsdfLuminanceCoefficients = struct{ a, b, c, d, e, f, g, h, k, m float64 }{
+	a: -1.3011877,
+	b: -2.5840191e-2,
+	c: 8.0242636e-2,
+	d: -1.0320229e-1,
+	e: 1.3646699e-1,
+	f: 2.8745620e-2,
+	g: -2.5468404e-2,
+	h: -3.1978977e-3,
+	k: 1.2992634e-4,
+	m: 1.3635334e-3,
+}
+
+// gsdfJNDCoefficients are A to I of the polynomial in log10(L) giving the JND index
+// of luminance L (PS3.14 Section 7).
+var gsdfJNDCoefficients = [9]float64{
+	71.498068,
+	94.593053,
+	41.912053,
+	9.8247004,
+	0.28175407,
+	-1.1878455,
+	-0.18014349,
+	0.14710899,
+	-0.017046845,
+}
+
+// GSDFLuminance returns the luminance in cd/m² of JND index jnd on the DICOM
+// Grayscale Standard Display Function, from 0.05 cd/m² at index 1 to about
+// 3993 cd/m² at index 1023. Successive indices differ by one just-noticeable
+// difference for a standard observer, so equal steps in index look equally large.
+// jnd is clamped to the defined range [1, 1023].
+//
+// Example:
+//
+//	l := pixel.GSDFLuminance(512)  // ≈ 130.07 cd/m²
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part14.html#chapter_7
+func GSDFLuminance(jnd float64) float64 {
+	jnd = min(max(jnd, 1), gsdfMaxJND)
+	c := gsdfLuminanceCoefficients
+	x := math.Log(jnd)
+	x2, x3, x4 := x*x, x*x*x, x*x*x*x
+	num := c.a + c.c*x + c.e*x2 + c.g*x3 + c.m*x4
+	den := 1 + c.b*x + c.d*x2 + c.f*x3 + c.h*x4 + c.k*x4*x
+	return math.Pow(10, num/den)
+}
+
+// GSDFJND returns the JND index of luminance in cd/m² on the Grayscale Standard
+// Display Function, the inverse of GSDFLuminance to within a small fraction of an
+// index. luminance is clamped to [GSDFMinLuminance, GSDFMaxLuminance].
+//
+// Example:
+//
+//	j := pixel.GSDFJND(130)  // ≈ 512
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part14.html#chapter_7
+func GSDFJND(luminance float64) float64 {
+	x := math.Log10(min(max(luminance, GSDFMinLuminance), GSDFMaxLuminance))
+	j, power := 0.0, 1.0
+	for _, coeff := range gsdfJNDCoefficients {
+		j += coeff * power
+		power *= x
+	}
+	return j
+}
+
+// ApplyGSDF perceptually linearizes display-ready grayscale values (P-Values, such
+// as the output of ApplyWindowLevel or ApplyFullImagePipeline) for a display whose
+// luminance rises linearly with its driving level from minLuminance to maxLuminance
+// cd/m².
+//
+// The P-Values 0 to 2^BitsStored-1 are spread evenly over the JND indices between
+// GSDFJND(minLuminance) and GSDFJND(maxLuminance). Each is given the driving level
+// at which the display shows the luminance of its JND index, so that equal steps in
+// P-Value appear as equal steps in brightness, as PS3.14 requires. The result has
+// the same size and bit depth as p. For a display with some other native response,
+// compose the result with that display's inverse characteristic curve.
+//
+// Returns an error if:
+//   - p is not single-sample unsigned pixel data of 8 or 16 bits allocated
+//   - minLuminance and maxLuminance are not increasing and within
+//     [GSDFMinLuminance, GSDFMaxLuminance]
+//
+// Example:
+//
+//	display, err := pixel.ApplyWindowLevel(pd, 40, 400, 8)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	// A monitor measured at 0.5 to 350 cd/m²
+//	calibrated, err := pixel.ApplyGSDF(display, 350, 0.5)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part14.html
+func ApplyGSDF(p *PixelData, maxLuminance, minLuminance float64) (*PixelData, error) {
+	if p == nil {
+		return nil, fmt.Errorf("pixel data cannot be nil")
+	}
+	if p.SamplesPerPixel != 1 {
+		return nil, fmt.Errorf("GSDF only applies to grayscale images (SamplesPerPixel=1), got %d", p.SamplesPerPixel)
+	}
+	if p.PixelRepresentation != 0 {
+		return nil, fmt.Errorf("GSDF requires unsigned P-Values, got signed pixel data")
+	}
+	if p.BitsAllocated != 8 && p.BitsAllocated != 16 {
+		return nil, fmt.Errorf("GSDF requires 8 or 16 bits allocated, got %d", p.BitsAllocated)
+	}
+	if !(minLuminance >= GSDFMinLuminance && maxLuminance <= GSDFMaxLuminance && minLuminance < maxLuminance) {
+		return nil, fmt.Errorf("luminance range %g-%g cd/m² must be increasing and within %g-%g cd/m²",
+			minLuminance, maxLuminance, GSDFMinLuminance, GSDFMaxLuminance)
+	}
+
+	bits := p.BitsStored
+	if bits == 0 || bits > p.BitsAllocated {
+		bits = p.BitsAllocated
+	}
+	maxValue := 1<<bits - 1
+
+	jMin, jMax := GSDFJND(minLuminance), GSDFJND(maxLuminance)
+	lut := make([]uint16, maxValue+1)
+	for v := range lut {
+		j := jMin + (jMax-jMin)*float64(v)/float64(maxValue)
+		level := (GSDFLuminance(j) - minLuminance) / (maxLuminance - minLuminance)
+		lut[v] = uint16(math.Round(min(max(level, 0), 1) * float64(maxValue)))
+	}
+	// The endpoints are the display's own black and white
+	lut[0], lut[maxValue] = 0, uint16(maxValue)
+
+	mask := uint16(maxValue)
+	data := make([]byte, len(p.data))
+	if p.BitsAllocated == 8 {
+		for i, v := range p.data {
+			data[i] = byte(lut[uint16(v)&mask])
+		}
+	} else {
+		for i := 0; i+1 < len(p.data); i += 2 {
+			v := lut[(uint16(p.data[i])|uint16(p.data[i+1])<<8)&mask]
+			data[i] = byte(v)
+			data[i+1] = byte(v >> 8)
+		}
+	}
+
+	result := *p
+	result.data = data
+	return &result, nil
+}
@@ -0,0 +1,105 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGSDFLuminance(t *testing.T) {
+	// Values from the table of PS3.14 Annex B
+	assert.InDelta(t, 0.0500, GSDFLuminance(1), 1e-4)
+	assert.InDelta(t, 1.8508, GSDFLuminance(100), 1e-3)
+	assert.InDelta(t, 130.07, GSDFLuminance(512), 0.01)
+	assert.InDelta(t, 3993.4, GSDFLuminance(1023), 0.1)
+
+	// Clamped outside the defined range
+	assert.Equal(t, GSDFLuminance(1), GSDFLuminance(0))
+	assert.Equal(t, GSDFLuminance(1023), GSDFLuminance(5000))
+
+	for j := 2.0; j <= 1023; j++ {
+		require.Greater(t, GSDFLuminance(j), GSDFLuminance(j-1), "increasing at %v", j)
+	}
+}
+
+func TestGSDFJND(t *testing.T) {
+	for _, j := range []float64{1, 10, 100, 512, 800, 1023} {
+		assert.InDelta(t, j, GSDFJND(GSDFLuminance(j)), 0.1, "j=%v", j)
+	}
+	assert.Equal(t, GSDFJND(GSDFMinLuminance), GSDFJND(0))
+}
+
+func TestApplyGSDF(t *testing.T) {
+	ramp := make([]uint8, 256)
+	for i := range ramp {
+		ramp[i] = uint8(i)
+	}
+	pd, err := NewPixelDataFromUint8(ramp, 16, 16)
+	require.NoError(t, err)
+
+	out, err := ApplyGSDF(pd, 350, 0.5)
+	require.NoError(t, err)
+	assert.Equal(t, pd.Rows, out.Rows)
+	assert.Equal(t, pd.BitsAllocated, out.BitsAllocated)
+
+	values := out.Array().([]uint8)
+	assert.Equal(t, uint8(0), values[0])
+	assert.Equal(t, uint8(255), values[255])
+	for i := 1; i < len(values); i++ {
+		require.GreaterOrEqual(t, values[i], values[i-1], "monotonic at %d", i)
+	}
+	// Luminance rises faster than linearly in JND space, so mid-grey drives the
+	// display well below half
+	assert.Less(t, values[128], uint8(64))
+
+	// Each P-Value step is the same number of JNDs: the display shows the GSDF
+	// luminance of each P-Value to within half a driving level
+	ddlStep := (350 - 0.5) / 255
+	jndStep := (GSDFJND(350) - GSDFJND(0.5)) / 255
+	for _, v := range []int{32, 64, 128, 192, 224} {
+		want := GSDFLuminance(GSDFJND(0.5) + float64(v)*jndStep)
+		assert.InDelta(t, want, 0.5+ddlStep*float64(values[v]), ddlStep/2, "P-Value %d", v)
+	}
+
+	// The source is unchanged
+	assert.Equal(t, ramp, pd.Array().([]uint8))
+}
+
+func TestApplyGSDF_16Bit(t *testing.T) {
+	pd, err := NewPixelDataFromUint16([]uint16{0, 1024, 2048, 4095}, 2, 2)
+	require.NoError(t, err)
+	pd.BitsStored, pd.HighBit = 12, 11
+
+	out, err := ApplyGSDF(pd, 1000, 1)
+	require.NoError(t, err)
+	values := out.Array().([]uint16)
+	assert.Equal(t, uint16(0), values[0])
+	assert.Equal(t, uint16(4095), values[3])
+	assert.Less(t, values[1], values[2])
+	assert.Less(t, values[2], uint16(2048))
+}
+
+func TestApplyGSDF_Errors(t *testing.T) {
+	gray, err := NewPixelDataFromUint8(make([]uint8, 4), 2, 2)
+	require.NoError(t, err)
+
+	_, err = ApplyGSDF(nil, 350, 0.5)
+	assert.Error(t, err)
+	_, err = ApplyGSDF(gray, 0.5, 350)
+	assert.ErrorContains(t, err, "luminance range")
+	_, err = ApplyGSDF(gray, 5000, 1)
+	assert.ErrorContains(t, err, "luminance range")
+	_, err = ApplyGSDF(gray, 350, 0)
+	assert.ErrorContains(t, err, "luminance range")
+
+	signed, err := NewPixelDataFromInt16(make([]int16, 4), 2, 2)
+	require.NoError(t, err)
+	_, err = ApplyGSDF(signed, 350, 0.5)
+	assert.ErrorContains(t, err, "unsigned")
+
+	rgb, err := NewPixelDataFromRGB(make([]byte, 12), 2, 2)
+	require.NoError(t, err)
+	_, err = ApplyGSDF(rgb, 350, 0.5)
+	assert.ErrorContains(t, err, "grayscale")
+}
@@ -0,0 +1,127 @@
+package pixel
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIconDataSet builds a dataset with the given transfer syntax whose Icon Image
+// Sequence holds icon.
+func newIconDataSet(t *testing.T, icon *dicom.DataSet, tsUID string) *dicom.DataSet {
+	t.Helper()
+	require.NoError(t, icon.Remove(tag.TransferSyntaxUID))
+
+	ds := dicom.NewDataSet()
+	tsValue, err := value.NewStringValue(vr.UniqueIdentifier, []string{tsUID})
+	require.NoError(t, err)
+	tsElem, err := element.NewElement(tag.TransferSyntaxUID, vr.UniqueIdentifier, tsValue)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(tsElem))
+
+	seq, err := dicom.NewSequenceElement(tag.IconImageSequence, []*dicom.DataSet{icon})
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(seq))
+	return ds
+}
+
+// addPaletteChannel adds a 16-bit palette descriptor and its OW data to ds.
+func addPaletteChannel(t *testing.T, ds *dicom.DataSet, descriptorTag, dataTag tag.Tag, entries []uint16) {
+	t.Helper()
+	descriptor, err := value.NewIntValue(vr.UnsignedShort, []int64{int64(len(entries)), 0, 16})
+	require.NoError(t, err)
+	elem, err := element.NewElement(descriptorTag, vr.UnsignedShort, descriptor)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(elem))
+
+	data := make([]byte, 2*len(entries))
+	for i, e := range entries {
+		binary.LittleEndian.PutUint16(data[i*2:], e)
+	}
+	raw, err := value.NewBytesValue(vr.OtherWord, data)
+	require.NoError(t, err)
+	elem, err = element.NewElement(dataTag, vr.OtherWord, raw)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(elem))
+}
+
+func TestExtractIcon(t *testing.T) {
+	t.Run("native icon in compressed dataset", func(t *testing.T) {
+		pd, err := NewPixelDataFromUint8([]uint8{10, 20, 30, 40, 50, 60}, 3, 2)
+		require.NoError(t, err)
+		icon := newExtractDataSet(t, pd, explicitVRLittleEndianUID)
+		ds := newIconDataSet(t, icon, "1.2.840.10008.1.2.4.50")
+
+		got, err := ExtractIcon(ds)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(2), got.Rows)
+		assert.Equal(t, uint16(3), got.Columns)
+		assert.Equal(t, "MONOCHROME2", got.PhotometricInterpretation)
+		assert.Equal(t, []byte{10, 20, 30, 40, 50, 60}, got.RawBytes())
+
+		// The icon item is left untouched
+		_, err = icon.Get(tag.TransferSyntaxUID)
+		assert.Error(t, err)
+	})
+
+	t.Run("encapsulated icon", func(t *testing.T) {
+		pd, err := NewPixelDataFromUint8([]uint8{1, 1, 1, 2, 2, 2, 3, 3}, 4, 2)
+		require.NoError(t, err)
+		icon := newExtractDataSet(t, pd, "1.2.840.10008.1.2.5")
+		ds := newIconDataSet(t, icon, "1.2.840.10008.1.2.5")
+
+		got, err := ExtractIcon(ds)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{1, 1, 1, 2, 2, 2, 3, 3}, got.RawBytes())
+	})
+
+	t.Run("palette color icon", func(t *testing.T) {
+		pd, err := NewPixelDataFromUint8([]uint8{0, 1, 2, 3}, 2, 2)
+		require.NoError(t, err)
+		pd.PhotometricInterpretation = "PALETTE COLOR"
+		icon := newExtractDataSet(t, pd, explicitVRLittleEndianUID)
+		addPaletteChannel(t, icon, tag.RedPaletteColorLookupTableDescriptor, tag.RedPaletteColorLookupTableData,
+			[]uint16{0x0000, 0xFFFF, 0x0000, 0x8000})
+		addPaletteChannel(t, icon, tag.GreenPaletteColorLookupTableDescriptor, tag.GreenPaletteColorLookupTableData,
+			[]uint16{0x0000, 0x0000, 0xFFFF, 0x8000})
+		addPaletteChannel(t, icon, tag.BluePaletteColorLookupTableDescriptor, tag.BluePaletteColorLookupTableData,
+			[]uint16{0x0000, 0x0000, 0x0000, 0x8000})
+		ds := newIconDataSet(t, icon, explicitVRLittleEndianUID)
+
+		got, err := ExtractIcon(ds)
+		require.NoError(t, err)
+		assert.Equal(t, "RGB", got.PhotometricInterpretation)
+		assert.Equal(t, uint16(3), got.SamplesPerPixel)
+		assert.Equal(t, []byte{
+			0x00, 0x00, 0x00,
+			0xFF, 0x00, 0x00,
+			0x00, 0xFF, 0x00,
+			0x80, 0x80, 0x80,
+		}, got.RawBytes())
+	})
+
+	t.Run("palette color icon without palette", func(t *testing.T) {
+		pd, err := NewPixelDataFromUint8([]uint8{0, 1, 2, 3}, 2, 2)
+		require.NoError(t, err)
+		pd.PhotometricInterpretation = "PALETTE COLOR"
+		ds := newIconDataSet(t, newExtractDataSet(t, pd, explicitVRLittleEndianUID), explicitVRLittleEndianUID)
+
+		_, err = ExtractIcon(ds)
+		var missing *MissingAttributeError
+		assert.ErrorAs(t, err, &missing)
+	})
+
+	t.Run("no icon", func(t *testing.T) {
+		_, err := ExtractIcon(dicom.NewDataSet())
+		var missing *MissingAttributeError
+		require.ErrorAs(t, err, &missing)
+		assert.Equal(t, "IconImageSequence", missing.AttributeName)
+	})
+}
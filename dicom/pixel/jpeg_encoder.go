@@ -0,0 +1,255 @@
+package pixel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/jpeg"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// EncodeJPEGBaseline compresses each frame with JPEG Baseline (Process 1) using the
+// stdlib image/jpeg encoder and returns one fragment per frame.
+//
+// Baseline JPEG is 8-bit only: pixel data must have BitsAllocated=8 and be unsigned.
+// Window 16-bit data to 8-bit first (for example with ApplyWindowLevel or Rescale).
+//
+// Grayscale (MONOCHROME1/MONOCHROME2) frames are encoded as single-component JPEG
+// and keep their photometric interpretation. RGB frames are converted to YCbCr with
+// chroma subsampling by the encoder, so the encoded image must be labelled
+// YBR_FULL_422; EncodeJPEGBaselineDataSet does this. Both interleaved and planar RGB
+// input are accepted.
+//
+// Parameters:
+//   - pd: Native 8-bit pixel data
+//   - quality: JPEG quality, 1 (smallest) to 100 (best)
+//
+// Example:
+//
+//	display, _ := pixel.ApplyWindowLevel(pd, 40, 400, 8)
+//	fragments, err := pixel.EncodeJPEGBaseline(display, 90)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	encapsulated := pixel.EncapsulateFrames(fragments)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_8.2.1
+func EncodeJPEGBaseline(pd *PixelData, quality int) ([][]byte, error) {
+	if pd == nil {
+		return nil, fmt.Errorf("pixel data is nil")
+	}
+	if quality < 1 || quality > 100 {
+		return nil, fmt.Errorf("JPEG quality must be between 1 and 100, got %d", quality)
+	}
+	if pd.BitsAllocated != 8 || pd.PixelRepresentation != 0 {
+		return nil, fmt.Errorf("JPEG Baseline requires unsigned 8-bit data, got BitsAllocated=%d PixelRepresentation=%d (window to 8-bit first)",
+			pd.BitsAllocated, pd.PixelRepresentation)
+	}
+
+	switch {
+	case pd.SamplesPerPixel == 1 && (pd.PhotometricInterpretation == "MONOCHROME1" || pd.PhotometricInterpretation == "MONOCHROME2"):
+	case pd.SamplesPerPixel == 3 && pd.PhotometricInterpretation == "RGB":
+	default:
+		return nil, fmt.Errorf("JPEG Baseline encoding supports MONOCHROME1, MONOCHROME2 and RGB, got %s with %d samples",
+			pd.PhotometricInterpretation, pd.SamplesPerPixel)
+	}
+
+	numFrames := max(pd.NumberOfFrames, 1)
+	frameSize := int(pd.Rows) * int(pd.Columns) * int(pd.SamplesPerPixel)
+	if len(pd.data) < frameSize*numFrames {
+		return nil, &PixelDataError{
+			Field:    "pixel data length",
+			Expected: frameSize * numFrames,
+			Actual:   len(pd.data),
+		}
+	}
+
+	fragments := make([][]byte, numFrames)
+	for i := 0; i < numFrames; i++ {
+		frame := &PixelData{
+			Rows:                      pd.Rows,
+			Columns:                   pd.Columns,
+			BitsAllocated:             pd.BitsAllocated,
+			BitsStored:                pd.BitsStored,
+			HighBit:                   pd.HighBit,
+			SamplesPerPixel:           pd.SamplesPerPixel,
+			PhotometricInterpretation: pd.PhotometricInterpretation,
+			PlanarConfiguration:       pd.PlanarConfiguration,
+			NumberOfFrames:            1,
+			data:                      pd.data[i*frameSize : (i+1)*frameSize],
+		}
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, frame.Image(), &jpeg.Options{Quality: quality}); err != nil {
+			return nil, fmt.Errorf("failed to encode frame %d: %w", i, err)
+		}
+		fragments[i] = buf.Bytes()
+	}
+
+	return fragments, nil
+}
+
+// DefaultJPEGQuality is the quality used by the JPEG Baseline encoder registered for
+// EncodeForTransferSyntax.
+const DefaultJPEGQuality = 90
+
+// JPEGBaselineEncoder implements Encoder for JPEG Baseline (Process 1) using
+// EncodeJPEGBaseline.
+type JPEGBaselineEncoder struct {
+	// Quality is the JPEG quality, 1 to 100. Zero selects DefaultJPEGQuality.
+	Quality int
+}
+
+// Encode compresses each frame of pd with EncodeJPEGBaseline.
+func (e *JPEGBaselineEncoder) Encode(pd *PixelData) ([][]byte, error) {
+	quality := e.Quality
+	if quality == 0 {
+		quality = DefaultJPEGQuality
+	}
+	return EncodeJPEGBaseline(pd, quality)
+}
+
+// TransferSyntaxUID returns the JPEG Baseline transfer syntax UID.
+func (e *JPEGBaselineEncoder) TransferSyntaxUID() string {
+	return "1.2.840.10008.1.2.4.50" // JPEG Baseline (Process 1)
+}
+
+// EncapsulateFrames builds encapsulated pixel data from one fragment per frame.
+//
+// The result contains a Basic Offset Table item holding the offset of each frame,
+// one item per fragment (padded to even length), and a Sequence Delimitation Item.
+// This is the value format stored in the Pixel Data (7FE0,0010) element for
+// compressed transfer syntaxes and read back by ParseEncapsulatedPixelData.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_A.4
+func EncapsulateFrames(fragments [][]byte) []byte {
+	var buf bytes.Buffer
+
+	writeItemHeader := func(tagElement uint16, length uint32) {
+		var header [8]byte
+		binary.LittleEndian.PutUint16(header[0:2], ItemTagGroup)
+		binary.LittleEndian.PutUint16(header[2:4], tagElement)
+		binary.LittleEndian.PutUint32(header[4:8], length)
+		buf.Write(header[:])
+	}
+
+	// Basic Offset Table: offsets are relative to the first byte after the table
+	writeItemHeader(ItemTag, uint32(4*len(fragments)))
+	offset := uint32(0)
+	for _, fragment := range fragments {
+		var entry [4]byte
+		binary.LittleEndian.PutUint32(entry[:], offset)
+		buf.Write(entry[:])
+		offset += 8 + uint32(len(fragment)+len(fragment)%2)
+	}
+
+	for _, fragment := range fragments {
+		writeItemHeader(ItemTag, uint32(len(fragment)+len(fragment)%2))
+		buf.Write(fragment)
+		if len(fragment)%2 != 0 {
+			buf.WriteByte(0x00)
+		}
+	}
+
+	writeItemHeader(SequenceDelimiterTag, 0)
+
+	return buf.Bytes()
+}
+
+// EncodeJPEGBaselineDataSet compresses pd with EncodeJPEGBaseline and stores the
+// result in ds, updating the attributes that describe the encoding:
+//   - (7FE0,0010) Pixel Data: encapsulated fragments with a Basic Offset Table
+//   - (0002,0010) Transfer Syntax UID: JPEG Baseline (1.2.840.10008.1.2.4.50)
+//   - (0028,0004) Photometric Interpretation: YBR_FULL_422 for colour images
+//   - (0028,0006) Planar Configuration: 0 for colour images
+//   - (0028,2110) Lossy Image Compression: "01"
+//   - (0028,2112) Lossy Image Compression Ratio: the ratio achieved, appended
+//   - (0028,2114) Lossy Image Compression Method: ISO_10918_1, appended
+//
+// The image pixel attributes (Rows, Columns, BitsAllocated, ...) are expected to
+// already describe pd. dicom.WriteFile writes the dataset as JPEG Baseline from its
+// Transfer Syntax UID; writing it with any other WriteOptions.TransferSyntax fails.
+//
+// Example:
+//
+//	pd, _ := pixel.Extract(ds)
+//	if err := pixel.EncodeJPEGBaselineDataSet(ds, pd, 90); err != nil {
+//	    log.Fatal(err)
+//	}
+//	err := dicom.WriteFile("compressed.dcm", ds)
+func EncodeJPEGBaselineDataSet(ds *dicom.DataSet, pd *PixelData, quality int) error {
+	if ds == nil {
+		return fmt.Errorf("dataset is nil")
+	}
+
+	fragments, err := EncodeJPEGBaseline(pd, quality)
+	if err != nil {
+		return err
+	}
+
+	pixelVal, err := value.NewBytesValue(vr.OtherByte, EncapsulateFrames(fragments))
+	if err != nil {
+		return fmt.Errorf("failed to create pixel data value: %w", err)
+	}
+	pixelElem, err := element.NewElement(tag.PixelData, vr.OtherByte, pixelVal)
+	if err != nil {
+		return fmt.Errorf("failed to create pixel data element: %w", err)
+	}
+	if err := ds.Set(pixelElem); err != nil {
+		return err
+	}
+
+	if err := setStringElement(ds, tag.TransferSyntaxUID, vr.UniqueIdentifier, uid.JPEGBaselineProcess1.String()); err != nil {
+		return err
+	}
+
+	if pd.SamplesPerPixel == 3 {
+		if err := setStringElement(ds, tag.PhotometricInterpretation, vr.CodeString, "YBR_FULL_422"); err != nil {
+			return err
+		}
+		planarVal, err := value.NewIntValue(vr.UnsignedShort, []int64{0})
+		if err != nil {
+			return err
+		}
+		planarElem, err := element.NewElement(tag.PlanarConfiguration, vr.UnsignedShort, planarVal)
+		if err != nil {
+			return err
+		}
+		if err := ds.Set(planarElem); err != nil {
+			return err
+		}
+	}
+
+	compressed := 0
+	for _, fragment := range fragments {
+		compressed += len(fragment)
+	}
+	uncompressed := int(pd.Rows) * int(pd.Columns) * int(pd.SamplesPerPixel) * max(pd.NumberOfFrames, 1)
+	return RecordLossyCompression(ds, float64(uncompressed)/float64(max(compressed, 1)), LossyMethodJPEG)
+}
+
+// setStringElement adds or replaces a single-valued string element.
+func setStringElement(ds *dicom.DataSet, t tag.Tag, v vr.VR, s string) error {
+	val, err := value.NewStringValue(v, []string{s})
+	if err != nil {
+		return fmt.Errorf("failed to create value for %s: %w", t, err)
+	}
+	elem, err := element.NewElement(t, v, val)
+	if err != nil {
+		return fmt.Errorf("failed to create element %s: %w", t, err)
+	}
+	return ds.Set(elem)
+}
+
+func init() {
+	// Register JPEG Baseline encoder
+	RegisterEncoder("1.2.840.10008.1.2.4.50", &JPEGBaselineEncoder{})
+}
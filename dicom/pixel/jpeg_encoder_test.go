@@ -0,0 +1,195 @@
+package pixel
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeJPEGBaseline_Grayscale(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:       PatternGradient,
+		Rows:          16,
+		Columns:       32,
+		BitsAllocated: 8,
+	})
+
+	fragments, err := EncodeJPEGBaseline(pd, 95)
+	require.NoError(t, err)
+	require.Len(t, fragments, 1)
+
+	decoded, err := NewJPEGBaselineDecoder(uid.JPEGBaselineProcess1.String()).Decode(fragments[0], &PixelInfo{
+		Rows:            16,
+		Columns:         32,
+		BitsAllocated:   8,
+		SamplesPerPixel: 1,
+		NumberOfFrames:  1,
+	})
+	require.NoError(t, err)
+	require.Len(t, decoded, len(pd.RawBytes()))
+	assert.Less(t, meanAbsDiff(pd.RawBytes(), decoded), 2.0)
+}
+
+func TestEncodeJPEGBaseline_RGB(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:                   PatternGradient,
+		Rows:                      16,
+		Columns:                   16,
+		PhotometricInterpretation: "RGB",
+	})
+
+	fragments, err := EncodeJPEGBaseline(pd, 95)
+	require.NoError(t, err)
+	require.Len(t, fragments, 1)
+
+	decoded, err := NewJPEGBaselineDecoder(uid.JPEGBaselineProcess1.String()).Decode(fragments[0], &PixelInfo{
+		Rows:            16,
+		Columns:         16,
+		BitsAllocated:   8,
+		SamplesPerPixel: 3,
+		NumberOfFrames:  1,
+	})
+	require.NoError(t, err)
+	require.Len(t, decoded, len(pd.RawBytes()))
+	assert.Less(t, meanAbsDiff(pd.RawBytes(), decoded), 8.0)
+}
+
+func TestEncodeJPEGBaseline_MultiFrame(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:        PatternCheckerboard,
+		Rows:           8,
+		Columns:        8,
+		NumberOfFrames: 3,
+		BitsAllocated:  8,
+	})
+
+	fragments, err := EncodeJPEGBaseline(pd, 80)
+	require.NoError(t, err)
+	require.Len(t, fragments, 3)
+
+	encapsulated, err := ParseEncapsulatedPixelData(EncapsulateFrames(fragments))
+	require.NoError(t, err)
+	require.Len(t, encapsulated.BasicOffsetTable.Offsets, 3)
+	require.Len(t, encapsulated.Fragments, 3)
+
+	assert.Equal(t, uint32(0), encapsulated.BasicOffsetTable.Offsets[0])
+	for i := 1; i < 3; i++ {
+		prevLen := len(encapsulated.Fragments[i-1].Data)
+		assert.Equal(t, encapsulated.BasicOffsetTable.Offsets[i-1]+8+uint32(prevLen),
+			encapsulated.BasicOffsetTable.Offsets[i])
+	}
+	for i, fragment := range encapsulated.Fragments {
+		assert.Zero(t, len(fragment.Data)%2, "fragment %d must have even length", i)
+	}
+}
+
+func TestEncodeJPEGBaseline_Errors(t *testing.T) {
+	gray8 := NewSyntheticPixelData(SyntheticOptions{Rows: 4, Columns: 4, BitsAllocated: 8})
+	gray16 := NewSyntheticPixelData(SyntheticOptions{Rows: 4, Columns: 4})
+	signed := NewSyntheticPixelData(SyntheticOptions{Rows: 4, Columns: 4, BitsAllocated: 8, Signed: true})
+
+	tests := []struct {
+		name    string
+		pd      *PixelData
+		quality int
+	}{
+		{"nil pixel data", nil, 90},
+		{"quality too low", gray8, 0},
+		{"quality too high", gray8, 101},
+		{"16-bit data", gray16, 90},
+		{"signed data", signed, 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EncodeJPEGBaseline(tt.pd, tt.quality)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEncodeJPEGBaselineDataSet_RoundTrip(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:                   PatternGradient,
+		Rows:                      16,
+		Columns:                   16,
+		PhotometricInterpretation: "RGB",
+	})
+
+	ds := dicom.NewDataSet()
+	addTestString := func(tg tag.Tag, v vr.VR, s string) {
+		val, err := value.NewStringValue(v, []string{s})
+		require.NoError(t, err)
+		elem, err := element.NewElement(tg, v, val)
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(elem))
+	}
+	addTestUint16 := func(tg tag.Tag, n int64) {
+		val, err := value.NewIntValue(vr.UnsignedShort, []int64{n})
+		require.NoError(t, err)
+		elem, err := element.NewElement(tg, vr.UnsignedShort, val)
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(elem))
+	}
+	addTestString(tag.SOPClassUID, vr.UniqueIdentifier, "1.2.840.10008.5.1.4.1.1.7")
+	addTestString(tag.SOPInstanceUID, vr.UniqueIdentifier, "1.2.826.0.1.3680043.10.1451.3")
+	addTestString(tag.PhotometricInterpretation, vr.CodeString, "RGB")
+	addTestUint16(tag.Rows, 16)
+	addTestUint16(tag.Columns, 16)
+	addTestUint16(tag.SamplesPerPixel, 3)
+	addTestUint16(tag.BitsAllocated, 8)
+	addTestUint16(tag.BitsStored, 8)
+	addTestUint16(tag.HighBit, 7)
+	addTestUint16(tag.PixelRepresentation, 0)
+
+	require.NoError(t, EncodeJPEGBaselineDataSet(ds, pd, 95))
+
+	elem, err := ds.Get(tag.PhotometricInterpretation)
+	require.NoError(t, err)
+	assert.Equal(t, "YBR_FULL_422", elem.Value().String())
+	elem, err = ds.Get(tag.LossyImageCompression)
+	require.NoError(t, err)
+	assert.Equal(t, "01", elem.Value().String())
+	lossy, err := LossyCompressionInfo(ds)
+	require.NoError(t, err)
+	assert.Equal(t, []string{LossyMethodJPEG}, lossy.Methods)
+	require.Len(t, lossy.Ratios, 1)
+	assert.Greater(t, lossy.Ratios[0], 1.0, "the ratio achieved is recorded")
+
+	path := filepath.Join(t.TempDir(), "jpeg.dcm")
+	ts := uid.JPEGBaselineProcess1
+	require.NoError(t, dicom.WriteFileWithOptions(path, ds, dicom.WriteOptions{TransferSyntax: &ts}))
+
+	parsed, err := dicom.ParseFile(path)
+	require.NoError(t, err)
+
+	elem, err = parsed.Get(tag.TransferSyntaxUID)
+	require.NoError(t, err)
+	assert.Equal(t, uid.JPEGBaselineProcess1.String(), elem.Value().String())
+
+	decoded, err := Extract(parsed)
+	require.NoError(t, err)
+	require.Len(t, decoded.RawBytes(), len(pd.RawBytes()))
+	assert.Less(t, meanAbsDiff(pd.RawBytes(), decoded.RawBytes()), 8.0)
+}
+
+// meanAbsDiff returns the mean absolute difference between two equal-length byte slices.
+func meanAbsDiff(a, b []byte) float64 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		if d < 0 {
+			d = -d
+		}
+		sum += d
+	}
+	return sum / float64(len(a))
+}
@@ -0,0 +1,173 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// Defined Terms for Lossy Image Compression Method (0028,2114).
+const (
+	LossyMethodJPEG     = "ISO_10918_1"  // JPEG Lossy Compression
+	LossyMethodJPEGLS   = "ISO_14495_1"  // JPEG-LS Near-lossless Compression
+	LossyMethodJPEG2000 = "ISO_15444_1"  // JPEG 2000 Irreversible Compression
+	LossyMethodHTJ2K    = "ISO_15444_15" // High-Throughput JPEG 2000 Irreversible Compression
+	LossyMethodMPEG2    = "ISO_13818_2"  // MPEG2 Compression
+	LossyMethodMPEG4    = "ISO_14496_10" // MPEG-4 AVC/H.264 Compression
+	LossyMethodHEVC     = "ISO_23008_2"  // HEVC/H.265 Lossy Compression
+)
+
+// LossyInfo describes the lossy compression an image has undergone, from the
+// Lossy Image Compression attributes of the General Image module.
+type LossyInfo struct {
+	// Lossy is true if Lossy Image Compression (0028,2110) is "01": the image has
+	// been lossily compressed at some point, even if it is stored uncompressed now.
+	Lossy bool
+
+	// Ratios holds Lossy Image Compression Ratio (0028,2112), one value per
+	// successive lossy compression, oldest first.
+	Ratios []float64
+
+	// Methods holds Lossy Image Compression Method (0028,2114), such as
+	// LossyMethodJPEG, corresponding to Ratios.
+	Methods []string
+}
+
+// LossyCompressionInfo reads the Lossy Image Compression (0028,2110), Lossy Image
+// Compression Ratio (0028,2112) and Lossy Image Compression Method (0028,2114)
+// attributes of ds. If none is present the result is the zero LossyInfo, since the
+// image is not known to have been lossily compressed.
+//
+// Returns an error if:
+//   - ds is nil
+//   - Lossy Image Compression is neither "00" nor "01"
+//   - a Lossy Image Compression Ratio value is not a number
+//
+// Example:
+//
+//	info, err := pixel.LossyCompressionInfo(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if info.Lossy {
+//	    fmt.Printf("lossy: %v at %v\n", info.Methods, info.Ratios)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.1.1.5
+func LossyCompressionInfo(ds *dicom.DataSet) (*LossyInfo, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	info := &LossyInfo{}
+	if elem, err := ds.Get(tag.LossyImageCompression); err == nil {
+		switch flag := strings.TrimSpace(elem.Value().String()); flag {
+		case "01":
+			info.Lossy = true
+		case "00", "":
+		default:
+			return nil, fmt.Errorf("invalid Lossy Image Compression %q, expected 00 or 01", flag)
+		}
+	}
+	if ds.Contains(tag.LossyImageCompressionRatio) {
+		ratios, err := ds.GetFloats(tag.LossyImageCompressionRatio)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Lossy Image Compression Ratio: %w", err)
+		}
+		info.Ratios = ratios
+	}
+	info.Methods = lossyStrings(ds, tag.LossyImageCompressionMethod)
+
+	return info, nil
+}
+
+// RecordLossyCompression marks ds as lossily compressed after one more lossy
+// compression step: Lossy Image Compression (0028,2110) is set to "01", and ratio
+// and method are appended to Lossy Image Compression Ratio (0028,2112) and Lossy
+// Image Compression Method (0028,2114), keeping the values of earlier steps.
+//
+// Once set, Lossy Image Compression is never reset to "00", even when the image is
+// later stored uncompressed. Lossy encoders that write into a dataset, such as
+// EncodeJPEGBaselineDataSet, call this themselves.
+//
+// Returns an error if:
+//   - ds is nil
+//   - ratio is not a positive number
+//   - method is empty or not a valid CS value
+//
+// Example:
+//
+//	// After compressing 524288 bytes of pixel data to 52000 with JPEG 2000
+//	err := pixel.RecordLossyCompression(ds, 524288.0/52000, pixel.LossyMethodJPEG2000)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.1.1.5
+func RecordLossyCompression(ds *dicom.DataSet, ratio float64, method string) error {
+	if ds == nil {
+		return fmt.Errorf("dataset is nil")
+	}
+	if !(ratio > 0) || math.IsInf(ratio, 0) {
+		return fmt.Errorf("lossy compression ratio must be a positive number, got %g", ratio)
+	}
+	if method == "" {
+		return fmt.Errorf("lossy compression method is required")
+	}
+
+	ratios := append(lossyStrings(ds, tag.LossyImageCompressionRatio), strconv.FormatFloat(ratio, 'g', 6, 64))
+	methods := append(lossyStrings(ds, tag.LossyImageCompressionMethod), method)
+
+	// Build every element before changing ds, so an invalid method leaves it as it was
+	var elems []*element.Element
+	for _, attr := range []struct {
+		t      tag.Tag
+		v      vr.VR
+		values []string
+	}{
+		{tag.LossyImageCompressionRatio, vr.DecimalString, ratios},
+		{tag.LossyImageCompressionMethod, vr.CodeString, methods},
+		{tag.LossyImageCompression, vr.CodeString, []string{"01"}},
+	} {
+		val, err := value.NewStringValue(attr.v, attr.values)
+		if err != nil {
+			return fmt.Errorf("failed to create value for %s: %w", attr.t, err)
+		}
+		elem, err := element.NewElement(attr.t, attr.v, val)
+		if err != nil {
+			return fmt.Errorf("failed to create element %s: %w", attr.t, err)
+		}
+		elems = append(elems, elem)
+	}
+	for _, elem := range elems {
+		if err := ds.Set(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lossyStrings returns the trimmed string values of t, or nil if it is absent.
+func lossyStrings(ds *dicom.DataSet, t tag.Tag) []string {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return nil
+	}
+	strVal, ok := elem.Value().(*value.StringValue)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, s := range strVal.Strings() {
+		if s = strings.TrimSpace(s); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
@@ -0,0 +1,81 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLossyCompressionInfo(t *testing.T) {
+	ds := dicom.NewDataSet()
+	info, err := LossyCompressionInfo(ds)
+	require.NoError(t, err)
+	assert.Equal(t, &LossyInfo{}, info, "no attributes: not known to be lossy")
+
+	addGSPSString(t, ds, tag.LossyImageCompression, vr.CodeString, "01")
+	addGSPSString(t, ds, tag.LossyImageCompressionRatio, vr.DecimalString, "10", "2.5")
+	addGSPSString(t, ds, tag.LossyImageCompressionMethod, vr.CodeString, LossyMethodJPEG, LossyMethodJPEG2000)
+	info, err = LossyCompressionInfo(ds)
+	require.NoError(t, err)
+	assert.Equal(t, &LossyInfo{
+		Lossy:   true,
+		Ratios:  []float64{10, 2.5},
+		Methods: []string{LossyMethodJPEG, LossyMethodJPEG2000},
+	}, info)
+
+	addGSPSString(t, ds, tag.LossyImageCompression, vr.CodeString, "00")
+	info, err = LossyCompressionInfo(ds)
+	require.NoError(t, err)
+	assert.False(t, info.Lossy)
+}
+
+func TestLossyCompressionInfo_Errors(t *testing.T) {
+	_, err := LossyCompressionInfo(nil)
+	assert.Error(t, err)
+
+	ds := dicom.NewDataSet()
+	addGSPSString(t, ds, tag.LossyImageCompression, vr.CodeString, "YES")
+	_, err = LossyCompressionInfo(ds)
+	assert.Error(t, err)
+
+	ds = dicom.NewDataSet()
+	addGSPSString(t, ds, tag.LossyImageCompressionRatio, vr.DecimalString, "ten")
+	_, err = LossyCompressionInfo(ds)
+	assert.Error(t, err)
+}
+
+func TestRecordLossyCompression(t *testing.T) {
+	ds := dicom.NewDataSet()
+	require.NoError(t, RecordLossyCompression(ds, 10, LossyMethodJPEG))
+	require.NoError(t, RecordLossyCompression(ds, 4.0/3, LossyMethodJPEG2000))
+
+	info, err := LossyCompressionInfo(ds)
+	require.NoError(t, err)
+	assert.True(t, info.Lossy)
+	require.Len(t, info.Ratios, 2)
+	assert.Equal(t, 10.0, info.Ratios[0])
+	assert.InDelta(t, 4.0/3, info.Ratios[1], 1e-5)
+	assert.Equal(t, []string{LossyMethodJPEG, LossyMethodJPEG2000}, info.Methods, "earlier steps are kept")
+
+	// Invalid input leaves the dataset unchanged
+	for _, tc := range []struct {
+		ratio  float64
+		method string
+	}{
+		{0, LossyMethodJPEG},
+		{-2, LossyMethodJPEG},
+		{5, ""},
+		{5, "not a code string"},
+	} {
+		assert.Error(t, RecordLossyCompression(ds, tc.ratio, tc.method), "%g %q", tc.ratio, tc.method)
+	}
+	again, err := LossyCompressionInfo(ds)
+	require.NoError(t, err)
+	assert.Equal(t, info, again)
+
+	assert.Error(t, RecordLossyCompression(nil, 10, LossyMethodJPEG))
+}
@@ -3,6 +3,7 @@ package pixel
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/codeninja55/go-radx/dicom"
 	"github.com/codeninja55/go-radx/dicom/tag"
@@ -178,20 +179,34 @@ func applyWindowLevelValue(val, lowerBound, upperBound, outputMax float64) float
 //   - slope: Rescale slope (default 1.0)
 //   - intercept: Rescale intercept (default 0.0)
 //
-// Returns new PixelData with modality LUT applied.
+// Returns new PixelData with modality LUT applied. Pass WithUnits to record the
+// units of the output values in the result's Units field.
 //
 // Example:
 //
 //	// Apply HU conversion to CT image
 //	// If RescaleSlope=1.0 and RescaleIntercept=-1024
-//	hu, err := pixel.ApplyModalityLUT(pixelData, 1.0, -1024)
-func ApplyModalityLUT(p *PixelData, slope, intercept float64) (*PixelData, error) {
+//	hu, err := pixel.ApplyModalityLUT(pixelData, 1.0, -1024, pixel.WithUnits(pixel.ModalityUnits(ds)))
+//	fmt.Printf("%d %s\n", value, hu.Units) // "40 HU"
+func ApplyModalityLUT(p *PixelData, slope, intercept float64, opts ...ModalityLUTOption) (*PixelData, error) {
+	var options modalityLUTOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	if p.SamplesPerPixel != 1 {
 		return nil, fmt.Errorf("modality LUT only applies to grayscale images (SamplesPerPixel=1), got %d",
 			p.SamplesPerPixel)
 	}
 
-	// Calculate output range to determine bit depth needed
+	minOutput, maxOutput := modalityOutputRange(p, slope, intercept)
+	outputBits, needsSigned := modalityOutputFormat(p, minOutput, maxOutput)
+	return rescaleModality(p, slope, intercept, outputBits, needsSigned, options.units), nil
+}
+
+// modalityOutputRange returns the lowest and highest values the rescale maps the
+// stored value range of p to.
+func modalityOutputRange(p *PixelData, slope, intercept float64) (float64, float64) {
 	var minVal, maxVal float64
 	if p.PixelRepresentation == 1 {
 		// Signed
@@ -212,19 +227,23 @@ func ApplyModalityLUT(p *PixelData, slope, intercept float64) (*PixelData, error
 		}
 	}
 
-	minOutput := slope*minVal + intercept
-	maxOutput := slope*maxVal + intercept
+	low, high := slope*minVal+intercept, slope*maxVal+intercept
+	return math.Min(low, high), math.Max(low, high)
+}
 
-	// Determine if output needs to be signed
-	needsSigned := minOutput < 0
+// modalityOutputFormat returns the bits allocated and signedness of rescaled values
+// between minOutput and maxOutput.
+func modalityOutputFormat(p *PixelData, minOutput, maxOutput float64) (uint16, bool) {
 	outputBits := p.BitsAllocated
-
-	// Calculate required bits
-	absMax := math.Max(math.Abs(minOutput), math.Abs(maxOutput))
-	if absMax > 32767 {
+	if math.Max(math.Abs(minOutput), math.Abs(maxOutput)) > 32767 {
 		outputBits = 16
 	}
+	return outputBits, minOutput < 0
+}
 
+// rescaleModality applies the rescale to p, writing outputBits-bit values that are
+// signed if needsSigned.
+func rescaleModality(p *PixelData, slope, intercept float64, outputBits uint16, needsSigned bool, units string) *PixelData {
 	data := make([]byte, len(p.data))
 
 	if p.BitsAllocated <= 8 {
@@ -301,9 +320,60 @@ func ApplyModalityLUT(p *PixelData, slope, intercept float64) (*PixelData, error
 		NumberOfFrames:            p.NumberOfFrames,
 		data:                      data,
 		TransferSyntaxUID:         p.TransferSyntaxUID,
+		Units:                     units,
 	}
 
-	return result, nil
+	return result
+}
+
+// ModalityLUTOption configures ApplyModalityLUT.
+type ModalityLUTOption func(*modalityLUTOptions)
+
+// modalityLUTOptions holds the settings applied by ModalityLUTOptions.
+type modalityLUTOptions struct {
+	units string
+}
+
+// WithUnits sets the Units of the PixelData returned by ApplyModalityLUT, typically
+// to the result of ModalityUnits.
+func WithUnits(units string) ModalityLUTOption {
+	return func(o *modalityLUTOptions) {
+		o.units = units
+	}
+}
+
+// ModalityUnits returns the units of pixel values after the Modality LUT, for
+// display next to a pixel value.
+//
+// The label is taken from, in order:
+//   - (0028,1054) Rescale Type, e.g. "HU", "OD", "US"
+//   - (0054,1001) Units for PET images, e.g. "BQML", "CNTS", "PROPCNTS"
+//   - "HU" for CT images, whose rescaled values are Hounsfield Units
+//
+// Otherwise it returns "US" (unspecified).
+//
+// Example:
+//
+//	units := pixel.ModalityUnits(ds)
+//	hu, err := pixel.ApplyModalityLUT(pd, lut.RescaleSlope, lut.RescaleIntercept, pixel.WithUnits(units))
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.11.1.1.2
+func ModalityUnits(ds *dicom.DataSet) string {
+	if ds == nil {
+		return "US"
+	}
+	for _, t := range []tag.Tag{tag.RescaleType, tag.Units} {
+		if elem, err := ds.Get(t); err == nil {
+			if units := strings.TrimSpace(elem.Value().String()); units != "" {
+				return units
+			}
+		}
+	}
+	if elem, err := ds.Get(tag.Modality); err == nil && strings.TrimSpace(elem.Value().String()) == "CT" {
+		return "HU"
+	}
+	return "US"
 }
 
 // ExtractWindowLevelFromDataSet extracts window/level parameters from a DICOM DataSet.
@@ -383,6 +453,126 @@ func ExtractModalityLUTFromDataSet(ds *dicom.DataSet) (*ModalityLUT, error) {
 	return result, nil
 }
 
+// ExtractModalityLUTForFrame extracts the modality LUT parameters of one frame
+// (0-based) of a multi-frame image.
+//
+// Enhanced multi-frame images (Enhanced CT, Enhanced MR, ...) carry the rescale in
+// the Pixel Value Transformation Sequence (0028,9145) functional group, which may
+// differ per frame. Each attribute is taken from the Per-Frame Functional Groups
+// Sequence item for the frame, then the Shared Functional Groups Sequence, and
+// finally the image-level attributes read by ExtractModalityLUTFromDataSet.
+//
+// Returns an error if frameIndex is out of range for the Per-Frame Functional Groups
+// Sequence or a value is malformed.
+//
+// Example:
+//
+//	lut, err := pixel.ExtractModalityLUTForFrame(ds, 3)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("frame 3: HU = %g * v + %g\n", lut.RescaleSlope, lut.RescaleIntercept)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.16.2.9
+func ExtractModalityLUTForFrame(ds *dicom.DataSet, frameIndex int) (*ModalityLUT, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	result, err := ExtractModalityLUTFromDataSet(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	intercept, ok, err := functionalGroupFloats(ds, frameIndex, tag.PixelValueTransformationSequence, tag.RescaleIntercept, 1)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		result.RescaleIntercept = intercept[0]
+	}
+
+	slope, ok, err := functionalGroupFloats(ds, frameIndex, tag.PixelValueTransformationSequence, tag.RescaleSlope, 1)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		result.RescaleSlope = slope[0]
+	}
+
+	if v, err := dicom.FunctionalGroupValue(ds, frameIndex, tag.PixelValueTransformationSequence, tag.RescaleType); err == nil {
+		if rescaleType := strings.TrimSpace(v.String()); rescaleType != "" {
+			result.RescaleType = rescaleType
+		}
+	}
+
+	return result, nil
+}
+
+// ApplyModalityLUTPerFrame applies the modality LUT of frame frameIndex (0-based), as
+// returned by ExtractModalityLUTForFrame, to that frame of p.
+//
+// Returns a single-frame PixelData holding the rescaled frame, with Units set from
+// the frame's Rescale Type or, failing that, ModalityUnits. Use it for enhanced
+// multi-frame images, whose frames may each have their own rescale.
+//
+// Example:
+//
+//	// Hounsfield Units of frame 10 of an Enhanced CT image
+//	hu, err := pixel.ApplyModalityLUTPerFrame(ds, pixelData, 10)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.16.2.9
+func ApplyModalityLUTPerFrame(ds *dicom.DataSet, p *PixelData, frameIndex int) (*PixelData, error) {
+	if p == nil {
+		return nil, fmt.Errorf("pixel data is nil")
+	}
+
+	frames := p.Frames()
+	if frameIndex < 0 || frameIndex >= len(frames) {
+		return nil, fmt.Errorf("frame index %d out of range, pixel data has %d frames", frameIndex, len(frames))
+	}
+
+	modalityLUT, err := ExtractModalityLUTForFrame(ds, frameIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract modality LUT for frame %d: %w", frameIndex, err)
+	}
+
+	return ApplyModalityLUT(singleFrame(p, frames, frameIndex), modalityLUT.RescaleSlope, modalityLUT.RescaleIntercept,
+		WithUnits(modalityLUTUnits(ds, modalityLUT)))
+}
+
+// modalityLUTUnits returns the units of the output of a frame's modality LUT.
+func modalityLUTUnits(ds *dicom.DataSet, modalityLUT *ModalityLUT) string {
+	if units := strings.TrimSpace(modalityLUT.RescaleType); units != "" {
+		return units
+	}
+	return ModalityUnits(ds)
+}
+
+// singleFrame returns frame frameIndex of p, whose frames are frames, as a
+// one-frame PixelData.
+func singleFrame(p *PixelData, frames []Frame, frameIndex int) *PixelData {
+	return &PixelData{
+		Rows:                      p.Rows,
+		Columns:                   p.Columns,
+		BitsAllocated:             p.BitsAllocated,
+		BitsStored:                p.BitsStored,
+		HighBit:                   p.HighBit,
+		PixelRepresentation:       p.PixelRepresentation,
+		SamplesPerPixel:           p.SamplesPerPixel,
+		PhotometricInterpretation: p.PhotometricInterpretation,
+		PlanarConfiguration:       p.PlanarConfiguration,
+		NumberOfFrames:            1,
+		data:                      frames[frameIndex].data,
+		TransferSyntaxUID:         p.TransferSyntaxUID,
+	}
+}
+
 // ApplyFullImagePipeline applies the complete image transformation pipeline:
 //  1. Modality LUT (if present) - converts to modality units
 //  2. VOI LUT (window/level) - prepares for display
@@ -394,17 +584,43 @@ func ExtractModalityLUTFromDataSet(ds *dicom.DataSet) (*ModalityLUT, error) {
 //   - p: Source pixel data
 //   - outputBits: Output bit depth (8 for display, 16 for processing)
 //
+// For multi-frame images with a Per-Frame Functional Groups Sequence (5200,9230),
+// the modality LUT of each frame is applied with ApplyModalityLUTPerFrame, so frames
+// with differing rescale are converted correctly.
+//
+// WithPaddingBackground renders the padding pixels identified by PaddingMask as a
+// fixed value instead of passing them through the LUTs.
+//
 // Example:
 //
-//	// Apply complete pipeline to CT image
-//	display, err := pixel.ApplyFullImagePipeline(dataset, pixelData, 8)
-func ApplyFullImagePipeline(ds *dicom.DataSet, p *PixelData, outputBits uint16) (*PixelData, error) {
+//	// Apply complete pipeline to CT image, with black padding
+//	display, err := pixel.ApplyFullImagePipeline(dataset, pixelData, 8, pixel.WithPaddingBackground(0))
+func ApplyFullImagePipeline(ds *dicom.DataSet, p *PixelData, outputBits uint16, opts ...PipelineOption) (*PixelData, error) {
+	var options pipelineOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var paddingMask []bool
+	if options.paddingBackground != nil {
+		var err error
+		if paddingMask, err = PaddingMask(ds, p); err != nil {
+			return nil, fmt.Errorf("failed to identify pixel padding: %w", err)
+		}
+	}
+
 	result := p
 
 	// Step 1: Apply Modality LUT if present
-	if modalityLUT, err := ExtractModalityLUTFromDataSet(ds); err == nil {
+	if _, err := ds.GetSequenceItems(tag.PerFrameFunctionalGroupsSequence); err == nil && p.NumberOfFrames > 1 {
+		result, err = applyModalityLUTFrames(ds, p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply modality LUT: %w", err)
+		}
+	} else if modalityLUT, err := ExtractModalityLUTFromDataSet(ds); err == nil {
 		if modalityLUT.RescaleSlope != 1.0 || modalityLUT.RescaleIntercept != 0.0 {
-			result, err = ApplyModalityLUT(result, modalityLUT.RescaleSlope, modalityLUT.RescaleIntercept)
+			result, err = ApplyModalityLUT(result, modalityLUT.RescaleSlope, modalityLUT.RescaleIntercept,
+				WithUnits(ModalityUnits(ds)))
 			if err != nil {
 				return nil, fmt.Errorf("failed to apply modality LUT: %w", err)
 			}
@@ -419,5 +635,91 @@ func ApplyFullImagePipeline(ds *dicom.DataSet, p *PixelData, outputBits uint16)
 		}
 	}
 
+	if paddingMask != nil {
+		result = fillPadding(result, paddingMask, *options.paddingBackground)
+	}
+
+	return result, nil
+}
+
+// PipelineOption configures ApplyFullImagePipeline.
+type PipelineOption func(*pipelineOptions)
+
+// pipelineOptions holds the settings applied by PipelineOption.
+type pipelineOptions struct {
+	paddingBackground *uint16
+}
+
+// WithPaddingBackground makes ApplyFullImagePipeline write background, in the
+// output's units (0-255 for 8-bit output), to every padding pixel, such as the
+// corners outside a circular CT field of view. It has no effect when the dataset has
+// no Pixel Padding Value (0028,0120).
+func WithPaddingBackground(background uint16) PipelineOption {
+	return func(o *pipelineOptions) {
+		o.paddingBackground = &background
+	}
+}
+
+// fillPadding returns a copy of p with the samples marked in mask set to background.
+func fillPadding(p *PixelData, mask []bool, background uint16) *PixelData {
+	filled := *p
+	filled.data = append([]byte(nil), p.data...)
+	bytesPerSample := int(p.BitsAllocated / 8)
+	for i, padded := range mask {
+		if !padded || (i+1)*bytesPerSample > len(filled.data) {
+			continue
+		}
+		if bytesPerSample == 1 {
+			filled.data[i] = byte(background)
+		} else {
+			filled.data[i*2] = byte(background)
+			filled.data[i*2+1] = byte(background >> 8)
+		}
+	}
+	return &filled
+}
+
+// applyModalityLUTFrames applies the modality LUT of each frame of p and joins the
+// rescaled frames back into one multi-frame PixelData.
+//
+// The output bit depth and signedness are chosen once from the range of every
+// frame's rescale, so that frames with, say, intercepts 0 and -1024 share one pixel
+// format.
+func applyModalityLUTFrames(ds *dicom.DataSet, p *PixelData) (*PixelData, error) {
+	if p.SamplesPerPixel != 1 {
+		return nil, fmt.Errorf("modality LUT only applies to grayscale images (SamplesPerPixel=1), got %d",
+			p.SamplesPerPixel)
+	}
+
+	frames := p.Frames()
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("pixel data has no frames")
+	}
+	luts := make([]*ModalityLUT, len(frames))
+	minOutput, maxOutput := math.Inf(1), math.Inf(-1)
+	for i := range frames {
+		modalityLUT, err := ExtractModalityLUTForFrame(ds, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract modality LUT for frame %d: %w", i, err)
+		}
+		luts[i] = modalityLUT
+		low, high := modalityOutputRange(p, modalityLUT.RescaleSlope, modalityLUT.RescaleIntercept)
+		minOutput, maxOutput = math.Min(minOutput, low), math.Max(maxOutput, high)
+	}
+	outputBits, needsSigned := modalityOutputFormat(p, minOutput, maxOutput)
+
+	var result *PixelData
+	data := make([]byte, 0, len(p.data))
+	for i, modalityLUT := range luts {
+		frame := rescaleModality(singleFrame(p, frames, i), modalityLUT.RescaleSlope, modalityLUT.RescaleIntercept,
+			outputBits, needsSigned, modalityLUTUnits(ds, modalityLUT))
+		if result == nil {
+			result = frame
+		}
+		data = append(data, frame.data...)
+	}
+
+	result.NumberOfFrames = len(frames)
+	result.data = data
 	return result, nil
 }
@@ -1,10 +1,12 @@
 package pixel
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/codeninja55/go-radx/dicom"
 	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
 )
 
 // PresentationLUT represents the Presentation LUT transformation.
@@ -199,24 +201,11 @@ func applyPresentationLUTTable(p *PixelData, presentationLUT *PresentationLUT) (
 	return result, nil
 }
 
-// invertPixelData inverts pixel values (max - value).
+// invertPixelData inverts pixel values (max - value) within the range of BitsStored.
 func invertPixelData(p *PixelData) (*PixelData, error) {
-	data := make([]byte, len(p.data))
-	maxVal := uint16((1 << p.BitsStored) - 1)
-
-	if p.BitsAllocated <= 8 {
-		// 8-bit
-		for i := 0; i < len(p.data); i++ {
-			data[i] = uint8(maxVal) - p.data[i]
-		}
-	} else {
-		// 16-bit
-		for i := 0; i < len(p.data)/2; i++ {
-			val16 := uint16(p.data[i*2]) | uint16(p.data[i*2+1])<<8
-			inverted := maxVal - val16
-			data[i*2] = byte(inverted)
-			data[i*2+1] = byte(inverted >> 8)
-		}
+	data, err := invertSamples(p)
+	if err != nil {
+		return nil, err
 	}
 
 	result := &PixelData{
@@ -357,7 +346,7 @@ func (p *PaletteColorLUT) lookupBlue(idx int) uint16 {
 // expandSegmentedPalettes expands segmented palette data into full arrays.
 func (p *PaletteColorLUT) expandSegmentedPalettes() error {
 	if p.RedSegmented != nil {
-		expanded, err := p.RedSegmented.Expand(int(p.RedDescriptor[0]))
+		expanded, err := p.RedSegmented.Expand(paletteEntries(p.RedDescriptor))
 		if err != nil {
 			return fmt.Errorf("failed to expand red segmented palette: %w", err)
 		}
@@ -365,7 +354,7 @@ func (p *PaletteColorLUT) expandSegmentedPalettes() error {
 	}
 
 	if p.GreenSegmented != nil {
-		expanded, err := p.GreenSegmented.Expand(int(p.GreenDescriptor[0]))
+		expanded, err := p.GreenSegmented.Expand(paletteEntries(p.GreenDescriptor))
 		if err != nil {
 			return fmt.Errorf("failed to expand green segmented palette: %w", err)
 		}
@@ -373,7 +362,7 @@ func (p *PaletteColorLUT) expandSegmentedPalettes() error {
 	}
 
 	if p.BlueSegmented != nil {
-		expanded, err := p.BlueSegmented.Expand(int(p.BlueDescriptor[0]))
+		expanded, err := p.BlueSegmented.Expand(paletteEntries(p.BlueDescriptor))
 		if err != nil {
 			return fmt.Errorf("failed to expand blue segmented palette: %w", err)
 		}
@@ -474,46 +463,112 @@ func (s *SegmentedLUT) Expand(numEntries int) ([]uint16, error) {
 //   - Green Palette Color Lookup Table Data (0028,1202)
 //   - Blue Palette Color Lookup Table Data (0028,1203)
 //   - Segmented palettes if present (0028,1221-1223)
+//
+// A descriptor entry count of 0 means 65536 entries. Entries of 8-bit palettes are
+// scaled to 16 bits, so ApplyPaletteColorLUT handles both entry sizes alike.
+//
+// Returns a MissingAttributeError if a descriptor, or both the plain and segmented
+// data of a channel, are missing.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.3.1.5
 func ExtractPaletteColorLUTFromDataSet(ds *dicom.DataSet) (*PaletteColorLUT, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
 	palette := &PaletteColorLUT{}
 
-	// Extract descriptors
-	if _, err := ds.Get(tag.New(0x0028, 0x1101)); err == nil {
-		// Red descriptor
-		// Descriptor is US with VM=3: [entries, first_mapped, bits]
-		// We'll need to parse this properly based on the VR
-		palette.RedDescriptor = [3]uint16{0, 0, 16} // Default values
+	for _, channel := range []struct {
+		name                 string
+		descriptorTag        tag.Tag
+		dataTag, segmentsTag tag.Tag
+		descriptor           *[3]uint16
+		data                 *[]uint16
+		segmented            **SegmentedLUT
+	}{
+		{"Red", tag.RedPaletteColorLookupTableDescriptor, tag.RedPaletteColorLookupTableData,
+			tag.SegmentedRedPaletteColorLookupTableData, &palette.RedDescriptor, &palette.RedData, &palette.RedSegmented},
+		{"Green", tag.GreenPaletteColorLookupTableDescriptor, tag.GreenPaletteColorLookupTableData,
+			tag.SegmentedGreenPaletteColorLookupTableData, &palette.GreenDescriptor, &palette.GreenData, &palette.GreenSegmented},
+		{"Blue", tag.BluePaletteColorLookupTableDescriptor, tag.BluePaletteColorLookupTableData,
+			tag.SegmentedBluePaletteColorLookupTableData, &palette.BlueDescriptor, &palette.BlueData, &palette.BlueSegmented},
+	} {
+		descriptor, err := ds.GetInts(channel.descriptorTag)
+		if err != nil || len(descriptor) != 3 {
+			return nil, &MissingAttributeError{
+				AttributeName: channel.name + "PaletteColorLookupTableDescriptor",
+				Tag:           channel.descriptorTag.String(),
+			}
+		}
+		for i, v := range descriptor {
+			channel.descriptor[i] = uint16(v)
+		}
+		if words, ok := paletteWords(ds, channel.dataTag, paletteEntries(*channel.descriptor), channel.descriptor[2]); ok {
+			*channel.data = words
+			continue
+		}
+		if words, ok := paletteWords(ds, channel.segmentsTag, 0, 16); ok {
+			*channel.segmented = &SegmentedLUT{Data: words}
+			continue
+		}
+		return nil, &MissingAttributeError{
+			AttributeName: channel.name + "PaletteColorLookupTableData",
+			Tag:           channel.dataTag.String(),
+		}
 	}
 
-	if _, err := ds.Get(tag.New(0x0028, 0x1102)); err == nil {
-		palette.GreenDescriptor = [3]uint16{0, 0, 16}
-	}
+	return palette, nil
+}
 
-	if _, err := ds.Get(tag.New(0x0028, 0x1103)); err == nil {
-		palette.BlueDescriptor = [3]uint16{0, 0, 16}
+// paletteEntries returns the number of entries of a palette descriptor, where 0
+// stands for 65536.
+func paletteEntries(descriptor [3]uint16) int {
+	if descriptor[0] == 0 {
+		return 65536
 	}
+	return int(descriptor[0])
+}
 
-	// Extract palette data
-	if elem, err := ds.Get(tag.New(0x0028, 0x1201)); err == nil {
-		// Red data - stored as OW (Other Word)
-		// Need to convert bytes to uint16 array
-		_ = elem // TODO: Parse actual data
+// paletteWords reads the palette LUT data in t as 16-bit entries. bits is the
+// descriptor's bits per entry; 8-bit entries, stored either one per word or packed
+// two per word (when the data holds exactly entries bytes), are scaled to 16 bits.
+func paletteWords(ds *dicom.DataSet, t tag.Tag, entries int, bits uint16) ([]uint16, bool) {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return nil, false
 	}
 
-	if elem, err := ds.Get(tag.New(0x0028, 0x1202)); err == nil {
-		// Green data
-		_ = elem
+	var words []uint16
+	switch v := elem.Value().(type) {
+	case *value.IntValue:
+		for _, n := range v.Ints() {
+			words = append(words, uint16(n))
+		}
+	default:
+		data := v.Bytes()
+		if bits == 8 && entries > 0 && len(data) == entries {
+			words = make([]uint16, entries)
+			for i, b := range data {
+				words[i] = uint16(b)
+			}
+		} else {
+			words = make([]uint16, len(data)/2)
+			for i := range words {
+				words[i] = binary.LittleEndian.Uint16(data[i*2:])
+			}
+		}
 	}
-
-	if elem, err := ds.Get(tag.New(0x0028, 0x1203)); err == nil {
-		// Blue data
-		_ = elem
+	if len(words) == 0 {
+		return nil, false
 	}
 
-	// Check for segmented palettes (0028,1221-1223)
-	// These are optional and provide a more compact representation
-
-	return palette, nil
+	if bits == 8 {
+		for i, w := range words {
+			w &= 0xFF
+			words[i] = w<<8 | w
+		}
+	}
+	return words, true
 }
 
 // ExtractPresentationLUTFromDataSet extracts Presentation LUT from a DICOM DataSet.
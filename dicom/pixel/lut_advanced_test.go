@@ -3,6 +3,11 @@ package pixel
 import (
 	"testing"
 
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -462,3 +467,51 @@ func setupSimplePalette(t *testing.T, numEntries int) *PaletteColorLUT {
 		BlueData:        blueData,
 	}
 }
+
+// TestExtractPaletteColorLUTFromDataSet tests reading palettes from a dataset
+func TestExtractPaletteColorLUTFromDataSet(t *testing.T) {
+	t.Run("16-bit entries", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addPaletteChannel(t, ds, tag.RedPaletteColorLookupTableDescriptor, tag.RedPaletteColorLookupTableData, []uint16{1, 2})
+		addPaletteChannel(t, ds, tag.GreenPaletteColorLookupTableDescriptor, tag.GreenPaletteColorLookupTableData, []uint16{3, 4})
+		addPaletteChannel(t, ds, tag.BluePaletteColorLookupTableDescriptor, tag.BluePaletteColorLookupTableData, []uint16{5, 6})
+
+		palette, err := ExtractPaletteColorLUTFromDataSet(ds)
+		require.NoError(t, err)
+		assert.Equal(t, [3]uint16{2, 0, 16}, palette.RedDescriptor)
+		assert.Equal(t, []uint16{1, 2}, palette.RedData)
+		assert.Equal(t, []uint16{3, 4}, palette.GreenData)
+		assert.Equal(t, []uint16{5, 6}, palette.BlueData)
+	})
+
+	t.Run("8-bit packed entries are scaled", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		for _, ch := range [][2]tag.Tag{
+			{tag.RedPaletteColorLookupTableDescriptor, tag.RedPaletteColorLookupTableData},
+			{tag.GreenPaletteColorLookupTableDescriptor, tag.GreenPaletteColorLookupTableData},
+			{tag.BluePaletteColorLookupTableDescriptor, tag.BluePaletteColorLookupTableData},
+		} {
+			descriptor, err := value.NewIntValue(vr.UnsignedShort, []int64{2, 0, 8})
+			require.NoError(t, err)
+			elem, err := element.NewElement(ch[0], vr.UnsignedShort, descriptor)
+			require.NoError(t, err)
+			require.NoError(t, ds.Add(elem))
+			raw, err := value.NewBytesValue(vr.OtherWord, []byte{0x00, 0x80})
+			require.NoError(t, err)
+			elem, err = element.NewElement(ch[1], vr.OtherWord, raw)
+			require.NoError(t, err)
+			require.NoError(t, ds.Add(elem))
+		}
+
+		palette, err := ExtractPaletteColorLUTFromDataSet(ds)
+		require.NoError(t, err)
+		assert.Equal(t, []uint16{0x0000, 0x8080}, palette.RedData)
+	})
+
+	t.Run("missing descriptor", func(t *testing.T) {
+		_, err := ExtractPaletteColorLUTFromDataSet(dicom.NewDataSet())
+		var missing *MissingAttributeError
+		require.ErrorAs(t, err, &missing)
+		assert.Equal(t, "RedPaletteColorLookupTableDescriptor", missing.AttributeName)
+	})
+}
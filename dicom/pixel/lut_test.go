@@ -359,6 +359,185 @@ func TestExtractModalityLUTFromDataSet_PartialTags(t *testing.T) {
 	assert.Equal(t, -500.0, lut.RescaleIntercept)
 }
 
+func TestModalityUnits(t *testing.T) {
+	addString := func(t *testing.T, ds *dicom.DataSet, tg tag.Tag, v vr.VR, s string) {
+		t.Helper()
+		val, err := value.NewStringValue(v, []string{s})
+		require.NoError(t, err)
+		elem, err := element.NewElement(tg, v, val)
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(elem))
+	}
+
+	t.Run("rescale type", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addString(t, ds, tag.Modality, vr.CodeString, "CT")
+		addString(t, ds, tag.RescaleType, vr.LongString, "OD")
+		assert.Equal(t, "OD", ModalityUnits(ds))
+	})
+
+	t.Run("PET units", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addString(t, ds, tag.Modality, vr.CodeString, "PT")
+		addString(t, ds, tag.Units, vr.CodeString, "PROPCNTS")
+		assert.Equal(t, "PROPCNTS", ModalityUnits(ds))
+	})
+
+	t.Run("CT defaults to HU", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addString(t, ds, tag.Modality, vr.CodeString, "CT")
+		assert.Equal(t, "HU", ModalityUnits(ds))
+	})
+
+	t.Run("unspecified", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addString(t, ds, tag.Modality, vr.CodeString, "MR")
+		assert.Equal(t, "US", ModalityUnits(ds))
+		assert.Equal(t, "US", ModalityUnits(nil))
+	})
+}
+
+func TestApplyModalityLUT_WithUnits(t *testing.T) {
+	pixelData, err := NewPixelDataFromUint16([]uint16{0, 1024, 2048, 4095}, 2, 2)
+	require.NoError(t, err)
+
+	hu, err := ApplyModalityLUT(pixelData, 1.0, -1024, WithUnits("HU"))
+	require.NoError(t, err)
+	assert.Equal(t, "HU", hu.Units)
+
+	plain, err := ApplyModalityLUT(pixelData, 1.0, -1024)
+	require.NoError(t, err)
+	assert.Empty(t, plain.Units)
+}
+
+// newPerFrameRescaleDataSet returns a two-frame enhanced CT image whose frames have
+// different Rescale Intercepts in the Pixel Value Transformation Sequence, with a
+// shared Rescale Type and a stale image-level intercept.
+func newPerFrameRescaleDataSet(t *testing.T) *dicom.DataSet {
+	shared := dicom.NewDataSet()
+	sharedTransform := dicom.NewDataSet()
+	addGSPSString(t, sharedTransform, tag.RescaleType, vr.LongString, "HU")
+	addGSPSSequence(t, shared, tag.PixelValueTransformationSequence, sharedTransform)
+
+	frame0 := dicom.NewDataSet()
+	addGSPSSequence(t, frame0, tag.PixelValueTransformationSequence, newMacroItem(t, map[tag.Tag][]string{
+		tag.RescaleIntercept: {"-1024"},
+		tag.RescaleSlope:     {"1"},
+	}))
+	frame1 := dicom.NewDataSet()
+	addGSPSSequence(t, frame1, tag.PixelValueTransformationSequence, newMacroItem(t, map[tag.Tag][]string{
+		tag.RescaleIntercept: {"-1000"},
+		tag.RescaleSlope:     {"1"},
+	}))
+
+	ds := dicom.NewDataSet()
+	addGSPSString(t, ds, tag.RescaleIntercept, vr.DecimalString, "-2048")
+	addGSPSSequence(t, ds, tag.SharedFunctionalGroupsSequence, shared)
+	addGSPSSequence(t, ds, tag.PerFrameFunctionalGroupsSequence, frame0, frame1)
+	return ds
+}
+
+// newTwoFramePixelData returns two 2x1 frames with the same stored values.
+func newTwoFramePixelData(t *testing.T) *PixelData {
+	pixelData, err := NewPixelDataFromUint16([]uint16{1000, 1100, 1000, 1100}, 2, 2)
+	require.NoError(t, err)
+	pixelData.Rows = 1
+	pixelData.NumberOfFrames = 2
+	return pixelData
+}
+
+func TestExtractModalityLUTForFrame(t *testing.T) {
+	ds := newPerFrameRescaleDataSet(t)
+
+	lut, err := ExtractModalityLUTForFrame(ds, 0)
+	require.NoError(t, err)
+	assert.Equal(t, -1024.0, lut.RescaleIntercept)
+	assert.Equal(t, 1.0, lut.RescaleSlope)
+	assert.Equal(t, "HU", lut.RescaleType)
+
+	lut, err = ExtractModalityLUTForFrame(ds, 1)
+	require.NoError(t, err)
+	assert.Equal(t, -1000.0, lut.RescaleIntercept)
+
+	_, err = ExtractModalityLUTForFrame(ds, 2)
+	assert.Error(t, err)
+
+	// Without functional groups the image-level attributes are used
+	plain := dicom.NewDataSet()
+	addGSPSString(t, plain, tag.RescaleIntercept, vr.DecimalString, "-1024")
+	addGSPSString(t, plain, tag.RescaleSlope, vr.DecimalString, "2")
+	lut, err = ExtractModalityLUTForFrame(plain, 0)
+	require.NoError(t, err)
+	assert.Equal(t, -1024.0, lut.RescaleIntercept)
+	assert.Equal(t, 2.0, lut.RescaleSlope)
+
+	_, err = ExtractModalityLUTForFrame(nil, 0)
+	assert.Error(t, err)
+}
+
+func TestApplyModalityLUTPerFrame(t *testing.T) {
+	ds := newPerFrameRescaleDataSet(t)
+	pixelData := newTwoFramePixelData(t)
+
+	hu0, err := ApplyModalityLUTPerFrame(ds, pixelData, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, hu0.NumberOfFrames)
+	assert.Equal(t, "HU", hu0.Units)
+	assert.Equal(t, []int16{-24, 76}, int16Samples(hu0))
+
+	hu1, err := ApplyModalityLUTPerFrame(ds, pixelData, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int16{0, 100}, int16Samples(hu1))
+
+	_, err = ApplyModalityLUTPerFrame(ds, pixelData, 2)
+	assert.Error(t, err)
+}
+
+func TestApplyFullImagePipeline_PerFrameRescale(t *testing.T) {
+	ds := newPerFrameRescaleDataSet(t)
+	pixelData := newTwoFramePixelData(t)
+
+	result, err := ApplyFullImagePipeline(ds, pixelData, 16)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.NumberOfFrames)
+	assert.Equal(t, []int16{-24, 76, 0, 100}, int16Samples(result))
+}
+
+func TestApplyModalityLUTFrames_MixedSignIntercepts(t *testing.T) {
+	// Frame 0 rescales to unsigned values on its own, frame 1 to signed ones
+	frame0 := dicom.NewDataSet()
+	addGSPSSequence(t, frame0, tag.PixelValueTransformationSequence, newMacroItem(t, map[tag.Tag][]string{
+		tag.RescaleIntercept: {"0"},
+		tag.RescaleSlope:     {"1"},
+	}))
+	frame1 := dicom.NewDataSet()
+	addGSPSSequence(t, frame1, tag.PixelValueTransformationSequence, newMacroItem(t, map[tag.Tag][]string{
+		tag.RescaleIntercept: {"-1024"},
+		tag.RescaleSlope:     {"1"},
+	}))
+	ds := dicom.NewDataSet()
+	addGSPSSequence(t, ds, tag.PerFrameFunctionalGroupsSequence, frame0, frame1)
+	pixelData := newTwoFramePixelData(t)
+	pixelData.BitsStored = 12
+	pixelData.HighBit = 11
+
+	result, err := applyModalityLUTFrames(ds, pixelData)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.NumberOfFrames)
+	assert.Equal(t, uint16(1), result.PixelRepresentation, "one signed representation for every frame")
+	assert.Equal(t, uint16(16), result.BitsAllocated)
+	assert.Equal(t, []int16{1000, 1100, -24, 76}, int16Samples(result))
+}
+
+// int16Samples returns the signed 16-bit samples of p.
+func int16Samples(p *PixelData) []int16 {
+	samples := make([]int16, len(p.data)/2)
+	for i := range samples {
+		samples[i] = int16(uint16(p.data[i*2]) | uint16(p.data[i*2+1])<<8)
+	}
+	return samples
+}
+
 func TestApplyFullImagePipeline_CompleteTransformation(t *testing.T) {
 	// Create CT-like data
 	data := make([]uint16, 10*10)
@@ -0,0 +1,310 @@
+package pixel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// NIfTI-1 header constants.
+const (
+	niftiHeaderSize   = 348
+	niftiVoxOffset    = 352 // header plus the 4-byte extension flag
+	niftiFloat32      = 16  // NIFTI_TYPE_FLOAT32
+	niftiUnitsMM      = 2   // NIFTI_UNITS_MM
+	niftiXformScanner = 1   // NIFTI_XFORM_SCANNER_ANAT
+)
+
+// NIfTIVolume is a Volume together with the NIfTI affine that maps its voxel indices
+// to RAS+ world coordinates.
+//
+// The voxels keep the Volume's order, which is also the NIfTI order: i runs along the
+// columns of a slice, j along its rows and k across the slices.
+type NIfTIVolume struct {
+	*Volume
+
+	// Affine maps a voxel index (i, j, k, 1) to the RAS+ coordinates, in mm, of the
+	// voxel's centre. It is written to the header as the sform.
+	Affine [4][4]float64
+}
+
+// ToNIfTI builds a volume from series, as BuildVolume does, and computes its NIfTI
+// affine. The result can be saved as a single-file .nii with MarshalBinary.
+//
+// Returns any error from BuildVolume.
+//
+// Example:
+//
+//	nv, err := pixel.ToNIfTI(series, pixel.WithResampling())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	data, err := nv.MarshalBinary()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = os.WriteFile("ct.nii", data, 0o644)
+func ToNIfTI(series []*dicom.DataSet, opts ...VolumeOption) (*NIfTIVolume, error) {
+	vol, err := BuildVolume(series, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &NIfTIVolume{Volume: vol, Affine: vol.NIfTIAffine()}, nil
+}
+
+// NIfTIAffine returns the affine that maps the volume's voxel indices to RAS+ world
+// coordinates: the DICOM LPS geometry with the x and y axes negated.
+func (v *Volume) NIfTIAffine() [4][4]float64 {
+	spacing := v.Spacing[2]
+	if spacing <= 0 {
+		// A single slice of unknown thickness
+		spacing = 1
+	}
+	step := [3]float64{v.Normal[0] * spacing, v.Normal[1] * spacing, v.Normal[2] * spacing}
+	if !v.Resampled && v.Slices > 1 {
+		// Follow the source positions exactly, as NIfTIAffine(series) does
+		first, _ := v.DataSets[0].GetFloats(tag.ImagePositionPatient)
+		last, _ := v.DataSets[len(v.DataSets)-1].GetFloats(tag.ImagePositionPatient)
+		if len(first) == 3 && len(last) == 3 {
+			step = sliceStep([3]float64(first), [3]float64(last), v.Slices)
+		}
+	}
+	return niftiAffine(v.Orientation, v.Spacing[0], v.Spacing[1], v.Origin, step)
+}
+
+// NIfTIAffine computes the NIfTI affine of a series from its geometry alone, without
+// reading pixel data.
+//
+// The slices are sorted along the normal of Image Orientation (Patient) (0020,0037)
+// of the first slice. The first three columns of the affine are the row direction
+// scaled by the column spacing, the column direction scaled by the row spacing (from
+// Pixel Spacing (0028,0030)), and the step between slices, taken from the Image
+// Position (Patient) (0020,0032) of the first and last slices; the fourth column is
+// the position of the first slice. A gantry-tilted series thus gets a sheared affine
+// that matches its slices as stored. For a single slice the step is the normal
+// scaled by Slice Thickness (0018,0050), or 1 mm if that is absent.
+//
+// DICOM patient coordinates are LPS+ (x to the patient's left, y to the posterior)
+// while NIfTI world coordinates are RAS+, so the first two rows are negated.
+//
+// Returns an error if:
+//   - The series is empty or a slice is nil
+//   - A slice lacks Image Position (Patient), or the first slice lacks Image
+//     Orientation (Patient) or Pixel Spacing
+//   - The first and last slices share a position
+//
+// Example:
+//
+//	affine, err := pixel.NIfTIAffine(series)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println("origin (RAS):", affine[0][3], affine[1][3], affine[2][3])
+//
+// NIfTI Reference:
+// https://nifti.nimh.nih.gov/nifti-1/documentation/nifti1fields/nifti1fields_pages/srow.html
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.2.1.1
+func NIfTIAffine(series []*dicom.DataSet) ([4][4]float64, error) {
+	if len(series) == 0 {
+		return [4][4]float64{}, fmt.Errorf("series is empty")
+	}
+	for i, ds := range series {
+		if ds == nil {
+			return [4][4]float64{}, fmt.Errorf("slice %d is nil", i)
+		}
+	}
+
+	ref := series[0]
+	o, err := ref.GetFloats(tag.ImageOrientationPatient)
+	if err != nil || len(o) != 6 {
+		return [4][4]float64{}, fmt.Errorf("%w: slice 0 has no valid ImageOrientationPatient", ErrMissingRequiredAttribute)
+	}
+	pixelSpacing, err := ref.GetFloats(tag.PixelSpacing)
+	if err != nil || len(pixelSpacing) != 2 {
+		return [4][4]float64{}, fmt.Errorf("%w: slice 0 has no valid PixelSpacing", ErrMissingRequiredAttribute)
+	}
+	orientation := [6]float64(o)
+	normal := [3]float64{
+		o[1]*o[5] - o[2]*o[4],
+		o[2]*o[3] - o[0]*o[5],
+		o[0]*o[4] - o[1]*o[3],
+	}
+
+	positions := make([][3]float64, len(series))
+	for i, ds := range series {
+		p, err := ds.GetFloats(tag.ImagePositionPatient)
+		if err != nil || len(p) != 3 {
+			return [4][4]float64{}, fmt.Errorf("%w: slice %d has no valid ImagePositionPatient", ErrMissingRequiredAttribute, i)
+		}
+		positions[i] = [3]float64(p)
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		return dot3(positions[i], normal) < dot3(positions[j], normal)
+	})
+
+	first, last := positions[0], positions[len(positions)-1]
+	var step [3]float64
+	if len(positions) == 1 {
+		thickness := 1.0
+		if t, err := ref.GetFloats(tag.SliceThickness); err == nil && len(t) == 1 && t[0] > 0 {
+			thickness = t[0]
+		}
+		step = [3]float64{normal[0] * thickness, normal[1] * thickness, normal[2] * thickness}
+	} else {
+		if dot3(sub3(last, first), normal) < volumeSpacingTolerance {
+			return [4][4]float64{}, fmt.Errorf("first and last slices share the position %v", first)
+		}
+		step = sliceStep(first, last, len(positions))
+	}
+
+	return niftiAffine(orientation, pixelSpacing[1], pixelSpacing[0], first, step), nil
+}
+
+// sliceStep returns the displacement between adjacent slices of n evenly spaced
+// slices running from first to last.
+func sliceStep(first, last [3]float64, n int) [3]float64 {
+	d := sub3(last, first)
+	return [3]float64{d[0] / float64(n-1), d[1] / float64(n-1), d[2] / float64(n-1)}
+}
+
+// niftiAffine builds the RAS+ affine of a voxel grid whose rows run along the first
+// three orientation cosines at columnSpacing, whose columns run along the last three
+// at rowSpacing, and whose slices are step apart, with voxel (0, 0, 0) at origin (LPS).
+func niftiAffine(orientation [6]float64, columnSpacing, rowSpacing float64, origin, step [3]float64) [4][4]float64 {
+	var affine [4][4]float64
+	for r := 0; r < 3; r++ {
+		affine[r] = [4]float64{
+			orientation[r] * columnSpacing,
+			orientation[3+r] * rowSpacing,
+			step[r],
+			origin[r],
+		}
+	}
+	// LPS+ to RAS+
+	for c := 0; c < 4; c++ {
+		affine[0][c] = -affine[0][c]
+		affine[1][c] = -affine[1][c]
+	}
+	affine[3] = [4]float64{0, 0, 0, 1}
+	return affine
+}
+
+// niftiHeader is the NIfTI-1 header, laid out so that binary.Write produces its
+// 348 bytes without padding.
+type niftiHeader struct {
+	SizeofHdr     int32
+	DataType      [10]byte
+	DBName        [18]byte
+	Extents       int32
+	SessionError  int16
+	Regular       byte
+	DimInfo       byte
+	Dim           [8]int16
+	IntentP1      float32
+	IntentP2      float32
+	IntentP3      float32
+	IntentCode    int16
+	Datatype      int16
+	Bitpix        int16
+	SliceStart    int16
+	Pixdim        [8]float32
+	VoxOffset     float32
+	SclSlope      float32
+	SclInter      float32
+	SliceEnd      int16
+	SliceCode     byte
+	XYZTUnits     byte
+	CalMax        float32
+	CalMin        float32
+	SliceDuration float32
+	TOffset       float32
+	GLMax         int32
+	GLMin         int32
+	Descrip       [80]byte
+	AuxFile       [24]byte
+	QformCode     int16
+	SformCode     int16
+	QuaternB      float32
+	QuaternC      float32
+	QuaternD      float32
+	QOffsetX      float32
+	QOffsetY      float32
+	QOffsetZ      float32
+	SrowX         [4]float32
+	SrowY         [4]float32
+	SrowZ         [4]float32
+	IntentName    [16]byte
+	Magic         [4]byte
+}
+
+// MarshalBinary encodes the volume as a single-file NIfTI-1 image (.nii): a
+// little-endian header, an empty extension flag and the voxels as 32-bit floats.
+//
+// The geometry is stored in the sform (sform_code 1, scanner anatomical) with the
+// voxel spacing in pixdim; the qform is left unset (qform_code 0), which readers
+// handle by using the sform.
+//
+// Returns an error if the volume has no voxels or a dimension exceeds the NIfTI-1
+// limit of 32767.
+//
+// Example:
+//
+//	data, err := nv.MarshalBinary()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = os.WriteFile("volume.nii", data, 0o644)
+//
+// NIfTI Reference:
+// https://nifti.nimh.nih.gov/pub/dist/src/niftilib/nifti1.h
+func (n *NIfTIVolume) MarshalBinary() ([]byte, error) {
+	if n.Volume == nil || len(n.Voxels) == 0 {
+		return nil, fmt.Errorf("volume has no voxels")
+	}
+	for _, d := range []int{n.Columns, n.Rows, n.Slices} {
+		if d < 1 || d > math.MaxInt16 {
+			return nil, fmt.Errorf("volume dimension %d is outside the NIfTI-1 range 1-%d", d, math.MaxInt16)
+		}
+	}
+	if len(n.Voxels) != n.Columns*n.Rows*n.Slices {
+		return nil, fmt.Errorf("volume has %d voxels, expected %d", len(n.Voxels), n.Columns*n.Rows*n.Slices)
+	}
+
+	hdr := niftiHeader{
+		SizeofHdr: niftiHeaderSize,
+		Regular:   'r',
+		Dim:       [8]int16{3, int16(n.Columns), int16(n.Rows), int16(n.Slices), 1, 1, 1, 1},
+		Datatype:  niftiFloat32,
+		Bitpix:    32,
+		Pixdim: [8]float32{1, float32(n.Spacing[0]), float32(n.Spacing[1]), float32(n.Spacing[2]),
+			0, 0, 0, 0},
+		VoxOffset: niftiVoxOffset,
+		SclSlope:  1,
+		XYZTUnits: niftiUnitsMM,
+		SformCode: niftiXformScanner,
+		Magic:     [4]byte{'n', '+', '1', 0},
+	}
+	for c := 0; c < 4; c++ {
+		hdr.SrowX[c] = float32(n.Affine[0][c])
+		hdr.SrowY[c] = float32(n.Affine[1][c])
+		hdr.SrowZ[c] = float32(n.Affine[2][c])
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(niftiVoxOffset + 4*len(n.Voxels))
+	if err := binary.Write(&buf, binary.LittleEndian, &hdr); err != nil {
+		return nil, fmt.Errorf("failed to write NIfTI header: %w", err)
+	}
+	buf.Write([]byte{0, 0, 0, 0}) // no extensions
+	if err := binary.Write(&buf, binary.LittleEndian, n.Voxels); err != nil {
+		return nil, fmt.Errorf("failed to write NIfTI voxels: %w", err)
+	}
+	return buf.Bytes(), nil
+}
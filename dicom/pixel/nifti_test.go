@@ -0,0 +1,188 @@
+package pixel
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// applyAffine maps voxel index (i, j, k) through affine.
+func applyAffine(affine [4][4]float64, i, j, k float64) [3]float64 {
+	var out [3]float64
+	for r := range 3 {
+		out[r] = affine[r][0]*i + affine[r][1]*j + affine[r][2]*k + affine[r][3]
+	}
+	return out
+}
+
+// floatStrings formats values for a DS element.
+func floatStrings(values []float64) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = fmt.Sprint(v)
+	}
+	return out
+}
+
+// assertRAS checks that lps, a DICOM patient position, is ras with x and y negated.
+func assertRAS(t *testing.T, lps [3]float64, ras [3]float64) {
+	t.Helper()
+	assert.InDelta(t, -lps[0], ras[0], 1e-9)
+	assert.InDelta(t, -lps[1], ras[1], 1e-9)
+	assert.InDelta(t, lps[2], ras[2], 1e-9)
+}
+
+func TestNIfTIAffine_Axial(t *testing.T) {
+	// Out of order along z; row spacing 0.5, column spacing 1
+	series := []*dicom.DataSet{
+		newVolumeSlice(t, [3]float64{-10, -20, 4}, uniformSlice(0)),
+		newVolumeSlice(t, [3]float64{-10, -20, 0}, uniformSlice(0)),
+		newVolumeSlice(t, [3]float64{-10, -20, 2}, uniformSlice(0)),
+	}
+
+	affine, err := NIfTIAffine(series)
+	require.NoError(t, err)
+	assert.Equal(t, [4][4]float64{
+		{-1, 0, 0, 10},
+		{0, -0.5, 0, 20},
+		{0, 0, 2, 0},
+		{0, 0, 0, 1},
+	}, affine)
+
+	nv, err := ToNIfTI(series)
+	require.NoError(t, err)
+	assert.Equal(t, affine, nv.Affine)
+}
+
+func TestNIfTIAffine_Oblique(t *testing.T) {
+	// Sagittal slices (rows along posterior, columns toward the feet), stacked
+	// along -x, in plane rotated about x by the angle whose cosine is 0.8
+	c, s := 0.8, 0.6
+	orientation := []float64{0, c, s, 0, s, -c}
+	spacing := []float64{0.8, 0.6} // row spacing, column spacing
+	var series []*dicom.DataSet
+	origin := [3]float64{30, -100, 50}
+	for k := range 4 {
+		p := [3]float64{origin[0] - 1.5*float64(k), origin[1], origin[2]}
+		ds := newVolumeSlice(t, p, uniformSlice(0))
+		addGSPSString(t, ds, tag.ImageOrientationPatient, vr.DecimalString, floatStrings(orientation)...)
+		addGSPSString(t, ds, tag.PixelSpacing, vr.DecimalString, floatStrings(spacing)...)
+		series = append([]*dicom.DataSet{ds}, series...)
+	}
+
+	affine, err := NIfTIAffine(series)
+	require.NoError(t, err)
+
+	// Every voxel maps to its DICOM patient position, flipped to RAS
+	for k := range 4 {
+		for j := range 2 {
+			for i := range 3 {
+				var lps [3]float64
+				for r := range 3 {
+					lps[r] = origin[r] + float64(i)*spacing[1]*orientation[r] + float64(j)*spacing[0]*orientation[3+r]
+				}
+				lps[0] -= 1.5 * float64(k)
+				assertRAS(t, lps, applyAffine(affine, float64(i), float64(j), float64(k)))
+			}
+		}
+	}
+
+	nv, err := ToNIfTI(series)
+	require.NoError(t, err)
+	for r := range 4 {
+		for col := range 4 {
+			assert.InDelta(t, affine[r][col], nv.Affine[r][col], 1e-9)
+		}
+	}
+}
+
+func TestNIfTIAffine_GantryTilt(t *testing.T) {
+	// Each slice is shifted 1 mm along y: the affine shears to follow the slices
+	var series []*dicom.DataSet
+	for k := range 3 {
+		series = append(series, newVolumeSlice(t, [3]float64{0, float64(k), float64(2 * k)}, uniformSlice(0)))
+	}
+	affine, err := NIfTIAffine(series)
+	require.NoError(t, err)
+	assert.Equal(t, [4]float64{0, -1, 2, 0}, [4]float64{affine[0][2], affine[1][2], affine[2][2], affine[3][2]})
+
+	// The resampled volume is stacked along the normal instead
+	nv, err := ToNIfTI(series, WithResampling())
+	require.NoError(t, err)
+	assert.Equal(t, [4]float64{0, 0, 2, 0}, [4]float64{nv.Affine[0][2], nv.Affine[1][2], nv.Affine[2][2], nv.Affine[3][2]})
+}
+
+func TestNIfTIAffine_SingleSlice(t *testing.T) {
+	ds := newVolumeSlice(t, [3]float64{1, 2, 3}, uniformSlice(0))
+	affine, err := NIfTIAffine([]*dicom.DataSet{ds})
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, affine[2][2], "1 mm without Slice Thickness")
+
+	addGSPSString(t, ds, tag.SliceThickness, vr.DecimalString, "2.5")
+	affine, err = NIfTIAffine([]*dicom.DataSet{ds})
+	require.NoError(t, err)
+	assert.Equal(t, [4]float64{-1, -2, 3, 1}, [4]float64{affine[0][3], affine[1][3], affine[2][3], affine[3][3]})
+	assert.Equal(t, 2.5, affine[2][2])
+}
+
+func TestNIfTIAffine_Errors(t *testing.T) {
+	_, err := NIfTIAffine(nil)
+	assert.ErrorContains(t, err, "series is empty")
+
+	_, err = NIfTIAffine([]*dicom.DataSet{nil})
+	assert.ErrorContains(t, err, "slice 0 is nil")
+
+	ds := newVolumeSlice(t, [3]float64{0, 0, 0}, uniformSlice(0))
+	require.NoError(t, ds.Remove(tag.PixelSpacing))
+	_, err = NIfTIAffine([]*dicom.DataSet{ds})
+	assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+
+	_, err = NIfTIAffine([]*dicom.DataSet{
+		newVolumeSlice(t, [3]float64{0, 0, 0}, uniformSlice(0)),
+		newVolumeSlice(t, [3]float64{0, 0, 0}, uniformSlice(0)),
+	})
+	assert.ErrorContains(t, err, "share the position")
+}
+
+func TestNIfTIVolume_MarshalBinary(t *testing.T) {
+	series := []*dicom.DataSet{
+		newVolumeSlice(t, [3]float64{-10, -20, 0}, []int16{1024, 1025, 1026, 1027, 1028, 1029}),
+		newVolumeSlice(t, [3]float64{-10, -20, 2}, uniformSlice(1034)),
+	}
+	nv, err := ToNIfTI(series)
+	require.NoError(t, err)
+
+	data, err := nv.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, 352+4*12)
+
+	le := binary.LittleEndian
+	f32 := func(off int) float32 { return math.Float32frombits(le.Uint32(data[off:])) }
+	assert.Equal(t, uint32(348), le.Uint32(data[0:]), "sizeof_hdr")
+	assert.Equal(t, []uint16{3, 3, 2, 2}, []uint16{le.Uint16(data[40:]), le.Uint16(data[42:]), le.Uint16(data[44:]), le.Uint16(data[46:])}, "dim")
+	assert.Equal(t, uint16(16), le.Uint16(data[70:]), "datatype")
+	assert.Equal(t, uint16(32), le.Uint16(data[72:]), "bitpix")
+	assert.Equal(t, []float32{1, 1, 0.5, 2}, []float32{f32(76), f32(80), f32(84), f32(88)}, "pixdim")
+	assert.Equal(t, float32(352), f32(108), "vox_offset")
+	assert.Equal(t, byte(2), data[123], "xyzt_units")
+	assert.Equal(t, uint16(0), le.Uint16(data[252:]), "qform_code")
+	assert.Equal(t, uint16(1), le.Uint16(data[254:]), "sform_code")
+	assert.Equal(t, []float32{-1, 0, 0, 10}, []float32{f32(280), f32(284), f32(288), f32(292)}, "srow_x")
+	assert.Equal(t, []float32{0, -0.5, 0, 20}, []float32{f32(296), f32(300), f32(304), f32(308)}, "srow_y")
+	assert.Equal(t, []float32{0, 0, 2, 0}, []float32{f32(312), f32(316), f32(320), f32(324)}, "srow_z")
+	assert.Equal(t, []byte("n+1\x00"), data[344:348], "magic")
+
+	assert.Equal(t, float32(0), f32(352))
+	assert.Equal(t, float32(5), f32(352+4*5))
+	assert.Equal(t, float32(10), f32(352+4*11))
+
+	_, err = (&NIfTIVolume{}).MarshalBinary()
+	assert.Error(t, err)
+}
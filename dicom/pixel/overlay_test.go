@@ -0,0 +1,78 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlay_AtAndCount(t *testing.T) {
+	o := &Overlay{Rows: 2, Columns: 2, NumberOfFrames: 2, Data: []byte{1, 0, 0, 1, 0, 0, 1, 0}}
+
+	assert.True(t, o.At(0, 0, 0))
+	assert.False(t, o.At(0, 0, 1))
+	assert.True(t, o.At(1, 1, 0))
+	assert.False(t, o.At(2, 0, 0))
+	assert.False(t, o.At(0, -1, 0))
+	assert.Equal(t, 3, o.Count())
+}
+
+func TestRenderOverlays(t *testing.T) {
+	pd, err := NewPixelDataFromUint8([]uint8{10, 20, 30, 40, 50, 60}, 3, 2)
+	require.NoError(t, err)
+
+	t.Run("aligned", func(t *testing.T) {
+		o := &Overlay{Rows: 2, Columns: 3, Origin: [2]int{1, 1}, Data: []byte{1, 0, 0, 0, 0, 1}}
+		out, err := RenderOverlays(pd, o)
+		require.NoError(t, err)
+		assert.Equal(t, []uint8{255, 20, 30, 40, 50, 255}, out.Array())
+		// Input is unchanged
+		assert.Equal(t, []uint8{10, 20, 30, 40, 50, 60}, pd.Array())
+	})
+
+	t.Run("origin offset and clipping", func(t *testing.T) {
+		o := &Overlay{Rows: 2, Columns: 2, Origin: [2]int{2, 3}, Data: []byte{1, 1, 1, 1}}
+		out, err := RenderOverlays(pd, o)
+		require.NoError(t, err)
+		assert.Equal(t, []uint8{10, 20, 30, 40, 50, 255}, out.Array())
+	})
+
+	t.Run("MONOCHROME1 uses minimum value", func(t *testing.T) {
+		mono1, err := NewPixelDataFromUint8([]uint8{10, 20}, 2, 1)
+		require.NoError(t, err)
+		mono1.PhotometricInterpretation = "MONOCHROME1"
+		o := &Overlay{Rows: 1, Columns: 2, Origin: [2]int{1, 1}, Data: []byte{0, 1}}
+		out, err := RenderOverlays(mono1, o)
+		require.NoError(t, err)
+		assert.Equal(t, []uint8{10, 0}, out.Array())
+	})
+
+	t.Run("16-bit signed", func(t *testing.T) {
+		ct, err := NewPixelDataFromInt16([]int16{-1000, 0}, 2, 1)
+		require.NoError(t, err)
+		o := &Overlay{Rows: 1, Columns: 2, Origin: [2]int{1, 1}, Data: []byte{1, 0}}
+		out, err := RenderOverlays(ct, o)
+		require.NoError(t, err)
+		assert.Equal(t, []int16{32767, 0}, out.Array())
+	})
+}
+
+func TestRenderOverlays_Errors(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{Rows: 2, Columns: 2, NumberOfFrames: 3, BitsAllocated: 8})
+	rgb := NewSyntheticPixelData(SyntheticOptions{Rows: 2, Columns: 2, PhotometricInterpretation: "RGB"})
+
+	_, err := RenderOverlays(nil)
+	assert.Error(t, err)
+
+	_, err = RenderOverlays(rgb)
+	assert.Error(t, err)
+
+	// Frame count mismatch
+	_, err = RenderOverlays(pd, &Overlay{Rows: 2, Columns: 2, NumberOfFrames: 2, Data: make([]byte, 8)})
+	assert.Error(t, err)
+
+	// Short overlay data
+	_, err = RenderOverlays(pd, &Overlay{Rows: 2, Columns: 2, Data: make([]byte, 2)})
+	assert.Error(t, err)
+}
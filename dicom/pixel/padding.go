@@ -0,0 +1,188 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// PaddingMask reports which pixels of pd are padding, as marked by Pixel Padding
+// Value (0028,0120) and Pixel Padding Range Limit (0028,0121) of ds.
+//
+// Padding marks pixels outside the reconstructed field of view, such as the corners
+// of a circular CT image, which carry no image information. A pixel is padding when
+// its stored value equals Pixel Padding Value or, if Pixel Padding Range Limit is
+// present, lies between the two values inclusive. Values are compared as stored,
+// before the Modality LUT.
+//
+// The mask has one entry per pixel of every frame, in the order of pd's data. It is
+// nil if ds has no Pixel Padding Value.
+//
+// Returns an error if pd is not single-sample (grayscale) or the padding attributes
+// are not valid US or SS values.
+//
+// Example:
+//
+//	mask, err := pixel.PaddingMask(ds, pd)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for i, padded := range mask {
+//	    if padded {
+//	        // pixel i is outside the field of view
+//	    }
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.5.1.1.2
+func PaddingMask(ds *dicom.DataSet, pd *PixelData) ([]bool, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+	if pd == nil {
+		return nil, fmt.Errorf("pixel data is nil")
+	}
+	if !ds.Contains(tag.PixelPaddingValue) {
+		return nil, nil
+	}
+	if pd.SamplesPerPixel != 1 {
+		return nil, fmt.Errorf("pixel padding only applies to grayscale images (SamplesPerPixel=1), got %d",
+			pd.SamplesPerPixel)
+	}
+	if pd.BitsAllocated != 8 && pd.BitsAllocated != 16 {
+		return nil, fmt.Errorf("pixel padding requires 8 or 16-bit data, got %d", pd.BitsAllocated)
+	}
+
+	low, err := ds.GetPixelValueStat(tag.PixelPaddingValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", tag.PixelPaddingValue, err)
+	}
+	high := low
+	if ds.Contains(tag.PixelPaddingRangeLimit) {
+		limit, err := ds.GetPixelValueStat(tag.PixelPaddingRangeLimit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", tag.PixelPaddingRangeLimit, err)
+		}
+		low, high = min(low, limit), max(low, limit)
+	}
+
+	values := storedValues(pd)
+	mask := make([]bool, len(values))
+	for i, v := range values {
+		mask[i] = v >= low && v <= high
+	}
+	return mask, nil
+}
+
+// PixelStatistics summarizes the stored values of the pixels of an image that are
+// not padding.
+type PixelStatistics struct {
+	Min    int64   // Smallest stored value
+	Max    int64   // Largest stored value
+	Mean   float64 // Mean stored value
+	StdDev float64 // Population standard deviation of the stored values
+	Count  int     // Number of pixels included
+	Padded int     // Number of padding pixels excluded
+}
+
+// Statistics computes the minimum, maximum, mean and standard deviation of the
+// stored values of pd over every frame, excluding the padding pixels identified by
+// PaddingMask. ds may be nil to include every pixel.
+//
+// Returns an error if pd is not 8 or 16-bit grayscale, or if every pixel is padding.
+//
+// Example:
+//
+//	stats, err := pixel.Statistics(ds, pd)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("range %d to %d, %d padding pixels\n", stats.Min, stats.Max, stats.Padded)
+func Statistics(ds *dicom.DataSet, pd *PixelData) (*PixelStatistics, error) {
+	if pd == nil {
+		return nil, fmt.Errorf("pixel data is nil")
+	}
+	if pd.SamplesPerPixel != 1 {
+		return nil, fmt.Errorf("statistics only apply to grayscale images (SamplesPerPixel=1), got %d",
+			pd.SamplesPerPixel)
+	}
+	if pd.BitsAllocated != 8 && pd.BitsAllocated != 16 {
+		return nil, fmt.Errorf("statistics require 8 or 16-bit data, got %d", pd.BitsAllocated)
+	}
+	var mask []bool
+	if ds != nil {
+		var err error
+		if mask, err = PaddingMask(ds, pd); err != nil {
+			return nil, err
+		}
+	}
+
+	stats := &PixelStatistics{Min: math.MaxInt64, Max: math.MinInt64}
+	var sum, sumSquares float64
+	for i, v := range storedValues(pd) {
+		if mask != nil && mask[i] {
+			stats.Padded++
+			continue
+		}
+		stats.Count++
+		stats.Min = min(stats.Min, v)
+		stats.Max = max(stats.Max, v)
+		sum += float64(v)
+		sumSquares += float64(v) * float64(v)
+	}
+	if stats.Count == 0 {
+		return nil, fmt.Errorf("no pixels outside the padding (%d padding pixels)", stats.Padded)
+	}
+
+	n := float64(stats.Count)
+	stats.Mean = sum / n
+	stats.StdDev = math.Sqrt(max(sumSquares/n-stats.Mean*stats.Mean, 0))
+	return stats, nil
+}
+
+// AutoWindow returns a window spanning the values of the image pixels of pd, ignoring
+// padding, so that padded corners do not stretch the window and wash out the image.
+//
+// The stored minimum and maximum from Statistics are converted with the Modality LUT
+// of ds (Rescale Slope and Intercept), so the window applies to modality values as in
+// ApplyFullImagePipeline. The width is at least 1.
+//
+// Returns an error if Statistics fails.
+//
+// Example:
+//
+//	wl, err := pixel.AutoWindow(ds, pd)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	display, err := pixel.ApplyWindowLevel(rescaled, wl.WindowCenter, wl.WindowWidth, 8)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.11.2.1.2
+func AutoWindow(ds *dicom.DataSet, pd *PixelData) (*WindowLevel, error) {
+	stats, err := Statistics(ds, pd)
+	if err != nil {
+		return nil, err
+	}
+
+	return windowForRange(ds, float64(stats.Min), float64(stats.Max)), nil
+}
+
+// windowForRange returns the window spanning the stored values low to high after
+// the Modality LUT of ds, with a width of at least 1.
+func windowForRange(ds *dicom.DataSet, low, high float64) *WindowLevel {
+	if ds != nil {
+		if lut, err := ExtractModalityLUTFromDataSet(ds); err == nil {
+			low = low*lut.RescaleSlope + lut.RescaleIntercept
+			high = high*lut.RescaleSlope + lut.RescaleIntercept
+			low, high = min(low, high), max(low, high)
+		}
+	}
+
+	return &WindowLevel{
+		WindowCenter: (low + high) / 2,
+		WindowWidth:  max(high-low, 1),
+	}
+}
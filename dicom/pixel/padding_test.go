@@ -0,0 +1,114 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPaddedCT returns a signed 4x2 CT image whose two left columns are padding
+// (-2000), with Pixel Padding Value -2000 and Rescale Intercept -1024.
+func newPaddedCT(t *testing.T) (*dicom.DataSet, *PixelData) {
+	pd, err := NewPixelDataFromInt16([]int16{
+		-2000, -2000, 1000, 1100,
+		-2000, -2000, 1200, 1300,
+	}, 4, 2)
+	require.NoError(t, err)
+
+	ds := dicom.NewDataSet()
+	require.NoError(t, setUint16Element(ds, tag.PixelRepresentation, 1))
+	addSignedShort(t, ds, tag.PixelPaddingValue, -2000)
+	addGSPSString(t, ds, tag.RescaleIntercept, vr.DecimalString, "-1024")
+	addGSPSString(t, ds, tag.RescaleSlope, vr.DecimalString, "1")
+	return ds, pd
+}
+
+func addSignedShort(t *testing.T, ds *dicom.DataSet, tg tag.Tag, n int64) {
+	val, err := value.NewIntValue(vr.SignedShort, []int64{n})
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, vr.SignedShort, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+func TestPaddingMask(t *testing.T) {
+	ds, pd := newPaddedCT(t)
+
+	mask, err := PaddingMask(ds, pd)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true, false, false, true, true, false, false}, mask)
+
+	t.Run("range limit", func(t *testing.T) {
+		addSignedShort(t, ds, tag.PixelPaddingRangeLimit, 1100)
+		mask, err := PaddingMask(ds, pd)
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, true, true, true, true, true, false, false}, mask)
+		require.NoError(t, ds.Remove(tag.PixelPaddingRangeLimit))
+	})
+
+	t.Run("no padding", func(t *testing.T) {
+		mask, err := PaddingMask(dicom.NewDataSet(), pd)
+		require.NoError(t, err)
+		assert.Nil(t, mask)
+	})
+
+	t.Run("colour", func(t *testing.T) {
+		rgb, err := NewPixelDataFromRGB([]byte{1, 2, 3}, 1, 1)
+		require.NoError(t, err)
+		_, err = PaddingMask(ds, rgb)
+		assert.Error(t, err)
+	})
+}
+
+func TestStatistics(t *testing.T) {
+	ds, pd := newPaddedCT(t)
+
+	stats, err := Statistics(ds, pd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), stats.Min)
+	assert.Equal(t, int64(1300), stats.Max)
+	assert.InDelta(t, 1150, stats.Mean, 1e-9)
+	assert.InDelta(t, 111.8034, stats.StdDev, 1e-4)
+	assert.Equal(t, 4, stats.Count)
+	assert.Equal(t, 4, stats.Padded)
+
+	all, err := Statistics(nil, pd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-2000), all.Min)
+	assert.Equal(t, 8, all.Count)
+
+	addSignedShort(t, ds, tag.PixelPaddingRangeLimit, 2000)
+	_, err = Statistics(ds, pd)
+	assert.Error(t, err, "every pixel is padding")
+}
+
+func TestAutoWindow(t *testing.T) {
+	ds, pd := newPaddedCT(t)
+
+	wl, err := AutoWindow(ds, pd)
+	require.NoError(t, err)
+	assert.InDelta(t, 150-1024+1000, wl.WindowCenter, 1e-9)
+	assert.InDelta(t, 300, wl.WindowWidth, 1e-9)
+}
+
+func TestApplyFullImagePipeline_PaddingBackground(t *testing.T) {
+	ds, pd := newPaddedCT(t)
+	addGSPSString(t, ds, tag.WindowCenter, vr.DecimalString, "-2000")
+	addGSPSString(t, ds, tag.WindowWidth, vr.DecimalString, "100")
+
+	// Without the option the padding is mapped like any other pixel
+	plain, err := ApplyFullImagePipeline(ds, pd, 8)
+	require.NoError(t, err)
+	assert.Equal(t, byte(0), plain.data[0])
+
+	display, err := ApplyFullImagePipeline(ds, pd, 8, WithPaddingBackground(7))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{7, 7, 255, 255, 7, 7, 255, 255}, display.data)
+	assert.Equal(t, byte(0), plain.data[0], "the unpadded result is not modified")
+}
@@ -31,6 +31,9 @@ type PixelData struct {
 
 	// Transfer syntax
 	TransferSyntaxUID string // Transfer syntax used for decompression
+
+	// Units of the pixel values after a modality LUT (e.g. "HU"), if known
+	Units string
 }
 
 // Frame represents a single frame from a multi-frame pixel data.
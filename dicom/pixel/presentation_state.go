@@ -0,0 +1,485 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// GraphicPoint is a 2D annotation coordinate.
+//
+// For PIXEL units X is the column and Y the row, with (1,1) at the top-left corner of
+// the top-left pixel. For DISPLAY units both are fractions of the displayed area (0.0-1.0).
+type GraphicPoint struct {
+	X, Y float64
+}
+
+// Graphic is a graphic or text annotation from a Grayscale Softcopy Presentation State.
+//
+// Graphic objects carry Type POINT, POLYLINE, INTERPOLATED, CIRCLE or ELLIPSE and their
+// points. Text objects carry Type "TEXT", the text, and optionally a bounding box
+// (two points: top-left and bottom-right) and an anchor point.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.10.5
+type Graphic struct {
+	Layer  string         // (0070,0002) Graphic Layer
+	Type   string         // (0070,0023) Graphic Type, or "TEXT" for text objects
+	Units  string         // PIXEL or DISPLAY
+	Points []GraphicPoint // Graphic Data, or the text bounding box corners
+	Filled bool           // (0070,0024) Graphic Filled
+
+	Text               string        // (0070,0006) Unformatted Text Value
+	Anchor             *GraphicPoint // (0070,0014) Anchor Point, if present
+	AnchorUnits        string        // (0070,0004) Anchor Point Annotation Units
+	AnchorPointVisible bool          // (0070,0015) Anchor Point Visibility
+}
+
+// ApplyPresentationState renders an image as described by a Grayscale Softcopy
+// Presentation State (GSPS) and returns the annotations that apply to it.
+//
+// The image's SOP Instance UID must be listed in the GSPS Referenced Series Sequence
+// (0008,1115). The grayscale pipeline is then applied to the stored values:
+//  1. Modality LUT: Rescale Slope/Intercept from the GSPS, falling back to the image
+//  2. Softcopy VOI LUT (0028,3110): window center/width or a VOI LUT table, from the
+//     first item that applies to the image; falls back to the image's window, then
+//     to the full range of the data
+//  3. Presentation LUT Shape (2050,0020): IDENTITY or INVERSE. If absent, MONOCHROME1
+//     images are inverted
+//
+// The result is 8-bit unsigned MONOCHROME2 P-Values ready for display. Spatial
+// transformations (rotation, flip, displayed area) are not applied; callers receive
+// annotation coordinates in the units recorded in the GSPS.
+//
+// Graphic Annotation Sequence items restricted to other images via their own
+// Referenced Image Sequence are skipped.
+//
+// Example:
+//
+//	image, _ := dicom.ParseFile("ct.dcm")
+//	gsps, _ := dicom.ParseFile("ct_gsps.dcm")
+//	pd, _ := pixel.Extract(image)
+//	display, graphics, err := pixel.ApplyPresentationState(image, gsps, pd)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, g := range graphics {
+//	    fmt.Println(g.Layer, g.Type, g.Points)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part04.html#sect_N.2
+func ApplyPresentationState(imageDS, gspsDS *dicom.DataSet, pd *PixelData) (*PixelData, []Graphic, error) {
+	if imageDS == nil || gspsDS == nil {
+		return nil, nil, fmt.Errorf("image and presentation state datasets are required")
+	}
+	if pd == nil {
+		return nil, nil, fmt.Errorf("pixel data is nil")
+	}
+	if pd.SamplesPerPixel != 1 {
+		return nil, nil, fmt.Errorf("presentation state only applies to grayscale images (SamplesPerPixel=1), got %d",
+			pd.SamplesPerPixel)
+	}
+	if pd.BitsAllocated != 8 && pd.BitsAllocated != 16 {
+		return nil, nil, fmt.Errorf("presentation state supports BitsAllocated 8 or 16, got %d", pd.BitsAllocated)
+	}
+
+	sopInstanceUID := datasetString(imageDS, tag.SOPInstanceUID)
+	if sopInstanceUID == "" {
+		return nil, nil, fmt.Errorf("%w: SOP Instance UID", ErrMissingRequiredAttribute)
+	}
+	if !presentationStateReferences(gspsDS, datasetString(imageDS, tag.SeriesInstanceUID), sopInstanceUID) {
+		return nil, nil, fmt.Errorf("presentation state does not reference image %s", sopInstanceUID)
+	}
+
+	// Step 1: Modality LUT
+	modalitySource := imageDS
+	if gspsDS.Contains(tag.RescaleSlope) || gspsDS.Contains(tag.RescaleIntercept) {
+		modalitySource = gspsDS
+	}
+	modality, err := ExtractModalityLUTFromDataSet(modalitySource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := storedValues(pd)
+	modalityValues := make([]float64, len(values))
+	for i, v := range values {
+		modalityValues[i] = modality.RescaleSlope*float64(v) + modality.RescaleIntercept
+	}
+
+	// Step 2: VOI LUT
+	voi, err := selectSoftcopyVOI(gspsDS, sopInstanceUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := make([]byte, len(modalityValues))
+	switch {
+	case voi != nil && len(voi.LUTData) > 0:
+		applyVOILUTTable(modalityValues, voi, data)
+
+	default:
+		var center, width float64
+		if voi != nil && voi.WindowCenter != nil && voi.WindowWidth != nil {
+			center, width = *voi.WindowCenter, *voi.WindowWidth
+		} else if wl, err := ExtractWindowLevelFromDataSet(imageDS); err == nil {
+			center, width = wl.WindowCenter, wl.WindowWidth
+		} else {
+			center, width = fullRangeWindow(modalityValues)
+		}
+		if width <= 0 {
+			return nil, nil, fmt.Errorf("window width must be positive, got %f", width)
+		}
+		lower, upper := center-width/2, center+width/2
+		for i, v := range modalityValues {
+			data[i] = uint8(math.Round(applyWindowLevelValue(v, lower, upper, 255)))
+		}
+	}
+
+	// Step 3: Presentation LUT
+	invert := pd.PhotometricInterpretation == "MONOCHROME1"
+	if shape := datasetString(gspsDS, tag.PresentationLUTShape); shape != "" {
+		switch shape {
+		case "IDENTITY":
+			invert = false
+		case "INVERSE":
+			invert = true
+		default:
+			return nil, nil, fmt.Errorf("unsupported Presentation LUT shape: %s", shape)
+		}
+	}
+	if invert {
+		for i := range data {
+			data[i] = 255 - data[i]
+		}
+	}
+
+	result := &PixelData{
+		Rows:                      pd.Rows,
+		Columns:                   pd.Columns,
+		BitsAllocated:             8,
+		BitsStored:                8,
+		HighBit:                   7,
+		PixelRepresentation:       0,
+		SamplesPerPixel:           1,
+		PhotometricInterpretation: "MONOCHROME2",
+		PlanarConfiguration:       0,
+		NumberOfFrames:            pd.NumberOfFrames,
+		data:                      data,
+		TransferSyntaxUID:         pd.TransferSyntaxUID,
+	}
+
+	graphics, err := extractGraphics(gspsDS, sopInstanceUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, graphics, nil
+}
+
+// presentationStateReferences reports whether the GSPS Referenced Series Sequence
+// lists the given image. The series UID is only compared when both are present.
+func presentationStateReferences(gspsDS *dicom.DataSet, seriesUID, sopInstanceUID string) bool {
+	seriesItems, err := gspsDS.GetSequenceItems(tag.ReferencedSeriesSequence)
+	if err != nil {
+		return false
+	}
+	for _, series := range seriesItems {
+		if s := datasetString(series, tag.SeriesInstanceUID); s != "" && seriesUID != "" && s != seriesUID {
+			continue
+		}
+		if referencesImage(series, sopInstanceUID) {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesImage reports whether the Referenced Image Sequence of ds lists the image.
+func referencesImage(ds *dicom.DataSet, sopInstanceUID string) bool {
+	images, err := ds.GetSOPReferences(tag.ReferencedImageSequence, dicom.ReferenceParseOptions{
+		AllowMissingUIDs:    true,
+		IgnoreInvalidFrames: true,
+	})
+	if err != nil {
+		return false
+	}
+	for _, image := range images {
+		if image.SOPInstanceUID == sopInstanceUID {
+			return true
+		}
+	}
+	return false
+}
+
+// appliesToImage reports whether a GSPS item applies to the image: items without
+// their own Referenced Image Sequence apply to every referenced image.
+func appliesToImage(item *dicom.DataSet, sopInstanceUID string) bool {
+	if !item.Contains(tag.ReferencedImageSequence) {
+		return true
+	}
+	return referencesImage(item, sopInstanceUID)
+}
+
+// selectSoftcopyVOI returns the first Softcopy VOI LUT Sequence item that applies
+// to the image, or nil if there is none.
+func selectSoftcopyVOI(gspsDS *dicom.DataSet, sopInstanceUID string) (*VOILUT, error) {
+	items, err := gspsDS.GetSequenceItems(tag.SoftcopyVOILUTSequence)
+	if err != nil {
+		return nil, nil
+	}
+
+	for _, item := range items {
+		if !appliesToImage(item, sopInstanceUID) {
+			continue
+		}
+
+		voi := &VOILUT{}
+		if wl, err := ExtractWindowLevelFromDataSet(item); err == nil {
+			voi.WindowCenter = &wl.WindowCenter
+			voi.WindowWidth = &wl.WindowWidth
+			return voi, nil
+		}
+
+		lutItems, err := item.GetSequenceItems(tag.VOILUTSequence)
+		if err != nil || len(lutItems) == 0 {
+			return nil, fmt.Errorf("softcopy VOI LUT item has neither a window nor a VOI LUT Sequence")
+		}
+		if err := readLUT(lutItems[0], voi); err != nil {
+			return nil, fmt.Errorf("invalid VOI LUT: %w", err)
+		}
+		return voi, nil
+	}
+
+	return nil, nil
+}
+
+// readLUT reads LUT Descriptor (0028,3002), LUT Data (0028,3006) and LUT
+// Explanation (0028,3003) into voi.
+func readLUT(item *dicom.DataSet, voi *VOILUT) error {
+	descElem, err := item.Get(tag.LUTDescriptor)
+	if err != nil {
+		return fmt.Errorf("missing LUT Descriptor: %w", err)
+	}
+	desc, ok := descElem.Value().(*value.IntValue)
+	if !ok || len(desc.Ints()) != 3 {
+		return fmt.Errorf("LUT Descriptor must hold 3 integer values")
+	}
+	for i, v := range desc.Ints() {
+		voi.LUTDescriptor[i] = uint16(v)
+	}
+
+	dataElem, err := item.Get(tag.LUTData)
+	if err != nil {
+		return fmt.Errorf("missing LUT Data: %w", err)
+	}
+	switch v := dataElem.Value().(type) {
+	case *value.IntValue:
+		for _, entry := range v.Ints() {
+			voi.LUTData = append(voi.LUTData, uint16(entry))
+		}
+	default:
+		raw := v.Bytes()
+		for i := 0; i+1 < len(raw); i += 2 {
+			voi.LUTData = append(voi.LUTData, uint16(raw[i])|uint16(raw[i+1])<<8)
+		}
+	}
+
+	numEntries := int(voi.LUTDescriptor[0])
+	if numEntries == 0 {
+		numEntries = 65536
+	}
+	if len(voi.LUTData) < numEntries {
+		return fmt.Errorf("LUT Data has %d entries, descriptor requires %d", len(voi.LUTData), numEntries)
+	}
+	voi.LUTData = voi.LUTData[:numEntries]
+
+	voi.LUTExplanation = datasetString(item, tag.New(0x0028, 0x3003))
+
+	return nil
+}
+
+// applyVOILUTTable maps modality values through a VOI LUT table to 8-bit output.
+// Values outside the table are clamped to the first or last entry.
+func applyVOILUTTable(values []float64, voi *VOILUT, out []byte) {
+	firstMapped := int(int16(voi.LUTDescriptor[1]))
+	bitsPerEntry := int(voi.LUTDescriptor[2])
+	if bitsPerEntry == 0 || bitsPerEntry > 16 {
+		bitsPerEntry = 16
+	}
+	entryMax := float64(uint32(1)<<bitsPerEntry - 1)
+	last := len(voi.LUTData) - 1
+
+	for i, v := range values {
+		idx := int(math.Round(v)) - firstMapped
+		idx = max(0, min(idx, last))
+		out[i] = uint8(math.Round(float64(voi.LUTData[idx]) / entryMax * 255))
+	}
+}
+
+// fullRangeWindow returns a window spanning the minimum and maximum of values.
+func fullRangeWindow(values []float64) (center, width float64) {
+	if len(values) == 0 {
+		return 0, 1
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	width = hi - lo
+	if width <= 0 {
+		width = 1
+	}
+	return (lo + hi) / 2, width
+}
+
+// extractGraphics reads the Graphic Annotation Sequence items that apply to the image.
+func extractGraphics(gspsDS *dicom.DataSet, sopInstanceUID string) ([]Graphic, error) {
+	annotations, err := gspsDS.GetSequenceItems(tag.GraphicAnnotationSequence)
+	if err != nil {
+		return nil, nil
+	}
+
+	var graphics []Graphic
+	for i, annotation := range annotations {
+		if !appliesToImage(annotation, sopInstanceUID) {
+			continue
+		}
+		layer := datasetString(annotation, tag.GraphicLayer)
+
+		if objects, err := annotation.GetSequenceItems(tag.GraphicObjectSequence); err == nil {
+			for j, obj := range objects {
+				g, err := parseGraphicObject(obj, layer)
+				if err != nil {
+					return nil, fmt.Errorf("graphic annotation %d object %d: %w", i, j, err)
+				}
+				graphics = append(graphics, g)
+			}
+		}
+
+		if objects, err := annotation.GetSequenceItems(tag.TextObjectSequence); err == nil {
+			for j, obj := range objects {
+				g, err := parseTextObject(obj, layer)
+				if err != nil {
+					return nil, fmt.Errorf("graphic annotation %d text %d: %w", i, j, err)
+				}
+				graphics = append(graphics, g)
+			}
+		}
+	}
+
+	return graphics, nil
+}
+
+// parseGraphicObject reads one Graphic Object Sequence item.
+func parseGraphicObject(obj *dicom.DataSet, layer string) (Graphic, error) {
+	g := Graphic{
+		Layer:  layer,
+		Type:   datasetString(obj, tag.GraphicType),
+		Units:  datasetString(obj, tag.GraphicAnnotationUnits),
+		Filled: datasetString(obj, tag.GraphicFilled) == "Y",
+	}
+
+	coords, err := datasetFloats(obj, tag.GraphicData)
+	if err != nil {
+		return g, err
+	}
+	if len(coords)%2 != 0 {
+		return g, fmt.Errorf("graphic data has %d values, not a multiple of 2", len(coords))
+	}
+	g.Points = pointsFromCoords(coords)
+
+	if obj.Contains(tag.NumberOfGraphicPoints) {
+		elem, _ := obj.Get(tag.NumberOfGraphicPoints)
+		if n, ok := elem.Value().(*value.IntValue); ok && len(n.Ints()) == 1 && int(n.Ints()[0]) != len(g.Points) {
+			return g, fmt.Errorf("number of graphic points is %d but graphic data holds %d points",
+				n.Ints()[0], len(g.Points))
+		}
+	}
+
+	return g, nil
+}
+
+// parseTextObject reads one Text Object Sequence item.
+func parseTextObject(obj *dicom.DataSet, layer string) (Graphic, error) {
+	g := Graphic{
+		Layer: layer,
+		Type:  "TEXT",
+		Units: datasetString(obj, tag.BoundingBoxAnnotationUnits),
+		Text:  datasetString(obj, tag.UnformattedTextValue),
+	}
+
+	if obj.Contains(tag.BoundingBoxTopLeftHandCorner) {
+		tlhc, err := datasetFloats(obj, tag.BoundingBoxTopLeftHandCorner)
+		if err != nil {
+			return g, err
+		}
+		brhc, err := datasetFloats(obj, tag.BoundingBoxBottomRightHandCorner)
+		if err != nil {
+			return g, err
+		}
+		if len(tlhc) != 2 || len(brhc) != 2 {
+			return g, fmt.Errorf("bounding box corners must hold 2 values each")
+		}
+		g.Points = pointsFromCoords(append(tlhc, brhc...))
+	}
+
+	if obj.Contains(tag.AnchorPoint) {
+		anchor, err := datasetFloats(obj, tag.AnchorPoint)
+		if err != nil {
+			return g, err
+		}
+		if len(anchor) != 2 {
+			return g, fmt.Errorf("anchor point must hold 2 values, got %d", len(anchor))
+		}
+		g.Anchor = &GraphicPoint{X: anchor[0], Y: anchor[1]}
+		g.AnchorUnits = datasetString(obj, tag.AnchorPointAnnotationUnits)
+		g.AnchorPointVisible = datasetString(obj, tag.AnchorPointVisibility) == "Y"
+		if g.Units == "" {
+			g.Units = g.AnchorUnits
+		}
+	}
+
+	if g.Points == nil && g.Anchor == nil {
+		return g, fmt.Errorf("text object has neither a bounding box nor an anchor point")
+	}
+
+	return g, nil
+}
+
+// pointsFromCoords pairs a flat x/y list into points.
+func pointsFromCoords(coords []float64) []GraphicPoint {
+	points := make([]GraphicPoint, len(coords)/2)
+	for i := range points {
+		points[i] = GraphicPoint{X: coords[i*2], Y: coords[i*2+1]}
+	}
+	return points
+}
+
+// datasetString returns the trimmed string value of an element, or "" if absent.
+func datasetString(ds *dicom.DataSet, t tag.Tag) string {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(elem.Value().String())
+}
+
+// datasetFloats returns the values of an FL/FD element.
+func datasetFloats(ds *dicom.DataSet, t tag.Tag) ([]float64, error) {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return nil, fmt.Errorf("missing %s: %w", t, err)
+	}
+	floatVal, ok := elem.Value().(*value.FloatValue)
+	if !ok {
+		return nil, fmt.Errorf("element %s has unexpected value type %T", t, elem.Value())
+	}
+	return floatVal.Floats(), nil
+}
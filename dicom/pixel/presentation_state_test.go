@@ -0,0 +1,220 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	gspsTestSeriesUID   = "1.2.826.0.1.3680043.10.1451.2"
+	gspsTestInstanceUID = "1.2.826.0.1.3680043.10.1451.2.1"
+)
+
+func addGSPSString(t *testing.T, ds *dicom.DataSet, tg tag.Tag, v vr.VR, values ...string) {
+	val, err := value.NewStringValue(v, values)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, v, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+func addGSPSFloats(t *testing.T, ds *dicom.DataSet, tg tag.Tag, values ...float64) {
+	val, err := value.NewFloatValue(vr.FloatingPointSingle, values)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, vr.FloatingPointSingle, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+func addGSPSSequence(t *testing.T, ds *dicom.DataSet, tg tag.Tag, items ...*dicom.DataSet) {
+	elem, err := dicom.NewSequenceElement(tg, items)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+// newGSPSImage returns an image dataset and 4-pixel CT data (stored -1000, 0, 40, 1000).
+func newGSPSImage(t *testing.T) (*dicom.DataSet, *PixelData) {
+	ds := dicom.NewDataSet()
+	addGSPSString(t, ds, tag.SOPInstanceUID, vr.UniqueIdentifier, gspsTestInstanceUID)
+	addGSPSString(t, ds, tag.SeriesInstanceUID, vr.UniqueIdentifier, gspsTestSeriesUID)
+
+	pd, err := NewPixelDataFromInt16([]int16{-1000, 0, 40, 1000}, 4, 1)
+	require.NoError(t, err)
+	return ds, pd
+}
+
+// newImageReference builds a Referenced Image Sequence item.
+func newImageReference(t *testing.T, sopInstanceUID string) *dicom.DataSet {
+	item := dicom.NewDataSet()
+	addGSPSString(t, item, tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier, sopInstanceUID)
+	return item
+}
+
+// newGSPS builds a presentation state referencing the test image with a soft
+// tissue window (40/400).
+func newGSPS(t *testing.T) *dicom.DataSet {
+	ds := dicom.NewDataSet()
+
+	series := dicom.NewDataSet()
+	addGSPSString(t, series, tag.SeriesInstanceUID, vr.UniqueIdentifier, gspsTestSeriesUID)
+	addGSPSSequence(t, series, tag.ReferencedImageSequence, newImageReference(t, gspsTestInstanceUID))
+	addGSPSSequence(t, ds, tag.ReferencedSeriesSequence, series)
+
+	voi := dicom.NewDataSet()
+	addGSPSString(t, voi, tag.WindowCenter, vr.DecimalString, "40")
+	addGSPSString(t, voi, tag.WindowWidth, vr.DecimalString, "400")
+	addGSPSSequence(t, ds, tag.SoftcopyVOILUTSequence, voi)
+
+	addGSPSString(t, ds, tag.PresentationLUTShape, vr.CodeString, "IDENTITY")
+
+	return ds
+}
+
+func TestApplyPresentationState_Window(t *testing.T) {
+	imageDS, pd := newGSPSImage(t)
+
+	display, graphics, err := ApplyPresentationState(imageDS, newGSPS(t), pd)
+	require.NoError(t, err)
+	assert.Empty(t, graphics)
+
+	assert.Equal(t, uint16(8), display.BitsAllocated)
+	assert.Equal(t, uint16(0), display.PixelRepresentation)
+	assert.Equal(t, "MONOCHROME2", display.PhotometricInterpretation)
+
+	// Window 40/400 spans -160..240
+	assert.Equal(t, []uint8{0, 102, 128, 255}, display.Array())
+}
+
+func TestApplyPresentationState_Inverse(t *testing.T) {
+	imageDS, pd := newGSPSImage(t)
+	gsps := newGSPS(t)
+	addGSPSString(t, gsps, tag.PresentationLUTShape, vr.CodeString, "INVERSE")
+
+	display, _, err := ApplyPresentationState(imageDS, gsps, pd)
+	require.NoError(t, err)
+	assert.Equal(t, []uint8{255, 153, 127, 0}, display.Array())
+}
+
+func TestApplyPresentationState_ModalityLUT(t *testing.T) {
+	imageDS, pd := newGSPSImage(t)
+	gsps := newGSPS(t)
+	addGSPSString(t, gsps, tag.RescaleSlope, vr.DecimalString, "1")
+	addGSPSString(t, gsps, tag.RescaleIntercept, vr.DecimalString, "-40")
+
+	display, _, err := ApplyPresentationState(imageDS, gsps, pd)
+	require.NoError(t, err)
+	// Modality values -1040, -40, 0, 960
+	assert.Equal(t, []uint8{0, 77, 102, 255}, display.Array())
+}
+
+func TestApplyPresentationState_VOILUTTable(t *testing.T) {
+	imageDS, _ := newGSPSImage(t)
+	pd, err := NewPixelDataFromUint8([]uint8{0, 1, 2, 3}, 4, 1)
+	require.NoError(t, err)
+
+	gsps := newGSPS(t)
+	lut := dicom.NewDataSet()
+	descVal, err := value.NewIntValue(vr.UnsignedShort, []int64{3, 1, 8})
+	require.NoError(t, err)
+	descElem, err := element.NewElement(tag.LUTDescriptor, vr.UnsignedShort, descVal)
+	require.NoError(t, err)
+	require.NoError(t, lut.Add(descElem))
+	dataVal, err := value.NewIntValue(vr.UnsignedShort, []int64{10, 100, 200})
+	require.NoError(t, err)
+	dataElem, err := element.NewElement(tag.LUTData, vr.UnsignedShort, dataVal)
+	require.NoError(t, err)
+	require.NoError(t, lut.Add(dataElem))
+
+	voi := dicom.NewDataSet()
+	addGSPSSequence(t, voi, tag.VOILUTSequence, lut)
+	addGSPSSequence(t, gsps, tag.SoftcopyVOILUTSequence, voi)
+
+	display, _, err := ApplyPresentationState(imageDS, gsps, pd)
+	require.NoError(t, err)
+	// Value 0 clamps to the first entry, 3 to the last
+	assert.Equal(t, []uint8{10, 10, 100, 200}, display.Array())
+}
+
+func TestApplyPresentationState_Graphics(t *testing.T) {
+	imageDS, pd := newGSPSImage(t)
+	gsps := newGSPS(t)
+
+	polyline := dicom.NewDataSet()
+	addGSPSString(t, polyline, tag.GraphicAnnotationUnits, vr.CodeString, "PIXEL")
+	addGSPSString(t, polyline, tag.GraphicType, vr.CodeString, "POLYLINE")
+	addGSPSString(t, polyline, tag.GraphicFilled, vr.CodeString, "N")
+	addGSPSFloats(t, polyline, tag.GraphicData, 1, 1, 4, 1)
+
+	text := dicom.NewDataSet()
+	addGSPSString(t, text, tag.UnformattedTextValue, vr.ShortText, "Lesion")
+	addGSPSString(t, text, tag.AnchorPointAnnotationUnits, vr.CodeString, "PIXEL")
+	addGSPSFloats(t, text, tag.AnchorPoint, 2, 1)
+	addGSPSString(t, text, tag.AnchorPointVisibility, vr.CodeString, "Y")
+
+	annotation := dicom.NewDataSet()
+	addGSPSString(t, annotation, tag.GraphicLayer, vr.CodeString, "MEASUREMENTS")
+	addGSPSSequence(t, annotation, tag.GraphicObjectSequence, polyline)
+	addGSPSSequence(t, annotation, tag.TextObjectSequence, text)
+
+	// Annotation restricted to another image is skipped
+	other := dicom.NewDataSet()
+	addGSPSString(t, other, tag.GraphicLayer, vr.CodeString, "OTHER")
+	addGSPSSequence(t, other, tag.ReferencedImageSequence, newImageReference(t, "1.2.3.4"))
+	addGSPSSequence(t, other, tag.GraphicObjectSequence, polyline)
+
+	addGSPSSequence(t, gsps, tag.GraphicAnnotationSequence, annotation, other)
+
+	_, graphics, err := ApplyPresentationState(imageDS, gsps, pd)
+	require.NoError(t, err)
+	require.Len(t, graphics, 2)
+
+	assert.Equal(t, "MEASUREMENTS", graphics[0].Layer)
+	assert.Equal(t, "POLYLINE", graphics[0].Type)
+	assert.Equal(t, "PIXEL", graphics[0].Units)
+	assert.Equal(t, []GraphicPoint{{X: 1, Y: 1}, {X: 4, Y: 1}}, graphics[0].Points)
+	assert.False(t, graphics[0].Filled)
+
+	assert.Equal(t, "TEXT", graphics[1].Type)
+	assert.Equal(t, "Lesion", graphics[1].Text)
+	require.NotNil(t, graphics[1].Anchor)
+	assert.Equal(t, GraphicPoint{X: 2, Y: 1}, *graphics[1].Anchor)
+	assert.True(t, graphics[1].AnchorPointVisible)
+	assert.Equal(t, "PIXEL", graphics[1].Units)
+}
+
+func TestApplyPresentationState_Errors(t *testing.T) {
+	imageDS, pd := newGSPSImage(t)
+
+	t.Run("image not referenced", func(t *testing.T) {
+		other := dicom.NewDataSet()
+		addGSPSString(t, other, tag.SOPInstanceUID, vr.UniqueIdentifier, "1.2.3.4")
+		_, _, err := ApplyPresentationState(other, newGSPS(t), pd)
+		assert.ErrorContains(t, err, "does not reference")
+	})
+
+	t.Run("missing SOP Instance UID", func(t *testing.T) {
+		_, _, err := ApplyPresentationState(dicom.NewDataSet(), newGSPS(t), pd)
+		assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+	})
+
+	t.Run("color image", func(t *testing.T) {
+		rgb, err := NewPixelDataFromRGB(make([]byte, 12), 4, 1)
+		require.NoError(t, err)
+		_, _, err = ApplyPresentationState(imageDS, newGSPS(t), rgb)
+		assert.Error(t, err)
+	})
+
+	t.Run("nil inputs", func(t *testing.T) {
+		_, _, err := ApplyPresentationState(nil, newGSPS(t), pd)
+		assert.Error(t, err)
+		_, _, err = ApplyPresentationState(imageDS, newGSPS(t), nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,208 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+)
+
+// ScaleMethod selects how Rescale maps stored values to the target bit depth.
+type ScaleMethod int
+
+const (
+	// LinearMinMax linearly maps the minimum and maximum stored values of the
+	// image onto the full unsigned range of the target depth [0, 2^targetBits-1].
+	// The result is always unsigned (PixelRepresentation = 0).
+	LinearMinMax ScaleMethod = iota
+
+	// WindowClip keeps stored values unchanged and clips those outside the range
+	// representable at the target depth. Signed data stays signed; use it when the
+	// values of interest already fit in the smaller depth.
+	WindowClip
+
+	// TruncateHighBits shifts values by the difference in BitsStored, keeping the
+	// most significant bits when down-converting and padding with zero low bits
+	// when up-converting. Signedness is preserved.
+	TruncateHighBits
+)
+
+// String returns the name of the scale method.
+func (m ScaleMethod) String() string {
+	switch m {
+	case LinearMinMax:
+		return "LinearMinMax"
+	case WindowClip:
+		return "WindowClip"
+	case TruncateHighBits:
+		return "TruncateHighBits"
+	default:
+		return fmt.Sprintf("ScaleMethod(%d)", int(m))
+	}
+}
+
+// Rescale re-encodes stored pixel values at a different bit depth.
+//
+// Unlike ApplyWindowLevel, which produces display values, Rescale produces new
+// stored pixel data intended to be persisted (for example down-converting 16-bit
+// data to 8-bit for a secondary capture). The result has BitsStored = targetBits,
+// HighBit = targetBits-1, and BitsAllocated = 8 for targetBits <= 8, otherwise 16.
+//
+// Signed input is converted to unsigned output by LinearMinMax; WindowClip and
+// TruncateHighBits preserve PixelRepresentation. Every sample of colour images is
+// scaled independently using the same mapping.
+//
+// Parameters:
+//   - pd: Source pixel data (native, BitsAllocated 8 or 16)
+//   - targetBits: Target BitsStored, between 1 and 16
+//   - method: How values are mapped to the target depth
+//
+// Example:
+//
+//	// Down-convert a 12-bit CT image to 8-bit for storage
+//	rescaled, err := pixel.Rescale(pixelData, 8, pixel.LinearMinMax)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_8.1.1
+func Rescale(pd *PixelData, targetBits int, method ScaleMethod) (*PixelData, error) {
+	if pd == nil {
+		return nil, fmt.Errorf("pixel data is nil")
+	}
+	if targetBits < 1 || targetBits > 16 {
+		return nil, fmt.Errorf("target bits must be between 1 and 16, got %d", targetBits)
+	}
+	if pd.BitsAllocated != 8 && pd.BitsAllocated != 16 {
+		return nil, fmt.Errorf("rescale supports BitsAllocated 8 or 16, got %d", pd.BitsAllocated)
+	}
+
+	values := storedValues(pd)
+
+	signed := pd.PixelRepresentation == 1
+	if method == LinearMinMax {
+		signed = false
+	}
+
+	lo, hi := int64(0), int64(1)<<targetBits-1
+	if signed {
+		lo, hi = -(int64(1) << (targetBits - 1)), int64(1)<<(targetBits-1)-1
+	}
+
+	switch method {
+	case LinearMinMax:
+		if len(values) > 0 {
+			minVal, maxVal := values[0], values[0]
+			for _, v := range values {
+				minVal = min(minVal, v)
+				maxVal = max(maxVal, v)
+			}
+			span := float64(maxVal - minVal)
+			for i, v := range values {
+				if span == 0 {
+					values[i] = 0
+					continue
+				}
+				values[i] = int64(math.Round(float64(v-minVal) / span * float64(hi)))
+			}
+		}
+
+	case WindowClip:
+		for i, v := range values {
+			values[i] = max(lo, min(v, hi))
+		}
+
+	case TruncateHighBits:
+		shift := int(effectiveBitsStored(pd)) - targetBits
+		for i, v := range values {
+			if shift >= 0 {
+				values[i] = v >> shift
+			} else {
+				values[i] = max(lo, min(v<<-shift, hi))
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported scale method: %s", method)
+	}
+
+	bitsAllocated := uint16(16)
+	if targetBits <= 8 {
+		bitsAllocated = 8
+	}
+
+	mask := uint64(1)<<targetBits - 1
+	data := make([]byte, len(values)*int(bitsAllocated/8))
+	for i, v := range values {
+		// Unsigned samples leave the bits above HighBit zero; signed samples are
+		// sign-extended into the whole BitsAllocated word, as invertSamples writes them
+		u := uint64(v) & mask
+		if signed {
+			u = uint64(v)
+		}
+		if bitsAllocated == 8 {
+			data[i] = byte(u)
+		} else {
+			data[i*2] = byte(u)
+			data[i*2+1] = byte(u >> 8)
+		}
+	}
+
+	pixelRepresentation := uint16(0)
+	if signed {
+		pixelRepresentation = 1
+	}
+
+	result := &PixelData{
+		Rows:                      pd.Rows,
+		Columns:                   pd.Columns,
+		BitsAllocated:             bitsAllocated,
+		BitsStored:                uint16(targetBits),
+		HighBit:                   uint16(targetBits - 1),
+		PixelRepresentation:       pixelRepresentation,
+		SamplesPerPixel:           pd.SamplesPerPixel,
+		PhotometricInterpretation: pd.PhotometricInterpretation,
+		PlanarConfiguration:       pd.PlanarConfiguration,
+		NumberOfFrames:            pd.NumberOfFrames,
+		data:                      data,
+		TransferSyntaxUID:         pd.TransferSyntaxUID,
+	}
+
+	return result, nil
+}
+
+// effectiveBitsStored returns BitsStored, falling back to BitsAllocated when unset
+// or out of range.
+func effectiveBitsStored(pd *PixelData) uint16 {
+	if pd.BitsStored == 0 || pd.BitsStored > pd.BitsAllocated {
+		return pd.BitsAllocated
+	}
+	return pd.BitsStored
+}
+
+// storedValues decodes every sample as an integer, masking bits above BitsStored
+// and sign-extending signed data.
+func storedValues(pd *PixelData) []int64 {
+	bitsStored := effectiveBitsStored(pd)
+	mask := uint32(1)<<bitsStored - 1
+	signBit := uint32(1) << (bitsStored - 1)
+
+	bytesPerSample := int(pd.BitsAllocated / 8)
+	values := make([]int64, len(pd.data)/bytesPerSample)
+	for i := range values {
+		var raw uint32
+		if bytesPerSample == 1 {
+			raw = uint32(pd.data[i])
+		} else {
+			raw = uint32(pd.data[i*2]) | uint32(pd.data[i*2+1])<<8
+		}
+		raw &= mask
+
+		if pd.PixelRepresentation == 1 && raw&signBit != 0 {
+			values[i] = int64(raw) - int64(mask) - 1
+		} else {
+			values[i] = int64(raw)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,164 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRescale_LinearMinMax(t *testing.T) {
+	t.Run("16-bit to 8-bit", func(t *testing.T) {
+		pd := NewSyntheticPixelData(SyntheticOptions{
+			Pattern:  PatternGradient,
+			Rows:     4,
+			Columns:  256,
+			MinValue: 1000,
+			MaxValue: 4000,
+		})
+
+		rescaled, err := Rescale(pd, 8, LinearMinMax)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint16(8), rescaled.BitsAllocated)
+		assert.Equal(t, uint16(8), rescaled.BitsStored)
+		assert.Equal(t, uint16(7), rescaled.HighBit)
+		assert.Equal(t, uint16(0), rescaled.PixelRepresentation)
+
+		pixels := rescaled.Array().([]uint8)
+		require.Len(t, pixels, 4*256)
+		assert.Equal(t, uint8(0), pixels[0])
+		assert.Equal(t, uint8(255), pixels[255])
+	})
+
+	t.Run("signed to unsigned", func(t *testing.T) {
+		pd := NewSyntheticPixelData(SyntheticOptions{
+			Pattern: PatternHounsfieldRamp,
+			Rows:    64,
+			Columns: 64,
+		})
+
+		rescaled, err := Rescale(pd, 12, LinearMinMax)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint16(16), rescaled.BitsAllocated)
+		assert.Equal(t, uint16(12), rescaled.BitsStored)
+		assert.Equal(t, uint16(11), rescaled.HighBit)
+		assert.Equal(t, uint16(0), rescaled.PixelRepresentation)
+
+		pixels := rescaled.Array().([]uint16)
+		assert.Equal(t, uint16(0), pixels[0])       // -1024 HU
+		assert.Equal(t, uint16(4095), pixels[4095]) // 3071 HU
+	})
+
+	t.Run("constant image", func(t *testing.T) {
+		pd := NewSyntheticPixelData(SyntheticOptions{
+			Rows:     2,
+			Columns:  2,
+			MinValue: 500,
+			MaxValue: 500,
+		})
+
+		rescaled, err := Rescale(pd, 8, LinearMinMax)
+		require.NoError(t, err)
+		assert.Equal(t, []uint8{0, 0, 0, 0}, rescaled.Array())
+	})
+}
+
+func TestRescale_WindowClip(t *testing.T) {
+	t.Run("unsigned clips to target range", func(t *testing.T) {
+		pd, err := NewPixelDataFromUint16([]uint16{0, 100, 255, 256, 65535}, 1, 5)
+		require.NoError(t, err)
+
+		rescaled, err := Rescale(pd, 8, WindowClip)
+		require.NoError(t, err)
+		assert.Equal(t, []uint8{0, 100, 255, 255, 255}, rescaled.Array())
+	})
+
+	t.Run("signed stays signed", func(t *testing.T) {
+		pd, err := NewPixelDataFromInt16([]int16{-1024, -128, 0, 127, 3071}, 1, 5)
+		require.NoError(t, err)
+
+		rescaled, err := Rescale(pd, 8, WindowClip)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(1), rescaled.PixelRepresentation)
+		assert.Equal(t, []int8{-128, -128, 0, 127, 127}, rescaled.Array())
+	})
+}
+
+func TestRescale_TruncateHighBits(t *testing.T) {
+	t.Run("down-convert keeps high bits", func(t *testing.T) {
+		pd, err := NewPixelDataFromUint16([]uint16{0x0000, 0x00FF, 0x0100, 0xFF00, 0xFFFF}, 1, 5)
+		require.NoError(t, err)
+
+		rescaled, err := Rescale(pd, 8, TruncateHighBits)
+		require.NoError(t, err)
+		assert.Equal(t, []uint8{0x00, 0x00, 0x01, 0xFF, 0xFF}, rescaled.Array())
+	})
+
+	t.Run("up-convert pads low bits", func(t *testing.T) {
+		pd, err := NewPixelDataFromUint8([]uint8{0, 1, 128, 255}, 1, 4)
+		require.NoError(t, err)
+
+		rescaled, err := Rescale(pd, 16, TruncateHighBits)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(16), rescaled.BitsAllocated)
+		assert.Equal(t, uint16(15), rescaled.HighBit)
+		assert.Equal(t, []uint16{0x0000, 0x0100, 0x8000, 0xFF00}, rescaled.Array())
+	})
+
+	t.Run("signed 12-bit stored", func(t *testing.T) {
+		pd := NewSyntheticPixelData(SyntheticOptions{
+			Pattern:    PatternRamp,
+			Rows:       1,
+			Columns:    4,
+			BitsStored: 12,
+			Signed:     true,
+			MinValue:   -2048,
+			MaxValue:   2047,
+		})
+
+		rescaled, err := Rescale(pd, 8, TruncateHighBits)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(1), rescaled.PixelRepresentation)
+		// -2048..-2045 >> 4 == -128
+		assert.Equal(t, []int8{-128, -128, -128, -128}, rescaled.Array())
+	})
+
+	t.Run("signed sign-extends into BitsAllocated", func(t *testing.T) {
+		pd, err := NewPixelDataFromInt16([]int16{-1024, -1, 0, 1023}, 1, 4)
+		require.NoError(t, err)
+
+		rescaled, err := Rescale(pd, 12, TruncateHighBits)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(16), rescaled.BitsAllocated)
+		assert.Equal(t, uint16(12), rescaled.BitsStored)
+		assert.Equal(t, uint16(1), rescaled.PixelRepresentation)
+		// -1024 >> 4 == -64 (0xFFC0), -1 >> 4 == -1 (0xFFFF)
+		assert.Equal(t, []byte{0xC0, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x3F, 0x00}, rescaled.data)
+		assert.Equal(t, []int16{-64, -1, 0, 63}, rescaled.Array())
+	})
+}
+
+func TestRescale_Errors(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{Rows: 2, Columns: 2})
+
+	tests := []struct {
+		name       string
+		pd         *PixelData
+		targetBits int
+		method     ScaleMethod
+	}{
+		{"nil pixel data", nil, 8, LinearMinMax},
+		{"zero target bits", pd, 0, LinearMinMax},
+		{"target bits too large", pd, 17, LinearMinMax},
+		{"unknown method", pd, 8, ScaleMethod(99)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Rescale(tt.pd, tt.targetBits, tt.method)
+			assert.Error(t, err)
+		})
+	}
+}
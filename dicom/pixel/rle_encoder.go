@@ -0,0 +1,131 @@
+package pixel
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// RLEEncoder implements DICOM RLE Lossless compression.
+//
+// Each frame is split into one segment per byte of each sample, most significant
+// byte first (a 16-bit RGB image has 6 segments). Every row of a segment is
+// PackBits-encoded separately and each segment is padded to even length with the
+// PackBits no-op byte (0x80), as required by PS3.5 Annex G.
+type RLEEncoder struct{}
+
+// Encode compresses each frame of pd with RLE Lossless and returns one fragment per frame.
+//
+// Both interleaved and planar colour data are accepted. At most 15 segments are
+// allowed, so BitsAllocated × SamplesPerPixel must not exceed 120.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#chapter_G
+func (e *RLEEncoder) Encode(pd *PixelData) ([][]byte, error) {
+	if pd == nil {
+		return nil, fmt.Errorf("pixel data is nil")
+	}
+
+	bytesPerSample := (int(pd.BitsAllocated) + 7) / 8
+	samples := int(pd.SamplesPerPixel)
+	numSegments := bytesPerSample * samples
+	if numSegments == 0 || numSegments > 15 {
+		return nil, fmt.Errorf("RLE Lossless supports at most 15 segments, got %d (BitsAllocated=%d, SamplesPerPixel=%d)",
+			numSegments, pd.BitsAllocated, pd.SamplesPerPixel)
+	}
+
+	rows, columns := int(pd.Rows), int(pd.Columns)
+	numFrames := max(pd.NumberOfFrames, 1)
+	frameSize := rows * columns * numSegments
+	if len(pd.data) < frameSize*numFrames {
+		return nil, &PixelDataError{
+			Field:    "pixel data length",
+			Expected: frameSize * numFrames,
+			Actual:   len(pd.data),
+		}
+	}
+
+	fragments := make([][]byte, numFrames)
+	row := make([]byte, columns)
+	for f := 0; f < numFrames; f++ {
+		frame := pd.data[f*frameSize : (f+1)*frameSize]
+
+		header := make([]byte, 64)
+		binary.LittleEndian.PutUint32(header[0:4], uint32(numSegments))
+		out := header
+
+		for s := 0; s < samples; s++ {
+			// Segments run from the most to the least significant byte of the sample
+			for b := bytesPerSample - 1; b >= 0; b-- {
+				segment := s*bytesPerSample + (bytesPerSample - 1 - b)
+				binary.LittleEndian.PutUint32(out[4+segment*4:], uint32(len(out)))
+
+				for r := 0; r < rows; r++ {
+					for c := 0; c < columns; c++ {
+						pixelIdx := r*columns + c
+						var sampleIdx int
+						if pd.PlanarConfiguration == 1 {
+							sampleIdx = s*rows*columns + pixelIdx
+						} else {
+							sampleIdx = pixelIdx*samples + s
+						}
+						row[c] = frame[sampleIdx*bytesPerSample+b]
+					}
+					out = encodePackBits(out, row)
+				}
+
+				// Pad with the PackBits no-op so decoders skip it
+				if len(out)%2 != 0 {
+					out = append(out, 0x80)
+				}
+			}
+		}
+
+		fragments[f] = out
+	}
+
+	return fragments, nil
+}
+
+// TransferSyntaxUID returns the RLE Lossless transfer syntax UID.
+func (e *RLEEncoder) TransferSyntaxUID() string {
+	return "1.2.840.10008.1.2.5" // RLE Lossless
+}
+
+// encodePackBits appends the PackBits encoding of src to dst.
+//
+// Runs of three or more identical bytes become replicate runs (control 1-n);
+// everything else is emitted as literal runs (control n-1), each at most 128 bytes.
+// It is the inverse of decodePackBits.
+func encodePackBits(dst, src []byte) []byte {
+	i := 0
+	for i < len(src) {
+		// Measure the run of identical bytes starting at i
+		run := 1
+		for i+run < len(src) && run < 128 && src[i+run] == src[i] {
+			run++
+		}
+
+		if run >= 3 {
+			dst = append(dst, byte(int8(1-run)), src[i])
+			i += run
+			continue
+		}
+
+		// Extend the literal until a run of three begins or the limit is reached
+		start := i
+		for i < len(src) && i-start < 128 {
+			if i+2 < len(src) && src[i] == src[i+1] && src[i] == src[i+2] {
+				break
+			}
+			i++
+		}
+		dst = append(dst, byte(i-start-1))
+		dst = append(dst, src[start:i]...)
+	}
+	return dst
+}
+
+func init() {
+	// Register RLE encoder
+	RegisterEncoder("1.2.840.10008.1.2.5", &RLEEncoder{})
+}
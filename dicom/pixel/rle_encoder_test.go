@@ -0,0 +1,96 @@
+package pixel
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodePackBits(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  []byte
+	}{
+		{"single byte", []byte{0x05}, []byte{0x00, 0x05}},
+		{"literal", []byte{1, 2, 3}, []byte{0x02, 1, 2, 3}},
+		{"replicate", []byte{7, 7, 7, 7}, []byte{0xFD, 7}},
+		{"pair stays literal", []byte{1, 1, 2}, []byte{0x02, 1, 1, 2}},
+		{"literal then replicate", []byte{1, 2, 9, 9, 9}, []byte{0x01, 1, 2, 0xFE, 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, encodePackBits(nil, tt.input))
+		})
+	}
+}
+
+func TestEncodePackBits_RoundTrip(t *testing.T) {
+	// Long runs and long literals exceed the 128-byte run limit
+	src := make([]byte, 0, 700)
+	for i := 0; i < 300; i++ {
+		src = append(src, 0xAA)
+	}
+	for i := 0; i < 400; i++ {
+		src = append(src, byte(i*7))
+	}
+
+	decoded, err := decodePackBits(encodePackBits(nil, src))
+	require.NoError(t, err)
+	assert.Equal(t, src, decoded)
+}
+
+func TestRLEEncoder_8Bit(t *testing.T) {
+	pd, err := NewPixelDataFromUint8([]uint8{0, 0, 0, 0, 1, 2, 3, 4, 5}, 3, 3)
+	require.NoError(t, err)
+
+	fragments, err := (&RLEEncoder{}).Encode(pd)
+	require.NoError(t, err)
+	require.Len(t, fragments, 1)
+	assert.Zero(t, len(fragments[0])%2)
+
+	decoded, err := (&RLEDecoder{}).Decode(fragments[0], &PixelInfo{
+		Rows:            3,
+		Columns:         3,
+		BitsAllocated:   8,
+		SamplesPerPixel: 1,
+		NumberOfFrames:  1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, pd.RawBytes(), decoded)
+}
+
+func TestRLEEncoder_SegmentOrder(t *testing.T) {
+	// 16-bit data: the first segment holds the most significant bytes (PS3.5 G.2)
+	pd, err := NewPixelDataFromUint16([]uint16{0x0102, 0x0304}, 2, 1)
+	require.NoError(t, err)
+
+	fragments, err := (&RLEEncoder{}).Encode(pd)
+	require.NoError(t, err)
+	fragment := fragments[0]
+
+	require.Equal(t, uint32(2), binary.LittleEndian.Uint32(fragment[0:4]))
+	msbOffset := binary.LittleEndian.Uint32(fragment[4:8])
+	lsbOffset := binary.LittleEndian.Uint32(fragment[8:12])
+	assert.Equal(t, uint32(64), msbOffset)
+
+	msb, err := decodePackBits(fragment[msbOffset:lsbOffset])
+	require.NoError(t, err)
+	lsb, err := decodePackBits(fragment[lsbOffset:])
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x03}, msb[:2])
+	assert.Equal(t, []byte{0x02, 0x04}, lsb[:2])
+}
+
+func TestRLEEncoder_Errors(t *testing.T) {
+	_, err := (&RLEEncoder{}).Encode(nil)
+	assert.Error(t, err)
+
+	pd := NewSyntheticPixelData(SyntheticOptions{Rows: 2, Columns: 2})
+	pd.SamplesPerPixel = 8 // 16 segments
+	_, err = (&RLEEncoder{}).Encode(pd)
+	assert.Error(t, err)
+}
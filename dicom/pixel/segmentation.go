@@ -0,0 +1,402 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// Segment describes one segment of a Segmentation, from an item of the Segment
+// Sequence (0062,0002).
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.8.20.4
+type Segment struct {
+	Number int    // Segment Number (0062,0004)
+	Label  string // Segment Label (0062,0005)
+
+	// AnatomicRegion is the first item of the Anatomic Region Sequence (0008,2218),
+	// or nil if absent.
+	AnatomicRegion *SegmentCode
+
+	// PropertyType is the Segmented Property Type Code Sequence (0062,000F) item, or
+	// nil if absent.
+	PropertyType *SegmentCode
+}
+
+// SegmentCode is a coded concept (Code Sequence Macro) describing a segment.
+type SegmentCode struct {
+	CodeValue              string // Code Value (0008,0100)
+	CodingSchemeDesignator string // Coding Scheme Designator (0008,0102)
+	CodeMeaning            string // Code Meaning (0008,0104)
+}
+
+// LabelMap is a Segmentation composited into label images: one frame per spatial
+// location, where each pixel holds the number of the segment covering it, or 0 for
+// background.
+type LabelMap struct {
+	Rows    int
+	Columns int
+
+	// NumberOfFrames is the number of spatial locations (label frames).
+	NumberOfFrames int
+
+	// Labels holds Rows×Columns segment numbers per frame, row by row and frame after
+	// frame.
+	Labels []uint16
+
+	// Positions holds the Image Position (Patient) of each label frame, or is nil if
+	// the segmentation locates frames by source image reference instead.
+	Positions [][3]float64
+
+	// Segments maps segment numbers to their descriptions.
+	Segments map[int]Segment
+}
+
+// At returns the segment number at (row, col) of frame, or 0 if out of range.
+func (m *LabelMap) At(frame, row, col int) uint16 {
+	if frame < 0 || frame >= m.NumberOfFrames || row < 0 || row >= m.Rows || col < 0 || col >= m.Columns {
+		return 0
+	}
+	return m.Labels[(frame*m.Rows+row)*m.Columns+col]
+}
+
+// LabelMapOption configures SegmentationLabelMap.
+type LabelMapOption func(*labelMapOptions)
+
+type labelMapOptions struct {
+	priority []int
+}
+
+// WithSegmentPriority sets which segment wins where segments overlap: segments
+// listed earlier take precedence over those listed later, and listed segments over
+// unlisted ones. Among unlisted segments the highest segment number wins.
+//
+// Example:
+//
+//	// Tumour (segment 3) is drawn over the organ (segment 1)
+//	lm, err := pixel.SegmentationLabelMap(seg, pixel.WithSegmentPriority(3, 1))
+func WithSegmentPriority(segmentNumbers ...int) LabelMapOption {
+	return func(o *labelMapOptions) {
+		o.priority = segmentNumbers
+	}
+}
+
+// SegmentationLabelMap composites the binary segment frames of a Segmentation (SEG)
+// into a label map, with one label frame per spatial location holding the segment
+// number of each pixel.
+//
+// Frames are assigned to segments by Referenced Segment Number (0062,000B) in the
+// Segment Identification Sequence, and grouped into locations by Image Position
+// (Patient) in the Plane Position Sequence or, failing that, by the source image
+// frame in the Derivation Image Sequence. Locations with positions are ordered along
+// the slice normal; otherwise they keep the order in which they first appear.
+//
+// BINARY segmentations must be 1-bit, with frames packed back to back. For
+// FRACTIONAL segmentations a pixel belongs to a segment when its value is at least
+// half of Maximum Fractional Value (0062,000E). Where segments overlap the highest
+// segment number wins unless WithSegmentPriority says otherwise. Only native
+// (uncompressed) pixel data is supported.
+//
+// Example:
+//
+//	lm, err := pixel.SegmentationLabelMap(seg)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	label := lm.At(10, 256, 256)
+//	fmt.Println(lm.Segments[int(label)].Label)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_A.51
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.8.20.2
+func SegmentationLabelMap(ds *dicom.DataSet, opts ...LabelMapOption) (*LabelMap, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+	var options labelMapOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	segments, err := segmentsFromDataSet(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := getUint16(ds, tag.Rows, "Rows")
+	if err != nil {
+		return nil, err
+	}
+	columns, err := getUint16(ds, tag.Columns, "Columns")
+	if err != nil {
+		return nil, err
+	}
+	numberOfFrames := getIntWithDefault(ds, tag.NumberOfFrames, 1)
+	frameSize := int(rows) * int(columns)
+
+	isSet, err := segmentationFramePixels(ds, numberOfFrames, frameSize)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := segmentationLocations(ds, numberOfFrames)
+	if err != nil {
+		return nil, err
+	}
+
+	lm := &LabelMap{
+		Rows:           int(rows),
+		Columns:        int(columns),
+		NumberOfFrames: locations.count,
+		Labels:         make([]uint16, locations.count*frameSize),
+		Positions:      locations.positions,
+		Segments:       segments,
+	}
+
+	rank := func(n int) int {
+		for i, p := range options.priority {
+			if p == n {
+				return i
+			}
+		}
+		return len(options.priority) + math.MaxUint16 - n
+	}
+
+	for frame := 0; frame < numberOfFrames; frame++ {
+		number, err := referencedSegmentNumber(ds, frame)
+		if err != nil {
+			return nil, err
+		}
+		if number < 1 || number > math.MaxUint16 {
+			return nil, &PixelDataError{
+				Field:    fmt.Sprintf("frame %d Referenced Segment Number", frame),
+				Expected: "1 to 65535",
+				Actual:   number,
+			}
+		}
+		label := uint16(number)
+		numberRank := rank(number)
+
+		out := lm.Labels[locations.index[frame]*frameSize:]
+		for p := 0; p < frameSize; p++ {
+			if !isSet(frame*frameSize + p) {
+				continue
+			}
+			if existing := out[p]; existing == 0 || numberRank < rank(int(existing)) {
+				out[p] = label
+			}
+		}
+	}
+
+	return lm, nil
+}
+
+// segmentsFromDataSet reads the Segment Sequence.
+func segmentsFromDataSet(ds *dicom.DataSet) (map[int]Segment, error) {
+	items, err := ds.GetSequenceItems(tag.SegmentSequence)
+	if err != nil {
+		return nil, &MissingAttributeError{
+			AttributeName: "SegmentSequence",
+			Tag:           tag.SegmentSequence.String(),
+		}
+	}
+
+	segments := make(map[int]Segment, len(items))
+	for i, item := range items {
+		number := getIntWithDefault(item, tag.SegmentNumber, 0)
+		if number < 1 {
+			return nil, fmt.Errorf("segment sequence item %d: %w: SegmentNumber", i, ErrMissingRequiredAttribute)
+		}
+		segments[number] = Segment{
+			Number:         number,
+			Label:          datasetString(item, tag.SegmentLabel),
+			AnatomicRegion: segmentCode(item, tag.AnatomicRegionSequence),
+			PropertyType:   segmentCode(item, tag.SegmentedPropertyTypeCodeSequence),
+		}
+	}
+	return segments, nil
+}
+
+// segmentCode returns the first item of the code sequence seqTag, or nil if absent.
+func segmentCode(item *dicom.DataSet, seqTag tag.Tag) *SegmentCode {
+	codes, err := item.GetSequenceItems(seqTag)
+	if err != nil || len(codes) == 0 {
+		return nil
+	}
+	return &SegmentCode{
+		CodeValue:              datasetString(codes[0], tag.CodeValue),
+		CodingSchemeDesignator: datasetString(codes[0], tag.CodingSchemeDesignator),
+		CodeMeaning:            datasetString(codes[0], tag.CodeMeaning),
+	}
+}
+
+// segmentationFramePixels returns a function reporting whether pixel i (counted
+// across all frames) belongs to its frame's segment.
+func segmentationFramePixels(ds *dicom.DataSet, numberOfFrames, frameSize int) (func(i int) bool, error) {
+	if tsUID := datasetString(ds, tag.TransferSyntaxUID); tsUID != "" && isEncapsulated(tsUID) {
+		return nil, &TransferSyntaxError{UID: tsUID}
+	}
+
+	elem, err := ds.Get(tag.PixelData)
+	if err != nil {
+		return nil, &MissingAttributeError{
+			AttributeName: "PixelData",
+			Tag:           tag.PixelData.String(),
+		}
+	}
+	data := elem.Value().Bytes()
+
+	bitsAllocated, err := getUint16(ds, tag.BitsAllocated, "BitsAllocated")
+	if err != nil {
+		return nil, err
+	}
+	segmentationType := strings.ToUpper(datasetString(ds, tag.SegmentationType))
+
+	switch segmentationType {
+	case "BINARY", "":
+		if bitsAllocated != 1 {
+			return nil, &PixelDataError{Field: "BINARY segmentation BitsAllocated", Expected: 1, Actual: int(bitsAllocated)}
+		}
+		if needed := (numberOfFrames*frameSize + 7) / 8; len(data) < needed {
+			return nil, &PixelDataError{Field: "PixelData length (bytes)", Expected: needed, Actual: len(data)}
+		}
+		// Bits are packed across frame boundaries, least significant bit first
+		return func(i int) bool { return data[i/8]&(1<<(i%8)) != 0 }, nil
+
+	case "FRACTIONAL":
+		if bitsAllocated != 8 {
+			return nil, &PixelDataError{Field: "FRACTIONAL segmentation BitsAllocated", Expected: 8, Actual: int(bitsAllocated)}
+		}
+		if needed := numberOfFrames * frameSize; len(data) < needed {
+			return nil, &PixelDataError{Field: "PixelData length (bytes)", Expected: needed, Actual: len(data)}
+		}
+		maxValue := getIntWithDefault(ds, tag.MaximumFractionalValue, 255)
+		if maxValue < 1 {
+			maxValue = 255
+		}
+		return func(i int) bool { return 2*int(data[i]) >= maxValue }, nil
+
+	default:
+		return nil, &PixelDataError{Field: "SegmentationType", Expected: "BINARY or FRACTIONAL", Actual: segmentationType}
+	}
+}
+
+// referencedSegmentNumber returns the segment a frame belongs to.
+func referencedSegmentNumber(ds *dicom.DataSet, frame int) (int, error) {
+	v, err := dicom.FunctionalGroupValue(ds, frame, tag.SegmentIdentificationSequence, tag.ReferencedSegmentNumber)
+	if err != nil {
+		return 0, fmt.Errorf("frame %d: %w: ReferencedSegmentNumber", frame, ErrMissingRequiredAttribute)
+	}
+	intVal, ok := v.(*value.IntValue)
+	if !ok || len(intVal.Ints()) == 0 {
+		return 0, &PixelDataError{
+			Field:    fmt.Sprintf("frame %d Referenced Segment Number", frame),
+			Expected: "US value",
+			Actual:   v.String(),
+		}
+	}
+	return int(intVal.Ints()[0]), nil
+}
+
+// segmentationLocationMap assigns segmentation frames to label frames.
+type segmentationLocationMap struct {
+	count     int
+	index     []int        // label frame of each segmentation frame
+	positions [][3]float64 // position of each label frame, nil if unknown
+}
+
+// segmentationLocations groups frames by spatial location.
+func segmentationLocations(ds *dicom.DataSet, numberOfFrames int) (*segmentationLocationMap, error) {
+	keys := make([]string, numberOfFrames)
+	framePositions := make([][3]float64, numberOfFrames)
+	havePositions := true
+	for frame := 0; frame < numberOfFrames; frame++ {
+		pos, err := PlanePosition(ds, frame)
+		if err != nil {
+			havePositions = false
+			break
+		}
+		framePositions[frame] = pos
+		keys[frame] = fmt.Sprintf("%.4f\\%.4f\\%.4f", pos[0], pos[1], pos[2])
+	}
+	if !havePositions {
+		for frame := 0; frame < numberOfFrames; frame++ {
+			key, err := sourceFrameKey(ds, frame)
+			if err != nil {
+				return nil, err
+			}
+			keys[frame] = key
+		}
+	}
+
+	// Distinct locations in order of first appearance
+	var order []string
+	first := make(map[string]int)
+	for frame, key := range keys {
+		if _, ok := first[key]; !ok {
+			first[key] = frame
+			order = append(order, key)
+		}
+	}
+
+	if havePositions {
+		if orient, err := PlaneOrientation(ds, 0); err == nil {
+			normal := [3]float64{
+				orient[1]*orient[5] - orient[2]*orient[4],
+				orient[2]*orient[3] - orient[0]*orient[5],
+				orient[0]*orient[4] - orient[1]*orient[3],
+			}
+			distance := func(key string) float64 {
+				pos := framePositions[first[key]]
+				return pos[0]*normal[0] + pos[1]*normal[1] + pos[2]*normal[2]
+			}
+			sort.SliceStable(order, func(i, j int) bool { return distance(order[i]) < distance(order[j]) })
+		}
+	}
+
+	locations := &segmentationLocationMap{count: len(order), index: make([]int, numberOfFrames)}
+	indexOf := make(map[string]int, len(order))
+	for i, key := range order {
+		indexOf[key] = i
+		if havePositions {
+			locations.positions = append(locations.positions, framePositions[first[key]])
+		}
+	}
+	for frame, key := range keys {
+		locations.index[frame] = indexOf[key]
+	}
+	return locations, nil
+}
+
+// sourceFrameKey identifies the source image frame a segmentation frame was derived
+// from, using the Derivation Image Sequence of the frame's functional groups.
+func sourceFrameKey(ds *dicom.DataSet, frame int) (string, error) {
+	missing := fmt.Errorf("frame %d: %w: Plane Position or Derivation Image Sequence", frame, ErrMissingRequiredAttribute)
+
+	perFrame, err := ds.GetSequenceItems(tag.PerFrameFunctionalGroupsSequence)
+	if err != nil || frame >= len(perFrame) {
+		return "", missing
+	}
+	derivations, err := perFrame[frame].GetSequenceItems(tag.DerivationImageSequence)
+	if err != nil || len(derivations) == 0 {
+		return "", missing
+	}
+	if !derivations[0].Contains(tag.SourceImageSequence) {
+		return "", missing
+	}
+	// Only the instance and frames identify the source; its SOP class may be absent
+	sources, err := derivations[0].GetSOPReferences(tag.SourceImageSequence, dicom.ReferenceParseOptions{AllowMissingUIDs: true})
+	if err != nil {
+		return "", fmt.Errorf("frame %d source image: %w", frame, err)
+	}
+	if len(sources) == 0 || sources[0].SOPInstanceUID == "" {
+		return "", missing
+	}
+	return fmt.Sprintf("%s:%v", sources[0].SOPInstanceUID, sources[0].Frames), nil
+}
@@ -0,0 +1,172 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addSegUint16 sets a US attribute.
+func addSegUint16(t *testing.T, ds *dicom.DataSet, tg tag.Tag, n int64) {
+	t.Helper()
+	val, err := value.NewIntValue(vr.UnsignedShort, []int64{n})
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, vr.UnsignedShort, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+// newSegment returns a Segment Sequence item.
+func newSegment(t *testing.T, number int64, label, regionCode, regionMeaning string) *dicom.DataSet {
+	item := dicom.NewDataSet()
+	addSegUint16(t, item, tag.SegmentNumber, number)
+	addGSPSString(t, item, tag.SegmentLabel, vr.LongString, label)
+	region := dicom.NewDataSet()
+	addGSPSString(t, region, tag.CodeValue, vr.ShortString, regionCode)
+	addGSPSString(t, region, tag.CodingSchemeDesignator, vr.ShortString, "SCT")
+	addGSPSString(t, region, tag.CodeMeaning, vr.LongString, regionMeaning)
+	addGSPSSequence(t, item, tag.AnatomicRegionSequence, region)
+	return item
+}
+
+// newSegmentationDataSet returns a 2x2 BINARY segmentation with three frames:
+//
+//	frame 0: segment 1 at z=1, pixels 1 1 / 1 0
+//	frame 1: segment 1 at z=0, pixels 1 1 / 0 0
+//	frame 2: segment 2 at z=1, pixels 0 1 / 1 1
+func newSegmentationDataSet(t *testing.T) *dicom.DataSet {
+	ds := dicom.NewDataSet()
+	addGSPSString(t, ds, tag.SegmentationType, vr.CodeString, "BINARY")
+	addGSPSString(t, ds, tag.NumberOfFrames, vr.IntegerString, "3")
+	addSegUint16(t, ds, tag.Rows, 2)
+	addSegUint16(t, ds, tag.Columns, 2)
+	addSegUint16(t, ds, tag.BitsAllocated, 1)
+	addGSPSSequence(t, ds, tag.SegmentSequence,
+		newSegment(t, 1, "Liver", "10200004", "Liver"),
+		newSegment(t, 2, "Tumor", "10200004", "Liver"))
+
+	shared := dicom.NewDataSet()
+	addGSPSSequence(t, shared, tag.PlaneOrientationSequence, newMacroItem(t, map[tag.Tag][]string{
+		tag.ImageOrientationPatient: {"1", "0", "0", "0", "1", "0"},
+	}))
+	addGSPSSequence(t, ds, tag.SharedFunctionalGroupsSequence, shared)
+
+	var frames []*dicom.DataSet
+	for _, f := range []struct {
+		segment int64
+		z       string
+	}{{1, "1"}, {1, "0"}, {2, "1"}} {
+		item := dicom.NewDataSet()
+		ident := dicom.NewDataSet()
+		addSegUint16(t, ident, tag.ReferencedSegmentNumber, f.segment)
+		addGSPSSequence(t, item, tag.SegmentIdentificationSequence, ident)
+		addGSPSSequence(t, item, tag.PlanePositionSequence, newMacroItem(t, map[tag.Tag][]string{
+			tag.ImagePositionPatient: {"0", "0", f.z},
+		}))
+		frames = append(frames, item)
+	}
+	addGSPSSequence(t, ds, tag.PerFrameFunctionalGroupsSequence, frames...)
+
+	// Bits 0-2, 4-5 and 9-11, packed least significant bit first
+	setValidatePixelData(t, ds, []byte{0x37, 0x0E})
+	return ds
+}
+
+func TestSegmentationLabelMap(t *testing.T) {
+	ds := newSegmentationDataSet(t)
+
+	lm, err := SegmentationLabelMap(ds)
+	require.NoError(t, err)
+	assert.Equal(t, 2, lm.Rows)
+	assert.Equal(t, 2, lm.Columns)
+	assert.Equal(t, 2, lm.NumberOfFrames)
+
+	// Locations are sorted along the normal; segment 2 wins the overlap
+	assert.Equal(t, []uint16{1, 1, 0, 0, 1, 2, 2, 2}, lm.Labels)
+	assert.Equal(t, [][3]float64{{0, 0, 0}, {0, 0, 1}}, lm.Positions)
+	assert.Equal(t, uint16(2), lm.At(1, 1, 1))
+	assert.Equal(t, uint16(0), lm.At(2, 0, 0))
+
+	require.Len(t, lm.Segments, 2)
+	assert.Equal(t, "Tumor", lm.Segments[2].Label)
+	require.NotNil(t, lm.Segments[1].AnatomicRegion)
+	assert.Equal(t, SegmentCode{"10200004", "SCT", "Liver"}, *lm.Segments[1].AnatomicRegion)
+	assert.Nil(t, lm.Segments[1].PropertyType)
+
+	t.Run("priority", func(t *testing.T) {
+		lm, err := SegmentationLabelMap(ds, WithSegmentPriority(1))
+		require.NoError(t, err)
+		assert.Equal(t, []uint16{1, 1, 0, 0, 1, 1, 1, 2}, lm.Labels)
+	})
+}
+
+func TestSegmentationLabelMap_SourceImages(t *testing.T) {
+	ds := newSegmentationDataSet(t)
+	perFrame, err := ds.GetSequenceItems(tag.PerFrameFunctionalGroupsSequence)
+	require.NoError(t, err)
+
+	// Replace plane positions with source image references
+	for i, uid := range []string{"1.2.3.2", "1.2.3.1", "1.2.3.2"} {
+		require.NoError(t, perFrame[i].Remove(tag.PlanePositionSequence))
+		source := dicom.NewDataSet()
+		addGSPSString(t, source, tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier, uid)
+		derivation := dicom.NewDataSet()
+		addGSPSSequence(t, derivation, tag.SourceImageSequence, source)
+		addGSPSSequence(t, perFrame[i], tag.DerivationImageSequence, derivation)
+	}
+	addGSPSSequence(t, ds, tag.PerFrameFunctionalGroupsSequence, perFrame...)
+
+	lm, err := SegmentationLabelMap(ds)
+	require.NoError(t, err)
+	assert.Nil(t, lm.Positions)
+	// Locations keep their order of first appearance
+	assert.Equal(t, []uint16{1, 2, 2, 2, 1, 1, 0, 0}, lm.Labels)
+}
+
+func TestSegmentationLabelMap_Fractional(t *testing.T) {
+	ds := newSegmentationDataSet(t)
+	addGSPSString(t, ds, tag.SegmentationType, vr.CodeString, "FRACTIONAL")
+	addSegUint16(t, ds, tag.BitsAllocated, 8)
+	addSegUint16(t, ds, tag.MaximumFractionalValue, 100)
+	setValidatePixelData(t, ds, []byte{
+		90, 50, 49, 0, // frame 0
+		100, 0, 0, 0, // frame 1
+		0, 0, 0, 60, // frame 2
+	})
+
+	lm, err := SegmentationLabelMap(ds)
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{1, 0, 0, 0, 1, 1, 0, 2}, lm.Labels)
+}
+
+func TestSegmentationLabelMap_Errors(t *testing.T) {
+	t.Run("missing segment sequence", func(t *testing.T) {
+		ds := newSegmentationDataSet(t)
+		require.NoError(t, ds.Remove(tag.SegmentSequence))
+		_, err := SegmentationLabelMap(ds)
+		assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+	})
+
+	t.Run("truncated pixel data", func(t *testing.T) {
+		ds := newSegmentationDataSet(t)
+		addGSPSString(t, ds, tag.NumberOfFrames, vr.IntegerString, "5")
+		_, err := SegmentationLabelMap(ds)
+		assert.ErrorIs(t, err, ErrInvalidPixelData)
+	})
+
+	t.Run("compressed", func(t *testing.T) {
+		ds := newSegmentationDataSet(t)
+		addGSPSString(t, ds, tag.TransferSyntaxUID, vr.UniqueIdentifier, "1.2.840.10008.1.2.5")
+		_, err := SegmentationLabelMap(ds)
+		assert.ErrorIs(t, err, ErrUnsupportedTransferSyntax)
+	})
+
+	_, err := SegmentationLabelMap(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+const (
+	// softTissueWindowCenter and softTissueWindowWidth are the CT soft tissue window,
+	// in Hounsfield Units, used when a CT image carries no window of its own.
+	softTissueWindowCenter = 40
+	softTissueWindowWidth  = 400
+
+	// projectionLowPercentile and projectionHighPercentile bound the window of
+	// projection radiographs, so that collimation, markers and direct exposure at
+	// the extremes of the histogram do not flatten the contrast of the anatomy.
+	projectionLowPercentile  = 0.01
+	projectionHighPercentile = 0.99
+)
+
+// SmartWindow returns a default display window for pd, chosen by the Modality
+// (0008,0060) of ds the way a viewer would:
+//   - CT: the image's own Window Center (0028,1050) and Width (0028,1051), or the
+//     soft tissue window (center 40 HU, width 400 HU)
+//   - CR, DX, MG, RG, PX and IO projection radiographs: the range between the 1st and
+//     99th percentiles of the pixel values
+//   - US: the full range of the pixel values, or of the bit depth for colour images
+//   - other modalities: the image's own window, or the full range as AutoWindow
+//
+// Padding pixels identified by PaddingMask are ignored, and windows computed from the
+// pixels are in modality values (after Rescale Slope and Intercept), as expected by
+// ApplyWindowLevel after ApplyModalityLUT.
+//
+// Returns an error if ds or pd is nil or the pixels cannot be summarized.
+//
+// Example:
+//
+//	center, width, err := pixel.SmartWindow(ds, pd)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	display, err := pixel.ApplyWindowLevel(rescaled, center, width, 8)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.11.2.1.2
+func SmartWindow(ds *dicom.DataSet, pd *PixelData) (center, width float64, err error) {
+	if ds == nil {
+		return 0, 0, fmt.Errorf("dataset is nil")
+	}
+	if pd == nil {
+		return 0, 0, fmt.Errorf("pixel data is nil")
+	}
+
+	var wl *WindowLevel
+	switch modality := strings.ToUpper(datasetString(ds, tag.Modality)); modality {
+	case "CT":
+		wl, err = ExtractWindowLevelFromDataSet(ds)
+		if err != nil || wl.WindowWidth <= 0 {
+			wl, err = &WindowLevel{WindowCenter: softTissueWindowCenter, WindowWidth: softTissueWindowWidth}, nil
+		}
+	case "CR", "DX", "MG", "RG", "PX", "IO":
+		wl, err = percentileWindow(ds, pd, projectionLowPercentile, projectionHighPercentile)
+	case "US":
+		if pd.SamplesPerPixel != 1 {
+			bits := float64(effectiveBitsStored(pd))
+			wl = &WindowLevel{WindowCenter: math.Exp2(bits) / 2, WindowWidth: math.Exp2(bits)}
+		} else {
+			wl, err = AutoWindow(ds, pd)
+		}
+	default:
+		wl, err = ExtractWindowLevelFromDataSet(ds)
+		if err != nil || wl.WindowWidth <= 0 {
+			wl, err = AutoWindow(ds, pd)
+		}
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return wl.WindowCenter, wl.WindowWidth, nil
+}
+
+// percentileWindow returns the window between the low and high percentiles (0-1) of
+// the stored values of pd that are not padding.
+func percentileWindow(ds *dicom.DataSet, pd *PixelData, low, high float64) (*WindowLevel, error) {
+	if pd.SamplesPerPixel != 1 {
+		return nil, fmt.Errorf("percentile windowing only applies to grayscale images (SamplesPerPixel=1), got %d",
+			pd.SamplesPerPixel)
+	}
+	if pd.BitsAllocated != 8 && pd.BitsAllocated != 16 {
+		return nil, fmt.Errorf("percentile windowing requires 8 or 16-bit data, got %d", pd.BitsAllocated)
+	}
+	mask, err := PaddingMask(ds, pd)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []int64
+	for i, v := range storedValues(pd) {
+		if mask == nil || !mask[i] {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no pixels outside the padding")
+	}
+	slices.Sort(values)
+
+	at := func(p float64) float64 {
+		return float64(values[int(math.Round(p*float64(len(values)-1)))])
+	}
+	return windowForRange(ds, at(low), at(high)), nil
+}
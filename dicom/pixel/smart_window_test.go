@@ -0,0 +1,87 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSmartWindow_CT(t *testing.T) {
+	ds, pd := newPaddedCT(t)
+	addGSPSString(t, ds, tag.Modality, vr.CodeString, "CT")
+
+	center, width, err := SmartWindow(ds, pd)
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, center, "soft tissue default")
+	assert.Equal(t, 400.0, width)
+
+	addGSPSString(t, ds, tag.WindowCenter, vr.DecimalString, "-600")
+	addGSPSString(t, ds, tag.WindowWidth, vr.DecimalString, "1500")
+	center, width, err = SmartWindow(ds, pd)
+	require.NoError(t, err)
+	assert.Equal(t, -600.0, center, "the image's own window is preferred")
+	assert.Equal(t, 1500.0, width)
+}
+
+func TestSmartWindow_Projection(t *testing.T) {
+	// 1000 pixels from 0 to 999 plus two saturated outliers
+	samples := make([]uint16, 0, 1002)
+	for i := range 1000 {
+		samples = append(samples, uint16(i))
+	}
+	samples = append(samples, 4095, 4095)
+	pd, err := NewPixelDataFromUint16(samples, 1002, 1)
+	require.NoError(t, err)
+
+	ds := dicom.NewDataSet()
+	addGSPSString(t, ds, tag.Modality, vr.CodeString, "DX")
+
+	center, width, err := SmartWindow(ds, pd)
+	require.NoError(t, err)
+	// The 1st and 99th percentiles are 10 and 991; the outliers are ignored
+	assert.Equal(t, (10.0+991.0)/2, center)
+	assert.Equal(t, 981.0, width)
+}
+
+func TestSmartWindow_US(t *testing.T) {
+	ds := dicom.NewDataSet()
+	addGSPSString(t, ds, tag.Modality, vr.CodeString, "US")
+
+	gray, err := NewPixelDataFromUint8([]uint8{20, 40, 60, 220}, 2, 2)
+	require.NoError(t, err)
+	center, width, err := SmartWindow(ds, gray)
+	require.NoError(t, err)
+	assert.Equal(t, 120.0, center, "full range of the pixel values")
+	assert.Equal(t, 200.0, width)
+
+	rgb, err := NewPixelDataFromRGB([]byte{1, 2, 3}, 1, 1)
+	require.NoError(t, err)
+	center, width, err = SmartWindow(ds, rgb)
+	require.NoError(t, err)
+	assert.Equal(t, 128.0, center, "full range of the bit depth")
+	assert.Equal(t, 256.0, width)
+}
+
+func TestSmartWindow_OtherModality(t *testing.T) {
+	ds, pd := newPaddedCT(t)
+	addGSPSString(t, ds, tag.Modality, vr.CodeString, "PT")
+
+	wl, err := AutoWindow(ds, pd)
+	require.NoError(t, err)
+	center, width, err := SmartWindow(ds, pd)
+	require.NoError(t, err)
+	assert.Equal(t, wl.WindowCenter, center)
+	assert.Equal(t, wl.WindowWidth, width)
+}
+
+func TestSmartWindow_Errors(t *testing.T) {
+	_, pd := newPaddedCT(t)
+	_, _, err := SmartWindow(nil, pd)
+	assert.Error(t, err)
+	_, _, err = SmartWindow(dicom.NewDataSet(), nil)
+	assert.Error(t, err)
+}
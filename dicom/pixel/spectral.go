@@ -0,0 +1,281 @@
+package pixel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// SpectralImageKind classifies the result a CT image holds with respect to the X-ray
+// energies it was acquired with.
+type SpectralImageKind int
+
+const (
+	// SpectralImageConventional is an image with no multi-energy result type, as
+	// from a single-energy acquisition.
+	SpectralImageConventional SpectralImageKind = iota
+	// SpectralImageEnergyWeighted combines the data of several energies with the
+	// Energy Weighting Factor (0018,9353) of each source.
+	SpectralImageEnergyWeighted
+	// SpectralImageMonoenergetic is a virtual monoenergetic image (Image Type
+	// Value 4 VMI), approximating an acquisition at a single photon energy.
+	SpectralImageMonoenergetic
+	// SpectralImageMaterialDecomposition is a material decomposition result, such
+	// as a material-specific or material-removed (virtual non-contrast) image or an
+	// effective atomic number or electron density map.
+	SpectralImageMaterialDecomposition
+)
+
+// String returns a short name for the kind, e.g. "monoenergetic".
+func (k SpectralImageKind) String() string {
+	switch k {
+	case SpectralImageConventional:
+		return "conventional"
+	case SpectralImageEnergyWeighted:
+		return "energy weighted"
+	case SpectralImageMonoenergetic:
+		return "monoenergetic"
+	case SpectralImageMaterialDecomposition:
+		return "material decomposition"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(k))
+	}
+}
+
+// SpectralSource is one X-ray source of a CT acquisition.
+type SpectralSource struct {
+	KVP float64 // KVP (0018,0060), peak kilovoltage; 0 when absent
+
+	// EnergyWeightingFactor is the weight of this source's data in a multiple
+	// energy composition image (0018,9353); 0 when absent.
+	EnergyWeightingFactor float64
+}
+
+// SpectralCTInfo describes the energy context of a CT image: the X-ray sources it was
+// acquired with and what kind of multi-energy result it holds.
+type SpectralCTInfo struct {
+	// MultiEnergy is true if the image comes from a multi-energy (dual-energy or
+	// spectral) acquisition.
+	MultiEnergy bool
+
+	// Sources holds the primary X-ray source followed by any additional sources,
+	// in sequence order.
+	Sources []SpectralSource
+
+	// Kind classifies the image from ImageTypeValue, or as energy weighted if a
+	// source has an energy weighting factor.
+	Kind SpectralImageKind
+
+	// ImageTypeValue is Value 4 of Image Type (0008,0008), or of the first frame's
+	// Frame Type (0008,9007) in enhanced images, such as "VMI" or "MAT_SPECIFIC".
+	// Empty when absent.
+	ImageTypeValue string
+
+	// MonoenergeticKeV is the Monoenergetic Energy Equivalent (0018,937C) of a
+	// virtual monoenergetic image in keV; 0 when absent.
+	MonoenergeticKeV float64
+}
+
+// KVPs returns the KVP of each source, primary first.
+func (s *SpectralCTInfo) KVPs() []float64 {
+	kvps := make([]float64, len(s.Sources))
+	for i, source := range s.Sources {
+		kvps[i] = source.KVP
+	}
+	return kvps
+}
+
+// SpectralInfo reads the energy context of a dual-energy or spectral CT image, so it
+// can be checked before quantitative analysis.
+//
+// For CT images the primary source is read from KVP (0018,0060) and Energy Weighting
+// Factor (0018,9353), followed by one source per item of the CT Additional X-Ray
+// Source Sequence (0018,9360). Enhanced CT images, which keep these in functional
+// groups, are read from the first frame: one source per item of its CT X-Ray Details
+// Sequence (0018,9325), and the multi-energy sequences from its Per-Frame or Shared
+// Functional Groups. Use FlattenFrame to read another frame.
+//
+// The image is multi-energy if Multi-energy CT Acquisition (0018,9361) is YES, there
+// is more than one source, a Multi-energy CT Acquisition Sequence (0018,9362) is
+// present, or its Image Type Value 4 names a multi-energy result. A dataset with
+// none of these attributes yields a conventional SpectralCTInfo with no sources.
+//
+// Returns an error if:
+//   - ds is nil
+//   - a KVP, Energy Weighting Factor or Monoenergetic Energy Equivalent value is not
+//     a number
+//
+// Example:
+//
+//	info, err := pixel.SpectralInfo(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if info.Kind == pixel.SpectralImageMonoenergetic {
+//	    fmt.Printf("VMI at %g keV from %v kVp\n", info.MonoenergeticKeV, info.KVPs())
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.8.2.1
+func SpectralInfo(ds *dicom.DataSet) (*SpectralCTInfo, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	info := &SpectralCTInfo{}
+	var err error
+	if info.Sources, err = spectralSources(ds); err != nil {
+		return nil, err
+	}
+
+	for _, item := range spectralItems(ds, tag.MultienergyCTCharacteristicsSequence, tag.MultienergyCTProcessingSequence) {
+		keV, ok, err := spectralFloat(item, tag.MonoenergeticEnergyEquivalent)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			info.MonoenergeticKeV = keV
+			break
+		}
+	}
+
+	info.ImageTypeValue = spectralImageTypeValue(ds)
+	switch {
+	case info.ImageTypeValue == "VMI":
+		info.Kind = SpectralImageMonoenergetic
+	case strings.HasPrefix(info.ImageTypeValue, "MAT_"),
+		info.ImageTypeValue == "EFF_ATOMIC_NUM",
+		info.ImageTypeValue == "ELECTRON_DENSITY":
+		info.Kind = SpectralImageMaterialDecomposition
+	case info.ImageTypeValue == "ENERGY_PROP_WT":
+		info.Kind = SpectralImageEnergyWeighted
+	default:
+		for _, source := range info.Sources {
+			if source.EnergyWeightingFactor != 0 {
+				info.Kind = SpectralImageEnergyWeighted
+			}
+		}
+	}
+
+	acquisition := []*dicom.DataSet{ds}
+	acquisition = append(acquisition, spectralItems(ds, tag.CTAcquisitionTypeSequence)...)
+	for _, item := range acquisition {
+		if flag, err := getString(item, tag.MultienergyCTAcquisition, "MultienergyCTAcquisition"); err == nil && strings.TrimSpace(flag) == "YES" {
+			info.MultiEnergy = true
+		}
+	}
+	if len(info.Sources) > 1 || info.Kind != SpectralImageConventional ||
+		len(spectralItems(ds, tag.MultienergyCTAcquisitionSequence)) > 0 {
+		info.MultiEnergy = true
+	}
+
+	return info, nil
+}
+
+// spectralSources reads the X-ray sources of ds, primary first.
+func spectralSources(ds *dicom.DataSet) ([]SpectralSource, error) {
+	readSource := func(item *dicom.DataSet) (SpectralSource, bool, error) {
+		kvp, hasKVP, err := spectralFloat(item, tag.KVP)
+		if err != nil {
+			return SpectralSource{}, false, err
+		}
+		weight, hasWeight, err := spectralFloat(item, tag.EnergyWeightingFactor)
+		if err != nil {
+			return SpectralSource{}, false, err
+		}
+		return SpectralSource{KVP: kvp, EnergyWeightingFactor: weight}, hasKVP || hasWeight, nil
+	}
+
+	primary, ok, err := readSource(ds)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// Enhanced CT: one CT X-Ray Details item per source
+		var sources []SpectralSource
+		for _, item := range spectralItems(ds, tag.CTXRayDetailsSequence) {
+			source, _, err := readSource(item)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, source)
+		}
+		return sources, nil
+	}
+
+	sources := []SpectralSource{primary}
+	additional, _ := ds.GetSequenceItems(tag.CTAdditionalXRaySourceSequence)
+	for _, item := range additional {
+		source, _, err := readSource(item)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// spectralItems returns the items of the first of sequenceTags found at the top level
+// of ds or, for enhanced images, in the first frame's Per-Frame or Shared Functional
+// Groups.
+func spectralItems(ds *dicom.DataSet, sequenceTags ...tag.Tag) []*dicom.DataSet {
+	containers := []*dicom.DataSet{ds}
+	for _, groupsTag := range []tag.Tag{tag.PerFrameFunctionalGroupsSequence, tag.SharedFunctionalGroupsSequence} {
+		if groups, err := ds.GetSequenceItems(groupsTag); err == nil && len(groups) > 0 {
+			containers = append(containers, groups[0])
+		}
+	}
+
+	for _, t := range sequenceTags {
+		for _, container := range containers {
+			if items, err := container.GetSequenceItems(t); err == nil && len(items) > 0 {
+				return items
+			}
+		}
+	}
+	return nil
+}
+
+// spectralImageTypeValue returns Value 4 of the first frame's Frame Type, or else
+// of Image Type.
+func spectralImageTypeValue(ds *dicom.DataSet) string {
+	for _, item := range spectralItems(ds, tag.CTImageFrameTypeSequence) {
+		if v := imageTypeValue(item, tag.FrameType, 3); v != "" {
+			return v
+		}
+	}
+	return imageTypeValue(ds, tag.ImageType, 3)
+}
+
+// imageTypeValue returns value index of the multi-valued CS attribute t, trimmed, or
+// "" if it is absent.
+func imageTypeValue(ds *dicom.DataSet, t tag.Tag, index int) string {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return ""
+	}
+	strVal, ok := elem.Value().(*value.StringValue)
+	if !ok || index >= len(strVal.Strings()) {
+		return ""
+	}
+	return strings.TrimSpace(strVal.Strings()[index])
+}
+
+// spectralFloat returns the first value of the numeric attribute t in ds. ok is
+// false if it is absent or empty.
+func spectralFloat(ds *dicom.DataSet, t tag.Tag) (float64, bool, error) {
+	if !ds.Contains(t) {
+		return 0, false, nil
+	}
+	values, err := ds.GetFloats(t)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid %s: %w", attributeKeyword(t), err)
+	}
+	if len(values) == 0 {
+		return 0, false, nil
+	}
+	return values[0], true, nil
+}
@@ -0,0 +1,147 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpectralInfo_Conventional(t *testing.T) {
+	info, err := SpectralInfo(dicom.NewDataSet())
+	require.NoError(t, err)
+	assert.Equal(t, &SpectralCTInfo{}, info)
+
+	ds := dicom.NewDataSet()
+	addGSPSString(t, ds, tag.ImageType, vr.CodeString, "ORIGINAL", "PRIMARY", "AXIAL")
+	addGSPSString(t, ds, tag.KVP, vr.DecimalString, "120")
+	info, err = SpectralInfo(ds)
+	require.NoError(t, err)
+	assert.False(t, info.MultiEnergy)
+	assert.Equal(t, SpectralImageConventional, info.Kind)
+	assert.Equal(t, []float64{120}, info.KVPs())
+}
+
+func TestSpectralInfo_DualSource(t *testing.T) {
+	ds := dicom.NewDataSet()
+	addGSPSString(t, ds, tag.ImageType, vr.CodeString, "DERIVED", "PRIMARY", "AXIAL")
+	addGSPSString(t, ds, tag.KVP, vr.DecimalString, "80")
+	addGSPSFloats(t, ds, tag.EnergyWeightingFactor, 0.25)
+
+	additional := dicom.NewDataSet()
+	addGSPSString(t, additional, tag.KVP, vr.DecimalString, "140")
+	addGSPSFloats(t, additional, tag.EnergyWeightingFactor, 0.75)
+	addGSPSSequence(t, ds, tag.CTAdditionalXRaySourceSequence, additional)
+
+	info, err := SpectralInfo(ds)
+	require.NoError(t, err)
+	assert.True(t, info.MultiEnergy)
+	assert.Equal(t, SpectralImageEnergyWeighted, info.Kind)
+	assert.Equal(t, []SpectralSource{
+		{KVP: 80, EnergyWeightingFactor: 0.25},
+		{KVP: 140, EnergyWeightingFactor: 0.75},
+	}, info.Sources)
+	assert.Equal(t, []float64{80, 140}, info.KVPs())
+}
+
+func TestSpectralInfo_ImageTypeKinds(t *testing.T) {
+	tests := []struct {
+		value4 string
+		want   SpectralImageKind
+	}{
+		{"VMI", SpectralImageMonoenergetic},
+		{"MAT_SPECIFIC", SpectralImageMaterialDecomposition},
+		{"MAT_REMOVED", SpectralImageMaterialDecomposition},
+		{"EFF_ATOMIC_NUM", SpectralImageMaterialDecomposition},
+		{"ELECTRON_DENSITY", SpectralImageMaterialDecomposition},
+		{"ENERGY_PROP_WT", SpectralImageEnergyWeighted},
+		{"NONE", SpectralImageConventional},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value4, func(t *testing.T) {
+			ds := dicom.NewDataSet()
+			addGSPSString(t, ds, tag.ImageType, vr.CodeString, "DERIVED", "PRIMARY", "AXIAL", tt.value4)
+			info, err := SpectralInfo(ds)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, info.Kind)
+			assert.Equal(t, tt.value4, info.ImageTypeValue)
+			assert.Equal(t, tt.want != SpectralImageConventional, info.MultiEnergy)
+		})
+	}
+}
+
+func TestSpectralInfo_MonoenergeticCT(t *testing.T) {
+	ds := dicom.NewDataSet()
+	addGSPSString(t, ds, tag.ImageType, vr.CodeString, "DERIVED", "PRIMARY", "AXIAL", "VMI")
+	addGSPSString(t, ds, tag.MultienergyCTAcquisition, vr.CodeString, "YES")
+
+	characteristics := dicom.NewDataSet()
+	addGSPSFloats(t, characteristics, tag.MonoenergeticEnergyEquivalent, 70)
+	addGSPSSequence(t, ds, tag.MultienergyCTCharacteristicsSequence, characteristics)
+
+	info, err := SpectralInfo(ds)
+	require.NoError(t, err)
+	assert.True(t, info.MultiEnergy)
+	assert.Equal(t, SpectralImageMonoenergetic, info.Kind)
+	assert.Equal(t, 70.0, info.MonoenergeticKeV)
+	assert.Empty(t, info.Sources)
+}
+
+func TestSpectralInfo_EnhancedCT(t *testing.T) {
+	ds := dicom.NewDataSet()
+	addGSPSString(t, ds, tag.ImageType, vr.CodeString, "DERIVED", "PRIMARY", "VOLUME", "MIXED")
+
+	// Shared groups: the X-ray details of both sources and the acquisition type
+	lowSource := dicom.NewDataSet()
+	addGSPSString(t, lowSource, tag.KVP, vr.DecimalString, "80")
+	highSource := dicom.NewDataSet()
+	addGSPSString(t, highSource, tag.KVP, vr.DecimalString, "150")
+	acquisitionType := dicom.NewDataSet()
+	addGSPSString(t, acquisitionType, tag.MultienergyCTAcquisition, vr.CodeString, "YES")
+	shared := dicom.NewDataSet()
+	addGSPSSequence(t, shared, tag.CTXRayDetailsSequence, lowSource, highSource)
+	addGSPSSequence(t, shared, tag.CTAcquisitionTypeSequence, acquisitionType)
+	addGSPSSequence(t, ds, tag.SharedFunctionalGroupsSequence, shared)
+
+	// Per-frame groups: the first frame is a material-specific image
+	frameType := dicom.NewDataSet()
+	addGSPSString(t, frameType, tag.FrameType, vr.CodeString, "DERIVED", "PRIMARY", "VOLUME", "MAT_SPECIFIC")
+	frame := dicom.NewDataSet()
+	addGSPSSequence(t, frame, tag.CTImageFrameTypeSequence, frameType)
+	addGSPSSequence(t, ds, tag.PerFrameFunctionalGroupsSequence, frame)
+
+	info, err := SpectralInfo(ds)
+	require.NoError(t, err)
+	assert.True(t, info.MultiEnergy)
+	assert.Equal(t, []float64{80, 150}, info.KVPs())
+	assert.Equal(t, "MAT_SPECIFIC", info.ImageTypeValue, "the frame type takes precedence over MIXED")
+	assert.Equal(t, SpectralImageMaterialDecomposition, info.Kind)
+}
+
+func TestSpectralInfo_Errors(t *testing.T) {
+	_, err := SpectralInfo(nil)
+	assert.Error(t, err)
+
+	ds := dicom.NewDataSet()
+	addGSPSString(t, ds, tag.KVP, vr.DecimalString, "high")
+	_, err = SpectralInfo(ds)
+	assert.Error(t, err)
+
+	ds = dicom.NewDataSet()
+	addGSPSString(t, ds, tag.KVP, vr.DecimalString, "120")
+	additional := dicom.NewDataSet()
+	addGSPSString(t, additional, tag.KVP, vr.DecimalString, "1e")
+	addGSPSSequence(t, ds, tag.CTAdditionalXRaySourceSequence, additional)
+	_, err = SpectralInfo(ds)
+	assert.Error(t, err)
+}
+
+func TestSpectralImageKind_String(t *testing.T) {
+	assert.Equal(t, "conventional", SpectralImageConventional.String())
+	assert.Equal(t, "monoenergetic", SpectralImageMonoenergetic.String())
+	assert.Equal(t, "material decomposition", SpectralImageMaterialDecomposition.String())
+	assert.Equal(t, "unknown (9)", SpectralImageKind(9).String())
+}
@@ -0,0 +1,144 @@
+package pixel
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// SetPixelData stores pd as the native (uncompressed) Pixel Data of ds and updates
+// every attribute that describes it, so the header cannot disagree with the pixels:
+//   - (0028,0010) Rows and (0028,0011) Columns
+//   - (0028,0002) Samples per Pixel and (0028,0004) Photometric Interpretation
+//   - (0028,0100) Bits Allocated, (0028,0101) Bits Stored, (0028,0102) High Bit
+//   - (0028,0103) Pixel Representation
+//   - (0028,0006) Planar Configuration, removed for single-sample images where it
+//     is not permitted
+//   - (0028,0008) Number of Frames, written for multi-frame data, or when already
+//     present
+//
+// The Pixel Data is written as OB for 8-bit samples and OW otherwise, big endian if
+// the dataset's Transfer Syntax UID is Explicit VR Big Endian. If the dataset has an
+// encapsulated (compressed) transfer syntax, it is changed to Explicit VR Little
+// Endian since the stored data is native; use EncodeJPEGBaselineDataSet or
+// EncodeForTransferSyntax to store compressed pixels instead.
+//
+// Returns an error, leaving ds unchanged, if the length of pd's data does not match
+// its dimensions.
+//
+// Example:
+//
+//	pd, _ := pixel.NewPixelDataBuilder().
+//	    WithDimensions(256, 256).
+//	    WithBitsAllocated(16).
+//	    WithPhotometricInterpretation("MONOCHROME2").
+//	    WithNumberOfFrames(10).
+//	    WithPixelData(volume).
+//	    Build()
+//	if err := pixel.SetPixelData(ds, pd); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.3
+func SetPixelData(ds *dicom.DataSet, pd *PixelData) error {
+	if ds == nil {
+		return fmt.Errorf("dataset is nil")
+	}
+	if pd == nil {
+		return fmt.Errorf("pixel data is nil")
+	}
+
+	frames := max(pd.NumberOfFrames, 1)
+	info := &PixelInfo{
+		Rows:            pd.Rows,
+		Columns:         pd.Columns,
+		BitsAllocated:   pd.BitsAllocated,
+		SamplesPerPixel: pd.SamplesPerPixel,
+		NumberOfFrames:  frames,
+	}
+	if err := ValidatePixelData(pd.data, info); err != nil {
+		return err
+	}
+
+	transferSyntaxUID, _ := getString(ds, tag.TransferSyntaxUID, "TransferSyntaxUID")
+	if transferSyntaxUID != "" && isEncapsulated(transferSyntaxUID) {
+		transferSyntaxUID = explicitVRLittleEndianUID
+		if err := setStringElement(ds, tag.TransferSyntaxUID, vr.UniqueIdentifier, transferSyntaxUID); err != nil {
+			return err
+		}
+	}
+
+	data, pixelVR := pd.data, vr.OtherWord
+	if pd.BitsAllocated <= 8 {
+		pixelVR = vr.OtherByte
+	} else if transferSyntaxUID == explicitVRBigEndianUID {
+		data = append([]byte(nil), pd.data...)
+		SwapBytes16(data)
+	}
+
+	for _, attr := range []struct {
+		t tag.Tag
+		v uint16
+	}{
+		{tag.Rows, pd.Rows},
+		{tag.Columns, pd.Columns},
+		{tag.SamplesPerPixel, pd.SamplesPerPixel},
+		{tag.BitsAllocated, pd.BitsAllocated},
+		{tag.BitsStored, pd.BitsStored},
+		{tag.HighBit, pd.HighBit},
+		{tag.PixelRepresentation, pd.PixelRepresentation},
+	} {
+		if err := setUint16Element(ds, attr.t, attr.v); err != nil {
+			return err
+		}
+	}
+
+	if err := setStringElement(ds, tag.PhotometricInterpretation, vr.CodeString, pd.PhotometricInterpretation); err != nil {
+		return err
+	}
+
+	if pd.SamplesPerPixel > 1 {
+		if err := setUint16Element(ds, tag.PlanarConfiguration, pd.PlanarConfiguration); err != nil {
+			return err
+		}
+	} else if ds.Contains(tag.PlanarConfiguration) {
+		if err := ds.Remove(tag.PlanarConfiguration); err != nil {
+			return err
+		}
+	}
+
+	if frames > 1 || ds.Contains(tag.NumberOfFrames) {
+		if err := setStringElement(ds, tag.NumberOfFrames, vr.IntegerString, strconv.Itoa(frames)); err != nil {
+			return err
+		}
+	}
+
+	pixelVal, err := value.NewBytesValue(pixelVR, data)
+	if err != nil {
+		return fmt.Errorf("failed to create pixel data value: %w", err)
+	}
+	pixelElem, err := element.NewElement(tag.PixelData, pixelVR, pixelVal)
+	if err != nil {
+		return fmt.Errorf("failed to create pixel data element: %w", err)
+	}
+	return ds.Set(pixelElem)
+}
+
+// setUint16Element adds or replaces a single-valued US element.
+func setUint16Element(ds *dicom.DataSet, t tag.Tag, n uint16) error {
+	val, err := value.NewIntValue(vr.UnsignedShort, []int64{int64(n)})
+	if err != nil {
+		return fmt.Errorf("failed to create value for %s: %w", t, err)
+	}
+	elem, err := element.NewElement(t, vr.UnsignedShort, val)
+	if err != nil {
+		return fmt.Errorf("failed to create element %s: %w", t, err)
+	}
+	return ds.Set(elem)
+}
@@ -0,0 +1,87 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetPixelData(t *testing.T) {
+	pd := newDigestPixelData(t) // three 4x2 16-bit frames
+
+	// Stale attributes from a previous single-frame colour image
+	stale, err := NewPixelDataFromRGB(make([]byte, 2*2*3), 2, 2)
+	require.NoError(t, err)
+	ds := newExtractDataSet(t, stale, "1.2.840.10008.1.2.1")
+	require.NoError(t, setUint16Element(ds, tag.PlanarConfiguration, 0))
+
+	require.NoError(t, SetPixelData(ds, pd))
+
+	got, err := Extract(ds)
+	require.NoError(t, err)
+	assert.Equal(t, pd.Rows, got.Rows)
+	assert.Equal(t, pd.Columns, got.Columns)
+	assert.Equal(t, 3, got.NumberOfFrames)
+	assert.Equal(t, uint16(1), got.SamplesPerPixel)
+	assert.Equal(t, "MONOCHROME2", got.PhotometricInterpretation)
+	assert.Equal(t, pd.RawBytes(), got.RawBytes())
+	assert.False(t, ds.Contains(tag.PlanarConfiguration), "not permitted for single-sample images")
+
+	elem, err := ds.Get(tag.PixelData)
+	require.NoError(t, err)
+	assert.Equal(t, vr.OtherWord, elem.VR())
+}
+
+func TestSetPixelData_NumberOfFrames(t *testing.T) {
+	single, err := NewPixelDataFromUint8([]uint8{1, 2, 3, 4}, 2, 2)
+	require.NoError(t, err)
+
+	ds := dicom.NewDataSet()
+	require.NoError(t, SetPixelData(ds, single))
+	assert.False(t, ds.Contains(tag.NumberOfFrames), "single-frame images need no Number of Frames")
+	elem, err := ds.Get(tag.PixelData)
+	require.NoError(t, err)
+	assert.Equal(t, vr.OtherByte, elem.VR())
+
+	// An existing Number of Frames is kept in step
+	require.NoError(t, setStringElement(ds, tag.NumberOfFrames, vr.IntegerString, "5"))
+	require.NoError(t, SetPixelData(ds, single))
+	elem, err = ds.Get(tag.NumberOfFrames)
+	require.NoError(t, err)
+	assert.Equal(t, "1", elem.Value().String())
+}
+
+func TestSetPixelData_TransferSyntax(t *testing.T) {
+	pd := newDigestPixelData(t)
+
+	bigEndian := newExtractDataSet(t, pd, explicitVRBigEndianUID)
+	require.NoError(t, SetPixelData(bigEndian, pd))
+	got, err := Extract(bigEndian)
+	require.NoError(t, err)
+	assert.Equal(t, pd.RawBytes(), got.RawBytes())
+
+	rle := newExtractDataSet(t, pd, "1.2.840.10008.1.2.5")
+	require.NoError(t, SetPixelData(rle, pd))
+	elem, err := rle.Get(tag.TransferSyntaxUID)
+	require.NoError(t, err)
+	assert.Equal(t, explicitVRLittleEndianUID, elem.Value().String())
+	got, err = Extract(rle)
+	require.NoError(t, err)
+	assert.Equal(t, pd.RawBytes(), got.RawBytes())
+}
+
+func TestSetPixelData_Errors(t *testing.T) {
+	pd := newDigestPixelData(t)
+	pd.NumberOfFrames = 4 // more frames than data
+
+	ds := dicom.NewDataSet()
+	assert.Error(t, SetPixelData(ds, pd))
+	assert.Equal(t, 0, ds.Len(), "dataset is unchanged on error")
+
+	assert.Error(t, SetPixelData(nil, pd))
+	assert.Error(t, SetPixelData(ds, nil))
+}
@@ -0,0 +1,224 @@
+package pixel
+
+// SyntheticPattern selects the image content produced by NewSyntheticPixelData.
+type SyntheticPattern int
+
+const (
+	// PatternGradient is a horizontal linear gradient from MinValue (left column)
+	// to MaxValue (right column). Every row is identical.
+	PatternGradient SyntheticPattern = iota
+
+	// PatternCheckerboard alternates MinValue and MaxValue in square cells of
+	// CheckerSize pixels, starting with MinValue in the top-left cell.
+	PatternCheckerboard
+
+	// PatternRamp increments by one per pixel in raster order starting at MinValue,
+	// wrapping back to MinValue after MaxValue.
+	PatternRamp
+
+	// PatternHounsfieldRamp is a PatternRamp over signed 16-bit storage where each
+	// stored value equals its Hounsfield unit (Rescale Slope 1, Intercept 0).
+	// MinValue and MaxValue default to -1024 (air) and 3071.
+	PatternHounsfieldRamp
+)
+
+// Default Hounsfield range used by PatternHounsfieldRamp.
+const (
+	syntheticHUMin = -1024
+	syntheticHUMax = 3071
+)
+
+// SyntheticOptions configures NewSyntheticPixelData.
+//
+// Zero values are replaced with defaults:
+//   - Rows, Columns: 64
+//   - BitsAllocated: 16 (8 for RGB)
+//   - BitsStored: BitsAllocated
+//   - NumberOfFrames: 1
+//   - PhotometricInterpretation: "MONOCHROME2"
+//   - CheckerSize: 8
+//   - MinValue, MaxValue: the full range representable in BitsStored
+type SyntheticOptions struct {
+	Pattern SyntheticPattern
+
+	Rows           uint16
+	Columns        uint16
+	NumberOfFrames int
+
+	BitsAllocated uint16 // 8 or 16
+	BitsStored    uint16
+	Signed        bool // PixelRepresentation = 1
+
+	// PhotometricInterpretation is MONOCHROME1, MONOCHROME2 or RGB.
+	// RGB images are always 8-bit unsigned and interleaved; the red channel carries
+	// the pattern, green its inverse, and blue a constant mid-range value.
+	PhotometricInterpretation string
+
+	// CheckerSize is the cell edge length in pixels for PatternCheckerboard.
+	CheckerSize int
+
+	// MinValue and MaxValue bound the generated values. Leave both zero to use the
+	// full range representable in BitsStored.
+	MinValue int
+	MaxValue int
+}
+
+// NewSyntheticPixelData generates deterministic pixel data for tests, demos and
+// viewer placeholders without shipping binary fixtures.
+//
+// Output is fully determined by opts, so statistics are known in advance:
+//   - PatternGradient and PatternCheckerboard reach exactly MinValue and MaxValue
+//     (the checkerboard needs at least two cells along one axis).
+//   - PatternRamp and PatternHounsfieldRamp start at MinValue in the top-left pixel
+//     and reach MaxValue once the image holds MaxValue-MinValue+1 pixels.
+//
+// Every frame of a multi-frame result is identical.
+//
+// Example:
+//
+//	pd := pixel.NewSyntheticPixelData(pixel.SyntheticOptions{
+//	    Pattern: pixel.PatternHounsfieldRamp,
+//	    Rows:    64,
+//	    Columns: 64,
+//	})
+//	// Stored values are HU: -1024, -1023, ... 3071
+//	windowed, _ := pixel.ApplyWindowLevel(pd, 40, 400, 8)
+func NewSyntheticPixelData(opts SyntheticOptions) *PixelData {
+	opts = applyDefaultSyntheticOptions(opts)
+
+	samplesPerPixel := uint16(1)
+	if opts.PhotometricInterpretation == "RGB" {
+		samplesPerPixel = 3
+	}
+
+	bytesPerSample := int(opts.BitsAllocated) / 8
+	pixelsPerFrame := int(opts.Rows) * int(opts.Columns)
+	frameSize := pixelsPerFrame * int(samplesPerPixel) * bytesPerSample
+
+	frame := make([]byte, frameSize)
+	for y := 0; y < int(opts.Rows); y++ {
+		for x := 0; x < int(opts.Columns); x++ {
+			idx := y*int(opts.Columns) + x
+			v := syntheticValue(opts, x, y)
+
+			if samplesPerPixel == 3 {
+				frame[idx*3] = byte(v)
+				frame[idx*3+1] = byte(opts.MaxValue + opts.MinValue - v)
+				frame[idx*3+2] = byte((opts.MaxValue + opts.MinValue) / 2)
+				continue
+			}
+
+			if bytesPerSample == 1 {
+				frame[idx] = byte(v)
+			} else {
+				frame[idx*2] = byte(uint16(v))
+				frame[idx*2+1] = byte(uint16(v) >> 8)
+			}
+		}
+	}
+
+	data := make([]byte, 0, frameSize*opts.NumberOfFrames)
+	for i := 0; i < opts.NumberOfFrames; i++ {
+		data = append(data, frame...)
+	}
+
+	pixelRepresentation := uint16(0)
+	if opts.Signed {
+		pixelRepresentation = 1
+	}
+
+	return &PixelData{
+		Rows:                      opts.Rows,
+		Columns:                   opts.Columns,
+		BitsAllocated:             opts.BitsAllocated,
+		BitsStored:                opts.BitsStored,
+		HighBit:                   opts.BitsStored - 1,
+		PixelRepresentation:       pixelRepresentation,
+		SamplesPerPixel:           samplesPerPixel,
+		PhotometricInterpretation: opts.PhotometricInterpretation,
+		PlanarConfiguration:       0,
+		NumberOfFrames:            opts.NumberOfFrames,
+		data:                      data,
+	}
+}
+
+// applyDefaultSyntheticOptions fills in missing options and clamps invalid ones.
+func applyDefaultSyntheticOptions(opts SyntheticOptions) SyntheticOptions {
+	if opts.Rows == 0 {
+		opts.Rows = 64
+	}
+	if opts.Columns == 0 {
+		opts.Columns = 64
+	}
+	if opts.NumberOfFrames <= 0 {
+		opts.NumberOfFrames = 1
+	}
+	if opts.PhotometricInterpretation == "" {
+		opts.PhotometricInterpretation = "MONOCHROME2"
+	}
+	if opts.CheckerSize <= 0 {
+		opts.CheckerSize = 8
+	}
+
+	if opts.Pattern == PatternHounsfieldRamp {
+		opts.BitsAllocated = 16
+		if opts.BitsStored == 0 {
+			opts.BitsStored = 16
+		}
+		opts.Signed = true
+		if opts.MinValue == 0 && opts.MaxValue == 0 {
+			opts.MinValue = syntheticHUMin
+			opts.MaxValue = syntheticHUMax
+		}
+	}
+
+	if opts.PhotometricInterpretation == "RGB" {
+		opts.BitsAllocated = 8
+		opts.BitsStored = 8
+		opts.Signed = false
+	}
+
+	if opts.BitsAllocated != 8 && opts.BitsAllocated != 16 {
+		opts.BitsAllocated = 16
+	}
+	if opts.BitsStored == 0 || opts.BitsStored > opts.BitsAllocated {
+		opts.BitsStored = opts.BitsAllocated
+	}
+
+	// Clamp the value range to what BitsStored can represent
+	lo, hi := 0, (1<<opts.BitsStored)-1
+	if opts.Signed {
+		lo, hi = -(1 << (opts.BitsStored - 1)), (1<<(opts.BitsStored-1))-1
+	}
+	if opts.MinValue == 0 && opts.MaxValue == 0 {
+		opts.MinValue, opts.MaxValue = lo, hi
+	}
+	opts.MinValue = max(lo, min(opts.MinValue, hi))
+	opts.MaxValue = max(lo, min(opts.MaxValue, hi))
+	if opts.MinValue > opts.MaxValue {
+		opts.MinValue, opts.MaxValue = opts.MaxValue, opts.MinValue
+	}
+
+	return opts
+}
+
+// syntheticValue returns the pattern value at (x, y).
+func syntheticValue(opts SyntheticOptions, x, y int) int {
+	switch opts.Pattern {
+	case PatternCheckerboard:
+		if (x/opts.CheckerSize+y/opts.CheckerSize)%2 == 0 {
+			return opts.MinValue
+		}
+		return opts.MaxValue
+
+	case PatternRamp, PatternHounsfieldRamp:
+		span := opts.MaxValue - opts.MinValue + 1
+		return opts.MinValue + (y*int(opts.Columns)+x)%span
+
+	default: // PatternGradient
+		if opts.Columns <= 1 {
+			return opts.MinValue
+		}
+		return opts.MinValue + x*(opts.MaxValue-opts.MinValue)/(int(opts.Columns)-1)
+	}
+}
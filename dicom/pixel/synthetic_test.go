@@ -0,0 +1,114 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSyntheticPixelData_Defaults(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{})
+
+	assert.Equal(t, uint16(64), pd.Rows)
+	assert.Equal(t, uint16(64), pd.Columns)
+	assert.Equal(t, uint16(16), pd.BitsAllocated)
+	assert.Equal(t, uint16(16), pd.BitsStored)
+	assert.Equal(t, uint16(15), pd.HighBit)
+	assert.Equal(t, "MONOCHROME2", pd.PhotometricInterpretation)
+	assert.Equal(t, 1, pd.NumberOfFrames)
+	assert.Len(t, pd.RawBytes(), 64*64*2)
+}
+
+func TestNewSyntheticPixelData_Gradient(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:       PatternGradient,
+		Rows:          4,
+		Columns:       256,
+		BitsAllocated: 8,
+	})
+
+	arr, ok := pd.Array().([]uint8)
+	require.True(t, ok)
+	require.Len(t, arr, 4*256)
+
+	// Each row runs 0..255 left to right
+	for x := 0; x < 256; x++ {
+		assert.Equal(t, uint8(x), arr[x])
+		assert.Equal(t, uint8(x), arr[3*256+x])
+	}
+}
+
+func TestNewSyntheticPixelData_Checkerboard(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:       PatternCheckerboard,
+		Rows:          4,
+		Columns:       4,
+		BitsAllocated: 16,
+		BitsStored:    12,
+		CheckerSize:   2,
+	})
+
+	arr, ok := pd.Array().([]uint16)
+	require.True(t, ok)
+
+	expected := []uint16{
+		0, 0, 4095, 4095,
+		0, 0, 4095, 4095,
+		4095, 4095, 0, 0,
+		4095, 4095, 0, 0,
+	}
+	assert.Equal(t, expected, arr)
+}
+
+func TestNewSyntheticPixelData_HounsfieldRamp(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern: PatternHounsfieldRamp,
+		Rows:    64,
+		Columns: 64,
+	})
+
+	assert.Equal(t, uint16(1), pd.PixelRepresentation)
+	assert.Equal(t, uint16(16), pd.BitsAllocated)
+
+	arr, ok := pd.Array().([]int16)
+	require.True(t, ok)
+	require.Len(t, arr, 4096)
+
+	// Stored values map 1:1 to HU across the full -1024..3071 range
+	for i, v := range arr {
+		assert.Equal(t, int16(-1024+i), v)
+	}
+}
+
+func TestNewSyntheticPixelData_RampWrapsAndFrames(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:        PatternRamp,
+		Rows:           2,
+		Columns:        5,
+		NumberOfFrames: 3,
+		BitsAllocated:  8,
+		MinValue:       10,
+		MaxValue:       13,
+	})
+
+	frames := pd.Frames()
+	require.Len(t, frames, 3)
+	for _, f := range frames {
+		assert.Equal(t, []uint8{10, 11, 12, 13, 10, 11, 12, 13, 10, 11}, f.Array())
+	}
+}
+
+func TestNewSyntheticPixelData_RGB(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:                   PatternGradient,
+		Rows:                      1,
+		Columns:                   2,
+		BitsAllocated:             16,
+		PhotometricInterpretation: "RGB",
+	})
+
+	assert.Equal(t, uint16(3), pd.SamplesPerPixel)
+	assert.Equal(t, uint16(8), pd.BitsAllocated)
+	assert.Equal(t, []byte{0, 255, 127, 255, 0, 127}, pd.RawBytes())
+}
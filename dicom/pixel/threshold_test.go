@@ -0,0 +1,89 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreshold_ModalityLUT(t *testing.T) {
+	// Stored values with intercept -1024: HU -1024, -24, 276, 476
+	pd, err := NewPixelDataFromUint16([]uint16{0, 1000, 1300, 1500}, 4, 1)
+	require.NoError(t, err)
+
+	ds := dicom.NewDataSet()
+	for _, kv := range []struct {
+		tag tag.Tag
+		val string
+	}{
+		{tag.RescaleIntercept, "-1024"},
+		{tag.RescaleSlope, "1"},
+	} {
+		val, err := value.NewStringValue(vr.DecimalString, []string{kv.val})
+		require.NoError(t, err)
+		elem, err := element.NewElement(kv.tag, vr.DecimalString, val)
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(elem))
+	}
+
+	mask, err := Threshold(pd, 300, 3000, ds)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 1}, mask.Data)
+	assert.Equal(t, "R", mask.Type)
+	assert.Equal(t, [2]int{1, 1}, mask.Origin)
+
+	// Bounds are inclusive
+	mask, err = Threshold(pd, -24, 276, ds)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 1, 0}, mask.Data)
+}
+
+func TestThreshold_StoredValuesWithoutDataSet(t *testing.T) {
+	pd, err := NewPixelDataFromInt16([]int16{-500, 0, 200}, 3, 1)
+	require.NoError(t, err)
+
+	mask, err := Threshold(pd, -600, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 1, 0}, mask.Data)
+	assert.Equal(t, 2, mask.Count())
+}
+
+func TestThreshold_MultiFrame(t *testing.T) {
+	pd := NewSyntheticPixelData(SyntheticOptions{
+		Pattern:        PatternCheckerboard,
+		Rows:           4,
+		Columns:        4,
+		NumberOfFrames: 3,
+		BitsAllocated:  8,
+	})
+
+	mask, err := Threshold(pd, 128, 255, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, mask.NumberOfFrames)
+	require.Len(t, mask.Data, 4*4*3)
+
+	// The mask renders on every frame
+	out, err := RenderOverlays(pd, mask)
+	require.NoError(t, err)
+	assert.Len(t, out.RawBytes(), len(pd.RawBytes()))
+}
+
+func TestThreshold_Errors(t *testing.T) {
+	gray := NewSyntheticPixelData(SyntheticOptions{Rows: 2, Columns: 2})
+	rgb := NewSyntheticPixelData(SyntheticOptions{Rows: 2, Columns: 2, PhotometricInterpretation: "RGB"})
+
+	_, err := Threshold(nil, 0, 1, nil)
+	assert.Error(t, err)
+
+	_, err = Threshold(rgb, 0, 1, nil)
+	assert.Error(t, err)
+
+	_, err = Threshold(gray, 10, 0, nil)
+	assert.Error(t, err)
+}
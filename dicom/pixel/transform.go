@@ -2,16 +2,37 @@ package pixel
 
 import (
 	"fmt"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
 )
 
 // ConvertPhotometricInterpretation converts pixel data between different color spaces.
 //
 // Supported conversions:
-//   - RGB → YBR_FULL
-//   - YBR_FULL → RGB
+//   - RGB → YBR_FULL (RGBToYBRFull)
+//   - RGB → YBR_FULL_422
+//   - YBR_FULL → RGB (YBRFullToRGB)
+//   - YBR_FULL_422 → RGB
+//   - RGB → YBR_PARTIAL_422
+//   - YBR_PARTIAL_422 → RGB
+//   - YBR_PARTIAL_420 → RGB (YBRPartialToRGB, with one Cb and Cr per pixel as
+//     decoded from JPEG)
+//
+// The 422 interpretations are subsampled: each horizontal pair of pixels shares
+// one Cb and Cr. RGBToYBRPartial and YBRPartialToRGB convert without subsampling,
+// as decoders produce. All conversions use the rounded ITU-R BT.601 transforms of
+// RGBToYBRFull and RGBToYBRPartial.
 //   - MONOCHROME1 → MONOCHROME2 (inversion)
 //   - MONOCHROME2 → MONOCHROME1 (inversion)
 //
+// Monochrome inversion maps each sample v to (1 << BitsStored) - 1 - v (-1 - v for
+// signed data), so 12-bit data stored in 16-bit words stays within 0-4095. Pixel
+// Padding Value is not part of PixelData; use InvertPixelPadding to update it in the
+// dataset.
+//
 // Returns a new PixelData with the converted color space.
 //
 // Example:
@@ -31,13 +52,19 @@ func ConvertPhotometricInterpretation(p *PixelData, targetPI string) (*PixelData
 	// Determine conversion path
 	switch {
 	case sourcePI == "RGB" && targetPI == "YBR_FULL":
-		return convertRGBToYBR(p)
+		return RGBToYBRFull(p)
 	case sourcePI == "RGB" && targetPI == "YBR_FULL_422":
-		return convertRGBToYBR422(p)
+		return convertRGBToYBR422(p, rgbToYBRFull, targetPI)
 	case sourcePI == "YBR_FULL" && targetPI == "RGB":
-		return convertYBRToRGB(p)
+		return YBRFullToRGB(p)
 	case sourcePI == "YBR_FULL_422" && targetPI == "RGB":
-		return convertYBR422ToRGB(p)
+		return convertYBR422ToRGB(p, ybrFullToRGB)
+	case sourcePI == "RGB" && targetPI == "YBR_PARTIAL_422":
+		return convertRGBToYBR422(p, rgbToYBRPartial, targetPI)
+	case sourcePI == "YBR_PARTIAL_422" && targetPI == "RGB":
+		return convertYBR422ToRGB(p, ybrPartialToRGB)
+	case sourcePI == "YBR_PARTIAL_420" && targetPI == "RGB":
+		return YBRPartialToRGB(p)
 	case sourcePI == "MONOCHROME1" && targetPI == "MONOCHROME2":
 		return invertMonochrome(p)
 	case sourcePI == "MONOCHROME2" && targetPI == "MONOCHROME1":
@@ -48,132 +75,60 @@ func ConvertPhotometricInterpretation(p *PixelData, targetPI string) (*PixelData
 	}
 }
 
-// convertRGBToYBR converts RGB to YBR_FULL color space.
-//
-// YBR_FULL uses ITU-R BT.601 color space:
+// convertRGBToYBR422 converts RGB to targetPI, YBR_FULL_422 or YBR_PARTIAL_422, with
+// the transform t of that colour space (4:2:2 chroma subsampling).
 //
-//	Y  =  0.299*R + 0.587*G + 0.114*B
-//	Cb = -0.169*R - 0.331*G + 0.500*B + 128
-//	Cr =  0.500*R - 0.419*G - 0.081*B + 128
-func convertRGBToYBR(p *PixelData) (*PixelData, error) {
+// In 4:2:2 subsampling, Cb and Cr are horizontally subsampled by factor of 2: each
+// pair of pixels keeps its own Y and shares the Cb and Cr of its average colour.
+func convertRGBToYBR422(p *PixelData, t colorTransform, targetPI string) (*PixelData, error) {
 	if p.SamplesPerPixel != 3 {
 		return nil, fmt.Errorf("RGB conversion requires SamplesPerPixel=3, got %d", p.SamplesPerPixel)
 	}
 
 	if p.BitsAllocated != 8 {
-		return nil, fmt.Errorf("RGB → YBR conversion currently only supports 8-bit data")
-	}
-
-	data := make([]byte, len(p.data))
-
-	if p.PlanarConfiguration == 0 {
-		// Interleaved: RGBRGBRGB...
-		for i := 0; i < len(p.data); i += 3 {
-			r := float64(p.data[i])
-			g := float64(p.data[i+1])
-			b := float64(p.data[i+2])
-
-			y := 0.299*r + 0.587*g + 0.114*b
-			cb := -0.169*r - 0.331*g + 0.500*b + 128
-			cr := 0.500*r - 0.419*g - 0.081*b + 128
-
-			data[i] = clampUint8(int32(y))
-			data[i+1] = clampUint8(int32(cb))
-			data[i+2] = clampUint8(int32(cr))
-		}
-	} else {
-		// Planar: RRR...GGG...BBB...
-		planeSize := len(p.data) / 3
-		for i := 0; i < planeSize; i++ {
-			r := float64(p.data[i])
-			g := float64(p.data[planeSize+i])
-			b := float64(p.data[2*planeSize+i])
-
-			y := 0.299*r + 0.587*g + 0.114*b
-			cb := -0.169*r - 0.331*g + 0.500*b + 128
-			cr := 0.500*r - 0.419*g - 0.081*b + 128
-
-			data[i] = clampUint8(int32(y))
-			data[planeSize+i] = clampUint8(int32(cb))
-			data[2*planeSize+i] = clampUint8(int32(cr))
-		}
-	}
-
-	result := &PixelData{
-		Rows:                      p.Rows,
-		Columns:                   p.Columns,
-		BitsAllocated:             p.BitsAllocated,
-		BitsStored:                p.BitsStored,
-		HighBit:                   p.HighBit,
-		PixelRepresentation:       p.PixelRepresentation,
-		SamplesPerPixel:           p.SamplesPerPixel,
-		PhotometricInterpretation: "YBR_FULL",
-		PlanarConfiguration:       p.PlanarConfiguration,
-		NumberOfFrames:            p.NumberOfFrames,
-		data:                      data,
-		TransferSyntaxUID:         p.TransferSyntaxUID,
-	}
-
-	return result, nil
-}
-
-// convertRGBToYBR422 converts RGB to YBR_FULL_422 (4:2:2 chroma subsampling).
-//
-// In 4:2:2 subsampling, Cb and Cr are horizontally subsampled by factor of 2.
-func convertRGBToYBR422(p *PixelData) (*PixelData, error) {
-	if p.SamplesPerPixel != 3 {
-		return nil, fmt.Errorf("RGB conversion requires SamplesPerPixel=3, got %d", p.SamplesPerPixel)
-	}
-
-	if p.BitsAllocated != 8 {
-		return nil, fmt.Errorf("RGB → YBR_FULL_422 conversion currently only supports 8-bit data")
+		return nil, fmt.Errorf("RGB → %s conversion currently only supports 8-bit data", targetPI)
 	}
 
 	if p.PlanarConfiguration != 0 {
-		return nil, fmt.Errorf("YBR_FULL_422 requires interleaved data (PlanarConfiguration=0)")
+		return nil, fmt.Errorf("%s requires interleaved data (PlanarConfiguration=0)", targetPI)
 	}
 
 	// For 4:2:2, we subsample Cb/Cr horizontally
-	// Output format: Y0 Cb Y1 Cr (2 pixels share Cb/Cr)
+	// Output format: Y0 Cb Y1 Cr (2 pixels share Cb/Cr); a last unpaired pixel of a
+	// row is stored as Y Cb Cr
 	data := make([]byte, len(p.data))
+	columns := int(p.Columns)
+	rows := len(p.data) / max(columns*3, 1) // Every row of every frame
 
-	for y := 0; y < int(p.Rows); y++ {
-		for x := 0; x < int(p.Columns); x += 2 {
-			idx := (y*int(p.Columns) + x) * 3
-
-			// First pixel
-			r1 := float64(p.data[idx])
-			g1 := float64(p.data[idx+1])
-			b1 := float64(p.data[idx+2])
+	rgb := func(idx int) [3]float64 {
+		return [3]float64{float64(p.data[idx]), float64(p.data[idx+1]), float64(p.data[idx+2])}
+	}
 
-			y1 := 0.299*r1 + 0.587*g1 + 0.114*b1
+	for y := 0; y < rows; y++ {
+		for x := 0; x < columns; x += 2 {
+			idx := (y*columns + x) * 3
 
-			// Second pixel (if exists)
-			var y2, r2, g2, b2 float64
-			if x+1 < int(p.Columns) {
-				r2 = float64(p.data[idx+3])
-				g2 = float64(p.data[idx+4])
-				b2 = float64(p.data[idx+5])
-				y2 = 0.299*r2 + 0.587*g2 + 0.114*b2
-			} else {
-				r2, g2, b2, y2 = r1, g1, b1, y1
+			// First pixel, and the second if it exists
+			rgb1 := rgb(idx)
+			if x+1 >= columns {
+				ybr := t.apply(rgb1)
+				data[idx], data[idx+1], data[idx+2] = ybr[0], ybr[1], ybr[2]
+				continue
 			}
+			rgb2 := rgb(idx + 3)
 
 			// Average chroma from both pixels
-			rAvg := (r1 + r2) / 2
-			gAvg := (g1 + g2) / 2
-			bAvg := (b1 + b2) / 2
-
-			cb := -0.169*rAvg - 0.331*gAvg + 0.500*bAvg + 128
-			cr := 0.500*rAvg - 0.419*gAvg - 0.081*bAvg + 128
+			var avg [3]float64
+			for c := range avg {
+				avg[c] = (rgb1[c] + rgb2[c]) / 2
+			}
+			chroma := t.apply(avg)
 
 			// Write Y0 Cb Y1 Cr
-			data[idx] = clampUint8(int32(y1))
-			data[idx+1] = clampUint8(int32(cb))
-			if x+1 < int(p.Columns) {
-				data[idx+3] = clampUint8(int32(y2))
-				data[idx+4] = clampUint8(int32(cr))
-			}
+			data[idx] = t.apply(rgb1)[0]
+			data[idx+1] = chroma[1]
+			data[idx+3] = t.apply(rgb2)[0]
+			data[idx+4] = chroma[2]
 		}
 	}
 
@@ -185,7 +140,7 @@ func convertRGBToYBR422(p *PixelData) (*PixelData, error) {
 		HighBit:                   p.HighBit,
 		PixelRepresentation:       p.PixelRepresentation,
 		SamplesPerPixel:           p.SamplesPerPixel,
-		PhotometricInterpretation: "YBR_FULL_422",
+		PhotometricInterpretation: targetPI,
 		PlanarConfiguration:       0, // 422 is always interleaved
 		NumberOfFrames:            p.NumberOfFrames,
 		data:                      data,
@@ -195,123 +150,48 @@ func convertRGBToYBR422(p *PixelData) (*PixelData, error) {
 	return result, nil
 }
 
-// convertYBRToRGB converts YBR_FULL to RGB color space.
-//
-// Inverse transformation of YBR → RGB using ITU-R BT.601:
-//
-//	R = Y                   + 1.402 * (Cr - 128)
-//	G = Y - 0.344 * (Cb - 128) - 0.714 * (Cr - 128)
-//	B = Y + 1.772 * (Cb - 128)
-func convertYBRToRGB(p *PixelData) (*PixelData, error) {
-	if p.SamplesPerPixel != 3 {
-		return nil, fmt.Errorf("YBR conversion requires SamplesPerPixel=3, got %d", p.SamplesPerPixel)
-	}
-
-	if p.BitsAllocated != 8 {
-		return nil, fmt.Errorf("YBR → RGB conversion currently only supports 8-bit data")
-	}
-
-	data := make([]byte, len(p.data))
-
-	if p.PlanarConfiguration == 0 {
-		// Interleaved: YCbCr YCbCr YCbCr...
-		for i := 0; i < len(p.data); i += 3 {
-			y := float64(p.data[i])
-			cb := float64(p.data[i+1]) - 128
-			cr := float64(p.data[i+2]) - 128
-
-			r := y + 1.402*cr
-			g := y - 0.344*cb - 0.714*cr
-			b := y + 1.772*cb
-
-			data[i] = clampUint8(int32(r))
-			data[i+1] = clampUint8(int32(g))
-			data[i+2] = clampUint8(int32(b))
-		}
-	} else {
-		// Planar: YYY...CbCbCb...CrCrCr...
-		planeSize := len(p.data) / 3
-		for i := 0; i < planeSize; i++ {
-			y := float64(p.data[i])
-			cb := float64(p.data[planeSize+i]) - 128
-			cr := float64(p.data[2*planeSize+i]) - 128
-
-			r := y + 1.402*cr
-			g := y - 0.344*cb - 0.714*cr
-			b := y + 1.772*cb
-
-			data[i] = clampUint8(int32(r))
-			data[planeSize+i] = clampUint8(int32(g))
-			data[2*planeSize+i] = clampUint8(int32(b))
-		}
-	}
-
-	result := &PixelData{
-		Rows:                      p.Rows,
-		Columns:                   p.Columns,
-		BitsAllocated:             p.BitsAllocated,
-		BitsStored:                p.BitsStored,
-		HighBit:                   p.HighBit,
-		PixelRepresentation:       p.PixelRepresentation,
-		SamplesPerPixel:           p.SamplesPerPixel,
-		PhotometricInterpretation: "RGB",
-		PlanarConfiguration:       p.PlanarConfiguration,
-		NumberOfFrames:            p.NumberOfFrames,
-		data:                      data,
-		TransferSyntaxUID:         p.TransferSyntaxUID,
-	}
-
-	return result, nil
-}
-
-// convertYBR422ToRGB converts YBR_FULL_422 to RGB color space.
+// convertYBR422ToRGB converts YBR_FULL_422 or YBR_PARTIAL_422, laid out as by
+// convertRGBToYBR422, to RGB with the transform t of the source colour space.
 //
 // Upsamples 4:2:2 chroma back to 4:4:4 RGB.
-func convertYBR422ToRGB(p *PixelData) (*PixelData, error) {
+func convertYBR422ToRGB(p *PixelData, t colorTransform) (*PixelData, error) {
+	sourcePI := p.PhotometricInterpretation
 	if p.SamplesPerPixel != 3 {
-		return nil, fmt.Errorf("YBR_FULL_422 conversion requires SamplesPerPixel=3, got %d", p.SamplesPerPixel)
+		return nil, fmt.Errorf("%s conversion requires SamplesPerPixel=3, got %d", sourcePI, p.SamplesPerPixel)
 	}
 
 	if p.BitsAllocated != 8 {
-		return nil, fmt.Errorf("YBR_FULL_422 → RGB conversion currently only supports 8-bit data")
+		return nil, fmt.Errorf("%s → RGB conversion currently only supports 8-bit data", sourcePI)
 	}
 
 	data := make([]byte, len(p.data))
+	columns := int(p.Columns)
+	rows := len(p.data) / max(columns*3, 1) // Every row of every frame
 
-	for y := 0; y < int(p.Rows); y++ {
-		for x := 0; x < int(p.Columns); x += 2 {
-			idx := (y*int(p.Columns) + x) * 3
+	for y := 0; y < rows; y++ {
+		for x := 0; x < columns; x += 2 {
+			idx := (y*columns + x) * 3
 
 			// Read Y0 Cb Y1 Cr (both pixels share Cb/Cr)
 			y1 := float64(p.data[idx])
-			cb := float64(p.data[idx+1]) - 128
+			cb := float64(p.data[idx+1])
 
 			var y2, cr float64
-			if x+1 < int(p.Columns) {
+			if x+1 < columns {
 				y2 = float64(p.data[idx+3])
-				cr = float64(p.data[idx+4]) - 128
+				cr = float64(p.data[idx+4])
 			} else {
 				y2 = y1
-				cr = float64(p.data[idx+2]) - 128
+				cr = float64(p.data[idx+2])
 			}
 
 			// Convert both pixels using shared chroma
-			r1 := y1 + 1.402*cr
-			g1 := y1 - 0.344*cb - 0.714*cr
-			b1 := y1 + 1.772*cb
-
-			r2 := y2 + 1.402*cr
-			g2 := y2 - 0.344*cb - 0.714*cr
-			b2 := y2 + 1.772*cb
-
-			data[idx] = clampUint8(int32(r1))
-			data[idx+1] = clampUint8(int32(g1))
-			data[idx+2] = clampUint8(int32(b1))
-
-			if x+1 < int(p.Columns) {
-				data[idx+3] = clampUint8(int32(r2))
-				data[idx+4] = clampUint8(int32(g2))
-				data[idx+5] = clampUint8(int32(b2))
+			rgb1 := t.apply([3]float64{y1, cb, cr})
+			data[idx], data[idx+1], data[idx+2] = rgb1[0], rgb1[1], rgb1[2]
+
+			if x+1 < columns {
+				rgb2 := t.apply([3]float64{y2, cb, cr})
+				data[idx+3], data[idx+4], data[idx+5] = rgb2[0], rgb2[1], rgb2[2]
 			}
 		}
 	}
@@ -340,28 +220,16 @@ func convertYBR422ToRGB(p *PixelData) (*PixelData, error) {
 //   - MONOCHROME1: Higher values = darker (0=white, max=black)
 //   - MONOCHROME2: Higher values = brighter (0=black, max=white)
 //
-// Formula: inverted_value = max_value - original_value
+// Formula: inverted_value = max_value - original_value, where max_value is
+// (1 << BitsStored) - 1 (see invertSamples).
 func invertMonochrome(p *PixelData) (*PixelData, error) {
 	if p.SamplesPerPixel != 1 {
 		return nil, fmt.Errorf("monochrome inversion requires SamplesPerPixel=1, got %d", p.SamplesPerPixel)
 	}
 
-	data := make([]byte, len(p.data))
-
-	if p.BitsAllocated <= 8 {
-		// 8-bit inversion
-		for i := 0; i < len(p.data); i++ {
-			data[i] = 255 - p.data[i]
-		}
-	} else {
-		// 16-bit inversion
-		maxVal := uint16((1 << p.BitsStored) - 1)
-		for i := 0; i < len(p.data)/2; i++ {
-			val := uint16(p.data[i*2]) | uint16(p.data[i*2+1])<<8
-			inverted := maxVal - val
-			data[i*2] = byte(inverted)
-			data[i*2+1] = byte(inverted >> 8)
-		}
+	data, err := invertSamples(p)
+	if err != nil {
+		return nil, err
 	}
 
 	// Determine target PI
@@ -388,6 +256,108 @@ func invertMonochrome(p *PixelData) (*PixelData, error) {
 	return result, nil
 }
 
+// invertSamples returns the pixel data of p with every sample inverted within the
+// range of BitsStored rather than BitsAllocated, so that 12-bit data in 16-bit words
+// maps 0 ↔ 4095 and not 0 ↔ 65535. Bits above BitsStored are ignored and signed
+// samples are sign-extended to BitsAllocated.
+func invertSamples(p *PixelData) ([]byte, error) {
+	bitsStored := p.BitsStored
+	if bitsStored == 0 || bitsStored > p.BitsAllocated {
+		bitsStored = p.BitsAllocated
+	}
+	mask := uint16(1<<bitsStored - 1)
+
+	data := make([]byte, len(p.data))
+	switch p.BitsAllocated {
+	case 1:
+		// Packed single-bit samples
+		for i, b := range p.data {
+			data[i] = ^b
+		}
+	case 8:
+		for i, b := range p.data {
+			data[i] = byte(invertedSample(uint16(b)&mask, bitsStored, p.PixelRepresentation))
+		}
+	case 16:
+		for i := 0; i+1 < len(p.data); i += 2 {
+			val := uint16(p.data[i]) | uint16(p.data[i+1])<<8
+			inverted := uint16(invertedSample(val&mask, bitsStored, p.PixelRepresentation))
+			data[i] = byte(inverted)
+			data[i+1] = byte(inverted >> 8)
+		}
+	default:
+		return nil, fmt.Errorf("monochrome inversion supports BitsAllocated 1, 8 or 16, got %d", p.BitsAllocated)
+	}
+	return data, nil
+}
+
+// invertedSample inverts a stored value of bitsStored bits: max - v for unsigned
+// samples and min + max - v (that is, -1 - v) for signed samples.
+func invertedSample(stored uint16, bitsStored, pixelRepresentation uint16) int64 {
+	v := int64(stored)
+	if pixelRepresentation == 1 {
+		if stored&(1<<(bitsStored-1)) != 0 {
+			v -= 1 << bitsStored
+		}
+		return -1 - v
+	}
+	return int64(1)<<bitsStored - 1 - v
+}
+
+// InvertPixelPadding rewrites Pixel Padding Value (0028,0120) and Pixel Padding Range
+// Limit (0028,0121) of ds to match pixel data inverted by ConvertPhotometricInterpretation
+// (MONOCHROME1 ↔ MONOCHROME2), so that padding still identifies the same pixels.
+// p describes the pixel data; its BitsStored and PixelRepresentation define the
+// inversion. The values are written as US or SS according to PixelRepresentation.
+// Attributes absent from ds are left absent.
+//
+// Example:
+//
+//	mono2, err := pixel.ConvertPhotometricInterpretation(pd, "MONOCHROME2")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if err := pixel.InvertPixelPadding(ds, mono2); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.5.1.1.2
+func InvertPixelPadding(ds *dicom.DataSet, p *PixelData) error {
+	if ds == nil {
+		return fmt.Errorf("dataset is nil")
+	}
+	bitsStored := p.BitsStored
+	if bitsStored == 0 || bitsStored > 16 {
+		return fmt.Errorf("pixel padding inversion requires BitsStored 1 to 16, got %d", bitsStored)
+	}
+	mask := uint16(1<<bitsStored - 1)
+	pixelVR := dicom.PixelValueVR(p.PixelRepresentation)
+
+	for _, t := range []tag.Tag{tag.PixelPaddingValue, tag.PixelPaddingRangeLimit} {
+		if !ds.Contains(t) {
+			continue
+		}
+		v, err := ds.GetPixelValueStat(t)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", t, err)
+		}
+		inverted := invertedSample(uint16(v)&mask, bitsStored, p.PixelRepresentation)
+		val, err := value.NewIntValue(pixelVR, []int64{inverted})
+		if err != nil {
+			return fmt.Errorf("failed to create value for %s: %w", t, err)
+		}
+		elem, err := element.NewElement(t, pixelVR, val)
+		if err != nil {
+			return fmt.Errorf("failed to create element %s: %w", t, err)
+		}
+		if err := ds.Set(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ConvertPlanarConfiguration converts between interleaved and planar pixel data organization.
 //
 // Converts:
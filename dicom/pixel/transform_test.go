@@ -3,6 +3,8 @@ package pixel
 import (
 	"testing"
 
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -199,6 +201,81 @@ func TestConvertPhotometricInterpretation_Monochrome16bit(t *testing.T) {
 	}
 }
 
+func TestConvertPhotometricInterpretation_Monochrome12bitIn16(t *testing.T) {
+	// 12-bit MONOCHROME1 data in 16-bit words; the last sample carries stray bits
+	// above BitsStored, which must not affect the result.
+	data := []uint16{0, 1, 1000, 2048, 4094, 4095, 0xF000 | 100}
+	pixelData, err := NewPixelDataFromUint16(data, len(data), 1)
+	require.NoError(t, err)
+	pixelData.BitsStored = 12
+	pixelData.HighBit = 11
+	pixelData.PhotometricInterpretation = "MONOCHROME1"
+
+	mono2, err := ConvertPhotometricInterpretation(pixelData, "MONOCHROME2")
+	require.NoError(t, err)
+	assert.Equal(t, "MONOCHROME2", mono2.PhotometricInterpretation)
+	assert.Equal(t, uint16(12), mono2.BitsStored)
+
+	const maxVal = 1<<12 - 1
+	array := mono2.Array().([]uint16)
+	for i, v := range data {
+		assert.Equal(t, uint16(maxVal-int(v&0x0FFF)), array[i], "pixel %d", i)
+	}
+
+	// Round trip restores the stored values
+	mono1, err := ConvertPhotometricInterpretation(mono2, "MONOCHROME1")
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{0, 1, 1000, 2048, 4094, 4095, 100}, mono1.Array().([]uint16))
+}
+
+func TestConvertPhotometricInterpretation_MonochromeSigned(t *testing.T) {
+	// Signed 12-bit samples: -2048..2047 maps onto 2047..-2048
+	data := []int16{-2048, -1, 0, 1000, 2047}
+	raw := make([]uint16, len(data))
+	for i, v := range data {
+		raw[i] = uint16(v)
+	}
+	pixelData, err := NewPixelDataFromUint16(raw, len(raw), 1)
+	require.NoError(t, err)
+	pixelData.BitsStored = 12
+	pixelData.HighBit = 11
+	pixelData.PixelRepresentation = 1
+	pixelData.PhotometricInterpretation = "MONOCHROME1"
+
+	mono2, err := ConvertPhotometricInterpretation(pixelData, "MONOCHROME2")
+	require.NoError(t, err)
+	assert.Equal(t, []int16{2047, 0, -1, -1001, -2048}, mono2.Array().([]int16))
+}
+
+func TestInvertPixelPadding(t *testing.T) {
+	pixelData, err := NewPixelDataFromUint16(make([]uint16, 4), 2, 2)
+	require.NoError(t, err)
+	pixelData.BitsStored = 12
+	pixelData.PhotometricInterpretation = "MONOCHROME1"
+
+	ds := dicom.NewDataSet()
+	addSegUint16(t, ds, tag.PixelPaddingValue, 4095)
+	addSegUint16(t, ds, tag.PixelPaddingRangeLimit, 4000)
+
+	mono2, err := ConvertPhotometricInterpretation(pixelData, "MONOCHROME2")
+	require.NoError(t, err)
+	require.NoError(t, InvertPixelPadding(ds, mono2))
+
+	padding, err := ds.GetPixelValueStat(tag.PixelPaddingValue)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), padding)
+	limit, err := ds.GetPixelValueStat(tag.PixelPaddingRangeLimit)
+	require.NoError(t, err)
+	assert.Equal(t, int64(95), limit)
+
+	// Absent attributes stay absent
+	empty := dicom.NewDataSet()
+	require.NoError(t, InvertPixelPadding(empty, mono2))
+	assert.False(t, empty.Contains(tag.PixelPaddingValue))
+
+	assert.Error(t, InvertPixelPadding(nil, mono2))
+}
+
 func TestConvertPhotometricInterpretation_RoundTrip(t *testing.T) {
 	// Test RGB → YBR_FULL → RGB round trip
 	original := make([]byte, 100*100*3)
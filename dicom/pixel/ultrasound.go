@@ -0,0 +1,224 @@
+package pixel
+
+import (
+	"fmt"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// USPhysicalUnits is the unit code of Physical Units X/Y Direction (0018,6024 and
+// 0018,6026) in an ultrasound region.
+type USPhysicalUnits int
+
+// Physical unit codes of ultrasound regions.
+const (
+	USUnitsNone                      USPhysicalUnits = 0x0
+	USUnitsPercent                   USPhysicalUnits = 0x1
+	USUnitsDecibel                   USPhysicalUnits = 0x2
+	USUnitsCentimeter                USPhysicalUnits = 0x3
+	USUnitsSeconds                   USPhysicalUnits = 0x4
+	USUnitsHertz                     USPhysicalUnits = 0x5
+	USUnitsDecibelPerSecond          USPhysicalUnits = 0x6
+	USUnitsCentimeterPerSecond       USPhysicalUnits = 0x7
+	USUnitsSquareCentimeter          USPhysicalUnits = 0x8
+	USUnitsSquareCentimeterPerSecond USPhysicalUnits = 0x9
+	USUnitsCubicCentimeter           USPhysicalUnits = 0xA
+	USUnitsCubicCentimeterPerSecond  USPhysicalUnits = 0xB
+	USUnitsDegrees                   USPhysicalUnits = 0xC
+)
+
+// String returns the unit symbol, e.g. "cm" or "cm/s".
+func (u USPhysicalUnits) String() string {
+	switch u {
+	case USUnitsNone:
+		return "none"
+	case USUnitsPercent:
+		return "%"
+	case USUnitsDecibel:
+		return "dB"
+	case USUnitsCentimeter:
+		return "cm"
+	case USUnitsSeconds:
+		return "s"
+	case USUnitsHertz:
+		return "Hz"
+	case USUnitsDecibelPerSecond:
+		return "dB/s"
+	case USUnitsCentimeterPerSecond:
+		return "cm/s"
+	case USUnitsSquareCentimeter:
+		return "cm2"
+	case USUnitsSquareCentimeterPerSecond:
+		return "cm2/s"
+	case USUnitsCubicCentimeter:
+		return "cm3"
+	case USUnitsCubicCentimeterPerSecond:
+		return "cm3/s"
+	case USUnitsDegrees:
+		return "deg"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(u))
+	}
+}
+
+// USRegion is one item of the Sequence of Ultrasound Regions (0018,6011): a
+// rectangle of the image with its own physical calibration, such as a 2D tissue
+// image, a spectral Doppler strip or an M-mode trace.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.8.5.5
+type USRegion struct {
+	SpatialFormat int    // Region Spatial Format (0018,6012): 1 = 2D, 2 = M-mode, 3 = spectral, ...
+	DataType      int    // Region Data Type (0018,6014): 1 = tissue, 3 = PW spectral Doppler, ...
+	Flags         uint32 // Region Flags (0018,6016)
+
+	// MinX0, MinY0, MaxX1 and MaxY1 are the pixel bounds of the region, inclusive
+	// (Region Location Min X0 to Max Y1, 0018,6018 to 0018,601E).
+	MinX0, MinY0, MaxX1, MaxY1 int
+
+	// ReferencePixelX0 and ReferencePixelY0 locate the reference pixel relative to
+	// the region's top-left corner (0018,6020 and 0018,6022). Zero when absent.
+	ReferencePixelX0, ReferencePixelY0 int
+
+	UnitsX USPhysicalUnits // Physical Units X Direction (0018,6024)
+	UnitsY USPhysicalUnits // Physical Units Y Direction (0018,6026)
+
+	// ReferencePhysicalX and ReferencePhysicalY are the physical values at the
+	// reference pixel (0018,6028 and 0018,602A). Zero when absent.
+	ReferencePhysicalX, ReferencePhysicalY float64
+
+	DeltaX float64 // Physical Delta X (0018,602C), physical units per pixel column
+	DeltaY float64 // Physical Delta Y (0018,602E), physical units per pixel row
+}
+
+// Contains reports whether pixel (x, y) lies inside the region.
+func (r *USRegion) Contains(x, y int) bool {
+	return x >= r.MinX0 && x <= r.MaxX1 && y >= r.MinY0 && y <= r.MaxY1
+}
+
+// PixelToPhysical converts image pixel (x, y) (column, row) to physical values in the
+// region's UnitsX and UnitsY, measured from the reference pixel's physical values.
+//
+// The result is only meaningful for pixels the region Contains.
+//
+// Example:
+//
+//	// Distance between two points of a 2D tissue region, in cm
+//	x1, y1 := region.PixelToPhysical(120, 80)
+//	x2, y2 := region.PixelToPhysical(180, 160)
+//	dist := math.Hypot(x2-x1, y2-y1)
+func (r *USRegion) PixelToPhysical(x, y int) (float64, float64) {
+	refX := r.MinX0 + r.ReferencePixelX0
+	refY := r.MinY0 + r.ReferencePixelY0
+	return r.ReferencePhysicalX + float64(x-refX)*r.DeltaX,
+		r.ReferencePhysicalY + float64(y-refY)*r.DeltaY
+}
+
+// UltrasoundRegions returns the calibrated regions of an ultrasound image from the
+// Sequence of Ultrasound Regions (0018,6011), in sequence order.
+//
+// Region location, physical units and physical deltas are required for each region;
+// the reference pixel and its physical values default to zero.
+//
+// Example:
+//
+//	regions, err := pixel.UltrasoundRegions(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, r := range regions {
+//	    if r.Contains(x, y) && r.UnitsX == pixel.USUnitsCentimeter {
+//	        px, py := r.PixelToPhysical(x, y)
+//	        fmt.Printf("%.2f cm, %.2f cm\n", px, py)
+//	    }
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.8.5.5
+func UltrasoundRegions(ds *dicom.DataSet) ([]USRegion, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+	items, err := ds.GetSequenceItems(tag.SequenceOfUltrasoundRegions)
+	if err != nil {
+		return nil, &MissingAttributeError{
+			AttributeName: "SequenceOfUltrasoundRegions",
+			Tag:           tag.SequenceOfUltrasoundRegions.String(),
+		}
+	}
+
+	regions := make([]USRegion, 0, len(items))
+	for i, item := range items {
+		region, err := ultrasoundRegion(item)
+		if err != nil {
+			return nil, fmt.Errorf("ultrasound region %d: %w", i, err)
+		}
+		regions = append(regions, region)
+	}
+	return regions, nil
+}
+
+// ultrasoundRegion reads one Sequence of Ultrasound Regions item.
+func ultrasoundRegion(item *dicom.DataSet) (USRegion, error) {
+	var r USRegion
+	var unitsX, unitsY int
+
+	for _, attr := range []struct {
+		tag      tag.Tag
+		name     string
+		dst      *int
+		required bool
+	}{
+		{tag.RegionLocationMinX0, "RegionLocationMinX0", &r.MinX0, true},
+		{tag.RegionLocationMinY0, "RegionLocationMinY0", &r.MinY0, true},
+		{tag.RegionLocationMaxX1, "RegionLocationMaxX1", &r.MaxX1, true},
+		{tag.RegionLocationMaxY1, "RegionLocationMaxY1", &r.MaxY1, true},
+		{tag.PhysicalUnitsXDirection, "PhysicalUnitsXDirection", &unitsX, true},
+		{tag.PhysicalUnitsYDirection, "PhysicalUnitsYDirection", &unitsY, true},
+		{tag.RegionSpatialFormat, "RegionSpatialFormat", &r.SpatialFormat, false},
+		{tag.RegionDataType, "RegionDataType", &r.DataType, false},
+		{tag.ReferencePixelX0, "ReferencePixelX0", &r.ReferencePixelX0, false},
+		{tag.ReferencePixelY0, "ReferencePixelY0", &r.ReferencePixelY0, false},
+	} {
+		if attr.required && !item.Contains(attr.tag) {
+			return r, &MissingAttributeError{AttributeName: attr.name, Tag: attr.tag.String()}
+		}
+		*attr.dst = getIntWithDefault(item, attr.tag, 0)
+	}
+	r.UnitsX = USPhysicalUnits(unitsX)
+	r.UnitsY = USPhysicalUnits(unitsY)
+	r.Flags = uint32(getIntWithDefault(item, tag.RegionFlags, 0))
+
+	for _, attr := range []struct {
+		tag      tag.Tag
+		dst      *float64
+		required bool
+	}{
+		{tag.PhysicalDeltaX, &r.DeltaX, true},
+		{tag.PhysicalDeltaY, &r.DeltaY, true},
+		{tag.ReferencePixelPhysicalValueX, &r.ReferencePhysicalX, false},
+		{tag.ReferencePixelPhysicalValueY, &r.ReferencePhysicalY, false},
+	} {
+		if !attr.required && !item.Contains(attr.tag) {
+			continue
+		}
+		values, err := datasetFloats(item, attr.tag)
+		if err != nil {
+			return r, fmt.Errorf("%w: %v", ErrMissingRequiredAttribute, err)
+		}
+		if len(values) == 0 {
+			return r, fmt.Errorf("%w: %s is empty", ErrMissingRequiredAttribute, attributeKeyword(attr.tag))
+		}
+		*attr.dst = values[0]
+	}
+
+	if r.MaxX1 < r.MinX0 || r.MaxY1 < r.MinY0 {
+		return r, &PixelDataError{
+			Field:    "region location",
+			Expected: "MaxX1 >= MinX0 and MaxY1 >= MinY0",
+			Actual:   fmt.Sprintf("(%d,%d)-(%d,%d)", r.MinX0, r.MinY0, r.MaxX1, r.MaxY1),
+		}
+	}
+	return r, nil
+}
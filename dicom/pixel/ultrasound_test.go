@@ -0,0 +1,138 @@
+package pixel
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addUSRegionValue sets an integer or FD attribute of an ultrasound region item.
+func addUSRegionValue(t *testing.T, item *dicom.DataSet, tg tag.Tag, v vr.VR, n float64) {
+	t.Helper()
+	var val value.Value
+	var err error
+	if v == vr.FloatingPointDouble {
+		val, err = value.NewFloatValue(v, []float64{n})
+	} else {
+		val, err = value.NewIntValue(v, []int64{int64(n)})
+	}
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, v, val)
+	require.NoError(t, err)
+	require.NoError(t, item.Set(elem))
+}
+
+// newUSRegionItem returns a 2D tissue region at (10,20)-(209,319) calibrated at
+// 0.05 cm per pixel, with its reference pixel at the region centre top.
+func newUSRegionItem(t *testing.T) *dicom.DataSet {
+	item := dicom.NewDataSet()
+	for _, a := range []struct {
+		tag tag.Tag
+		vr  vr.VR
+		n   float64
+	}{
+		{tag.RegionSpatialFormat, vr.UnsignedShort, 1},
+		{tag.RegionDataType, vr.UnsignedShort, 1},
+		{tag.RegionFlags, vr.UnsignedLong, 2},
+		{tag.RegionLocationMinX0, vr.UnsignedLong, 10},
+		{tag.RegionLocationMinY0, vr.UnsignedLong, 20},
+		{tag.RegionLocationMaxX1, vr.UnsignedLong, 209},
+		{tag.RegionLocationMaxY1, vr.UnsignedLong, 319},
+		{tag.ReferencePixelX0, vr.SignedLong, 100},
+		{tag.ReferencePixelY0, vr.SignedLong, 0},
+		{tag.PhysicalUnitsXDirection, vr.UnsignedShort, 3},
+		{tag.PhysicalUnitsYDirection, vr.UnsignedShort, 3},
+		{tag.ReferencePixelPhysicalValueX, vr.FloatingPointDouble, 0},
+		{tag.ReferencePixelPhysicalValueY, vr.FloatingPointDouble, 0},
+		{tag.PhysicalDeltaX, vr.FloatingPointDouble, 0.05},
+		{tag.PhysicalDeltaY, vr.FloatingPointDouble, 0.05},
+	} {
+		addUSRegionValue(t, item, a.tag, a.vr, a.n)
+	}
+	return item
+}
+
+func TestUltrasoundRegions(t *testing.T) {
+	// A spectral Doppler strip below the tissue image: time in s across, velocity in
+	// cm/s down, with the baseline at row 60 of the region
+	doppler := dicom.NewDataSet()
+	for _, a := range []struct {
+		tag tag.Tag
+		vr  vr.VR
+		n   float64
+	}{
+		{tag.RegionSpatialFormat, vr.UnsignedShort, 3},
+		{tag.RegionLocationMinX0, vr.UnsignedLong, 10},
+		{tag.RegionLocationMinY0, vr.UnsignedLong, 340},
+		{tag.RegionLocationMaxX1, vr.UnsignedLong, 409},
+		{tag.RegionLocationMaxY1, vr.UnsignedLong, 459},
+		{tag.ReferencePixelY0, vr.SignedLong, 60},
+		{tag.PhysicalUnitsXDirection, vr.UnsignedShort, 4},
+		{tag.PhysicalUnitsYDirection, vr.UnsignedShort, 7},
+		{tag.PhysicalDeltaX, vr.FloatingPointDouble, 0.01},
+		{tag.PhysicalDeltaY, vr.FloatingPointDouble, -2},
+	} {
+		addUSRegionValue(t, doppler, a.tag, a.vr, a.n)
+	}
+
+	ds := dicom.NewDataSet()
+	addGSPSSequence(t, ds, tag.SequenceOfUltrasoundRegions, newUSRegionItem(t), doppler)
+
+	regions, err := UltrasoundRegions(ds)
+	require.NoError(t, err)
+	require.Len(t, regions, 2)
+
+	tissue := regions[0]
+	assert.Equal(t, 1, tissue.SpatialFormat)
+	assert.Equal(t, uint32(2), tissue.Flags)
+	assert.Equal(t, [4]int{10, 20, 209, 319}, [4]int{tissue.MinX0, tissue.MinY0, tissue.MaxX1, tissue.MaxY1})
+	assert.Equal(t, USUnitsCentimeter, tissue.UnitsX)
+	assert.Equal(t, "cm", tissue.UnitsY.String())
+	assert.True(t, tissue.Contains(10, 319))
+	assert.False(t, tissue.Contains(210, 100))
+
+	// The reference pixel is relative to the region's top-left corner
+	x, y := tissue.PixelToPhysical(110, 20)
+	assert.InDelta(t, 0, x, 1e-9)
+	assert.InDelta(t, 0, y, 1e-9)
+	x, y = tissue.PixelToPhysical(150, 120)
+	assert.InDelta(t, 2.0, x, 1e-9)
+	assert.InDelta(t, 5.0, y, 1e-9)
+
+	spectral := regions[1]
+	assert.Equal(t, "s", spectral.UnitsX.String())
+	assert.Equal(t, USUnitsCentimeterPerSecond, spectral.UnitsY)
+	x, y = spectral.PixelToPhysical(110, 380)
+	assert.InDelta(t, 1.0, x, 1e-9)
+	assert.InDelta(t, 40.0, y, 1e-9)
+}
+
+func TestUltrasoundRegions_Errors(t *testing.T) {
+	_, err := UltrasoundRegions(dicom.NewDataSet())
+	assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+
+	_, err = UltrasoundRegions(nil)
+	assert.Error(t, err)
+
+	for _, missing := range []tag.Tag{tag.RegionLocationMaxX1, tag.PhysicalUnitsYDirection, tag.PhysicalDeltaX} {
+		item := newUSRegionItem(t)
+		require.NoError(t, item.Remove(missing))
+		ds := dicom.NewDataSet()
+		addGSPSSequence(t, ds, tag.SequenceOfUltrasoundRegions, item)
+		_, err := UltrasoundRegions(ds)
+		assert.ErrorIs(t, err, ErrMissingRequiredAttribute, "missing %s", missing)
+	}
+
+	inverted := newUSRegionItem(t)
+	addUSRegionValue(t, inverted, tag.RegionLocationMaxX1, vr.UnsignedLong, 5)
+	ds := dicom.NewDataSet()
+	addGSPSSequence(t, ds, tag.SequenceOfUltrasoundRegions, inverted)
+	_, err = UltrasoundRegions(ds)
+	assert.ErrorIs(t, err, ErrInvalidPixelData)
+}
@@ -0,0 +1,157 @@
+package pixel
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// Validate checks that the Pixel Data (7FE0,0010) of ds is consistent with the
+// declared image dimensions, catching truncated or corrupt transfers before the data
+// is decoded.
+//
+// For native (uncompressed) data the byte length must equal
+// Rows × Columns × SamplesPerPixel × NumberOfFrames × BitsAllocated/8, allowing for
+// the single pad byte of odd-length values; 1-bit data is expected packed. For
+// encapsulated data the Basic Offset Table must hold one offset per frame or, when
+// it is empty, there must be at least one fragment per frame.
+//
+// Whether the data is encapsulated is decided by the Transfer Syntax UID (0002,0010),
+// or by the value starting with an Item tag when the dataset has no File Meta
+// Information.
+//
+// A size or count mismatch is returned as a *PixelDataError (matching
+// ErrInvalidPixelData) whose Expected and Actual fields hold the declared and found
+// byte lengths or frame counts as ints. Missing attributes are reported with
+// ErrMissingRequiredAttribute.
+//
+// Example:
+//
+//	if err := pixel.Validate(ds); err != nil {
+//	    var pe *pixel.PixelDataError
+//	    if errors.As(err, &pe) {
+//	        fmt.Printf("%s: want %v, got %v\n", pe.Field, pe.Expected, pe.Actual)
+//	    }
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_8.1.1
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_A.4
+func Validate(ds *dicom.DataSet) error {
+	if ds == nil {
+		return fmt.Errorf("dataset is nil")
+	}
+
+	pixelDataElem, err := ds.Get(tag.PixelData)
+	if err != nil {
+		return &MissingAttributeError{
+			AttributeName: "PixelData",
+			Tag:           tag.PixelData.String(),
+		}
+	}
+	bytesVal, ok := pixelDataElem.Value().(*value.BytesValue)
+	if !ok {
+		return &PixelDataError{
+			Field:    "PixelData value type",
+			Expected: "*value.BytesValue",
+			Actual:   fmt.Sprintf("%T", pixelDataElem.Value()),
+		}
+	}
+	data := bytesVal.Bytes()
+
+	numberOfFrames := getIntWithDefault(ds, tag.NumberOfFrames, 1)
+	if numberOfFrames < 1 {
+		return &PixelDataError{
+			Field:    "NumberOfFrames",
+			Expected: "at least 1",
+			Actual:   numberOfFrames,
+		}
+	}
+
+	if validateAsEncapsulated(ds, data) {
+		return validateEncapsulated(data, numberOfFrames)
+	}
+
+	info := &PixelInfo{NumberOfFrames: numberOfFrames}
+	for _, attr := range []struct {
+		tag  tag.Tag
+		name string
+		dst  *uint16
+	}{
+		{tag.Rows, "Rows", &info.Rows},
+		{tag.Columns, "Columns", &info.Columns},
+		{tag.SamplesPerPixel, "SamplesPerPixel", &info.SamplesPerPixel},
+		{tag.BitsAllocated, "BitsAllocated", &info.BitsAllocated},
+	} {
+		if *attr.dst, err = getUint16(ds, attr.tag, attr.name); err != nil {
+			return err
+		}
+	}
+
+	expected := CalculateExpectedSize(info)
+	if info.BitsAllocated == 1 {
+		// Single-bit pixels are packed eight to a byte
+		expected = (int(info.Rows)*int(info.Columns)*int(info.SamplesPerPixel)*numberOfFrames + 7) / 8
+	}
+	if len(data) != expected && !(expected%2 == 1 && len(data) == expected+1) {
+		return &PixelDataError{
+			Field:    "PixelData length (bytes)",
+			Expected: expected,
+			Actual:   len(data),
+		}
+	}
+	return nil
+}
+
+// validateAsEncapsulated reports whether Validate should treat data as encapsulated.
+func validateAsEncapsulated(ds *dicom.DataSet, data []byte) bool {
+	if elem, err := ds.Get(tag.TransferSyntaxUID); err == nil {
+		if tsUID := strings.TrimRight(elem.Value().String(), " \x00"); tsUID != "" {
+			return isEncapsulated(tsUID)
+		}
+	}
+	return startsWithItem(data)
+}
+
+// startsWithItem reports whether data begins with an Item tag (FFFE,E000), the first
+// tag of encapsulated pixel data.
+func startsWithItem(data []byte) bool {
+	return len(data) >= 4 &&
+		binary.LittleEndian.Uint16(data[0:2]) == ItemTagGroup &&
+		binary.LittleEndian.Uint16(data[2:4]) == ItemTag
+}
+
+// validateEncapsulated checks the fragment structure of encapsulated data against
+// numberOfFrames.
+func validateEncapsulated(data []byte, numberOfFrames int) error {
+	encapsulated, err := ParseEncapsulatedPixelData(data)
+	if err != nil {
+		return &PixelDataError{
+			Field:    "encapsulated pixel data parsing",
+			Expected: "valid encapsulated format",
+			Actual:   fmt.Sprintf("parse error: %v", err),
+		}
+	}
+
+	if offsets := len(encapsulated.BasicOffsetTable.Offsets); offsets > 0 && offsets != numberOfFrames {
+		return &PixelDataError{
+			Field:    "Basic Offset Table frame count",
+			Expected: numberOfFrames,
+			Actual:   offsets,
+		}
+	}
+
+	// A frame may span several fragments, but every frame needs at least one
+	if len(encapsulated.Fragments) < numberOfFrames {
+		return &PixelDataError{
+			Field:    "encapsulated fragment count",
+			Expected: numberOfFrames,
+			Actual:   len(encapsulated.Fragments),
+		}
+	}
+	return nil
+}
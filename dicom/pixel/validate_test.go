@@ -0,0 +1,125 @@
+package pixel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setValidatePixelData replaces the Pixel Data of ds with data.
+func setValidatePixelData(t *testing.T, ds *dicom.DataSet, data []byte) {
+	t.Helper()
+	val, err := value.NewBytesValue(vr.OtherByte, data)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tag.PixelData, vr.OtherByte, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+func TestValidate_Native(t *testing.T) {
+	pd, err := NewPixelDataFromUint16([]uint16{1, 2, 3, 4, 5, 6}, 3, 2)
+	require.NoError(t, err)
+	ds := newExtractDataSet(t, pd, uid.ExplicitVRLittleEndian.String())
+	require.NoError(t, Validate(ds))
+
+	t.Run("truncated", func(t *testing.T) {
+		setValidatePixelData(t, ds, make([]byte, 8))
+
+		err := Validate(ds)
+		require.ErrorIs(t, err, ErrInvalidPixelData)
+		var pe *PixelDataError
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, 12, pe.Expected)
+		assert.Equal(t, 8, pe.Actual)
+	})
+
+	t.Run("frames missing", func(t *testing.T) {
+		setValidatePixelData(t, ds, pd.RawBytes())
+		addGSPSString(t, ds, tag.NumberOfFrames, vr.IntegerString, "2")
+
+		var pe *PixelDataError
+		require.True(t, errors.As(Validate(ds), &pe))
+		assert.Equal(t, 24, pe.Expected)
+		assert.Equal(t, 12, pe.Actual)
+	})
+
+	t.Run("missing attribute", func(t *testing.T) {
+		require.NoError(t, ds.Remove(tag.Columns))
+		assert.ErrorIs(t, Validate(ds), ErrMissingRequiredAttribute)
+	})
+}
+
+func TestValidate_Padding(t *testing.T) {
+	// 3x1 8-bit pixels are stored as 4 bytes with a trailing pad byte
+	pd, err := NewPixelDataFromUint8([]uint8{1, 2, 3}, 3, 1)
+	require.NoError(t, err)
+	ds := newExtractDataSet(t, pd, uid.ExplicitVRLittleEndian.String())
+	setValidatePixelData(t, ds, []byte{1, 2, 3, 0})
+	assert.NoError(t, Validate(ds))
+}
+
+func TestValidate_SingleBit(t *testing.T) {
+	pd, err := NewPixelDataFromUint8(make([]uint8, 16), 4, 4)
+	require.NoError(t, err)
+	ds := newExtractDataSet(t, pd, uid.ExplicitVRLittleEndian.String())
+	for _, tg := range []tag.Tag{tag.BitsAllocated, tag.BitsStored} {
+		one, err := value.NewIntValue(vr.UnsignedShort, []int64{1})
+		require.NoError(t, err)
+		elem, err := element.NewElement(tg, vr.UnsignedShort, one)
+		require.NoError(t, err)
+		require.NoError(t, ds.Set(elem))
+	}
+
+	// 16 packed bits fit in 2 bytes
+	setValidatePixelData(t, ds, []byte{0xFF, 0x00})
+	assert.NoError(t, Validate(ds))
+
+	setValidatePixelData(t, ds, make([]byte, 16))
+	assert.ErrorIs(t, Validate(ds), ErrInvalidPixelData)
+}
+
+func TestValidate_Encapsulated(t *testing.T) {
+	frames := [][]byte{{0x01, 0x02}, {0x03, 0x04}}
+
+	t.Run("consistent", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addGSPSString(t, ds, tag.TransferSyntaxUID, vr.UniqueIdentifier, uid.RLELossless.String())
+		addGSPSString(t, ds, tag.NumberOfFrames, vr.IntegerString, "2")
+		setValidatePixelData(t, ds, EncapsulateFrames(frames))
+		assert.NoError(t, Validate(ds))
+	})
+
+	t.Run("offset table disagrees with NumberOfFrames", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addGSPSString(t, ds, tag.TransferSyntaxUID, vr.UniqueIdentifier, uid.RLELossless.String())
+		addGSPSString(t, ds, tag.NumberOfFrames, vr.IntegerString, "3")
+		setValidatePixelData(t, ds, EncapsulateFrames(frames))
+
+		var pe *PixelDataError
+		require.True(t, errors.As(Validate(ds), &pe))
+		assert.Equal(t, 3, pe.Expected)
+		assert.Equal(t, 2, pe.Actual)
+	})
+
+	t.Run("detected without transfer syntax", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		setValidatePixelData(t, ds, EncapsulateFrames(frames[:1]))
+		assert.NoError(t, Validate(ds))
+
+		addGSPSString(t, ds, tag.NumberOfFrames, vr.IntegerString, "2")
+		assert.ErrorIs(t, Validate(ds), ErrInvalidPixelData)
+	})
+}
+
+func TestValidate_MissingPixelData(t *testing.T) {
+	assert.ErrorIs(t, Validate(dicom.NewDataSet()), ErrMissingRequiredAttribute)
+	assert.Error(t, Validate(nil))
+}
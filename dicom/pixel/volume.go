@@ -0,0 +1,448 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+const (
+	// volumeOrientationTolerance is the largest difference allowed between the
+	// direction cosines of two slices of a volume.
+	volumeOrientationTolerance = 1e-4
+
+	// volumeSpacingTolerance is the largest difference, in mm, allowed between the
+	// pixel spacings of two slices of a volume.
+	volumeSpacingTolerance = 1e-3
+
+	// sliceGapTolerance is the largest deviation of a gap between adjacent slices
+	// from the median gap, as a fraction of the median, for the spacing to count as
+	// uniform.
+	sliceGapTolerance = 0.01
+
+	// tiltTolerance is the largest in-plane offset between slices, as a fraction of
+	// a pixel, for the slices to count as stacked along their normal.
+	tiltTolerance = 0.1
+)
+
+// Volume is a series of parallel slices stacked into a contiguous 3D array of voxels
+// in real-world units.
+//
+// Voxels are stored slice by slice, each slice row by row, so the voxel at column x,
+// row y of slice z is Voxels[(z*Rows+y)*Columns+x]. Slices are ordered along Normal,
+// which points from the first slice to the last. The patient coordinates (mm) of a
+// voxel's centre are
+//
+//	Origin + x*Spacing[0]*row direction + y*Spacing[1]*column direction + z*Spacing[2]*Normal
+//
+// where the row and column directions are the first and last three values of
+// Orientation.
+type Volume struct {
+	// Columns, Rows and Slices are the dimensions of the volume.
+	Columns int
+	Rows    int
+	Slices  int
+
+	// Spacing is the distance in mm between voxel centres along the rows, the
+	// columns and the slices. Note that the first two values are Pixel Spacing
+	// (0028,0030) reversed, since Pixel Spacing lists the row spacing first.
+	Spacing [3]float64
+
+	// Origin is Image Position (Patient) (0020,0032) of the first slice: the patient
+	// coordinates in mm of the centre of the first voxel.
+	Origin [3]float64
+
+	// Orientation is Image Orientation (Patient) (0020,0037) shared by the slices.
+	Orientation [6]float64
+
+	// Normal is the unit vector along which the slices are stacked, the cross
+	// product of the row and column directions.
+	Normal [3]float64
+
+	// Units are the units of the voxel values, as reported by ModalityUnits for the
+	// first slice, such as "HU" for CT.
+	Units string
+
+	// Voxels are the modality LUT outputs (Rescale Slope and Intercept applied) of
+	// every voxel.
+	Voxels []float32
+
+	// DataSets are the source slices sorted along Normal. Unless Resampled is set,
+	// DataSets[z] is slice z of the volume.
+	DataSets []*dicom.DataSet
+
+	// Resampled reports whether the slices were interpolated onto a regular grid
+	// (see WithResampling).
+	Resampled bool
+}
+
+// Index returns the position in Voxels of the voxel at column x, row y of slice z.
+func (v *Volume) Index(x, y, z int) int {
+	return (z*v.Rows+y)*v.Columns + x
+}
+
+// At returns the voxel at column x, row y of slice z.
+func (v *Volume) At(x, y, z int) float32 {
+	return v.Voxels[v.Index(x, y, z)]
+}
+
+// Int16 returns the voxels rounded to the nearest integer, the usual representation
+// of CT volumes in Hounsfield Units.
+//
+// Returns an error if a voxel is outside the int16 range.
+//
+// Example:
+//
+//	hu, err := vol.Int16()
+func (v *Volume) Int16() ([]int16, error) {
+	out := make([]int16, len(v.Voxels))
+	for i, voxel := range v.Voxels {
+		r := math.Round(float64(voxel))
+		if r < math.MinInt16 || r > math.MaxInt16 {
+			return nil, fmt.Errorf("voxel %d value %g is outside the int16 range", i, voxel)
+		}
+		out[i] = int16(r)
+	}
+	return out, nil
+}
+
+// VolumeOption configures BuildVolume.
+type VolumeOption func(*volumeOptions)
+
+// volumeOptions holds the settings applied by VolumeOption functions.
+type volumeOptions struct {
+	resample bool
+}
+
+// WithResampling makes BuildVolume accept gantry-tilted and irregularly spaced
+// series by interpolating them onto a regular grid aligned with the first slice:
+// slices perpendicular to Normal at the median gap between the source slices.
+// Voxels are interpolated linearly between the two nearest source slices and
+// bilinearly within them; voxels that fall outside one of the two slices take the
+// value of the other, and those outside both, as at the edges of a tilted series,
+// take the lowest value in the series.
+func WithResampling() VolumeOption {
+	return func(o *volumeOptions) {
+		o.resample = true
+	}
+}
+
+// volumeSlice is one source slice of a volume.
+type volumeSlice struct {
+	ds       *dicom.DataSet
+	position [3]float64
+	distance float64 // position along the slice normal
+	voxels   []float32
+}
+
+// BuildVolume stacks the single-frame grayscale images of a series into a Volume
+// for 3D processing.
+//
+// The slices are sorted by their position along the slice normal, whatever their
+// order in series, and each slice's stored values are passed through its own Rescale
+// Slope (0028,1053) and Rescale Intercept (0028,1052), so that voxels are in
+// real-world units such as Hounsfield Units.
+//
+// Returns an error if:
+//   - The series is empty or a slice is nil
+//   - A slice is multi-frame or colour, or its pixel data cannot be extracted
+//   - A slice lacks Image Position (Patient), Image Orientation (Patient) or Pixel
+//     Spacing
+//   - The slices differ in Rows, Columns, orientation, pixel spacing or Frame of
+//     Reference UID, or two slices share a position
+//   - The slices are offset in plane (ErrGantryTilt) or unevenly spaced
+//     (ErrIrregularSliceSpacing), unless WithResampling is given
+//
+// Example:
+//
+//	vol, err := pixel.BuildVolume(coll.GetBySeriesInstanceUID(seriesUID))
+//	if errors.Is(err, pixel.ErrGantryTilt) {
+//	    vol, err = pixel.BuildVolume(series, pixel.WithResampling())
+//	}
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%dx%dx%d voxels of %v mm in %s\n",
+//	    vol.Columns, vol.Rows, vol.Slices, vol.Spacing, vol.Units)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.2.1.1
+func BuildVolume(series []*dicom.DataSet, opts ...VolumeOption) (*Volume, error) {
+	var options volumeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if len(series) == 0 {
+		return nil, fmt.Errorf("series is empty")
+	}
+	for i, ds := range series {
+		if ds == nil {
+			return nil, fmt.Errorf("slice %d is nil", i)
+		}
+	}
+
+	ref := series[0]
+	orientation, err := ref.GetFloats(tag.ImageOrientationPatient)
+	if err != nil || len(orientation) != 6 {
+		return nil, fmt.Errorf("%w: slice 0 has no valid ImageOrientationPatient", ErrMissingRequiredAttribute)
+	}
+	pixelSpacing, err := ref.GetFloats(tag.PixelSpacing)
+	if err != nil || len(pixelSpacing) != 2 {
+		return nil, fmt.Errorf("%w: slice 0 has no valid PixelSpacing", ErrMissingRequiredAttribute)
+	}
+
+	vol := &Volume{
+		Spacing: [3]float64{pixelSpacing[1], pixelSpacing[0], 0},
+		Units:   ModalityUnits(ref),
+	}
+	copy(vol.Orientation[:], orientation)
+	vol.Normal = [3]float64{
+		orientation[1]*orientation[5] - orientation[2]*orientation[4],
+		orientation[2]*orientation[3] - orientation[0]*orientation[5],
+		orientation[0]*orientation[4] - orientation[1]*orientation[3],
+	}
+
+	sorted := make([]volumeSlice, len(series))
+	for i, ds := range series {
+		s, err := readVolumeSlice(ds, ref, orientation, pixelSpacing, vol)
+		if err != nil {
+			return nil, fmt.Errorf("slice %d: %w", i, err)
+		}
+		s.distance = dot3(s.position, vol.Normal)
+		sorted[i] = s
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].distance < sorted[j].distance })
+
+	vol.Origin = sorted[0].position
+	vol.DataSets = make([]*dicom.DataSet, len(sorted))
+	for i, s := range sorted {
+		vol.DataSets[i] = s.ds
+	}
+
+	gaps := make([]float64, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		gaps[i-1] = sorted[i].distance - sorted[i-1].distance
+		if gaps[i-1] < volumeSpacingTolerance {
+			return nil, fmt.Errorf("slices at %v and %v share the same position",
+				sorted[i-1].position, sorted[i].position)
+		}
+	}
+	if len(gaps) == 0 {
+		// A single slice: report its thickness as the slice spacing, if known
+		if thickness, err := ref.GetFloats(tag.SliceThickness); err == nil && len(thickness) == 1 {
+			vol.Spacing[2] = thickness[0]
+		}
+		vol.Slices = 1
+		vol.Voxels = sorted[0].voxels
+		return vol, nil
+	}
+
+	median := medianGap(gaps)
+	geometryErr := checkVolumeGeometry(sorted, gaps, median, vol)
+	if geometryErr == nil {
+		vol.Spacing[2] = (sorted[len(sorted)-1].distance - sorted[0].distance) / float64(len(gaps))
+		vol.Slices = len(sorted)
+		vol.Voxels = make([]float32, 0, vol.Slices*vol.Rows*vol.Columns)
+		for _, s := range sorted {
+			vol.Voxels = append(vol.Voxels, s.voxels...)
+		}
+		return vol, nil
+	}
+	if !options.resample {
+		return nil, geometryErr
+	}
+
+	resampleVolume(vol, sorted, median)
+	return vol, nil
+}
+
+// readVolumeSlice checks that ds can be stacked with ref and reads its position and
+// rescaled voxels. The first slice read sets the volume's Rows and Columns.
+func readVolumeSlice(ds, ref *dicom.DataSet, orientation, pixelSpacing []float64, vol *Volume) (volumeSlice, error) {
+	if got, want := datasetString(ds, tag.FrameOfReferenceUID), datasetString(ref, tag.FrameOfReferenceUID); got != want {
+		return volumeSlice{}, fmt.Errorf("FrameOfReferenceUID %q differs from %q", got, want)
+	}
+
+	o, err := ds.GetFloats(tag.ImageOrientationPatient)
+	if err != nil || len(o) != 6 {
+		return volumeSlice{}, fmt.Errorf("%w: no valid ImageOrientationPatient", ErrMissingRequiredAttribute)
+	}
+	for i := range o {
+		if math.Abs(o[i]-orientation[i]) > volumeOrientationTolerance {
+			return volumeSlice{}, fmt.Errorf("ImageOrientationPatient %v differs from %v", o, orientation)
+		}
+	}
+	sp, err := ds.GetFloats(tag.PixelSpacing)
+	if err != nil || len(sp) != 2 {
+		return volumeSlice{}, fmt.Errorf("%w: no valid PixelSpacing", ErrMissingRequiredAttribute)
+	}
+	if math.Abs(sp[0]-pixelSpacing[0]) > volumeSpacingTolerance || math.Abs(sp[1]-pixelSpacing[1]) > volumeSpacingTolerance {
+		return volumeSlice{}, fmt.Errorf("PixelSpacing %v differs from %v", sp, pixelSpacing)
+	}
+	position, err := ds.GetFloats(tag.ImagePositionPatient)
+	if err != nil || len(position) != 3 {
+		return volumeSlice{}, fmt.Errorf("%w: no valid ImagePositionPatient", ErrMissingRequiredAttribute)
+	}
+
+	pd, err := Extract(ds)
+	if err != nil {
+		return volumeSlice{}, err
+	}
+	if pd.NumberOfFrames > 1 {
+		return volumeSlice{}, fmt.Errorf("multi-frame image with %d frames cannot be a volume slice", pd.NumberOfFrames)
+	}
+	if pd.SamplesPerPixel != 1 {
+		return volumeSlice{}, fmt.Errorf("colour image with %d samples per pixel cannot be a volume slice", pd.SamplesPerPixel)
+	}
+	if vol.Rows == 0 {
+		vol.Rows, vol.Columns = int(pd.Rows), int(pd.Columns)
+	} else if int(pd.Rows) != vol.Rows || int(pd.Columns) != vol.Columns {
+		return volumeSlice{}, fmt.Errorf("image size %dx%d differs from %dx%d",
+			pd.Columns, pd.Rows, vol.Columns, vol.Rows)
+	}
+
+	lut, err := ExtractModalityLUTFromDataSet(ds)
+	if err != nil {
+		return volumeSlice{}, err
+	}
+	stored := storedValues(pd)
+	voxels := make([]float32, len(stored))
+	for i, v := range stored {
+		voxels[i] = float32(float64(v)*lut.RescaleSlope + lut.RescaleIntercept)
+	}
+
+	s := volumeSlice{ds: ds, voxels: voxels}
+	copy(s.position[:], position)
+	return s, nil
+}
+
+// checkVolumeGeometry returns ErrGantryTilt if a slice is offset in plane from the
+// first, or ErrIrregularSliceSpacing if a gap deviates from the median.
+func checkVolumeGeometry(sorted []volumeSlice, gaps []float64, median float64, vol *Volume) error {
+	rowDir, colDir := vol.rowDirection(), vol.columnDirection()
+	for _, s := range sorted[1:] {
+		offset := sub3(s.position, vol.Origin)
+		u := dot3(offset, rowDir) / vol.Spacing[0]
+		v := dot3(offset, colDir) / vol.Spacing[1]
+		if math.Abs(u) > tiltTolerance || math.Abs(v) > tiltTolerance {
+			return fmt.Errorf("%w: slice at %v is offset %.2f columns and %.2f rows from the first",
+				ErrGantryTilt, s.position, u, v)
+		}
+	}
+	for i, gap := range gaps {
+		if math.Abs(gap-median) > sliceGapTolerance*median {
+			return fmt.Errorf("%w: gap of %.3f mm after slice at %v, median gap %.3f mm",
+				ErrIrregularSliceSpacing, gap, sorted[i].position, median)
+		}
+	}
+	return nil
+}
+
+// resampleVolume fills vol with the source slices interpolated onto slices
+// perpendicular to the normal, starting at the first slice, spaced by gap.
+func resampleVolume(vol *Volume, sorted []volumeSlice, gap float64) {
+	extent := sorted[len(sorted)-1].distance - sorted[0].distance
+	vol.Slices = int(math.Round(extent/gap)) + 1
+	vol.Spacing[2] = extent / float64(vol.Slices-1)
+	vol.Resampled = true
+
+	fill := float32(math.Inf(1))
+	for _, s := range sorted {
+		fill = min(fill, slices.Min(s.voxels))
+	}
+
+	// In-plane offset, in pixels, of the first slice's grid within each source slice
+	rowDir, colDir := vol.rowDirection(), vol.columnDirection()
+	offsets := make([][2]float64, len(sorted))
+	for i, s := range sorted {
+		offset := sub3(vol.Origin, s.position)
+		offsets[i] = [2]float64{dot3(offset, rowDir) / vol.Spacing[0], dot3(offset, colDir) / vol.Spacing[1]}
+	}
+
+	sliceSize := vol.Rows * vol.Columns
+	vol.Voxels = make([]float32, vol.Slices*sliceSize)
+	next := 1
+	for z := 0; z < vol.Slices; z++ {
+		d := sorted[0].distance + float64(z)*vol.Spacing[2]
+		for next < len(sorted)-1 && sorted[next].distance < d {
+			next++
+		}
+		below, above := sorted[next-1], sorted[next]
+		t := (d - below.distance) / (above.distance - below.distance)
+		t = min(max(t, 0), 1)
+
+		out := vol.Voxels[z*sliceSize : (z+1)*sliceSize]
+		for y := 0; y < vol.Rows; y++ {
+			for x := 0; x < vol.Columns; x++ {
+				a, okA := vol.sample(below.voxels, float64(x)+offsets[next-1][0], float64(y)+offsets[next-1][1])
+				b, okB := vol.sample(above.voxels, float64(x)+offsets[next][0], float64(y)+offsets[next][1])
+				switch {
+				case okA && okB:
+					out[y*vol.Columns+x] = float32((1-t)*a + t*b)
+				case okA:
+					out[y*vol.Columns+x] = float32(a)
+				case okB:
+					out[y*vol.Columns+x] = float32(b)
+				default:
+					out[y*vol.Columns+x] = fill
+				}
+			}
+		}
+	}
+}
+
+// sample interpolates a slice's voxels bilinearly at column x and row y, reporting
+// false if the point lies outside the slice.
+func (v *Volume) sample(voxels []float32, x, y float64) (float64, bool) {
+	const edge = 1e-6
+	if x < -edge || y < -edge || x > float64(v.Columns-1)+edge || y > float64(v.Rows-1)+edge {
+		return 0, false
+	}
+	x = min(max(x, 0), float64(v.Columns-1))
+	y = min(max(y, 0), float64(v.Rows-1))
+
+	x0, y0 := int(x), int(y)
+	x1, y1 := min(x0+1, v.Columns-1), min(y0+1, v.Rows-1)
+	fx, fy := x-float64(x0), y-float64(y0)
+
+	at := func(x, y int) float64 { return float64(voxels[y*v.Columns+x]) }
+	top := at(x0, y0)*(1-fx) + at(x1, y0)*fx
+	bottom := at(x0, y1)*(1-fx) + at(x1, y1)*fx
+	return top*(1-fy) + bottom*fy, true
+}
+
+// rowDirection returns the direction cosines of the rows (increasing column).
+func (v *Volume) rowDirection() [3]float64 {
+	return [3]float64{v.Orientation[0], v.Orientation[1], v.Orientation[2]}
+}
+
+// columnDirection returns the direction cosines of the columns (increasing row).
+func (v *Volume) columnDirection() [3]float64 {
+	return [3]float64{v.Orientation[3], v.Orientation[4], v.Orientation[5]}
+}
+
+// medianGap returns the median of the gaps between adjacent slices.
+func medianGap(gaps []float64) float64 {
+	sorted := slices.Clone(gaps)
+	slices.Sort(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// dot3 returns the dot product of two vectors.
+func dot3(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// sub3 returns the vector difference a - b.
+func sub3(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
@@ -0,0 +1,164 @@
+package pixel
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newVolumeSlice returns an axial 3x2 CT slice at position whose stored values are
+// pixels, rescaled by intercept -1024.
+func newVolumeSlice(t *testing.T, position [3]float64, pixels []int16) *dicom.DataSet {
+	t.Helper()
+
+	pd, err := NewPixelDataFromInt16(pixels, 3, 2)
+	require.NoError(t, err)
+	ds := newExtractDataSet(t, pd, "1.2.840.10008.1.2.1")
+	addGSPSString(t, ds, tag.Modality, vr.CodeString, "CT")
+	addGSPSString(t, ds, tag.FrameOfReferenceUID, vr.UniqueIdentifier, "1.2.3")
+	addGSPSString(t, ds, tag.ImageOrientationPatient, vr.DecimalString, "1", "0", "0", "0", "1", "0")
+	addGSPSString(t, ds, tag.PixelSpacing, vr.DecimalString, "0.5", "1")
+	addGSPSString(t, ds, tag.ImagePositionPatient, vr.DecimalString,
+		fmt.Sprint(position[0]), fmt.Sprint(position[1]), fmt.Sprint(position[2]))
+	addGSPSString(t, ds, tag.RescaleIntercept, vr.DecimalString, "-1024")
+	addGSPSString(t, ds, tag.RescaleSlope, vr.DecimalString, "1")
+	return ds
+}
+
+// uniformSlice returns the stored values of a slice with every pixel set to v.
+func uniformSlice(v int16) []int16 {
+	return []int16{v, v, v, v, v, v}
+}
+
+func TestBuildVolume(t *testing.T) {
+	// Slices out of order along z
+	series := []*dicom.DataSet{
+		newVolumeSlice(t, [3]float64{-10, -20, 4}, uniformSlice(1044)),
+		newVolumeSlice(t, [3]float64{-10, -20, 0}, []int16{1024, 1025, 1026, 1027, 1028, 1029}),
+		newVolumeSlice(t, [3]float64{-10, -20, 2}, uniformSlice(1034)),
+	}
+
+	vol, err := BuildVolume(series)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, vol.Columns)
+	assert.Equal(t, 2, vol.Rows)
+	assert.Equal(t, 3, vol.Slices)
+	assert.Equal(t, [3]float64{1, 0.5, 2}, vol.Spacing)
+	assert.Equal(t, [3]float64{-10, -20, 0}, vol.Origin)
+	assert.Equal(t, [3]float64{0, 0, 1}, vol.Normal)
+	assert.Equal(t, "HU", vol.Units)
+	assert.False(t, vol.Resampled)
+	assert.Equal(t, []*dicom.DataSet{series[1], series[2], series[0]}, vol.DataSets)
+
+	require.Len(t, vol.Voxels, 18)
+	assert.Equal(t, []float32{0, 1, 2, 3, 4, 5}, vol.Voxels[:6])
+	assert.Equal(t, float32(5), vol.At(2, 1, 0))
+	assert.Equal(t, float32(10), vol.At(1, 0, 1))
+	assert.Equal(t, float32(20), vol.At(2, 1, 2))
+
+	hu, err := vol.Int16()
+	require.NoError(t, err)
+	assert.Equal(t, int16(20), hu[vol.Index(0, 0, 2)])
+
+	t.Run("single slice", func(t *testing.T) {
+		ds := newVolumeSlice(t, [3]float64{0, 0, 0}, uniformSlice(1024))
+		addGSPSString(t, ds, tag.SliceThickness, vr.DecimalString, "1.25")
+		vol, err := BuildVolume([]*dicom.DataSet{ds})
+		require.NoError(t, err)
+		assert.Equal(t, 1, vol.Slices)
+		assert.Equal(t, 1.25, vol.Spacing[2])
+	})
+}
+
+func TestBuildVolume_IrregularSpacing(t *testing.T) {
+	// Gaps of 1 and 2 mm; values rise by 10 HU per mm
+	series := []*dicom.DataSet{
+		newVolumeSlice(t, [3]float64{0, 0, 0}, uniformSlice(1024)),
+		newVolumeSlice(t, [3]float64{0, 0, 1}, uniformSlice(1034)),
+		newVolumeSlice(t, [3]float64{0, 0, 3}, uniformSlice(1054)),
+	}
+
+	_, err := BuildVolume(series)
+	require.ErrorIs(t, err, ErrIrregularSliceSpacing)
+
+	vol, err := BuildVolume(series, WithResampling())
+	require.NoError(t, err)
+	assert.True(t, vol.Resampled)
+	assert.Equal(t, 3, vol.Slices)
+	assert.Equal(t, 1.5, vol.Spacing[2], "median gap")
+	assert.Equal(t, float32(0), vol.At(0, 0, 0))
+	assert.InDelta(t, 15, vol.At(1, 1, 1), 1e-4)
+	assert.InDelta(t, 30, vol.At(2, 0, 2), 1e-4)
+}
+
+func TestBuildVolume_GantryTilt(t *testing.T) {
+	// Each slice is shifted one column (1 mm) along x from the previous one; the
+	// stored values are 100 per mm of x, so the same anatomy lines up after resampling
+	var series []*dicom.DataSet
+	for k := range 3 {
+		x := int16(100 * k)
+		series = append(series, newVolumeSlice(t, [3]float64{float64(k), 0, float64(2 * k)},
+			[]int16{1024 + x, 1124 + x, 1224 + x, 1024 + x, 1124 + x, 1224 + x}))
+	}
+
+	_, err := BuildVolume(series)
+	require.ErrorIs(t, err, ErrGantryTilt)
+
+	vol, err := BuildVolume(series, WithResampling())
+	require.NoError(t, err)
+	assert.Equal(t, 3, vol.Slices)
+	assert.Equal(t, 2.0, vol.Spacing[2])
+	assert.Equal(t, [3]float64{0, 0, 0}, vol.Origin)
+
+	// Column x of every resampled slice lies at x mm
+	for z := range 3 {
+		for x := z; x < 3; x++ {
+			assert.InDelta(t, float32(100*x), vol.At(x, 1, z), 1e-3, "x=%d z=%d", x, z)
+		}
+	}
+	// Left of the shifted slices there is no data: the series minimum
+	assert.Equal(t, float32(0), vol.At(0, 0, 2))
+}
+
+func TestBuildVolume_Errors(t *testing.T) {
+	_, err := BuildVolume(nil)
+	assert.ErrorContains(t, err, "series is empty")
+
+	_, err = BuildVolume([]*dicom.DataSet{nil})
+	assert.ErrorContains(t, err, "slice 0 is nil")
+
+	t.Run("duplicate position", func(t *testing.T) {
+		_, err := BuildVolume([]*dicom.DataSet{
+			newVolumeSlice(t, [3]float64{0, 0, 0}, uniformSlice(0)),
+			newVolumeSlice(t, [3]float64{0, 0, 0}, uniformSlice(0)),
+		})
+		assert.ErrorContains(t, err, "share the same position")
+	})
+
+	t.Run("differing orientation", func(t *testing.T) {
+		other := newVolumeSlice(t, [3]float64{0, 0, 1}, uniformSlice(0))
+		addGSPSString(t, other, tag.ImageOrientationPatient, vr.DecimalString, "0", "1", "0", "0", "0", "-1")
+		_, err := BuildVolume([]*dicom.DataSet{newVolumeSlice(t, [3]float64{0, 0, 0}, uniformSlice(0)), other})
+		assert.ErrorContains(t, err, "slice 1: ImageOrientationPatient")
+	})
+
+	t.Run("differing frame of reference", func(t *testing.T) {
+		other := newVolumeSlice(t, [3]float64{0, 0, 1}, uniformSlice(0))
+		addGSPSString(t, other, tag.FrameOfReferenceUID, vr.UniqueIdentifier, "9.9.9")
+		_, err := BuildVolume([]*dicom.DataSet{newVolumeSlice(t, [3]float64{0, 0, 0}, uniformSlice(0)), other})
+		assert.ErrorContains(t, err, "FrameOfReferenceUID")
+	})
+
+	t.Run("missing position", func(t *testing.T) {
+		ds := newVolumeSlice(t, [3]float64{0, 0, 0}, uniformSlice(0))
+		require.NoError(t, ds.Remove(tag.ImagePositionPatient))
+		_, err := BuildVolume([]*dicom.DataSet{ds})
+		assert.ErrorIs(t, err, ErrMissingRequiredAttribute)
+	})
+}
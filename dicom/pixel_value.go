@@ -0,0 +1,116 @@
+package dicom
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// pixelValueTags are the "US or SS" attributes holding a stored pixel value, whose
+// signedness follows Pixel Representation (0028,0103). LUT descriptors are also
+// "US or SS" but only their first mapped value follows it, so they are not listed.
+var pixelValueTags = map[tag.Tag]bool{
+	tag.ZeroVelocityPixelValue:         true,
+	tag.MappedPixelValue:               true,
+	tag.PerimeterValue:                 true,
+	tag.SmallestValidPixelValue:        true,
+	tag.LargestValidPixelValue:         true,
+	tag.SmallestImagePixelValue:        true,
+	tag.LargestImagePixelValue:         true,
+	tag.SmallestPixelValueInSeries:     true,
+	tag.LargestPixelValueInSeries:      true,
+	tag.SmallestImagePixelValueInPlane: true,
+	tag.LargestImagePixelValueInPlane:  true,
+	tag.PixelPaddingValue:              true,
+	tag.PixelPaddingRangeLimit:         true,
+	tag.RealWorldValueFirstValueMapped: true,
+	tag.RealWorldValueLastValueMapped:  true,
+	tag.HistogramFirstBinValue:         true,
+	tag.HistogramLastBinValue:          true,
+}
+
+// IsPixelValueTag reports whether t is a "US or SS" attribute holding a stored
+// pixel value, such as Smallest Image Pixel Value (0028,0106) or Pixel Padding Value
+// (0028,0120), whose VR is decided by Pixel Representation (0028,0103).
+func IsPixelValueTag(t tag.Tag) bool {
+	return pixelValueTags[t]
+}
+
+// PixelValueVR returns the VR of a pixel value attribute (see IsPixelValueTag) for
+// the given Pixel Representation: SS for signed (1) pixel data, US otherwise.
+//
+// The parser uses it to resolve these attributes in Implicit VR datasets, from the
+// Pixel Representation already read from the same dataset or an enclosing one.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_A.1
+func PixelValueVR(pixelRepresentation uint16) vr.VR {
+	if pixelRepresentation == 1 {
+		return vr.SignedShort
+	}
+	return vr.UnsignedShort
+}
+
+// GetPixelValueStat returns the first value of a "US or SS" pixel value attribute
+// such as Smallest Image Pixel Value (0028,0106), Largest Image Pixel Value
+// (0028,0107) or Pixel Padding Value (0028,0120), with the signedness given by the
+// dataset's Pixel Representation (0028,0103) (unsigned when absent).
+//
+// The value is reinterpreted when its recorded VR disagrees with Pixel
+// Representation, as when a writer encoded a signed value as US: the 16 bits are
+// read as int16 for signed pixel data and as uint16 otherwise. UN values are decoded
+// as little endian.
+//
+// Returns an error if the tag is not present, is empty, or is not a 16-bit integer.
+//
+// Example:
+//
+//	// Signed CT with Smallest Image Pixel Value stored as US 0xFC18
+//	smallest, err := ds.GetPixelValueStat(tag.SmallestImagePixelValue) // -1000
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.7.6.3.1.4
+func (ds *DataSet) GetPixelValueStat(t tag.Tag) (int64, error) {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return 0, err
+	}
+
+	var bits uint16
+	switch v := elem.Value().(type) {
+	case *value.IntValue:
+		if v.VR() != vr.UnsignedShort && v.VR() != vr.SignedShort {
+			return 0, fmt.Errorf("element %s is not US or SS (VR %s)", t, v.VR())
+		}
+		ints := v.Ints()
+		if len(ints) == 0 {
+			return 0, fmt.Errorf("element %s is empty", t)
+		}
+		bits = uint16(ints[0])
+	case *value.BytesValue:
+		b := v.Bytes()
+		if len(b) < 2 {
+			return 0, fmt.Errorf("element %s is empty", t)
+		}
+		bits = binary.LittleEndian.Uint16(b)
+	default:
+		return 0, fmt.Errorf("element %s is not US or SS (VR %s)", t, elem.VR())
+	}
+
+	if PixelValueVR(ds.pixelRepresentation()) == vr.SignedShort {
+		return int64(int16(bits)), nil
+	}
+	return int64(bits), nil
+}
+
+// pixelRepresentation returns Pixel Representation (0028,0103), or 0 when absent.
+func (ds *DataSet) pixelRepresentation() uint16 {
+	values, err := ds.GetInts(tag.PixelRepresentation)
+	if err != nil || len(values) == 0 {
+		return 0
+	}
+	return uint16(values[0])
+}
@@ -0,0 +1,64 @@
+package dicom_test
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPixelValueStat(t *testing.T) {
+	build := func(pixelRepresentation int64, v vr.VR, stored int64) *dicom.DataSet {
+		ds := dicom.NewDataSet()
+		if pixelRepresentation >= 0 {
+			pr, err := value.NewIntValue(vr.UnsignedShort, []int64{pixelRepresentation})
+			require.NoError(t, err)
+			require.NoError(t, ds.Add(mustNewElement(tag.PixelRepresentation, vr.UnsignedShort, pr)))
+		}
+		val, err := value.NewIntValue(v, []int64{stored})
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(mustNewElement(tag.SmallestImagePixelValue, v, val)))
+		return ds
+	}
+
+	tests := []struct {
+		name                string
+		pixelRepresentation int64 // -1 to omit
+		vr                  vr.VR
+		stored              int64
+		want                int64
+	}{
+		{"signed stored as SS", 1, vr.SignedShort, -1000, -1000},
+		{"signed stored as US", 1, vr.UnsignedShort, 0xFC18, -1000},
+		{"unsigned stored as US", 0, vr.UnsignedShort, 0xFC18, 0xFC18},
+		{"unsigned stored as SS", 0, vr.SignedShort, -1000, 0xFC18},
+		{"no pixel representation", -1, vr.UnsignedShort, 40000, 40000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := build(tt.pixelRepresentation, tt.vr, tt.stored).GetPixelValueStat(tag.SmallestImagePixelValue)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("UN value", func(t *testing.T) {
+		ds := build(1, vr.SignedShort, 0)
+		raw, err := value.NewBytesValue(vr.Unknown, []byte{0x18, 0xFC})
+		require.NoError(t, err)
+		require.NoError(t, ds.Set(mustNewElement(tag.LargestImagePixelValue, vr.Unknown, raw)))
+
+		got, err := ds.GetPixelValueStat(tag.LargestImagePixelValue)
+		require.NoError(t, err)
+		assert.Equal(t, int64(-1000), got)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := dicom.NewDataSet().GetPixelValueStat(tag.PixelPaddingValue)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,124 @@
+package dicom
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// PrivateBlock is a view over the block of private elements reserved by one Private
+// Creator in a dataset.
+//
+// A Private Creator element (gggg,00xx) reserves the elements (gggg,xx00-xxFF) of an
+// odd group. Elements are addressed by their offset, the low byte of the element
+// number, which stays the same across datasets even when the creator reserves a
+// different block. The view reads the dataset directly, so later changes to the
+// dataset are visible through it.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.8.1
+type PrivateBlock struct {
+	ds      *DataSet
+	group   uint16
+	block   uint8
+	creator string
+}
+
+// PrivateBlock returns the private block reserved by creator in group.
+//
+// The Private Creator elements (gggg,0010-00FF) are searched for a value equal to
+// creator, ignoring padding. If the creator reserved more than one block in the
+// group, the lowest one is returned.
+//
+// Returns an error if group is not a private (odd) group or creator has no block in
+// it.
+//
+// Example:
+//
+//	block, err := ds.PrivateBlock(0x0029, "SIEMENS CSA HEADER")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	elem, err := block.Get(0x10) // (0029,xx10)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.8.1
+func (ds *DataSet) PrivateBlock(group uint16, creator string) (*PrivateBlock, error) {
+	if group%2 == 0 {
+		return nil, fmt.Errorf("group %04X is not a private group", group)
+	}
+	creator = strings.TrimSpace(creator)
+	if creator == "" {
+		return nil, fmt.Errorf("private creator is empty")
+	}
+
+	for block := 0x10; block <= 0xFF; block++ {
+		elem, err := ds.Get(tag.New(group, uint16(block)))
+		if err != nil {
+			continue
+		}
+		if strings.TrimRight(strings.TrimSpace(elem.Value().String()), "\x00") == creator {
+			return &PrivateBlock{ds: ds, group: group, block: uint8(block), creator: creator}, nil
+		}
+	}
+	return nil, fmt.Errorf("private creator %q not found in group %04X", creator, group)
+}
+
+// Creator returns the Private Creator of the block.
+func (b *PrivateBlock) Creator() string {
+	return b.creator
+}
+
+// Group returns the private group of the block.
+func (b *PrivateBlock) Group() uint16 {
+	return b.group
+}
+
+// Tag returns the tag of the element at offset within the block, (gggg,xxoo).
+func (b *PrivateBlock) Tag(offset uint8) tag.Tag {
+	return tag.New(b.group, uint16(b.block)<<8|uint16(offset))
+}
+
+// Get returns the element at offset within the block.
+//
+// Returns an error if the element is not present.
+func (b *PrivateBlock) Get(offset uint8) (*element.Element, error) {
+	return b.ds.Get(b.Tag(offset))
+}
+
+// GetByKeyword returns the element with the given keyword in the private dictionary
+// registered for the block's creator with tag.RegisterPrivateDictionary.
+//
+// Returns an error if the keyword is not registered for the creator or the element
+// is not present.
+//
+// Example:
+//
+//	elem, err := block.GetByKeyword("CoilTemperature")
+func (b *PrivateBlock) GetByKeyword(keyword string) (*element.Element, error) {
+	info, err := tag.FindPrivateByKeyword(b.group, b.creator, keyword)
+	if err != nil {
+		return nil, err
+	}
+	return b.Get(info.Offset)
+}
+
+// Info returns the private dictionary entry for the element at offset, if one is
+// registered for the block's creator.
+func (b *PrivateBlock) Info(offset uint8) (tag.PrivateInfo, bool) {
+	info, err := tag.FindPrivate(b.group, b.creator, offset)
+	return info, err == nil
+}
+
+// Elements returns the elements of the block present in the dataset, in offset order.
+func (b *PrivateBlock) Elements() []*element.Element {
+	var elements []*element.Element
+	for offset := 0; offset <= 0xFF; offset++ {
+		if elem, err := b.Get(uint8(offset)); err == nil {
+			elements = append(elements, elem)
+		}
+	}
+	return elements
+}
@@ -0,0 +1,79 @@
+package dicom_test
+
+import (
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPrivateDataSet returns a dataset where "ACME 1.0" reserves block 0x11 of group
+// 0x0019, after another creator's block 0x10.
+func newPrivateDataSet(t *testing.T) *dicom.DataSet {
+	ds := dicom.NewDataSet()
+	add := func(tg tag.Tag, v vr.VR, s string) {
+		require.NoError(t, ds.Add(mustNewElement(tg, v, mustNewStringValue(v, []string{s}))))
+	}
+	add(tag.New(0x0019, 0x0010), vr.LongString, "OTHER VENDOR")
+	add(tag.New(0x0019, 0x0011), vr.LongString, "ACME 1.0 ")
+	add(tag.New(0x0019, 0x1010), vr.LongString, "other value")
+	add(tag.New(0x0019, 0x1110), vr.DecimalString, "36.5")
+	add(tag.New(0x0019, 0x1120), vr.LongString, "FAST")
+	add(tag.PatientID, vr.LongString, "PAT001")
+	return ds
+}
+
+func TestDataSet_PrivateBlock(t *testing.T) {
+	ds := newPrivateDataSet(t)
+
+	block, err := ds.PrivateBlock(0x0019, "ACME 1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "ACME 1.0", block.Creator())
+	assert.Equal(t, uint16(0x0019), block.Group())
+	assert.Equal(t, tag.New(0x0019, 0x1110), block.Tag(0x10))
+
+	elem, err := block.Get(0x10)
+	require.NoError(t, err)
+	assert.Equal(t, "36.5", elem.Value().String())
+
+	elements := block.Elements()
+	require.Len(t, elements, 2, "other creators' elements are not part of the block")
+	assert.Equal(t, tag.New(0x0019, 0x1110), elements[0].Tag())
+	assert.Equal(t, tag.New(0x0019, 0x1120), elements[1].Tag())
+
+	_, err = block.Get(0x30)
+	assert.Error(t, err)
+
+	_, err = ds.PrivateBlock(0x0019, "MISSING")
+	assert.Error(t, err)
+	_, err = ds.PrivateBlock(0x0010, "ACME 1.0")
+	assert.Error(t, err, "even groups are not private")
+	_, err = ds.PrivateBlock(0x0019, "")
+	assert.Error(t, err)
+}
+
+func TestPrivateBlock_GetByKeyword(t *testing.T) {
+	require.NoError(t, tag.RegisterPrivateDictionary(
+		tag.PrivateInfo{Creator: "ACME 1.0", Group: 0x0019, Offset: 0x10, VR: vr.DecimalString,
+			Name: "Coil Temperature", Keyword: "CoilTemperature", VM: "1"},
+	))
+
+	block, err := newPrivateDataSet(t).PrivateBlock(0x0019, "ACME 1.0")
+	require.NoError(t, err)
+
+	elem, err := block.GetByKeyword("CoilTemperature")
+	require.NoError(t, err)
+	assert.Equal(t, "36.5", elem.Value().String())
+
+	info, ok := block.Info(0x10)
+	require.True(t, ok)
+	assert.Equal(t, "Coil Temperature", info.Name)
+	_, ok = block.Info(0x20)
+	assert.False(t, ok)
+
+	_, err = block.GetByKeyword("Unregistered")
+	assert.Error(t, err)
+}
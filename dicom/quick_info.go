@@ -0,0 +1,86 @@
+package dicom
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// quickInfoBufferSize is the read buffer QuickInfo uses for the preamble and File
+// Meta Information.
+const quickInfoBufferSize = 1024
+
+// FileInfo holds the identifying attributes of a DICOM file from its File Meta
+// Information, as returned by QuickInfo.
+type FileInfo struct {
+	MediaStorageSOPClassUID    string // (0002,0002)
+	MediaStorageSOPInstanceUID string // (0002,0003)
+	TransferSyntaxUID          string // (0002,0010)
+	ImplementationVersionName  string // (0002,0013), empty if absent
+}
+
+// QuickInfo reads only the preamble and File Meta Information (group 0002) of the
+// DICOM file at path and returns its SOP Class, SOP Instance and Transfer Syntax
+// UIDs and Implementation Version Name.
+//
+// Reading stops at the end of the File Meta Information, so the main dataset,
+// including its pixel data, is never read or decoded. This makes QuickInfo suitable
+// for routing and classifying large archives, where a full ParseFile per file would
+// be wasteful. The values come from the File Meta Information only; a file whose
+// main dataset disagrees with it is not detected.
+//
+// Returns an error if:
+//   - the file cannot be opened or has no valid preamble and "DICM" prefix
+//   - the File Meta Information cannot be read
+//   - Transfer Syntax UID (0002,0010) is missing (ErrMissingTransferSyntax)
+//
+// Example:
+//
+//	info, err := dicom.QuickInfo("image.dcm")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if info.MediaStorageSOPClassUID == uid.CTImageStorage.String() {
+//	    routeToCT(info.MediaStorageSOPInstanceUID)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part10.html#sect_7.1
+func QuickInfo(path string) (*FileInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	//nolint:errcheck // File close in defer for read-only operation
+	defer func() { _ = file.Close() }()
+
+	// One buffered read usually covers the preamble and the whole File Meta group
+	reader := NewReaderSize(file, binary.LittleEndian, quickInfoBufferSize)
+	reader.size = streamSize(file)
+	parser := &Parser{
+		reader:    reader,
+		rawReader: file,
+		opts:      applyDefaultParseOptions(ParseOptions{}),
+	}
+
+	if err := parser.readPreamble(); err != nil {
+		return nil, fmt.Errorf("invalid DICOM file: %w", err)
+	}
+	metaInfo, err := parser.readFileMetaInformation()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read File Meta Information: %w", err)
+	}
+
+	info := &FileInfo{
+		MediaStorageSOPClassUID:    stringValue(metaInfo, tag.MediaStorageSOPClassUID),
+		MediaStorageSOPInstanceUID: stringValue(metaInfo, tag.MediaStorageSOPInstanceUID),
+		TransferSyntaxUID:          stringValue(metaInfo, tag.TransferSyntaxUID),
+		ImplementationVersionName:  stringValue(metaInfo, tag.ImplementationVersionName),
+	}
+	if info.TransferSyntaxUID == "" {
+		return nil, fmt.Errorf("%w: Transfer Syntax UID not found in File Meta Information", ErrMissingTransferSyntax)
+	}
+	return info, nil
+}
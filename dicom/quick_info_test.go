@@ -0,0 +1,106 @@
+package dicom_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuickInfo(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "testdata", "dicom", "*.dcm"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	checked := 0
+	for _, path := range files {
+		ds, err := dicom.ParseFile(path)
+		if err != nil {
+			continue // Deliberately broken test files
+		}
+		info, err := dicom.QuickInfo(path)
+		require.NoError(t, err, path)
+
+		assert.Equal(t, stringOfOptional(ds, tag.MediaStorageSOPClassUID), info.MediaStorageSOPClassUID, path)
+		assert.Equal(t, stringOfOptional(ds, tag.MediaStorageSOPInstanceUID), info.MediaStorageSOPInstanceUID, path)
+		assert.Equal(t, stringOfOptional(ds, tag.TransferSyntaxUID), info.TransferSyntaxUID, path)
+		assert.Equal(t, stringOfOptional(ds, tag.ImplementationVersionName), info.ImplementationVersionName, path)
+		checked++
+	}
+	assert.Greater(t, checked, 10)
+}
+
+func TestQuickInfo_WrittenFile(t *testing.T) {
+	ds, err := newSecondaryCaptureBuilder().Build()
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "sc.dcm")
+	require.NoError(t, dicom.WriteFile(path, ds))
+
+	info, err := dicom.QuickInfo(path)
+	require.NoError(t, err)
+	assert.Equal(t, stringOf(t, ds, tag.SOPClassUID), info.MediaStorageSOPClassUID)
+	assert.Equal(t, stringOf(t, ds, tag.SOPInstanceUID), info.MediaStorageSOPInstanceUID)
+	assert.NotEmpty(t, info.TransferSyntaxUID)
+}
+
+func TestQuickInfo_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := dicom.QuickInfo(filepath.Join(dir, "missing.dcm"))
+	assert.Error(t, err)
+
+	notDICOM := filepath.Join(dir, "text.dcm")
+	require.NoError(t, os.WriteFile(notDICOM, []byte("not a DICOM file"), 0o600))
+	_, err = dicom.QuickInfo(notDICOM)
+	assert.ErrorIs(t, err, dicom.ErrInvalidPreamble)
+}
+
+// stringOfOptional returns the trimmed string value of tg, or "" if it is absent.
+func stringOfOptional(ds *dicom.DataSet, tg tag.Tag) string {
+	elem, err := ds.Get(tg)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(strings.TrimSpace(elem.Value().String()), "\x00")
+}
+
+func BenchmarkQuickInfo(b *testing.B) {
+	files := benchmarkSeriesFiles(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, path := range files {
+			if _, err := dicom.QuickInfo(path); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkQuickInfo_FullParse parses the same files completely, for comparison.
+func BenchmarkQuickInfo_FullParse(b *testing.B) {
+	files := benchmarkSeriesFiles(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, path := range files {
+			if _, err := dicom.ParseFile(path); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func benchmarkSeriesFiles(b *testing.B) []string {
+	b.Helper()
+	files, err := filepath.Glob(filepath.Join("..", "testdata", "dicom", "nested", "series_7", "*.dcm"))
+	if err != nil || len(files) == 0 {
+		b.Fatalf("no benchmark files: %v", err)
+	}
+	return files
+}
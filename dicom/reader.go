@@ -5,6 +5,7 @@
 package dicom
 
 import (
+	"bufio"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -14,12 +15,23 @@ import (
 // It supports both Little Endian and Big Endian byte ordering, which can be changed
 // dynamically during parsing.
 //
+// Reader is usable on its own for building network layers such as DIMSE PDU parsing:
+// create it with NewReaderSize to buffer small reads from a socket, and use Position
+// to find where a length-delimited structure ends.
+//
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.3
 type Reader struct {
 	r         io.Reader
 	byteOrder binary.ByteOrder
-	position  int64 // Track bytes read for position tracking
+	position  int64   // Track bytes read for position tracking
+	size      int64   // Total bytes available from the start position, or -1 if unknown
+	scratch   [8]byte // Reused for fixed-size reads
+
+	// record holds the bytes read while at least one capture is active (see
+	// beginCapture); capturing counts the active captures.
+	record    []byte
+	capturing int
 }
 
 // NewReader creates a new DICOM binary reader with the specified byte order.
@@ -34,30 +46,109 @@ func NewReader(r io.Reader, byteOrder binary.ByteOrder) *Reader {
 	return &Reader{
 		r:         r,
 		byteOrder: byteOrder,
+		size:      -1,
 	}
 }
 
-// ReadUint16 reads a 16-bit unsigned integer using the current byte order.
+// NewReaderSize creates a reader that buffers reads from r in a buffer of at least
+// bufferSize bytes.
 //
-// Returns io.EOF if the end of the stream is reached.
+// Unbuffered, every typed read is a separate Read call on r, which is expensive for
+// network connections. Buffering may read ahead of the bytes consumed, so r should
+// not be read directly afterwards. Position still counts only consumed bytes. A
+// bufferSize of 0 or less returns an unbuffered reader, like NewReader.
 //
-// DICOM Standard Reference:
-// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1.2
-func (r *Reader) ReadUint16() (uint16, error) {
-	buf := make([]byte, 2)
+// Example:
+//
+//	conn, _ := net.Dial("tcp", "pacs.example.org:104")
+//	r := dicom.NewReaderSize(conn, binary.BigEndian, 64*1024)
+//	pduType, _ := r.ReadUint8()
+func NewReaderSize(r io.Reader, byteOrder binary.ByteOrder, bufferSize int) *Reader {
+	if bufferSize > 0 {
+		r = bufio.NewReaderSize(r, bufferSize)
+	}
+	return NewReader(r, byteOrder)
+}
+
+// readFull fills buf from the stream, advancing the position.
+//
+// Returns io.EOF if no bytes were available and io.ErrUnexpectedEOF if the stream
+// ended part way through buf.
+func (r *Reader) readFull(buf []byte, what string) error {
+	if err := r.fill(buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return err
+		}
+		return fmt.Errorf("failed to read %s: %w", what, err)
+	}
+	return nil
+}
+
+// fill is readFull without the description of what was being read, for callers
+// that only need to build one when the read fails.
+func (r *Reader) fill(buf []byte) error {
 	n, err := io.ReadFull(r.r, buf)
 	if err != nil {
 		if err == io.EOF && n == 0 {
-			return 0, io.EOF
+			return io.EOF
 		}
 		if err == io.ErrUnexpectedEOF || (err == io.EOF && n > 0) {
-			return 0, io.ErrUnexpectedEOF
+			return io.ErrUnexpectedEOF
 		}
-		return 0, fmt.Errorf("failed to read uint16: %w", err)
+		return err
 	}
 
-	r.position += 2
-	return r.byteOrder.Uint16(buf), nil
+	r.position += int64(n)
+	if r.capturing > 0 {
+		r.record = append(r.record, buf...)
+	}
+	return nil
+}
+
+// beginCapture starts recording the bytes read, returning the mark to pass to the
+// matching endCapture. Captures nest, as for the elements of a sequence item.
+func (r *Reader) beginCapture() int {
+	r.capturing++
+	return len(r.record)
+}
+
+// endCapture returns the bytes read since the beginCapture that returned mark.
+//
+// Nested captures share the record; it is discarded rather than reused when the
+// outermost capture ends, so returned slices stay valid.
+func (r *Reader) endCapture(mark int) []byte {
+	r.capturing--
+	captured := r.record[mark:len(r.record):len(r.record)]
+	if captured == nil {
+		captured = []byte{} // Empty values were captured too
+	}
+	if r.capturing == 0 {
+		r.record = nil
+	}
+	return captured
+}
+
+// ReadUint8 reads a single byte.
+//
+// Returns io.EOF if the end of the stream is reached.
+func (r *Reader) ReadUint8() (uint8, error) {
+	if err := r.readFull(r.scratch[:1], "uint8"); err != nil {
+		return 0, err
+	}
+	return r.scratch[0], nil
+}
+
+// ReadUint16 reads a 16-bit unsigned integer using the current byte order.
+//
+// Returns io.EOF if the end of the stream is reached.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1.2
+func (r *Reader) ReadUint16() (uint16, error) {
+	if err := r.readFull(r.scratch[:2], "uint16"); err != nil {
+		return 0, err
+	}
+	return r.byteOrder.Uint16(r.scratch[:2]), nil
 }
 
 // ReadUint32 reads a 32-bit unsigned integer using the current byte order.
@@ -67,50 +158,81 @@ func (r *Reader) ReadUint16() (uint16, error) {
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1.2
 func (r *Reader) ReadUint32() (uint32, error) {
-	buf := make([]byte, 4)
-	n, err := io.ReadFull(r.r, buf)
-	if err != nil {
-		if err == io.EOF && n == 0 {
-			return 0, io.EOF
-		}
-		if err == io.ErrUnexpectedEOF || (err == io.EOF && n > 0) {
-			return 0, io.ErrUnexpectedEOF
-		}
-		return 0, fmt.Errorf("failed to read uint32: %w", err)
+	if err := r.readFull(r.scratch[:4], "uint32"); err != nil {
+		return 0, err
 	}
+	return r.byteOrder.Uint32(r.scratch[:4]), nil
+}
 
-	r.position += 4
-	return r.byteOrder.Uint32(buf), nil
+// ReadUint64 reads a 64-bit unsigned integer using the current byte order, as used
+// by UV (Unsigned 64-bit Very Long) values.
+//
+// Returns io.EOF if the end of the stream is reached.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1.2
+func (r *Reader) ReadUint64() (uint64, error) {
+	if err := r.readFull(r.scratch[:8], "uint64"); err != nil {
+		return 0, err
+	}
+	return r.byteOrder.Uint64(r.scratch[:8]), nil
 }
 
+// readBytesChunk is the largest buffer ReadBytes allocates ahead of the data when
+// the stream size is unknown, so that a corrupt length cannot allocate far more
+// memory than the stream holds.
+const readBytesChunk = 1 << 20
+
 // ReadBytes reads exactly n bytes from the reader.
 //
 // Returns an error if fewer than n bytes are available.
 // Returns an empty slice if n is 0.
 //
+// When the stream size is unknown, large reads grow their buffer as the data
+// arrives rather than allocating n bytes up front.
+//
 // DICOM Standard Reference:
 // https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1.2
 func (r *Reader) ReadBytes(n int) ([]byte, error) {
 	if n == 0 {
 		return []byte{}, nil
 	}
+	if n < 0 {
+		return nil, fmt.Errorf("failed to read %d bytes: negative length", n)
+	}
 
-	buf := make([]byte, n)
-	read, err := io.ReadFull(r.r, buf)
-	if err != nil {
-		if err == io.EOF && read == 0 {
-			return nil, io.EOF
+	if _, known := r.Remaining(); known || n <= readBytesChunk {
+		buf := make([]byte, n)
+		if err := r.fill(buf); err != nil {
+			return nil, readBytesError(n, err)
 		}
-		if err == io.ErrUnexpectedEOF || (err == io.EOF && read > 0) {
-			return nil, io.ErrUnexpectedEOF
+		return buf, nil
+	}
+
+	buf := make([]byte, 0, readBytesChunk)
+	for len(buf) < n {
+		start := len(buf)
+		buf = append(buf, make([]byte, min(n-start, readBytesChunk))...)
+		if err := r.fill(buf[start:]); err != nil {
+			if start > 0 && err == io.EOF {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, readBytesError(n, err)
 		}
-		return nil, fmt.Errorf("failed to read %d bytes: %w", n, err)
 	}
 
-	r.position += int64(n)
 	return buf, nil
 }
 
+// readBytesError describes a failed read of n bytes, leaving io.EOF and
+// io.ErrUnexpectedEOF unwrapped as readFull does.
+func readBytesError(n int, err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return err
+	}
+	return fmt.Errorf("failed to read %d bytes: %w", n, err)
+}
+
 // ReadString reads exactly n bytes and returns them as a string.
 //
 // DICOM strings may contain null terminators or trailing spaces which are preserved.
@@ -147,21 +269,59 @@ func (r *Reader) SetByteOrder(order binary.ByteOrder) {
 
 // Position returns the current byte position in the stream.
 //
-// This tracks the total number of bytes read from the underlying reader,
-// which is useful for parsing operations that need to know byte offsets.
+// This tracks the total number of bytes consumed through the Reader (not read ahead
+// by buffering), which is useful for parsing operations that need to know byte
+// offsets or when a length-delimited structure such as a PDU ends.
 func (r *Reader) Position() int64 {
 	return r.position
 }
 
+// Remaining returns the number of unread bytes in the stream, if known.
+//
+// The size is known when the underlying reader reports its length (bytes.Reader,
+// strings.Reader, bytes.Buffer) or can seek (os.File). The second result is false
+// when the size is unknown, for example for network streams or inflated data.
+func (r *Reader) Remaining() (int64, bool) {
+	if r.size < 0 {
+		return 0, false
+	}
+	return r.size - r.position, true
+}
+
 // WrapReader replaces the underlying reader with a new one.
 //
 // This is used for applying transformations to the reader stream,
 // such as wrapping it in a decompression reader for deflated transfer syntax.
 // The position counter is preserved to maintain accurate position tracking
-// relative to the original stream.
+// relative to the original stream. The remaining size becomes unknown.
 //
 // Parameters:
 //   - newReader: The new io.Reader to use for subsequent read operations
 func (r *Reader) WrapReader(newReader io.Reader) {
 	r.r = newReader
+	r.size = -1
+}
+
+// streamSize returns the number of bytes readable from r's current position,
+// or -1 if it cannot be determined without consuming the stream.
+func streamSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case io.Seeker:
+		cur, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := v.Seek(cur, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - cur
+	default:
+		return -1
+	}
 }
@@ -4,8 +4,10 @@ package dicom
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"io"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -162,6 +164,42 @@ func TestReader_ReadBytes(t *testing.T) {
 	}
 }
 
+// TestReader_ReadBytes_UnknownSize tests that large reads from a stream of unknown
+// size succeed in chunks and that a length beyond the end of the stream fails.
+func TestReader_ReadBytes_UnknownSize(t *testing.T) {
+	data := bytes.Repeat([]byte{0xAB}, readBytesChunk*2+3)
+
+	reader := NewReader(io.MultiReader(bytes.NewReader(data)), binary.LittleEndian)
+	got, err := reader.ReadBytes(len(data))
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+	assert.Equal(t, int64(len(data)), reader.Position())
+
+	reader = NewReader(io.MultiReader(bytes.NewReader(data)), binary.LittleEndian)
+	_, err = reader.ReadBytes(1 << 31)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+// TestReader_ReadBytes_Allocations tests that a successful read allocates only the
+// returned buffer and that a failed read still describes its length.
+func TestReader_ReadBytes_Allocations(t *testing.T) {
+	data := make([]byte, 64)
+	src := bytes.NewReader(data)
+	reader := NewReader(src, binary.LittleEndian)
+	allocs := testing.AllocsPerRun(100, func() {
+		src.Reset(data)
+		if _, err := reader.ReadBytes(len(data)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Equal(t, 1.0, allocs)
+
+	failing := errors.New("device error")
+	_, err := NewReader(iotest.ErrReader(failing), binary.LittleEndian).ReadBytes(8)
+	require.ErrorIs(t, err, failing)
+	assert.Contains(t, err.Error(), "failed to read 8 bytes")
+}
+
 // TestReader_ReadString tests reading string data.
 func TestReader_ReadString(t *testing.T) {
 	testCases := []struct {
@@ -299,3 +337,96 @@ func TestReader_EmptyReader(t *testing.T) {
 	assert.Error(t, err)
 	assert.Empty(t, str)
 }
+
+// TestReader_Remaining tests remaining-size tracking for sized and unsized streams.
+func TestReader_Remaining(t *testing.T) {
+	src := bytes.NewReader([]byte{1, 2, 3, 4, 5, 6})
+	reader := NewReader(src, binary.LittleEndian)
+	reader.size = streamSize(src)
+
+	remaining, ok := reader.Remaining()
+	require.True(t, ok)
+	assert.Equal(t, int64(6), remaining)
+
+	_, err := reader.ReadUint32()
+	require.NoError(t, err)
+	remaining, ok = reader.Remaining()
+	require.True(t, ok)
+	assert.Equal(t, int64(2), remaining)
+
+	// Wrapping (e.g. for deflate) makes the size unknown
+	reader.WrapReader(io.MultiReader(src))
+	_, ok = reader.Remaining()
+	assert.False(t, ok)
+
+	// A plain io.Reader has no known size
+	assert.Equal(t, int64(-1), streamSize(io.MultiReader(src)))
+}
+
+// TestReader_ReadUint8AndUint64 tests single-byte and 64-bit reads in both byte orders.
+func TestReader_ReadUint8AndUint64(t *testing.T) {
+	data := []byte{0x04, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+	le := NewReader(bytes.NewReader(data), binary.LittleEndian)
+	b, err := le.ReadUint8()
+	require.NoError(t, err)
+	assert.Equal(t, uint8(0x04), b)
+	v, err := le.ReadUint64()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0x0807060504030201), v)
+	assert.Equal(t, int64(9), le.Position())
+
+	_, err = le.ReadUint8()
+	assert.Equal(t, io.EOF, err)
+
+	be := NewReader(bytes.NewReader(data[1:]), binary.BigEndian)
+	v, err = be.ReadUint64()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0x0102030405060708), v)
+
+	short := NewReader(bytes.NewReader(data[:3]), binary.LittleEndian)
+	_, err = short.ReadUint64()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+// countingReader counts the Read calls made on the wrapped reader.
+type countingReader struct {
+	r     io.Reader
+	reads int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.reads++
+	return c.r.Read(p)
+}
+
+// TestNewReaderSize tests that buffering batches underlying reads while Position
+// counts only consumed bytes.
+func TestNewReaderSize(t *testing.T) {
+	data := make([]byte, 64)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	unbuffered := &countingReader{r: bytes.NewReader(data)}
+	r := NewReaderSize(unbuffered, binary.LittleEndian, 0)
+	for range 16 {
+		_, err := r.ReadUint16()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 16, unbuffered.reads)
+
+	buffered := &countingReader{r: bytes.NewReader(data)}
+	r = NewReaderSize(buffered, binary.LittleEndian, 4096)
+	for range 16 {
+		_, err := r.ReadUint16()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, buffered.reads)
+	assert.Equal(t, int64(32), r.Position())
+
+	rest, err := r.ReadBytes(32)
+	require.NoError(t, err)
+	assert.Equal(t, data[32:], rest)
+	assert.Equal(t, int64(64), r.Position())
+}
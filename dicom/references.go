@@ -0,0 +1,248 @@
+package dicom
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// SOPReference is a reference to a composite instance, as held by the items of the
+// Referenced SOP Sequence (0008,1199), Referenced Image Sequence (0008,1140), Source
+// Image Sequence (0008,2112), Referenced Instance Sequence (0008,114A) and the many
+// other sequences that share the SOP Instance Reference Macro.
+type SOPReference struct {
+	SOPClassUID    string // (0008,1150) Referenced SOP Class UID
+	SOPInstanceUID string // (0008,1155) Referenced SOP Instance UID
+	Frames         []int  // (0008,1160) Referenced Frame Number; nil references every frame
+}
+
+// ReferencedFrames returns the frame numbers listed in Referenced Frame Number
+// (0008,1160) of a referenced image item, such as an item of the Referenced Image
+// Sequence (0008,1140) or Referenced SOP Sequence (0008,1199) in a Key Object
+// Selection or Structured Report document.
+//
+// Frame numbers are 1-based, as in the standard, and returned in the order listed.
+// A nil slice with a nil error means the attribute is absent or empty, which by
+// convention references every frame of the image.
+//
+// Returns an error if item is nil or a frame number is not a positive integer.
+//
+// Example:
+//
+//	refs, err := content.GetSequenceItems(tag.ReferencedSOPSequence)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, ref := range refs {
+//	    frames, err := dicom.ReferencedFrames(ref)
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    if frames == nil {
+//	        fmt.Println("all frames")
+//	    } else {
+//	        fmt.Println("frames", frames) // e.g. [5]
+//	    }
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_10.3
+func ReferencedFrames(item *DataSet) ([]int, error) {
+	if item == nil {
+		return nil, fmt.Errorf("referenced image item is nil")
+	}
+	if stringValue(item, tag.ReferencedFrameNumber) == "" {
+		return nil, nil
+	}
+
+	values, err := item.GetInts(tag.ReferencedFrameNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Referenced Frame Number: %w", err)
+	}
+
+	frames := make([]int, len(values))
+	for i, v := range values {
+		if v < 1 {
+			return nil, fmt.Errorf("invalid Referenced Frame Number %d at index %d: frame numbers start at 1", v, i)
+		}
+		frames[i] = int(v)
+	}
+	return frames, nil
+}
+
+// ReferenceParseOptions relaxes the checks of ParseReferencedSOPItemsWithOptions, for
+// readers that only need part of each reference or must tolerate imperfect files.
+type ReferenceParseOptions struct {
+	// AllowMissingUIDs keeps items lacking Referenced SOP Class UID or Referenced
+	// SOP Instance UID, with that UID empty, instead of failing.
+	AllowMissingUIDs bool
+
+	// IgnoreInvalidFrames leaves Frames nil for items whose Referenced Frame
+	// Number is invalid, instead of failing.
+	IgnoreInvalidFrames bool
+}
+
+// ParseReferencedSOPItems reads the Referenced SOP Class UID and Referenced SOP
+// Instance UID, and any Referenced Frame Number, of every item of a reference
+// sequence. Other attributes of the items, such as the Purpose of Reference Code
+// Sequence, are ignored.
+//
+// Returns an error if seq is nil, or an item is not a dataset, lacks either UID or
+// has an invalid frame number. Use ParseReferencedSOPItemsWithOptions to tolerate
+// incomplete items.
+//
+// Example:
+//
+//	elem, err := ds.Get(tag.SourceImageSequence)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	seq, _ := elem.Value().(*value.SequenceValue)
+//	refs, err := dicom.ParseReferencedSOPItems(seq)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, ref := range refs {
+//	    fmt.Println(ref.SOPInstanceUID, ref.Frames)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#table_10-11
+func ParseReferencedSOPItems(seq *value.SequenceValue) ([]SOPReference, error) {
+	return ParseReferencedSOPItemsWithOptions(seq, ReferenceParseOptions{})
+}
+
+// ParseReferencedSOPItemsWithOptions is ParseReferencedSOPItems with checks relaxed
+// by opts.
+//
+// Returns an error if seq is nil or an item is not a dataset, and otherwise for the
+// problems opts does not allow.
+//
+// Example:
+//
+//	// Keep every reference of a Structured Report, however incomplete
+//	refs, err := dicom.ParseReferencedSOPItemsWithOptions(seq, dicom.ReferenceParseOptions{
+//	    AllowMissingUIDs:    true,
+//	    IgnoreInvalidFrames: true,
+//	})
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#table_10-11
+func ParseReferencedSOPItemsWithOptions(seq *value.SequenceValue, opts ReferenceParseOptions) ([]SOPReference, error) {
+	items, err := SequenceItems(seq)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]SOPReference, 0, len(items))
+	for i, item := range items {
+		ref := SOPReference{
+			SOPClassUID:    stringValue(item, tag.ReferencedSOPClassUID),
+			SOPInstanceUID: stringValue(item, tag.ReferencedSOPInstanceUID),
+		}
+		if ref.SOPClassUID == "" && !opts.AllowMissingUIDs {
+			return nil, fmt.Errorf("item %d has no Referenced SOP Class UID", i)
+		}
+		if ref.SOPInstanceUID == "" && !opts.AllowMissingUIDs {
+			return nil, fmt.Errorf("item %d has no Referenced SOP Instance UID", i)
+		}
+		if ref.Frames, err = ReferencedFrames(item); err != nil {
+			if !opts.IgnoreInvalidFrames {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			ref.Frames = nil
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// GetSOPReferences reads the reference sequence t of ds with
+// ParseReferencedSOPItemsWithOptions.
+//
+// Returns an error if t is absent or not a sequence, or if its items cannot be read
+// under opts.
+//
+// Example:
+//
+//	refs, err := ds.GetSOPReferences(tag.ReferencedImageSequence, dicom.ReferenceParseOptions{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#table_10-11
+func (ds *DataSet) GetSOPReferences(t tag.Tag, opts ReferenceParseOptions) ([]SOPReference, error) {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return nil, err
+	}
+	seq, ok := elem.Value().(*value.SequenceValue)
+	if !ok {
+		return nil, fmt.Errorf("element %s is not a sequence (VR %s)", t, elem.VR())
+	}
+	refs, err := ParseReferencedSOPItemsWithOptions(seq, opts)
+	if err != nil {
+		return nil, fmt.Errorf("sequence %s: %w", t, err)
+	}
+	return refs, nil
+}
+
+// BuildReferencedSOPSequence returns a sequence value with one item per reference,
+// holding Referenced SOP Class UID, Referenced SOP Instance UID and, if Frames is
+// set, Referenced Frame Number. Wrap it in an element of whichever reference
+// sequence tag is needed.
+//
+// Returns an error if a reference lacks either UID, or has an invalid UID or frame
+// number.
+//
+// Example:
+//
+//	seq, err := dicom.BuildReferencedSOPSequence([]dicom.SOPReference{
+//	    {SOPClassUID: uid.CTImageStorage.String(), SOPInstanceUID: "1.2.3.4"},
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	elem, err := element.NewElement(tag.ReferencedImageSequence, vr.SequenceOfItems, seq)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#table_10-11
+func BuildReferencedSOPSequence(refs []SOPReference) (*value.SequenceValue, error) {
+	items := make([]value.Item, len(refs))
+	for i, ref := range refs {
+		if ref.SOPClassUID == "" || ref.SOPInstanceUID == "" {
+			return nil, fmt.Errorf("reference %d must have both SOP Class UID and SOP Instance UID", i)
+		}
+
+		item := NewDataSet()
+		if err := setString(item, tag.ReferencedSOPClassUID, vr.UniqueIdentifier, ref.SOPClassUID); err != nil {
+			return nil, fmt.Errorf("reference %d: %w", i, err)
+		}
+		if err := setString(item, tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier, ref.SOPInstanceUID); err != nil {
+			return nil, fmt.Errorf("reference %d: %w", i, err)
+		}
+		if len(ref.Frames) > 0 {
+			frames := make([]string, len(ref.Frames))
+			for j, f := range ref.Frames {
+				if f < 1 {
+					return nil, fmt.Errorf("reference %d: invalid frame number %d: frame numbers start at 1", i, f)
+				}
+				frames[j] = strconv.Itoa(f)
+			}
+			if err := setStrings(item, tag.ReferencedFrameNumber, vr.IntegerString, frames); err != nil {
+				return nil, fmt.Errorf("reference %d: %w", i, err)
+			}
+		}
+		items[i] = item
+	}
+
+	seq, err := value.NewSequenceValue(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sequence value: %w", err)
+	}
+	return seq, nil
+}
@@ -0,0 +1,176 @@
+package dicom_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReferencedImageItem returns a Referenced SOP Sequence item, listing frames in
+// Referenced Frame Number unless frames is empty.
+func newReferencedImageItem(t *testing.T, frames ...string) *dicom.DataSet {
+	t.Helper()
+	item := dicom.NewDataSet()
+	require.NoError(t, item.Set(mustNewElement(tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier,
+		mustNewStringValue(vr.UniqueIdentifier, []string{"1.2.3.4.5.6"}))))
+	if len(frames) > 0 {
+		require.NoError(t, item.Set(mustNewElement(tag.ReferencedFrameNumber, vr.IntegerString,
+			mustNewStringValue(vr.IntegerString, frames))))
+	}
+	return item
+}
+
+func TestReferencedFrames(t *testing.T) {
+	// Key Object Selection content item referencing frame 5 of one image, frames
+	// 1, 3 and 7 of another and all frames of a third
+	refs, err := dicom.NewSequenceElement(tag.ReferencedSOPSequence, []*dicom.DataSet{
+		newReferencedImageItem(t, "5"),
+		newReferencedImageItem(t, "1", " 3", "+7 "),
+		newReferencedImageItem(t),
+	})
+	require.NoError(t, err)
+	content := dicom.NewDataSet()
+	require.NoError(t, content.Set(refs))
+
+	items, err := content.GetSequenceItems(tag.ReferencedSOPSequence)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	want := [][]int{{5}, {1, 3, 7}, nil}
+	for i, item := range items {
+		frames, err := dicom.ReferencedFrames(item)
+		require.NoError(t, err)
+		assert.Equal(t, want[i], frames, "item %d", i)
+	}
+}
+
+func TestReferencedFrames_Errors(t *testing.T) {
+	for _, frames := range [][]string{{"0"}, {"2", "-1"}, {"first"}} {
+		_, err := dicom.ReferencedFrames(newReferencedImageItem(t, frames...))
+		assert.Error(t, err, "frames %v", frames)
+	}
+
+	_, err := dicom.ReferencedFrames(nil)
+	assert.Error(t, err)
+}
+
+func TestReferencedSOPSequence_RoundTrip(t *testing.T) {
+	refs := []dicom.SOPReference{
+		{SOPClassUID: uid.CTImageStorage.String(), SOPInstanceUID: "1.2.3.4.1"},
+		{SOPClassUID: uid.EnhancedCTImageStorage.String(), SOPInstanceUID: "1.2.3.4.2", Frames: []int{2, 4}},
+	}
+
+	seq, err := dicom.BuildReferencedSOPSequence(refs)
+	require.NoError(t, err)
+	require.Equal(t, 2, seq.Len())
+
+	// The same items serve any reference sequence, here Source Image Sequence
+	elem, err := element.NewElement(tag.SourceImageSequence, vr.SequenceOfItems, seq)
+	require.NoError(t, err)
+	ds, err := newSecondaryCaptureBuilder().Build()
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+
+	path := filepath.Join(t.TempDir(), "refs.dcm")
+	require.NoError(t, dicom.WriteFile(path, ds))
+	parsed, err := dicom.ParseFile(path)
+	require.NoError(t, err)
+
+	elem, err = parsed.Get(tag.SourceImageSequence)
+	require.NoError(t, err)
+	got, err := dicom.ParseReferencedSOPItems(elem.Value().(*value.SequenceValue))
+	require.NoError(t, err)
+	assert.Equal(t, refs, got)
+}
+
+func TestParseReferencedSOPItems_Errors(t *testing.T) {
+	_, err := dicom.ParseReferencedSOPItems(nil)
+	assert.Error(t, err)
+
+	parse := func(items ...*dicom.DataSet) error {
+		elem, err := dicom.NewSequenceElement(tag.ReferencedImageSequence, items)
+		require.NoError(t, err)
+		_, err = dicom.ParseReferencedSOPItems(elem.Value().(*value.SequenceValue))
+		return err
+	}
+
+	// newReferencedImageItem has no SOP Class UID
+	assert.ErrorContains(t, parse(newReferencedImageItem(t)), "item 0 has no Referenced SOP Class UID")
+
+	withClass := func(frames ...string) *dicom.DataSet {
+		item := newReferencedImageItem(t, frames...)
+		require.NoError(t, item.Set(mustNewElement(tag.ReferencedSOPClassUID, vr.UniqueIdentifier,
+			mustNewStringValue(vr.UniqueIdentifier, []string{uid.CTImageStorage.String()}))))
+		return item
+	}
+	assert.NoError(t, parse(withClass()))
+	assert.ErrorContains(t, parse(withClass(), withClass("0")), "item 1")
+
+	noInstance := withClass()
+	require.NoError(t, noInstance.Remove(tag.ReferencedSOPInstanceUID))
+	assert.ErrorContains(t, parse(noInstance), "no Referenced SOP Instance UID")
+}
+
+func TestParseReferencedSOPItemsWithOptions(t *testing.T) {
+	elem, err := dicom.NewSequenceElement(tag.ReferencedImageSequence, []*dicom.DataSet{
+		newReferencedImageItem(t, "2"),
+		newReferencedImageItem(t, "0"),
+	})
+	require.NoError(t, err)
+	seq := elem.Value().(*value.SequenceValue)
+
+	_, err = dicom.ParseReferencedSOPItemsWithOptions(seq, dicom.ReferenceParseOptions{AllowMissingUIDs: true})
+	assert.ErrorContains(t, err, "item 1", "invalid frames still fail")
+
+	refs, err := dicom.ParseReferencedSOPItemsWithOptions(seq, dicom.ReferenceParseOptions{
+		AllowMissingUIDs:    true,
+		IgnoreInvalidFrames: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []dicom.SOPReference{
+		{SOPInstanceUID: "1.2.3.4.5.6", Frames: []int{2}},
+		{SOPInstanceUID: "1.2.3.4.5.6"},
+	}, refs)
+
+	ds := dicom.NewDataSet()
+	require.NoError(t, ds.Add(elem))
+	got, err := ds.GetSOPReferences(tag.ReferencedImageSequence, dicom.ReferenceParseOptions{
+		AllowMissingUIDs:    true,
+		IgnoreInvalidFrames: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, refs, got)
+
+	_, err = ds.GetSOPReferences(tag.ReferencedImageSequence, dicom.ReferenceParseOptions{})
+	assert.ErrorContains(t, err, "no Referenced SOP Class UID")
+	_, err = ds.GetSOPReferences(tag.SourceImageSequence, dicom.ReferenceParseOptions{})
+	assert.Error(t, err)
+}
+
+func TestBuildReferencedSOPSequence_Errors(t *testing.T) {
+	_, err := dicom.BuildReferencedSOPSequence([]dicom.SOPReference{{SOPInstanceUID: "1.2.3"}})
+	assert.ErrorContains(t, err, "reference 0")
+
+	_, err = dicom.BuildReferencedSOPSequence([]dicom.SOPReference{
+		{SOPClassUID: "1.2.3", SOPInstanceUID: "1.2.3.4", Frames: []int{0}},
+	})
+	assert.ErrorContains(t, err, "invalid frame number 0")
+
+	_, err = dicom.BuildReferencedSOPSequence([]dicom.SOPReference{
+		{SOPClassUID: "1.2.3", SOPInstanceUID: "1." + strings.Repeat("2", 64)},
+	})
+	assert.Error(t, err)
+
+	seq, err := dicom.BuildReferencedSOPSequence(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, seq.Len())
+}
@@ -0,0 +1,28 @@
+// Package rt provides access to DICOM Radiotherapy (RT) objects.
+//
+// # RT Structure Set
+//
+// ParseStructureSet extracts the regions of interest (ROIs) defined in an RT
+// Structure Set, joining the Structure Set ROI Sequence (names, numbers) with the
+// ROI Contour Sequence (display colour and contour geometry):
+//
+//	ds, err := dicom.ParseFile("rtstruct.dcm")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	ss, err := rt.ParseStructureSet(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, roi := range ss.ROIs {
+//	    fmt.Printf("ROI %d %q: %d contours\n", roi.Number, roi.Name, len(roi.Contours))
+//	    for _, polygon := range roi.Polygons() {
+//	        // polygon is a []rt.Point3D in patient coordinates (mm)
+//	    }
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_A.19
+package rt
@@ -0,0 +1,268 @@
+package rt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+)
+
+// Point3D is a point in the patient-based coordinate system, in millimetres.
+type Point3D struct {
+	X, Y, Z float64
+}
+
+// Contour is a single contour of an ROI.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.8.8.6
+type Contour struct {
+	GeometricType string    // (3006,0042) POINT, OPEN_PLANAR, OPEN_NONPLANAR or CLOSED_PLANAR
+	Points        []Point3D // (3006,0050) Contour Data as x/y/z triplets
+}
+
+// ROI is a region of interest from an RT Structure Set.
+type ROI struct {
+	Number                        int       // (3006,0022) ROI Number
+	Name                          string    // (3006,0026) ROI Name
+	ReferencedFrameOfReferenceUID string    // (3006,0024)
+	GenerationAlgorithm           string    // (3006,0036) AUTOMATIC, SEMIAUTOMATIC or MANUAL
+	Color                         [3]uint8  // (3006,002A) ROI Display Color (RGB)
+	HasColor                      bool      // true if ROI Display Color was present
+	Contours                      []Contour // From the matching ROI Contour Sequence item
+}
+
+// Polygons returns the points of each contour of the ROI.
+func (r *ROI) Polygons() [][]Point3D {
+	polygons := make([][]Point3D, len(r.Contours))
+	for i, c := range r.Contours {
+		polygons[i] = c.Points
+	}
+	return polygons
+}
+
+// StructureSet is the parsed content of an RT Structure Set.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.8.8.5
+type StructureSet struct {
+	Label string // (3006,0002) Structure Set Label
+	Name  string // (3006,0004) Structure Set Name
+	ROIs  []ROI  // In Structure Set ROI Sequence order
+}
+
+// ROIByNumber returns the ROI with the given ROI Number, or nil if none matches.
+func (s *StructureSet) ROIByNumber(number int) *ROI {
+	for i := range s.ROIs {
+		if s.ROIs[i].Number == number {
+			return &s.ROIs[i]
+		}
+	}
+	return nil
+}
+
+// ROIByName returns the first ROI whose name matches (case-insensitive), or nil.
+func (s *StructureSet) ROIByName(name string) *ROI {
+	for i := range s.ROIs {
+		if strings.EqualFold(s.ROIs[i].Name, name) {
+			return &s.ROIs[i]
+		}
+	}
+	return nil
+}
+
+// ParseStructureSet extracts the ROIs and their contours from an RT Structure Set.
+//
+// ROIs are taken from the Structure Set ROI Sequence (3006,0020) and joined with the
+// ROI Contour Sequence (3006,0039) via Referenced ROI Number (3006,0084). Contour Data
+// (3006,0050) is read as x/y/z triplets and checked against Number of Contour Points
+// (3006,0046).
+//
+// Returns an error if the dataset is not an RT Structure Set (when SOP Class UID is
+// present), the Structure Set ROI Sequence is missing, or any contour is malformed.
+//
+// Example:
+//
+//	ss, err := rt.ParseStructureSet(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if roi := ss.ROIByName("PTV"); roi != nil {
+//	    fmt.Printf("PTV has %d contours\n", len(roi.Contours))
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_A.19
+func ParseStructureSet(ds *dicom.DataSet) (*StructureSet, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	if sopClass := getString(ds, tag.SOPClassUID); sopClass != "" && sopClass != uid.RTStructureSetStorage.String() {
+		return nil, fmt.Errorf("not an RT Structure Set: SOP Class UID is %s", sopClass)
+	}
+
+	roiItems, err := ds.GetSequenceItems(tag.StructureSetROISequence)
+	if err != nil {
+		return nil, fmt.Errorf("missing Structure Set ROI Sequence: %w", err)
+	}
+
+	ss := &StructureSet{
+		Label: getString(ds, tag.StructureSetLabel),
+		Name:  getString(ds, tag.StructureSetName),
+		ROIs:  make([]ROI, 0, len(roiItems)),
+	}
+
+	index := make(map[int]int, len(roiItems)) // ROI Number -> position in ss.ROIs
+	for i, item := range roiItems {
+		number, err := getInt(item, tag.ROINumber)
+		if err != nil {
+			return nil, fmt.Errorf("structure set ROI item %d: %w", i, err)
+		}
+		index[number] = len(ss.ROIs)
+		ss.ROIs = append(ss.ROIs, ROI{
+			Number:                        number,
+			Name:                          getString(item, tag.ROIName),
+			ReferencedFrameOfReferenceUID: getString(item, tag.ReferencedFrameOfReferenceUID),
+			GenerationAlgorithm:           getString(item, tag.ROIGenerationAlgorithm),
+		})
+	}
+
+	// ROI Contour Sequence is optional (an ROI may have no contours yet)
+	contourItems, err := ds.GetSequenceItems(tag.ROIContourSequence)
+	if err != nil {
+		return ss, nil
+	}
+
+	for i, item := range contourItems {
+		number, err := getInt(item, tag.ReferencedROINumber)
+		if err != nil {
+			return nil, fmt.Errorf("ROI contour item %d: %w", i, err)
+		}
+		pos, ok := index[number]
+		if !ok {
+			return nil, fmt.Errorf("ROI contour item %d references unknown ROI number %d", i, number)
+		}
+		roi := &ss.ROIs[pos]
+
+		if color, ok, err := parseColor(item); err != nil {
+			return nil, fmt.Errorf("ROI %d: %w", number, err)
+		} else if ok {
+			roi.Color = color
+			roi.HasColor = true
+		}
+
+		contours, err := item.GetSequenceItems(tag.ContourSequence)
+		if err != nil {
+			continue
+		}
+		for j, contourItem := range contours {
+			contour, err := parseContour(contourItem)
+			if err != nil {
+				return nil, fmt.Errorf("ROI %d contour %d: %w", number, j, err)
+			}
+			roi.Contours = append(roi.Contours, contour)
+		}
+	}
+
+	return ss, nil
+}
+
+// parseContour reads one Contour Sequence item.
+func parseContour(item *dicom.DataSet) (Contour, error) {
+	contour := Contour{GeometricType: getString(item, tag.ContourGeometricType)}
+
+	elem, err := item.Get(tag.ContourData)
+	if err != nil {
+		return contour, fmt.Errorf("missing Contour Data: %w", err)
+	}
+	strVal, ok := elem.Value().(*value.StringValue)
+	if !ok {
+		return contour, fmt.Errorf("contour data has unexpected value type %T", elem.Value())
+	}
+	coords, err := strVal.AsFloats()
+	if err != nil {
+		return contour, fmt.Errorf("invalid contour data: %w", err)
+	}
+	if len(coords)%3 != 0 {
+		return contour, fmt.Errorf("contour data has %d values, not a multiple of 3", len(coords))
+	}
+
+	contour.Points = make([]Point3D, len(coords)/3)
+	for i := range contour.Points {
+		contour.Points[i] = Point3D{X: coords[i*3], Y: coords[i*3+1], Z: coords[i*3+2]}
+	}
+
+	if item.Contains(tag.NumberOfContourPoints) {
+		n, err := getInt(item, tag.NumberOfContourPoints)
+		if err != nil {
+			return contour, err
+		}
+		if n != len(contour.Points) {
+			return contour, fmt.Errorf("number of contour points is %d but contour data holds %d points", n, len(contour.Points))
+		}
+	}
+
+	return contour, nil
+}
+
+// parseColor reads ROI Display Color (3006,002A) as an RGB triplet.
+func parseColor(item *dicom.DataSet) ([3]uint8, bool, error) {
+	var color [3]uint8
+
+	elem, err := item.Get(tag.ROIDisplayColor)
+	if err != nil {
+		return color, false, nil
+	}
+	strVal, ok := elem.Value().(*value.StringValue)
+	if !ok {
+		return color, false, fmt.Errorf("ROI Display Color has unexpected value type %T", elem.Value())
+	}
+	components, err := strVal.AsInts()
+	if err != nil {
+		return color, false, fmt.Errorf("invalid ROI Display Color: %w", err)
+	}
+	if len(components) != 3 {
+		return color, false, fmt.Errorf("ROI Display Color has %d components, expected 3", len(components))
+	}
+	for i, c := range components {
+		if c < 0 || c > 255 {
+			return color, false, fmt.Errorf("ROI Display Color component %d out of range", c)
+		}
+		color[i] = uint8(c)
+	}
+
+	return color, true, nil
+}
+
+// getString returns the trimmed string value of an element, or "" if absent.
+func getString(ds *dicom.DataSet, t tag.Tag) string {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(elem.Value().String())
+}
+
+// getInt returns the single integer value of an IS element.
+func getInt(ds *dicom.DataSet, t tag.Tag) (int, error) {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return 0, fmt.Errorf("missing %s: %w", t, err)
+	}
+	strVal, ok := elem.Value().(*value.StringValue)
+	if !ok {
+		return 0, fmt.Errorf("element %s has unexpected value type %T", t, elem.Value())
+	}
+	ints, err := strVal.AsInts()
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", t, err)
+	}
+	if len(ints) != 1 {
+		return 0, fmt.Errorf("element %s has %d values, expected 1", t, len(ints))
+	}
+	return int(ints[0]), nil
+}
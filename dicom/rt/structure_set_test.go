@@ -0,0 +1,163 @@
+package rt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addString adds a string element to ds.
+func addString(t *testing.T, ds *dicom.DataSet, tg tag.Tag, v vr.VR, values ...string) {
+	val, err := value.NewStringValue(v, values)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, v, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+// addSequence adds a sequence element to ds.
+func addSequence(t *testing.T, ds *dicom.DataSet, tg tag.Tag, items ...*dicom.DataSet) {
+	elem, err := dicom.NewSequenceElement(tg, items)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+// newContourItem builds a Contour Sequence item.
+func newContourItem(t *testing.T, numPoints string, data ...string) *dicom.DataSet {
+	item := dicom.NewDataSet()
+	addString(t, item, tag.ContourGeometricType, vr.CodeString, "CLOSED_PLANAR")
+	addString(t, item, tag.NumberOfContourPoints, vr.IntegerString, numPoints)
+	addString(t, item, tag.ContourData, vr.DecimalString, data...)
+	return item
+}
+
+// newTestStructureSet builds an RT Structure Set with two ROIs.
+func newTestStructureSet(t *testing.T) *dicom.DataSet {
+	ds := dicom.NewDataSet()
+	addString(t, ds, tag.SOPClassUID, vr.UniqueIdentifier, uid.RTStructureSetStorage.String())
+	addString(t, ds, tag.SOPInstanceUID, vr.UniqueIdentifier, "1.2.826.0.1.3680043.10.1451.1")
+	addString(t, ds, tag.StructureSetLabel, vr.ShortString, "PLAN1")
+
+	// Structure Set ROI Sequence
+	ptv := dicom.NewDataSet()
+	addString(t, ptv, tag.ROINumber, vr.IntegerString, "1")
+	addString(t, ptv, tag.ROIName, vr.LongString, "PTV")
+	addString(t, ptv, tag.ROIGenerationAlgorithm, vr.CodeString, "MANUAL")
+	body := dicom.NewDataSet()
+	addString(t, body, tag.ROINumber, vr.IntegerString, "2")
+	addString(t, body, tag.ROIName, vr.LongString, "Body")
+	addSequence(t, ds, tag.StructureSetROISequence, ptv, body)
+
+	// ROI Contour Sequence (only PTV has contours)
+	ptvContours := dicom.NewDataSet()
+	addString(t, ptvContours, tag.ReferencedROINumber, vr.IntegerString, "1")
+	addString(t, ptvContours, tag.ROIDisplayColor, vr.IntegerString, "255", "0", "128")
+	addSequence(t, ptvContours, tag.ContourSequence,
+		newContourItem(t, "3", "0", "0", "10", "10", "0", "10", "10", "10", "10"),
+		newContourItem(t, "2", "0", "0", "12.5", "-1.5", "2", "12.5"),
+	)
+	addSequence(t, ds, tag.ROIContourSequence, ptvContours)
+
+	return ds
+}
+
+func TestParseStructureSet(t *testing.T) {
+	ss, err := ParseStructureSet(newTestStructureSet(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "PLAN1", ss.Label)
+	require.Len(t, ss.ROIs, 2)
+
+	ptv := ss.ROIByName("ptv")
+	require.NotNil(t, ptv)
+	assert.Equal(t, 1, ptv.Number)
+	assert.Equal(t, "MANUAL", ptv.GenerationAlgorithm)
+	assert.True(t, ptv.HasColor)
+	assert.Equal(t, [3]uint8{255, 0, 128}, ptv.Color)
+
+	polygons := ptv.Polygons()
+	require.Len(t, polygons, 2)
+	assert.Equal(t, []Point3D{{0, 0, 10}, {10, 0, 10}, {10, 10, 10}}, polygons[0])
+	assert.Equal(t, []Point3D{{0, 0, 12.5}, {-1.5, 2, 12.5}}, polygons[1])
+	assert.Equal(t, "CLOSED_PLANAR", ptv.Contours[0].GeometricType)
+
+	bodyROI := ss.ROIByNumber(2)
+	require.NotNil(t, bodyROI)
+	assert.Equal(t, "Body", bodyROI.Name)
+	assert.False(t, bodyROI.HasColor)
+	assert.Empty(t, bodyROI.Contours)
+
+	assert.Nil(t, ss.ROIByNumber(99))
+}
+
+func TestParseStructureSet_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rtstruct.dcm")
+	require.NoError(t, dicom.WriteFile(path, newTestStructureSet(t)))
+
+	ds, err := dicom.ParseFile(path)
+	require.NoError(t, err)
+
+	ss, err := ParseStructureSet(ds)
+	require.NoError(t, err)
+	require.NotNil(t, ss.ROIByName("PTV"))
+	assert.Len(t, ss.ROIByName("PTV").Contours, 2)
+}
+
+func TestParseStructureSet_Errors(t *testing.T) {
+	t.Run("nil dataset", func(t *testing.T) {
+		_, err := ParseStructureSet(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong SOP class", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addString(t, ds, tag.SOPClassUID, vr.UniqueIdentifier, uid.CTImageStorage.String())
+		_, err := ParseStructureSet(ds)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing ROI sequence", func(t *testing.T) {
+		_, err := ParseStructureSet(dicom.NewDataSet())
+		assert.Error(t, err)
+	})
+
+	t.Run("point count mismatch", func(t *testing.T) {
+		ds := newTestStructureSet(t)
+		contours := dicom.NewDataSet()
+		addString(t, contours, tag.ReferencedROINumber, vr.IntegerString, "2")
+		addSequence(t, contours, tag.ContourSequence, newContourItem(t, "5", "0", "0", "0"))
+		addSequence(t, ds, tag.ROIContourSequence, contours)
+
+		_, err := ParseStructureSet(ds)
+		assert.Error(t, err)
+	})
+
+	t.Run("incomplete triplet", func(t *testing.T) {
+		ds := newTestStructureSet(t)
+		contours := dicom.NewDataSet()
+		addString(t, contours, tag.ReferencedROINumber, vr.IntegerString, "2")
+		addSequence(t, contours, tag.ContourSequence, newContourItem(t, "1", "0", "0"))
+		addSequence(t, ds, tag.ROIContourSequence, contours)
+
+		_, err := ParseStructureSet(ds)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown ROI reference", func(t *testing.T) {
+		ds := newTestStructureSet(t)
+		contours := dicom.NewDataSet()
+		addString(t, contours, tag.ReferencedROINumber, vr.IntegerString, "7")
+		addSequence(t, ds, tag.ROIContourSequence, contours)
+
+		_, err := ParseStructureSet(ds)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,369 @@
+package dicom
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"  //nolint:gosec // MD5 is a MAC Algorithm defined term, offered for interoperability
+	"crypto/sha1" //nolint:gosec // SHA1 is a MAC Algorithm defined term, offered for interoperability
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"slices"
+	"time"
+
+	"github.com/codeninja55/go-radx/dicom/datetime"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// Defined Terms for MAC Algorithm (0400,0015) supported by SignDataset.
+const (
+	MACAlgorithmMD5    = "MD5"
+	MACAlgorithmSHA1   = "SHA1"
+	MACAlgorithmSHA256 = "SHA256"
+	MACAlgorithmSHA384 = "SHA384"
+	MACAlgorithmSHA512 = "SHA512"
+)
+
+// SignOptions configures SignDataset.
+type SignOptions struct {
+	// Key is the secret key of the HMAC. The same key is needed to verify the
+	// signature with VerifyDatasetSignatures. Required.
+	Key []byte
+
+	// Algorithm is the hash function of the HMAC, one of the MACAlgorithm constants.
+	// Default: MACAlgorithmSHA256
+	Algorithm string
+
+	// Tags lists the top-level data elements to sign. Each must be present in the
+	// dataset.
+	// Default: every top-level element except the File Meta Information, group
+	// lengths, the MAC Parameters (4FFE,0001) and Digital Signatures (FFFA,FFFA)
+	// Sequences and Data Set Trailing Padding (FFFC,FFFC)
+	Tags []tag.Tag
+
+	// Time is recorded as the Digital Signature DateTime (0400,0105).
+	// Default: the current time
+	Time time.Time
+}
+
+// SignDataset computes a MAC over data elements of ds and stores it as a new digital
+// signature, so later changes to those elements can be detected with
+// VerifyDatasetSignatures.
+//
+// Following the Digital Signatures mechanism of PS3.15, the elements are encoded in
+// ascending tag order with the MAC Calculation Transfer Syntax, Explicit VR Little
+// Endian, followed by the attributes of the new MAC Parameters item and the MAC ID
+// Number, Digital Signature UID and Digital Signature DateTime of the new signature,
+// so that they cannot be altered either. A MAC Parameters Sequence (4FFE,0001) item
+// records the algorithm and the Data Elements Signed (0400,0020), and a Digital
+// Signatures Sequence (FFFA,FFFA) item holds the MAC in Signature (0400,0120). Earlier
+// signatures are kept, each with its own MAC ID Number (0400,0005).
+//
+// This is a simplified, shared-secret scheme: the MAC is an HMAC keyed with
+// opts.Key rather than a signature with a private key, and no certificate is stored,
+// so other toolkits cannot verify it. It detects tampering by anyone without the
+// key; it does not prove who signed.
+//
+// The values are hashed as the writer encodes them, including the raw bytes retained
+// by ParseOptions.RetainRawValues. Parse signed files with that option to verify
+// values whose padding is not the canonical one.
+// Write signed datasets with an Explicit VR transfer syntax: an Implicit VR file does
+// not record VRs, so elements such as private attributes are read back as UN and no
+// longer match.
+//
+// Returns an error if:
+//   - ds is nil or opts.Key is empty
+//   - opts.Algorithm is not supported
+//   - an element of opts.Tags is absent, or there is nothing to sign
+//
+// Example:
+//
+//	err := dicom.SignDataset(ds, dicom.SignOptions{Key: secret})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = dicom.WriteFile("signed.dcm", ds)
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part15.html#sect_C.1
+func SignDataset(ds *DataSet, opts SignOptions) error {
+	if ds == nil {
+		return fmt.Errorf("dataset is nil")
+	}
+	if len(opts.Key) == 0 {
+		return fmt.Errorf("signing key is required")
+	}
+	if opts.Algorithm == "" {
+		opts.Algorithm = MACAlgorithmSHA256
+	}
+	newHash, err := macHash(opts.Algorithm)
+	if err != nil {
+		return err
+	}
+	if opts.Time.IsZero() {
+		opts.Time = time.Now()
+	}
+
+	signedTags := slices.Clone(opts.Tags)
+	if signedTags == nil {
+		for _, t := range ds.Tags() {
+			if isSignableTag(t) {
+				signedTags = append(signedTags, t)
+			}
+		}
+	}
+	if len(signedTags) == 0 {
+		return fmt.Errorf("no data elements to sign")
+	}
+	slices.SortFunc(signedTags, tag.Tag.Compare)
+	signedTags = slices.Compact(signedTags)
+	for _, t := range signedTags {
+		if !ds.Contains(t) {
+			return fmt.Errorf("data element %s to sign is not in the dataset", t)
+		}
+	}
+
+	macParams, err := ds.GetSequenceItems(tag.MACParametersSequence)
+	if err != nil {
+		macParams = nil
+	}
+	signatures, err := ds.GetSequenceItems(tag.DigitalSignaturesSequence)
+	if err != nil {
+		signatures = nil
+	}
+	macID := nextMACIDNumber(macParams)
+
+	macItem := NewDataSet()
+	signatureItem := NewDataSet()
+	for _, attr := range []struct {
+		item *DataSet
+		t    tag.Tag
+		v    vr.VR
+		val  func() (value.Value, error)
+	}{
+		{macItem, tag.MACIDNumber, vr.UnsignedShort, func() (value.Value, error) {
+			return value.NewIntValue(vr.UnsignedShort, []int64{macID})
+		}},
+		{macItem, tag.MACCalculationTransferSyntaxUID, vr.UniqueIdentifier, func() (value.Value, error) {
+			return value.NewStringValue(vr.UniqueIdentifier, []string{uid.ExplicitVRLittleEndian.String()})
+		}},
+		{macItem, tag.MACAlgorithm, vr.CodeString, func() (value.Value, error) {
+			return value.NewStringValue(vr.CodeString, []string{opts.Algorithm})
+		}},
+		{macItem, tag.DataElementsSigned, vr.AttributeTag, func() (value.Value, error) {
+			return value.NewTagValue(signedTags), nil
+		}},
+		{signatureItem, tag.MACIDNumber, vr.UnsignedShort, func() (value.Value, error) {
+			return value.NewIntValue(vr.UnsignedShort, []int64{macID})
+		}},
+		{signatureItem, tag.DigitalSignatureUID, vr.UniqueIdentifier, func() (value.Value, error) {
+			return value.NewStringValue(vr.UniqueIdentifier, []string{uid.Generate()})
+		}},
+		{signatureItem, tag.DigitalSignatureDateTime, vr.DateTime, func() (value.Value, error) {
+			dt := datetime.DateTime{Time: opts.Time, Precision: datetime.PrecisionMS6}
+			return value.NewStringValue(vr.DateTime, []string{dt.DCM()})
+		}},
+	} {
+		val, err := attr.val()
+		if err != nil {
+			return fmt.Errorf("failed to create value for %s: %w", attr.t, err)
+		}
+		if err := setSignatureElement(attr.item, attr.t, attr.v, val); err != nil {
+			return err
+		}
+	}
+
+	mac, err := computeMAC(ds, macItem, signatureItem, newHash, opts.Key)
+	if err != nil {
+		return err
+	}
+	macValue, err := value.NewBytesValue(vr.OtherByte, mac)
+	if err != nil {
+		return fmt.Errorf("failed to create signature value: %w", err)
+	}
+	if err := setSignatureElement(signatureItem, tag.Signature, vr.OtherByte, macValue); err != nil {
+		return err
+	}
+
+	macSeq, err := NewSequenceElement(tag.MACParametersSequence, append(slices.Clip(macParams), macItem))
+	if err != nil {
+		return err
+	}
+	signatureSeq, err := NewSequenceElement(tag.DigitalSignaturesSequence, append(slices.Clip(signatures), signatureItem))
+	if err != nil {
+		return err
+	}
+	if err := ds.Set(macSeq); err != nil {
+		return err
+	}
+	return ds.Set(signatureSeq)
+}
+
+// VerifyDatasetSignatures checks every digital signature created by SignDataset in
+// ds against key, recomputing each MAC from the current values of its Data Elements
+// Signed.
+//
+// Returns an error if:
+//   - ds is nil or has no Digital Signatures Sequence (FFFA,FFFA)
+//   - a signature has no matching MAC Parameters item or uses an unsupported
+//     algorithm
+//   - a signed element has been removed, or a MAC does not match
+//     (ErrInvalidSignature)
+//
+// Example:
+//
+//	ds, err := dicom.ParseFileWithOptions("signed.dcm", dicom.ParseOptions{RetainRawValues: true})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if err := dicom.VerifyDatasetSignatures(ds, secret); errors.Is(err, dicom.ErrInvalidSignature) {
+//	    log.Fatal("dataset has been modified since it was signed")
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part15.html#sect_C.1
+func VerifyDatasetSignatures(ds *DataSet, key []byte) error {
+	if ds == nil {
+		return fmt.Errorf("dataset is nil")
+	}
+	signatures, err := ds.GetSequenceItems(tag.DigitalSignaturesSequence)
+	if err != nil || len(signatures) == 0 {
+		return fmt.Errorf("dataset has no Digital Signatures Sequence")
+	}
+	macParams, err := ds.GetSequenceItems(tag.MACParametersSequence)
+	if err != nil {
+		return fmt.Errorf("dataset has no MAC Parameters Sequence: %w", err)
+	}
+
+	for i, signatureItem := range signatures {
+		ids, err := signatureItem.GetInts(tag.MACIDNumber)
+		if err != nil || len(ids) != 1 {
+			return fmt.Errorf("digital signature %d has no MAC ID Number", i+1)
+		}
+		macItem := findMACParameters(macParams, ids[0])
+		if macItem == nil {
+			return fmt.Errorf("digital signature %d: no MAC Parameters item with MAC ID Number %d", i+1, ids[0])
+		}
+		algorithm := stringValue(macItem, tag.MACAlgorithm)
+		newHash, err := macHash(algorithm)
+		if err != nil {
+			return fmt.Errorf("digital signature %d: %w", i+1, err)
+		}
+		signatureElem, err := signatureItem.Get(tag.Signature)
+		if err != nil {
+			return fmt.Errorf("digital signature %d has no Signature: %w", i+1, err)
+		}
+
+		unsigned := signatureItem.Copy()
+		_ = unsigned.Remove(tag.Signature)
+		mac, err := computeMAC(ds, macItem, unsigned, newHash, key)
+		if err != nil {
+			return fmt.Errorf("digital signature %d: %w", i+1, err)
+		}
+		if !hmac.Equal(mac, signatureElem.Value().Bytes()) {
+			return fmt.Errorf("%w: digital signature %d (MAC ID Number %d) does not match", ErrInvalidSignature, i+1, ids[0])
+		}
+	}
+	return nil
+}
+
+// computeMAC returns the HMAC of the Data Elements Signed listed in macItem, followed
+// by the elements of macItem and signatureItem, all encoded as Explicit VR Little
+// Endian.
+func computeMAC(ds, macItem, signatureItem *DataSet, newHash func() hash.Hash, key []byte) ([]byte, error) {
+	signedElem, err := macItem.Get(tag.DataElementsSigned)
+	if err != nil {
+		return nil, fmt.Errorf("MAC Parameters item has no Data Elements Signed: %w", err)
+	}
+	tagValue, ok := signedElem.Value().(*value.IntValue)
+	if !ok {
+		return nil, fmt.Errorf("invalid Data Elements Signed: unexpected VR %s", signedElem.VR())
+	}
+	signedTags, err := tagValue.AsTags()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Data Elements Signed: %w", err)
+	}
+
+	explicitLE := uid.ExplicitVRLittleEndian
+	enc := datasetEncoding(ds, &explicitLE)
+	buf := new(bytes.Buffer)
+	for _, t := range signedTags {
+		elem, err := ds.Get(t)
+		if err != nil {
+			return nil, fmt.Errorf("%w: signed element %s is missing", ErrInvalidSignature, t)
+		}
+		if err := encodeElement(buf, elem, enc); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", t, err)
+		}
+	}
+	for _, item := range []*DataSet{macItem, signatureItem} {
+		for _, elem := range item.Elements() {
+			if err := encodeElement(buf, elem, enc); err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", elem.Tag(), err)
+			}
+		}
+	}
+
+	mac := hmac.New(newHash, key)
+	mac.Write(buf.Bytes())
+	return mac.Sum(nil), nil
+}
+
+// macHash returns the hash function of a MAC Algorithm (0400,0015) defined term.
+func macHash(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case MACAlgorithmMD5:
+		return md5.New, nil
+	case MACAlgorithmSHA1:
+		return sha1.New, nil
+	case MACAlgorithmSHA256:
+		return sha256.New, nil
+	case MACAlgorithmSHA384:
+		return sha512.New384, nil
+	case MACAlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported MAC algorithm %q", algorithm)
+	}
+}
+
+// isSignableTag reports whether SignDataset signs t by default.
+func isSignableTag(t tag.Tag) bool {
+	return t.Group != 0x0002 && !isGroupLengthTag(t) &&
+		t != tag.MACParametersSequence && t != tag.DigitalSignaturesSequence && t != tag.DataSetTrailingPadding
+}
+
+// nextMACIDNumber returns a MAC ID Number not used by any of items.
+func nextMACIDNumber(items []*DataSet) int64 {
+	next := int64(1)
+	for _, item := range items {
+		if ids, err := item.GetInts(tag.MACIDNumber); err == nil && len(ids) > 0 && ids[0] >= next {
+			next = ids[0] + 1
+		}
+	}
+	return next
+}
+
+// findMACParameters returns the MAC Parameters item with MAC ID Number id, or nil.
+func findMACParameters(items []*DataSet, id int64) *DataSet {
+	for _, item := range items {
+		if ids, err := item.GetInts(tag.MACIDNumber); err == nil && len(ids) == 1 && ids[0] == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// setSignatureElement sets element t of VR v with val in item.
+func setSignatureElement(item *DataSet, t tag.Tag, v vr.VR, val value.Value) error {
+	elem, err := element.NewElement(t, v, val)
+	if err != nil {
+		return fmt.Errorf("failed to create element %s: %w", t, err)
+	}
+	return item.Set(elem)
+}
@@ -0,0 +1,130 @@
+package dicom_test
+
+import (
+	"testing"
+	"time"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var signatureKey = []byte("chain-of-custody-key")
+
+func TestSignDataset_RoundTrip(t *testing.T) {
+	ds := newTranscodeDataSet(t)
+	signedAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	require.NoError(t, dicom.SignDataset(ds, dicom.SignOptions{Key: signatureKey, Time: signedAt}))
+	require.NoError(t, dicom.VerifyDatasetSignatures(ds, signatureKey))
+
+	macParams, err := ds.GetSequenceItems(tag.MACParametersSequence)
+	require.NoError(t, err)
+	require.Len(t, macParams, 1)
+	assert.Equal(t, dicom.MACAlgorithmSHA256, stringOf(t, macParams[0], tag.MACAlgorithm))
+	assert.Equal(t, uid.ExplicitVRLittleEndian.String(), stringOf(t, macParams[0], tag.MACCalculationTransferSyntaxUID))
+
+	signatures, err := ds.GetSequenceItems(tag.DigitalSignaturesSequence)
+	require.NoError(t, err)
+	require.Len(t, signatures, 1)
+	assert.Equal(t, "20240301093000.000000+0000", stringOf(t, signatures[0], tag.DigitalSignatureDateTime))
+	assert.Len(t, bytesOf(t, signatures[0], tag.Signature), 32, "HMAC-SHA256")
+
+	for _, ts := range []uid.UID{uid.ExplicitVRLittleEndian, uid.ExplicitVRBigEndian} {
+		for _, retain := range []bool{false, true} {
+			parsed, _ := writeAndParse(t, ds, ts, dicom.ParseOptions{RetainRawValues: retain})
+			assert.NoError(t, dicom.VerifyDatasetSignatures(parsed, signatureKey),
+				"written as %s, raw values retained: %v", ts, retain)
+		}
+	}
+}
+
+func TestVerifyDatasetSignatures_DetectsTampering(t *testing.T) {
+	ds := newTranscodeDataSet(t)
+	require.NoError(t, dicom.SignDataset(ds, dicom.SignOptions{Key: signatureKey}))
+	signed, _ := writeAndParse(t, ds, uid.ExplicitVRLittleEndian, dicom.ParseOptions{RetainRawValues: true})
+
+	t.Run("wrong key", func(t *testing.T) {
+		assert.ErrorIs(t, dicom.VerifyDatasetSignatures(signed, []byte("other key")), dicom.ErrInvalidSignature)
+	})
+
+	t.Run("changed value", func(t *testing.T) {
+		tampered := signed.Copy()
+		require.NoError(t, tampered.Set(mustNewElement(tag.PatientName, vr.PersonName,
+			mustNewStringValue(vr.PersonName, []string{"Doe^Jane"}))))
+		assert.ErrorIs(t, dicom.VerifyDatasetSignatures(tampered, signatureKey), dicom.ErrInvalidSignature)
+	})
+
+	t.Run("removed element", func(t *testing.T) {
+		tampered := signed.Copy()
+		require.NoError(t, tampered.Remove(tag.PatientID))
+		assert.ErrorIs(t, dicom.VerifyDatasetSignatures(tampered, signatureKey), dicom.ErrInvalidSignature)
+	})
+
+	t.Run("changed signature datetime", func(t *testing.T) {
+		tampered := signed.Copy()
+		signatures, err := tampered.GetSequenceItems(tag.DigitalSignaturesSequence)
+		require.NoError(t, err)
+		item := signatures[0].Copy()
+		require.NoError(t, item.Set(mustNewElement(tag.DigitalSignatureDateTime, vr.DateTime,
+			mustNewStringValue(vr.DateTime, []string{"20000101000000.000000+0000"}))))
+		seq, err := dicom.NewSequenceElement(tag.DigitalSignaturesSequence, []*dicom.DataSet{item})
+		require.NoError(t, err)
+		require.NoError(t, tampered.Set(seq))
+		assert.ErrorIs(t, dicom.VerifyDatasetSignatures(tampered, signatureKey), dicom.ErrInvalidSignature)
+	})
+
+	t.Run("unsigned element", func(t *testing.T) {
+		// Elements added after signing are not covered
+		extended := signed.Copy()
+		require.NoError(t, extended.Set(mustNewElement(tag.StudyDescription, vr.LongString,
+			mustNewStringValue(vr.LongString, []string{"ADDED LATER"}))))
+		assert.NoError(t, dicom.VerifyDatasetSignatures(extended, signatureKey))
+	})
+}
+
+func TestSignDataset_MultipleSignatures(t *testing.T) {
+	ds := newTranscodeDataSet(t)
+	require.NoError(t, dicom.SignDataset(ds, dicom.SignOptions{Key: signatureKey}))
+	require.NoError(t, dicom.SignDataset(ds, dicom.SignOptions{
+		Key:       []byte("second key"),
+		Algorithm: dicom.MACAlgorithmSHA512,
+		Tags:      []tag.Tag{tag.PatientID, tag.PatientName, tag.PatientID},
+	}))
+
+	macParams, err := ds.GetSequenceItems(tag.MACParametersSequence)
+	require.NoError(t, err)
+	require.Len(t, macParams, 2)
+	ids, err := macParams[1].GetInts(tag.MACIDNumber)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, ids)
+	signed, err := macParams[1].Get(tag.DataElementsSigned)
+	require.NoError(t, err)
+	signedTags, err := signed.Value().(*value.IntValue).AsTags()
+	require.NoError(t, err)
+	assert.Equal(t, []tag.Tag{tag.PatientName, tag.PatientID}, signedTags, "sorted without duplicates")
+
+	// Every signature is checked against the one key
+	assert.ErrorIs(t, dicom.VerifyDatasetSignatures(ds, signatureKey), dicom.ErrInvalidSignature)
+	signatures, err := ds.GetSequenceItems(tag.DigitalSignaturesSequence)
+	require.NoError(t, err)
+	require.Len(t, signatures, 2)
+	assert.Len(t, bytesOf(t, signatures[1], tag.Signature), 64, "HMAC-SHA512")
+}
+
+func TestSignDataset_Errors(t *testing.T) {
+	ds := newTranscodeDataSet(t)
+
+	assert.Error(t, dicom.SignDataset(nil, dicom.SignOptions{Key: signatureKey}))
+	assert.Error(t, dicom.SignDataset(ds, dicom.SignOptions{}), "key is required")
+	assert.Error(t, dicom.SignDataset(ds, dicom.SignOptions{Key: signatureKey, Algorithm: "RIPEMD160"}))
+	assert.Error(t, dicom.SignDataset(ds, dicom.SignOptions{Key: signatureKey, Tags: []tag.Tag{tag.StudyComments}}),
+		"signed elements must be present")
+	assert.False(t, ds.Contains(tag.DigitalSignaturesSequence), "a failed signing leaves ds unchanged")
+
+	assert.Error(t, dicom.VerifyDatasetSignatures(nil, signatureKey))
+	assert.Error(t, dicom.VerifyDatasetSignatures(ds, signatureKey), "unsigned dataset")
+}
@@ -0,0 +1,33 @@
+// Package sr provides access to DICOM Structured Reporting (SR) documents.
+//
+// # Content Tree
+//
+// An SR document is a tree of content items. The root is a CONTAINER whose Concept
+// Name is the document title; each item may hold further items in its Content
+// Sequence (0040,A730), linked to the parent by a Relationship Type such as CONTAINS
+// or HAS PROPERTIES. Parse walks the whole tree:
+//
+//	ds, err := dicom.ParseFile("report.dcm")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	doc, err := sr.Parse(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	fmt.Println("Title:", doc.Root.ConceptName.Meaning)
+//	doc.Root.Walk(func(item *sr.ContentItem, depth int) {
+//	    switch item.ValueType {
+//	    case sr.ValueTypeText:
+//	        fmt.Printf("%*s%s: %s\n", depth*2, "", item.ConceptName.Meaning, item.Text)
+//	    case sr.ValueTypeNum:
+//	        m := item.Measurement
+//	        fmt.Printf("%*s%s: %g %s\n", depth*2, "", item.ConceptName.Meaning, m.Value, m.Units.Value)
+//	    }
+//	})
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.17.3
+package sr
@@ -0,0 +1,387 @@
+package sr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+)
+
+// Value types of SR content items (0040,A040).
+const (
+	ValueTypeContainer = "CONTAINER"
+	ValueTypeText      = "TEXT"
+	ValueTypeCode      = "CODE"
+	ValueTypeNum       = "NUM"
+	ValueTypeDateTime  = "DATETIME"
+	ValueTypeDate      = "DATE"
+	ValueTypeTime      = "TIME"
+	ValueTypeUIDRef    = "UIDREF"
+	ValueTypePName     = "PNAME"
+	ValueTypeComposite = "COMPOSITE"
+	ValueTypeImage     = "IMAGE"
+	ValueTypeWaveform  = "WAVEFORM"
+	ValueTypeSCoord    = "SCOORD"
+	ValueTypeSCoord3D  = "SCOORD3D"
+	ValueTypeTCoord    = "TCOORD"
+)
+
+// Code is a coded entry from a Code Sequence Macro item.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_8.8
+type Code struct {
+	Value            string // (0008,0100) Code Value, or Long/URN Code Value
+	SchemeDesignator string // (0008,0102) Coding Scheme Designator
+	SchemeVersion    string // (0008,0103) Coding Scheme Version
+	Meaning          string // (0008,0104) Code Meaning
+}
+
+// Matches reports whether c has the given code value and coding scheme.
+func (c *Code) Matches(value, scheme string) bool {
+	return c != nil && c.Value == value && c.SchemeDesignator == scheme
+}
+
+// String returns the code in the conventional (value, scheme, "meaning") form.
+func (c Code) String() string {
+	return fmt.Sprintf("(%s, %s, %q)", c.Value, c.SchemeDesignator, c.Meaning)
+}
+
+// MeasuredValue is the value of a NUM content item.
+type MeasuredValue struct {
+	Value float64 // (0040,A30A) Numeric Value
+	Units Code    // (0040,08EA) Measurement Units Code Sequence, usually UCUM
+}
+
+// Reference is a Referenced SOP Sequence (0008,1199) item of a COMPOSITE, IMAGE or
+// WAVEFORM content item.
+type Reference = dicom.SOPReference
+
+// ContentItem is a node of the SR content tree.
+//
+// Only the fields matching ValueType are set. An item included by reference (a
+// by-reference relationship) has no ValueType; ReferencedItem then holds the
+// position of the target item in the tree, as in Referenced Content Item Identifier.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.17.3.2
+type ContentItem struct {
+	RelationshipType string // (0040,A010) CONTAINS, HAS PROPERTIES, ...; empty for the root
+	ValueType        string // (0040,A040) One of the ValueType constants
+	ConceptName      *Code  // (0040,A043) Concept Name Code Sequence; nil if absent
+
+	// Text holds the value of TEXT, DATETIME, DATE, TIME, UIDREF and PNAME items.
+	Text string
+
+	Code        *Code          // CODE: (0040,A168) Concept Code Sequence
+	Measurement *MeasuredValue // NUM: (0040,A300) Measured Value Sequence; nil if empty
+	Qualifier   *Code          // NUM: (0040,A301) Numeric Value Qualifier, e.g. "Not a number"
+
+	ContinuityOfContent string      // CONTAINER: (0040,A050) SEPARATE or CONTINUOUS
+	References          []Reference // COMPOSITE, IMAGE, WAVEFORM: (0008,1199)
+	GraphicType         string      // SCOORD, SCOORD3D: (0070,0023) POINT, POLYLINE, ...
+	GraphicData         []float64   // SCOORD, SCOORD3D: (0070,0022) coordinates
+
+	ReferencedItem []int // (0040,DB73) Referenced Content Item Identifier
+
+	Children []*ContentItem // (0040,A730) Content Sequence, in order
+}
+
+// Walk calls fn for the item and each of its descendants in depth-first order. The
+// item itself has depth 0.
+func (c *ContentItem) Walk(fn func(item *ContentItem, depth int)) {
+	c.walk(fn, 0)
+}
+
+func (c *ContentItem) walk(fn func(item *ContentItem, depth int), depth int) {
+	fn(c, depth)
+	for _, child := range c.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// Find returns the item and descendants whose Concept Name matches the given code
+// value and coding scheme, in depth-first order.
+//
+// Example:
+//
+//	// All "Finding" items (DCM 121071)
+//	findings := doc.Root.Find("121071", "DCM")
+func (c *ContentItem) Find(value, scheme string) []*ContentItem {
+	var found []*ContentItem
+	c.Walk(func(item *ContentItem, _ int) {
+		if item.ConceptName.Matches(value, scheme) {
+			found = append(found, item)
+		}
+	})
+	return found
+}
+
+// SRDocument is the parsed content of an SR document.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.17.2
+type SRDocument struct {
+	CompletionFlag   string       // (0040,A491) PARTIAL or COMPLETE
+	VerificationFlag string       // (0040,A493) UNVERIFIED or VERIFIED
+	Root             *ContentItem // Document root CONTAINER; its Concept Name is the title
+}
+
+// Parse builds the content tree of an SR document.
+//
+// The document root is read from the top level of the dataset and must be a
+// CONTAINER. Children are read recursively from each item's Content Sequence
+// (0040,A730), and the value of each item is decoded according to its Value Type
+// (0040,A040). Value types not listed among the ValueType constants are kept with
+// their Concept Name and children only.
+//
+// Returns an error if the dataset is not an SR document (when SOP Class UID is
+// present), the root is not a CONTAINER, or any content item is malformed.
+//
+// Example:
+//
+//	doc, err := sr.Parse(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, item := range doc.Root.Find("121071", "DCM") {
+//	    fmt.Println("Finding:", item.Text)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.17.3
+func Parse(ds *dicom.DataSet) (*SRDocument, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	if sopClass := getString(ds, tag.SOPClassUID); sopClass != "" {
+		u, err := uid.Parse(sopClass)
+		if err != nil || uid.Category(u) != uid.CategoryStructuredReport {
+			return nil, fmt.Errorf("not an SR document: SOP Class UID is %s", sopClass)
+		}
+	}
+
+	root, err := parseContentItem(ds)
+	if err != nil {
+		return nil, err
+	}
+	// The relationship of the root describes nothing; ignore a stray value.
+	root.RelationshipType = ""
+	if root.ValueType != ValueTypeContainer {
+		return nil, fmt.Errorf("document root value type is %q, expected %s", root.ValueType, ValueTypeContainer)
+	}
+
+	return &SRDocument{
+		CompletionFlag:   getString(ds, tag.CompletionFlag),
+		VerificationFlag: getString(ds, tag.VerificationFlag),
+		Root:             root,
+	}, nil
+}
+
+// parseContentItem reads a content item and, recursively, its Content Sequence.
+func parseContentItem(ds *dicom.DataSet) (*ContentItem, error) {
+	item := &ContentItem{
+		RelationshipType: getString(ds, tag.RelationshipType),
+		ValueType:        getString(ds, tag.ValueType),
+	}
+
+	var err error
+	if item.ConceptName, err = ParseCode(ds, tag.ConceptNameCodeSequence); err != nil {
+		return nil, err
+	}
+
+	if item.ValueType == "" {
+		ints, err := ds.GetInts(tag.ReferencedContentItemIdentifier)
+		if err != nil {
+			return nil, fmt.Errorf("content item has neither Value Type nor Referenced Content Item Identifier")
+		}
+		item.ReferencedItem = make([]int, len(ints))
+		for i, n := range ints {
+			item.ReferencedItem[i] = int(n)
+		}
+		return item, nil
+	}
+
+	if err := parseValue(ds, item); err != nil {
+		return nil, fmt.Errorf("%s item %s: %w", item.ValueType, conceptLabel(item.ConceptName), err)
+	}
+
+	children, err := ds.GetSequenceItems(tag.ContentSequence)
+	if err != nil {
+		return item, nil
+	}
+	item.Children = make([]*ContentItem, 0, len(children))
+	for i, child := range children {
+		c, err := parseContentItem(child)
+		if err != nil {
+			return nil, fmt.Errorf("content item %d of %s: %w", i+1, conceptLabel(item.ConceptName), err)
+		}
+		item.Children = append(item.Children, c)
+	}
+	return item, nil
+}
+
+// parseValue decodes the value of item according to its Value Type.
+func parseValue(ds *dicom.DataSet, item *ContentItem) error {
+	var err error
+	switch item.ValueType {
+	case ValueTypeContainer:
+		item.ContinuityOfContent = getString(ds, tag.ContinuityOfContent)
+	case ValueTypeText:
+		// Leading spaces of UT are significant, so only the padding is trimmed.
+		item.Text = getText(ds, tag.TextValue)
+	case ValueTypeDateTime:
+		item.Text = getString(ds, tag.DateTime)
+	case ValueTypeDate:
+		item.Text = getString(ds, tag.Date)
+	case ValueTypeTime:
+		item.Text = getString(ds, tag.Time)
+	case ValueTypeUIDRef:
+		item.Text = strings.TrimRight(getString(ds, tag.UID), "\x00")
+	case ValueTypePName:
+		item.Text = getString(ds, tag.PersonName)
+	case ValueTypeCode:
+		if item.Code, err = ParseCode(ds, tag.ConceptCodeSequence); err != nil {
+			return err
+		}
+		if item.Code == nil {
+			return fmt.Errorf("missing Concept Code Sequence")
+		}
+	case ValueTypeNum:
+		return parseMeasurement(ds, item)
+	case ValueTypeComposite, ValueTypeImage, ValueTypeWaveform:
+		return parseReferences(ds, item)
+	case ValueTypeSCoord, ValueTypeSCoord3D:
+		item.GraphicType = getString(ds, tag.GraphicType)
+		if item.GraphicData, err = ds.GetFloats(tag.GraphicData); err != nil {
+			return fmt.Errorf("missing Graphic Data: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseMeasurement reads the Measured Value Sequence of a NUM item. The sequence may
+// be empty, in which case a Numeric Value Qualifier usually explains why.
+func parseMeasurement(ds *dicom.DataSet, item *ContentItem) error {
+	var err error
+	if item.Qualifier, err = ParseCode(ds, tag.NumericValueQualifierCodeSequence); err != nil {
+		return err
+	}
+
+	values, err := ds.GetSequenceItems(tag.MeasuredValueSequence)
+	if err != nil || len(values) == 0 {
+		return nil
+	}
+	mv := values[0]
+
+	numbers, err := mv.GetFloats(tag.NumericValue)
+	if err != nil {
+		return fmt.Errorf("invalid Numeric Value: %w", err)
+	}
+	if len(numbers) != 1 {
+		return fmt.Errorf("numeric value has %d values, expected 1", len(numbers))
+	}
+	units, err := ParseCode(mv, tag.MeasurementUnitsCodeSequence)
+	if err != nil {
+		return err
+	}
+	if units == nil {
+		return fmt.Errorf("missing Measurement Units Code Sequence")
+	}
+
+	item.Measurement = &MeasuredValue{Value: numbers[0], Units: *units}
+	return nil
+}
+
+// parseReferences reads the Referenced SOP Sequence of a COMPOSITE, IMAGE or WAVEFORM
+// item. As for the rest of the content tree it is lenient: an item missing a UID is
+// kept with that UID empty and an invalid Referenced Frame Number is ignored, so one
+// bad reference does not prevent reading the document.
+func parseReferences(ds *dicom.DataSet, item *ContentItem) error {
+	if !ds.Contains(tag.ReferencedSOPSequence) {
+		return fmt.Errorf("missing Referenced SOP Sequence")
+	}
+	refs, err := ds.GetSOPReferences(tag.ReferencedSOPSequence, dicom.ReferenceParseOptions{
+		AllowMissingUIDs:    true,
+		IgnoreInvalidFrames: true,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid Referenced SOP Sequence: %w", err)
+	}
+	item.References = append(item.References, refs...)
+	return nil
+}
+
+// ParseCode reads the first item of the code sequence t of ds, such as Concept Name
+// Code Sequence (0040,A043). The code value is taken from Code Value (0008,0100),
+// falling back to Long Code Value (0008,0119) and URN Code Value (0008,0120).
+//
+// Returns nil, and no error, if the sequence is absent or empty. Returns an error if
+// the item has no code value.
+//
+// Example:
+//
+//	units, err := sr.ParseCode(item, tag.MeasurementUnitsCodeSequence)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if units.Matches("mm", "UCUM") {
+//	    // ...
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_8.8
+func ParseCode(ds *dicom.DataSet, t tag.Tag) (*Code, error) {
+	items, err := ds.GetSequenceItems(t)
+	if err != nil || len(items) == 0 {
+		return nil, nil
+	}
+	item := items[0]
+
+	code := &Code{
+		Value:            getString(item, tag.CodeValue),
+		SchemeDesignator: getString(item, tag.CodingSchemeDesignator),
+		SchemeVersion:    getString(item, tag.CodingSchemeVersion),
+		Meaning:          getString(item, tag.CodeMeaning),
+	}
+	if code.Value == "" {
+		code.Value = getString(item, tag.LongCodeValue)
+	}
+	if code.Value == "" {
+		code.Value = getString(item, tag.URNCodeValue)
+	}
+	if code.Value == "" {
+		return nil, fmt.Errorf("%s item has no code value", t)
+	}
+	return code, nil
+}
+
+// conceptLabel names a content item in error messages.
+func conceptLabel(c *Code) string {
+	if c == nil {
+		return "(no concept name)"
+	}
+	return c.String()
+}
+
+// getString returns the trimmed string value of an element, or "" if absent.
+func getString(ds *dicom.DataSet, t tag.Tag) string {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(elem.Value().String())
+}
+
+// getText returns the value of a text element with only its trailing padding
+// removed, or "" if absent.
+func getText(ds *dicom.DataSet, t tag.Tag) string {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(elem.Value().String(), " ")
+}
@@ -0,0 +1,314 @@
+package sr
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addString adds a string element to ds.
+func addString(t *testing.T, ds *dicom.DataSet, tg tag.Tag, v vr.VR, values ...string) {
+	val, err := value.NewStringValue(v, values)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, v, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+// addFloats adds a binary floating point element to ds.
+func addFloats(t *testing.T, ds *dicom.DataSet, tg tag.Tag, v vr.VR, values ...float64) {
+	val, err := value.NewFloatValue(v, values)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, v, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+// addInts adds a binary integer element to ds.
+func addInts(t *testing.T, ds *dicom.DataSet, tg tag.Tag, v vr.VR, values ...int64) {
+	val, err := value.NewIntValue(v, values)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, v, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+// addSequence adds a sequence element to ds.
+func addSequence(t *testing.T, ds *dicom.DataSet, tg tag.Tag, items ...*dicom.DataSet) {
+	elem, err := dicom.NewSequenceElement(tg, items)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+// addCode adds a single-item code sequence to ds.
+func addCode(t *testing.T, ds *dicom.DataSet, tg tag.Tag, codeValue, scheme, meaning string) {
+	item := dicom.NewDataSet()
+	addString(t, item, tag.CodeValue, vr.ShortString, codeValue)
+	addString(t, item, tag.CodingSchemeDesignator, vr.ShortString, scheme)
+	addString(t, item, tag.CodeMeaning, vr.LongString, meaning)
+	addSequence(t, ds, tg, item)
+}
+
+// newItem builds a content item with a relationship, value type and concept name.
+func newItem(t *testing.T, relationship, valueType, codeValue, scheme, meaning string) *dicom.DataSet {
+	item := dicom.NewDataSet()
+	addString(t, item, tag.RelationshipType, vr.CodeString, relationship)
+	addString(t, item, tag.ValueType, vr.CodeString, valueType)
+	addCode(t, item, tag.ConceptNameCodeSequence, codeValue, scheme, meaning)
+	return item
+}
+
+// newTestReport builds a small Comprehensive SR with a finding, a measurement, a
+// coded entry, an image reference and a spatial coordinate.
+func newTestReport(t *testing.T) *dicom.DataSet {
+	ds := dicom.NewDataSet()
+	addString(t, ds, tag.SOPClassUID, vr.UniqueIdentifier, uid.ComprehensiveSRStorage.String())
+	addString(t, ds, tag.ValueType, vr.CodeString, "CONTAINER")
+	addCode(t, ds, tag.ConceptNameCodeSequence, "18748-4", "LN", "Diagnostic Imaging Report")
+	addString(t, ds, tag.ContinuityOfContent, vr.CodeString, "SEPARATE")
+	addString(t, ds, tag.CompletionFlag, vr.CodeString, "COMPLETE")
+	addString(t, ds, tag.VerificationFlag, vr.CodeString, "UNVERIFIED")
+
+	findings := newItem(t, "CONTAINS", "CONTAINER", "121070", "DCM", "Findings")
+	addString(t, findings, tag.ContinuityOfContent, vr.CodeString, "SEPARATE")
+
+	finding := newItem(t, "CONTAINS", "TEXT", "121071", "DCM", "Finding")
+	addString(t, finding, tag.TextValue, vr.UnlimitedText, "  Nodule in right upper lobe. ")
+
+	diameter := newItem(t, "HAS PROPERTIES", "NUM", "81827009", "SCT", "Diameter")
+	measured := dicom.NewDataSet()
+	addString(t, measured, tag.NumericValue, vr.DecimalString, "12.5")
+	addCode(t, measured, tag.MeasurementUnitsCodeSequence, "mm", "UCUM", "millimeter")
+	addSequence(t, diameter, tag.MeasuredValueSequence, measured)
+
+	site := newItem(t, "HAS CONCEPT MOD", "CODE", "363698007", "SCT", "Finding Site")
+	addCode(t, site, tag.ConceptCodeSequence, "45653009", "SCT", "Upper lobe of right lung")
+
+	image := dicom.NewDataSet()
+	addString(t, image, tag.RelationshipType, vr.CodeString, "SELECTED FROM")
+	addString(t, image, tag.ValueType, vr.CodeString, "IMAGE")
+	ref := dicom.NewDataSet()
+	addString(t, ref, tag.ReferencedSOPClassUID, vr.UniqueIdentifier, uid.CTImageStorage.String())
+	addString(t, ref, tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier, "1.2.826.0.1.3680043.10.1451.7")
+	addString(t, ref, tag.ReferencedFrameNumber, vr.IntegerString, "3")
+	addSequence(t, image, tag.ReferencedSOPSequence, ref)
+
+	region := newItem(t, "INFERRED FROM", "SCOORD", "111030", "DCM", "Image Region")
+	addString(t, region, tag.GraphicType, vr.CodeString, "POINT")
+	addFloats(t, region, tag.GraphicData, vr.FloatingPointSingle, 100.5, 80)
+	addSequence(t, region, tag.ContentSequence, image)
+
+	addSequence(t, finding, tag.ContentSequence, diameter, site, region)
+	addSequence(t, findings, tag.ContentSequence, finding)
+	addSequence(t, ds, tag.ContentSequence, findings)
+	return ds
+}
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(newTestReport(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "COMPLETE", doc.CompletionFlag)
+	assert.Equal(t, "UNVERIFIED", doc.VerificationFlag)
+
+	root := doc.Root
+	require.NotNil(t, root)
+	assert.Equal(t, ValueTypeContainer, root.ValueType)
+	assert.Empty(t, root.RelationshipType)
+	assert.Equal(t, Code{Value: "18748-4", SchemeDesignator: "LN", Meaning: "Diagnostic Imaging Report"}, *root.ConceptName)
+	assert.Equal(t, "SEPARATE", root.ContinuityOfContent)
+	require.Len(t, root.Children, 1)
+
+	findings := root.Children[0]
+	assert.Equal(t, "CONTAINS", findings.RelationshipType)
+	require.Len(t, findings.Children, 1)
+
+	finding := findings.Children[0]
+	assert.Equal(t, ValueTypeText, finding.ValueType)
+	assert.Equal(t, "  Nodule in right upper lobe.", finding.Text, "leading spaces of UT are kept")
+	require.Len(t, finding.Children, 3)
+
+	diameter := finding.Children[0]
+	assert.Equal(t, "HAS PROPERTIES", diameter.RelationshipType)
+	require.NotNil(t, diameter.Measurement)
+	assert.InDelta(t, 12.5, diameter.Measurement.Value, 1e-9)
+	assert.True(t, (&diameter.Measurement.Units).Matches("mm", "UCUM"))
+	assert.Nil(t, diameter.Qualifier)
+
+	site := finding.Children[1]
+	require.NotNil(t, site.Code)
+	assert.Equal(t, "Upper lobe of right lung", site.Code.Meaning)
+
+	region := finding.Children[2]
+	assert.Equal(t, "POINT", region.GraphicType)
+	assert.Equal(t, []float64{100.5, 80}, region.GraphicData)
+	require.Len(t, region.Children, 1)
+
+	image := region.Children[0]
+	assert.Equal(t, ValueTypeImage, image.ValueType)
+	assert.Nil(t, image.ConceptName)
+	assert.Equal(t, []Reference{{
+		SOPClassUID:    uid.CTImageStorage.String(),
+		SOPInstanceUID: "1.2.826.0.1.3680043.10.1451.7",
+		Frames:         []int{3},
+	}}, image.References)
+}
+
+func TestContentItem_WalkAndFind(t *testing.T) {
+	doc, err := Parse(newTestReport(t))
+	require.NoError(t, err)
+
+	var types []string
+	var depths []int
+	doc.Root.Walk(func(item *ContentItem, depth int) {
+		types = append(types, item.ValueType)
+		depths = append(depths, depth)
+	})
+	assert.Equal(t, []string{"CONTAINER", "CONTAINER", "TEXT", "NUM", "CODE", "SCOORD", "IMAGE"}, types)
+	assert.Equal(t, []int{0, 1, 2, 3, 3, 3, 4}, depths)
+
+	found := doc.Root.Find("81827009", "SCT")
+	require.Len(t, found, 1)
+	assert.Equal(t, ValueTypeNum, found[0].ValueType)
+
+	assert.Empty(t, doc.Root.Find("81827009", "DCM"), "scheme must match")
+}
+
+func TestParse_NumericQualifierWithoutValue(t *testing.T) {
+	ds := dicom.NewDataSet()
+	addString(t, ds, tag.ValueType, vr.CodeString, "CONTAINER")
+	num := newItem(t, "CONTAINS", "NUM", "81827009", "SCT", "Diameter")
+	addSequence(t, num, tag.MeasuredValueSequence)
+	addCode(t, num, tag.NumericValueQualifierCodeSequence, "114000", "DCM", "Not a number")
+	addSequence(t, ds, tag.ContentSequence, num)
+
+	doc, err := Parse(ds)
+	require.NoError(t, err)
+	item := doc.Root.Children[0]
+	assert.Nil(t, item.Measurement)
+	require.NotNil(t, item.Qualifier)
+	assert.Equal(t, "114000", item.Qualifier.Value)
+}
+
+func TestParse_ByReference(t *testing.T) {
+	ds := dicom.NewDataSet()
+	addString(t, ds, tag.ValueType, vr.CodeString, "CONTAINER")
+	ref := dicom.NewDataSet()
+	addString(t, ref, tag.RelationshipType, vr.CodeString, "INFERRED FROM")
+	addInts(t, ref, tag.ReferencedContentItemIdentifier, vr.UnsignedLong, 1, 2, 1)
+	addSequence(t, ds, tag.ContentSequence, ref)
+
+	doc, err := Parse(ds)
+	require.NoError(t, err)
+	item := doc.Root.Children[0]
+	assert.Empty(t, item.ValueType)
+	assert.Equal(t, []int{1, 2, 1}, item.ReferencedItem)
+}
+
+func TestParse_IncompleteReference(t *testing.T) {
+	ds := dicom.NewDataSet()
+	addString(t, ds, tag.ValueType, vr.CodeString, "CONTAINER")
+	image := dicom.NewDataSet()
+	addString(t, image, tag.RelationshipType, vr.CodeString, "CONTAINS")
+	addString(t, image, tag.ValueType, vr.CodeString, "IMAGE")
+	missingClass := dicom.NewDataSet()
+	addString(t, missingClass, tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier, "1.2.3.4")
+	addString(t, missingClass, tag.ReferencedFrameNumber, vr.IntegerString, "0")
+	complete := dicom.NewDataSet()
+	addString(t, complete, tag.ReferencedSOPClassUID, vr.UniqueIdentifier, uid.CTImageStorage.String())
+	addString(t, complete, tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier, "1.2.3.5")
+	addSequence(t, image, tag.ReferencedSOPSequence, missingClass, complete)
+	addSequence(t, ds, tag.ContentSequence, image)
+
+	// A reference missing a UID or with an invalid frame number does not fail the
+	// document
+	doc, err := Parse(ds)
+	require.NoError(t, err)
+	assert.Equal(t, []Reference{
+		{SOPInstanceUID: "1.2.3.4"},
+		{SOPClassUID: uid.CTImageStorage.String(), SOPInstanceUID: "1.2.3.5"},
+	}, doc.Root.Children[0].References)
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Run("nil dataset", func(t *testing.T) {
+		_, err := Parse(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("not an SR", func(t *testing.T) {
+		ds := newTestReport(t)
+		addString(t, ds, tag.SOPClassUID, vr.UniqueIdentifier, uid.CTImageStorage.String())
+		_, err := Parse(ds)
+		assert.ErrorContains(t, err, "not an SR document")
+	})
+
+	t.Run("root not a container", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addString(t, ds, tag.ValueType, vr.CodeString, "TEXT")
+		_, err := Parse(ds)
+		assert.ErrorContains(t, err, "expected CONTAINER")
+	})
+
+	t.Run("CODE without concept code", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addString(t, ds, tag.ValueType, vr.CodeString, "CONTAINER")
+		addSequence(t, ds, tag.ContentSequence, newItem(t, "CONTAINS", "CODE", "363698007", "SCT", "Finding Site"))
+		_, err := Parse(ds)
+		assert.ErrorContains(t, err, "Concept Code Sequence")
+	})
+
+	t.Run("NUM without units", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addString(t, ds, tag.ValueType, vr.CodeString, "CONTAINER")
+		num := newItem(t, "CONTAINS", "NUM", "81827009", "SCT", "Diameter")
+		measured := dicom.NewDataSet()
+		addString(t, measured, tag.NumericValue, vr.DecimalString, "4")
+		addSequence(t, num, tag.MeasuredValueSequence, measured)
+		addSequence(t, ds, tag.ContentSequence, num)
+		_, err := Parse(ds)
+		assert.ErrorContains(t, err, "Measurement Units")
+	})
+
+	t.Run("item without value type or reference", func(t *testing.T) {
+		ds := dicom.NewDataSet()
+		addString(t, ds, tag.ValueType, vr.CodeString, "CONTAINER")
+		item := dicom.NewDataSet()
+		addString(t, item, tag.RelationshipType, vr.CodeString, "CONTAINS")
+		addSequence(t, ds, tag.ContentSequence, item)
+		_, err := Parse(ds)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseCode(t *testing.T) {
+	ds := dicom.NewDataSet()
+	addCode(t, ds, tag.ConceptNameCodeSequence, "121071", "DCM", "Finding")
+	code, err := ParseCode(ds, tag.ConceptNameCodeSequence)
+	require.NoError(t, err)
+	assert.Equal(t, &Code{Value: "121071", SchemeDesignator: "DCM", Meaning: "Finding"}, code)
+
+	// Long Code Value is used when Code Value is absent
+	item := dicom.NewDataSet()
+	addString(t, item, tag.LongCodeValue, vr.UnlimitedCharacters, "LONG-CODE-VALUE")
+	addString(t, item, tag.CodingSchemeDesignator, vr.ShortString, "99LOCAL")
+	addSequence(t, ds, tag.ConceptCodeSequence, item)
+	code, err = ParseCode(ds, tag.ConceptCodeSequence)
+	require.NoError(t, err)
+	assert.Equal(t, "LONG-CODE-VALUE", code.Value)
+
+	code, err = ParseCode(ds, tag.MeasurementUnitsCodeSequence)
+	require.NoError(t, err)
+	assert.Nil(t, code, "absent sequence")
+
+	addSequence(t, ds, tag.MeasurementUnitsCodeSequence, dicom.NewDataSet())
+	_, err = ParseCode(ds, tag.MeasurementUnitsCodeSequence)
+	assert.Error(t, err, "item without a code value")
+}
@@ -0,0 +1,114 @@
+package tag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// PrivateInfo describes an element of a vendor's private dictionary.
+//
+// Private elements are identified by their Private Creator, group and the low byte
+// of the element number (the offset within the block reserved for the creator),
+// since the high byte depends on which block the creator happens to reserve in each
+// dataset.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.8.1
+type PrivateInfo struct {
+	Creator string // Private Creator, e.g. "SIEMENS CSA HEADER"
+	Group   uint16 // Odd group number, e.g. 0x0029
+	Offset  uint8  // Low byte of the element number, e.g. 0x10 for (0029,xx10)
+	VR      vr.VR
+	Name    string
+	Keyword string
+	VM      string
+}
+
+// privateKey identifies a private dictionary entry.
+type privateKey struct {
+	creator string
+	group   uint16
+	offset  uint8
+}
+
+var (
+	privateDictMu sync.RWMutex
+	privateDict   = make(map[privateKey]PrivateInfo)
+)
+
+// RegisterPrivateDictionary adds vendor private dictionary entries, replacing any
+// entry already registered for the same creator, group and offset.
+//
+// Registration is safe for concurrent use with lookups, and is typically done once
+// at start-up from an init function.
+//
+// Returns an error, registering nothing, if an entry has an even group number or no
+// Private Creator.
+//
+// Example:
+//
+//	err := tag.RegisterPrivateDictionary(
+//	    tag.PrivateInfo{Creator: "ACME 1.0", Group: 0x0019, Offset: 0x10, VR: vr.DecimalString,
+//	        Name: "Coil Temperature", Keyword: "CoilTemperature", VM: "1"},
+//	)
+func RegisterPrivateDictionary(entries ...PrivateInfo) error {
+	for _, e := range entries {
+		if e.Group%2 == 0 {
+			return fmt.Errorf("private dictionary entry %q: group %04X is not private", e.Keyword, e.Group)
+		}
+		if strings.TrimSpace(e.Creator) == "" {
+			return fmt.Errorf("private dictionary entry %q: missing Private Creator", e.Keyword)
+		}
+	}
+
+	privateDictMu.Lock()
+	defer privateDictMu.Unlock()
+	for _, e := range entries {
+		e.Creator = strings.TrimSpace(e.Creator)
+		privateDict[privateKey{creator: e.Creator, group: e.Group, offset: e.Offset}] = e
+	}
+	return nil
+}
+
+// FindPrivate returns the private dictionary entry registered for the element at
+// offset in the block of creator in group.
+//
+// Returns an error if no entry is registered.
+func FindPrivate(group uint16, creator string, offset uint8) (PrivateInfo, error) {
+	privateDictMu.RLock()
+	defer privateDictMu.RUnlock()
+
+	info, ok := privateDict[privateKey{creator: strings.TrimSpace(creator), group: group, offset: offset}]
+	if !ok {
+		return PrivateInfo{}, fmt.Errorf("private element (%04X,xx%02X) of %q not found in dictionary",
+			group, offset, creator)
+	}
+	return info, nil
+}
+
+// FindPrivateByKeyword returns the private dictionary entry of creator in group with
+// the given keyword. When several entries share a keyword the lowest offset wins.
+//
+// Returns an error if no entry is registered.
+func FindPrivateByKeyword(group uint16, creator string, keyword string) (PrivateInfo, error) {
+	privateDictMu.RLock()
+	defer privateDictMu.RUnlock()
+
+	creator = strings.TrimSpace(creator)
+	var matches []PrivateInfo
+	for key, info := range privateDict {
+		if key.creator == creator && key.group == group && info.Keyword == keyword {
+			matches = append(matches, info)
+		}
+	}
+	if len(matches) == 0 {
+		return PrivateInfo{}, fmt.Errorf("private keyword %q of %q in group %04X not found in dictionary",
+			keyword, creator, group)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Offset < matches[j].Offset })
+	return matches[0], nil
+}
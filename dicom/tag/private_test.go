@@ -0,0 +1,37 @@
+package tag
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterPrivateDictionary(t *testing.T) {
+	require.NoError(t, RegisterPrivateDictionary(
+		PrivateInfo{Creator: "TEST DICT 1.0 ", Group: 0x0019, Offset: 0x10, VR: vr.DecimalString,
+			Name: "Coil Temperature", Keyword: "CoilTemperature", VM: "1"},
+		PrivateInfo{Creator: "TEST DICT 1.0", Group: 0x0019, Offset: 0x20, VR: vr.LongString,
+			Name: "Sequence Variant", Keyword: "SequenceVariant", VM: "1"},
+	))
+
+	info, err := FindPrivate(0x0019, "TEST DICT 1.0", 0x10)
+	require.NoError(t, err)
+	assert.Equal(t, "CoilTemperature", info.Keyword)
+	assert.Equal(t, "TEST DICT 1.0", info.Creator, "creator padding is trimmed")
+
+	info, err = FindPrivateByKeyword(0x0019, "TEST DICT 1.0", "SequenceVariant")
+	require.NoError(t, err)
+	assert.Equal(t, uint8(0x20), info.Offset)
+
+	_, err = FindPrivate(0x0019, "TEST DICT 1.0", 0x30)
+	assert.Error(t, err)
+	_, err = FindPrivate(0x0021, "TEST DICT 1.0", 0x10)
+	assert.Error(t, err, "entries are per group")
+	_, err = FindPrivateByKeyword(0x0019, "OTHER", "CoilTemperature")
+	assert.Error(t, err)
+
+	assert.Error(t, RegisterPrivateDictionary(PrivateInfo{Creator: "TEST DICT 1.0", Group: 0x0018, Offset: 0x10}))
+	assert.Error(t, RegisterPrivateDictionary(PrivateInfo{Creator: " ", Group: 0x0019, Offset: 0x40}))
+}
@@ -8,6 +8,7 @@ package tag
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/codeninja55/go-radx/dicom/vr"
 )
@@ -167,8 +168,9 @@ func Find(t Tag) (Info, error) {
 // FindByKeyword searches for a tag by its keyword or name field.
 // Returns an error if no tag with the given keyword or name is found.
 //
-// Note: This performs a linear search through all tags, so it's less efficient than Find.
-// The search first checks keywords, then falls back to checking names.
+// Lookups go through keyword and name indexes built once from TagDict on first use;
+// after that they are read-only map lookups and safe for concurrent use without
+// locking. Keywords are checked first, then names.
 //
 // Example: FindByKeyword("SOPClassUID") or FindByKeyword("SOP Class UID")
 //
@@ -178,14 +180,46 @@ func FindByKeyword(keyword string) (Info, error) {
 	if keyword == "" {
 		return Info{}, fmt.Errorf("keyword cannot be empty")
 	}
-	for _, info := range TagDict {
-		if info.Keyword == keyword || info.Name == keyword {
-			return info, nil
-		}
+	idx := keywordIndex()
+	if t, ok := idx.byKeyword[keyword]; ok {
+		return TagDict[t], nil
+	}
+	if t, ok := idx.byName[keyword]; ok {
+		return TagDict[t], nil
 	}
 	return Info{}, fmt.Errorf("tag with keyword %q not found in dictionary", keyword)
 }
 
+// dictIndex maps keywords and names to tags in TagDict.
+type dictIndex struct {
+	byKeyword map[string]Tag
+	byName    map[string]Tag
+}
+
+// keywordIndex returns the keyword and name indexes, building them on first call.
+// When several tags share a keyword or name the lowest tag wins, so results do not
+// depend on map iteration order.
+var keywordIndex = sync.OnceValue(func() dictIndex {
+	idx := dictIndex{
+		byKeyword: make(map[string]Tag, len(TagDict)),
+		byName:    make(map[string]Tag, len(TagDict)),
+	}
+	add := func(m map[string]Tag, key string, t Tag) {
+		if key == "" {
+			return
+		}
+		if existing, ok := m[key]; ok && existing.Compare(t) <= 0 {
+			return
+		}
+		m[key] = t
+	}
+	for t, info := range TagDict {
+		add(idx.byKeyword, info.Keyword, t)
+		add(idx.byName, info.Name, t)
+	}
+	return idx
+})
+
 // FindByName searches for a tag by its human-readable name.
 // This is a convenience wrapper around FindByKeyword.
 //
@@ -1,6 +1,7 @@
 package tag_test
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/codeninja55/go-radx/dicom/tag"
@@ -487,3 +488,69 @@ func TestMustFind(t *testing.T) {
 		tag.MustFind(tag.New(0x9999, 0x9999))
 	})
 }
+
+// TestFind_Concurrent exercises dictionary lookups from many goroutines, as the
+// directory reader does with parallel workers. Run with -race to check for data races.
+func TestFind_Concurrent(t *testing.T) {
+	const workers = 16
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				if _, err := tag.Find(tag.PatientName); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := tag.Find(tag.New(0x0028, 0x0000)); err != nil {
+					errs <- err
+					return
+				}
+				info, err := tag.FindByKeyword("SOPInstanceUID")
+				if err != nil {
+					errs <- err
+					return
+				}
+				if info.Tag != tag.SOPInstanceUID {
+					t.Errorf("FindByKeyword returned %s, want %s", info.Tag, tag.SOPInstanceUID)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func BenchmarkFind_Parallel(b *testing.B) {
+	tags := []tag.Tag{tag.PatientName, tag.StudyInstanceUID, tag.PixelData, tag.New(0x0010, 0x0000)}
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, err := tag.Find(tags[i%len(tags)]); err != nil {
+				b.Fatal(err)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkFindByKeyword_Parallel(b *testing.B) {
+	keywords := []string{"PatientName", "StudyInstanceUID", "Pixel Data", "SOPClassUID"}
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, err := tag.FindByKeyword(keywords[i%len(keywords)]); err != nil {
+				b.Fatal(err)
+			}
+			i++
+		}
+	})
+}
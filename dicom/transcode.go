@@ -0,0 +1,155 @@
+package dicom
+
+import (
+	"fmt"
+
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// Transcode returns a copy of ds re-encoded for the native (uncompressed) transfer
+// syntax target, ready to be written with WriteOptions.TransferSyntax set to target.
+//
+// The bytes of word-based binary values (OW, OF, OD, OL, OV), which are held as they
+// were read, are swapped when target has the other byte order from the dataset's
+// Transfer Syntax UID (0002,0010), and Transfer Syntax UID is set to target. Values of
+// VR UN are carried through byte for byte: their real VR, and so their structure, is
+// unknown, and swapping them would corrupt multi-byte values. Numeric values are held
+// as numbers and need no conversion; the writer encodes them in the target byte
+// order. Parse with ParseOptions.PreserveUN to keep ambiguous Implicit VR attributes
+// as UN through a transcode.
+//
+// ds itself is not modified; elements that need no change are shared with the copy,
+// as with DataSet.Copy.
+//
+// Returns an error if:
+//   - ds is nil
+//   - target is not a supported transfer syntax, or is a compressed one (use
+//     pixel.EncodeForTransferSyntax to compress pixel data)
+//   - ds holds pixel data in a compressed transfer syntax, which must be decoded
+//     with the pixel package first
+//
+// Example:
+//
+//	ds, err := dicom.ParseFileWithOptions("be.dcm", dicom.ParseOptions{PreserveUN: true})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	le, err := dicom.Transcode(ds, uid.ExplicitVRLittleEndian)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = dicom.WriteFileWithOptions("le.dcm", le, dicom.WriteOptions{
+//	    TransferSyntax: &uid.ExplicitVRLittleEndian,
+//	})
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#chapter_10
+func Transcode(ds *DataSet, target uid.UID) (*DataSet, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("cannot transcode nil dataset")
+	}
+
+	targetTS, err := NewTransferSyntax(target)
+	if err != nil {
+		return nil, err
+	}
+	if targetTS.Compressed {
+		return nil, fmt.Errorf("%w: cannot transcode to compressed transfer syntax %s; encode the pixel data with the pixel package",
+			ErrInvalidTransferSyntax, target)
+	}
+
+	source := datasetTransferSyntax(ds)
+	if sourceTS, ok := transferSyntaxes[source]; ok && sourceTS.Compressed && ds.Contains(tag.PixelData) {
+		return nil, fmt.Errorf("%w: pixel data in compressed transfer syntax %s must be decoded before transcoding",
+			ErrInvalidTransferSyntax, source)
+	}
+
+	swap := source != "" && transferSyntaxByteOrder(source) != targetTS.ByteOrder
+	result, err := transcodeItem(ds, swap)
+	if err != nil {
+		return nil, err
+	}
+
+	tsValue, err := value.NewStringValue(vr.UniqueIdentifier, []string{target.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Transfer Syntax UID: %w", err)
+	}
+	tsElem, err := element.NewElement(tag.TransferSyntaxUID, vr.UniqueIdentifier, tsValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Transfer Syntax UID: %w", err)
+	}
+	if err := result.Set(tsElem); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// transcodeItem copies ds, swapping the bytes of word-based binary values in it and
+// its sequence items if swap is set.
+func transcodeItem(ds *DataSet, swap bool) (*DataSet, error) {
+	result := ds.Copy()
+	if !swap {
+		return result, nil
+	}
+
+	for _, elem := range ds.Elements() {
+		replaced, err := transcodeElement(elem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transcode %s: %w", elem.Tag(), err)
+		}
+		if replaced != elem {
+			if err := result.Set(replaced); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// transcodeElement returns elem with its bytes swapped to the other byte order, or
+// elem itself if its value has no byte order to change.
+func transcodeElement(elem *element.Element) (*element.Element, error) {
+	switch val := elem.Value().(type) {
+	case *value.SequenceValue:
+		items := make([]value.Item, len(val.Items()))
+		for i, item := range val.Items() {
+			itemDS, ok := item.(*DataSet)
+			if !ok {
+				return nil, fmt.Errorf("sequence item %d has unsupported type %T", i, item)
+			}
+			transcoded, err := transcodeItem(itemDS, true)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			items[i] = transcoded
+		}
+		seq, err := value.NewSequenceValue(items)
+		if err != nil {
+			return nil, err
+		}
+		return element.NewElement(elem.Tag(), elem.VR(), seq)
+
+	case *value.BytesValue:
+		size := wordSize(elem.VR())
+		data := val.Bytes()
+		if size < 2 || len(data)%size != 0 {
+			// UN and OB are byte streams; data not a whole number of words is left as is
+			return elem, nil
+		}
+		swapped := make([]byte, len(data))
+		copy(swapped, data)
+		swapWordBytes(swapped, size)
+		bytesVal, err := value.NewBytesValue(elem.VR(), swapped)
+		if err != nil {
+			return nil, err
+		}
+		return element.NewElement(elem.Tag(), elem.VR(), bytesVal)
+
+	default:
+		return elem, nil
+	}
+}
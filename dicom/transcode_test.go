@@ -0,0 +1,172 @@
+package dicom_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unPayload is the value of the private UN element; as 16- or 32-bit words it would
+// be corrupted by swapping.
+var unPayload = []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+var privateUNTag = tag.New(0x0009, 0x1001)
+
+// newTranscodeDataSet returns a dataset with a private UN element, an OW element
+// holding the words 1 and 2, and an AT element.
+func newTranscodeDataSet(t *testing.T) *dicom.DataSet {
+	t.Helper()
+
+	ds, err := newSecondaryCaptureBuilder().Build()
+	require.NoError(t, err)
+
+	require.NoError(t, ds.Set(mustNewElement(tag.New(0x0009, 0x0010), vr.LongString,
+		mustNewStringValue(vr.LongString, []string{"ACME 1.0"}))))
+	un, err := value.NewBytesValue(vr.Unknown, unPayload)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(mustNewElement(privateUNTag, vr.Unknown, un)))
+
+	ow, err := value.NewBytesValue(vr.OtherWord, []byte{0x01, 0x00, 0x02, 0x00})
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(mustNewElement(tag.RedPaletteColorLookupTableData, vr.OtherWord, ow)))
+
+	at, err := value.NewIntValue(vr.AttributeTag, []int64{0x00181063})
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(mustNewElement(tag.FrameIncrementPointer, vr.AttributeTag, at)))
+	return ds
+}
+
+func bytesOf(t *testing.T, ds *dicom.DataSet, tg tag.Tag) []byte {
+	t.Helper()
+	elem, err := ds.Get(tg)
+	require.NoError(t, err)
+	return elem.Value().Bytes()
+}
+
+func writeAndParse(t *testing.T, ds *dicom.DataSet, ts uid.UID, opts dicom.ParseOptions) (*dicom.DataSet, []byte) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "transcoded.dcm")
+	require.NoError(t, dicom.WriteFileWithOptions(path, ds, dicom.WriteOptions{TransferSyntax: &ts}))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	parsed, err := dicom.ParseFileWithOptions(path, opts)
+	require.NoError(t, err)
+	return parsed, data
+}
+
+func TestTranscode_AcrossEndianness(t *testing.T) {
+	source, _ := writeAndParse(t, newTranscodeDataSet(t), uid.ExplicitVRLittleEndian, dicom.ParseOptions{})
+
+	bigEndian, err := dicom.Transcode(source, uid.ExplicitVRBigEndian)
+	require.NoError(t, err)
+	assert.Equal(t, uid.ExplicitVRBigEndian.String(), stringOf(t, bigEndian, tag.TransferSyntaxUID))
+	assert.Equal(t, []byte{0x00, 0x01, 0x00, 0x02}, bytesOf(t, bigEndian, tag.RedPaletteColorLookupTableData))
+	assert.Equal(t, unPayload, bytesOf(t, bigEndian, privateUNTag))
+	assert.Equal(t, []byte{0x01, 0x00, 0x02, 0x00}, bytesOf(t, source, tag.RedPaletteColorLookupTableData),
+		"the source dataset is not modified")
+
+	parsedBE, raw := writeAndParse(t, bigEndian, uid.ExplicitVRBigEndian, dicom.ParseOptions{})
+	assert.True(t, bytes.Contains(raw, append([]byte{0x00, 0x09, 0x10, 0x01, 'U', 'N', 0, 0, 0, 0, 0, 8}, unPayload...)),
+		"UN element written big endian with its value bytes unchanged")
+	assert.Equal(t, unPayload, bytesOf(t, parsedBE, privateUNTag))
+	assert.Equal(t, []byte{0x00, 0x01, 0x00, 0x02}, bytesOf(t, parsedBE, tag.RedPaletteColorLookupTableData))
+	assert.Equal(t, stringOf(t, source, tag.Rows), stringOf(t, parsedBE, tag.Rows))
+
+	at, err := parsedBE.Get(tag.FrameIncrementPointer)
+	require.NoError(t, err)
+	tags, err := at.Value().(*value.IntValue).AsTags()
+	require.NoError(t, err)
+	assert.Equal(t, []tag.Tag{tag.FrameTime}, tags)
+
+	// And back again
+	littleEndian, err := dicom.Transcode(parsedBE, uid.ExplicitVRLittleEndian)
+	require.NoError(t, err)
+	parsedLE, _ := writeAndParse(t, littleEndian, uid.ExplicitVRLittleEndian, dicom.ParseOptions{})
+	for _, tg := range []tag.Tag{privateUNTag, tag.RedPaletteColorLookupTableData, tag.FrameIncrementPointer, tag.PixelData} {
+		assert.Equal(t, bytesOf(t, source, tg), bytesOf(t, parsedLE, tg), "%s", tg)
+	}
+}
+
+func TestWriteFileWithOptions_SwapsWordsForOtherEndianness(t *testing.T) {
+	source, _ := writeAndParse(t, newTranscodeDataSet(t), uid.ExplicitVRLittleEndian, dicom.ParseOptions{})
+
+	// Writing without Transcode converts the OW words but still leaves UN alone
+	parsed, _ := writeAndParse(t, source, uid.ExplicitVRBigEndian, dicom.ParseOptions{})
+	assert.Equal(t, []byte{0x00, 0x01, 0x00, 0x02}, bytesOf(t, parsed, tag.RedPaletteColorLookupTableData))
+	assert.Equal(t, unPayload, bytesOf(t, parsed, privateUNTag))
+}
+
+func TestWriteFileWithOptions_SwapsInMemoryWordsForBigEndian(t *testing.T) {
+	// A dataset built in memory has no Transfer Syntax UID; its OW words are little
+	// endian, as NewBytesValue documents, and must be swapped for big endian
+	ds := dicom.NewDataSet()
+	require.NoError(t, ds.Set(mustNewElement(tag.SOPClassUID, vr.UniqueIdentifier,
+		mustNewStringValue(vr.UniqueIdentifier, []string{uid.SecondaryCaptureImageStorage.String()}))))
+	require.NoError(t, ds.Set(mustNewElement(tag.SOPInstanceUID, vr.UniqueIdentifier,
+		mustNewStringValue(vr.UniqueIdentifier, []string{"1.2.3.4.5"}))))
+	ow, err := value.NewBytesValue(vr.OtherWord, []byte{0x01, 0x00, 0x02, 0x00})
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(mustNewElement(tag.RedPaletteColorLookupTableData, vr.OtherWord, ow)))
+	require.False(t, ds.Contains(tag.TransferSyntaxUID))
+
+	parsed, raw := writeAndParse(t, ds, uid.ExplicitVRBigEndian, dicom.ParseOptions{})
+	assert.True(t, bytes.Contains(raw, []byte{0x00, 0x28, 0x12, 0x01, 'O', 'W', 0, 0, 0, 0, 0, 4, 0x00, 0x01, 0x00, 0x02}),
+		"OW words written big endian")
+	assert.Equal(t, []byte{0x00, 0x01, 0x00, 0x02}, bytesOf(t, parsed, tag.RedPaletteColorLookupTableData))
+
+	parsed, _ = writeAndParse(t, ds, uid.ExplicitVRLittleEndian, dicom.ParseOptions{})
+	assert.Equal(t, []byte{0x01, 0x00, 0x02, 0x00}, bytesOf(t, parsed, tag.RedPaletteColorLookupTableData))
+}
+
+func TestTranscode_Errors(t *testing.T) {
+	_, err := dicom.Transcode(nil, uid.ExplicitVRLittleEndian)
+	assert.Error(t, err)
+
+	ds := newTranscodeDataSet(t)
+	_, err = dicom.Transcode(ds, uid.JPEGBaselineProcess1)
+	assert.ErrorIs(t, err, dicom.ErrInvalidTransferSyntax)
+
+	require.NoError(t, ds.Set(mustNewElement(tag.TransferSyntaxUID, vr.UniqueIdentifier,
+		mustNewStringValue(vr.UniqueIdentifier, []string{uid.RLELossless.String()}))))
+	_, err = dicom.Transcode(ds, uid.ExplicitVRLittleEndian)
+	assert.ErrorIs(t, err, dicom.ErrInvalidTransferSyntax)
+}
+
+func TestParseOptions_PreserveUN(t *testing.T) {
+	ds := newTranscodeDataSet(t)
+	descriptor, err := value.NewIntValue(vr.UnsignedShort, []int64{256, 0, 16})
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(mustNewElement(tag.LUTDescriptor, vr.UnsignedShort, descriptor)))
+
+	// LUT Descriptor is "US or SS" in the dictionary
+	guessed, _ := writeAndParse(t, ds, uid.ImplicitVRLittleEndian, dicom.ParseOptions{})
+	elem, err := guessed.Get(tag.LUTDescriptor)
+	require.NoError(t, err)
+	assert.Equal(t, vr.UnsignedShort, elem.VR())
+
+	preserved, _ := writeAndParse(t, ds, uid.ImplicitVRLittleEndian, dicom.ParseOptions{PreserveUN: true})
+	elem, err = preserved.Get(tag.LUTDescriptor)
+	require.NoError(t, err)
+	assert.Equal(t, vr.Unknown, elem.VR())
+	assert.Equal(t, []byte{0x00, 0x01, 0x00, 0x00, 0x10, 0x00}, elem.Value().Bytes())
+
+	// Tags with a single dictionary VR are still resolved
+	elem, err = preserved.Get(tag.Rows)
+	require.NoError(t, err)
+	assert.Equal(t, vr.UnsignedShort, elem.VR())
+
+	// The preserved bytes survive a transcode to big endian unchanged
+	bigEndian, err := dicom.Transcode(preserved, uid.ExplicitVRBigEndian)
+	require.NoError(t, err)
+	parsed, _ := writeAndParse(t, bigEndian, uid.ExplicitVRBigEndian, dicom.ParseOptions{})
+	assert.Equal(t, []byte{0x00, 0x01, 0x00, 0x00, 0x10, 0x00}, bytesOf(t, parsed, tag.LUTDescriptor))
+}
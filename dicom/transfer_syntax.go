@@ -0,0 +1,93 @@
+package dicom
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/codeninja55/go-radx/dicom/uid"
+)
+
+// TransferSyntax describes the encoding of a DICOM dataset.
+type TransferSyntax struct {
+	UID        string           // Transfer Syntax UID
+	ExplicitVR bool             // true = Explicit VR, false = Implicit VR
+	ByteOrder  binary.ByteOrder // Little or Big Endian
+	Compressed bool             // true if pixel data is compressed
+	Deflated   bool             // true for deflated transfer syntax
+}
+
+// transferSyntaxes is the registry of transfer syntaxes the parser can decode,
+// keyed by Transfer Syntax UID.
+//
+// Compressed transfer syntaxes all use Explicit VR Little Endian for the dataset
+// itself; their pixel data remains as raw bytes until explicitly decompressed via
+// pixel.Extract().
+var transferSyntaxes = map[string]TransferSyntax{
+	// Implicit VR Little Endian
+	"1.2.840.10008.1.2": {ExplicitVR: false, ByteOrder: binary.LittleEndian},
+	// Explicit VR Little Endian (default)
+	"1.2.840.10008.1.2.1": {ExplicitVR: true, ByteOrder: binary.LittleEndian},
+	// Explicit VR Big Endian (RETIRED)
+	"1.2.840.10008.1.2.2": {ExplicitVR: true, ByteOrder: binary.BigEndian},
+	// Deflated Explicit VR Little Endian
+	"1.2.840.10008.1.2.1.99": {ExplicitVR: true, ByteOrder: binary.LittleEndian, Deflated: true},
+
+	// RLE Lossless
+	"1.2.840.10008.1.2.5": {ExplicitVR: true, ByteOrder: binary.LittleEndian, Compressed: true},
+	// JPEG Baseline (Process 1)
+	"1.2.840.10008.1.2.4.50": {ExplicitVR: true, ByteOrder: binary.LittleEndian, Compressed: true},
+	// JPEG Baseline (Processes 2 & 4)
+	"1.2.840.10008.1.2.4.51": {ExplicitVR: true, ByteOrder: binary.LittleEndian, Compressed: true},
+	// JPEG Lossless, Non-Hierarchical, First-Order Prediction
+	"1.2.840.10008.1.2.4.57": {ExplicitVR: true, ByteOrder: binary.LittleEndian, Compressed: true},
+	// JPEG Lossless, Non-Hierarchical (Process 14)
+	"1.2.840.10008.1.2.4.70": {ExplicitVR: true, ByteOrder: binary.LittleEndian, Compressed: true},
+	// JPEG 2000 Image Compression (Lossless Only)
+	"1.2.840.10008.1.2.4.90": {ExplicitVR: true, ByteOrder: binary.LittleEndian, Compressed: true},
+	// JPEG 2000 Image Compression
+	"1.2.840.10008.1.2.4.91": {ExplicitVR: true, ByteOrder: binary.LittleEndian, Compressed: true},
+	// High-Throughput JPEG 2000 (HTJ2K) Lossless Only
+	"1.2.840.10008.1.2.4.201": {ExplicitVR: true, ByteOrder: binary.LittleEndian, Compressed: true},
+	// High-Throughput JPEG 2000 (HTJ2K) Lossless or Lossy
+	"1.2.840.10008.1.2.4.203": {ExplicitVR: true, ByteOrder: binary.LittleEndian, Compressed: true},
+}
+
+// NewTransferSyntax returns the encoding properties for a Transfer Syntax UID.
+//
+// The UID is looked up in the parser's transfer syntax registry, which supplies
+// ExplicitVR, ByteOrder, Compressed and Deflated. An error wrapping
+// ErrInvalidTransferSyntax is returned when the UID is not a transfer syntax at all,
+// or is a registered DICOM transfer syntax that this package cannot decode. An empty
+// UID returns ErrMissingTransferSyntax.
+//
+// Example:
+//
+//	ts, err := dicom.NewTransferSyntax(uid.ExplicitVRBigEndian)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(ts.ByteOrder) // BigEndian
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#chapter_10
+func NewTransferSyntax(tsUID uid.UID) (*TransferSyntax, error) {
+	return lookupTransferSyntax(tsUID.String())
+}
+
+// lookupTransferSyntax resolves a Transfer Syntax UID string against the registry.
+func lookupTransferSyntax(tsUID string) (*TransferSyntax, error) {
+	if tsUID == "" {
+		return nil, fmt.Errorf("%w: Transfer Syntax UID is empty", ErrMissingTransferSyntax)
+	}
+
+	ts, ok := transferSyntaxes[tsUID]
+	if !ok {
+		if !uid.IsTransferSyntax(tsUID) {
+			return nil, fmt.Errorf("%w: %q is not a Transfer Syntax UID", ErrInvalidTransferSyntax, tsUID)
+		}
+		return nil, fmt.Errorf("%w: Transfer Syntax UID %q not supported", ErrInvalidTransferSyntax, tsUID)
+	}
+
+	ts.UID = tsUID
+	return &ts, nil
+}
@@ -0,0 +1,71 @@
+package dicom_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransferSyntax(t *testing.T) {
+	tests := []struct {
+		name       string
+		uid        uid.UID
+		explicitVR bool
+		byteOrder  binary.ByteOrder
+		compressed bool
+		deflated   bool
+	}{
+		{"implicit VR little endian", uid.ImplicitVRLittleEndian, false, binary.LittleEndian, false, false},
+		{"explicit VR little endian", uid.ExplicitVRLittleEndian, true, binary.LittleEndian, false, false},
+		{"explicit VR big endian", uid.ExplicitVRBigEndian, true, binary.BigEndian, false, false},
+		{"deflated", uid.DeflatedExplicitVRLittleEndian, true, binary.LittleEndian, false, true},
+		{"JPEG baseline", uid.JPEGBaselineProcess1, true, binary.LittleEndian, true, false},
+		{"RLE lossless", uid.MustParse("1.2.840.10008.1.2.5"), true, binary.LittleEndian, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := dicom.NewTransferSyntax(tt.uid)
+			require.NoError(t, err)
+			assert.Equal(t, tt.uid.String(), ts.UID)
+			assert.Equal(t, tt.explicitVR, ts.ExplicitVR)
+			assert.Equal(t, tt.byteOrder, ts.ByteOrder)
+			assert.Equal(t, tt.compressed, ts.Compressed)
+			assert.Equal(t, tt.deflated, ts.Deflated)
+		})
+	}
+}
+
+func TestNewTransferSyntax_ReturnsCopy(t *testing.T) {
+	ts, err := dicom.NewTransferSyntax(uid.ExplicitVRLittleEndian)
+	require.NoError(t, err)
+	ts.ExplicitVR = false
+
+	again, err := dicom.NewTransferSyntax(uid.ExplicitVRLittleEndian)
+	require.NoError(t, err)
+	assert.True(t, again.ExplicitVR)
+}
+
+func TestNewTransferSyntax_Errors(t *testing.T) {
+	t.Run("empty UID", func(t *testing.T) {
+		_, err := dicom.NewTransferSyntax(uid.UID{})
+		assert.ErrorIs(t, err, dicom.ErrMissingTransferSyntax)
+	})
+
+	t.Run("unsupported transfer syntax", func(t *testing.T) {
+		// JPEG-LS Lossless is a registered transfer syntax the parser does not decode
+		_, err := dicom.NewTransferSyntax(uid.JPEGLsLosslessImageCompression)
+		assert.ErrorIs(t, err, dicom.ErrInvalidTransferSyntax)
+		assert.Contains(t, err.Error(), "not supported")
+	})
+
+	t.Run("not a transfer syntax", func(t *testing.T) {
+		_, err := dicom.NewTransferSyntax(uid.CTImageStorage)
+		assert.ErrorIs(t, err, dicom.ErrInvalidTransferSyntax)
+		assert.Contains(t, err.Error(), "not a Transfer Syntax UID")
+	})
+}
@@ -0,0 +1,202 @@
+package dicom
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// ElementMatcher selects the elements a TransformPipeline rule applies to. path holds
+// the tags of the sequences enclosing the element, outermost first, as in Walk.
+type ElementMatcher func(path []tag.Tag, elem *element.Element) bool
+
+// MatchTag returns an ElementMatcher selecting elements with any of the given tags,
+// at any nesting level.
+func MatchTag(tags ...tag.Tag) ElementMatcher {
+	return func(_ []tag.Tag, elem *element.Element) bool {
+		for _, t := range tags {
+			if elem.Tag() == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchVR returns an ElementMatcher selecting elements with any of the given VRs.
+func MatchVR(vrs ...vr.VR) ElementMatcher {
+	return func(_ []tag.Tag, elem *element.Element) bool {
+		for _, v := range vrs {
+			if elem.VR() == v {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchKeyword returns an ElementMatcher selecting elements whose dictionary keyword
+// (e.g. "PatientName") is any of the given keywords. Private and unknown tags have
+// no keyword and never match.
+func MatchKeyword(keywords ...string) ElementMatcher {
+	return func(_ []tag.Tag, elem *element.Element) bool {
+		keyword := elem.Keyword()
+		if keyword == "" {
+			return false
+		}
+		for _, k := range keywords {
+			if keyword == k {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ValueTransform computes the new value of a matched element. Returning nil or a value
+// equal to v leaves the element untouched; any other value must have the VR of v.
+type ValueTransform func(v value.Value) (value.Value, error)
+
+// transformRule is one rule of a TransformPipeline.
+type transformRule struct {
+	match     ElementMatcher
+	transform ValueTransform
+}
+
+// TransformPipeline rewrites element values with an ordered list of rules, for
+// normalization on ingest: canonicalizing code strings, trimming whitespace, fixing
+// malformed dates and similar clean-ups.
+//
+// A TransformPipeline is not safe for concurrent modification, but a fully built
+// pipeline may be applied to several datasets concurrently.
+type TransformPipeline struct {
+	rules []transformRule
+}
+
+// NewTransformPipeline returns an empty TransformPipeline.
+//
+// Example:
+//
+//	pipeline := dicom.NewTransformPipeline().
+//	    AddRule(dicom.MatchVR(vr.CodeString), dicom.UpperCaseTransform).
+//	    AddRule(dicom.MatchVR(vr.Date), dicom.NormalizeDateTransform)
+//	counts, err := pipeline.Apply(ds)
+func NewTransformPipeline() *TransformPipeline {
+	return &TransformPipeline{}
+}
+
+// AddRule appends a rule applying transform to the elements selected by match, and
+// returns the pipeline for chaining.
+func (p *TransformPipeline) AddRule(match ElementMatcher, transform ValueTransform) *TransformPipeline {
+	p.rules = append(p.rules, transformRule{match: match, transform: transform})
+	return p
+}
+
+// Apply runs the pipeline over every element of ds, descending into the items of
+// sequences via Walk.
+//
+// Rules are applied to each element in the order they were added, each seeing the
+// value produced by the previous ones. The element is updated when a transform
+// returns a value that differs from its input (per value.Equals).
+//
+// Returns the number of elements each rule changed, indexed in rule order. Returns an
+// error, leaving ds partially transformed, if a transform fails or returns a value of
+// the wrong VR.
+//
+// Example:
+//
+//	pipeline := dicom.NewTransformPipeline().
+//	    AddRule(dicom.MatchVR(vr.CodeString), dicom.UpperCaseTransform).
+//	    AddRule(dicom.MatchKeyword("StudyDate", "SeriesDate"), dicom.NormalizeDateTransform)
+//	counts, err := pipeline.Apply(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("uppercased %d, fixed %d dates\n", counts[0], counts[1])
+func (p *TransformPipeline) Apply(ds *DataSet) ([]int, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	counts := make([]int, len(p.rules))
+	err := ds.Walk(func(path []tag.Tag, elem *element.Element) error {
+		for i, rule := range p.rules {
+			if !rule.match(path, elem) {
+				continue
+			}
+			current := elem.Value()
+			next, err := rule.transform(current)
+			if err != nil {
+				return fmt.Errorf("transform rule %d on %s: %w", i, elem.Tag(), err)
+			}
+			if next == nil || next == current || next.Equals(current) {
+				continue
+			}
+			if err := elem.SetValue(next); err != nil {
+				return fmt.Errorf("transform rule %d on %s: %w", i, elem.Tag(), err)
+			}
+			counts[i]++
+		}
+		return nil
+	})
+	return counts, err
+}
+
+// StringValueTransform adapts a function on single string values into a
+// ValueTransform. fn is applied to each value of a *value.StringValue; other values
+// are returned unchanged.
+//
+// Example:
+//
+//	stripCommas := dicom.StringValueTransform(func(s string) string {
+//	    return strings.ReplaceAll(s, ",", " ")
+//	})
+func StringValueTransform(fn func(s string) string) ValueTransform {
+	return func(v value.Value) (value.Value, error) {
+		sv, ok := v.(*value.StringValue)
+		if !ok {
+			return v, nil
+		}
+		values := sv.Strings()
+		out := make([]string, len(values))
+		for i, s := range values {
+			out[i] = fn(s)
+		}
+		return value.NewStringValue(sv.VR(), out)
+	}
+}
+
+// TrimSpaceTransform removes leading and trailing spaces from each string value.
+var TrimSpaceTransform = StringValueTransform(func(s string) string {
+	return strings.Trim(s, " ")
+})
+
+// UpperCaseTransform converts each string value to upper case, the canonical form of
+// code strings (CS).
+var UpperCaseTransform = StringValueTransform(strings.ToUpper)
+
+// malformedDatePattern matches dates written with separators, as in ACR-NEMA
+// ("2024.01.15") or ISO 8601 ("2024-01-15"), with one or two digit month and day.
+var malformedDatePattern = regexp.MustCompile(`^\s*(\d{4})[-./](\d{1,2})[-./](\d{1,2})\s*$`)
+
+// NormalizeDateTransform rewrites dates written with separators ("2024-01-15",
+// "2024/1/5" or the ACR-NEMA "2024.01.15") into the DA form "YYYYMMDD". Other values,
+// including date ranges, are left unchanged.
+//
+// A DA value is at most 8 characters, so separated dates mostly appear in DA elements
+// in their short forms ("2024-1-5"), or in DT and text elements.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2
+var NormalizeDateTransform = StringValueTransform(func(s string) string {
+	if m := malformedDatePattern.FindStringSubmatch(s); m != nil {
+		pad := func(n string) string { return strings.Repeat("0", 2-len(n)) + n }
+		return m[1] + pad(m[2]) + pad(m[3])
+	}
+	return s
+})
@@ -0,0 +1,140 @@
+package dicom_test
+
+import (
+	"errors"
+	"testing"
+
+	dicom "github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTransformDataSet returns a dataset with code strings, dates and a sequence item
+// holding another code string.
+func newTransformDataSet(t *testing.T) *dicom.DataSet {
+	ds := dicom.NewDataSet()
+	require.NoError(t, ds.Add(mustNewElement(tag.Modality, vr.CodeString,
+		mustNewStringValue(vr.CodeString, []string{"ct"}))))
+	require.NoError(t, ds.Add(mustNewElement(tag.PatientSex, vr.CodeString,
+		mustNewStringValue(vr.CodeString, []string{"M"}))))
+	require.NoError(t, ds.Add(mustNewElement(tag.StudyDate, vr.Date,
+		mustNewStringValue(vr.Date, []string{"2024-1-5"}))))
+	require.NoError(t, ds.Add(mustNewElement(tag.SeriesDate, vr.Date,
+		mustNewStringValue(vr.Date, []string{"20240115"}))))
+	require.NoError(t, ds.Add(mustNewElement(tag.StudyDescription, vr.LongString,
+		mustNewStringValue(vr.LongString, []string{"  chest ct "}))))
+
+	item := dicom.NewDataSet()
+	require.NoError(t, item.Add(mustNewElement(tag.CodingSchemeDesignator, vr.ShortString,
+		mustNewStringValue(vr.ShortString, []string{"dcm"}))))
+	require.NoError(t, item.Add(mustNewElement(tag.ValueType, vr.CodeString,
+		mustNewStringValue(vr.CodeString, []string{"text"}))))
+	seq, err := dicom.NewSequenceElement(tag.ContentSequence, []*dicom.DataSet{item})
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(seq))
+	return ds
+}
+
+// stringOf returns the string value of t in ds.
+func stringOf(t *testing.T, ds *dicom.DataSet, tg tag.Tag) string {
+	elem, err := ds.Get(tg)
+	require.NoError(t, err)
+	return elem.Value().String()
+}
+
+func TestTransformPipeline_Apply(t *testing.T) {
+	ds := newTransformDataSet(t)
+
+	counts, err := dicom.NewTransformPipeline().
+		AddRule(dicom.MatchVR(vr.CodeString), dicom.UpperCaseTransform).
+		AddRule(dicom.MatchVR(vr.Date), dicom.NormalizeDateTransform).
+		AddRule(dicom.MatchKeyword("StudyDescription"), dicom.TrimSpaceTransform).
+		Apply(ds)
+	require.NoError(t, err)
+
+	// Modality and the nested Value Type were uppercased; PatientSex already was
+	assert.Equal(t, []int{2, 1, 1}, counts)
+	assert.Equal(t, "CT", stringOf(t, ds, tag.Modality))
+	assert.Equal(t, "20240105", stringOf(t, ds, tag.StudyDate))
+	assert.Equal(t, "chest ct", stringOf(t, ds, tag.StudyDescription))
+
+	items, err := ds.GetSequenceItems(tag.ContentSequence)
+	require.NoError(t, err)
+	assert.Equal(t, "TEXT", stringOf(t, items[0], tag.ValueType))
+	assert.Equal(t, "dcm", stringOf(t, items[0], tag.CodingSchemeDesignator), "SH is not matched")
+}
+
+func TestTransformPipeline_RulesChain(t *testing.T) {
+	ds := newTransformDataSet(t)
+
+	// The second rule sees the output of the first
+	counts, err := dicom.NewTransformPipeline().
+		AddRule(dicom.MatchTag(tag.StudyDescription), dicom.TrimSpaceTransform).
+		AddRule(dicom.MatchTag(tag.StudyDescription), dicom.StringValueTransform(func(s string) string {
+			return s + "!"
+		})).
+		Apply(ds)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 1}, counts)
+	assert.Equal(t, "chest ct!", stringOf(t, ds, tag.StudyDescription))
+}
+
+func TestTransformPipeline_MatchPath(t *testing.T) {
+	ds := newTransformDataSet(t)
+
+	nestedOnly := func(path []tag.Tag, elem *element.Element) bool {
+		return len(path) == 1 && path[0] == tag.ContentSequence && elem.VR() == vr.CodeString
+	}
+	counts, err := dicom.NewTransformPipeline().AddRule(nestedOnly, dicom.UpperCaseTransform).Apply(ds)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, counts)
+	assert.Equal(t, "ct", stringOf(t, ds, tag.Modality))
+}
+
+func TestTransformPipeline_Errors(t *testing.T) {
+	_, err := dicom.NewTransformPipeline().Apply(nil)
+	assert.Error(t, err)
+
+	ds := newTransformDataSet(t)
+	boom := errors.New("boom")
+	_, err = dicom.NewTransformPipeline().
+		AddRule(dicom.MatchTag(tag.Modality), func(value.Value) (value.Value, error) { return nil, boom }).
+		Apply(ds)
+	assert.ErrorIs(t, err, boom)
+
+	wrongVR := func(value.Value) (value.Value, error) {
+		return value.NewStringValue(vr.LongString, []string{"CT"})
+	}
+	_, err = dicom.NewTransformPipeline().AddRule(dicom.MatchTag(tag.Modality), wrongVR).Apply(ds)
+	assert.Error(t, err)
+	assert.Equal(t, "ct", stringOf(t, ds, tag.Modality))
+}
+
+func TestNormalizeDateTransform(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"2024-01-15", "20240115"},
+		{"2024/01/15", "20240115"},
+		{"2024.01.15", "20240115"},
+		{"2024-1-5", "20240105"},
+		{"2024.1.15", "20240115"},
+		{"20240115", "20240115"},
+		{"20240101-20240131", "20240101-20240131"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := dicom.NormalizeDateTransform(mustNewStringValue(vr.DateTime, []string{tt.in}))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got.String(), tt.in)
+	}
+
+	// Non-string values pass through
+	ints, err := value.NewIntValue(vr.UnsignedShort, []int64{1})
+	require.NoError(t, err)
+	got, err := dicom.NormalizeDateTransform(ints)
+	require.NoError(t, err)
+	assert.Same(t, ints, got)
+}
@@ -0,0 +1,120 @@
+package uid
+
+import "strings"
+
+// SOPCategory groups Storage SOP Classes by the kind of object they store, for
+// routing instances to the right processor.
+type SOPCategory int
+
+const (
+	// CategoryOther is any SOP class not covered by a more specific category,
+	// including non-storage SOP classes and unknown UIDs.
+	CategoryOther SOPCategory = iota
+	// CategoryImageStorage is an image storage SOP class (CT, MR, US, XA, VL, ...).
+	CategoryImageStorage
+	// CategoryStructuredReport is an SR document, including Key Object Selection.
+	CategoryStructuredReport
+	// CategoryWaveform is a waveform storage SOP class (ECG, audio, respiratory, ...).
+	CategoryWaveform
+	// CategoryPresentationState is a softcopy, volumetric or waveform presentation state.
+	CategoryPresentationState
+	// CategorySegmentation is a segmentation (binary/fractional, surface, label map, ...).
+	CategorySegmentation
+	// CategoryRTObject is a radiotherapy object (structure set, plan, dose, records, ...).
+	CategoryRTObject
+)
+
+// String returns the category name.
+func (c SOPCategory) String() string {
+	switch c {
+	case CategoryImageStorage:
+		return "Image Storage"
+	case CategoryStructuredReport:
+		return "Structured Report"
+	case CategoryWaveform:
+		return "Waveform"
+	case CategoryPresentationState:
+		return "Presentation State"
+	case CategorySegmentation:
+		return "Segmentation"
+	case CategoryRTObject:
+		return "RT Object"
+	default:
+		return "Other"
+	}
+}
+
+// Storage SOP class UID roots for the second-generation RT objects.
+const (
+	rtIonAndSecondGenerationRoot = "1.2.840.10008.5.1.4.1.1.481."
+	rtDeliveryInstructionRoot    = "1.2.840.10008.5.1.4.34."
+)
+
+// imageStorageWithoutImageInName are pixel-bearing storage classes whose names do not
+// say "Image Storage".
+var imageStorageWithoutImageInName = map[string]bool{
+	ParametricMapStorage.String():    true,
+	EnhancedUSVolumeStorage.String(): true,
+}
+
+// IsStorage reports whether u is a Storage SOP Class, i.e. one whose instances are
+// transferred with C-STORE. Retired and trial storage classes are included; the Media
+// Storage Directory (DICOMDIR) class and print, query and other service classes are not.
+//
+// Example:
+//
+//	uid.IsStorage(uid.CTImageStorage)       // true
+//	uid.IsStorage(uid.VerificationSOPClass) // false
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part04.html#sect_B.5
+func IsStorage(u UID) bool {
+	info, ok := uidMap[u.value]
+	if !ok || info.Type != TypeSOPClass || u.value == MediaStorageDirectoryStorage.value {
+		return false
+	}
+	return strings.HasSuffix(info.Name, " Storage") || strings.Contains(info.Name, " Storage - ")
+}
+
+// Category returns the kind of object stored by the SOP class u.
+//
+// The category is derived from the SOP class entries in the generated dictionary, so
+// new and retired classes are covered without a hand-maintained list. Non-storage and
+// unknown UIDs return CategoryOther.
+//
+// Example:
+//
+//	switch uid.Category(sopClass) {
+//	case uid.CategoryImageStorage:
+//	    processImage(ds)
+//	case uid.CategoryStructuredReport:
+//	    processReport(ds)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part04.html#sect_B.5
+func Category(u UID) SOPCategory {
+	if !IsStorage(u) {
+		return CategoryOther
+	}
+	name := uidMap[u.value].Name
+
+	switch {
+	case strings.Contains(name, "Presentation State"):
+		return CategoryPresentationState
+	case strings.Contains(name, " SR Storage") || u.value == KeyObjectSelectionDocumentStorage.value:
+		return CategoryStructuredReport
+	case strings.Contains(name, "Waveform"):
+		return CategoryWaveform
+	case strings.Contains(name, "Segmentation"):
+		return CategorySegmentation
+	case strings.HasPrefix(name, "RT "),
+		strings.HasPrefix(u.value, rtIonAndSecondGenerationRoot),
+		strings.HasPrefix(u.value, rtDeliveryInstructionRoot):
+		return CategoryRTObject
+	case strings.Contains(name, "Image Storage"), imageStorageWithoutImageInName[u.value]:
+		return CategoryImageStorage
+	default:
+		return CategoryOther
+	}
+}
@@ -0,0 +1,63 @@
+package uid_test
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  uid.UID
+		want uid.SOPCategory
+	}{
+		{"CT image", uid.CTImageStorage, uid.CategoryImageStorage},
+		{"DX for processing", uid.DigitalXRayImageStorageForProcessing, uid.CategoryImageStorage},
+		{"retired NM image", uid.NuclearMedicineImageStorage_5, uid.CategoryImageStorage},
+		{"parametric map", uid.ParametricMapStorage, uid.CategoryImageStorage},
+		{"basic text SR", uid.BasicTextSRStorage, uid.CategoryStructuredReport},
+		{"retired trial SR", uid.TextSRStorageTrial, uid.CategoryStructuredReport},
+		{"key object selection", uid.KeyObjectSelectionDocumentStorage, uid.CategoryStructuredReport},
+		{"waveform annotation SR", uid.WaveformAnnotationSRStorage, uid.CategoryStructuredReport},
+		{"12-lead ECG", uid.UID12LeadEcgWaveformStorage, uid.CategoryWaveform},
+		{"grayscale presentation state", uid.GrayscaleSoftcopyPresentationStateStorage, uid.CategoryPresentationState},
+		{"waveform presentation state", uid.WaveformPresentationStateStorage, uid.CategoryPresentationState},
+		{"segmentation", uid.SegmentationStorage, uid.CategorySegmentation},
+		{"label map segmentation", uid.LabelMapSegmentationStorage, uid.CategorySegmentation},
+		{"RT structure set", uid.RTStructureSetStorage, uid.CategoryRTObject},
+		{"second generation RT", uid.CArmPhotonElectronRadiationStorage, uid.CategoryRTObject},
+		{"RT delivery instruction", uid.RTBeamsDeliveryInstructionStorage, uid.CategoryRTObject},
+		{"encapsulated PDF", uid.EncapsulatedPDFStorage, uid.CategoryOther},
+		{"retired standalone curve", uid.StandaloneCurveStorage, uid.CategoryOther},
+		{"verification", uid.VerificationSOPClass, uid.CategoryOther},
+		{"transfer syntax", uid.ExplicitVRLittleEndian, uid.CategoryOther},
+		{"unknown", uid.MustParse("1.2.3.4"), uid.CategoryOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, uid.Category(tt.uid), "got %s", uid.Category(tt.uid))
+		})
+	}
+}
+
+func TestIsStorage(t *testing.T) {
+	assert.True(t, uid.IsStorage(uid.CTImageStorage))
+	assert.True(t, uid.IsStorage(uid.DigitalXRayImageStorageForProcessing))
+	assert.True(t, uid.IsStorage(uid.TextSRStorageTrial))
+	assert.True(t, uid.IsStorage(uid.EncapsulatedPDFStorage))
+
+	assert.False(t, uid.IsStorage(uid.VerificationSOPClass))
+	assert.False(t, uid.IsStorage(uid.MediaStorageDirectoryStorage))
+	assert.False(t, uid.IsStorage(uid.MustParse("1.2.840.10008.1.20.1")), "Storage Commitment is a service")
+	assert.False(t, uid.IsStorage(uid.ExplicitVRLittleEndian))
+	assert.False(t, uid.IsStorage(uid.UID{}))
+}
+
+func TestSOPCategory_String(t *testing.T) {
+	assert.Equal(t, "Image Storage", uid.CategoryImageStorage.String())
+	assert.Equal(t, "RT Object", uid.CategoryRTObject.String())
+	assert.Equal(t, "Other", uid.CategoryOther.String())
+}
@@ -0,0 +1,71 @@
+package uid
+
+import (
+	"fmt"
+	"sync"
+)
+
+// defaultRoot is the organizational root used by Generate.
+const defaultRoot = "1.2.826.0.1.3680043.10"
+
+// maxGeneratorRoot is the longest root a Generator accepts: a UID may have 64
+// characters, and the suffix takes up to 32 (".", 10 seed digits, ".", 20 counter
+// digits).
+const maxGeneratorRoot = 64 - 32
+
+// Generator produces a sequence of UIDs under a fixed root.
+//
+// A deterministic generator (see NewDeterministicGenerator) yields the same sequence
+// every time it is created with the same root and seed, which makes test fixtures
+// and repeatable pseudonymization workflows reproducible. It is NOT a source of
+// globally unique UIDs: UIDs are only guaranteed to be distinct within one
+// generator's sequence, and two processes using the same root and seed produce the
+// same UIDs. Use Generate for production UIDs.
+//
+// A Generator is safe for concurrent use, although the order in which concurrent
+// callers receive UIDs is then not deterministic.
+type Generator struct {
+	mu      sync.Mutex
+	prefix  string
+	counter uint64
+}
+
+// NewDeterministicGenerator returns a Generator producing the reproducible sequence
+// of UIDs root.S.1, root.S.2, ..., where S is a number derived from seed.
+//
+// root must be a valid UID of at most 32 characters so that every generated UID fits
+// in 64; otherwise the root used by Generate is substituted.
+//
+// Example:
+//
+//	gen := uid.NewDeterministicGenerator("1.2.826.0.1.3680043.10.999", 42)
+//	study := gen.Generate()  // same value on every run
+//	series := gen.Generate() // differs from study
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_9.1
+func NewDeterministicGenerator(root string, seed int64) *Generator {
+	if !IsValid(root) || len(root) > maxGeneratorRoot {
+		root = defaultRoot
+	}
+	return &Generator{prefix: fmt.Sprintf("%s.%d.", root, seedComponent(seed))}
+}
+
+// Generate returns the next UID of the sequence.
+func (g *Generator) Generate() string {
+	g.mu.Lock()
+	g.counter++
+	n := g.counter
+	g.mu.Unlock()
+	return fmt.Sprintf("%s%d", g.prefix, n)
+}
+
+// seedComponent scrambles seed into a 32-bit UID component (at most 10 digits) with
+// the SplitMix64 finalizer, so that nearby seeds give unrelated prefixes.
+func seedComponent(seed int64) uint32 {
+	z := uint64(seed) + 0x9E3779B97F4A7C15
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	z ^= z >> 31
+	return uint32(z)
+}
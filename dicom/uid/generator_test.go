@@ -0,0 +1,79 @@
+package uid_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/uid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeterministicGenerator(t *testing.T) {
+	const root = "1.2.826.0.1.3680043.10.999"
+
+	sequence := func(seed int64, n int) []string {
+		gen := uid.NewDeterministicGenerator(root, seed)
+		uids := make([]string, n)
+		for i := range uids {
+			uids[i] = gen.Generate()
+		}
+		return uids
+	}
+
+	first := sequence(42, 100)
+	assert.Equal(t, first, sequence(42, 100), "same seed gives the same sequence")
+	assert.NotEqual(t, first[0], sequence(43, 1)[0], "different seeds give different sequences")
+
+	seen := make(map[string]bool)
+	for _, u := range first {
+		assert.True(t, uid.IsValid(u), "%q is not a valid UID", u)
+		assert.True(t, strings.HasPrefix(u, root+"."), "%q is not under the root", u)
+		assert.False(t, seen[u], "duplicate UID %q", u)
+		seen[u] = true
+	}
+}
+
+func TestNewDeterministicGenerator_Root(t *testing.T) {
+	tests := []struct {
+		name string
+		root string
+		seed int64
+	}{
+		{"invalid root", "1.2.abc", 1},
+		{"empty root", "", 1},
+		{"root too long", "1.2.840.10008.99999999.99999999.99999999", 1},
+		{"extreme seed", "1.2.3", -9223372036854775808},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := uid.NewDeterministicGenerator(tt.root, tt.seed).Generate()
+			assert.True(t, uid.IsValid(u), "%q is not a valid UID", u)
+			assert.LessOrEqual(t, len(u), 64)
+		})
+	}
+}
+
+func TestGenerator_Concurrent(t *testing.T) {
+	gen := uid.NewDeterministicGenerator("1.2.3", 7)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[string]bool)
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				u := gen.Generate()
+				mu.Lock()
+				seen[u] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Len(t, seen, 800)
+}
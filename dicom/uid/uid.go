@@ -251,7 +251,7 @@ func FindAllByType(t Type) []Info {
 func Generate() string {
 	// Use PixelMed reserved root for generated UIDs
 	// This is commonly used for DICOM implementations
-	const orgRoot = "1.2.826.0.1.3680043.10"
+	const orgRoot = defaultRoot
 
 	// Get current timestamp in microseconds
 	timestamp := time.Now().UnixMicro()
@@ -272,8 +272,8 @@ func TestBytesValue_InvalidVR(t *testing.T) {
 			name: "reject FD (float double)",
 			vr:   vr.FloatingPointDouble,
 		},
-		// Note: SQ (SequenceOfItems) is now accepted as a binary VR because
-		// sequences are skipped during parsing and represented as placeholder byte values
+		// Note: SQ (SequenceOfItems) is still accepted as a binary VR for backward
+		// compatibility; parsed sequences are represented by SequenceValue
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,120 @@
+package value
+
+import (
+	"bytes"
+	"strings"
+)
+
+// paddingInsensitiveItem is implemented by sequence items that can compare
+// themselves while ignoring value padding (*dicom.DataSet does).
+type paddingInsensitiveItem interface {
+	EqualsIgnoringPadding(other Item) bool
+}
+
+// EqualsIgnoringPadding reports whether a and b are equal once value padding is
+// disregarded. It is the comparison to use for diffing and de-duplicating files that
+// may differ only in optional padding; Equals remains a strict comparison.
+//
+// The VRs must match. Padding is handled per value type:
+//   - *StringValue: trailing padding of each value is ignored: spaces (0x20) for text
+//     VRs and NULs (0x00) for UI. Leading spaces remain significant.
+//   - *BytesValue: a single trailing pad byte (0x00 for OB, OW, UN and the other
+//     binary VRs) is ignored, since only one byte is ever added to reach even length.
+//     Further trailing zeros are data.
+//   - *SequenceValue: items are compared pairwise, recursively ignoring padding when
+//     the items support it (*dicom.DataSet does).
+//
+// Other values are compared with Equals. Two nil values are equal.
+//
+// Example:
+//
+//	a, _ := value.NewStringValue(vr.UniqueIdentifier, []string{"1.2.3"})
+//	b, _ := value.NewStringValue(vr.UniqueIdentifier, []string{"1.2.3\x00"})
+//	a.Equals(b)                       // false
+//	value.EqualsIgnoringPadding(a, b) // true
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2
+func EqualsIgnoringPadding(a, b Value) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.VR() != b.VR() {
+		return false
+	}
+
+	switch av := a.(type) {
+	case *StringValue:
+		bv, ok := b.(*StringValue)
+		if !ok {
+			return false
+		}
+		return stringsEqualIgnoringPadding(av, bv)
+	case *BytesValue:
+		bv, ok := b.(*BytesValue)
+		if !ok {
+			return false
+		}
+		return bytesEqualIgnoringPadding(av.data, bv.data, av.vr.PaddingByte())
+	case *SequenceValue:
+		bv, ok := b.(*SequenceValue)
+		if !ok || len(av.items) != len(bv.items) {
+			return false
+		}
+		for i, item := range av.items {
+			if p, ok := item.(paddingInsensitiveItem); ok {
+				if !p.EqualsIgnoringPadding(bv.items[i]) {
+					return false
+				}
+			} else if !item.Equals(bv.items[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a.Equals(b)
+	}
+}
+
+// stringsEqualIgnoringPadding compares two string values of the same VR with the
+// trailing pad characters of each value removed.
+func stringsEqualIgnoringPadding(a, b *StringValue) bool {
+	pad := string(a.vr.PaddingByte())
+	trimmed := func(values []string) []string {
+		out := make([]string, len(values))
+		for i, v := range values {
+			out[i] = strings.TrimRight(v, pad)
+		}
+		// A value holding only padding is an empty value.
+		if len(out) == 1 && out[0] == "" {
+			return nil
+		}
+		return out
+	}
+
+	av, bv := trimmed(a.values), trimmed(b.values)
+	if len(av) != len(bv) {
+		return false
+	}
+	for i := range av {
+		if av[i] != bv[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// bytesEqualIgnoringPadding compares a and b, allowing one of them to carry a single
+// extra trailing pad byte.
+func bytesEqualIgnoringPadding(a, b []byte, pad byte) bool {
+	switch {
+	case len(a) == len(b):
+		return bytes.Equal(a, b)
+	case len(a) == len(b)+1:
+		return a[len(a)-1] == pad && bytes.Equal(a[:len(b)], b)
+	case len(b) == len(a)+1:
+		return b[len(b)-1] == pad && bytes.Equal(b[:len(a)], a)
+	default:
+		return false
+	}
+}
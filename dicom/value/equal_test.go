@@ -0,0 +1,61 @@
+package value_test
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEqualsIgnoringPadding tests padding-insensitive comparison against the strict Equals
+func TestEqualsIgnoringPadding(t *testing.T) {
+	str := func(v vr.VR, values ...string) value.Value {
+		val, err := value.NewStringValue(v, values)
+		require.NoError(t, err)
+		return val
+	}
+	ints := func(v vr.VR, values ...int64) value.Value {
+		val, err := value.NewIntValue(v, values)
+		require.NoError(t, err)
+		return val
+	}
+	raw := func(v vr.VR, data ...byte) value.Value {
+		val, err := value.NewBytesValue(v, data)
+		require.NoError(t, err)
+		return val
+	}
+
+	tests := []struct {
+		name        string
+		a, b        value.Value
+		wantStrict  bool
+		wantPadding bool
+	}{
+		{"UI trailing NUL", str(vr.UniqueIdentifier, "1.2.3"), str(vr.UniqueIdentifier, "1.2.3\x00"), false, true},
+		{"UI trailing space is not padding", str(vr.UniqueIdentifier, "1.2.3"), str(vr.UniqueIdentifier, "1.2.3 "), false, false},
+		{"PN trailing space", str(vr.PersonName, "DOE^JOHN"), str(vr.PersonName, "DOE^JOHN "), false, true},
+		{"CS multi-valued", str(vr.CodeString, "ORIGINAL ", "PRIMARY"), str(vr.CodeString, "ORIGINAL", "PRIMARY "), false, true},
+		{"leading space is significant", str(vr.LongString, "A"), str(vr.LongString, " A"), false, false},
+		{"padding-only value is empty", str(vr.LongString, " "), str(vr.LongString), false, true},
+		{"different text", str(vr.LongString, "A "), str(vr.LongString, "B"), false, false},
+		{"different VR", str(vr.LongString, "A"), str(vr.ShortString, "A"), false, false},
+		{"OB trailing pad byte", raw(vr.OtherByte, 1, 2, 3), raw(vr.OtherByte, 1, 2, 3, 0), false, true},
+		{"OB two extra zeros are data", raw(vr.OtherByte, 1, 2), raw(vr.OtherByte, 1, 2, 0, 0), false, false},
+		{"OB extra non-pad byte", raw(vr.OtherByte, 1, 2, 3), raw(vr.OtherByte, 1, 2, 3, 4), false, false},
+		{"identical bytes", raw(vr.OtherByte, 1, 2), raw(vr.OtherByte, 1, 2), true, true},
+		{"ints fall back to Equals", ints(vr.UnsignedShort, 1), ints(vr.UnsignedShort, 1), true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantStrict, tt.a.Equals(tt.b))
+			assert.Equal(t, tt.wantPadding, value.EqualsIgnoringPadding(tt.a, tt.b))
+			assert.Equal(t, tt.wantPadding, value.EqualsIgnoringPadding(tt.b, tt.a))
+		})
+	}
+
+	assert.True(t, value.EqualsIgnoringPadding(nil, nil))
+	assert.False(t, value.EqualsIgnoringPadding(str(vr.LongString, "A"), nil))
+}
@@ -1,8 +1,10 @@
 package value_test
 
 import (
+	"encoding/binary"
 	"testing"
 
+	"github.com/codeninja55/go-radx/dicom/tag"
 	"github.com/codeninja55/go-radx/dicom/value"
 	"github.com/codeninja55/go-radx/dicom/vr"
 	"github.com/stretchr/testify/assert"
@@ -422,3 +424,39 @@ func TestIntValue_InvalidVR(t *testing.T) {
 		})
 	}
 }
+
+// TestIntValue_AsTags tests converting AT values to tags and back through Bytes
+func TestIntValue_AsTags(t *testing.T) {
+	tags := []tag.Tag{tag.FrameTime, tag.New(0x7FE0, 0x0010), tag.New(0x0009, 0x1001)}
+	val := value.NewTagValue(tags)
+	assert.Equal(t, vr.AttributeTag, val.VR())
+	assert.Equal(t, []int64{0x00181063, 0x7FE00010, 0x00091001}, val.Ints())
+
+	got, err := val.AsTags()
+	require.NoError(t, err)
+	assert.Equal(t, tags, got)
+
+	// Round trip through the encoded form: group then element, each little-endian
+	data := val.Bytes()
+	require.Len(t, data, 12)
+	decoded := make([]int64, 0, 3)
+	for off := 0; off < len(data); off += 4 {
+		group := binary.LittleEndian.Uint16(data[off:])
+		element := binary.LittleEndian.Uint16(data[off+2:])
+		decoded = append(decoded, int64(group)<<16|int64(element))
+	}
+	parsed, err := value.NewIntValue(vr.AttributeTag, decoded)
+	require.NoError(t, err)
+	got, err = parsed.AsTags()
+	require.NoError(t, err)
+	assert.Equal(t, tags, got)
+
+	empty, err := value.NewTagValue(nil).AsTags()
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	us, err := value.NewIntValue(vr.UnsignedShort, []int64{1})
+	require.NoError(t, err)
+	_, err = us.AsTags()
+	assert.Error(t, err)
+}
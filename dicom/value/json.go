@@ -0,0 +1,311 @@
+package value
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// The MarshalJSON methods in this file encode a value as a DICOM JSON attribute
+// object, without the enclosing tag key:
+//
+//	{"vr":"CS","Value":["ORIGINAL","PRIMARY"]}
+//
+// The "Value" (or "InlineBinary") member is omitted when the value is empty, and
+// individual empty values of a multi-valued attribute are encoded as null.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part18.html#sect_F.2
+
+// personNameGroups are the PN component group names, in their "=" separated order.
+var personNameGroups = [...]string{"Alphabetic", "Ideographic", "Phonetic"}
+
+// MarshalJSON encodes the value as a DICOM JSON attribute object.
+//
+// PN values are encoded as objects with Alphabetic, Ideographic and Phonetic
+// members. DS and IS values are encoded as JSON numbers, falling back to strings
+// for values that are not valid numbers. All other VRs are encoded as strings.
+//
+// Example:
+//
+//	val, _ := value.NewStringValue(vr.PersonName, []string{"Yamada^Tarou=山田^太郎"})
+//	data, _ := json.Marshal(val)
+//	// {"vr":"PN","Value":[{"Alphabetic":"Yamada^Tarou","Ideographic":"山田^太郎"}]}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part18.html#sect_F.2.3
+func (s *StringValue) MarshalJSON() ([]byte, error) {
+	return s.marshalJSON(true)
+}
+
+// MarshalJSONStrings encodes the value as MarshalJSON does, except that DS and IS
+// values are encoded as JSON strings rather than numbers. PS3.18 permits both forms;
+// this one is for consumers that only accept strings.
+//
+// Example:
+//
+//	val, _ := value.NewStringValue(vr.IntegerString, []string{"42"})
+//	data, _ := val.MarshalJSONStrings()
+//	// {"vr":"IS","Value":["42"]}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part18.html#sect_F.2.3.1
+func (s *StringValue) MarshalJSONStrings() ([]byte, error) {
+	return s.marshalJSON(false)
+}
+
+// marshalJSON encodes the value, with DS and IS values as JSON numbers if numbers
+// is true.
+func (s *StringValue) marshalJSON(numbers bool) ([]byte, error) {
+	values := make([]string, len(s.values))
+	empty := true
+	for i, val := range s.values {
+		values[i] = trimJSONString(s.vr, val)
+		if values[i] != "" {
+			empty = false
+		}
+	}
+
+	var buf bytes.Buffer
+	writeJSONVR(&buf, s.vr)
+	if !empty {
+		buf.WriteString(`,"Value":[`)
+		for i, val := range values {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONString(&buf, s.vr, val, numbers); err != nil {
+				return nil, err
+			}
+		}
+		buf.WriteByte(']')
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// trimJSONString removes padding that is not part of the value. Leading spaces are
+// significant for the text VRs and are kept.
+func trimJSONString(v vr.VR, s string) string {
+	s = strings.TrimRight(s, " \x00")
+	switch v {
+	case vr.LongText, vr.ShortText, vr.UnlimitedText, vr.UnlimitedCharacters:
+		return s
+	default:
+		return strings.TrimLeft(s, " ")
+	}
+}
+
+// writeJSONString writes a single string value in the form required for its VR.
+// DS and IS values are written as JSON numbers only if numbers is true.
+func writeJSONString(buf *bytes.Buffer, v vr.VR, s string, numbers bool) error {
+	if s == "" {
+		buf.WriteString("null")
+		return nil
+	}
+
+	switch {
+	case v == vr.PersonName:
+		return writeJSONPersonName(buf, s)
+	case v == vr.DecimalString && numbers:
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			buf.WriteString(jsonNumber(s, f))
+			return nil
+		}
+	case v == vr.IntegerString && numbers:
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			buf.WriteString(strconv.FormatInt(n, 10))
+			return nil
+		}
+	}
+	return writeJSONValue(buf, s)
+}
+
+// jsonNumber returns s if it is already a valid JSON number, otherwise the
+// canonical form of f (e.g. for "+1.5", ".5" or "007", which JSON does not allow).
+func jsonNumber(s string, f float64) string {
+	if json.Valid([]byte(s)) {
+		return s
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// writeJSONPersonName writes a PN value as an object of its component groups.
+// Empty component groups are omitted.
+func writeJSONPersonName(buf *bytes.Buffer, s string) error {
+	buf.WriteByte('{')
+	first := true
+	for i, group := range strings.SplitN(s, "=", len(personNameGroups)) {
+		if group == "" {
+			continue
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		buf.WriteString(`"` + personNameGroups[i] + `":`)
+		if err := writeJSONValue(buf, group); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// MarshalJSON encodes the value as a DICOM JSON attribute object with the data
+// base64 encoded in an "InlineBinary" member.
+//
+// Example:
+//
+//	val, _ := value.NewBytesValue(vr.OtherByte, []byte{0x01, 0x02})
+//	data, _ := json.Marshal(val)
+//	// {"vr":"OB","InlineBinary":"AQI="}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part18.html#sect_F.2.7
+func (b *BytesValue) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	writeJSONVR(&buf, b.vr)
+	if len(b.data) > 0 {
+		buf.WriteString(`,"InlineBinary":"`)
+		buf.WriteString(base64.StdEncoding.EncodeToString(b.data))
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON encodes the value as a DICOM JSON attribute object with a numeric
+// "Value" array. AT values are encoded as "GGGGEEEE" hexadecimal strings.
+//
+// Example:
+//
+//	val, _ := value.NewIntValue(vr.UnsignedShort, []int64{512})
+//	data, _ := json.Marshal(val)
+//	// {"vr":"US","Value":[512]}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part18.html#sect_F.2.3
+func (i *IntValue) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	writeJSONVR(&buf, i.vr)
+	if len(i.values) > 0 {
+		buf.WriteString(`,"Value":[`)
+		for j, val := range i.values {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			if i.vr == vr.AttributeTag {
+				fmt.Fprintf(&buf, `"%08X"`, uint32(val))
+				continue
+			}
+			if i.vr == vr.UnsignedVeryLong {
+				buf.WriteString(strconv.FormatUint(uint64(val), 10))
+				continue
+			}
+			buf.WriteString(strconv.FormatInt(val, 10))
+		}
+		buf.WriteByte(']')
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON encodes the value as a DICOM JSON attribute object with a numeric
+// "Value" array. Returns an error for NaN and infinite values, which JSON cannot
+// represent.
+//
+// Example:
+//
+//	val, _ := value.NewFloatValue(vr.FloatingPointDouble, []float64{0.5, 1.25})
+//	data, _ := json.Marshal(val)
+//	// {"vr":"FD","Value":[0.5,1.25]}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part18.html#sect_F.2.3
+func (f *FloatValue) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	writeJSONVR(&buf, f.vr)
+	if len(f.values) > 0 {
+		bitSize := 64
+		if f.vr == vr.FloatingPointSingle {
+			bitSize = 32
+		}
+		buf.WriteString(`,"Value":[`)
+		for i, val := range f.values {
+			if math.IsNaN(val) || math.IsInf(val, 0) {
+				return nil, fmt.Errorf("%s value %v cannot be represented in JSON", f.vr.String(), val)
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.FormatFloat(val, 'g', -1, bitSize))
+		}
+		buf.WriteByte(']')
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON encodes the sequence as a DICOM JSON attribute object whose "Value"
+// array holds one JSON object per item. Items must implement json.Marshaler, as
+// *dicom.DataSet does.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part18.html#sect_F.2.3
+func (s *SequenceValue) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	writeJSONVR(&buf, vr.SequenceOfItems)
+	if len(s.items) > 0 {
+		buf.WriteString(`,"Value":[`)
+		for i, item := range s.items {
+			m, ok := item.(json.Marshaler)
+			if !ok {
+				return nil, fmt.Errorf("sequence item %d of type %T does not implement json.Marshaler", i, item)
+			}
+			data, err := m.MarshalJSON()
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal sequence item %d: %w", i, err)
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(data)
+		}
+		buf.WriteByte(']')
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// writeJSONVR opens an attribute object with its "vr" member.
+func writeJSONVR(buf *bytes.Buffer, v vr.VR) {
+	buf.WriteString(`{"vr":"`)
+	buf.WriteString(v.String())
+	buf.WriteByte('"')
+}
+
+// writeJSONValue writes v as a JSON string.
+func writeJSONValue(buf *bytes.Buffer, v string) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// Verify the value types implement json.Marshaler at compile time
+var (
+	_ json.Marshaler = (*StringValue)(nil)
+	_ json.Marshaler = (*BytesValue)(nil)
+	_ json.Marshaler = (*IntValue)(nil)
+	_ json.Marshaler = (*FloatValue)(nil)
+	_ json.Marshaler = (*SequenceValue)(nil)
+)
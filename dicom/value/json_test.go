@@ -0,0 +1,236 @@
+package value_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringValue_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		vr     vr.VR
+		values []string
+		want   string
+	}{
+		{
+			name:   "CS multi-valued",
+			vr:     vr.CodeString,
+			values: []string{"ORIGINAL", "PRIMARY"},
+			want:   `{"vr":"CS","Value":["ORIGINAL","PRIMARY"]}`,
+		},
+		{
+			name:   "padding is trimmed",
+			vr:     vr.UniqueIdentifier,
+			values: []string{"1.2.840.10008.1.2\x00"},
+			want:   `{"vr":"UI","Value":["1.2.840.10008.1.2"]}`,
+		},
+		{
+			name:   "leading spaces kept for text",
+			vr:     vr.LongText,
+			values: []string{"  indented "},
+			want:   `{"vr":"LT","Value":["  indented"]}`,
+		},
+		{
+			name:   "empty value in multi-valued attribute is null",
+			vr:     vr.LongString,
+			values: []string{"A", "", "C"},
+			want:   `{"vr":"LO","Value":["A",null,"C"]}`,
+		},
+		{
+			name:   "empty attribute omits Value",
+			vr:     vr.ShortString,
+			values: []string{""},
+			want:   `{"vr":"SH"}`,
+		},
+		{
+			name:   "no values omits Value",
+			vr:     vr.Date,
+			values: nil,
+			want:   `{"vr":"DA"}`,
+		},
+		{
+			name:   "PN alphabetic",
+			vr:     vr.PersonName,
+			values: []string{"Doe^John"},
+			want:   `{"vr":"PN","Value":[{"Alphabetic":"Doe^John"}]}`,
+		},
+		{
+			name:   "PN component groups",
+			vr:     vr.PersonName,
+			values: []string{"Yamada^Tarou=山田^太郎=やまだ^たろう"},
+			want:   `{"vr":"PN","Value":[{"Alphabetic":"Yamada^Tarou","Ideographic":"山田^太郎","Phonetic":"やまだ^たろう"}]}`,
+		},
+		{
+			name:   "PN empty groups omitted",
+			vr:     vr.PersonName,
+			values: []string{"=山田^太郎"},
+			want:   `{"vr":"PN","Value":[{"Ideographic":"山田^太郎"}]}`,
+		},
+		{
+			name:   "DS as numbers",
+			vr:     vr.DecimalString,
+			values: []string{"1.5", " -2 ", "+3", ".25", "1e3"},
+			want:   `{"vr":"DS","Value":[1.5,-2,3,0.25,1e3]}`,
+		},
+		{
+			name:   "IS as numbers",
+			vr:     vr.IntegerString,
+			values: []string{"42", "+7", "-003"},
+			want:   `{"vr":"IS","Value":[42,7,-3]}`,
+		},
+		{
+			name:   "invalid DS falls back to string",
+			vr:     vr.DecimalString,
+			values: []string{"abc"},
+			want:   `{"vr":"DS","Value":["abc"]}`,
+		},
+		{
+			name:   "special characters are escaped",
+			vr:     vr.LongString,
+			values: []string{`say "hi"`},
+			want:   `{"vr":"LO","Value":["say \"hi\""]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := value.NewStringValue(tt.vr, tt.values)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(val)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestStringValue_MarshalJSONStrings(t *testing.T) {
+	tests := []struct {
+		vr     vr.VR
+		values []string
+		want   string
+	}{
+		{vr.DecimalString, []string{"1.5", " -2 ", "", "+3"}, `{"vr":"DS","Value":["1.5","-2",null,"+3"]}`},
+		{vr.IntegerString, []string{"42", "-003"}, `{"vr":"IS","Value":["42","-003"]}`},
+		{vr.PersonName, []string{"Doe^John"}, `{"vr":"PN","Value":[{"Alphabetic":"Doe^John"}]}`},
+		{vr.CodeString, []string{"ORIGINAL"}, `{"vr":"CS","Value":["ORIGINAL"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vr.String(), func(t *testing.T) {
+			val, err := value.NewStringValue(tt.vr, tt.values)
+			require.NoError(t, err)
+
+			data, err := val.MarshalJSONStrings()
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestBytesValue_MarshalJSON(t *testing.T) {
+	val, err := value.NewBytesValue(vr.OtherByte, []byte{0x01, 0x02, 0x03})
+	require.NoError(t, err)
+	data, err := json.Marshal(val)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"vr":"OB","InlineBinary":"AQID"}`, string(data))
+
+	empty, err := value.NewBytesValue(vr.OtherWord, nil)
+	require.NoError(t, err)
+	data, err = json.Marshal(empty)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"vr":"OW"}`, string(data))
+}
+
+func TestIntValue_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		vr     vr.VR
+		values []int64
+		want   string
+	}{
+		{"US", vr.UnsignedShort, []int64{512, 0}, `{"vr":"US","Value":[512,0]}`},
+		{"SS negative", vr.SignedShort, []int64{-100}, `{"vr":"SS","Value":[-100]}`},
+		{"UL", vr.UnsignedLong, []int64{4294967295}, `{"vr":"UL","Value":[4294967295]}`},
+		{"AT as hex string", vr.AttributeTag, []int64{0x00100010, 0x7FE00010}, `{"vr":"AT","Value":["00100010","7FE00010"]}`},
+		{"empty", vr.UnsignedShort, nil, `{"vr":"US"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := value.NewIntValue(tt.vr, tt.values)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(val)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestFloatValue_MarshalJSON(t *testing.T) {
+	val, err := value.NewFloatValue(vr.FloatingPointDouble, []float64{0.5, -1.25, 1e-10})
+	require.NoError(t, err)
+	data, err := json.Marshal(val)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"vr":"FD","Value":[0.5,-1.25,1e-10]}`, string(data))
+
+	// FL values are written with float32 precision
+	single, err := value.NewFloatValue(vr.FloatingPointSingle, []float64{float64(float32(0.1))})
+	require.NoError(t, err)
+	data, err = json.Marshal(single)
+	require.NoError(t, err)
+	assert.Equal(t, `{"vr":"FL","Value":[0.1]}`, string(data))
+
+	for _, special := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		val, err := value.NewFloatValue(vr.FloatingPointDouble, []float64{special})
+		require.NoError(t, err)
+		_, err = json.Marshal(val)
+		assert.Error(t, err, "%v should not be encodable", special)
+	}
+}
+
+// jsonItem is a minimal sequence item that marshals to a fixed object.
+type jsonItem struct {
+	json string
+}
+
+func (i jsonItem) Len() int                     { return 1 }
+func (i jsonItem) String() string               { return i.json }
+func (i jsonItem) Equals(other value.Item) bool { return i == other }
+func (i jsonItem) MarshalJSON() ([]byte, error) { return []byte(i.json), nil }
+
+// plainItem is a sequence item without a JSON encoding.
+type plainItem struct{}
+
+func (plainItem) Len() int                     { return 0 }
+func (plainItem) String() string               { return "" }
+func (plainItem) Equals(other value.Item) bool { return false }
+
+func TestSequenceValue_MarshalJSON(t *testing.T) {
+	seq, err := value.NewSequenceValue([]value.Item{
+		jsonItem{`{"00080100":{"vr":"SH","Value":["T-D1100"]}}`},
+		jsonItem{`{}`},
+	})
+	require.NoError(t, err)
+	data, err := json.Marshal(seq)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"vr":"SQ","Value":[{"00080100":{"vr":"SH","Value":["T-D1100"]}},{}]}`, string(data))
+
+	empty, err := value.NewSequenceValue(nil)
+	require.NoError(t, err)
+	data, err = json.Marshal(empty)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"vr":"SQ"}`, string(data))
+
+	unsupported, err := value.NewSequenceValue([]value.Item{plainItem{}})
+	require.NoError(t, err)
+	_, err = json.Marshal(unsupported)
+	assert.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package value
+
+import (
+	"fmt"
+
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// Item is a single item of a DICOM sequence.
+//
+// Items are nested datasets; in practice every item is a *dicom.DataSet. The
+// interface exists so that the value package does not depend on package dicom.
+// Callers type-assert items back to *dicom.DataSet:
+//
+//	for _, item := range seq.Items() {
+//	    ds := item.(*dicom.DataSet)
+//	    // ...
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.5
+type Item interface {
+	// Len returns the number of elements in the item
+	Len() int
+
+	// String returns a human-readable string representation
+	String() string
+
+	// Equals returns true if this item equals another item
+	Equals(other Item) bool
+}
+
+// SequenceValue represents a Sequence of Items (SQ) value.
+//
+// A sequence holds zero or more items, each of which is a nested dataset.
+// Sequences have no flat byte encoding independent of the transfer syntax, so
+// Bytes returns an empty slice; the dicom writer encodes items directly.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.5
+type SequenceValue struct {
+	items []Item
+}
+
+// NewSequenceValue creates a new SequenceValue holding the given items.
+// Returns an error if any item is nil.
+//
+// Example:
+//
+//	item := dicom.NewDataSet()
+//	// ... add elements to item ...
+//	seq, err := value.NewSequenceValue([]value.Item{item})
+func NewSequenceValue(items []Item) (*SequenceValue, error) {
+	for i, item := range items {
+		if item == nil {
+			return nil, fmt.Errorf("sequence item %d is nil", i)
+		}
+	}
+
+	copied := make([]Item, len(items))
+	copy(copied, items)
+
+	return &SequenceValue{items: copied}, nil
+}
+
+// VR returns the Value Representation of this value (always SQ).
+func (s *SequenceValue) VR() vr.VR {
+	return vr.SequenceOfItems
+}
+
+// Items returns the items of the sequence in order.
+func (s *SequenceValue) Items() []Item {
+	return s.items
+}
+
+// Len returns the number of items in the sequence.
+func (s *SequenceValue) Len() int {
+	return len(s.items)
+}
+
+// Bytes returns an empty slice.
+// Sequence encoding depends on the transfer syntax and is handled by the writer.
+func (s *SequenceValue) Bytes() []byte {
+	return []byte{}
+}
+
+// String returns a human-readable summary of the sequence.
+func (s *SequenceValue) String() string {
+	if len(s.items) == 1 {
+		return "[1 item]"
+	}
+	return fmt.Sprintf("[%d items]", len(s.items))
+}
+
+// Equals returns true if this value equals another value.
+// Sequences are equal if they have the same number of items and each item is
+// equal to the item at the same position.
+func (s *SequenceValue) Equals(other Value) bool {
+	otherSeq, ok := other.(*SequenceValue)
+	if !ok {
+		return false
+	}
+
+	if len(s.items) != len(otherSeq.items) {
+		return false
+	}
+
+	for i := range s.items {
+		if !s.items[i].Equals(otherSeq.items[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Verify SequenceValue implements Value interface at compile time
+var _ Value = (*SequenceValue)(nil)
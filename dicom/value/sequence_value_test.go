@@ -0,0 +1,58 @@
+package value
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testItem is a minimal Item implementation for tests.
+type testItem struct {
+	name string
+}
+
+func (i *testItem) Len() int       { return 1 }
+func (i *testItem) String() string { return i.name }
+func (i *testItem) Equals(other Item) bool {
+	o, ok := other.(*testItem)
+	return ok && o.name == i.name
+}
+
+func TestNewSequenceValue(t *testing.T) {
+	seq, err := NewSequenceValue([]Item{&testItem{"a"}, &testItem{"b"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, vr.SequenceOfItems, seq.VR())
+	assert.Equal(t, 2, seq.Len())
+	assert.Equal(t, "[2 items]", seq.String())
+	assert.Empty(t, seq.Bytes())
+
+	empty, err := NewSequenceValue(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, "[0 items]", empty.String())
+
+	single, err := NewSequenceValue([]Item{&testItem{"a"}})
+	require.NoError(t, err)
+	assert.Equal(t, "[1 item]", single.String())
+}
+
+func TestNewSequenceValue_NilItem(t *testing.T) {
+	_, err := NewSequenceValue([]Item{&testItem{"a"}, nil})
+	assert.Error(t, err)
+}
+
+func TestSequenceValue_Equals(t *testing.T) {
+	a, _ := NewSequenceValue([]Item{&testItem{"a"}, &testItem{"b"}})
+	same, _ := NewSequenceValue([]Item{&testItem{"a"}, &testItem{"b"}})
+	reordered, _ := NewSequenceValue([]Item{&testItem{"b"}, &testItem{"a"}})
+	shorter, _ := NewSequenceValue([]Item{&testItem{"a"}})
+	bytesVal, _ := NewBytesValue(vr.SequenceOfItems, nil)
+
+	assert.True(t, a.Equals(same))
+	assert.False(t, a.Equals(reordered), "item order is significant")
+	assert.False(t, a.Equals(shorter))
+	assert.False(t, a.Equals(bytesVal))
+}
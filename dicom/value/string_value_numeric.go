@@ -0,0 +1,182 @@
+package value
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// AsFloats parses every component of a Decimal String (DS) value as float64.
+//
+// Leading and trailing spaces are permitted by the standard and are ignored. Each
+// component must be a fixed point or floating point number as defined for DS, which
+// includes forms such as "+.5", "5." and "1.5E+02". Empty components, characters
+// outside the DS repertoire ("1,5", "NaN", "0x10") and incomplete exponents ("1.5E")
+// are rejected with an error naming the component, its index and the problem. Use
+// AsFloatsLax to accept common malformations.
+//
+// Example:
+//
+//	val, _ := NewStringValue(vr.DecimalString, []string{"1.5", "-2", "3e2"})
+//	floats, err := val.AsFloats()  // []float64{1.5, -2, 300}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2
+func (s *StringValue) AsFloats() ([]float64, error) {
+	if s.vr != vr.DecimalString {
+		return nil, fmt.Errorf("cannot parse VR %s as floats (expected DS)", s.vr.String())
+	}
+
+	result := make([]float64, len(s.values))
+	for i, str := range s.values {
+		f, err := parseDS(str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DS value %q at index %d: %w", str, i, err)
+		}
+		result[i] = f
+	}
+
+	return result, nil
+}
+
+// DSCoercion records a DS component that AsFloatsLax rewrote before parsing.
+type DSCoercion struct {
+	Index    int    // Index of the component in the value
+	Original string // Component as stored
+	Coerced  string // Component as parsed
+}
+
+// String describes the coercion for a warning log.
+func (c DSCoercion) String() string {
+	return fmt.Sprintf("DS value %q at index %d coerced to %q", c.Original, c.Index, c.Coerced)
+}
+
+// AsFloatsLax parses a Decimal String (DS) value like AsFloats, but rewrites
+// malformed components seen from real modalities instead of rejecting them:
+//   - an exponent marker without digits is dropped ("1.5E" and "1.5E+" become "1.5")
+//   - a single comma used as the decimal separator becomes a point ("1,5" becomes "1.5")
+//   - NUL padding is removed
+//
+// Every rewritten component is returned as a DSCoercion so callers can record a
+// warning. Components that are still invalid after rewriting return the same error
+// as AsFloats.
+//
+// Example:
+//
+//	val, _ := NewStringValue(vr.DecimalString, []string{"0.5", "1.5E"})
+//	floats, coercions, err := val.AsFloatsLax()  // []float64{0.5, 1.5}
+//	for _, c := range coercions {
+//	    log.Printf("warning: %s", c)
+//	}
+func (s *StringValue) AsFloatsLax() ([]float64, []DSCoercion, error) {
+	if s.vr != vr.DecimalString {
+		return nil, nil, fmt.Errorf("cannot parse VR %s as floats (expected DS)", s.vr.String())
+	}
+
+	result := make([]float64, len(s.values))
+	var coercions []DSCoercion
+	for i, str := range s.values {
+		f, err := parseDS(str)
+		if err != nil {
+			coerced := coerceDS(str)
+			cf, cerr := parseDS(coerced)
+			if coerced == strings.TrimSpace(str) || cerr != nil {
+				return nil, nil, fmt.Errorf("invalid DS value %q at index %d: %w", str, i, err)
+			}
+			f = cf
+			coercions = append(coercions, DSCoercion{Index: i, Original: str, Coerced: coerced})
+		}
+		result[i] = f
+	}
+
+	return result, coercions, nil
+}
+
+// dsPattern is the grammar of a DS component: a fixed or floating point number with
+// optional sign, and digits on at least one side of the decimal point.
+var dsPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// dsIncompleteExponent matches an exponent marker with no digits at the end of a
+// component.
+var dsIncompleteExponent = regexp.MustCompile(`[eE][+-]?$`)
+
+// parseDS parses one DS component, describing the problem when it is malformed.
+func parseDS(str string) (float64, error) {
+	t := strings.TrimSpace(str)
+	if !dsPattern.MatchString(t) {
+		return 0, errors.New(dsSyntaxProblem(t))
+	}
+	f, err := strconv.ParseFloat(t, 64)
+	if err != nil {
+		return 0, fmt.Errorf("out of range for float64")
+	}
+	return f, nil
+}
+
+// dsSyntaxProblem explains why t does not match dsPattern.
+func dsSyntaxProblem(t string) string {
+	if t == "" {
+		return "empty component"
+	}
+	for _, r := range t {
+		if !strings.ContainsRune("0123456789+-eE.", r) {
+			return fmt.Sprintf("invalid character %q", r)
+		}
+	}
+	mantissa, exponent := t, ""
+	if i := strings.IndexAny(t, "eE"); i >= 0 {
+		mantissa, exponent = t[:i], t[i+1:]
+		if strings.TrimLeft(exponent, "+-") == "" {
+			return "exponent has no digits"
+		}
+	}
+	if strings.Count(mantissa, ".") > 1 {
+		return "more than one decimal point"
+	}
+	if strings.Trim(mantissa, "+-.") == "" {
+		return "no digits before the exponent"
+	}
+	return "malformed number"
+}
+
+// coerceDS rewrites the malformations accepted by AsFloatsLax.
+func coerceDS(str string) string {
+	t := strings.Trim(str, " \x00")
+	if !strings.Contains(t, ".") && strings.Count(t, ",") == 1 {
+		t = strings.Replace(t, ",", ".", 1)
+	}
+	return dsIncompleteExponent.ReplaceAllString(t, "")
+}
+
+// AsInts parses every component of an Integer String (IS) value as int64.
+//
+// Leading and trailing spaces are permitted by the standard and are ignored.
+// Empty components are rejected.
+//
+// Example:
+//
+//	val, _ := NewStringValue(vr.IntegerString, []string{"1", " 42", "-7"})
+//	ints, err := val.AsInts()  // []int64{1, 42, -7}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2
+func (s *StringValue) AsInts() ([]int64, error) {
+	if s.vr != vr.IntegerString {
+		return nil, fmt.Errorf("cannot parse VR %s as ints (expected IS)", s.vr.String())
+	}
+
+	result := make([]int64, len(s.values))
+	for i, str := range s.values {
+		n, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IS value %q at index %d: %w", str, i, err)
+		}
+		result[i] = n
+	}
+
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package value
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringValue_AsFloats(t *testing.T) {
+	tests := []struct {
+		name     string
+		vr       vr.VR
+		values   []string
+		expected []float64
+		wantErr  bool
+	}{
+		{name: "single", vr: vr.DecimalString, values: []string{"1.5"}, expected: []float64{1.5}},
+		{name: "multi with spaces", vr: vr.DecimalString, values: []string{" -2.25", "3e2 ", "0"}, expected: []float64{-2.25, 300, 0}},
+		{name: "empty", vr: vr.DecimalString, values: []string{}, expected: []float64{}},
+		{name: "invalid component", vr: vr.DecimalString, values: []string{"1.0", "abc"}, wantErr: true},
+		{name: "empty component", vr: vr.DecimalString, values: []string{"1.0", ""}, wantErr: true},
+		{name: "wrong VR", vr: vr.LongString, values: []string{"1.0"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := NewStringValue(tt.vr, tt.values)
+			require.NoError(t, err)
+
+			floats, err := val.AsFloats()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, floats)
+		})
+	}
+}
+
+func TestStringValue_AsFloats_ModalityInputs(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		problem string
+	}{
+		{in: "+.5", want: 0.5},
+		{in: "-.25", want: -0.25},
+		{in: "5.", want: 5},
+		{in: "1.5E+02", want: 150},
+		{in: "1.5e-3", want: 0.0015},
+		{in: "  -1024 ", want: -1024},
+		{in: "00012.50", want: 12.5},
+		{in: "1.5E", problem: "exponent has no digits"},
+		{in: "2E-", problem: "exponent has no digits"},
+		{in: "1,5", problem: `invalid character ','`},
+		{in: "NaN", problem: `invalid character 'N'`},
+		{in: "0x10", problem: `invalid character 'x'`},
+		{in: "1.2.3", problem: "more than one decimal point"},
+		{in: ".", problem: "no digits before the exponent"},
+		{in: "1-2", problem: "malformed number"},
+		{in: "1e999", problem: "out of range for float64"},
+		{in: " ", problem: "empty component"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			val, err := NewStringValue(vr.DecimalString, []string{"0", tt.in})
+			require.NoError(t, err)
+
+			floats, err := val.AsFloats()
+			if tt.problem != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "at index 1")
+				assert.Contains(t, err.Error(), tt.problem)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []float64{0, tt.want}, floats)
+		})
+	}
+}
+
+func TestStringValue_AsFloatsLax(t *testing.T) {
+	val, err := NewStringValue(vr.DecimalString, []string{"0.5", "1.5E", "2,25", "3E+", "4\x00"})
+	require.NoError(t, err)
+
+	// The strict parser rejects the value
+	_, err = val.AsFloats()
+	assert.Error(t, err)
+
+	floats, coercions, err := val.AsFloatsLax()
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.5, 1.5, 2.25, 3, 4}, floats)
+	assert.Equal(t, []DSCoercion{
+		{Index: 1, Original: "1.5E", Coerced: "1.5"},
+		{Index: 2, Original: "2,25", Coerced: "2.25"},
+		{Index: 3, Original: "3E+", Coerced: "3"},
+		{Index: 4, Original: "4\x00", Coerced: "4"},
+	}, coercions)
+	assert.Equal(t, `DS value "1.5E" at index 1 coerced to "1.5"`, coercions[0].String())
+
+	valid, err := NewStringValue(vr.DecimalString, []string{"+.5", "1E+02"})
+	require.NoError(t, err)
+	floats, coercions, err = valid.AsFloatsLax()
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.5, 100}, floats)
+	assert.Empty(t, coercions)
+
+	for _, bad := range []string{"abc", "1,5,0", "1.5EE"} {
+		val, err := NewStringValue(vr.DecimalString, []string{bad})
+		require.NoError(t, err)
+		_, _, err = val.AsFloatsLax()
+		assert.Error(t, err, bad)
+	}
+
+	wrongVR, err := NewStringValue(vr.IntegerString, []string{"1"})
+	require.NoError(t, err)
+	_, _, err = wrongVR.AsFloatsLax()
+	assert.Error(t, err)
+}
+
+func TestStringValue_AsInts(t *testing.T) {
+	tests := []struct {
+		name     string
+		vr       vr.VR
+		values   []string
+		expected []int64
+		wantErr  bool
+	}{
+		{name: "single", vr: vr.IntegerString, values: []string{"42"}, expected: []int64{42}},
+		{name: "multi with spaces", vr: vr.IntegerString, values: []string{" 1", "-7 ", "+3"}, expected: []int64{1, -7, 3}},
+		{name: "decimal rejected", vr: vr.IntegerString, values: []string{"1.5"}, wantErr: true},
+		{name: "wrong VR", vr: vr.DecimalString, values: []string{"1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := NewStringValue(tt.vr, tt.values)
+			require.NoError(t, err)
+
+			ints, err := val.AsInts()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ints)
+		})
+	}
+}
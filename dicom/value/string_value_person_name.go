@@ -0,0 +1,157 @@
+package value
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom/charset"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// PersonNameComponents holds the five components of one Person Name (PN) component
+// group, in the order they are stored.
+type PersonNameComponents struct {
+	FamilyName string
+	GivenName  string
+	MiddleName string
+	NamePrefix string
+	NameSuffix string
+}
+
+// components returns the components in stored order.
+func (c PersonNameComponents) components() []string {
+	return []string{c.FamilyName, c.GivenName, c.MiddleName, c.NamePrefix, c.NameSuffix}
+}
+
+// String formats the components separated by carets, omitting trailing empty
+// components.
+func (c PersonNameComponents) String() string {
+	return joinTrimmed(c.components(), "^")
+}
+
+// IsEmpty returns true if every component is empty.
+func (c PersonNameComponents) IsEmpty() bool {
+	return c == PersonNameComponents{}
+}
+
+// PersonName is a decoded Person Name (PN) value with its three component groups:
+// the alphabetic (single-byte) representation, the ideographic representation, and
+// the phonetic representation.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2.1
+type PersonName struct {
+	Alphabetic  PersonNameComponents
+	Ideographic PersonNameComponents
+	Phonetic    PersonNameComponents
+}
+
+// ParsePersonName splits a decoded PN value into its component groups (separated by
+// "=") and components (separated by "^"). Missing groups and components are empty.
+//
+// Example:
+//
+//	pn := ParsePersonName("Yamada^Tarou=山田^太郎=やまだ^たろう")
+//	pn.Ideographic.FamilyName // "山田"
+func ParsePersonName(s string) PersonName {
+	groups := strings.SplitN(s, "=", 3)
+	var pn PersonName
+	for i, group := range groups {
+		parts := strings.SplitN(group, "^", 5)
+		parts = append(parts, make([]string, 5-len(parts))...)
+		c := PersonNameComponents{
+			FamilyName: parts[0],
+			GivenName:  parts[1],
+			MiddleName: parts[2],
+			NamePrefix: parts[3],
+			NameSuffix: parts[4],
+		}
+		switch i {
+		case 0:
+			pn.Alphabetic = c
+		case 1:
+			pn.Ideographic = c
+		case 2:
+			pn.Phonetic = c
+		}
+	}
+	return pn
+}
+
+// String formats the name as a PN value, omitting trailing empty components and
+// component groups.
+func (pn PersonName) String() string {
+	return joinTrimmed([]string{pn.Alphabetic.String(), pn.Ideographic.String(), pn.Phonetic.String()}, "=")
+}
+
+// Encode returns the bytes of the name as written in a dataset whose Specific
+// Character Set (0008,0005) is specificCharacterSet, including the escape sequences
+// that switch each component group between the character set's repertoires.
+//
+// Returns an error if the character set is not supported or cannot represent the
+// name.
+//
+// Example:
+//
+//	pn := ParsePersonName("Yamada^Tarou=山田^太郎=やまだ^たろう")
+//	raw, err := pn.Encode(`ISO 2022 IR 6\ISO 2022 IR 87`)
+//	val, _ := NewStringValue(vr.PersonName, []string{string(raw)})
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_H.3.1
+func (pn PersonName) Encode(specificCharacterSet string) ([]byte, error) {
+	raw, err := charset.EncodePersonName(pn.String(), specificCharacterSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode person name: %w", err)
+	}
+	return raw, nil
+}
+
+// AsPersonName decodes the StringValue as Person Name (PN) values using the
+// dataset's Specific Character Set (0008,0005), given as its backslash-separated
+// Defined Terms. Each component group is decoded with the escape sequences it
+// contains, starting from the first character set.
+//
+// Returns an error if:
+//   - The VR is not PN
+//   - The character set is not supported or a value is not valid in it
+//
+// Example:
+//
+//	names, err := val.AsPersonName(`ISO 2022 IR 6\ISO 2022 IR 87`)
+//	names[0].Ideographic.FamilyName // "山田"
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2.1
+func (s *StringValue) AsPersonName(specificCharacterSet string) ([]PersonName, error) {
+	if s.vr != vr.PersonName {
+		return nil, fmt.Errorf("cannot parse VR %s as PersonName (expected PN)", s.vr.String())
+	}
+
+	if len(s.values) == 0 {
+		return []PersonName{}, nil
+	}
+
+	// Decode the joined value rather than each value: a byte of a multi-byte
+	// character may be 0x5C, which the parser has already mistaken for a separator.
+	decoded, err := charset.DecodePersonName([]byte(s.String()), specificCharacterSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode person name: %w", err)
+	}
+
+	parts := strings.Split(decoded, "\\")
+	names := make([]PersonName, 0, len(parts))
+	for _, part := range parts {
+		names = append(names, ParsePersonName(part))
+	}
+	return names, nil
+}
+
+// joinTrimmed joins parts with sep after dropping trailing empty parts.
+func joinTrimmed(parts []string, sep string) string {
+	n := len(parts)
+	for n > 0 && parts[n-1] == "" {
+		n--
+	}
+	return strings.Join(parts[:n], sep)
+}
@@ -0,0 +1,86 @@
+package value
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParsePersonName tests splitting PN values into groups and components.
+func TestParsePersonName(t *testing.T) {
+	pn := ParsePersonName("Adams^John Robert Quincy^^Rev.^B.A. M.Div.")
+	assert.Equal(t, PersonNameComponents{
+		FamilyName: "Adams",
+		GivenName:  "John Robert Quincy",
+		NamePrefix: "Rev.",
+		NameSuffix: "B.A. M.Div.",
+	}, pn.Alphabetic)
+	assert.True(t, pn.Ideographic.IsEmpty())
+	assert.Equal(t, "Adams^John Robert Quincy^^Rev.^B.A. M.Div.", pn.String())
+
+	pn = ParsePersonName("=山田^太郎")
+	assert.True(t, pn.Alphabetic.IsEmpty())
+	assert.Equal(t, "山田", pn.Ideographic.FamilyName)
+	assert.Equal(t, "=山田^太郎", pn.String())
+
+	assert.Equal(t, "Smith", ParsePersonName("Smith^^=").String())
+}
+
+// TestStringValue_AsPersonName tests decoding PN values with code extensions.
+func TestStringValue_AsPersonName(t *testing.T) {
+	const cs = `ISO 2022 IR 6\ISO 2022 IR 87`
+	want := ParsePersonName("Yamada^Tarou=山田^太郎=やまだ^たろう")
+
+	raw, err := want.Encode(cs)
+	require.NoError(t, err)
+
+	val, err := NewStringValue(vr.PersonName, []string{string(raw), "Smith^John"})
+	require.NoError(t, err)
+
+	names, err := val.AsPersonName(cs)
+	require.NoError(t, err)
+	require.Len(t, names, 2)
+	assert.Equal(t, want, names[0])
+	assert.Equal(t, "Smith", names[1].Alphabetic.FamilyName)
+
+	t.Run("0x5C inside a multi-byte character", func(t *testing.T) {
+		// 旬 is 0x3D 0x5C in JIS X 0208; the parser splits the value on its second byte.
+		pn := ParsePersonName("=旬^太郎")
+		raw, err := pn.Encode(cs)
+		require.NoError(t, err)
+		require.Contains(t, string(raw), `\`)
+
+		val, err := NewStringValue(vr.PersonName, splitBackslash(string(raw)))
+		require.NoError(t, err)
+		names, err := val.AsPersonName(cs)
+		require.NoError(t, err)
+		assert.Equal(t, []PersonName{pn}, names)
+	})
+
+	t.Run("wrong VR", func(t *testing.T) {
+		val, err := NewStringValue(vr.LongString, []string{"Smith"})
+		require.NoError(t, err)
+		_, err = val.AsPersonName("")
+		assert.ErrorContains(t, err, "expected PN")
+	})
+
+	t.Run("unsupported character set", func(t *testing.T) {
+		_, err := val.AsPersonName("ISO_IR 999")
+		assert.ErrorContains(t, err, "unsupported specific character set")
+	})
+}
+
+// splitBackslash splits s on backslashes, as the parser splits string values.
+func splitBackslash(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/codeninja55/go-radx/dicom/tag"
 	"github.com/codeninja55/go-radx/dicom/vr"
 )
 
@@ -33,6 +34,36 @@ type Value interface {
 	Equals(other Value) bool
 }
 
+// PaddedBytes returns the encoding of v padded to even length, as it is written to a
+// DICOM stream.
+//
+// Odd-length values get one trailing pad byte chosen by VR (see vr.VR.PaddingByte):
+// NUL (0x00) for UI and the binary VRs, space (0x20) for the other string VRs. The
+// writer and dicom.EncodedLength both use PaddedBytes so that they always agree.
+// The result never aliases v's internal data when padding is added. A nil v returns
+// an empty slice.
+//
+// Example:
+//
+//	val, _ := value.NewStringValue(vr.LongString, []string{"ABC"})
+//	value.PaddedBytes(val) // []byte("ABC ")
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.1.1
+func PaddedBytes(v Value) []byte {
+	if v == nil {
+		return []byte{}
+	}
+	b := v.Bytes()
+	if len(b)%2 == 0 {
+		return b
+	}
+	padded := make([]byte, len(b)+1)
+	copy(padded, b)
+	padded[len(b)] = v.VR().PaddingByte()
+	return padded
+}
+
 // StringValue represents string-based DICOM values.
 // Supports VRs: AE, AS, CS, DA, DS, DT, IS, LO, LT, PN, SH, ST, TM, UC, UI, UR, UT
 //
@@ -142,6 +173,14 @@ func (s *StringValue) Bytes() []byte {
 
 // Equals returns true if this value equals another value.
 // Compares VR and all string values for equality.
+//
+// The comparison is position-sensitive: "ORIGINAL\PRIMARY" does not equal
+// "PRIMARY\ORIGINAL". This is correct for most attributes, where the order of values
+// carries meaning (for example the components of Image Type). Use EqualsUnordered for
+// attributes whose values form a set.
+//
+// The comparison is strict about padding: "SMITH" does not equal "SMITH ". Use
+// EqualsIgnoringPadding to disregard trailing pad characters.
 func (s *StringValue) Equals(other Value) bool {
 	// Check if other is also a StringValue
 	otherStr, ok := other.(*StringValue)
@@ -169,6 +208,54 @@ func (s *StringValue) Equals(other Value) bool {
 	return true
 }
 
+// EqualsUnordered reports whether a and b hold the same string values regardless of
+// their order, comparing them as multisets: each value must occur the same number of
+// times in both. The VRs must match.
+//
+// This is an opt-in comparison for attributes whose values are logically unordered,
+// such as lists of referenced UIDs or keywords. Most multi-valued attributes are
+// ordered (Image Type, Pixel Spacing, Image Orientation (Patient)) and must be compared
+// with Equals instead; comparing them with EqualsUnordered would, for instance, treat
+// a 0.5\0.7 mm pixel spacing as equal to 0.7\0.5 mm.
+//
+// Values that are not both *StringValue are compared with Equals. Two nil values are
+// equal.
+//
+// Example:
+//
+//	a, _ := value.NewStringValue(vr.UniqueIdentifier, []string{"1.2.3", "1.2.4"})
+//	b, _ := value.NewStringValue(vr.UniqueIdentifier, []string{"1.2.4", "1.2.3"})
+//	a.Equals(b)                  // false
+//	value.EqualsUnordered(a, b) // true
+func EqualsUnordered(a, b Value) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	aStr, aOK := a.(*StringValue)
+	bStr, bOK := b.(*StringValue)
+	if !aOK || !bOK {
+		return a.Equals(b)
+	}
+
+	if aStr.vr != bStr.vr || len(aStr.values) != len(bStr.values) {
+		return false
+	}
+
+	counts := make(map[string]int, len(aStr.values))
+	for _, v := range aStr.values {
+		counts[v]++
+	}
+	for _, v := range bStr.values {
+		if counts[v] == 0 {
+			return false
+		}
+		counts[v]--
+	}
+
+	return true
+}
+
 // Verify StringValue implements Value interface at compile time
 var _ Value = (*StringValue)(nil)
 
@@ -281,6 +368,9 @@ func (b *BytesValue) String() string {
 // Equals returns true if this value equals another value.
 // Compares VR and byte data for equality.
 // Nil and empty byte slices are considered equal.
+//
+// The comparison is byte for byte, so a value with a trailing pad byte does not
+// equal the same value without it. Use EqualsIgnoringPadding to disregard padding.
 func (b *BytesValue) Equals(other Value) bool {
 	// Check if other is also a BytesValue
 	otherBytes, ok := other.(*BytesValue)
@@ -412,6 +502,51 @@ func (i *IntValue) Ints() []int64 {
 	return i.values
 }
 
+// NewTagValue creates an AT (Attribute Tag) value holding tags.
+//
+// Example:
+//
+//	// Frame Increment Pointer referencing Frame Time
+//	val := value.NewTagValue([]tag.Tag{tag.FrameTime})
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2
+func NewTagValue(tags []tag.Tag) *IntValue {
+	values := make([]int64, len(tags))
+	for idx, t := range tags {
+		values[idx] = int64(t.Group)<<16 | int64(t.Element)
+	}
+	return &IntValue{
+		vr:     vr.AttributeTag,
+		values: values,
+	}
+}
+
+// AsTags returns the values of an AT (Attribute Tag) value as tags, splitting each
+// 32-bit value into its group (high 16 bits) and element (low 16 bits) numbers.
+// Returns an error if the VR is not AT.
+//
+// Example:
+//
+//	elem, _ := ds.Get(tag.FrameIncrementPointer)
+//	if at, ok := elem.Value().(*value.IntValue); ok {
+//	    tags, err := at.AsTags()
+//	    ...
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2
+func (i *IntValue) AsTags() ([]tag.Tag, error) {
+	if i.vr != vr.AttributeTag {
+		return nil, fmt.Errorf("cannot convert VR %s to tags: not AT", i.vr.String())
+	}
+	tags := make([]tag.Tag, len(i.values))
+	for idx, val := range i.values {
+		tags[idx] = tag.New(uint16(val>>16), uint16(val))
+	}
+	return tags, nil
+}
+
 // String returns a human-readable string representation.
 // Multiple values are separated by backslash (\).
 //
@@ -337,6 +337,103 @@ func TestStringValue_Equals(t *testing.T) {
 	}
 }
 
+// TestEqualsUnordered tests multiset comparison against the ordered Equals
+func TestEqualsUnordered(t *testing.T) {
+	tests := []struct {
+		name          string
+		vr1           vr.VR
+		vals1         []string
+		vr2           vr.VR
+		vals2         []string
+		wantOrdered   bool
+		wantUnordered bool
+	}{
+		{
+			name:          "same order",
+			vr1:           vr.UniqueIdentifier,
+			vals1:         []string{"1.2.3", "1.2.4"},
+			vr2:           vr.UniqueIdentifier,
+			vals2:         []string{"1.2.3", "1.2.4"},
+			wantOrdered:   true,
+			wantUnordered: true,
+		},
+		{
+			name:          "different order",
+			vr1:           vr.UniqueIdentifier,
+			vals1:         []string{"1.2.3", "1.2.4", "1.2.5"},
+			vr2:           vr.UniqueIdentifier,
+			vals2:         []string{"1.2.5", "1.2.3", "1.2.4"},
+			wantOrdered:   false,
+			wantUnordered: true,
+		},
+		{
+			name:          "duplicates must match in count",
+			vr1:           vr.CodeString,
+			vals1:         []string{"A", "A", "B"},
+			vr2:           vr.CodeString,
+			vals2:         []string{"A", "B", "B"},
+			wantOrdered:   false,
+			wantUnordered: false,
+		},
+		{
+			name:          "different values",
+			vr1:           vr.CodeString,
+			vals1:         []string{"A", "B"},
+			vr2:           vr.CodeString,
+			vals2:         []string{"A", "C"},
+			wantOrdered:   false,
+			wantUnordered: false,
+		},
+		{
+			name:          "different VRs",
+			vr1:           vr.CodeString,
+			vals1:         []string{"B", "A"},
+			vr2:           vr.LongString,
+			vals2:         []string{"A", "B"},
+			wantOrdered:   false,
+			wantUnordered: false,
+		},
+		{
+			name:          "different lengths",
+			vr1:           vr.CodeString,
+			vals1:         []string{"A"},
+			vr2:           vr.CodeString,
+			vals2:         []string{"A", "A"},
+			wantOrdered:   false,
+			wantUnordered: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val1, err := value.NewStringValue(tt.vr1, tt.vals1)
+			require.NoError(t, err)
+			val2, err := value.NewStringValue(tt.vr2, tt.vals2)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOrdered, val1.Equals(val2))
+			assert.Equal(t, tt.wantUnordered, value.EqualsUnordered(val1, val2))
+			assert.Equal(t, tt.wantUnordered, value.EqualsUnordered(val2, val1))
+		})
+	}
+
+	t.Run("non-string values use ordered comparison", func(t *testing.T) {
+		a, err := value.NewIntValue(vr.UnsignedShort, []int64{1, 2})
+		require.NoError(t, err)
+		b, err := value.NewIntValue(vr.UnsignedShort, []int64{2, 1})
+		require.NoError(t, err)
+		assert.False(t, value.EqualsUnordered(a, b))
+		assert.True(t, value.EqualsUnordered(a, a))
+	})
+
+	t.Run("nil values", func(t *testing.T) {
+		a, err := value.NewStringValue(vr.CodeString, []string{"A"})
+		require.NoError(t, err)
+		assert.True(t, value.EqualsUnordered(nil, nil))
+		assert.False(t, value.EqualsUnordered(a, nil))
+		assert.False(t, value.EqualsUnordered(nil, a))
+	})
+}
+
 // TestStringValue_MaxLength tests length validation
 func TestStringValue_MaxLength(t *testing.T) {
 	tests := []struct {
@@ -568,3 +665,37 @@ func TestStringValue_InvalidVR(t *testing.T) {
 		})
 	}
 }
+
+// TestPaddedBytes tests that odd-length values are padded with the VR's padding byte
+func TestPaddedBytes(t *testing.T) {
+	tests := []struct {
+		name   string
+		vr     vr.VR
+		values []string
+		want   []byte
+	}{
+		{"UI pads with NUL", vr.UniqueIdentifier, []string{"1.2.3"}, []byte("1.2.3\x00")},
+		{"LO pads with space", vr.LongString, []string{"ABC"}, []byte("ABC ")},
+		{"CS pads with space", vr.CodeString, []string{"M"}, []byte("M ")},
+		{"even length unchanged", vr.LongString, []string{"AB"}, []byte("AB")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := value.NewStringValue(tt.vr, tt.values)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value.PaddedBytes(val))
+		})
+	}
+
+	t.Run("does not modify value", func(t *testing.T) {
+		val, err := value.NewStringValue(vr.LongString, []string{"ABC"})
+		require.NoError(t, err)
+		_ = value.PaddedBytes(val)
+		assert.Equal(t, []byte("ABC"), val.Bytes())
+	})
+
+	t.Run("nil value", func(t *testing.T) {
+		assert.Empty(t, value.PaddedBytes(nil))
+	})
+}
@@ -0,0 +1,146 @@
+package dicom
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+)
+
+// lazyValueMinBytes is the smallest value the directory reader loads on demand
+// when ParseDirectoryOptions.ValueCacheBytes is set. Smaller values stay in memory
+// with the rest of the metadata.
+const lazyValueMinBytes = 1024
+
+// valueCache bounds the memory held by the lazily loaded values of the datasets
+// parsed by one ParseDirectoryWithOptions call. Once the loaded values exceed the
+// budget, the least recently loaded are unloaded, to be read from disk again on
+// their next access.
+//
+// Lock order is the cache before any element, which is safe because elements call
+// loaded without holding their own lock.
+type valueCache struct {
+	mu      sync.Mutex
+	budget  int64
+	size    int64
+	order   *list.List // *valueCacheEntry, least recently loaded first
+	entries map[*element.Element]*list.Element
+}
+
+type valueCacheEntry struct {
+	elem *element.Element
+	size int64
+}
+
+func newValueCache(budget int64) *valueCache {
+	return &valueCache{
+		budget:  budget,
+		order:   list.New(),
+		entries: make(map[*element.Element]*list.Element),
+	}
+}
+
+// loaded records that elem has loaded v and unloads the least recently loaded
+// values, other than v, until the total is within the budget.
+func (c *valueCache) loaded(elem *element.Element, v value.Value) {
+	size := int64(len(v.Bytes()))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if old, ok := c.entries[elem]; ok {
+		// Reloaded after being unloaded outside the cache
+		c.size -= old.Value.(*valueCacheEntry).size
+		c.order.Remove(old)
+	}
+	c.entries[elem] = c.order.PushBack(&valueCacheEntry{elem: elem, size: size})
+	c.size += size
+
+	for c.size > c.budget && c.order.Len() > 1 {
+		oldest := c.order.Front()
+		entry := oldest.Value.(*valueCacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.elem)
+		c.size -= entry.size
+		entry.elem.Unload()
+	}
+}
+
+// makeLazy replaces the large top-level binary values of ds, parsed from path with
+// offset tracking, by lazy elements that read them back from the file when needed.
+// The values are dropped from memory until then.
+func (c *valueCache) makeLazy(ds *DataSet, path string) error {
+	tsElem, err := ds.Get(tag.TransferSyntaxUID)
+	if err != nil {
+		return nil // No File Meta Information to reread the file with
+	}
+	ts, err := lookupTransferSyntax(tsElem.Value().String())
+	if err != nil || ts.Deflated {
+		return nil // Offsets are not tracked in deflated datasets
+	}
+	var bitsAllocated uint16
+	if bits, err := ds.GetInts(tag.BitsAllocated); err == nil && len(bits) > 0 {
+		bitsAllocated = uint16(bits[0])
+	}
+
+	for _, elem := range ds.Elements() {
+		if !isLazyVR(elem.VR()) {
+			continue
+		}
+		loc, ok := elem.Location()
+		if !ok || loc.Length-loc.HeaderLength() < lazyValueMinBytes {
+			continue
+		}
+
+		load := func() (value.Value, error) {
+			return readElementValueAt(path, ts, bitsAllocated, elem.Tag(), loc)
+		}
+		lazy, err := element.NewLazyElement(elem.Tag(), elem.VR(), load, c.loaded)
+		if err != nil {
+			return err
+		}
+		lazy.SetLocation(loc)
+		if err := ds.Set(lazy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isLazyVR reports whether values of VR v can be loaded on demand.
+func isLazyVR(v vr.VR) bool {
+	switch v {
+	case vr.OtherByte, vr.OtherWord, vr.OtherFloat, vr.OtherDouble, vr.OtherLong, vr.OtherVeryLong, vr.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// readElementValueAt reads the value of the element t found at loc in the file at
+// path, encoded with transfer syntax ts.
+func readElementValueAt(path string, ts *TransferSyntax, bitsAllocated uint16, t tag.Tag, loc element.Location) (value.Value, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	//nolint:errcheck // File close in defer for read-only operation
+	defer func() { _ = file.Close() }()
+
+	elemParser := NewElementParser(NewReader(io.NewSectionReader(file, loc.Offset, loc.Length), ts.ByteOrder), ts)
+	elemParser.bitsAllocated = bitsAllocated
+	elem, err := elemParser.ReadElement()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at offset %d of %s: %w", t, loc.Offset, path, err)
+	}
+	if elem.Tag() != t {
+		return nil, fmt.Errorf("found %s instead of %s at offset %d of %s; the file has changed", elem.Tag(), t, loc.Offset, path)
+	}
+	return elem.Value(), nil
+}
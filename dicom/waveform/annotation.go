@@ -0,0 +1,244 @@
+package waveform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/sr"
+	"github.com/codeninja55/go-radx/dicom/tag"
+)
+
+// Temporal Range Types (0040,A130) of a waveform annotation.
+const (
+	TemporalRangePoint        = "POINT"
+	TemporalRangeMultipoint   = "MULTIPOINT"
+	TemporalRangeSegment      = "SEGMENT"
+	TemporalRangeMultisegment = "MULTISEGMENT"
+	TemporalRangeBegin        = "BEGIN"
+	TemporalRangeEnd          = "END"
+)
+
+// Channel is a Channel Definition Sequence (003A,0200) item of a multiplex group.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.10.9.1
+type Channel struct {
+	Group  int // 1-based multiplex group (Waveform Sequence item) number
+	Number int // 1-based channel number within the group, or (003A,0202) if present
+
+	GroupLabel        string  // (003A,0020) Multiplex Group Label, e.g. "RHYTHM"
+	SamplingFrequency float64 // (003A,001A) Sampling Frequency of the group, in Hz
+
+	Label  string   // (003A,0203) Channel Label, e.g. "Lead II"
+	Source *sr.Code // (003A,0208) Channel Source Sequence; nil if absent
+
+	Sensitivity      *float64 // (003A,0210) Channel Sensitivity; nil if absent
+	SensitivityUnits *sr.Code // (003A,0211) Channel Sensitivity Units Sequence, e.g. uV
+}
+
+// ChannelRef identifies a channel referenced by an annotation, as a pair of 1-based
+// numbers from Referenced Waveform Channels (0040,A0B0).
+type ChannelRef struct {
+	Group   int // Multiplex group number
+	Channel int // Channel number within the group
+}
+
+// WaveformAnnotation is a Waveform Annotation Sequence (0040,B020) item.
+//
+// An annotation carries free text, a coded concept or a measurement, and optionally
+// the channels and temporal range it applies to. Only the fields present in the item
+// are set.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.10.10
+type WaveformAnnotation struct {
+	Text        string            // (0070,0006) Unformatted Text Value
+	ConceptName *sr.Code          // (0040,A043) Concept Name Code Sequence, e.g. a measurement name
+	Concept     *sr.Code          // (0040,A168) Concept Code Sequence, e.g. a coded event
+	Measurement *sr.MeasuredValue // (0040,A30A) Numeric Value with (0040,08EA) units
+
+	Channels []ChannelRef // (0040,A0B0) Referenced Waveform Channels; empty means all
+
+	TemporalRangeType string    // (0040,A130) One of the TemporalRange constants
+	SamplePositions   []int     // (0040,A132) Referenced Sample Positions, 1-based
+	TimeOffsets       []float64 // (0040,A138) Referenced Time Offsets, in seconds
+	DateTimes         []string  // (0040,A13A) Referenced DateTime values
+
+	GroupNumber int // (0040,A180) Annotation Group Number; 0 if absent
+}
+
+// Channels returns the channel definitions of every multiplex group in the Waveform
+// Sequence (5400,0100), in group then channel order.
+//
+// Returns an error if the dataset has no Waveform Sequence or a channel attribute is
+// malformed.
+//
+// Example:
+//
+//	channels, err := waveform.Channels(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, ch := range channels {
+//	    fmt.Printf("%s (%g Hz)\n", ch.Label, ch.SamplingFrequency)
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.10.9
+func Channels(ds *dicom.DataSet) ([]Channel, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	groups, err := ds.GetSequenceItems(tag.WaveformSequence)
+	if err != nil {
+		return nil, fmt.Errorf("missing Waveform Sequence: %w", err)
+	}
+
+	var channels []Channel
+	for g, group := range groups {
+		groupLabel := getString(group, tag.MultiplexGroupLabel)
+		var frequency float64
+		if f, err := group.GetFloats(tag.SamplingFrequency); err == nil && len(f) > 0 {
+			frequency = f[0]
+		}
+
+		definitions, err := group.GetSequenceItems(tag.ChannelDefinitionSequence)
+		if err != nil {
+			continue
+		}
+		for c, def := range definitions {
+			ch := Channel{
+				Group:             g + 1,
+				Number:            c + 1,
+				GroupLabel:        groupLabel,
+				SamplingFrequency: frequency,
+				Label:             getString(def, tag.ChannelLabel),
+			}
+			if n, err := def.GetInts(tag.WaveformChannelNumber); err == nil && len(n) > 0 {
+				ch.Number = int(n[0])
+			}
+			if ch.Source, err = sr.ParseCode(def, tag.ChannelSourceSequence); err != nil {
+				return nil, fmt.Errorf("group %d channel %d: %w", ch.Group, c+1, err)
+			}
+			if s, err := def.GetFloats(tag.ChannelSensitivity); err == nil && len(s) > 0 {
+				sensitivity := s[0]
+				ch.Sensitivity = &sensitivity
+			}
+			if ch.SensitivityUnits, err = sr.ParseCode(def, tag.ChannelSensitivityUnitsSequence); err != nil {
+				return nil, fmt.Errorf("group %d channel %d: %w", ch.Group, c+1, err)
+			}
+			channels = append(channels, ch)
+		}
+	}
+	return channels, nil
+}
+
+// Annotations returns the items of the Waveform Annotation Sequence (0040,B020), in
+// order. A dataset without annotations returns an empty slice.
+//
+// Returns an error if an annotation is malformed, for example with an odd number of
+// Referenced Waveform Channels values or a Numeric Value without units.
+//
+// Example:
+//
+//	annotations, err := waveform.Annotations(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, a := range annotations {
+//	    if a.Measurement != nil {
+//	        fmt.Printf("%s: %g %s\n", a.ConceptName.Meaning, a.Measurement.Value, a.Measurement.Units.Value)
+//	    }
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.10.10
+func Annotations(ds *dicom.DataSet) ([]WaveformAnnotation, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	items, err := ds.GetSequenceItems(tag.WaveformAnnotationSequence)
+	if err != nil {
+		return []WaveformAnnotation{}, nil
+	}
+
+	annotations := make([]WaveformAnnotation, 0, len(items))
+	for i, item := range items {
+		a, err := parseAnnotation(item)
+		if err != nil {
+			return nil, fmt.Errorf("waveform annotation %d: %w", i+1, err)
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations, nil
+}
+
+// parseAnnotation reads one Waveform Annotation Sequence item.
+func parseAnnotation(item *dicom.DataSet) (WaveformAnnotation, error) {
+	a := WaveformAnnotation{
+		Text:              getString(item, tag.UnformattedTextValue),
+		TemporalRangeType: getString(item, tag.TemporalRangeType),
+	}
+
+	var err error
+	if a.ConceptName, err = sr.ParseCode(item, tag.ConceptNameCodeSequence); err != nil {
+		return a, err
+	}
+	if a.Concept, err = sr.ParseCode(item, tag.ConceptCodeSequence); err != nil {
+		return a, err
+	}
+
+	if values, err := item.GetFloats(tag.NumericValue); err == nil && len(values) > 0 {
+		units, err := sr.ParseCode(item, tag.MeasurementUnitsCodeSequence)
+		if err != nil {
+			return a, err
+		}
+		if units == nil {
+			return a, fmt.Errorf("numeric value without Measurement Units Code Sequence")
+		}
+		a.Measurement = &sr.MeasuredValue{Value: values[0], Units: *units}
+	}
+
+	if refs, err := item.GetInts(tag.ReferencedWaveformChannels); err == nil {
+		if len(refs)%2 != 0 {
+			return a, fmt.Errorf("referenced waveform channels has %d values, expected pairs", len(refs))
+		}
+		for j := 0; j < len(refs); j += 2 {
+			a.Channels = append(a.Channels, ChannelRef{Group: int(refs[j]), Channel: int(refs[j+1])})
+		}
+	}
+
+	if positions, err := item.GetInts(tag.ReferencedSamplePositions); err == nil {
+		a.SamplePositions = make([]int, len(positions))
+		for j, p := range positions {
+			a.SamplePositions[j] = int(p)
+		}
+	}
+	if offsets, err := item.GetFloats(tag.ReferencedTimeOffsets); err == nil {
+		a.TimeOffsets = offsets
+	}
+	if elem, err := item.Get(tag.ReferencedDateTime); err == nil {
+		for _, dt := range strings.Split(elem.Value().String(), `\`) {
+			if dt = strings.TrimSpace(dt); dt != "" {
+				a.DateTimes = append(a.DateTimes, dt)
+			}
+		}
+	}
+
+	if group, err := item.GetInts(tag.AnnotationGroupNumber); err == nil && len(group) > 0 {
+		a.GroupNumber = int(group[0])
+	}
+	return a, nil
+}
+
+// getString returns the trimmed string value of an element, or "" if absent.
+func getString(ds *dicom.DataSet, t tag.Tag) string {
+	elem, err := ds.Get(t)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(elem.Value().String())
+}
@@ -0,0 +1,162 @@
+package waveform
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom"
+	"github.com/codeninja55/go-radx/dicom/element"
+	"github.com/codeninja55/go-radx/dicom/tag"
+	"github.com/codeninja55/go-radx/dicom/value"
+	"github.com/codeninja55/go-radx/dicom/vr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addString adds a string element to ds.
+func addString(t *testing.T, ds *dicom.DataSet, tg tag.Tag, v vr.VR, values ...string) {
+	val, err := value.NewStringValue(v, values)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, v, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+// addInts adds a binary integer element to ds.
+func addInts(t *testing.T, ds *dicom.DataSet, tg tag.Tag, v vr.VR, values ...int64) {
+	val, err := value.NewIntValue(v, values)
+	require.NoError(t, err)
+	elem, err := element.NewElement(tg, v, val)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+// addSequence adds a sequence element to ds.
+func addSequence(t *testing.T, ds *dicom.DataSet, tg tag.Tag, items ...*dicom.DataSet) {
+	elem, err := dicom.NewSequenceElement(tg, items)
+	require.NoError(t, err)
+	require.NoError(t, ds.Set(elem))
+}
+
+// addCode adds a single-item code sequence to ds.
+func addCode(t *testing.T, ds *dicom.DataSet, tg tag.Tag, codeValue, scheme, meaning string) {
+	item := dicom.NewDataSet()
+	addString(t, item, tag.CodeValue, vr.ShortString, codeValue)
+	addString(t, item, tag.CodingSchemeDesignator, vr.ShortString, scheme)
+	addString(t, item, tag.CodeMeaning, vr.LongString, meaning)
+	addSequence(t, ds, tg, item)
+}
+
+// newChannelDefinition builds a Channel Definition Sequence item.
+func newChannelDefinition(t *testing.T, label, sourceCode, sourceMeaning string) *dicom.DataSet {
+	def := dicom.NewDataSet()
+	addString(t, def, tag.ChannelLabel, vr.ShortString, label)
+	addCode(t, def, tag.ChannelSourceSequence, sourceCode, "MDC", sourceMeaning)
+	addString(t, def, tag.ChannelSensitivity, vr.DecimalString, "2.5")
+	addCode(t, def, tag.ChannelSensitivityUnitsSequence, "uV", "UCUM", "microvolt")
+	return def
+}
+
+// newTestECG builds a two-lead ECG with an R-wave marker, a measurement and a text
+// annotation.
+func newTestECG(t *testing.T) *dicom.DataSet {
+	group := dicom.NewDataSet()
+	addString(t, group, tag.MultiplexGroupLabel, vr.ShortString, "RHYTHM")
+	addString(t, group, tag.SamplingFrequency, vr.DecimalString, "500")
+	addSequence(t, group, tag.ChannelDefinitionSequence,
+		newChannelDefinition(t, "Lead I", "2:1", "Lead I"),
+		newChannelDefinition(t, "Lead II", "2:2", "Lead II"))
+
+	rWave := dicom.NewDataSet()
+	addCode(t, rWave, tag.ConceptCodeSequence, "R", "99TEST", "R wave")
+	addInts(t, rWave, tag.ReferencedWaveformChannels, vr.UnsignedShort, 1, 1, 1, 2)
+	addString(t, rWave, tag.TemporalRangeType, vr.CodeString, TemporalRangeMultipoint)
+	addInts(t, rWave, tag.ReferencedSamplePositions, vr.UnsignedLong, 120, 530)
+	addInts(t, rWave, tag.AnnotationGroupNumber, vr.UnsignedShort, 1)
+
+	heartRate := dicom.NewDataSet()
+	addCode(t, heartRate, tag.ConceptNameCodeSequence, "HR", "99TEST", "Heart rate")
+	addString(t, heartRate, tag.NumericValue, vr.DecimalString, "72")
+	addCode(t, heartRate, tag.MeasurementUnitsCodeSequence, "/min", "UCUM", "per minute")
+
+	note := dicom.NewDataSet()
+	addString(t, note, tag.UnformattedTextValue, vr.ShortText, "Sinus rhythm ")
+	addString(t, note, tag.TemporalRangeType, vr.CodeString, TemporalRangeSegment)
+	addString(t, note, tag.ReferencedTimeOffsets, vr.DecimalString, "0.0", "1.5")
+
+	ds := dicom.NewDataSet()
+	addSequence(t, ds, tag.WaveformSequence, group)
+	addSequence(t, ds, tag.WaveformAnnotationSequence, rWave, heartRate, note)
+	return ds
+}
+
+func TestChannels(t *testing.T) {
+	channels, err := Channels(newTestECG(t))
+	require.NoError(t, err)
+	require.Len(t, channels, 2)
+
+	lead := channels[1]
+	assert.Equal(t, 1, lead.Group)
+	assert.Equal(t, 2, lead.Number)
+	assert.Equal(t, "RHYTHM", lead.GroupLabel)
+	assert.Equal(t, 500.0, lead.SamplingFrequency)
+	assert.Equal(t, "Lead II", lead.Label)
+	require.NotNil(t, lead.Source)
+	assert.True(t, lead.Source.Matches("2:2", "MDC"))
+	require.NotNil(t, lead.Sensitivity)
+	assert.Equal(t, 2.5, *lead.Sensitivity)
+	require.NotNil(t, lead.SensitivityUnits)
+	assert.Equal(t, "uV", lead.SensitivityUnits.Value)
+
+	_, err = Channels(dicom.NewDataSet())
+	assert.Error(t, err)
+	_, err = Channels(nil)
+	assert.Error(t, err)
+}
+
+func TestAnnotations(t *testing.T) {
+	annotations, err := Annotations(newTestECG(t))
+	require.NoError(t, err)
+	require.Len(t, annotations, 3)
+
+	rWave := annotations[0]
+	require.NotNil(t, rWave.Concept)
+	assert.Equal(t, "R wave", rWave.Concept.Meaning)
+	assert.Equal(t, []ChannelRef{{Group: 1, Channel: 1}, {Group: 1, Channel: 2}}, rWave.Channels)
+	assert.Equal(t, TemporalRangeMultipoint, rWave.TemporalRangeType)
+	assert.Equal(t, []int{120, 530}, rWave.SamplePositions)
+	assert.Equal(t, 1, rWave.GroupNumber)
+	assert.Nil(t, rWave.Measurement)
+
+	heartRate := annotations[1]
+	require.NotNil(t, heartRate.Measurement)
+	assert.Equal(t, 72.0, heartRate.Measurement.Value)
+	assert.Equal(t, "/min", heartRate.Measurement.Units.Value)
+	assert.True(t, heartRate.ConceptName.Matches("HR", "99TEST"))
+	assert.Empty(t, heartRate.Channels, "no channels means all channels")
+
+	note := annotations[2]
+	assert.Equal(t, "Sinus rhythm", note.Text)
+	assert.Equal(t, []float64{0, 1.5}, note.TimeOffsets)
+
+	none, err := Annotations(dicom.NewDataSet())
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestAnnotations_Malformed(t *testing.T) {
+	oddChannels := dicom.NewDataSet()
+	addInts(t, oddChannels, tag.ReferencedWaveformChannels, vr.UnsignedShort, 1, 1, 2)
+	ds := dicom.NewDataSet()
+	addSequence(t, ds, tag.WaveformAnnotationSequence, oddChannels)
+	_, err := Annotations(ds)
+	assert.Error(t, err)
+
+	noUnits := dicom.NewDataSet()
+	addString(t, noUnits, tag.NumericValue, vr.DecimalString, "72")
+	addSequence(t, ds, tag.WaveformAnnotationSequence, noUnits)
+	_, err = Annotations(ds)
+	assert.Error(t, err)
+
+	_, err = Annotations(nil)
+	assert.Error(t, err)
+}
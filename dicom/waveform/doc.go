@@ -0,0 +1,38 @@
+// Package waveform provides access to the clinical context of DICOM waveform
+// objects (ECG, hemodynamic, audio and similar): channel definitions and waveform
+// annotations.
+//
+// # Channels
+//
+// Channels reads the Channel Definition Sequence of each multiplex group in the
+// Waveform Sequence, giving the lead label, the coded channel source and the
+// sensitivity needed to scale raw samples:
+//
+//	channels, err := waveform.Channels(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, ch := range channels {
+//	    fmt.Printf("group %d channel %d: %s\n", ch.Group, ch.Number, ch.Label)
+//	}
+//
+// # Annotations
+//
+// Annotations reads the Waveform Annotation Sequence, which carries measurements,
+// coded events such as R-wave markers, and free text, each attached to a temporal
+// range of referenced channels:
+//
+//	annotations, err := waveform.Annotations(ds)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, a := range annotations {
+//	    if a.Concept != nil && a.TemporalRangeType == waveform.TemporalRangePoint {
+//	        // e.g. an R-wave marker
+//	        fmt.Println(a.Concept.Meaning, "at samples", a.SamplePositions)
+//	    }
+//	}
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part03.html#sect_C.10.9
+package waveform
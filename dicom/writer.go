@@ -1,11 +1,13 @@
 package dicom
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/codeninja55/go-radx/dicom/element"
@@ -18,7 +20,10 @@ import (
 // WriteOptions configures DICOM file writing behavior.
 type WriteOptions struct {
 	// TransferSyntax specifies the transfer syntax for encoding the dataset.
-	// If nil, uses Explicit VR Little Endian (1.2.840.10008.1.2.1)
+	// If nil, uses the dataset's Transfer Syntax UID (0002,0010) when that is a
+	// compressed transfer syntax, and Explicit VR Little Endian (1.2.840.10008.1.2.1)
+	// otherwise. Pixel Data in a compressed transfer syntax cannot be written in
+	// another one.
 	TransferSyntax *uid.UID
 
 	// Overwrite allows overwriting existing files.
@@ -41,6 +46,7 @@ type WriteOptions struct {
 // WriteFile writes a DataSet to a DICOM file with proper Part 10 format.
 //
 // The function automatically generates required File Meta Information if not present:
+//   - (0002,0000) File Meta Information Group Length (recomputed on every write)
 //   - (0002,0001) File Meta Information Version
 //   - (0002,0002) Media Storage SOP Class UID (from dataset 0008,0016)
 //   - (0002,0003) Media Storage SOP Instance UID (from dataset 0008,0018)
@@ -83,6 +89,12 @@ func WriteFileWithOptions(path string, ds *DataSet, opts WriteOptions) error {
 		return fmt.Errorf("cannot write nil dataset")
 	}
 
+	// Compressed pixel data can only be written in its own transfer syntax, so that
+	// is the default for such a dataset
+	if opts.TransferSyntax == nil {
+		opts.TransferSyntax = encapsulatedTransferSyntax(ds)
+	}
+
 	// Apply default options
 	opts = applyDefaultWriteOptions(opts)
 
@@ -281,6 +293,10 @@ func writeFileDirect(path string, ds *DataSet, opts WriteOptions) (err error) {
 
 // writeDICOMFile writes the complete DICOM Part 10 file structure to a writer.
 func writeDICOMFile(w io.Writer, ds *DataSet, opts WriteOptions) error {
+	if err := checkPixelDataTransferSyntax(ds, opts.TransferSyntax); err != nil {
+		return err
+	}
+
 	// 1. Write 128-byte preamble (null bytes)
 	preamble := make([]byte, 128)
 	if _, err := w.Write(preamble); err != nil {
@@ -410,37 +426,93 @@ func generateFileMetaInformation(ds *DataSet, transferSyntax *uid.UID) (*DataSet
 
 // writeFileMetaInformation writes the File Meta Information group to a writer.
 // File Meta Information is always written in Explicit VR Little Endian.
+//
+// The (0002,0000) File Meta Information Group Length is always recomputed from the
+// encoded group rather than copied from metaInfo, so it can never go stale.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part10.html#sect_7.1
 func writeFileMetaInformation(w io.Writer, metaInfo *DataSet) error {
-	// File Meta Information is always Explicit VR Little Endian
-	// We need to write each element in the proper format
-
-	// Get all elements from metaInfo and sort by tag
-	elements := metaInfo.Elements()
+	groupLengthTag := tag.New(0x0002, 0x0000)
 
-	for _, elem := range elements {
-		if err := writeElement(w, elem, true); err != nil {
+	// Encode the group (excluding any existing group length) so we can measure it
+	var group bytes.Buffer
+	for _, elem := range metaInfo.Elements() {
+		if elem.Tag().Equals(groupLengthTag) {
+			continue
+		}
+		if err := writeElement(&group, elem, true); err != nil {
 			return fmt.Errorf("failed to write meta info element %s: %w", elem.Tag(), err)
 		}
 	}
 
+	// (0002,0000) File Meta Information Group Length
+	groupLengthValue, err := value.NewIntValue(vr.UnsignedLong, []int64{int64(group.Len())})
+	if err != nil {
+		return fmt.Errorf("failed to create group length value: %w", err)
+	}
+	groupLengthElem, err := element.NewElement(groupLengthTag, vr.UnsignedLong, groupLengthValue)
+	if err != nil {
+		return fmt.Errorf("failed to create group length element: %w", err)
+	}
+	if err := writeElement(w, groupLengthElem, true); err != nil {
+		return fmt.Errorf("failed to write meta info element %s: %w", groupLengthTag, err)
+	}
+
+	if _, err := w.Write(group.Bytes()); err != nil {
+		return fmt.Errorf("failed to write meta info group: %w", err)
+	}
+
+	return nil
+}
+
+// encapsulatedTransferSyntax returns the Transfer Syntax UID (0002,0010) of ds if it
+// is a compressed transfer syntax, whose Pixel Data is encapsulated, or nil.
+func encapsulatedTransferSyntax(ds *DataSet) *uid.UID {
+	source := datasetTransferSyntax(ds)
+	if !transferSyntaxes[source].Compressed {
+		return nil
+	}
+	ts, err := uid.Parse(source)
+	if err != nil {
+		return nil
+	}
+	return &ts
+}
+
+// checkPixelDataTransferSyntax checks that the Pixel Data of ds can be written with
+// transferSyntax. Pixel Data encoded for a compressed transfer syntax, as recorded
+// in the dataset's Transfer Syntax UID (0002,0010), can only be written in that
+// transfer syntax, and native Pixel Data read with another transfer syntax cannot be
+// written in a compressed one; both need decoding or encoding first.
+func checkPixelDataTransferSyntax(ds *DataSet, transferSyntax *uid.UID) error {
+	source := datasetTransferSyntax(ds)
+	if transferSyntax == nil || source == "" || !ds.Contains(tag.PixelData) {
+		return nil
+	}
+	target := transferSyntax.String()
+	if source != target && (transferSyntaxes[source].Compressed || transferSyntaxes[target].Compressed) {
+		return fmt.Errorf("%w: Pixel Data is encoded for transfer syntax %s and cannot be written as %s without decoding",
+			ErrInvalidTransferSyntax, source, target)
+	}
 	return nil
 }
 
 // writeDataSetElements writes all dataset elements to a writer.
 func writeDataSetElements(w io.Writer, ds *DataSet, transferSyntax *uid.UID) error {
-	// Determine if we should use explicit VR based on transfer syntax
-	useExplicitVR := isExplicitVRTransferSyntax(transferSyntax)
+	enc := datasetEncoding(ds, transferSyntax)
 
 	// Get all elements and write them
 	elements := ds.Elements()
 
 	for _, elem := range elements {
-		// Skip File Meta Information group (0002) in dataset
-		if elem.Tag().Group == 0x0002 {
+		// Skip File Meta Information group (0002) and retired dataset Group Lengths,
+		// whose values may be stale
+		if elem.Tag().Group == 0x0002 || isGroupLengthTag(elem.Tag()) {
 			continue
 		}
 
-		if err := writeElement(w, elem, useExplicitVR); err != nil {
+		if err := encodeElement(w, elem, enc); err != nil {
 			return fmt.Errorf("failed to write element %s: %w", elem.Tag(), err)
 		}
 	}
@@ -448,42 +520,123 @@ func writeDataSetElements(w io.Writer, ds *DataSet, transferSyntax *uid.UID) err
 	return nil
 }
 
+// elementEncoding describes how dataset elements are encoded by the writer.
+type elementEncoding struct {
+	explicitVR bool
+	byteOrder  binary.ByteOrder
+
+	// swapWords is set when OW, OF, OD, OL and OV values are held in the opposite
+	// byte order from byteOrder, because the dataset was read with a transfer syntax
+	// of the other endianness.
+	swapWords bool
+
+	// encapsulatedPixelData is set when the target transfer syntax is compressed, so
+	// that the top-level Pixel Data holds its fragments as items and is written with
+	// undefined length. Pixel Data in sequence items, such as icon images, is native.
+	encapsulatedPixelData bool
+}
+
+// datasetEncoding returns the encoding for writing ds with transferSyntax.
+//
+// Numeric values are held in memory as numbers and written in the target byte order.
+// The bytes of word-based binary values (OW, OF, ...) are held as they were read, in
+// the byte order of the dataset's own Transfer Syntax UID (0002,0010), or little
+// endian for a dataset built in memory without one, so they are swapped when that
+// differs from the target. UN and OB values are never swapped.
+func datasetEncoding(ds *DataSet, transferSyntax *uid.UID) elementEncoding {
+	enc := elementEncoding{
+		explicitVR: isExplicitVRTransferSyntax(transferSyntax),
+		byteOrder:  binary.LittleEndian,
+	}
+	if transferSyntax != nil {
+		enc.byteOrder = transferSyntaxByteOrder(transferSyntax.String())
+		enc.encapsulatedPixelData = transferSyntaxes[transferSyntax.String()].Compressed
+	}
+	enc.swapWords = transferSyntaxByteOrder(datasetTransferSyntax(ds)) != enc.byteOrder
+	return enc
+}
+
+// datasetTransferSyntax returns the Transfer Syntax UID recorded in ds, or "".
+func datasetTransferSyntax(ds *DataSet) string {
+	elem, err := ds.Get(tag.TransferSyntaxUID)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(strings.TrimSpace(elem.Value().String()), "\x00")
+}
+
+// transferSyntaxByteOrder returns the byte order of a transfer syntax, little endian
+// for every transfer syntax but Explicit VR Big Endian.
+func transferSyntaxByteOrder(ts string) binary.ByteOrder {
+	if props, ok := transferSyntaxes[ts]; ok && props.ByteOrder != nil {
+		return props.ByteOrder
+	}
+	return binary.LittleEndian
+}
+
 // isExplicitVRTransferSyntax determines if a transfer syntax uses explicit VR.
 func isExplicitVRTransferSyntax(ts *uid.UID) bool {
 	if ts == nil {
 		return true // Default to explicit
 	}
 
-	tsStr := ts.String()
-
-	// Implicit VR Little Endian
-	if tsStr == "1.2.840.10008.1.2" {
-		return false
+	if props, ok := transferSyntaxes[ts.String()]; ok {
+		return props.ExplicitVR
 	}
 
 	// Most other transfer syntaxes use Explicit VR
 	return true
 }
 
-// writeElement writes a single DICOM element to a writer.
+// writeElement writes a single DICOM element to a writer in little endian byte order.
 func writeElement(w io.Writer, elem *element.Element, explicitVR bool) error {
+	return encodeElement(w, elem, elementEncoding{explicitVR: explicitVR, byteOrder: binary.LittleEndian})
+}
+
+// encodeElement writes a single DICOM element to a writer with the given encoding.
+func encodeElement(w io.Writer, elem *element.Element, enc elementEncoding) error {
 	t := elem.Tag()
 	v := elem.VR()
+
+	// A value read on demand is reloaded from its file, which may have changed;
+	// writing or signing it as empty would lose it silently
+	if err := elem.Load(); err != nil {
+		return fmt.Errorf("failed to load value of %s: %w", t, err)
+	}
 	val := elem.Value()
 
 	// Write tag (group, element)
-	if err := binary.Write(w, binary.LittleEndian, t.Group); err != nil {
+	if err := binary.Write(w, enc.byteOrder, t.Group); err != nil {
 		return fmt.Errorf("failed to write tag group: %w", err)
 	}
-	if err := binary.Write(w, binary.LittleEndian, t.Element); err != nil {
+	if err := binary.Write(w, enc.byteOrder, t.Element); err != nil {
 		return fmt.Errorf("failed to write tag element: %w", err)
 	}
 
-	// Get value bytes
-	valueBytes := val.Bytes()
+	// Sequences are written with undefined length and delimited items
+	if seq, ok := val.(*value.SequenceValue); ok {
+		return writeSequence(w, v, seq, enc)
+	}
+
+	// Get value bytes, padded to even length as required by PS3.5 Section 7.1.1.
+	// String values parsed with their raw bytes retained are re-emitted as read, so
+	// their padding and length survive the round trip
+	valueBytes, ok := retainedStringBytes(elem, val)
+	if !ok {
+		valueBytes = encodeValueBytes(val, enc)
+	}
 	valueLength := uint32(len(valueBytes))
 
-	if explicitVR {
+	// Under a compressed transfer syntax, Pixel Data already holds its items and
+	// sequence delimiter and must be written with undefined length
+	if t.Equals(tag.PixelData) && enc.encapsulatedPixelData {
+		if !isEncapsulatedPixelValue(valueBytes) {
+			return fmt.Errorf("%w: Pixel Data is not encapsulated as the transfer syntax requires", ErrInvalidTransferSyntax)
+		}
+		valueLength = undefinedLength
+	}
+
+	if enc.explicitVR {
 		// Write VR (2 bytes)
 		vrBytes := []byte(v.String())
 		if len(vrBytes) != 2 {
@@ -493,18 +646,14 @@ func writeElement(w io.Writer, elem *element.Element, explicitVR bool) error {
 			return fmt.Errorf("failed to write VR: %w", err)
 		}
 
-		// Check if VR needs 4-byte length (OB, OD, OF, OL, OW, SQ, UC, UN, UR, UT)
-		needsLongLength := v == vr.OtherByte || v == vr.OtherDouble || v == vr.OtherFloat || v == vr.OtherLong ||
-			v == vr.OtherWord || v == vr.SequenceOfItems || v == vr.UnlimitedCharacters || v == vr.Unknown ||
-			v == vr.UniversalResourceIdentifier || v == vr.UnlimitedText
-
-		if needsLongLength {
+		// Check if VR needs 4-byte length (OB, OD, OF, OL, OV, OW, SQ, UC, UN, UR, UT)
+		if v.UsesExplicitLength32() {
 			// Write 2 reserved bytes (0x0000)
-			if err := binary.Write(w, binary.LittleEndian, uint16(0)); err != nil {
+			if err := binary.Write(w, enc.byteOrder, uint16(0)); err != nil {
 				return fmt.Errorf("failed to write reserved bytes: %w", err)
 			}
 			// Write 4-byte length
-			if err := binary.Write(w, binary.LittleEndian, valueLength); err != nil {
+			if err := binary.Write(w, enc.byteOrder, valueLength); err != nil {
 				return fmt.Errorf("failed to write value length: %w", err)
 			}
 		} else {
@@ -512,13 +661,13 @@ func writeElement(w io.Writer, elem *element.Element, explicitVR bool) error {
 			if valueLength > 0xFFFF {
 				return fmt.Errorf("value length %d exceeds 2-byte limit for VR %s", valueLength, v.String())
 			}
-			if err := binary.Write(w, binary.LittleEndian, uint16(valueLength)); err != nil {
+			if err := binary.Write(w, enc.byteOrder, uint16(valueLength)); err != nil {
 				return fmt.Errorf("failed to write value length: %w", err)
 			}
 		}
 	} else {
 		// Implicit VR: just write 4-byte length
-		if err := binary.Write(w, binary.LittleEndian, valueLength); err != nil {
+		if err := binary.Write(w, enc.byteOrder, valueLength); err != nil {
 			return fmt.Errorf("failed to write value length: %w", err)
 		}
 	}
@@ -532,3 +681,161 @@ func writeElement(w io.Writer, elem *element.Element, explicitVR bool) error {
 
 	return nil
 }
+
+// retainedStringBytes returns the raw bytes elem was parsed from, if it holds a
+// string value that they still encode: an even number of bytes that differ from
+// the value only by trailing NUL or space padding. String bytes do not depend on
+// byte order, so they can be written under any transfer syntax.
+//
+// This keeps values such as a UI padded with a space instead of NUL, or with more
+// than one byte of padding, byte-for-byte identical when a dataset parsed with
+// ParseOptions.RetainRawValues is written again, so digital signatures computed
+// over them still verify. Elements whose value was replaced have no raw bytes.
+func retainedStringBytes(elem *element.Element, val value.Value) ([]byte, bool) {
+	strVal, ok := val.(*value.StringValue)
+	if !ok {
+		return nil, false
+	}
+	raw := elem.RawBytes()
+	if raw == nil || len(raw)%2 != 0 {
+		return nil, false
+	}
+	if strings.TrimRight(string(raw), "\x00 ") != strings.Join(strVal.Strings(), "\\") {
+		return nil, false
+	}
+	return raw, true
+}
+
+// encodeValueBytes returns the padded bytes of val in the byte order of enc.
+//
+// Numeric values (US, SS, UL, SL, FL, FD, SV, UV, AT) are encoded little endian by
+// Value.Bytes and swapped for a big endian target. Word-based binary values are
+// swapped only when enc.swapWords is set. UN, OB and string values are written
+// exactly as held.
+func encodeValueBytes(val value.Value, enc elementEncoding) []byte {
+	valueBytes := value.PaddedBytes(val)
+	if val == nil || len(valueBytes) == 0 {
+		return valueBytes
+	}
+
+	_, numeric := val.(*value.IntValue)
+	if _, ok := val.(*value.FloatValue); ok {
+		numeric = true
+	}
+	swap := enc.swapWords
+	if numeric {
+		swap = enc.byteOrder == binary.BigEndian
+	}
+	size := wordSize(val.VR())
+	if !swap || size < 2 || len(valueBytes)%size != 0 {
+		return valueBytes
+	}
+
+	swapped := make([]byte, len(valueBytes))
+	copy(swapped, valueBytes)
+	swapWordBytes(swapped, size)
+	return swapped
+}
+
+// wordSize returns the size in bytes of the units whose byte order depends on the
+// transfer syntax for values of VR v, or 1 for VRs that are byte streams (including
+// UN, whose structure is unknown).
+func wordSize(v vr.VR) int {
+	switch v {
+	case vr.UnsignedShort, vr.SignedShort, vr.OtherWord, vr.AttributeTag:
+		return 2
+	case vr.UnsignedLong, vr.SignedLong, vr.FloatingPointSingle, vr.OtherFloat, vr.OtherLong:
+		return 4
+	case vr.FloatingPointDouble, vr.OtherDouble, vr.OtherVeryLong, vr.SignedVeryLong, vr.UnsignedVeryLong:
+		return 8
+	default:
+		return 1
+	}
+}
+
+// swapWordBytes reverses the byte order of each size-byte word of b in place.
+func swapWordBytes(b []byte, size int) {
+	for i := 0; i+size <= len(b); i += size {
+		slices.Reverse(b[i : i+size])
+	}
+}
+
+// isEncapsulatedPixelValue reports whether a Pixel Data value is in encapsulated
+// format: it starts with an Item (the Basic Offset Table) and ends with a Sequence
+// Delimitation Item.
+func isEncapsulatedPixelValue(b []byte) bool {
+	if len(b) < 16 {
+		return false
+	}
+	tagAt := func(off int) uint32 {
+		return uint32(binary.LittleEndian.Uint16(b[off:]))<<16 | uint32(binary.LittleEndian.Uint16(b[off+2:]))
+	}
+	end := len(b) - 8
+	return tagAt(0) == itemTagValue && tagAt(end) == sequenceDelimitationTagValue &&
+		binary.LittleEndian.Uint32(b[end+4:]) == 0
+}
+
+// writeSequence writes the VR/length header and items of a sequence element whose
+// tag has already been written.
+//
+// Sequences and their items are always written with undefined length, terminated by
+// Item Delimitation (FFFE,E00D) and Sequence Delimitation (FFFE,E0DD) items. This
+// avoids having to pre-compute nested lengths.
+//
+// DICOM Standard Reference:
+// https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_7.5
+func writeSequence(w io.Writer, v vr.VR, seq *value.SequenceValue, enc elementEncoding) error {
+	if enc.explicitVR {
+		if _, err := w.Write([]byte(v.String())); err != nil {
+			return fmt.Errorf("failed to write VR: %w", err)
+		}
+		if err := binary.Write(w, enc.byteOrder, uint16(0)); err != nil {
+			return fmt.Errorf("failed to write reserved bytes: %w", err)
+		}
+	}
+	if err := binary.Write(w, enc.byteOrder, undefinedLength); err != nil {
+		return fmt.Errorf("failed to write sequence length: %w", err)
+	}
+
+	// Only the top-level Pixel Data is encapsulated; Pixel Data in items is native
+	enc.encapsulatedPixelData = false
+
+	for i, item := range seq.Items() {
+		itemDS, ok := item.(*DataSet)
+		if !ok {
+			return fmt.Errorf("sequence item %d has unsupported type %T", i, item)
+		}
+
+		if err := writeDelimiter(w, enc.byteOrder, itemTagValue, undefinedLength); err != nil {
+			return fmt.Errorf("failed to write item %d: %w", i, err)
+		}
+		for _, elem := range itemDS.Elements() {
+			if isGroupLengthTag(elem.Tag()) {
+				continue
+			}
+			if err := encodeElement(w, elem, enc); err != nil {
+				return fmt.Errorf("failed to write element %s in item %d: %w", elem.Tag(), i, err)
+			}
+		}
+		if err := writeDelimiter(w, enc.byteOrder, itemDelimitationTagValue, 0); err != nil {
+			return fmt.Errorf("failed to write item %d delimiter: %w", i, err)
+		}
+	}
+
+	if err := writeDelimiter(w, enc.byteOrder, sequenceDelimitationTagValue, 0); err != nil {
+		return fmt.Errorf("failed to write sequence delimiter: %w", err)
+	}
+
+	return nil
+}
+
+// writeDelimiter writes an item or delimitation tag (group FFFE) followed by a 4-byte length.
+func writeDelimiter(w io.Writer, order binary.ByteOrder, tagValue, length uint32) error {
+	if err := binary.Write(w, order, uint16(tagValue>>16)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, order, uint16(tagValue)); err != nil {
+		return err
+	}
+	return binary.Write(w, order, length)
+}
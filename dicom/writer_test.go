@@ -1,6 +1,8 @@
 package dicom
 
 import (
+	"bytes"
+	"encoding/binary"
 	"os"
 	"path/filepath"
 	"testing"
@@ -276,6 +278,90 @@ func TestWriteFile_FileMetaInformation(t *testing.T) {
 	verifyFileMetaElement(t, writtenDS, tag.New(0x0002, 0x0013)) // Implementation Version Name
 }
 
+// TestWriteFile_GroupLength tests that (0002,0000) is written and matches the encoded group.
+func TestWriteFile_GroupLength(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "group_length.dcm")
+
+	ds := createTestDatasetForWriter(t)
+	require.NoError(t, WriteFile(outputPath, ds))
+
+	// Strict parsing validates the group length against the encoded group
+	writtenDS, err := ParseFile(outputPath)
+	require.NoError(t, err)
+
+	meta, err := FileMetaInfo(writtenDS)
+	require.NoError(t, err)
+	assert.True(t, meta.HasGroupLength)
+	assert.Greater(t, meta.GroupLength, uint32(0))
+}
+
+// TestWriteFile_StripsDatasetGroupLengths tests that a file carrying retired dataset
+// Group Length elements can be stripped and re-written as a valid file.
+func TestWriteFile_StripsDatasetGroupLengths(t *testing.T) {
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeDICOMFile(buf, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+
+	// Append a stale (0020,0000) UL Group Length to the encoded dataset
+	groupLength := make([]byte, 12)
+	binary.LittleEndian.PutUint16(groupLength[0:2], 0x0020)
+	binary.LittleEndian.PutUint16(groupLength[2:4], 0x0000)
+	copy(groupLength[4:6], "UL")
+	binary.LittleEndian.PutUint16(groupLength[6:8], 4)
+	binary.LittleEndian.PutUint32(groupLength[8:12], 9999)
+	data := append(buf.Bytes(), groupLength...)
+
+	ds, err := ParseReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.True(t, ds.Contains(tag.New(0x0020, 0x0000)))
+
+	require.NoError(t, ds.RemoveGroupLengths())
+	assert.False(t, ds.Contains(tag.New(0x0020, 0x0000)))
+	assert.True(t, ds.Contains(tag.New(0x0002, 0x0000)), "File Meta group length is kept")
+
+	outputPath := filepath.Join(t.TempDir(), "stripped.dcm")
+	require.NoError(t, WriteFile(outputPath, ds))
+
+	// Strict parsing validates the recomputed File Meta group length
+	parsed, err := ParseFile(outputPath)
+	require.NoError(t, err)
+	assert.False(t, parsed.Contains(tag.New(0x0020, 0x0000)))
+	verifyElementsMatch(t, ds, parsed, tag.StudyInstanceUID)
+
+	meta, err := FileMetaInfo(parsed)
+	require.NoError(t, err)
+	assert.True(t, meta.HasGroupLength)
+}
+
+// TestWriteFile_NeverWritesDatasetGroupLengths tests that the writer drops dataset
+// Group Length elements even when they have not been stripped.
+func TestWriteFile_NeverWritesDatasetGroupLengths(t *testing.T) {
+	ds := createTestDatasetForWriter(t)
+
+	staleValue, err := value.NewIntValue(vr.UnsignedLong, []int64{1})
+	require.NoError(t, err)
+	staleElem, err := element.NewElement(tag.New(0x0008, 0x0000), vr.UnsignedLong, staleValue)
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(staleElem))
+
+	item := NewDataSet()
+	require.NoError(t, item.Add(staleElem))
+	seqElem, err := NewSequenceElement(tag.ReferencedImageSequence, []*DataSet{item})
+	require.NoError(t, err)
+	require.NoError(t, ds.Add(seqElem))
+
+	outputPath := filepath.Join(t.TempDir(), "no_group_length.dcm")
+	require.NoError(t, WriteFile(outputPath, ds))
+
+	parsed, err := ParseFile(outputPath)
+	require.NoError(t, err)
+	assert.False(t, parsed.Contains(tag.New(0x0008, 0x0000)))
+
+	items, err := parsed.GetSequenceItems(tag.ReferencedImageSequence)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, 0, items[0].Len())
+}
+
 // TestWriteFile_MultipleFiles tests writing multiple files sequentially.
 func TestWriteFile_MultipleFiles(t *testing.T) {
 	tempDir := t.TempDir()
@@ -393,3 +479,263 @@ func createTestDatasetForWriter(t *testing.T) *DataSet {
 
 	return ds
 }
+
+// TestWriteFile_SequenceRoundTrip tests that nested sequences survive a write/parse cycle.
+func TestWriteFile_SequenceRoundTrip(t *testing.T) {
+	for _, ts := range []uid.UID{uid.ExplicitVRLittleEndian, uid.ImplicitVRLittleEndian} {
+		t.Run(ts.String(), func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "sequence.dcm")
+			ds := createTestDatasetForWriter(t)
+
+			// Nested: ReferencedImageSequence -> item -> ReferencedSOPInstanceUID,
+			// plus a nested empty sequence inside the item
+			item := NewDataSet()
+			refValue, err := value.NewStringValue(vr.UniqueIdentifier, []string{"1.2.3.4.5"})
+			require.NoError(t, err)
+			refElem, err := element.NewElement(tag.ReferencedSOPInstanceUID, vr.UniqueIdentifier, refValue)
+			require.NoError(t, err)
+			require.NoError(t, item.Add(refElem))
+			emptySeq, err := NewSequenceElement(tag.PurposeOfReferenceCodeSequence, nil)
+			require.NoError(t, err)
+			require.NoError(t, item.Add(emptySeq))
+
+			seqElem, err := NewSequenceElement(tag.ReferencedImageSequence, []*DataSet{item, item.Copy()})
+			require.NoError(t, err)
+			require.NoError(t, ds.Add(seqElem))
+
+			tsCopy := ts
+			require.NoError(t, WriteFileWithOptions(outputPath, ds, WriteOptions{TransferSyntax: &tsCopy}))
+
+			parsed, err := ParseFile(outputPath)
+			require.NoError(t, err)
+
+			parsedSeq, err := parsed.Get(tag.ReferencedImageSequence)
+			require.NoError(t, err)
+			assert.True(t, seqElem.Equals(parsedSeq), "sequence should round-trip")
+
+			// Elements after the sequence are still parsed
+			verifyElementsMatch(t, ds, parsed, tag.StudyInstanceUID)
+		})
+	}
+}
+
+// TestWriteElement_EncapsulatedPixelData tests that Pixel Data is written with
+// undefined length under a compressed transfer syntax and with its actual length
+// otherwise, whatever its content.
+func TestWriteElement_EncapsulatedPixelData(t *testing.T) {
+	encapsulated := []byte{
+		0xFE, 0xFF, 0x00, 0xE0, 0x00, 0x00, 0x00, 0x00, // Basic Offset Table (empty)
+		0xFE, 0xFF, 0x00, 0xE0, 0x02, 0x00, 0x00, 0x00, 0xFF, 0xD8, // Fragment
+		0xFE, 0xFF, 0xDD, 0xE0, 0x00, 0x00, 0x00, 0x00, // Sequence Delimitation Item
+	}
+	jpegBaseline := uid.JPEGBaselineProcess1
+	explicitLE := uid.ExplicitVRLittleEndian
+
+	tests := []struct {
+		name           string
+		data           []byte
+		transferSyntax *uid.UID
+		wantLength     uint32
+		wantErr        bool
+	}{
+		{"encapsulated", encapsulated, &jpegBaseline, undefinedLength, false},
+		{"native", make([]byte, 26), &explicitLE, 26, false},
+		{"native starting with an item tag", encapsulated, &explicitLE, uint32(len(encapsulated)), false},
+		{"native under a compressed transfer syntax", make([]byte, 26), &jpegBaseline, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := value.NewBytesValue(vr.OtherByte, tt.data)
+			require.NoError(t, err)
+			elem, err := element.NewElement(tag.PixelData, vr.OtherByte, val)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			err = encodeElement(&buf, elem, datasetEncoding(NewDataSet(), tt.transferSyntax))
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidTransferSyntax)
+				return
+			}
+			require.NoError(t, err)
+
+			// tag (4) + VR (2) + reserved (2) + length (4) + value
+			out := buf.Bytes()
+			require.Len(t, out, 12+len(tt.data))
+			assert.Equal(t, tt.wantLength, binary.LittleEndian.Uint32(out[8:12]))
+		})
+	}
+}
+
+// TestWriteFileWithOptions_PixelDataTransferSyntax tests that a dataset holding
+// compressed Pixel Data is written in the transfer syntax recorded in it, and that
+// writing it in another transfer syntax fails.
+func TestWriteFileWithOptions_PixelDataTransferSyntax(t *testing.T) {
+	newCompressedDataSet := func(t *testing.T) *DataSet {
+		ds := createTestDatasetForWriter(t)
+		encapsulated := []byte{
+			0xFE, 0xFF, 0x00, 0xE0, 0x00, 0x00, 0x00, 0x00,
+			0xFE, 0xFF, 0x00, 0xE0, 0x02, 0x00, 0x00, 0x00, 0xFF, 0xD8,
+			0xFE, 0xFF, 0xDD, 0xE0, 0x00, 0x00, 0x00, 0x00,
+		}
+		val, err := value.NewBytesValue(vr.OtherByte, encapsulated)
+		require.NoError(t, err)
+		elem, err := element.NewElement(tag.PixelData, vr.OtherByte, val)
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(elem))
+
+		tsVal, err := value.NewStringValue(vr.UniqueIdentifier, []string{uid.JPEGBaselineProcess1.String()})
+		require.NoError(t, err)
+		tsElem, err := element.NewElement(tag.TransferSyntaxUID, vr.UniqueIdentifier, tsVal)
+		require.NoError(t, err)
+		require.NoError(t, ds.Add(tsElem))
+		return ds
+	}
+
+	t.Run("defaults to the dataset transfer syntax", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "compressed.dcm")
+		require.NoError(t, WriteFile(path, newCompressedDataSet(t)))
+
+		parsed, err := ParseFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, uid.JPEGBaselineProcess1.String(), datasetTransferSyntax(parsed))
+	})
+
+	t.Run("rejects another transfer syntax", func(t *testing.T) {
+		explicitLE := uid.ExplicitVRLittleEndian
+		path := filepath.Join(t.TempDir(), "native.dcm")
+		err := WriteFileWithOptions(path, newCompressedDataSet(t), WriteOptions{TransferSyntax: &explicitLE})
+		require.ErrorIs(t, err, ErrInvalidTransferSyntax)
+	})
+}
+
+// TestWriteElement_Padding tests that odd-length values are padded by VR and that the
+// written size matches EncodedLength.
+func TestWriteElement_Padding(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     tag.Tag
+		vr      vr.VR
+		value   string
+		wantPad byte
+	}{
+		{"UI pads with NUL", tag.SOPInstanceUID, vr.UniqueIdentifier, "1.2.3", 0x00},
+		{"LO pads with space", tag.InstitutionName, vr.LongString, "ABC", 0x20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := value.NewStringValue(tt.vr, []string{tt.value})
+			require.NoError(t, err)
+			elem, err := element.NewElement(tt.tag, tt.vr, val)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, writeElement(&buf, elem, true))
+
+			// tag (4) + VR (2) + length (2) + padded value
+			out := buf.Bytes()
+			require.Len(t, out, 8+len(tt.value)+1)
+			assert.Equal(t, uint16(len(tt.value)+1), binary.LittleEndian.Uint16(out[6:8]))
+			assert.Equal(t, tt.wantPad, out[len(out)-1])
+			assert.Equal(t, len(out), EncodedLength(elem, nil))
+		})
+	}
+}
+
+// TestWriteElement_UIPaddingRoundTrip tests that UI values stored with odd length
+// and a trailing NUL are re-emitted with the same padding and length, with and
+// without retained raw bytes.
+func TestWriteElement_UIPaddingRoundTrip(t *testing.T) {
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeDICOMFile(buf, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+
+	tests := []struct {
+		name string
+		tag  tag.Tag
+		raw  string
+		want []string
+	}{
+		{"single value", tag.FrameOfReferenceUID, "1.2.3\x00", []string{"1.2.3"}},
+		{"multiple values", tag.RelatedGeneralSOPClassUID, "1.2\\1.3\x00", []string{"1.2", "1.3"}},
+		{"even length", tag.SynchronizationFrameOfReferenceUID, "1.2.34", []string{"1.2.34"}},
+	}
+	data := buf.Bytes()
+	for _, tt := range tests {
+		data = appendShortElement(data, tt.tag, "UI", tt.raw)
+	}
+
+	for _, retain := range []bool{false, true} {
+		ds, err := ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{RetainRawValues: retain})
+		require.NoError(t, err)
+
+		for _, tt := range tests {
+			elem, err := ds.Get(tt.tag)
+			require.NoError(t, err)
+			strVal, ok := elem.Value().(*value.StringValue)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, strVal.Strings(), "%s parsed value", tt.name)
+
+			assert.Equal(t, []byte(tt.raw), value.PaddedBytes(elem.Value()), "%s padded bytes", tt.name)
+
+			var out bytes.Buffer
+			require.NoError(t, writeElement(&out, elem, true))
+			assert.Equal(t, uint16(len(tt.raw)), binary.LittleEndian.Uint16(out.Bytes()[6:8]), "%s length", tt.name)
+			assert.Equal(t, []byte(tt.raw), out.Bytes()[8:], "%s bytes", tt.name)
+			assert.Equal(t, out.Len(), EncodedLength(elem, nil))
+		}
+
+		// A whole-file round trip keeps the values and their encoding
+		out := new(bytes.Buffer)
+		require.NoError(t, writeDICOMFile(out, ds, applyDefaultWriteOptions(WriteOptions{})))
+		reparsed, err := ParseReaderWithOptions(bytes.NewReader(out.Bytes()), ParseOptions{RetainRawValues: true})
+		require.NoError(t, err)
+		for _, tt := range tests {
+			elem, err := reparsed.Get(tt.tag)
+			require.NoError(t, err)
+			assert.Equal(t, []byte(tt.raw), elem.RawBytes(), "%s after round trip", tt.name)
+		}
+	}
+}
+
+// TestWriteElement_RetainedStringBytes tests that non-canonical padding is kept when
+// raw bytes are retained, and normalised when they are not or the value has changed.
+func TestWriteElement_RetainedStringBytes(t *testing.T) {
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeDICOMFile(buf, createTestDatasetForWriter(t), applyDefaultWriteOptions(WriteOptions{})))
+	data := appendShortElement(buf.Bytes(), tag.FrameOfReferenceUID, "UI", "1.2.3 ")
+	data = appendShortElement(data, tag.RelatedGeneralSOPClassUID, "UI", "1.2.34\x00\x00")
+
+	writtenValue := func(t *testing.T, elem *element.Element) []byte {
+		t.Helper()
+		var out bytes.Buffer
+		require.NoError(t, writeElement(&out, elem, true))
+		assert.Equal(t, out.Len(), EncodedLength(elem, nil))
+		return out.Bytes()[8:]
+	}
+
+	retained, err := ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{RetainRawValues: true})
+	require.NoError(t, err)
+	spacePadded, err := retained.Get(tag.FrameOfReferenceUID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1.2.3 "), writtenValue(t, spacePadded))
+	overPadded, err := retained.Get(tag.RelatedGeneralSOPClassUID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1.2.34\x00\x00"), writtenValue(t, overPadded))
+
+	// Without raw bytes the padding is normalised to NUL
+	plain, err := ParseReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	elem, err := plain.Get(tag.FrameOfReferenceUID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1.2.3\x00"), writtenValue(t, elem))
+	elem, err = plain.Get(tag.RelatedGeneralSOPClassUID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1.2.34"), writtenValue(t, elem))
+
+	// A replaced value is written from the value, not the stale raw bytes
+	val, err := value.NewStringValue(vr.UniqueIdentifier, []string{"1.2.345"})
+	require.NoError(t, err)
+	require.NoError(t, spacePadded.SetValue(val))
+	assert.Equal(t, []byte("1.2.345\x00"), writtenValue(t, spacePadded))
+}
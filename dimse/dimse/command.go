@@ -297,7 +297,7 @@ func addUInt16Element(ds *dicom.DataSet, t tag.Tag, val uint16) error {
 	if err != nil {
 		return err
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 func addStringElement(ds *dicom.DataSet, t tag.Tag, val string) error {
@@ -309,7 +309,7 @@ func addStringElement(ds *dicom.DataSet, t tag.Tag, val string) error {
 	if err != nil {
 		return err
 	}
-	return ds.Add(elem)
+	return ds.Set(elem)
 }
 
 func getUInt16(ds *dicom.DataSet, t tag.Tag) (uint16, error) {
@@ -290,7 +290,7 @@ func decodeDataSetImplicitVR(r io.Reader) (*dicom.DataSet, error) {
 			return nil, fmt.Errorf("failed to read element: %w", err)
 		}
 
-		if err := ds.Add(elem); err != nil {
+		if err := ds.Set(elem); err != nil {
 			return nil, fmt.Errorf("failed to add element to dataset: %w", err)
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -30,6 +31,55 @@ func NewDate(value string) (Date, error) {
 	return d, nil
 }
 
+// ParseDate parses a FHIR date string ("YYYY", "YYYY-MM" or "YYYY-MM-DD") and checks
+// that it names a real calendar date.
+//
+// Unlike NewDate, which only checks the format, ParseDate rejects out-of-range
+// components such as month 13 or February 30. The precision of the input is kept:
+// Precision reports "year", "month" or "day", and Time fills missing components with
+// the first month or day, as the DICOM datetime package does for DA values.
+//
+// Example:
+//
+//	d, err := primitives.ParseDate("2024-02")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(d.Precision()) // month
+//
+// FHIR Reference:
+// https://hl7.org/fhir/R5/datatypes.html#date
+func ParseDate(s string) (Date, error) {
+	d, err := NewDate(strings.TrimSpace(s))
+	if err != nil {
+		return Date{}, err
+	}
+
+	var year, month, day int
+	switch d.Precision() {
+	case "day":
+		_, err = fmt.Sscanf(d.value, "%4d-%2d-%2d", &year, &month, &day)
+	case "month":
+		_, err = fmt.Sscanf(d.value, "%4d-%2d", &year, &month)
+		day = 1
+	default:
+		return d, nil
+	}
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid FHIR date %s: %w", d.value, err)
+	}
+
+	if month < 1 || month > 12 {
+		return Date{}, fmt.Errorf("invalid FHIR date %s: month %d out of range 1-12", d.value, month)
+	}
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Day() != day {
+		return Date{}, fmt.Errorf("invalid FHIR date %s: day %d out of range for month", d.value, day)
+	}
+
+	return d, nil
+}
+
 // MustDate creates a new Date, panicking if invalid.
 func MustDate(value string) Date {
 	d, err := NewDate(value)
@@ -402,3 +402,36 @@ func TestDate_Precision(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		precision string
+		wantErr   bool
+	}{
+		{name: "year", input: "2024", want: "2024", precision: "year"},
+		{name: "year and month", input: "2024-02", want: "2024-02", precision: "month"},
+		{name: "full date", input: "2024-02-29", want: "2024-02-29", precision: "day"},
+		{name: "surrounding whitespace", input: " 2024-02-15 ", want: "2024-02-15", precision: "day"},
+		{name: "month out of range", input: "2024-13", wantErr: true},
+		{name: "zero month", input: "2024-00-10", wantErr: true},
+		{name: "day out of range", input: "2023-02-29", wantErr: true},
+		{name: "DICOM format", input: "20240215", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseDate(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, d.String())
+			assert.Equal(t, tt.precision, d.Precision())
+		})
+	}
+}
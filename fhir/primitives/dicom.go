@@ -0,0 +1,35 @@
+package primitives
+
+import (
+	"github.com/codeninja55/go-radx/dicom/datetime"
+)
+
+// FromDICOMDate converts a DICOM Date (DA) into a FHIR date, keeping its precision.
+//
+// A DICOM date with year precision ("2024") becomes "2024", month precision
+// ("202402") becomes "2024-02", and day precision ("20240215", or the NEMA form
+// "2024.02.15") becomes "2024-02-15". A zero DICOM date yields a zero Date.
+//
+// Example:
+//
+//	da, _ := datetime.ParseDate("202402")
+//	d := primitives.FromDICOMDate(da)
+//	fmt.Println(d) // 2024-02
+//
+// References:
+//   - DICOM PS3.5 Section 6.2 (DA): https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2
+//   - FHIR R5 date: https://hl7.org/fhir/R5/datatypes.html#date
+func FromDICOMDate(d datetime.Date) Date {
+	if d.Time.IsZero() {
+		return Date{}
+	}
+
+	switch d.Precision {
+	case datetime.PrecisionYear:
+		return FromTimeYear(d.Time)
+	case datetime.PrecisionMonth:
+		return FromTimeMonth(d.Time)
+	default:
+		return FromTime(d.Time)
+	}
+}
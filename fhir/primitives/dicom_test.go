@@ -0,0 +1,37 @@
+package primitives
+
+import (
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/datetime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromDICOMDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		precision string
+	}{
+		{name: "year", input: "2024", want: "2024", precision: "year"},
+		{name: "month", input: "202402", want: "2024-02", precision: "month"},
+		{name: "day", input: "20240215", want: "2024-02-15", precision: "day"},
+		{name: "NEMA", input: "2024.02.15", want: "2024-02-15", precision: "day"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			da, err := datetime.ParseDate(tt.input)
+			require.NoError(t, err)
+
+			d := FromDICOMDate(da)
+			assert.Equal(t, tt.want, d.String())
+			assert.Equal(t, tt.precision, d.Precision())
+			assert.NoError(t, d.Validate())
+		})
+	}
+
+	assert.True(t, FromDICOMDate(datetime.Date{}).IsZero())
+}
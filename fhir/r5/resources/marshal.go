@@ -0,0 +1,133 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/codeninja55/go-radx/fhir"
+)
+
+var resourceReflectType = reflect.TypeOf(fhir.Resource{})
+
+// Marshal encodes a FHIR resource as JSON with "resourceType" as its first member.
+//
+// FHIR JSON requires every resource to carry its type as the "resourceType"
+// discriminator, and servers reject resources without it. The generated structs
+// leave the embedded ResourceType field for the caller to fill in, so plain
+// json.Marshal emits `"resourceType":""` when it is forgotten. Marshal sets it from
+// the Go type name, which matches the ResourceType* constants (for example
+// ResourceTypeExplanationOfBenefit), and moves it to the front as the specification
+// recommends. The resource passed in is not modified.
+//
+// r must be a resource struct or a pointer to one. An explicitly set ResourceType that
+// disagrees with the Go type is reported as an error.
+//
+// Example:
+//
+//	eob := &resources.ExplanationOfBenefit{Status: "active"}
+//	data, err := resources.Marshal(eob)
+//	// {"resourceType":"ExplanationOfBenefit","status":"active",...}
+//
+// FHIR Reference:
+// https://hl7.org/fhir/R5/json.html#resources
+func Marshal(r any) ([]byte, error) {
+	resourceType, err := ResourceTypeOf(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Marshal a shallow copy with the discriminator set
+	v := reflect.Indirect(reflect.ValueOf(r))
+	resourceCopy := reflect.New(v.Type())
+	resourceCopy.Elem().Set(v)
+	typeField := resourceCopy.Elem().FieldByName("Resource").FieldByName("ResourceType")
+	if current := typeField.String(); current != "" && current != resourceType {
+		return nil, fmt.Errorf("resourceType %q does not match resource %s", current, resourceType)
+	}
+	typeField.SetString(resourceType)
+
+	data, err := json.Marshal(resourceCopy.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s: %w", resourceType, err)
+	}
+
+	return moveResourceTypeFirst(data, resourceType)
+}
+
+// ResourceTypeOf returns the FHIR resource type name of a resource struct, such as
+// "ExplanationOfBenefit" for *ExplanationOfBenefit.
+//
+// Returns an error if r is not a struct (or pointer to a struct) embedding
+// fhir.Resource, directly or through fhir.DomainResource.
+func ResourceTypeOf(r any) (string, error) {
+	if r == nil {
+		return "", fmt.Errorf("resource is nil")
+	}
+
+	v := reflect.ValueOf(r)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", fmt.Errorf("resource is nil")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("%T is not a FHIR resource", r)
+	}
+
+	field, ok := v.Type().FieldByName("Resource")
+	if !ok || !field.Anonymous || field.Type != resourceReflectType {
+		return "", fmt.Errorf("%T is not a FHIR resource", r)
+	}
+
+	return v.Type().Name(), nil
+}
+
+// moveResourceTypeFirst rewrites a JSON object so that "resourceType" is its first
+// member, keeping the order of the remaining members.
+func moveResourceTypeFirst(data []byte, resourceType string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("marshal %s: expected JSON object", resourceType)
+	}
+
+	typeJSON, err := json.Marshal(resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(data))
+	buf.WriteString(`{"resourceType":`)
+	buf.Write(typeJSON)
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", resourceType, err)
+		}
+		key, _ := tok.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", resourceType, err)
+		}
+		if key == "resourceType" {
+			continue
+		}
+
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(',')
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		buf.Write(raw)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
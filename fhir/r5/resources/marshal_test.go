@@ -0,0 +1,100 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/codeninja55/go-radx/fhir"
+	"github.com/codeninja55/go-radx/fhir/internal/testutil"
+	"github.com/codeninja55/go-radx/fhir/primitives"
+)
+
+// TestMarshal_InjectsResourceType tests that the discriminator is emitted first
+// for a resource whose ResourceType was never set
+func TestMarshal_InjectsResourceType(t *testing.T) {
+	eob := &ExplanationOfBenefit{
+		Status:  "active",
+		Use:     "claim",
+		Outcome: "complete",
+		Patient: Reference{Reference: testutil.StringPtr("Patient/123")},
+		Created: primitives.MustDateTime("2024-01-15T10:30:00Z"),
+	}
+	eob.ID = testutil.StringPtr("eob-1")
+
+	data, err := Marshal(eob)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	prefix := `{"resourceType":"ExplanationOfBenefit",`
+	if !bytes.HasPrefix(data, []byte(prefix)) {
+		t.Fatalf("expected JSON to start with %s, got %s", prefix, data)
+	}
+	if bytes.Count(data, []byte(`"resourceType"`)) != 1 {
+		t.Errorf("expected exactly one resourceType member, got %s", data)
+	}
+
+	// The input is not modified
+	if eob.ResourceType != "" {
+		t.Errorf("Marshal modified the resource: ResourceType=%q", eob.ResourceType)
+	}
+
+	// Round trip
+	var parsed ExplanationOfBenefit
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if parsed.ResourceType != ResourceTypeExplanationOfBenefit {
+		t.Errorf("expected resourceType %q, got %q", ResourceTypeExplanationOfBenefit, parsed.ResourceType)
+	}
+	if parsed.ID == nil || *parsed.ID != "eob-1" {
+		t.Errorf("expected id eob-1, got %v", parsed.ID)
+	}
+	if parsed.Status != "active" || parsed.Outcome != "complete" {
+		t.Errorf("unexpected round-trip values: status=%q outcome=%q", parsed.Status, parsed.Outcome)
+	}
+}
+
+// TestMarshal_ResourceWithoutDomainResource tests resources embedding fhir.Resource directly
+func TestMarshal_ResourceWithoutDomainResource(t *testing.T) {
+	data, err := Marshal(Bundle{Type: "collection"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte(`{"resourceType":"Bundle",`)) {
+		t.Errorf("expected Bundle resourceType first, got %s", data)
+	}
+}
+
+// TestMarshal_Errors tests rejection of non-resources and mismatched resource types
+func TestMarshal_Errors(t *testing.T) {
+	mismatched := &Patient{
+		DomainResource: fhir.DomainResource{Resource: fhir.Resource{ResourceType: "Observation"}},
+	}
+
+	tests := []struct {
+		name string
+		r    any
+	}{
+		{"nil", nil},
+		{"nil pointer", (*Patient)(nil)},
+		{"not a struct", "Patient"},
+		{"datatype", HumanName{}},
+		{"mismatched resourceType", mismatched},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Marshal(tt.r); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+
+	// A matching explicit ResourceType is accepted
+	patient := &Patient{DomainResource: fhir.DomainResource{Resource: fhir.Resource{ResourceType: ResourceTypePatient}}}
+	if _, err := Marshal(patient); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package fhirbridge
+
+import (
+	"github.com/codeninja55/go-radx/dicom/datetime"
+	"github.com/codeninja55/go-radx/fhir/r5/resources"
+)
+
+// UCUMSystem is the code system URI for UCUM units of measure.
+const UCUMSystem = "http://unitsofmeasure.org"
+
+// ucumAgeCodes maps DICOM Age String units to UCUM codes.
+var ucumAgeCodes = map[datetime.AgeUnit]string{
+	datetime.Days:   "d",
+	datetime.Weeks:  "wk",
+	datetime.Months: "mo",
+	datetime.Years:  "a",
+}
+
+// AgeToQuantity converts a DICOM Age String (AS) into a UCUM-coded FHIR Quantity.
+//
+// The original unit is preserved ("042Y" becomes 42 a, "006M" becomes 6 mo) rather
+// than normalised to days, since a paediatric age recorded in months carries
+// different clinical meaning from the same span in days. Use Age.Duration when
+// an absolute time span is required.
+//
+// UCUM codes: D → "d", W → "wk", M → "mo", Y → "a". The Unit field holds the
+// human-readable unit ("days", "month", ...). An age with an unrecognised unit
+// yields a Quantity with only Value set.
+//
+// Example:
+//
+//	age, _ := datetime.ParseAge("042Y")
+//	q := fhirbridge.AgeToQuantity(age)
+//	// *q.Value == 42, *q.Code == "a", *q.Unit == "years"
+//
+// References:
+//   - DICOM PS3.5 Section 6.2 (AS): https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2
+//   - FHIR R5 Age: https://hl7.org/fhir/R5/datatypes.html#Age
+func AgeToQuantity(age datetime.Age) resources.Quantity {
+	value := float64(age.Value)
+	q := resources.Quantity{Value: &value}
+
+	code, ok := ucumAgeCodes[age.Unit]
+	if !ok {
+		return q
+	}
+
+	unit := ageUnitText(age)
+	system := UCUMSystem
+	q.Unit = &unit
+	q.System = &system
+	q.Code = &code
+
+	return q
+}
+
+// DurationFromAge converts a DICOM Age String (AS) into a UCUM-coded FHIR Duration.
+//
+// The mapping is identical to AgeToQuantity; use it where a FHIR element is typed
+// as Duration (for example Observation.valueDuration).
+//
+// Example:
+//
+//	age, _ := datetime.ParseAge("004W")
+//	d := fhirbridge.DurationFromAge(age)
+//	// *d.Value == 4, *d.Code == "wk"
+//
+// References:
+//   - FHIR R5 Duration: https://hl7.org/fhir/R5/datatypes.html#Duration
+func DurationFromAge(age datetime.Age) resources.Duration {
+	q := AgeToQuantity(age)
+	return resources.Duration{
+		Value:  q.Value,
+		Unit:   q.Unit,
+		System: q.System,
+		Code:   q.Code,
+	}
+}
+
+// ageUnitText returns the human-readable unit, singular for a value of one.
+func ageUnitText(age datetime.Age) string {
+	unit := age.Unit.LongString()
+	if age.Value == 1 && len(unit) > 0 && unit[len(unit)-1] == 's' {
+		unit = unit[:len(unit)-1]
+	}
+	return unit
+}
@@ -0,0 +1,67 @@
+package fhirbridge
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/codeninja55/go-radx/dicom/datetime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgeToQuantity(t *testing.T) {
+	tests := []struct {
+		as       string
+		value    float64
+		code     string
+		unitText string
+	}{
+		{"007D", 7, "d", "days"},
+		{"001D", 1, "d", "day"},
+		{"004W", 4, "wk", "weeks"},
+		{"006M", 6, "mo", "months"},
+		{"001M", 1, "mo", "month"},
+		{"042Y", 42, "a", "years"},
+		{"000Y", 0, "a", "years"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.as, func(t *testing.T) {
+			age, err := datetime.ParseAge(tt.as)
+			require.NoError(t, err)
+
+			q := AgeToQuantity(age)
+			require.NotNil(t, q.Value)
+			require.NotNil(t, q.Code)
+			require.NotNil(t, q.System)
+			require.NotNil(t, q.Unit)
+
+			assert.Equal(t, tt.value, *q.Value)
+			assert.Equal(t, tt.code, *q.Code)
+			assert.Equal(t, UCUMSystem, *q.System)
+			assert.Equal(t, tt.unitText, *q.Unit)
+			assert.Nil(t, q.Comparator)
+		})
+	}
+}
+
+func TestAgeToQuantity_UnknownUnit(t *testing.T) {
+	q := AgeToQuantity(datetime.Age{Value: 5, Unit: datetime.AgeUnit(99)})
+
+	require.NotNil(t, q.Value)
+	assert.Equal(t, 5.0, *q.Value)
+	assert.Nil(t, q.Code)
+	assert.Nil(t, q.System)
+	assert.Nil(t, q.Unit)
+}
+
+func TestDurationFromAge(t *testing.T) {
+	age, err := datetime.ParseAge("004W")
+	require.NoError(t, err)
+
+	d := DurationFromAge(age)
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"value":4,"unit":"weeks","system":"http://unitsofmeasure.org","code":"wk"}`, string(data))
+}
@@ -0,0 +1,17 @@
+// Package fhirbridge converts DICOM values into FHIR R5 data types.
+//
+// The bridge maps DICOM Value Representations onto their closest FHIR equivalents
+// without losing clinical meaning. For example, a DICOM Age String keeps its
+// original unit when converted to a UCUM-coded Quantity rather than being
+// normalised to days.
+//
+// Example:
+//
+//	age, _ := datetime.ParseAge("006M")
+//	q := fhirbridge.AgeToQuantity(age)
+//	// q.Value = 6, q.Code = "mo", q.System = "http://unitsofmeasure.org"
+//
+// References:
+//   - DICOM PS3.5 Value Representations: https://dicom.nema.org/medical/dicom/current/output/html/part05.html#sect_6.2
+//   - FHIR R5 Quantity: https://hl7.org/fhir/R5/datatypes.html#Quantity
+package fhirbridge